@@ -462,8 +462,8 @@ The journey is challenging but incredibly rewarding. Keep coding!`,
 	for _, article := range articles {
 		var articleID int
 		err := s.db.QueryRow(`
-			INSERT INTO articles (slug, title, description, body, author_id)
-			VALUES ($1, $2, $3, $4, $5)
+			INSERT INTO articles (slug, title, description, body, author_id, published_at)
+			VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP)
 			RETURNING id`,
 			article.slug, article.title, article.description, article.body, article.authorID,
 		).Scan(&articleID)
@@ -471,6 +471,16 @@ The journey is challenging but incredibly rewarding. Keep coding!`,
 			return fmt.Errorf("failed to insert article %s: %w", article.title, err)
 		}
 
+		// Record the article text as its first revision
+		_, err = s.db.Exec(`
+			INSERT INTO article_revisions (article_id, revision_number, title, description, body)
+			VALUES ($1, 1, $2, $3, $4)`,
+			articleID, article.title, article.description, article.body,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert revision of article %s: %w", article.title, err)
+		}
+
 		// Add tags to article
 		for _, tagName := range article.tags {
 			var tagID int
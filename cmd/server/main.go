@@ -7,14 +7,21 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/Nilesh2000/conduit/internal/config"
+	"github.com/Nilesh2000/conduit/internal/cursor"
 	"github.com/Nilesh2000/conduit/internal/handler"
+	"github.com/Nilesh2000/conduit/internal/jwtkeys"
 	"github.com/Nilesh2000/conduit/internal/middleware"
+	"github.com/Nilesh2000/conduit/internal/outbox"
 	"github.com/Nilesh2000/conduit/internal/repository/postgres"
 	"github.com/Nilesh2000/conduit/internal/service"
+	"github.com/Nilesh2000/conduit/internal/stream"
+	"github.com/Nilesh2000/conduit/internal/webhook"
+	"github.com/Nilesh2000/conduit/internal/worker"
 )
 
 func main() {
@@ -52,30 +59,145 @@ func main() {
 		log.Fatalf("Failed to ping database: %v", err)
 	}
 
+	// Load the JWT signing and verification keys
+	jwtKeys, err := jwtkeys.FromConfig(cfg.JWT)
+	if err != nil {
+		log.Fatalf("Failed to load JWT keys: %v", err)
+	}
+
 	// Initialize repositories
 	userRepository := postgres.NewUserRepository(db)
 	profileRepository := postgres.NewProfileRepository(db)
-	articleRepository := postgres.NewArticleRepository(db)
+	articleRepository := postgres.NewArticleRepository(db, cfg.Search.Language)
 	tagRepository := postgres.NewTagRepository(db)
 	commentRepository := postgres.NewCommentRepository(db)
+	tokenRepository := postgres.NewTokenRepository(db)
+	revisionRepository := postgres.NewRevisionRepository(db)
+	blockRepository := postgres.NewBlockRepository(db)
+	muteRepository := postgres.NewMuteRepository(db)
+	notificationRepository := postgres.NewNotificationRepository(db)
+	webhookRepository := postgres.NewWebhookRepository(db)
+	outboxRepository := postgres.NewOutboxRepository(db)
+
+	// Fan the events streamed to clients out to every replica
+	streamHub := stream.NewHub(cfg.Stream.HistorySize)
+	streamBus := stream.NewPostgresBus(db, cfg.Database.GetDSN(), streamHub)
 
 	// Initialize services
-	userService := service.NewUserService(userRepository, cfg.JWT.SecretKey, cfg.JWT.Expiry)
-	profileService := service.NewProfileService(userRepository, profileRepository)
-	articleService := service.NewArticleService(articleRepository, profileRepository)
+	userService := service.NewUserService(
+		userRepository,
+		tokenRepository,
+		jwtKeys,
+		cfg.JWT.Expiry,
+		cfg.JWT.RefreshExpiry,
+	)
+	profileService := service.NewProfileService(
+		userRepository,
+		profileRepository,
+		blockRepository,
+	)
+	slugGenerator := service.NewSlugGenerator(cfg.Slugs.Strategy, cfg.Slugs.MaxAttempts)
+	articleService := service.NewArticleService(
+		articleRepository,
+		profileRepository,
+		slugGenerator,
+	)
 	tagService := service.NewTagService(tagRepository)
-	commentService := service.NewCommentService(commentRepository, articleRepository)
+	muteService := service.NewMuteService(muteRepository)
+	notificationService := service.NewNotificationService(notificationRepository)
+	commentService := service.NewCommentService(
+		commentRepository,
+		articleRepository,
+		userRepository,
+		blockRepository,
+		cfg.Comments.MaxDepth,
+		cfg.Comments.EditWindow,
+		cfg.Comments.Moderators,
+	)
+	revisionService := service.NewRevisionService(
+		revisionRepository,
+		articleRepository,
+		slugGenerator,
+	)
+	trashService := service.NewTrashService(
+		articleRepository,
+		commentRepository,
+		articleRepository,
+		cfg.Trash.Retention,
+	)
+	webhookService := service.NewWebhookService(
+		webhookRepository,
+		webhook.NewSender(cfg.Webhooks.Timeout, cfg.Webhooks.AllowedNetworks),
+		cfg.Webhooks.Timeout,
+		cfg.Webhooks.MaxAttempts,
+		cfg.Webhooks.RetryBackoff,
+		cfg.Webhooks.MaxRetryBackoff,
+	)
+	// Relay the domain events written to the outbox to the configured sinks, in order.
+	// Notifications, stream events and webhook deliveries are produced from the events
+	// handed to the bus.
+	outboxBus := outbox.NewBus()
+	service.NewProducerService(
+		articleRepository,
+		commentRepository,
+		userRepository,
+		notificationRepository,
+		streamBus,
+		webhookRepository,
+	).Subscribe(outboxBus)
+	var outboxSinks []service.OutboxSink
+	for _, sink := range cfg.Outbox.Sinks {
+		switch sink {
+		case config.OutboxSinkBus:
+			outboxSinks = append(outboxSinks, outboxBus)
+		case config.OutboxSinkLog:
+			outboxSinks = append(outboxSinks, outbox.NewLogSink())
+		case config.OutboxSinkHTTP:
+			outboxSinks = append(outboxSinks, outbox.NewHTTPSink(cfg.Outbox.HTTPURL, cfg.Outbox.Timeout))
+		}
+	}
+	outboxService := service.NewOutboxService(
+		outboxRepository,
+		outboxSinks,
+		cfg.Outbox.Timeout,
+		cfg.Outbox.MaxAttempts,
+		cfg.Outbox.RetryBackoff,
+		cfg.Outbox.MaxRetryBackoff,
+	)
+	streamService := service.NewStreamService(
+		streamHub,
+		articleService,
+		articleRepository,
+		commentRepository,
+		userRepository,
+		profileRepository,
+		blockRepository,
+		notificationRepository,
+	)
 
 	// Initialize handlers
+	cursors := cursor.NewSigner([]byte(cfg.Cursors.SecretKey))
 	userHandler := handler.NewUserHandler(userService)
-	profileHandler := handler.NewProfileHandler(profileService)
-	articleHandler := handler.NewArticleHandler(articleService)
+	profileHandler := handler.NewProfileHandler(profileService, cursors)
+	articleHandler := handler.NewArticleHandler(articleService, cursors)
 	tagHandler := handler.NewTagHandler(tagService)
-	commentHandler := handler.NewCommentHandler(commentService)
+	muteHandler := handler.NewMuteHandler(muteService)
+	notificationHandler := handler.NewNotificationHandler(notificationService, cursors)
+	commentHandler := handler.NewCommentHandler(commentService, cursors)
+	revisionHandler := handler.NewRevisionHandler(revisionService)
+	trashHandler := handler.NewTrashHandler(trashService)
+	webhookHandler := handler.NewWebhookHandler(webhookService, cursors)
+	streamHandler := handler.NewStreamHandler(
+		streamService,
+		cfg.Stream.HeartbeatInterval,
+		cfg.Server.WriteTimeout,
+	)
 	healthHandler := handler.NewHealthHandler(cfg.Version)
+	jwksHandler := handler.NewJWKSHandler(jwtKeys)
 
 	// Initialize middleware
-	authMiddleware := middleware.RequireAuth([]byte(cfg.JWT.SecretKey))
+	authMiddleware := middleware.RequireAuth(jwtKeys, tokenRepository)
+	optionalAuthMiddleware := middleware.OptionalAuth(jwtKeys, tokenRepository)
 
 	// Setup router
 	router := http.NewServeMux()
@@ -86,25 +208,78 @@ func main() {
 	// Health endpoint
 	router.HandleFunc("GET /health", healthHandler.Health())
 
+	// JSON Web Key Set endpoint
+	router.HandleFunc("GET /.well-known/jwks.json", jwksHandler.GetJWKS())
+
 	// Article routes
-	router.HandleFunc("GET /api/articles", articleHandler.ListArticles())
+	router.HandleFunc("GET /api/articles", optionalAuthMiddleware(articleHandler.ListArticles()))
 	router.HandleFunc("GET /api/articles/feed", authMiddleware(articleHandler.GetArticlesFeed()))
 	router.HandleFunc("POST /api/articles", authMiddleware(articleHandler.CreateArticle()))
-	router.HandleFunc("GET /api/articles/{slug}", articleHandler.GetArticle())
+	router.HandleFunc(
+		"GET /api/articles/{slug}",
+		optionalAuthMiddleware(articleHandler.GetArticle()),
+	)
 	router.HandleFunc("PUT /api/articles/{slug}", authMiddleware(articleHandler.UpdateArticle()))
 	router.HandleFunc("DELETE /api/articles/{slug}", authMiddleware(articleHandler.DeleteArticle()))
 
 	// Comment routes
-	router.HandleFunc("GET /api/articles/{slug}/comments", commentHandler.GetComments())
+	router.HandleFunc(
+		"GET /api/articles/{slug}/comments",
+		optionalAuthMiddleware(commentHandler.GetComments()),
+	)
 	router.HandleFunc(
 		"POST /api/articles/{slug}/comments",
 		authMiddleware(commentHandler.CreateComment()),
 	)
+	router.HandleFunc(
+		"PUT /api/articles/{slug}/comments/{id}",
+		authMiddleware(commentHandler.UpdateComment()),
+	)
+	router.HandleFunc(
+		"GET /api/articles/{slug}/comments/{id}/versions",
+		authMiddleware(commentHandler.GetCommentVersions()),
+	)
 	router.HandleFunc(
 		"DELETE /api/articles/{slug}/comments/{id}",
 		authMiddleware(commentHandler.DeleteComment()),
 	)
 
+	// Revision routes
+	router.HandleFunc(
+		"GET /api/articles/{slug}/revisions",
+		optionalAuthMiddleware(revisionHandler.ListRevisions()),
+	)
+	router.HandleFunc(
+		"GET /api/articles/{slug}/revisions/{n}",
+		optionalAuthMiddleware(revisionHandler.GetRevision()),
+	)
+	router.HandleFunc(
+		"GET /api/articles/{slug}/revisions/{n}/diff",
+		optionalAuthMiddleware(revisionHandler.DiffRevisions()),
+	)
+	router.HandleFunc(
+		"POST /api/articles/{slug}/revisions/{n}/restore",
+		authMiddleware(revisionHandler.RestoreRevision()),
+	)
+
+	// Publishing routes
+	router.HandleFunc(
+		"POST /api/articles/{slug}/publish",
+		authMiddleware(articleHandler.PublishArticle()),
+	)
+	router.HandleFunc(
+		"DELETE /api/articles/{slug}/publish",
+		authMiddleware(articleHandler.UnpublishArticle()),
+	)
+	router.HandleFunc(
+		"POST /api/articles/{slug}/archive",
+		authMiddleware(articleHandler.ArchiveArticle()),
+	)
+	router.HandleFunc(
+		"POST /api/articles/{slug}/schedule",
+		authMiddleware(articleHandler.ScheduleArticle()),
+	)
+
 	// Favorite routes
 	router.HandleFunc(
 		"POST /api/articles/{slug}/favorite",
@@ -116,7 +291,18 @@ func main() {
 	)
 
 	// Profile routes
-	router.HandleFunc("GET /api/profiles/{username}", profileHandler.GetProfile())
+	router.HandleFunc(
+		"GET /api/profiles/{username}",
+		optionalAuthMiddleware(profileHandler.GetProfile()),
+	)
+	router.HandleFunc(
+		"GET /api/profiles/{username}/followers",
+		optionalAuthMiddleware(profileHandler.GetFollowers()),
+	)
+	router.HandleFunc(
+		"GET /api/profiles/{username}/following",
+		optionalAuthMiddleware(profileHandler.GetFollowing()),
+	)
 	router.HandleFunc(
 		"POST /api/profiles/{username}/follow",
 		authMiddleware(profileHandler.Follow()),
@@ -125,6 +311,84 @@ func main() {
 		"DELETE /api/profiles/{username}/follow",
 		authMiddleware(profileHandler.Unfollow()),
 	)
+	router.HandleFunc(
+		"POST /api/profiles/{username}/block",
+		authMiddleware(profileHandler.Block()),
+	)
+	router.HandleFunc(
+		"DELETE /api/profiles/{username}/block",
+		authMiddleware(profileHandler.Unblock()),
+	)
+	router.HandleFunc("GET /api/user/blocks", authMiddleware(profileHandler.GetBlocks()))
+
+	// Mute routes
+	router.HandleFunc(
+		"POST /api/profiles/{username}/mute",
+		authMiddleware(muteHandler.MuteUser()),
+	)
+	router.HandleFunc(
+		"DELETE /api/profiles/{username}/mute",
+		authMiddleware(muteHandler.UnmuteUser()),
+	)
+	router.HandleFunc("POST /api/tags/{tag}/mute", authMiddleware(muteHandler.MuteTag()))
+	router.HandleFunc("DELETE /api/tags/{tag}/mute", authMiddleware(muteHandler.UnmuteTag()))
+	router.HandleFunc("GET /api/user/mutes", authMiddleware(muteHandler.GetMutes()))
+
+	// Notification routes
+	router.HandleFunc(
+		"GET /api/notifications",
+		authMiddleware(notificationHandler.ListNotifications()),
+	)
+	router.HandleFunc(
+		"POST /api/notifications/read",
+		authMiddleware(notificationHandler.MarkAllRead()),
+	)
+	router.HandleFunc(
+		"POST /api/notifications/{id}/read",
+		authMiddleware(notificationHandler.MarkRead()),
+	)
+	router.HandleFunc(
+		"GET /api/notifications/preferences",
+		authMiddleware(notificationHandler.GetPreferences()),
+	)
+	router.HandleFunc(
+		"PUT /api/notifications/preferences",
+		authMiddleware(notificationHandler.UpdatePreferences()),
+	)
+
+	// Event stream routes
+	router.HandleFunc("GET /api/stream", authMiddleware(streamHandler.Stream()))
+
+	// Trash routes
+	router.HandleFunc("GET /api/user/trash", authMiddleware(trashHandler.GetTrash()))
+	router.HandleFunc(
+		"POST /api/articles/{slug}/restore",
+		authMiddleware(trashHandler.RestoreArticle()),
+	)
+	router.HandleFunc(
+		"POST /api/articles/{slug}/comments/{id}/restore",
+		authMiddleware(trashHandler.RestoreComment()),
+	)
+
+	// Webhook routes
+	router.HandleFunc("GET /api/user/webhooks", authMiddleware(webhookHandler.ListWebhooks()))
+	router.HandleFunc("POST /api/user/webhooks", authMiddleware(webhookHandler.CreateWebhook()))
+	router.HandleFunc(
+		"PUT /api/user/webhooks/{id}",
+		authMiddleware(webhookHandler.UpdateWebhook()),
+	)
+	router.HandleFunc(
+		"DELETE /api/user/webhooks/{id}",
+		authMiddleware(webhookHandler.DeleteWebhook()),
+	)
+	router.HandleFunc(
+		"GET /api/user/webhooks/{id}/deliveries",
+		authMiddleware(webhookHandler.ListDeliveries()),
+	)
+	router.HandleFunc(
+		"POST /api/user/webhooks/{id}/deliveries/{deliveryId}/replay",
+		authMiddleware(webhookHandler.ReplayDelivery()),
+	)
 
 	// Tag routes
 	router.HandleFunc("GET /api/tags", tagHandler.GetTags())
@@ -132,6 +396,8 @@ func main() {
 	// User and Authentication routes
 	router.HandleFunc("POST /api/users/login", userHandler.Login())
 	router.HandleFunc("POST /api/users", userHandler.Register())
+	router.HandleFunc("POST /api/users/refresh", userHandler.Refresh())
+	router.HandleFunc("POST /api/users/logout", authMiddleware(userHandler.Logout()))
 	router.HandleFunc("GET /api/user", authMiddleware(userHandler.GetCurrentUser()))
 	router.HandleFunc("PUT /api/user", authMiddleware(userHandler.UpdateCurrentUser()))
 
@@ -141,14 +407,86 @@ func main() {
 		Handler:           handler,
 		ReadHeaderTimeout: 10 * time.Second,
 		ReadTimeout:       30 * time.Second,
-		WriteTimeout:      30 * time.Second,
+		WriteTimeout:      cfg.Server.WriteTimeout,
 		IdleTimeout:       120 * time.Second,
 	}
 
+	// End event streams when shutting down, as they would otherwise hold it up
+	server.RegisterOnShutdown(streamHub.Close)
+
 	// Handle graceful shutdown
 	done := make(chan os.Signal, 1)
 	signal.Notify(done, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 
+	// Start background workers
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+
+	publisher := worker.NewPublisher(
+		articleService,
+		cfg.Publisher.Interval,
+		cfg.Publisher.BatchSize,
+	)
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		publisher.Run(workerCtx)
+	}()
+
+	purger := worker.NewBatch(
+		"expired trash",
+		trashService.PurgeTrash,
+		cfg.Trash.PurgeInterval,
+		cfg.Trash.PurgeBatchSize,
+	)
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		purger.Run(workerCtx)
+	}()
+
+	sweeper := worker.NewBatch(
+		"expired tokens",
+		userService.PurgeExpiredTokens,
+		cfg.JWT.PurgeInterval,
+		cfg.JWT.PurgeBatchSize,
+	)
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		sweeper.Run(workerCtx)
+	}()
+
+	deliverer := worker.NewBatch(
+		"webhook deliveries",
+		webhookService.DeliverDueWebhooks,
+		cfg.Webhooks.Interval,
+		cfg.Webhooks.BatchSize,
+	)
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		deliverer.Run(workerCtx)
+	}()
+
+	relay := worker.NewBatch(
+		"outbox events",
+		outboxService.RelayEvents,
+		cfg.Outbox.Interval,
+		cfg.Outbox.BatchSize,
+	)
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		relay.Run(workerCtx)
+	}()
+
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		streamBus.Run(workerCtx)
+	}()
+
 	// Start server in goroutine
 	go func() {
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -169,5 +507,18 @@ func main() {
 		log.Fatalf("Server shutdown failed: %v", err)
 	}
 
+	// Stop background workers and wait for the work in progress to finish
+	stopWorkers()
+	workersDone := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(workersDone)
+	}()
+	select {
+	case <-workersDone:
+	case <-ctx.Done():
+		log.Printf("Timed out waiting for background workers to stop")
+	}
+
 	log.Printf("Server exited properly")
 }
@@ -2,8 +2,11 @@ package config
 
 import (
 	"fmt"
+	"net/netip"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -11,10 +14,19 @@ import (
 
 // Config represents the application configuration.
 type Config struct {
-	Database Database
-	JWT      JWT
-	Server   Server
-	Version  string
+	Database  Database
+	JWT       JWT
+	Server    Server
+	Publisher Publisher
+	Slugs     Slugs
+	Search    Search
+	Cursors   Cursors
+	Comments  Comments
+	Trash     Trash
+	Stream    Stream
+	Webhooks  Webhooks
+	Outbox    Outbox
+	Version   string
 }
 
 // Database represents the database configuration.
@@ -32,15 +44,161 @@ type Database struct {
 	ConnMaxIdleTime time.Duration
 }
 
+// Supported JWT signing algorithms.
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
 // JWT represents the JWT configuration.
 type JWT struct {
+	// Algorithm is the algorithm new tokens are signed with.
+	Algorithm string
+	// SecretKey is the HS256 secret, used when Algorithm is HS256 or HMACFallback is set.
 	SecretKey string
-	Expiry    time.Duration
+	// PrivateKeyFile is the PEM file holding the RS256 or EdDSA signing key.
+	PrivateKeyFile string
+	// PublicKeyFiles are PEM files holding additional keys accepted during key rotation.
+	PublicKeyFiles []string
+	// HMACFallback keeps accepting HS256 tokens when signing with an asymmetric key.
+	HMACFallback bool
+
+	Expiry        time.Duration
+	RefreshExpiry time.Duration
+	// PurgeInterval is how often the worker looks for revoked and refresh tokens that
+	// have expired.
+	PurgeInterval time.Duration
+	// PurgeBatchSize is the maximum number of tokens purged at once.
+	PurgeBatchSize int
 }
 
 // Server represents the server configuration.
 type Server struct {
 	Port string
+	// WriteTimeout is how long the server has to write a response, 0 allowing any time.
+	// Event streams push the deadline back before every event and heartbeat they write.
+	WriteTimeout time.Duration
+}
+
+// Publisher represents the scheduled publishing worker configuration.
+type Publisher struct {
+	// Interval is how often the worker looks for scheduled articles that are due.
+	Interval time.Duration
+	// BatchSize is the maximum number of articles published at once.
+	BatchSize int
+}
+
+// Supported slug strategies.
+const (
+	SlugStrategySuffix = "suffix"
+	SlugStrategyRandom = "random"
+)
+
+// Slugs represents the article slug configuration.
+type Slugs struct {
+	// Strategy is how a slug that is already taken is made unique: suffix appends a
+	// counter and random appends a short random ID.
+	Strategy string
+	// MaxAttempts is the number of slugs tried with the strategy, after which a few
+	// slugs with a random ID are tried instead.
+	MaxAttempts int
+}
+
+// Search represents the full-text search configuration.
+type Search struct {
+	// Language is the Postgres text search configuration articles are indexed and
+	// searched with, such as english or simple.
+	Language string
+}
+
+// Cursors represents the pagination cursor configuration.
+type Cursors struct {
+	// SecretKey is the HMAC key cursors handed out to clients are signed with.
+	SecretKey string
+}
+
+// Comments represents the comment configuration.
+type Comments struct {
+	// MaxDepth is how deeply replies can be nested, 0 allowing no replies at all.
+	MaxDepth int
+	// EditWindow is how long after posting a comment its author can edit it, 0 allowing
+	// edits at any time.
+	EditWindow time.Duration
+	// Moderators are the usernames of the users who can see previous versions of edited
+	// comments.
+	Moderators []string
+}
+
+// Trash represents the configuration of deleted articles and comments.
+type Trash struct {
+	// Retention is how long deleted articles and comments can be restored for before
+	// they are purged.
+	Retention time.Duration
+	// PurgeInterval is how often the worker looks for deleted items past the retention.
+	PurgeInterval time.Duration
+	// PurgeBatchSize is the maximum number of items purged at once.
+	PurgeBatchSize int
+}
+
+// Stream represents the configuration of the event streams pushed to clients.
+type Stream struct {
+	// HeartbeatInterval is how often an idle stream is written to, which keeps proxies
+	// and the write timeout from closing it.
+	HeartbeatInterval time.Duration
+	// HistorySize is the number of recent events kept for clients that reconnect with
+	// the ID of the last event they got.
+	HistorySize int
+}
+
+// Webhooks represents the configuration of the deliveries sent to webhooks.
+type Webhooks struct {
+	// Interval is how often the worker looks for deliveries that are due.
+	Interval time.Duration
+	// BatchSize is the maximum number of deliveries sent at once.
+	BatchSize int
+	// Timeout is how long a receiver has to answer a delivery.
+	Timeout time.Duration
+	// MaxAttempts is how many times a delivery is sent before it is given up on.
+	MaxAttempts int
+	// RetryBackoff is how long a delivery waits after its first failed attempt, doubling
+	// after each one that follows up to MaxRetryBackoff.
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+	// AllowedNetworks are the networks deliveries may be sent to even though they are
+	// not public, such as loopback for receivers run locally while testing.
+	AllowedNetworks []netip.Prefix
+}
+
+// Supported outbox sinks.
+const (
+	OutboxSinkBus  = "bus"
+	OutboxSinkLog  = "log"
+	OutboxSinkHTTP = "http"
+)
+
+// Outbox represents the configuration of the relay publishing the domain events written
+// to the outbox.
+type Outbox struct {
+	// Interval is how often the relay looks for events that are due.
+	Interval time.Duration
+	// BatchSize is the maximum number of events published at once.
+	BatchSize int
+	// Sinks are where events are published to, in order: bus hands them to the handlers
+	// in the process, log writes them to the log and http posts them to HTTPURL. The bus
+	// is required, since notifications, stream events and webhook deliveries are
+	// produced by its handlers.
+	Sinks   []string
+	HTTPURL string
+	// Timeout is how long each sink has to take an event.
+	Timeout time.Duration
+	// MaxAttempts is how many times an event is published before it is given up on and
+	// kept in the outbox with its last error.
+	MaxAttempts int
+	// RetryBackoff is how long an event waits after failing to be published the first
+	// time, doubling after each failure that follows up to MaxRetryBackoff.
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
 }
 
 // Load loads the configuration from the environment variables.
@@ -55,12 +213,27 @@ func Load() (*Config, error) {
 		}
 	}
 
-	jwtExpiry := getEnv("JWT_EXPIRY", "24h")
+	jwtExpiry := getEnv("JWT_EXPIRY", "15m")
 	expiry, err := time.ParseDuration(jwtExpiry)
 	if err != nil {
 		return nil, fmt.Errorf("invalid JWT expiry duration: %w", err)
 	}
 
+	jwtRefreshExpiry := getEnv("JWT_REFRESH_EXPIRY", "720h")
+	refreshExpiry, err := time.ParseDuration(jwtRefreshExpiry)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT refresh expiry duration: %w", err)
+	}
+
+	var webhookAllowedNetworks []netip.Prefix
+	for _, network := range getEnvList("WEBHOOK_ALLOWED_NETWORKS") {
+		prefix, err := netip.ParsePrefix(network)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook allowed network: %w", err)
+		}
+		webhookAllowedNetworks = append(webhookAllowedNetworks, prefix)
+	}
+
 	cfg := &Config{
 		Database: Database{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -76,15 +249,75 @@ func Load() (*Config, error) {
 			ConnMaxIdleTime: getEnvDuration("DB_CONN_MAX_IDLE_TIME", 5*time.Second),
 		},
 		JWT: JWT{
-			SecretKey: getEnv("JWT_SECRET_KEY", "this-is-a-32-char-long-secret-key-123"),
-			Expiry:    expiry,
+			Algorithm:      getEnv("JWT_ALGORITHM", AlgorithmHS256),
+			SecretKey:      getEnv("JWT_SECRET_KEY", "this-is-a-32-char-long-secret-key-123"),
+			PrivateKeyFile: getEnv("JWT_PRIVATE_KEY_FILE", ""),
+			PublicKeyFiles: getEnvList("JWT_PUBLIC_KEY_FILES"),
+			HMACFallback:   getEnvBool("JWT_HMAC_FALLBACK", false),
+			Expiry:         expiry,
+			RefreshExpiry:  refreshExpiry,
+			PurgeInterval:  getEnvDuration("JWT_PURGE_INTERVAL", time.Hour),
+			PurgeBatchSize: getEnvInt("JWT_PURGE_BATCH_SIZE", 1000),
 		},
 		Server: Server{
-			Port: getEnv("SERVER_PORT", "8080"),
+			Port:         getEnv("SERVER_PORT", "8080"),
+			WriteTimeout: getEnvDuration("SERVER_WRITE_TIMEOUT", 30*time.Second),
+		},
+		Publisher: Publisher{
+			Interval:  getEnvDuration("PUBLISHER_INTERVAL", 30*time.Second),
+			BatchSize: getEnvInt("PUBLISHER_BATCH_SIZE", 100),
+		},
+		Slugs: Slugs{
+			Strategy:    getEnv("SLUG_STRATEGY", SlugStrategySuffix),
+			MaxAttempts: getEnvInt("SLUG_MAX_ATTEMPTS", 10),
+		},
+		Search: Search{
+			Language: getEnv("SEARCH_LANGUAGE", "english"),
+		},
+		Cursors: Cursors{
+			SecretKey: getEnv("CURSOR_SECRET_KEY", "this-is-a-32-char-long-cursor-key-123"),
+		},
+		Comments: Comments{
+			MaxDepth:   getEnvInt("COMMENT_MAX_DEPTH", 5),
+			EditWindow: getEnvDuration("COMMENT_EDIT_WINDOW", 15*time.Minute),
+			Moderators: getEnvList("COMMENT_MODERATORS"),
+		},
+		Trash: Trash{
+			Retention:      getEnvDuration("TRASH_RETENTION", 720*time.Hour),
+			PurgeInterval:  getEnvDuration("TRASH_PURGE_INTERVAL", time.Hour),
+			PurgeBatchSize: getEnvInt("TRASH_PURGE_BATCH_SIZE", 100),
+		},
+		Stream: Stream{
+			HeartbeatInterval: getEnvDuration("STREAM_HEARTBEAT_INTERVAL", 15*time.Second),
+			HistorySize:       getEnvInt("STREAM_HISTORY_SIZE", 1000),
+		},
+		Webhooks: Webhooks{
+			Interval:        getEnvDuration("WEBHOOK_INTERVAL", 5*time.Second),
+			BatchSize:       getEnvInt("WEBHOOK_BATCH_SIZE", 50),
+			Timeout:         getEnvDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+			MaxAttempts:     getEnvInt("WEBHOOK_MAX_ATTEMPTS", 8),
+			RetryBackoff:    getEnvDuration("WEBHOOK_RETRY_BACKOFF", 30*time.Second),
+			MaxRetryBackoff: getEnvDuration("WEBHOOK_MAX_RETRY_BACKOFF", 6*time.Hour),
+			AllowedNetworks: webhookAllowedNetworks,
+		},
+		Outbox: Outbox{
+			Interval:        getEnvDuration("OUTBOX_INTERVAL", time.Second),
+			BatchSize:       getEnvInt("OUTBOX_BATCH_SIZE", 100),
+			Sinks:           getEnvList("OUTBOX_SINKS"),
+			HTTPURL:         getEnv("OUTBOX_HTTP_URL", ""),
+			Timeout:         getEnvDuration("OUTBOX_TIMEOUT", 10*time.Second),
+			MaxAttempts:     getEnvInt("OUTBOX_MAX_ATTEMPTS", 10),
+			RetryBackoff:    getEnvDuration("OUTBOX_RETRY_BACKOFF", 5*time.Second),
+			MaxRetryBackoff: getEnvDuration("OUTBOX_MAX_RETRY_BACKOFF", 5*time.Minute),
 		},
 		Version: getEnv("APP_VERSION", "1.0.0"),
 	}
 
+	// Events are handed to the handlers in the process unless other sinks are set
+	if len(cfg.Outbox.Sinks) == 0 {
+		cfg.Outbox.Sinks = []string{OutboxSinkBus}
+	}
+
 	return cfg, nil
 }
 
@@ -118,6 +351,56 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("server configuration error: %w", err)
 	}
 
+	// Validate publisher configuration
+	if err := c.Publisher.Validate(); err != nil {
+		return fmt.Errorf("publisher configuration error: %w", err)
+	}
+
+	// Validate slug configuration
+	if err := c.Slugs.Validate(); err != nil {
+		return fmt.Errorf("slug configuration error: %w", err)
+	}
+
+	// Validate search configuration
+	if err := c.Search.Validate(); err != nil {
+		return fmt.Errorf("search configuration error: %w", err)
+	}
+
+	// Validate cursor configuration
+	if err := c.Cursors.Validate(); err != nil {
+		return fmt.Errorf("cursor configuration error: %w", err)
+	}
+
+	// Validate comment configuration
+	if err := c.Comments.Validate(); err != nil {
+		return fmt.Errorf("comment configuration error: %w", err)
+	}
+
+	// Validate trash configuration
+	if err := c.Trash.Validate(); err != nil {
+		return fmt.Errorf("trash configuration error: %w", err)
+	}
+
+	// Validate stream configuration
+	if err := c.Stream.Validate(); err != nil {
+		return fmt.Errorf("stream configuration error: %w", err)
+	}
+
+	// Validate webhook configuration
+	if err := c.Webhooks.Validate(); err != nil {
+		return fmt.Errorf("webhook configuration error: %w", err)
+	}
+
+	// Validate outbox configuration
+	if err := c.Outbox.Validate(); err != nil {
+		return fmt.Errorf("outbox configuration error: %w", err)
+	}
+
+	// Idle streams would be cut off by the write timeout between heartbeats
+	if c.Server.WriteTimeout > 0 && c.Stream.HeartbeatInterval >= c.Server.WriteTimeout {
+		return fmt.Errorf("stream configuration error: heartbeat interval must be shorter than the server write timeout")
+	}
+
 	return nil
 }
 
@@ -166,12 +449,44 @@ func (d *Database) Validate() error {
 
 // Validate checks if the JWT configuration is valid.
 func (j *JWT) Validate() error {
-	if j.SecretKey == "" {
-		return fmt.Errorf("secret key is required")
+	switch j.Algorithm {
+	case AlgorithmHS256:
+	case AlgorithmRS256, AlgorithmEdDSA:
+		if j.PrivateKeyFile == "" {
+			return fmt.Errorf("private key file is required for %s", j.Algorithm)
+		}
+	default:
+		return fmt.Errorf(
+			"algorithm must be one of %s, %s or %s",
+			AlgorithmHS256,
+			AlgorithmRS256,
+			AlgorithmEdDSA,
+		)
 	}
 	if j.Expiry <= 0 {
 		return fmt.Errorf("expiry must be greater than 0")
 	}
+	if j.RefreshExpiry <= 0 {
+		return fmt.Errorf("refresh expiry must be greater than 0")
+	}
+	if j.RefreshExpiry < j.Expiry {
+		return fmt.Errorf("refresh expiry must not be shorter than expiry")
+	}
+	if j.PurgeInterval <= 0 {
+		return fmt.Errorf("purge interval must be greater than 0")
+	}
+	if j.PurgeBatchSize <= 0 {
+		return fmt.Errorf("purge batch size must be greater than 0")
+	}
+
+	// The secret key is only needed when HS256 tokens are signed or accepted
+	if j.Algorithm != AlgorithmHS256 && !j.HMACFallback {
+		return nil
+	}
+
+	if j.SecretKey == "" {
+		return fmt.Errorf("secret key is required")
+	}
 
 	// Validate secret key is at least 32 bytes long for security
 	if len(j.SecretKey) < 32 {
@@ -195,6 +510,173 @@ func (s *Server) Validate() error {
 	if port < 0 || port > 65535 {
 		return fmt.Errorf("port must be between 0 and 65535")
 	}
+	if s.WriteTimeout < 0 {
+		return fmt.Errorf("write timeout must not be negative")
+	}
+
+	return nil
+}
+
+// Validate checks if the publisher configuration is valid.
+func (p *Publisher) Validate() error {
+	if p.Interval <= 0 {
+		return fmt.Errorf("interval must be greater than 0")
+	}
+	if p.BatchSize <= 0 {
+		return fmt.Errorf("batch size must be greater than 0")
+	}
+
+	return nil
+}
+
+// Validate checks if the slug configuration is valid.
+func (s *Slugs) Validate() error {
+	if s.Strategy != SlugStrategySuffix && s.Strategy != SlugStrategyRandom {
+		return fmt.Errorf(
+			"strategy must be one of %s or %s",
+			SlugStrategySuffix,
+			SlugStrategyRandom,
+		)
+	}
+	if s.MaxAttempts <= 0 {
+		return fmt.Errorf("max attempts must be greater than 0")
+	}
+
+	return nil
+}
+
+// Validate checks if the search configuration is valid.
+func (s *Search) Validate() error {
+	if s.Language == "" {
+		return fmt.Errorf("language is required")
+	}
+
+	// Text search configurations are plain identifiers
+	for _, r := range s.Language {
+		if (r < 'a' || r > 'z') && r != '_' {
+			return fmt.Errorf("language must only contain lowercase letters and underscores")
+		}
+	}
+
+	return nil
+}
+
+// Validate checks if the cursor configuration is valid.
+func (c *Cursors) Validate() error {
+	if c.SecretKey == "" {
+		return fmt.Errorf("secret key is required")
+	}
+
+	// Validate secret key is at least 32 bytes long for security
+	if len(c.SecretKey) < 32 {
+		return fmt.Errorf("secret key must be at least 32 bytes long for security")
+	}
+
+	return nil
+}
+
+// Validate checks if the comment configuration is valid.
+func (c *Comments) Validate() error {
+	if c.MaxDepth < 0 {
+		return fmt.Errorf("max depth must not be negative")
+	}
+	if c.EditWindow < 0 {
+		return fmt.Errorf("edit window must not be negative")
+	}
+
+	return nil
+}
+
+// Validate checks if the trash configuration is valid.
+func (t *Trash) Validate() error {
+	if t.Retention <= 0 {
+		return fmt.Errorf("retention must be greater than 0")
+	}
+	if t.PurgeInterval <= 0 {
+		return fmt.Errorf("purge interval must be greater than 0")
+	}
+	if t.PurgeBatchSize <= 0 {
+		return fmt.Errorf("purge batch size must be greater than 0")
+	}
+
+	return nil
+}
+
+// Validate checks if the stream configuration is valid.
+func (s *Stream) Validate() error {
+	if s.HeartbeatInterval <= 0 {
+		return fmt.Errorf("heartbeat interval must be greater than 0")
+	}
+	if s.HistorySize < 0 {
+		return fmt.Errorf("history size must not be negative")
+	}
+
+	return nil
+}
+
+// Validate checks if the webhook configuration is valid.
+func (w *Webhooks) Validate() error {
+	if w.Interval <= 0 {
+		return fmt.Errorf("interval must be greater than 0")
+	}
+	if w.BatchSize <= 0 {
+		return fmt.Errorf("batch size must be greater than 0")
+	}
+	if w.Timeout <= 0 {
+		return fmt.Errorf("timeout must be greater than 0")
+	}
+	if w.MaxAttempts <= 0 {
+		return fmt.Errorf("max attempts must be greater than 0")
+	}
+	if w.RetryBackoff <= 0 {
+		return fmt.Errorf("retry backoff must be greater than 0")
+	}
+	if w.MaxRetryBackoff < w.RetryBackoff {
+		return fmt.Errorf("max retry backoff must not be shorter than the retry backoff")
+	}
+
+	return nil
+}
+
+// Validate checks if the outbox configuration is valid.
+func (o *Outbox) Validate() error {
+	if o.Interval <= 0 {
+		return fmt.Errorf("interval must be greater than 0")
+	}
+	if o.BatchSize <= 0 {
+		return fmt.Errorf("batch size must be greater than 0")
+	}
+	if !slices.Contains(o.Sinks, OutboxSinkBus) {
+		return fmt.Errorf(
+			"the %s sink is required, since notifications, stream events and webhooks are produced from it",
+			OutboxSinkBus,
+		)
+	}
+	for _, sink := range o.Sinks {
+		if sink != OutboxSinkBus && sink != OutboxSinkLog && sink != OutboxSinkHTTP {
+			return fmt.Errorf(
+				"sinks must be one of %s, %s or %s",
+				OutboxSinkBus,
+				OutboxSinkLog,
+				OutboxSinkHTTP,
+			)
+		}
+		if sink == OutboxSinkHTTP && o.HTTPURL == "" {
+			return fmt.Errorf("HTTP URL is required for the %s sink", OutboxSinkHTTP)
+		}
+	}
+	if o.Timeout <= 0 {
+		return fmt.Errorf("timeout must be greater than 0")
+	}
+	if o.MaxAttempts <= 0 {
+		return fmt.Errorf("max attempts must be greater than 0")
+	}
+	if o.RetryBackoff <= 0 {
+		return fmt.Errorf("retry backoff must be greater than 0")
+	}
+	if o.MaxRetryBackoff < o.RetryBackoff {
+		return fmt.Errorf("max retry backoff must not be shorter than the retry backoff")
+	}
 
 	return nil
 }
@@ -228,3 +710,24 @@ func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
 	}
 	return duration
 }
+
+// getEnvBool returns the value of the environment variable as a bool.
+func getEnvBool(key string, defaultValue bool) bool {
+	value := getEnv(key, strconv.FormatBool(defaultValue))
+	val, err := strconv.ParseBool(value)
+	if err != nil {
+		return defaultValue
+	}
+	return val
+}
+
+// getEnvList returns the value of the environment variable as a comma-separated list.
+func getEnvList(key string) []string {
+	var list []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -62,14 +62,887 @@ func TestConfig_Validate(t *testing.T) {
 					ConnMaxIdleTime: 5 * time.Second,
 				},
 				JWT: JWT{
-					SecretKey: "this-is-a-32-char-long-secret-key-123",
-					Expiry:    24 * time.Hour,
+					Algorithm:      AlgorithmHS256,
+					SecretKey:      "this-is-a-32-char-long-secret-key-123",
+					Expiry:         24 * time.Hour,
+					RefreshExpiry:  720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 1000,
 				},
 				Server: Server{
 					Port: "8080",
 				},
+				Publisher: Publisher{
+					Interval:  30 * time.Second,
+					BatchSize: 100,
+				},
+				Slugs: Slugs{
+					Strategy:    SlugStrategySuffix,
+					MaxAttempts: 10,
+				},
+				Search: Search{
+					Language: "english",
+				},
+				Cursors: Cursors{
+					SecretKey: "this-is-a-32-char-long-cursor-key-123",
+				},
+				Comments: Comments{
+					MaxDepth:   5,
+					EditWindow: 15 * time.Minute,
+				},
+				Trash: Trash{
+					Retention:      720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 100,
+				},
+				Stream: Stream{
+					HeartbeatInterval: 15 * time.Second,
+					HistorySize:       1000,
+				},
+				Webhooks: Webhooks{
+					Interval:        5 * time.Second,
+					BatchSize:       50,
+					Timeout:         10 * time.Second,
+					MaxAttempts:     8,
+					RetryBackoff:    30 * time.Second,
+					MaxRetryBackoff: 6 * time.Hour,
+				},
+				Outbox: Outbox{
+					Interval:        time.Second,
+					BatchSize:       100,
+					Sinks:           []string{OutboxSinkBus},
+					Timeout:         10 * time.Second,
+					MaxAttempts:     10,
+					RetryBackoff:    5 * time.Second,
+					MaxRetryBackoff: 5 * time.Minute,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Invalid publisher batch size",
+			config: Config{
+				Database: Database{
+					Host:     "localhost",
+					Port:     "5432",
+					User:     "testuser",
+					Password: "testpass",
+					Name:     "testdb",
+					SSLMode:  "disable",
+
+					MaxOpenConns:    10,
+					MaxIdleConns:    5,
+					ConnMaxLifetime: 10 * time.Second,
+					ConnMaxIdleTime: 5 * time.Second,
+				},
+				JWT: JWT{
+					Algorithm:      AlgorithmHS256,
+					SecretKey:      "this-is-a-32-char-long-secret-key-123",
+					Expiry:         24 * time.Hour,
+					RefreshExpiry:  720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 1000,
+				},
+				Server: Server{
+					Port: "8080",
+				},
+				Publisher: Publisher{
+					Interval:  30 * time.Second,
+					BatchSize: 0,
+				},
+				Slugs: Slugs{
+					Strategy:    SlugStrategySuffix,
+					MaxAttempts: 10,
+				},
+				Search: Search{
+					Language: "english",
+				},
+				Cursors: Cursors{
+					SecretKey: "this-is-a-32-char-long-cursor-key-123",
+				},
+				Comments: Comments{
+					MaxDepth:   5,
+					EditWindow: 15 * time.Minute,
+				},
+				Trash: Trash{
+					Retention:      720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 100,
+				},
+				Stream: Stream{
+					HeartbeatInterval: 15 * time.Second,
+					HistorySize:       1000,
+				},
+				Webhooks: Webhooks{
+					Interval:        5 * time.Second,
+					BatchSize:       50,
+					Timeout:         10 * time.Second,
+					MaxAttempts:     8,
+					RetryBackoff:    30 * time.Second,
+					MaxRetryBackoff: 6 * time.Hour,
+				},
+				Outbox: Outbox{
+					Interval:        time.Second,
+					BatchSize:       100,
+					Sinks:           []string{OutboxSinkBus},
+					Timeout:         10 * time.Second,
+					MaxAttempts:     10,
+					RetryBackoff:    5 * time.Second,
+					MaxRetryBackoff: 5 * time.Minute,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Invalid slug strategy",
+			config: Config{
+				Database: Database{
+					Host:     "localhost",
+					Port:     "5432",
+					User:     "testuser",
+					Password: "testpass",
+					Name:     "testdb",
+					SSLMode:  "disable",
+
+					MaxOpenConns:    10,
+					MaxIdleConns:    5,
+					ConnMaxLifetime: 10 * time.Second,
+					ConnMaxIdleTime: 5 * time.Second,
+				},
+				JWT: JWT{
+					Algorithm:      AlgorithmHS256,
+					SecretKey:      "this-is-a-32-char-long-secret-key-123",
+					Expiry:         24 * time.Hour,
+					RefreshExpiry:  720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 1000,
+				},
+				Server: Server{
+					Port: "8080",
+				},
+				Publisher: Publisher{
+					Interval:  30 * time.Second,
+					BatchSize: 100,
+				},
+				Slugs: Slugs{
+					Strategy:    "uuid",
+					MaxAttempts: 10,
+				},
+				Search: Search{
+					Language: "english",
+				},
+				Cursors: Cursors{
+					SecretKey: "this-is-a-32-char-long-cursor-key-123",
+				},
+				Comments: Comments{
+					MaxDepth:   5,
+					EditWindow: 15 * time.Minute,
+				},
+				Trash: Trash{
+					Retention:      720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 100,
+				},
+				Stream: Stream{
+					HeartbeatInterval: 15 * time.Second,
+					HistorySize:       1000,
+				},
+				Webhooks: Webhooks{
+					Interval:        5 * time.Second,
+					BatchSize:       50,
+					Timeout:         10 * time.Second,
+					MaxAttempts:     8,
+					RetryBackoff:    30 * time.Second,
+					MaxRetryBackoff: 6 * time.Hour,
+				},
+				Outbox: Outbox{
+					Interval:        time.Second,
+					BatchSize:       100,
+					Sinks:           []string{OutboxSinkBus},
+					Timeout:         10 * time.Second,
+					MaxAttempts:     10,
+					RetryBackoff:    5 * time.Second,
+					MaxRetryBackoff: 5 * time.Minute,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Invalid search language",
+			config: Config{
+				Database: Database{
+					Host:     "localhost",
+					Port:     "5432",
+					User:     "testuser",
+					Password: "testpass",
+					Name:     "testdb",
+					SSLMode:  "disable",
+
+					MaxOpenConns:    10,
+					MaxIdleConns:    5,
+					ConnMaxLifetime: 10 * time.Second,
+					ConnMaxIdleTime: 5 * time.Second,
+				},
+				JWT: JWT{
+					Algorithm:      AlgorithmHS256,
+					SecretKey:      "this-is-a-32-char-long-secret-key-123",
+					Expiry:         24 * time.Hour,
+					RefreshExpiry:  720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 1000,
+				},
+				Server: Server{
+					Port: "8080",
+				},
+				Publisher: Publisher{
+					Interval:  30 * time.Second,
+					BatchSize: 100,
+				},
+				Slugs: Slugs{
+					Strategy:    SlugStrategySuffix,
+					MaxAttempts: 10,
+				},
+				Search: Search{
+					Language: "english'; DROP TABLE articles; --",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Short cursor secret key",
+			config: Config{
+				Database: Database{
+					Host:     "localhost",
+					Port:     "5432",
+					User:     "testuser",
+					Password: "testpass",
+					Name:     "testdb",
+					SSLMode:  "disable",
+
+					MaxOpenConns:    10,
+					MaxIdleConns:    5,
+					ConnMaxLifetime: 10 * time.Second,
+					ConnMaxIdleTime: 5 * time.Second,
+				},
+				JWT: JWT{
+					Algorithm:      AlgorithmHS256,
+					SecretKey:      "this-is-a-32-char-long-secret-key-123",
+					Expiry:         24 * time.Hour,
+					RefreshExpiry:  720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 1000,
+				},
+				Server: Server{
+					Port: "8080",
+				},
+				Publisher: Publisher{
+					Interval:  30 * time.Second,
+					BatchSize: 100,
+				},
+				Slugs: Slugs{
+					Strategy:    SlugStrategySuffix,
+					MaxAttempts: 10,
+				},
+				Search: Search{
+					Language: "english",
+				},
+				Cursors: Cursors{
+					SecretKey: "short",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative comment max depth",
+			config: Config{
+				Database: Database{
+					Host:     "localhost",
+					Port:     "5432",
+					User:     "testuser",
+					Password: "testpass",
+					Name:     "testdb",
+					SSLMode:  "disable",
+
+					MaxOpenConns:    10,
+					MaxIdleConns:    5,
+					ConnMaxLifetime: 10 * time.Second,
+					ConnMaxIdleTime: 5 * time.Second,
+				},
+				JWT: JWT{
+					Algorithm:      AlgorithmHS256,
+					SecretKey:      "this-is-a-32-char-long-secret-key-123",
+					Expiry:         24 * time.Hour,
+					RefreshExpiry:  720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 1000,
+				},
+				Server: Server{
+					Port: "8080",
+				},
+				Publisher: Publisher{
+					Interval:  30 * time.Second,
+					BatchSize: 100,
+				},
+				Slugs: Slugs{
+					Strategy:    SlugStrategySuffix,
+					MaxAttempts: 10,
+				},
+				Search: Search{
+					Language: "english",
+				},
+				Cursors: Cursors{
+					SecretKey: "this-is-a-32-char-long-cursor-key-123",
+				},
+				Comments: Comments{
+					MaxDepth: -1,
+				},
+				Trash: Trash{
+					Retention:      720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 100,
+				},
+				Stream: Stream{
+					HeartbeatInterval: 15 * time.Second,
+					HistorySize:       1000,
+				},
+				Webhooks: Webhooks{
+					Interval:        5 * time.Second,
+					BatchSize:       50,
+					Timeout:         10 * time.Second,
+					MaxAttempts:     8,
+					RetryBackoff:    30 * time.Second,
+					MaxRetryBackoff: 6 * time.Hour,
+				},
+				Outbox: Outbox{
+					Interval:        time.Second,
+					BatchSize:       100,
+					Sinks:           []string{OutboxSinkBus},
+					Timeout:         10 * time.Second,
+					MaxAttempts:     10,
+					RetryBackoff:    5 * time.Second,
+					MaxRetryBackoff: 5 * time.Minute,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative comment edit window",
+			config: Config{
+				Database: Database{
+					Host:     "localhost",
+					Port:     "5432",
+					User:     "testuser",
+					Password: "testpass",
+					Name:     "testdb",
+					SSLMode:  "disable",
+
+					MaxOpenConns:    10,
+					MaxIdleConns:    5,
+					ConnMaxLifetime: 10 * time.Second,
+					ConnMaxIdleTime: 5 * time.Second,
+				},
+				JWT: JWT{
+					Algorithm:      AlgorithmHS256,
+					SecretKey:      "this-is-a-32-char-long-secret-key-123",
+					Expiry:         24 * time.Hour,
+					RefreshExpiry:  720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 1000,
+				},
+				Server: Server{
+					Port: "8080",
+				},
+				Publisher: Publisher{
+					Interval:  30 * time.Second,
+					BatchSize: 100,
+				},
+				Slugs: Slugs{
+					Strategy:    SlugStrategySuffix,
+					MaxAttempts: 10,
+				},
+				Search: Search{
+					Language: "english",
+				},
+				Cursors: Cursors{
+					SecretKey: "this-is-a-32-char-long-cursor-key-123",
+				},
+				Comments: Comments{
+					MaxDepth:   5,
+					EditWindow: -time.Minute,
+				},
+				Trash: Trash{
+					Retention:      720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 100,
+				},
+				Stream: Stream{
+					HeartbeatInterval: 15 * time.Second,
+					HistorySize:       1000,
+				},
+				Webhooks: Webhooks{
+					Interval:        5 * time.Second,
+					BatchSize:       50,
+					Timeout:         10 * time.Second,
+					MaxAttempts:     8,
+					RetryBackoff:    30 * time.Second,
+					MaxRetryBackoff: 6 * time.Hour,
+				},
+				Outbox: Outbox{
+					Interval:        time.Second,
+					BatchSize:       100,
+					Sinks:           []string{OutboxSinkBus},
+					Timeout:         10 * time.Second,
+					MaxAttempts:     10,
+					RetryBackoff:    5 * time.Second,
+					MaxRetryBackoff: 5 * time.Minute,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Zero trash retention",
+			config: Config{
+				Database: Database{
+					Host:     "localhost",
+					Port:     "5432",
+					User:     "testuser",
+					Password: "testpass",
+					Name:     "testdb",
+					SSLMode:  "disable",
+
+					MaxOpenConns:    10,
+					MaxIdleConns:    5,
+					ConnMaxLifetime: 10 * time.Second,
+					ConnMaxIdleTime: 5 * time.Second,
+				},
+				JWT: JWT{
+					Algorithm:      AlgorithmHS256,
+					SecretKey:      "this-is-a-32-char-long-secret-key-123",
+					Expiry:         24 * time.Hour,
+					RefreshExpiry:  720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 1000,
+				},
+				Server: Server{
+					Port: "8080",
+				},
+				Publisher: Publisher{
+					Interval:  30 * time.Second,
+					BatchSize: 100,
+				},
+				Slugs: Slugs{
+					Strategy:    SlugStrategySuffix,
+					MaxAttempts: 10,
+				},
+				Search: Search{
+					Language: "english",
+				},
+				Cursors: Cursors{
+					SecretKey: "this-is-a-32-char-long-cursor-key-123",
+				},
+				Comments: Comments{
+					MaxDepth:   5,
+					EditWindow: 15 * time.Minute,
+				},
+				Trash: Trash{
+					Retention:      0,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 100,
+				},
+				Stream: Stream{
+					HeartbeatInterval: 15 * time.Second,
+					HistorySize:       1000,
+				},
+				Webhooks: Webhooks{
+					Interval:        5 * time.Second,
+					BatchSize:       50,
+					Timeout:         10 * time.Second,
+					MaxAttempts:     8,
+					RetryBackoff:    30 * time.Second,
+					MaxRetryBackoff: 6 * time.Hour,
+				},
+				Outbox: Outbox{
+					Interval:        time.Second,
+					BatchSize:       100,
+					Sinks:           []string{OutboxSinkBus},
+					Timeout:         10 * time.Second,
+					MaxAttempts:     10,
+					RetryBackoff:    5 * time.Second,
+					MaxRetryBackoff: 5 * time.Minute,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Heartbeat not shorter than write timeout",
+			config: Config{
+				Database: Database{
+					Host:     "localhost",
+					Port:     "5432",
+					User:     "testuser",
+					Password: "testpass",
+					Name:     "testdb",
+					SSLMode:  "disable",
+
+					MaxOpenConns:    10,
+					MaxIdleConns:    5,
+					ConnMaxLifetime: 10 * time.Second,
+					ConnMaxIdleTime: 5 * time.Second,
+				},
+				JWT: JWT{
+					Algorithm:      AlgorithmHS256,
+					SecretKey:      "this-is-a-32-char-long-secret-key-123",
+					Expiry:         24 * time.Hour,
+					RefreshExpiry:  720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 1000,
+				},
+				Server: Server{
+					Port:         "8080",
+					WriteTimeout: 30 * time.Second,
+				},
+				Publisher: Publisher{
+					Interval:  30 * time.Second,
+					BatchSize: 100,
+				},
+				Slugs: Slugs{
+					Strategy:    SlugStrategySuffix,
+					MaxAttempts: 10,
+				},
+				Search: Search{
+					Language: "english",
+				},
+				Cursors: Cursors{
+					SecretKey: "this-is-a-32-char-long-cursor-key-123",
+				},
+				Comments: Comments{
+					MaxDepth:   5,
+					EditWindow: 15 * time.Minute,
+				},
+				Trash: Trash{
+					Retention:      720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 100,
+				},
+				Stream: Stream{
+					HeartbeatInterval: 30 * time.Second,
+					HistorySize:       1000,
+				},
+				Webhooks: Webhooks{
+					Interval:        5 * time.Second,
+					BatchSize:       50,
+					Timeout:         10 * time.Second,
+					MaxAttempts:     8,
+					RetryBackoff:    30 * time.Second,
+					MaxRetryBackoff: 6 * time.Hour,
+				},
+				Outbox: Outbox{
+					Interval:        time.Second,
+					BatchSize:       100,
+					Sinks:           []string{OutboxSinkBus},
+					Timeout:         10 * time.Second,
+					MaxAttempts:     10,
+					RetryBackoff:    5 * time.Second,
+					MaxRetryBackoff: 5 * time.Minute,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Max retry backoff shorter than retry backoff",
+			config: Config{
+				Database: Database{
+					Host:     "localhost",
+					Port:     "5432",
+					User:     "testuser",
+					Password: "testpass",
+					Name:     "testdb",
+					SSLMode:  "disable",
+
+					MaxOpenConns:    10,
+					MaxIdleConns:    5,
+					ConnMaxLifetime: 10 * time.Second,
+					ConnMaxIdleTime: 5 * time.Second,
+				},
+				JWT: JWT{
+					Algorithm:      AlgorithmHS256,
+					SecretKey:      "this-is-a-32-char-long-secret-key-123",
+					Expiry:         24 * time.Hour,
+					RefreshExpiry:  720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 1000,
+				},
+				Server: Server{
+					Port: "8080",
+				},
+				Publisher: Publisher{
+					Interval:  30 * time.Second,
+					BatchSize: 100,
+				},
+				Slugs: Slugs{
+					Strategy:    SlugStrategySuffix,
+					MaxAttempts: 10,
+				},
+				Search: Search{
+					Language: "english",
+				},
+				Cursors: Cursors{
+					SecretKey: "this-is-a-32-char-long-cursor-key-123",
+				},
+				Comments: Comments{
+					MaxDepth:   5,
+					EditWindow: 15 * time.Minute,
+				},
+				Trash: Trash{
+					Retention:      720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 100,
+				},
+				Stream: Stream{
+					HeartbeatInterval: 15 * time.Second,
+					HistorySize:       1000,
+				},
+				Webhooks: Webhooks{
+					Interval:        5 * time.Second,
+					BatchSize:       50,
+					Timeout:         10 * time.Second,
+					MaxAttempts:     8,
+					RetryBackoff:    30 * time.Second,
+					MaxRetryBackoff: 10 * time.Second,
+				},
+				Outbox: Outbox{
+					Interval:        time.Second,
+					BatchSize:       100,
+					Sinks:           []string{OutboxSinkBus},
+					Timeout:         10 * time.Second,
+					MaxAttempts:     10,
+					RetryBackoff:    5 * time.Second,
+					MaxRetryBackoff: 5 * time.Minute,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Unknown outbox sink",
+			config: Config{
+				Database: Database{
+					Host:     "localhost",
+					Port:     "5432",
+					User:     "testuser",
+					Password: "testpass",
+					Name:     "testdb",
+					SSLMode:  "disable",
+
+					MaxOpenConns:    10,
+					MaxIdleConns:    5,
+					ConnMaxLifetime: 10 * time.Second,
+					ConnMaxIdleTime: 5 * time.Second,
+				},
+				JWT: JWT{
+					Algorithm:      AlgorithmHS256,
+					SecretKey:      "this-is-a-32-char-long-secret-key-123",
+					Expiry:         24 * time.Hour,
+					RefreshExpiry:  720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 1000,
+				},
+				Server: Server{
+					Port: "8080",
+				},
+				Publisher: Publisher{
+					Interval:  30 * time.Second,
+					BatchSize: 100,
+				},
+				Slugs: Slugs{
+					Strategy:    SlugStrategySuffix,
+					MaxAttempts: 10,
+				},
+				Search: Search{
+					Language: "english",
+				},
+				Cursors: Cursors{
+					SecretKey: "this-is-a-32-char-long-cursor-key-123",
+				},
+				Comments: Comments{
+					MaxDepth:   5,
+					EditWindow: 15 * time.Minute,
+				},
+				Trash: Trash{
+					Retention:      720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 100,
+				},
+				Stream: Stream{
+					HeartbeatInterval: 15 * time.Second,
+					HistorySize:       1000,
+				},
+				Webhooks: Webhooks{
+					Interval:        5 * time.Second,
+					BatchSize:       50,
+					Timeout:         10 * time.Second,
+					MaxAttempts:     8,
+					RetryBackoff:    30 * time.Second,
+					MaxRetryBackoff: 6 * time.Hour,
+				},
+				Outbox: Outbox{
+					Interval:        time.Second,
+					BatchSize:       100,
+					Sinks:           []string{OutboxSinkBus, "kafka"},
+					Timeout:         10 * time.Second,
+					MaxAttempts:     10,
+					RetryBackoff:    5 * time.Second,
+					MaxRetryBackoff: 5 * time.Minute,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "HTTP outbox sink without URL",
+			config: Config{
+				Database: Database{
+					Host:     "localhost",
+					Port:     "5432",
+					User:     "testuser",
+					Password: "testpass",
+					Name:     "testdb",
+					SSLMode:  "disable",
+
+					MaxOpenConns:    10,
+					MaxIdleConns:    5,
+					ConnMaxLifetime: 10 * time.Second,
+					ConnMaxIdleTime: 5 * time.Second,
+				},
+				JWT: JWT{
+					Algorithm:      AlgorithmHS256,
+					SecretKey:      "this-is-a-32-char-long-secret-key-123",
+					Expiry:         24 * time.Hour,
+					RefreshExpiry:  720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 1000,
+				},
+				Server: Server{
+					Port: "8080",
+				},
+				Publisher: Publisher{
+					Interval:  30 * time.Second,
+					BatchSize: 100,
+				},
+				Slugs: Slugs{
+					Strategy:    SlugStrategySuffix,
+					MaxAttempts: 10,
+				},
+				Search: Search{
+					Language: "english",
+				},
+				Cursors: Cursors{
+					SecretKey: "this-is-a-32-char-long-cursor-key-123",
+				},
+				Comments: Comments{
+					MaxDepth:   5,
+					EditWindow: 15 * time.Minute,
+				},
+				Trash: Trash{
+					Retention:      720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 100,
+				},
+				Stream: Stream{
+					HeartbeatInterval: 15 * time.Second,
+					HistorySize:       1000,
+				},
+				Webhooks: Webhooks{
+					Interval:        5 * time.Second,
+					BatchSize:       50,
+					Timeout:         10 * time.Second,
+					MaxAttempts:     8,
+					RetryBackoff:    30 * time.Second,
+					MaxRetryBackoff: 6 * time.Hour,
+				},
+				Outbox: Outbox{
+					Interval:        time.Second,
+					BatchSize:       100,
+					Sinks:           []string{OutboxSinkBus, OutboxSinkHTTP},
+					Timeout:         10 * time.Second,
+					MaxAttempts:     10,
+					RetryBackoff:    5 * time.Second,
+					MaxRetryBackoff: 5 * time.Minute,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Outbox sinks without the bus",
+			config: Config{
+				Database: Database{
+					Host:     "localhost",
+					Port:     "5432",
+					User:     "testuser",
+					Password: "testpass",
+					Name:     "testdb",
+					SSLMode:  "disable",
+
+					MaxOpenConns:    10,
+					MaxIdleConns:    5,
+					ConnMaxLifetime: 10 * time.Second,
+					ConnMaxIdleTime: 5 * time.Second,
+				},
+				JWT: JWT{
+					Algorithm:      AlgorithmHS256,
+					SecretKey:      "this-is-a-32-char-long-secret-key-123",
+					Expiry:         24 * time.Hour,
+					RefreshExpiry:  720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 1000,
+				},
+				Server: Server{
+					Port: "8080",
+				},
+				Publisher: Publisher{
+					Interval:  30 * time.Second,
+					BatchSize: 100,
+				},
+				Slugs: Slugs{
+					Strategy:    SlugStrategySuffix,
+					MaxAttempts: 10,
+				},
+				Search: Search{
+					Language: "english",
+				},
+				Cursors: Cursors{
+					SecretKey: "this-is-a-32-char-long-cursor-key-123",
+				},
+				Comments: Comments{
+					MaxDepth:   5,
+					EditWindow: 15 * time.Minute,
+				},
+				Trash: Trash{
+					Retention:      720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 100,
+				},
+				Stream: Stream{
+					HeartbeatInterval: 15 * time.Second,
+					HistorySize:       1000,
+				},
+				Webhooks: Webhooks{
+					Interval:        5 * time.Second,
+					BatchSize:       50,
+					Timeout:         10 * time.Second,
+					MaxAttempts:     8,
+					RetryBackoff:    30 * time.Second,
+					MaxRetryBackoff: 6 * time.Hour,
+				},
+				Outbox: Outbox{
+					Interval:        time.Second,
+					BatchSize:       100,
+					Sinks:           []string{OutboxSinkLog},
+					Timeout:         10 * time.Second,
+					MaxAttempts:     10,
+					RetryBackoff:    5 * time.Second,
+					MaxRetryBackoff: 5 * time.Minute,
+				},
 			},
-			wantErr: false,
+			wantErr: true,
 		},
 		{
 			name: "Missing database host",
@@ -87,12 +960,60 @@ func TestConfig_Validate(t *testing.T) {
 					ConnMaxIdleTime: 5 * time.Second,
 				},
 				JWT: JWT{
-					SecretKey: "this-is-a-32-char-long-secret-key-123",
-					Expiry:    24 * time.Hour,
+					Algorithm:      AlgorithmHS256,
+					SecretKey:      "this-is-a-32-char-long-secret-key-123",
+					Expiry:         24 * time.Hour,
+					RefreshExpiry:  720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 1000,
 				},
 				Server: Server{
 					Port: "8080",
 				},
+				Publisher: Publisher{
+					Interval:  30 * time.Second,
+					BatchSize: 100,
+				},
+				Slugs: Slugs{
+					Strategy:    SlugStrategySuffix,
+					MaxAttempts: 10,
+				},
+				Search: Search{
+					Language: "english",
+				},
+				Cursors: Cursors{
+					SecretKey: "this-is-a-32-char-long-cursor-key-123",
+				},
+				Comments: Comments{
+					MaxDepth:   5,
+					EditWindow: 15 * time.Minute,
+				},
+				Trash: Trash{
+					Retention:      720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 100,
+				},
+				Stream: Stream{
+					HeartbeatInterval: 15 * time.Second,
+					HistorySize:       1000,
+				},
+				Webhooks: Webhooks{
+					Interval:        5 * time.Second,
+					BatchSize:       50,
+					Timeout:         10 * time.Second,
+					MaxAttempts:     8,
+					RetryBackoff:    30 * time.Second,
+					MaxRetryBackoff: 6 * time.Hour,
+				},
+				Outbox: Outbox{
+					Interval:        time.Second,
+					BatchSize:       100,
+					Sinks:           []string{OutboxSinkBus},
+					Timeout:         10 * time.Second,
+					MaxAttempts:     10,
+					RetryBackoff:    5 * time.Second,
+					MaxRetryBackoff: 5 * time.Minute,
+				},
 			},
 			wantErr: true,
 		},
@@ -113,12 +1034,504 @@ func TestConfig_Validate(t *testing.T) {
 					ConnMaxIdleTime: 5 * time.Second,
 				},
 				JWT: JWT{
-					SecretKey: "this-is-a-32-char-long-secret-key-123",
-					Expiry:    -1 * time.Hour,
+					Algorithm:      AlgorithmHS256,
+					SecretKey:      "this-is-a-32-char-long-secret-key-123",
+					Expiry:         -1 * time.Hour,
+					RefreshExpiry:  720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 1000,
+				},
+				Server: Server{
+					Port: "8080",
+				},
+				Publisher: Publisher{
+					Interval:  30 * time.Second,
+					BatchSize: 100,
+				},
+				Slugs: Slugs{
+					Strategy:    SlugStrategySuffix,
+					MaxAttempts: 10,
+				},
+				Search: Search{
+					Language: "english",
+				},
+				Cursors: Cursors{
+					SecretKey: "this-is-a-32-char-long-cursor-key-123",
+				},
+				Comments: Comments{
+					MaxDepth:   5,
+					EditWindow: 15 * time.Minute,
+				},
+				Trash: Trash{
+					Retention:      720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 100,
+				},
+				Stream: Stream{
+					HeartbeatInterval: 15 * time.Second,
+					HistorySize:       1000,
+				},
+				Webhooks: Webhooks{
+					Interval:        5 * time.Second,
+					BatchSize:       50,
+					Timeout:         10 * time.Second,
+					MaxAttempts:     8,
+					RetryBackoff:    30 * time.Second,
+					MaxRetryBackoff: 6 * time.Hour,
+				},
+				Outbox: Outbox{
+					Interval:        time.Second,
+					BatchSize:       100,
+					Sinks:           []string{OutboxSinkBus},
+					Timeout:         10 * time.Second,
+					MaxAttempts:     10,
+					RetryBackoff:    5 * time.Second,
+					MaxRetryBackoff: 5 * time.Minute,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Refresh expiry shorter than JWT expiry",
+			config: Config{
+				Database: Database{
+					Host:     "localhost",
+					Port:     "5432",
+					User:     "testuser",
+					Password: "testpass",
+					Name:     "testdb",
+					SSLMode:  "disable",
+
+					MaxOpenConns:    10,
+					MaxIdleConns:    5,
+					ConnMaxLifetime: 10 * time.Second,
+					ConnMaxIdleTime: 5 * time.Second,
+				},
+				JWT: JWT{
+					Algorithm:      AlgorithmHS256,
+					SecretKey:      "this-is-a-32-char-long-secret-key-123",
+					Expiry:         24 * time.Hour,
+					RefreshExpiry:  time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 1000,
+				},
+				Server: Server{
+					Port: "8080",
+				},
+				Publisher: Publisher{
+					Interval:  30 * time.Second,
+					BatchSize: 100,
+				},
+				Slugs: Slugs{
+					Strategy:    SlugStrategySuffix,
+					MaxAttempts: 10,
+				},
+				Search: Search{
+					Language: "english",
+				},
+				Cursors: Cursors{
+					SecretKey: "this-is-a-32-char-long-cursor-key-123",
+				},
+				Comments: Comments{
+					MaxDepth:   5,
+					EditWindow: 15 * time.Minute,
+				},
+				Trash: Trash{
+					Retention:      720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 100,
+				},
+				Stream: Stream{
+					HeartbeatInterval: 15 * time.Second,
+					HistorySize:       1000,
+				},
+				Webhooks: Webhooks{
+					Interval:        5 * time.Second,
+					BatchSize:       50,
+					Timeout:         10 * time.Second,
+					MaxAttempts:     8,
+					RetryBackoff:    30 * time.Second,
+					MaxRetryBackoff: 6 * time.Hour,
+				},
+				Outbox: Outbox{
+					Interval:        time.Second,
+					BatchSize:       100,
+					Sinks:           []string{OutboxSinkBus},
+					Timeout:         10 * time.Second,
+					MaxAttempts:     10,
+					RetryBackoff:    5 * time.Second,
+					MaxRetryBackoff: 5 * time.Minute,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Zero JWT purge interval",
+			config: Config{
+				Database: Database{
+					Host:     "localhost",
+					Port:     "5432",
+					User:     "testuser",
+					Password: "testpass",
+					Name:     "testdb",
+					SSLMode:  "disable",
+
+					MaxOpenConns:    10,
+					MaxIdleConns:    5,
+					ConnMaxLifetime: 10 * time.Second,
+					ConnMaxIdleTime: 5 * time.Second,
+				},
+				JWT: JWT{
+					Algorithm:      AlgorithmHS256,
+					SecretKey:      "this-is-a-32-char-long-secret-key-123",
+					Expiry:         24 * time.Hour,
+					RefreshExpiry:  720 * time.Hour,
+					PurgeInterval:  0,
+					PurgeBatchSize: 1000,
+				},
+				Server: Server{
+					Port: "8080",
+				},
+				Publisher: Publisher{
+					Interval:  30 * time.Second,
+					BatchSize: 100,
+				},
+				Slugs: Slugs{
+					Strategy:    SlugStrategySuffix,
+					MaxAttempts: 10,
+				},
+				Search: Search{
+					Language: "english",
+				},
+				Cursors: Cursors{
+					SecretKey: "this-is-a-32-char-long-cursor-key-123",
+				},
+				Comments: Comments{
+					MaxDepth:   5,
+					EditWindow: 15 * time.Minute,
+				},
+				Trash: Trash{
+					Retention:      720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 100,
+				},
+				Stream: Stream{
+					HeartbeatInterval: 15 * time.Second,
+					HistorySize:       1000,
+				},
+				Webhooks: Webhooks{
+					Interval:        5 * time.Second,
+					BatchSize:       50,
+					Timeout:         10 * time.Second,
+					MaxAttempts:     8,
+					RetryBackoff:    30 * time.Second,
+					MaxRetryBackoff: 6 * time.Hour,
+				},
+				Outbox: Outbox{
+					Interval:        time.Second,
+					BatchSize:       100,
+					Sinks:           []string{OutboxSinkBus},
+					Timeout:         10 * time.Second,
+					MaxAttempts:     10,
+					RetryBackoff:    5 * time.Second,
+					MaxRetryBackoff: 5 * time.Minute,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Asymmetric signing without secret key",
+			config: Config{
+				Database: Database{
+					Host:     "localhost",
+					Port:     "5432",
+					User:     "testuser",
+					Password: "testpass",
+					Name:     "testdb",
+					SSLMode:  "disable",
+
+					MaxOpenConns:    10,
+					MaxIdleConns:    5,
+					ConnMaxLifetime: 10 * time.Second,
+					ConnMaxIdleTime: 5 * time.Second,
+				},
+				JWT: JWT{
+					Algorithm:      AlgorithmRS256,
+					PrivateKeyFile: "/etc/conduit/jwt.pem",
+					Expiry:         24 * time.Hour,
+					RefreshExpiry:  720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 1000,
+				},
+				Server: Server{
+					Port: "8080",
+				},
+				Publisher: Publisher{
+					Interval:  30 * time.Second,
+					BatchSize: 100,
+				},
+				Slugs: Slugs{
+					Strategy:    SlugStrategySuffix,
+					MaxAttempts: 10,
+				},
+				Search: Search{
+					Language: "english",
+				},
+				Cursors: Cursors{
+					SecretKey: "this-is-a-32-char-long-cursor-key-123",
+				},
+				Comments: Comments{
+					MaxDepth:   5,
+					EditWindow: 15 * time.Minute,
+				},
+				Trash: Trash{
+					Retention:      720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 100,
+				},
+				Stream: Stream{
+					HeartbeatInterval: 15 * time.Second,
+					HistorySize:       1000,
+				},
+				Webhooks: Webhooks{
+					Interval:        5 * time.Second,
+					BatchSize:       50,
+					Timeout:         10 * time.Second,
+					MaxAttempts:     8,
+					RetryBackoff:    30 * time.Second,
+					MaxRetryBackoff: 6 * time.Hour,
+				},
+				Outbox: Outbox{
+					Interval:        time.Second,
+					BatchSize:       100,
+					Sinks:           []string{OutboxSinkBus},
+					Timeout:         10 * time.Second,
+					MaxAttempts:     10,
+					RetryBackoff:    5 * time.Second,
+					MaxRetryBackoff: 5 * time.Minute,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Asymmetric signing without private key file",
+			config: Config{
+				Database: Database{
+					Host:     "localhost",
+					Port:     "5432",
+					User:     "testuser",
+					Password: "testpass",
+					Name:     "testdb",
+					SSLMode:  "disable",
+
+					MaxOpenConns:    10,
+					MaxIdleConns:    5,
+					ConnMaxLifetime: 10 * time.Second,
+					ConnMaxIdleTime: 5 * time.Second,
+				},
+				JWT: JWT{
+					Algorithm:      AlgorithmEdDSA,
+					Expiry:         24 * time.Hour,
+					RefreshExpiry:  720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 1000,
+				},
+				Server: Server{
+					Port: "8080",
+				},
+				Publisher: Publisher{
+					Interval:  30 * time.Second,
+					BatchSize: 100,
+				},
+				Slugs: Slugs{
+					Strategy:    SlugStrategySuffix,
+					MaxAttempts: 10,
+				},
+				Search: Search{
+					Language: "english",
+				},
+				Cursors: Cursors{
+					SecretKey: "this-is-a-32-char-long-cursor-key-123",
+				},
+				Comments: Comments{
+					MaxDepth:   5,
+					EditWindow: 15 * time.Minute,
+				},
+				Trash: Trash{
+					Retention:      720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 100,
+				},
+				Stream: Stream{
+					HeartbeatInterval: 15 * time.Second,
+					HistorySize:       1000,
+				},
+				Webhooks: Webhooks{
+					Interval:        5 * time.Second,
+					BatchSize:       50,
+					Timeout:         10 * time.Second,
+					MaxAttempts:     8,
+					RetryBackoff:    30 * time.Second,
+					MaxRetryBackoff: 6 * time.Hour,
+				},
+				Outbox: Outbox{
+					Interval:        time.Second,
+					BatchSize:       100,
+					Sinks:           []string{OutboxSinkBus},
+					Timeout:         10 * time.Second,
+					MaxAttempts:     10,
+					RetryBackoff:    5 * time.Second,
+					MaxRetryBackoff: 5 * time.Minute,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "HMAC fallback without secret key",
+			config: Config{
+				Database: Database{
+					Host:     "localhost",
+					Port:     "5432",
+					User:     "testuser",
+					Password: "testpass",
+					Name:     "testdb",
+					SSLMode:  "disable",
+
+					MaxOpenConns:    10,
+					MaxIdleConns:    5,
+					ConnMaxLifetime: 10 * time.Second,
+					ConnMaxIdleTime: 5 * time.Second,
+				},
+				JWT: JWT{
+					Algorithm:      AlgorithmRS256,
+					PrivateKeyFile: "/etc/conduit/jwt.pem",
+					HMACFallback:   true,
+					Expiry:         24 * time.Hour,
+					RefreshExpiry:  720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 1000,
+				},
+				Server: Server{
+					Port: "8080",
+				},
+				Publisher: Publisher{
+					Interval:  30 * time.Second,
+					BatchSize: 100,
+				},
+				Slugs: Slugs{
+					Strategy:    SlugStrategySuffix,
+					MaxAttempts: 10,
+				},
+				Search: Search{
+					Language: "english",
+				},
+				Cursors: Cursors{
+					SecretKey: "this-is-a-32-char-long-cursor-key-123",
+				},
+				Comments: Comments{
+					MaxDepth:   5,
+					EditWindow: 15 * time.Minute,
+				},
+				Trash: Trash{
+					Retention:      720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 100,
+				},
+				Stream: Stream{
+					HeartbeatInterval: 15 * time.Second,
+					HistorySize:       1000,
+				},
+				Webhooks: Webhooks{
+					Interval:        5 * time.Second,
+					BatchSize:       50,
+					Timeout:         10 * time.Second,
+					MaxAttempts:     8,
+					RetryBackoff:    30 * time.Second,
+					MaxRetryBackoff: 6 * time.Hour,
+				},
+				Outbox: Outbox{
+					Interval:        time.Second,
+					BatchSize:       100,
+					Sinks:           []string{OutboxSinkBus},
+					Timeout:         10 * time.Second,
+					MaxAttempts:     10,
+					RetryBackoff:    5 * time.Second,
+					MaxRetryBackoff: 5 * time.Minute,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Unsupported JWT algorithm",
+			config: Config{
+				Database: Database{
+					Host:     "localhost",
+					Port:     "5432",
+					User:     "testuser",
+					Password: "testpass",
+					Name:     "testdb",
+					SSLMode:  "disable",
+
+					MaxOpenConns:    10,
+					MaxIdleConns:    5,
+					ConnMaxLifetime: 10 * time.Second,
+					ConnMaxIdleTime: 5 * time.Second,
+				},
+				JWT: JWT{
+					Algorithm:      "none",
+					SecretKey:      "this-is-a-32-char-long-secret-key-123",
+					Expiry:         24 * time.Hour,
+					RefreshExpiry:  720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 1000,
 				},
 				Server: Server{
 					Port: "8080",
 				},
+				Publisher: Publisher{
+					Interval:  30 * time.Second,
+					BatchSize: 100,
+				},
+				Slugs: Slugs{
+					Strategy:    SlugStrategySuffix,
+					MaxAttempts: 10,
+				},
+				Search: Search{
+					Language: "english",
+				},
+				Cursors: Cursors{
+					SecretKey: "this-is-a-32-char-long-cursor-key-123",
+				},
+				Comments: Comments{
+					MaxDepth:   5,
+					EditWindow: 15 * time.Minute,
+				},
+				Trash: Trash{
+					Retention:      720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 100,
+				},
+				Stream: Stream{
+					HeartbeatInterval: 15 * time.Second,
+					HistorySize:       1000,
+				},
+				Webhooks: Webhooks{
+					Interval:        5 * time.Second,
+					BatchSize:       50,
+					Timeout:         10 * time.Second,
+					MaxAttempts:     8,
+					RetryBackoff:    30 * time.Second,
+					MaxRetryBackoff: 6 * time.Hour,
+				},
+				Outbox: Outbox{
+					Interval:        time.Second,
+					BatchSize:       100,
+					Sinks:           []string{OutboxSinkBus},
+					Timeout:         10 * time.Second,
+					MaxAttempts:     10,
+					RetryBackoff:    5 * time.Second,
+					MaxRetryBackoff: 5 * time.Minute,
+				},
 			},
 			wantErr: true,
 		},
@@ -139,12 +1552,60 @@ func TestConfig_Validate(t *testing.T) {
 					ConnMaxIdleTime: 5 * time.Second,
 				},
 				JWT: JWT{
-					SecretKey: "this-is-a-32-char-long-secret-key-123",
-					Expiry:    24 * time.Hour,
+					Algorithm:      AlgorithmHS256,
+					SecretKey:      "this-is-a-32-char-long-secret-key-123",
+					Expiry:         24 * time.Hour,
+					RefreshExpiry:  720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 1000,
 				},
 				Server: Server{
 					Port: "",
 				},
+				Publisher: Publisher{
+					Interval:  30 * time.Second,
+					BatchSize: 100,
+				},
+				Slugs: Slugs{
+					Strategy:    SlugStrategySuffix,
+					MaxAttempts: 10,
+				},
+				Search: Search{
+					Language: "english",
+				},
+				Cursors: Cursors{
+					SecretKey: "this-is-a-32-char-long-cursor-key-123",
+				},
+				Comments: Comments{
+					MaxDepth:   5,
+					EditWindow: 15 * time.Minute,
+				},
+				Trash: Trash{
+					Retention:      720 * time.Hour,
+					PurgeInterval:  time.Hour,
+					PurgeBatchSize: 100,
+				},
+				Stream: Stream{
+					HeartbeatInterval: 15 * time.Second,
+					HistorySize:       1000,
+				},
+				Webhooks: Webhooks{
+					Interval:        5 * time.Second,
+					BatchSize:       50,
+					Timeout:         10 * time.Second,
+					MaxAttempts:     8,
+					RetryBackoff:    30 * time.Second,
+					MaxRetryBackoff: 6 * time.Hour,
+				},
+				Outbox: Outbox{
+					Interval:        time.Second,
+					BatchSize:       100,
+					Sinks:           []string{OutboxSinkBus},
+					Timeout:         10 * time.Second,
+					MaxAttempts:     10,
+					RetryBackoff:    5 * time.Second,
+					MaxRetryBackoff: 5 * time.Minute,
+				},
 			},
 			wantErr: true,
 		},
@@ -171,7 +1632,32 @@ DB_NAME=testdb
 DB_SSLMODE=require
 JWT_SECRET_KEY=test-secret-key-that-is-long-enough-for-security
 JWT_EXPIRY=12h
+JWT_REFRESH_EXPIRY=48h
+JWT_PURGE_INTERVAL=30m
+JWT_PURGE_BATCH_SIZE=500
+JWT_ALGORITHM=EdDSA
+JWT_PRIVATE_KEY_FILE=/etc/conduit/jwt.pem
+JWT_PUBLIC_KEY_FILES=/etc/conduit/old.pem, /etc/conduit/older.pem
+JWT_HMAC_FALLBACK=true
 SERVER_PORT=9090
+PUBLISHER_INTERVAL=1m
+PUBLISHER_BATCH_SIZE=25
+SLUG_STRATEGY=random
+SLUG_MAX_ATTEMPTS=5
+SEARCH_LANGUAGE=simple
+CURSOR_SECRET_KEY=test-cursor-key-that-is-long-enough-for-security
+COMMENT_MAX_DEPTH=3
+COMMENT_EDIT_WINDOW=1h
+COMMENT_MODERATORS=alice, bob
+TRASH_RETENTION=168h
+SERVER_WRITE_TIMEOUT=1m
+STREAM_HEARTBEAT_INTERVAL=20s
+STREAM_HISTORY_SIZE=500
+WEBHOOK_MAX_ATTEMPTS=5
+WEBHOOK_RETRY_BACKOFF=1m
+WEBHOOK_ALLOWED_NETWORKS=127.0.0.0/8, ::1/128
+OUTBOX_SINKS=bus, http
+OUTBOX_HTTP_URL=https://events.example.com/conduit
 APP_VERSION=2.0.0`
 
 	// Write temporary .env file
@@ -243,9 +1729,96 @@ APP_VERSION=2.0.0`
 	if cfg.JWT.Expiry != 12*time.Hour {
 		t.Errorf("Expected JWT_EXPIRY to be 12h, got '%v'", cfg.JWT.Expiry)
 	}
+	if cfg.JWT.RefreshExpiry != 48*time.Hour {
+		t.Errorf("Expected JWT_REFRESH_EXPIRY to be 48h, got '%v'", cfg.JWT.RefreshExpiry)
+	}
+	if cfg.JWT.PurgeInterval != 30*time.Minute {
+		t.Errorf("Expected JWT_PURGE_INTERVAL to be 30m, got '%v'", cfg.JWT.PurgeInterval)
+	}
+	if cfg.JWT.PurgeBatchSize != 500 {
+		t.Errorf("Expected JWT_PURGE_BATCH_SIZE to be 500, got %d", cfg.JWT.PurgeBatchSize)
+	}
+	if cfg.JWT.Algorithm != AlgorithmEdDSA {
+		t.Errorf("Expected JWT_ALGORITHM to be 'EdDSA', got '%s'", cfg.JWT.Algorithm)
+	}
+	if cfg.JWT.PrivateKeyFile != "/etc/conduit/jwt.pem" {
+		t.Errorf(
+			"Expected JWT_PRIVATE_KEY_FILE to be '/etc/conduit/jwt.pem', got '%s'",
+			cfg.JWT.PrivateKeyFile,
+		)
+	}
+	if len(cfg.JWT.PublicKeyFiles) != 2 || cfg.JWT.PublicKeyFiles[1] != "/etc/conduit/older.pem" {
+		t.Errorf("Expected JWT_PUBLIC_KEY_FILES to list 2 files, got '%v'", cfg.JWT.PublicKeyFiles)
+	}
+	if !cfg.JWT.HMACFallback {
+		t.Errorf("Expected JWT_HMAC_FALLBACK to be true")
+	}
 	if cfg.Server.Port != "9090" {
 		t.Errorf("Expected SERVER_PORT to be '9090', got '%s'", cfg.Server.Port)
 	}
+	if cfg.Publisher.Interval != time.Minute {
+		t.Errorf("Expected PUBLISHER_INTERVAL to be 1m, got '%v'", cfg.Publisher.Interval)
+	}
+	if cfg.Publisher.BatchSize != 25 {
+		t.Errorf("Expected PUBLISHER_BATCH_SIZE to be 25, got '%d'", cfg.Publisher.BatchSize)
+	}
+	if cfg.Slugs.Strategy != SlugStrategyRandom {
+		t.Errorf("Expected SLUG_STRATEGY to be random, got '%s'", cfg.Slugs.Strategy)
+	}
+	if cfg.Slugs.MaxAttempts != 5 {
+		t.Errorf("Expected SLUG_MAX_ATTEMPTS to be 5, got '%d'", cfg.Slugs.MaxAttempts)
+	}
+	if cfg.Search.Language != "simple" {
+		t.Errorf("Expected SEARCH_LANGUAGE to be 'simple', got '%s'", cfg.Search.Language)
+	}
+	if cfg.Cursors.SecretKey != "test-cursor-key-that-is-long-enough-for-security" {
+		t.Errorf("Expected CURSOR_SECRET_KEY to be set, got '%s'", cfg.Cursors.SecretKey)
+	}
+	if cfg.Comments.MaxDepth != 3 {
+		t.Errorf("Expected COMMENT_MAX_DEPTH to be 3, got '%d'", cfg.Comments.MaxDepth)
+	}
+	if cfg.Comments.EditWindow != time.Hour {
+		t.Errorf("Expected COMMENT_EDIT_WINDOW to be 1h, got '%v'", cfg.Comments.EditWindow)
+	}
+	if len(cfg.Comments.Moderators) != 2 || cfg.Comments.Moderators[1] != "bob" {
+		t.Errorf("Expected COMMENT_MODERATORS to list alice and bob, got '%v'", cfg.Comments.Moderators)
+	}
+	if cfg.Trash.Retention != 168*time.Hour {
+		t.Errorf("Expected TRASH_RETENTION to be 168h, got '%v'", cfg.Trash.Retention)
+	}
+	if cfg.Server.WriteTimeout != time.Minute {
+		t.Errorf("Expected SERVER_WRITE_TIMEOUT to be 1m, got '%v'", cfg.Server.WriteTimeout)
+	}
+	if cfg.Stream.HeartbeatInterval != 20*time.Second {
+		t.Errorf(
+			"Expected STREAM_HEARTBEAT_INTERVAL to be 20s, got '%v'",
+			cfg.Stream.HeartbeatInterval,
+		)
+	}
+	if cfg.Stream.HistorySize != 500 {
+		t.Errorf("Expected STREAM_HISTORY_SIZE to be 500, got '%d'", cfg.Stream.HistorySize)
+	}
+	if cfg.Webhooks.MaxAttempts != 5 {
+		t.Errorf("Expected WEBHOOK_MAX_ATTEMPTS to be 5, got '%d'", cfg.Webhooks.MaxAttempts)
+	}
+	if cfg.Webhooks.RetryBackoff != time.Minute {
+		t.Errorf("Expected WEBHOOK_RETRY_BACKOFF to be 1m, got '%v'", cfg.Webhooks.RetryBackoff)
+	}
+	if len(cfg.Webhooks.AllowedNetworks) != 2 || cfg.Webhooks.AllowedNetworks[1].String() != "::1/128" {
+		t.Errorf("Expected WEBHOOK_ALLOWED_NETWORKS to list 127.0.0.0/8 and ::1/128, got %v", cfg.Webhooks.AllowedNetworks)
+	}
+	if len(cfg.Outbox.Sinks) != 2 || cfg.Outbox.Sinks[0] != OutboxSinkBus || cfg.Outbox.Sinks[1] != OutboxSinkHTTP {
+		t.Errorf("Expected OUTBOX_SINKS to be [bus http], got %v", cfg.Outbox.Sinks)
+	}
+	if cfg.Outbox.HTTPURL != "https://events.example.com/conduit" {
+		t.Errorf("Expected OUTBOX_HTTP_URL to be 'https://events.example.com/conduit', got '%s'", cfg.Outbox.HTTPURL)
+	}
+	if cfg.Outbox.Interval != time.Second {
+		t.Errorf("Expected default outbox interval of 1s, got '%v'", cfg.Outbox.Interval)
+	}
+	if cfg.Outbox.MaxAttempts != 10 {
+		t.Errorf("Expected default outbox max attempts of 10, got '%d'", cfg.Outbox.MaxAttempts)
+	}
 	if cfg.Version != "2.0.0" {
 		t.Errorf("Expected APP_VERSION to be '2.0.0', got '%s'", cfg.Version)
 	}
@@ -0,0 +1,64 @@
+// Package cursor signs and verifies the opaque cursors of paginated lists.
+package cursor
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"github.com/Nilesh2000/conduit/internal/repository"
+)
+
+// payloadSize is the size of an encoded cursor before its signature
+const payloadSize = 16
+
+// ErrInvalidCursor is returned when a token is not a cursor signed with the signer's key
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Signer turns cursors into tokens and back. Tokens are signed so that clients cannot
+// forge positions in a list, only hand back the ones they were given.
+type Signer struct {
+	key []byte
+}
+
+// NewSigner creates a new Signer signing tokens with key
+func NewSigner(key []byte) *Signer {
+	return &Signer{key: key}
+}
+
+// Encode returns the token of a cursor
+func (s *Signer) Encode(c repository.Cursor) string {
+	payload := make([]byte, payloadSize, payloadSize+sha256.Size)
+	binary.BigEndian.PutUint64(payload[:8], uint64(c.CreatedAt.UnixNano()))
+	binary.BigEndian.PutUint64(payload[8:], uint64(c.ID))
+
+	return base64.RawURLEncoding.EncodeToString(append(payload, s.sign(payload)...))
+}
+
+// Decode returns the cursor of a token
+func (s *Signer) Decode(token string) (repository.Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(data) != payloadSize+sha256.Size {
+		return repository.Cursor{}, ErrInvalidCursor
+	}
+
+	payload, signature := data[:payloadSize], data[payloadSize:]
+	if !hmac.Equal(signature, s.sign(payload)) {
+		return repository.Cursor{}, ErrInvalidCursor
+	}
+
+	return repository.Cursor{
+		CreatedAt: time.Unix(0, int64(binary.BigEndian.Uint64(payload[:8]))).UTC(),
+		ID:        int64(binary.BigEndian.Uint64(payload[8:])),
+	}, nil
+}
+
+// sign returns the signature of a payload
+func (s *Signer) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,80 @@
+package cursor
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Nilesh2000/conduit/internal/repository"
+)
+
+// TestSigner tests that tokens decode to the cursor they were encoded from, and only
+// with the key they were signed with
+func TestSigner(t *testing.T) {
+	t.Parallel()
+
+	signer := NewSigner([]byte("this-is-a-32-char-long-cursor-key-123"))
+	c := repository.Cursor{
+		CreatedAt: time.Date(2024, 1, 1, 12, 30, 0, 123456000, time.UTC),
+		ID:        42,
+	}
+	token := signer.Encode(c)
+
+	tests := []struct {
+		name        string
+		signer      *Signer
+		token       string
+		expectedErr error
+	}{
+		{
+			name:        "Valid token",
+			signer:      signer,
+			token:       token,
+			expectedErr: nil,
+		},
+		{
+			name:        "Token signed with another key",
+			signer:      NewSigner([]byte("another-32-char-long-cursor-key-4567")),
+			token:       token,
+			expectedErr: ErrInvalidCursor,
+		},
+		{
+			name:        "Tampered token",
+			signer:      signer,
+			token:       "A" + token[1:],
+			expectedErr: ErrInvalidCursor,
+		},
+		{
+			name:        "Truncated token",
+			signer:      signer,
+			token:       token[:len(token)-4],
+			expectedErr: ErrInvalidCursor,
+		},
+		{
+			name:        "Not base64",
+			signer:      signer,
+			token:       "not a cursor!",
+			expectedErr: ErrInvalidCursor,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Call method
+			decoded, err := tt.signer.Decode(tt.token)
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate cursor if no error
+			if err == nil && (!decoded.CreatedAt.Equal(c.CreatedAt) || decoded.ID != c.ID) {
+				t.Errorf("Expected cursor %+v, got %+v", c, decoded)
+			}
+		})
+	}
+}
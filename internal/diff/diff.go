@@ -0,0 +1,174 @@
+package diff
+
+import (
+	"slices"
+	"strings"
+)
+
+// Line operations
+const (
+	OpEqual  = "equal"
+	OpInsert = "insert"
+	OpDelete = "delete"
+)
+
+// Line is a line of a diff
+type Line struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// Lines returns a line-based diff turning a into b. Deleted lines come before the lines
+// inserted in their place.
+func Lines(a, b string) []Line {
+	oldLines := splitLines(a)
+	newLines := splitLines(b)
+
+	// Lines shared at the start and end do not need to go through the LCS table
+	prefix := 0
+	for prefix < len(oldLines) && prefix < len(newLines) &&
+		oldLines[prefix] == newLines[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix &&
+		oldLines[len(oldLines)-1-suffix] == newLines[len(newLines)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]Line, 0, len(oldLines)+len(newLines))
+	for _, line := range oldLines[:prefix] {
+		lines = append(lines, Line{Op: OpEqual, Text: line})
+	}
+	lines = append(
+		lines,
+		middle(oldLines[prefix:len(oldLines)-suffix], newLines[prefix:len(newLines)-suffix])...,
+	)
+	for _, line := range oldLines[len(oldLines)-suffix:] {
+		lines = append(lines, Line{Op: OpEqual, Text: line})
+	}
+
+	return lines
+}
+
+// maxCells bounds the work of diffing the lines that differ between two texts, as the
+// number of pairs of lines compared. Beyond it the lines are replaced as a whole.
+const maxCells = 1 << 22
+
+// middle diffs two slices of lines using their longest common subsequence
+func middle(oldLines, newLines []string) []Line {
+	lines := make([]Line, 0, len(oldLines)+len(newLines))
+	if len(oldLines)*len(newLines) > maxCells {
+		return replace(lines, oldLines, newLines)
+	}
+
+	return deletesFirst(subsequence(lines, oldLines, newLines))
+}
+
+// subsequence appends a diff of oldLines and newLines following their longest common
+// subsequence to lines. The subsequence is found with Hirschberg's algorithm, which
+// only keeps a row of the LCS table at a time.
+func subsequence(lines []Line, oldLines, newLines []string) []Line {
+	switch {
+	case len(oldLines) == 0 || len(newLines) == 0:
+		return replace(lines, oldLines, newLines)
+	case len(oldLines) == 1:
+		j := slices.Index(newLines, oldLines[0])
+		if j < 0 {
+			return replace(lines, oldLines, newLines)
+		}
+		lines = replace(lines, nil, newLines[:j])
+		lines = append(lines, Line{Op: OpEqual, Text: oldLines[0]})
+		return replace(lines, nil, newLines[j+1:])
+	}
+
+	// Split newLines where the halves of oldLines share the most lines with each side
+	mid := len(oldLines) / 2
+	forward := lcsRow(oldLines[:mid], newLines, false)
+	backward := lcsRow(oldLines[mid:], newLines, true)
+	split, best := 0, -1
+	for k := range forward {
+		if length := forward[k] + backward[len(newLines)-k]; length > best {
+			split, best = k, length
+		}
+	}
+
+	lines = subsequence(lines, oldLines[:mid], newLines[:split])
+	return subsequence(lines, oldLines[mid:], newLines[split:])
+}
+
+// lcsRow returns the length of the longest common subsequence of a and each prefix of b,
+// indexed by the length of the prefix, or of each suffix of b if reverse is set, in
+// which case a is read backwards as well
+func lcsRow(a, b []string, reverse bool) []int {
+	m := len(b)
+	prev := make([]int, m+1)
+	cur := make([]int, m+1)
+	for i := range a {
+		lineA := a[i]
+		if reverse {
+			lineA = a[len(a)-1-i]
+		}
+		for j := 1; j <= m; j++ {
+			lineB := b[j-1]
+			if reverse {
+				lineB = b[m-j]
+			}
+			if lineA == lineB {
+				cur[j] = prev[j-1] + 1
+			} else {
+				cur[j] = max(prev[j], cur[j-1])
+			}
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev
+}
+
+// replace appends the deletion of oldLines followed by the insertion of newLines to lines
+func replace(lines []Line, oldLines, newLines []string) []Line {
+	for _, line := range oldLines {
+		lines = append(lines, Line{Op: OpDelete, Text: line})
+	}
+	for _, line := range newLines {
+		lines = append(lines, Line{Op: OpInsert, Text: line})
+	}
+	return lines
+}
+
+// deletesFirst moves the deleted lines of each run of changes before the inserted ones
+func deletesFirst(lines []Line) []Line {
+	sorted := make([]Line, 0, len(lines))
+	for start := 0; start < len(lines); {
+		if lines[start].Op == OpEqual {
+			sorted = append(sorted, lines[start])
+			start++
+			continue
+		}
+
+		end := start
+		for end < len(lines) && lines[end].Op != OpEqual {
+			end++
+		}
+		for _, op := range []string{OpDelete, OpInsert} {
+			for _, line := range lines[start:end] {
+				if line.Op == op {
+					sorted = append(sorted, line)
+				}
+			}
+		}
+		start = end
+	}
+
+	return sorted
+}
+
+// splitLines splits text into lines. Empty text has no lines.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
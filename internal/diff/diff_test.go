@@ -0,0 +1,169 @@
+package diff
+
+import (
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestLines tests the Lines function
+func TestLines(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		a        string
+		b        string
+		expected []Line
+	}{
+		{
+			name:     "Both empty",
+			a:        "",
+			b:        "",
+			expected: []Line{},
+		},
+		{
+			name: "Identical",
+			a:    "one\ntwo",
+			b:    "one\ntwo",
+			expected: []Line{
+				{Op: OpEqual, Text: "one"},
+				{Op: OpEqual, Text: "two"},
+			},
+		},
+		{
+			name: "From empty",
+			a:    "",
+			b:    "one\ntwo",
+			expected: []Line{
+				{Op: OpInsert, Text: "one"},
+				{Op: OpInsert, Text: "two"},
+			},
+		},
+		{
+			name: "To empty",
+			a:    "one\ntwo",
+			b:    "",
+			expected: []Line{
+				{Op: OpDelete, Text: "one"},
+				{Op: OpDelete, Text: "two"},
+			},
+		},
+		{
+			name: "Changed line",
+			a:    "one\ntwo\nthree",
+			b:    "one\n2\nthree",
+			expected: []Line{
+				{Op: OpEqual, Text: "one"},
+				{Op: OpDelete, Text: "two"},
+				{Op: OpInsert, Text: "2"},
+				{Op: OpEqual, Text: "three"},
+			},
+		},
+		{
+			name: "Inserted and deleted lines",
+			a:    "a\nb\nc\nd",
+			b:    "a\nc\nd\ne",
+			expected: []Line{
+				{Op: OpEqual, Text: "a"},
+				{Op: OpDelete, Text: "b"},
+				{Op: OpEqual, Text: "c"},
+				{Op: OpEqual, Text: "d"},
+				{Op: OpInsert, Text: "e"},
+			},
+		},
+		{
+			name: "Moved line",
+			a:    "x\na\nb\ny",
+			b:    "x\nb\na\ny",
+			expected: []Line{
+				{Op: OpEqual, Text: "x"},
+				{Op: OpDelete, Text: "a"},
+				{Op: OpEqual, Text: "b"},
+				{Op: OpInsert, Text: "a"},
+				{Op: OpEqual, Text: "y"},
+			},
+		},
+		{
+			name: "Trailing newline and CRLF are ignored",
+			a:    "one\r\ntwo\r\n",
+			b:    "one\ntwo",
+			expected: []Line{
+				{Op: OpEqual, Text: "one"},
+				{Op: OpEqual, Text: "two"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := Lines(tt.a, tt.b)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Lines() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestLines_Sides tests that the diff of generated texts turns one into the other and
+// keeps as many lines as the longest common subsequence
+func TestLines_Sides(t *testing.T) {
+	t.Parallel()
+
+	random := rand.New(rand.NewSource(1))
+	text := func() []string {
+		lines := make([]string, random.Intn(20))
+		for i := range lines {
+			lines[i] = strconv.Itoa(random.Intn(4))
+		}
+		return lines
+	}
+
+	for range 200 {
+		a, b := text(), text()
+		got := Lines(strings.Join(a, "\n"), strings.Join(b, "\n"))
+
+		oldLines, newLines, equal := []string{}, []string{}, 0
+		for _, line := range got {
+			if line.Op != OpInsert {
+				oldLines = append(oldLines, line.Text)
+			}
+			if line.Op != OpDelete {
+				newLines = append(newLines, line.Text)
+			}
+			if line.Op == OpEqual {
+				equal++
+			}
+		}
+		if !reflect.DeepEqual(oldLines, a) ||
+			!reflect.DeepEqual(newLines, b) {
+			t.Fatalf("Lines(%q, %q) = %v does not turn one into the other", a, b, got)
+		}
+		if want := lcsRow(a, b, false)[len(b)]; equal != want {
+			t.Fatalf("Lines(%q, %q) kept %d lines, want %d", a, b, equal, want)
+		}
+	}
+}
+
+// TestLines_Large tests that texts too large to diff line by line are replaced as a whole
+func TestLines_Large(t *testing.T) {
+	t.Parallel()
+
+	oldLines := make([]string, 3000)
+	newLines := make([]string, 3000)
+	for i := range oldLines {
+		oldLines[i] = "old " + strconv.Itoa(i)
+		newLines[i] = "new " + strconv.Itoa(i)
+	}
+	newLines[1500] = oldLines[1500]
+
+	got := Lines(strings.Join(oldLines, "\n"), strings.Join(newLines, "\n"))
+	if len(got) != 6000 || got[2999].Op != OpDelete || got[3000].Op != OpInsert {
+		t.Errorf("Expected every old line deleted before every new line inserted, got %d lines", len(got))
+	}
+}
@@ -5,8 +5,12 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/Nilesh2000/conduit/internal/cursor"
 	"github.com/Nilesh2000/conduit/internal/middleware"
 	"github.com/Nilesh2000/conduit/internal/repository"
 	"github.com/Nilesh2000/conduit/internal/response"
@@ -19,19 +23,31 @@ import (
 // CreateArticleRequest is the request body for creating an article
 type CreateArticleRequest struct {
 	Article struct {
-		Title       string   `json:"title" validate:"required"`
-		Description string   `json:"description" validate:"required"`
-		Body        string   `json:"body" validate:"required"`
-		TagList     []string `json:"tagList,omitempty"`
+		Title       string     `json:"title" validate:"required"`
+		Description string     `json:"description" validate:"required"`
+		Body        string     `json:"body" validate:"required,max=100000"`
+		TagList     []string   `json:"tagList,omitempty"`
+		Status      string     `json:"status" validate:"omitempty,oneof=draft published,excluded_with=PublishAt"`
+		PublishAt   *time.Time `json:"publishAt"`
 	} `json:"article" validate:"required"`
 }
 
 // UpdateArticleRequest is the request body for updating an article
 type UpdateArticleRequest struct {
 	Article struct {
-		Title       *string `json:"title" validate:"omitempty"`
-		Description *string `json:"description" validate:"omitempty"`
-		Body        *string `json:"body" validate:"omitempty"`
+		Title       *string  `json:"title" validate:"omitempty"`
+		Description *string  `json:"description" validate:"omitempty"`
+		Body        *string  `json:"body" validate:"omitempty,max=100000"`
+		TagList     []string `json:"tagList" validate:"excluded_with=AddTags RemoveTags"`
+		AddTags     []string `json:"addTags"`
+		RemoveTags  []string `json:"removeTags"`
+	} `json:"article" validate:"required"`
+}
+
+// ScheduleArticleRequest is the request body for scheduling an article
+type ScheduleArticleRequest struct {
+	Article struct {
+		PublishAt *time.Time `json:"publishAt" validate:"required"`
 	} `json:"article" validate:"required"`
 }
 
@@ -44,6 +60,8 @@ type ArticleResponse struct {
 type MultipleArticlesResponse struct {
 	Articles      []service.Article `json:"articles"`
 	ArticlesCount int               `json:"articlesCount"`
+	NextCursor    string            `json:"nextCursor,omitempty"`
+	PrevCursor    string            `json:"prevCursor,omitempty"`
 }
 
 // ArticleService defines the interface for article service operations
@@ -51,7 +69,8 @@ type ArticleService interface {
 	CreateArticle(
 		ctx context.Context,
 		userID int64,
-		title, description, body string,
+		title, description, body, status string,
+		publishAt *time.Time,
 		tagList []string,
 	) (*service.Article, error)
 	GetArticle(ctx context.Context, slug string, currentUserID *int64) (*service.Article, error)
@@ -60,10 +79,20 @@ type ArticleService interface {
 		userID int64,
 		slug string,
 		title, description, body *string,
+		tags repository.TagChanges,
 	) (*service.Article, error)
 	DeleteArticle(ctx context.Context, userID int64, slug string) error
 	FavoriteArticle(ctx context.Context, userID int64, slug string) (*service.Article, error)
 	UnfavoriteArticle(ctx context.Context, userID int64, slug string) (*service.Article, error)
+	PublishArticle(ctx context.Context, userID int64, slug string) (*service.Article, error)
+	UnpublishArticle(ctx context.Context, userID int64, slug string) (*service.Article, error)
+	ArchiveArticle(ctx context.Context, userID int64, slug string) (*service.Article, error)
+	ScheduleArticle(
+		ctx context.Context,
+		userID int64,
+		slug string,
+		publishAt time.Time,
+	) (*service.Article, error)
 	ListArticles(
 		ctx context.Context,
 		filters repository.ArticleFilters,
@@ -72,20 +101,23 @@ type ArticleService interface {
 	GetArticlesFeed(
 		ctx context.Context,
 		userID int64,
-		limit, offset int,
+		filters repository.FeedFilters,
 	) (*repository.ArticleListResult, error)
 }
 
 // articleHandler is a handler for article operations
 type articleHandler struct {
 	articleService ArticleService
+	cursors        *cursor.Signer
 	validate       *validator.Validate
 }
 
-// NewArticleHandler creates a new ArticleHandler
-func NewArticleHandler(articleService ArticleService) *articleHandler {
+// NewArticleHandler creates a new ArticleHandler that signs the cursors of article lists
+// with cursors
+func NewArticleHandler(articleService ArticleService, cursors *cursor.Signer) *articleHandler {
 	return &articleHandler{
 		articleService: articleService,
+		cursors:        cursors,
 		validate:       validator.New(),
 	}
 }
@@ -128,6 +160,8 @@ func (h *articleHandler) CreateArticle() http.HandlerFunc {
 			req.Article.Title,
 			req.Article.Description,
 			req.Article.Body,
+			req.Article.Status,
+			req.Article.PublishAt,
 			req.Article.TagList,
 		)
 		if err != nil {
@@ -140,6 +174,12 @@ func (h *articleHandler) CreateArticle() http.HandlerFunc {
 					http.StatusUnprocessableEntity,
 					[]string{"Article with this title already exists"},
 				)
+			case errors.Is(err, service.ErrInvalidPublishAt):
+				response.RespondWithError(
+					w,
+					http.StatusUnprocessableEntity,
+					[]string{"PublishAt must be in the future"},
+				)
 			default:
 				response.RespondWithError(
 					w,
@@ -184,6 +224,9 @@ func (h *articleHandler) GetArticle() http.HandlerFunc {
 			userID,
 		)
 		if err != nil {
+			if redirectMovedArticle(w, r, err) {
+				return
+			}
 			switch {
 			case errors.Is(err, service.ErrArticleNotFound):
 				response.RespondWithError(w, http.StatusNotFound, []string{"Article not found"})
@@ -250,6 +293,11 @@ func (h *articleHandler) UpdateArticle() http.HandlerFunc {
 			req.Article.Title,
 			req.Article.Description,
 			req.Article.Body,
+			repository.TagChanges{
+				Replace: req.Article.TagList,
+				Add:     req.Article.AddTags,
+				Remove:  req.Article.RemoveTags,
+			},
 		)
 		if err != nil {
 			switch {
@@ -261,6 +309,12 @@ func (h *articleHandler) UpdateArticle() http.HandlerFunc {
 				)
 			case errors.Is(err, service.ErrArticleNotFound):
 				response.RespondWithError(w, http.StatusNotFound, []string{"Article not found"})
+			case errors.Is(err, service.ErrArticleAlreadyExists):
+				response.RespondWithError(
+					w,
+					http.StatusUnprocessableEntity,
+					[]string{"Article with this title already exists"},
+				)
 			default:
 				response.RespondWithError(
 					w,
@@ -349,6 +403,9 @@ func (h *articleHandler) FavoriteArticle() http.HandlerFunc {
 		)
 		// Handle errors
 		if err != nil {
+			if redirectMovedArticle(w, r, err) {
+				return
+			}
 			switch {
 			case errors.Is(err, service.ErrUserNotFound):
 				response.RespondWithError(w, http.StatusNotFound, []string{"User not found"})
@@ -400,6 +457,9 @@ func (h *articleHandler) UnfavoriteArticle() http.HandlerFunc {
 		)
 		// Handle errors
 		if err != nil {
+			if redirectMovedArticle(w, r, err) {
+				return
+			}
 			switch {
 			case errors.Is(err, service.ErrUserNotFound):
 				response.RespondWithError(w, http.StatusNotFound, []string{"User not found"})
@@ -427,6 +487,153 @@ func (h *articleHandler) UnfavoriteArticle() http.HandlerFunc {
 	}
 }
 
+// PublishArticle is a handler function for publishing an article
+func (h *articleHandler) PublishArticle() http.HandlerFunc {
+	return h.changeArticleStatus(h.articleService.PublishArticle)
+}
+
+// UnpublishArticle is a handler function for turning an article back into a draft
+func (h *articleHandler) UnpublishArticle() http.HandlerFunc {
+	return h.changeArticleStatus(h.articleService.UnpublishArticle)
+}
+
+// ArchiveArticle is a handler function for archiving an article
+func (h *articleHandler) ArchiveArticle() http.HandlerFunc {
+	return h.changeArticleStatus(h.articleService.ArchiveArticle)
+}
+
+// ScheduleArticle is a handler function for scheduling an article to be published later
+func (h *articleHandler) ScheduleArticle() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Set the content type to JSON
+		w.Header().Set("Content-Type", "application/json")
+
+		// Get user ID from context
+		userID, ok := middleware.GetUserIDFromContext(r.Context())
+		if !ok {
+			response.RespondWithError(w, http.StatusUnauthorized, []string{"Unauthorized"})
+			return
+		}
+
+		// Parse request body
+		var req ScheduleArticleRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusUnprocessableEntity,
+				[]string{"Invalid request body"},
+			)
+			return
+		}
+
+		// Validate request body
+		if err := h.validate.Struct(req); err != nil {
+			errors := validation.TranslateValidationErrors(err)
+			response.RespondWithError(w, http.StatusUnprocessableEntity, errors)
+			return
+		}
+
+		// Get slug from request path
+		slug := r.PathValue("slug")
+
+		// Call service to schedule article
+		article, err := h.articleService.ScheduleArticle(
+			r.Context(),
+			userID,
+			slug,
+			*req.Article.PublishAt,
+		)
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrInvalidPublishAt):
+				response.RespondWithError(
+					w,
+					http.StatusUnprocessableEntity,
+					[]string{"PublishAt must be in the future"},
+				)
+			case errors.Is(err, service.ErrArticleNotAuthorized):
+				response.RespondWithError(
+					w,
+					http.StatusForbidden,
+					[]string{"You are not the author of this article"},
+				)
+			case errors.Is(err, service.ErrArticleNotFound):
+				response.RespondWithError(w, http.StatusNotFound, []string{"Article not found"})
+			default:
+				response.RespondWithError(
+					w,
+					http.StatusInternalServerError,
+					[]string{"Internal server error"},
+				)
+			}
+			return
+		}
+
+		// Respond with article
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(ArticleResponse{Article: *article}); err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusInternalServerError,
+				[]string{"Internal server error"},
+			)
+		}
+	}
+}
+
+// changeArticleStatus returns a handler function that changes the status of an article
+// with the given service method
+func (h *articleHandler) changeArticleStatus(
+	change func(ctx context.Context, userID int64, slug string) (*service.Article, error),
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Set the content type to JSON
+		w.Header().Set("Content-Type", "application/json")
+
+		// Get user ID from context
+		userID, ok := middleware.GetUserIDFromContext(r.Context())
+		if !ok {
+			response.RespondWithError(w, http.StatusUnauthorized, []string{"Unauthorized"})
+			return
+		}
+
+		// Get slug from request path
+		slug := r.PathValue("slug")
+
+		// Call service to change the article status
+		article, err := change(r.Context(), userID, slug)
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrArticleNotAuthorized):
+				response.RespondWithError(
+					w,
+					http.StatusForbidden,
+					[]string{"You are not the author of this article"},
+				)
+			case errors.Is(err, service.ErrArticleNotFound):
+				response.RespondWithError(w, http.StatusNotFound, []string{"Article not found"})
+			default:
+				response.RespondWithError(
+					w,
+					http.StatusInternalServerError,
+					[]string{"Internal server error"},
+				)
+			}
+			return
+		}
+
+		// Respond with article
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(ArticleResponse{Article: *article}); err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusInternalServerError,
+				[]string{"Internal server error"},
+			)
+		}
+	}
+}
+
 // ListArticles is a handler function for listing articles with optional filters
 func (h *articleHandler) ListArticles() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -440,37 +647,186 @@ func (h *articleHandler) ListArticles() http.HandlerFunc {
 		}
 
 		// Parse query parameters
+		query := r.URL.Query()
 		filters := repository.ArticleFilters{
 			Limit:  20, // Default limit
 			Offset: 0,  // Default offset
 		}
 
-		// Parse tag filter
-		if tag := r.URL.Query().Get("tag"); tag != "" {
-			filters.Tag = &tag
+		// Parse search query
+		if q := strings.TrimSpace(query.Get("q")); q != "" {
+			filters.Query = &q
 		}
 
-		// Parse author filter
-		if author := r.URL.Query().Get("author"); author != "" {
-			filters.Author = &author
+		// Parse tag and author filters, which can be repeated
+		filters.Tags = query["tag"]
+		filters.ExcludeTags = query["excludeTag"]
+		filters.Authors = query["author"]
+		filters.ExcludeAuthors = query["excludeAuthor"]
+		for _, param := range []struct {
+			values  []string
+			message string
+		}{
+			{filters.Tags, "Tag cannot be empty"},
+			{filters.ExcludeTags, "Exclude tag cannot be empty"},
+			{filters.Authors, "Author cannot be empty"},
+			{filters.ExcludeAuthors, "Exclude author cannot be empty"},
+		} {
+			if slices.Contains(param.values, "") {
+				response.RespondWithError(
+					w,
+					http.StatusUnprocessableEntity,
+					[]string{param.message},
+				)
+				return
+			}
+		}
+
+		// Parse tag match
+		if tagMatch := query.Get("tagMatch"); tagMatch != "" {
+			switch tagMatch {
+			case repository.ArticleTagMatchAny, repository.ArticleTagMatchAll:
+				filters.TagMatch = tagMatch
+			default:
+				response.RespondWithError(
+					w,
+					http.StatusUnprocessableEntity,
+					[]string{"Tag match must be one of any or all"},
+				)
+				return
+			}
 		}
 
 		// Parse favorited filter
-		if favorited := r.URL.Query().Get("favorited"); favorited != "" {
+		if favorited := query.Get("favorited"); favorited != "" {
 			filters.Favorited = &favorited
 		}
 
+		// Parse status filter
+		if status := query.Get("status"); status != "" {
+			switch status {
+			case repository.ArticleStatusDraft,
+				repository.ArticleStatusScheduled,
+				repository.ArticleStatusPublished,
+				repository.ArticleStatusArchived:
+				filters.Status = &status
+			default:
+				response.RespondWithError(
+					w,
+					http.StatusUnprocessableEntity,
+					[]string{"Status must be one of draft, scheduled, published or archived"},
+				)
+				return
+			}
+		}
+
+		// Parse sort order
+		if sort := query.Get("sort"); sort != "" {
+			switch sort {
+			case repository.ArticleSortNewest,
+				repository.ArticleSortOldest,
+				repository.ArticleSortMostFavorited,
+				repository.ArticleSortMostCommented,
+				repository.ArticleSortRecentlyUpdated:
+				filters.Sort = sort
+			default:
+				response.RespondWithError(
+					w,
+					http.StatusUnprocessableEntity,
+					[]string{"Sort must be one of newest, oldest, most-favorited, most-commented or recently-updated"},
+				)
+				return
+			}
+		}
+
+		// Parse creation time bounds
+		for _, param := range []struct {
+			name    string
+			bound   **time.Time
+			message string
+		}{
+			{"createdAfter", &filters.CreatedAfter, "Created after must be an RFC3339 timestamp"},
+			{"createdBefore", &filters.CreatedBefore, "Created before must be an RFC3339 timestamp"},
+		} {
+			if value := query.Get(param.name); value != "" {
+				bound, err := time.Parse(time.RFC3339, value)
+				if err != nil {
+					response.RespondWithError(
+						w,
+						http.StatusUnprocessableEntity,
+						[]string{param.message},
+					)
+					return
+				}
+				*param.bound = &bound
+			}
+		}
+		if filters.CreatedAfter != nil && filters.CreatedBefore != nil &&
+			!filters.CreatedAfter.Before(*filters.CreatedBefore) {
+			response.RespondWithError(
+				w,
+				http.StatusUnprocessableEntity,
+				[]string{"Created after must be earlier than created before"},
+			)
+			return
+		}
+
 		// Parse limit parameter
-		if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
-			if limit, err := strconv.Atoi(limitStr); err == nil && limit > 0 {
-				filters.Limit = limit
+		if limitStr := query.Get("limit"); limitStr != "" {
+			limit, err := strconv.Atoi(limitStr)
+			if err != nil || limit <= 0 {
+				response.RespondWithError(
+					w,
+					http.StatusUnprocessableEntity,
+					[]string{"Limit must be a positive integer"},
+				)
+				return
 			}
+			filters.Limit = limit
 		}
 
 		// Parse offset parameter
-		if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
-			if offset, err := strconv.Atoi(offsetStr); err == nil && offset >= 0 {
-				filters.Offset = offset
+		if offsetStr := query.Get("offset"); offsetStr != "" {
+			offset, err := strconv.Atoi(offsetStr)
+			if err != nil || offset < 0 {
+				response.RespondWithError(
+					w,
+					http.StatusUnprocessableEntity,
+					[]string{"Offset must be a non-negative integer"},
+				)
+				return
+			}
+			filters.Offset = offset
+		}
+
+		// Parse cursor parameters
+		var ok bool
+		if filters.After, filters.Before, ok = parseCursors(w, r, h.cursors); !ok {
+			return
+		}
+		if filters.After != nil || filters.Before != nil {
+			switch {
+			case filters.Offset > 0:
+				response.RespondWithError(
+					w,
+					http.StatusUnprocessableEntity,
+					[]string{"Offset cannot be used with a cursor"},
+				)
+				return
+			case filters.Query != nil:
+				response.RespondWithError(
+					w,
+					http.StatusUnprocessableEntity,
+					[]string{"Cursors cannot be used with a search query"},
+				)
+				return
+			case !sortedByCreation(filters.Sort):
+				response.RespondWithError(
+					w,
+					http.StatusUnprocessableEntity,
+					[]string{"Cursors can only be used when sorting by newest or oldest"},
+				)
+				return
 			}
 		}
 
@@ -485,35 +841,21 @@ func (h *articleHandler) ListArticles() http.HandlerFunc {
 			return
 		}
 
-		// Convert repository articles to service articles
-		var articles []service.Article
-		for _, repoArticle := range result.Articles {
-			article := service.Article{
-				Slug:           repoArticle.Slug,
-				Title:          repoArticle.Title,
-				Description:    repoArticle.Description,
-				Body:           repoArticle.Body,
-				TagList:        repoArticle.TagList,
-				CreatedAt:      repoArticle.CreatedAt,
-				UpdatedAt:      repoArticle.UpdatedAt,
-				Favorited:      repoArticle.Favorited,
-				FavoritesCount: repoArticle.FavoritesCount,
-				Author: service.Profile{
-					Username:  repoArticle.Author.Username,
-					Bio:       repoArticle.Author.Bio,
-					Image:     repoArticle.Author.Image,
-					Following: repoArticle.Author.Following,
-				},
-			}
-			articles = append(articles, article)
+		resp := MultipleArticlesResponse{
+			Articles:      articlesFromRepository(result.Articles),
+			ArticlesCount: result.Count,
+		}
+
+		// Search results are ranked by relevance, and other sort orders than newest and
+		// oldest are not by creation time either, so they are only paginated by offset
+		if filters.Query == nil && sortedByCreation(filters.Sort) {
+			page := articleListPage(result, filters.After, filters.Before, filters.Offset)
+			resp.PrevCursor, resp.NextCursor = page.cursors(h.cursors)
 		}
 
 		// Respond with articles
 		w.WriteHeader(http.StatusOK)
-		if err := json.NewEncoder(w).Encode(MultipleArticlesResponse{
-			Articles:      articles,
-			ArticlesCount: result.Count,
-		}); err != nil {
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
 			response.RespondWithError(
 				w,
 				http.StatusInternalServerError,
@@ -537,25 +879,40 @@ func (h *articleHandler) GetArticlesFeed() http.HandlerFunc {
 		}
 
 		// Parse query parameters
-		limit := 20 // Default limit
-		offset := 0 // Default offset
+		filters := repository.FeedFilters{
+			Limit:  20, // Default limit
+			Offset: 0,  // Default offset
+		}
 
 		// Parse limit parameter
 		if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
 			if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
-				limit = l
+				filters.Limit = l
 			}
 		}
 
 		// Parse offset parameter
 		if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
 			if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
-				offset = o
+				filters.Offset = o
 			}
 		}
 
+		// Parse cursor parameters
+		if filters.After, filters.Before, ok = parseCursors(w, r, h.cursors); !ok {
+			return
+		}
+		if (filters.After != nil || filters.Before != nil) && filters.Offset > 0 {
+			response.RespondWithError(
+				w,
+				http.StatusUnprocessableEntity,
+				[]string{"Offset cannot be used with a cursor"},
+			)
+			return
+		}
+
 		// Call service to get articles feed
-		result, err := h.articleService.GetArticlesFeed(r.Context(), userID, limit, offset)
+		result, err := h.articleService.GetArticlesFeed(r.Context(), userID, filters)
 		if err != nil {
 			response.RespondWithError(
 				w,
@@ -565,35 +922,16 @@ func (h *articleHandler) GetArticlesFeed() http.HandlerFunc {
 			return
 		}
 
-		// Convert repository articles to service articles
-		var articles []service.Article
-		for _, repoArticle := range result.Articles {
-			article := service.Article{
-				Slug:           repoArticle.Slug,
-				Title:          repoArticle.Title,
-				Description:    repoArticle.Description,
-				Body:           repoArticle.Body,
-				TagList:        repoArticle.TagList,
-				CreatedAt:      repoArticle.CreatedAt,
-				UpdatedAt:      repoArticle.UpdatedAt,
-				Favorited:      repoArticle.Favorited,
-				FavoritesCount: repoArticle.FavoritesCount,
-				Author: service.Profile{
-					Username:  repoArticle.Author.Username,
-					Bio:       repoArticle.Author.Bio,
-					Image:     repoArticle.Author.Image,
-					Following: repoArticle.Author.Following,
-				},
-			}
-			articles = append(articles, article)
+		resp := MultipleArticlesResponse{
+			Articles:      articlesFromRepository(result.Articles),
+			ArticlesCount: result.Count,
 		}
+		page := articleListPage(result, filters.After, filters.Before, filters.Offset)
+		resp.PrevCursor, resp.NextCursor = page.cursors(h.cursors)
 
 		// Respond with articles
 		w.WriteHeader(http.StatusOK)
-		if err := json.NewEncoder(w).Encode(MultipleArticlesResponse{
-			Articles:      articles,
-			ArticlesCount: result.Count,
-		}); err != nil {
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
 			response.RespondWithError(
 				w,
 				http.StatusInternalServerError,
@@ -602,3 +940,86 @@ func (h *articleHandler) GetArticlesFeed() http.HandlerFunc {
 		}
 	}
 }
+
+// articlesFromRepository converts repository articles to service articles
+func articlesFromRepository(repoArticles []*repository.Article) []service.Article {
+	var articles []service.Article
+	for _, repoArticle := range repoArticles {
+		article := service.Article{
+			Slug:           repoArticle.Slug,
+			Title:          repoArticle.Title,
+			Description:    repoArticle.Description,
+			Body:           repoArticle.Body,
+			TagList:        repoArticle.TagList,
+			CreatedAt:      repoArticle.CreatedAt,
+			UpdatedAt:      repoArticle.UpdatedAt,
+			Favorited:      repoArticle.Favorited,
+			FavoritesCount: repoArticle.FavoritesCount,
+			Author: service.Profile{
+				Username:  repoArticle.Author.Username,
+				Bio:       repoArticle.Author.Bio,
+				Image:     repoArticle.Author.Image,
+				Following: repoArticle.Author.Following,
+			},
+			Status:      repoArticle.Status,
+			PublishAt:   repoArticle.PublishAt,
+			PublishedAt: repoArticle.PublishedAt,
+		}
+		if repoArticle.Highlight != nil {
+			article.Highlight = &service.ArticleHighlight{
+				Title:       repoArticle.Highlight.Title,
+				Description: repoArticle.Highlight.Description,
+				Body:        repoArticle.Highlight.Body,
+			}
+		}
+		articles = append(articles, article)
+	}
+	return articles
+}
+
+// sortedByCreation reports whether articles listed in the sort order are ordered by their
+// creation time, and can be paginated with cursors
+func sortedByCreation(sort string) bool {
+	return sort == "" || sort == repository.ArticleSortNewest || sort == repository.ArticleSortOldest
+}
+
+// articleListPage returns the listPage of a page of articles requested after or before a
+// cursor or at an offset
+func articleListPage(
+	result *repository.ArticleListResult,
+	after, before *repository.Cursor,
+	offset int,
+) listPage {
+	page := listPage{after: after, before: before, offset: offset, hasMore: result.HasMore}
+	if n := len(result.Articles); n > 0 {
+		first, last := result.Articles[0], result.Articles[n-1]
+		page.first = &repository.Cursor{CreatedAt: first.CreatedAt, ID: first.ID}
+		page.last = &repository.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+	return page
+}
+
+// redirectMovedArticle redirects a request for an article by one of its previous slugs
+// to the same path under the current slug, reporting whether the error was handled.
+// Requests other than GET and HEAD are redirected with 308, so that clients repeat
+// them with the same method and body.
+func redirectMovedArticle(w http.ResponseWriter, r *http.Request, err error) bool {
+	var moved *service.ArticleMovedError
+	if !errors.As(err, &moved) {
+		return false
+	}
+
+	slug := r.PathValue("slug")
+	location := *r.URL
+	location.Path = strings.Replace(r.URL.Path, "/articles/"+slug, "/articles/"+moved.Slug, 1)
+	location.RawPath = ""
+
+	status := http.StatusMovedPermanently
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		status = http.StatusPermanentRedirect
+	}
+
+	w.Header().Set("Location", location.RequestURI())
+	response.RespondWithError(w, status, []string{"Article has moved"})
+	return true
+}
@@ -7,35 +7,67 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/Nilesh2000/conduit/internal/jwtkeys"
 	"github.com/Nilesh2000/conduit/internal/middleware"
 	"github.com/Nilesh2000/conduit/internal/repository"
 	"github.com/Nilesh2000/conduit/internal/response"
 	"github.com/Nilesh2000/conduit/internal/service"
+
+	"github.com/golang-jwt/jwt/v5"
 )
 
+const testJWTSecret = "this-is-a-32-char-long-secret-key-123"
+
+// signTestToken signs a token for the given user ID with the test secret
+func signTestToken(t *testing.T, userID string) string {
+	t.Helper()
+
+	now := time.Now()
+	claims := jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		ID:        "test-token-id",
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now.Add(-time.Minute)),
+		Subject:   userID,
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).
+		SignedString([]byte(testJWTSecret))
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+	return token
+}
+
 // MockArticleService is a mock implementation of the ArticleService interface
 type MockArticleService struct {
-	createArticleFunc     func(ctx context.Context, userID int64, title, description, body string, tagList []string) (*service.Article, error)
+	createArticleFunc     func(ctx context.Context, userID int64, title, description, body, status string, publishAt *time.Time, tagList []string) (*service.Article, error)
 	getArticleFunc        func(ctx context.Context, slug string, currentUserID *int64) (*service.Article, error)
-	updateArticleFunc     func(ctx context.Context, userID int64, slug string, title, description, body *string) (*service.Article, error)
+	updateArticleFunc     func(ctx context.Context, userID int64, slug string, title, description, body *string, tags repository.TagChanges) (*service.Article, error)
 	deleteArticleFunc     func(ctx context.Context, userID int64, slug string) error
 	favoriteArticleFunc   func(ctx context.Context, userID int64, slug string) (*service.Article, error)
 	unfavoriteArticleFunc func(ctx context.Context, userID int64, slug string) (*service.Article, error)
 	listArticlesFunc      func(ctx context.Context, filters repository.ArticleFilters, currentUserID *int64) (*repository.ArticleListResult, error)
-	getArticlesFeedFunc   func(ctx context.Context, userID int64, limit, offset int) (*repository.ArticleListResult, error)
+	getArticlesFeedFunc   func(ctx context.Context, userID int64, filters repository.FeedFilters) (*repository.ArticleListResult, error)
+	publishArticleFunc    func(ctx context.Context, userID int64, slug string) (*service.Article, error)
+	unpublishArticleFunc  func(ctx context.Context, userID int64, slug string) (*service.Article, error)
+	archiveArticleFunc    func(ctx context.Context, userID int64, slug string) (*service.Article, error)
+	scheduleArticleFunc   func(ctx context.Context, userID int64, slug string, publishAt time.Time) (*service.Article, error)
 }
 
 // CreateArticle is a mock implementation of the CreateArticle method
 func (m *MockArticleService) CreateArticle(
 	ctx context.Context,
 	userID int64,
-	title, description, body string,
+	title, description, body, status string,
+	publishAt *time.Time,
 	tagList []string,
 ) (*service.Article, error) {
-	return m.createArticleFunc(ctx, userID, title, description, body, tagList)
+	return m.createArticleFunc(ctx, userID, title, description, body, status, publishAt, tagList)
 }
 
 // GetArticle is a mock implementation of the GetArticle method
@@ -53,8 +85,9 @@ func (m *MockArticleService) UpdateArticle(
 	userID int64,
 	slug string,
 	title, description, body *string,
+	tags repository.TagChanges,
 ) (*service.Article, error) {
-	return m.updateArticleFunc(ctx, userID, slug, title, description, body)
+	return m.updateArticleFunc(ctx, userID, slug, title, description, body, tags)
 }
 
 // DeleteArticle is a mock implementation of the DeleteArticle method
@@ -97,9 +130,46 @@ func (m *MockArticleService) ListArticles(
 func (m *MockArticleService) GetArticlesFeed(
 	ctx context.Context,
 	userID int64,
-	limit, offset int,
+	filters repository.FeedFilters,
 ) (*repository.ArticleListResult, error) {
-	return m.getArticlesFeedFunc(ctx, userID, limit, offset)
+	return m.getArticlesFeedFunc(ctx, userID, filters)
+}
+
+// PublishArticle is a mock implementation of the PublishArticle method
+func (m *MockArticleService) PublishArticle(
+	ctx context.Context,
+	userID int64,
+	slug string,
+) (*service.Article, error) {
+	return m.publishArticleFunc(ctx, userID, slug)
+}
+
+// UnpublishArticle is a mock implementation of the UnpublishArticle method
+func (m *MockArticleService) UnpublishArticle(
+	ctx context.Context,
+	userID int64,
+	slug string,
+) (*service.Article, error) {
+	return m.unpublishArticleFunc(ctx, userID, slug)
+}
+
+// ArchiveArticle is a mock implementation of the ArchiveArticle method
+func (m *MockArticleService) ArchiveArticle(
+	ctx context.Context,
+	userID int64,
+	slug string,
+) (*service.Article, error) {
+	return m.archiveArticleFunc(ctx, userID, slug)
+}
+
+// ScheduleArticle is a mock implementation of the ScheduleArticle method
+func (m *MockArticleService) ScheduleArticle(
+	ctx context.Context,
+	userID int64,
+	slug string,
+	publishAt time.Time,
+) (*service.Article, error) {
+	return m.scheduleArticleFunc(ctx, userID, slug, publishAt)
 }
 
 // TestArticleHandler_CreateArticle tests the CreateArticle method of the ArticleHandler
@@ -118,10 +188,12 @@ func TestArticleHandler_CreateArticle(t *testing.T) {
 			name: "Successful article creation",
 			requestBody: CreateArticleRequest{
 				Article: struct {
-					Title       string   `json:"title" validate:"required"`
-					Description string   `json:"description" validate:"required"`
-					Body        string   `json:"body" validate:"required"`
-					TagList     []string `json:"tagList,omitempty"`
+					Title       string     `json:"title" validate:"required"`
+					Description string     `json:"description" validate:"required"`
+					Body        string     `json:"body" validate:"required,max=100000"`
+					TagList     []string   `json:"tagList,omitempty"`
+					Status      string     `json:"status" validate:"omitempty,oneof=draft published,excluded_with=PublishAt"`
+					PublishAt   *time.Time `json:"publishAt"`
 				}{
 					Title:       "Test Article",
 					Description: "Test Description",
@@ -139,7 +211,7 @@ func TestArticleHandler_CreateArticle(t *testing.T) {
 			},
 			setupMock: func() *MockArticleService {
 				mockService := &MockArticleService{
-					createArticleFunc: func(ctx context.Context, userID int64, title, description, body string, tagList []string) (*service.Article, error) {
+					createArticleFunc: func(ctx context.Context, userID int64, title, description, body, status string, publishAt *time.Time, tagList []string) (*service.Article, error) {
 						if userID != 1 {
 							t.Errorf("Expected userID 1, got %d", userID)
 						}
@@ -203,10 +275,12 @@ func TestArticleHandler_CreateArticle(t *testing.T) {
 			name: "Unauthenticated request",
 			requestBody: CreateArticleRequest{
 				Article: struct {
-					Title       string   `json:"title" validate:"required"`
-					Description string   `json:"description" validate:"required"`
-					Body        string   `json:"body" validate:"required"`
-					TagList     []string `json:"tagList,omitempty"`
+					Title       string     `json:"title" validate:"required"`
+					Description string     `json:"description" validate:"required"`
+					Body        string     `json:"body" validate:"required,max=100000"`
+					TagList     []string   `json:"tagList,omitempty"`
+					Status      string     `json:"status" validate:"omitempty,oneof=draft published,excluded_with=PublishAt"`
+					PublishAt   *time.Time `json:"publishAt"`
 				}{
 					Title:       "Test Article",
 					Description: "Test Description",
@@ -220,7 +294,7 @@ func TestArticleHandler_CreateArticle(t *testing.T) {
 			},
 			setupMock: func() *MockArticleService {
 				mockService := &MockArticleService{
-					createArticleFunc: func(ctx context.Context, userID int64, title, description, body string, tagList []string) (*service.Article, error) {
+					createArticleFunc: func(ctx context.Context, userID int64, title, description, body, status string, publishAt *time.Time, tagList []string) (*service.Article, error) {
 						t.Errorf("CreateArticle should not be called for unauthenticated request")
 						return nil, nil
 					},
@@ -251,7 +325,7 @@ func TestArticleHandler_CreateArticle(t *testing.T) {
 			},
 			setupMock: func() *MockArticleService {
 				mockService := &MockArticleService{
-					createArticleFunc: func(ctx context.Context, userID int64, title, description, body string, tagList []string) (*service.Article, error) {
+					createArticleFunc: func(ctx context.Context, userID int64, title, description, body, status string, publishAt *time.Time, tagList []string) (*service.Article, error) {
 						t.Errorf("CreateArticle should not be called for invalid JSON")
 						return nil, nil
 					},
@@ -269,10 +343,12 @@ func TestArticleHandler_CreateArticle(t *testing.T) {
 			name: "Missing required fields",
 			requestBody: CreateArticleRequest{
 				Article: struct {
-					Title       string   `json:"title" validate:"required"`
-					Description string   `json:"description" validate:"required"`
-					Body        string   `json:"body" validate:"required"`
-					TagList     []string `json:"tagList,omitempty"`
+					Title       string     `json:"title" validate:"required"`
+					Description string     `json:"description" validate:"required"`
+					Body        string     `json:"body" validate:"required,max=100000"`
+					TagList     []string   `json:"tagList,omitempty"`
+					Status      string     `json:"status" validate:"omitempty,oneof=draft published,excluded_with=PublishAt"`
+					PublishAt   *time.Time `json:"publishAt"`
 				}{
 					Title: "Test Article",
 				},
@@ -287,7 +363,7 @@ func TestArticleHandler_CreateArticle(t *testing.T) {
 			},
 			setupMock: func() *MockArticleService {
 				mockService := &MockArticleService{
-					createArticleFunc: func(ctx context.Context, userID int64, title, description, body string, tagList []string) (*service.Article, error) {
+					createArticleFunc: func(ctx context.Context, userID int64, title, description, body, status string, publishAt *time.Time, tagList []string) (*service.Article, error) {
 						t.Errorf("CreateArticle should not be called for missing required fields")
 						return nil, nil
 					},
@@ -301,14 +377,38 @@ func TestArticleHandler_CreateArticle(t *testing.T) {
 				}{Body: []string{"Description is required", "Body is required"}},
 			},
 		},
+		{
+			name:        "Body too long",
+			requestBody: `{"article":{"title":"Test Article","description":"Test Description","body":"` + strings.Repeat("a", 100001) + `"}}`,
+			setupAuth: func(r *http.Request) *http.Request {
+				ctx := context.WithValue(r.Context(), middleware.UserIDContextKey, int64(1))
+				return r.WithContext(ctx)
+			},
+			setupMock: func() *MockArticleService {
+				return &MockArticleService{
+					createArticleFunc: func(ctx context.Context, userID int64, title, description, body, status string, publishAt *time.Time, tagList []string) (*service.Article, error) {
+						t.Errorf("CreateArticle should not be called for a body that is too long")
+						return nil, nil
+					},
+				}
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Body must be at most 100000 characters long"}},
+			},
+		},
 		{
 			name: "Article already exists",
 			requestBody: CreateArticleRequest{
 				Article: struct {
-					Title       string   `json:"title" validate:"required"`
-					Description string   `json:"description" validate:"required"`
-					Body        string   `json:"body" validate:"required"`
-					TagList     []string `json:"tagList,omitempty"`
+					Title       string     `json:"title" validate:"required"`
+					Description string     `json:"description" validate:"required"`
+					Body        string     `json:"body" validate:"required,max=100000"`
+					TagList     []string   `json:"tagList,omitempty"`
+					Status      string     `json:"status" validate:"omitempty,oneof=draft published,excluded_with=PublishAt"`
+					PublishAt   *time.Time `json:"publishAt"`
 				}{
 					Title:       "Existing Article",
 					Description: "Test Description",
@@ -326,7 +426,7 @@ func TestArticleHandler_CreateArticle(t *testing.T) {
 			},
 			setupMock: func() *MockArticleService {
 				mockService := &MockArticleService{
-					createArticleFunc: func(ctx context.Context, userID int64, title, description, body string, tagList []string) (*service.Article, error) {
+					createArticleFunc: func(ctx context.Context, userID int64, title, description, body, status string, publishAt *time.Time, tagList []string) (*service.Article, error) {
 						return nil, service.ErrArticleAlreadyExists
 					},
 				}
@@ -343,10 +443,12 @@ func TestArticleHandler_CreateArticle(t *testing.T) {
 			name: "User not found",
 			requestBody: CreateArticleRequest{
 				Article: struct {
-					Title       string   `json:"title" validate:"required"`
-					Description string   `json:"description" validate:"required"`
-					Body        string   `json:"body" validate:"required"`
-					TagList     []string `json:"tagList,omitempty"`
+					Title       string     `json:"title" validate:"required"`
+					Description string     `json:"description" validate:"required"`
+					Body        string     `json:"body" validate:"required,max=100000"`
+					TagList     []string   `json:"tagList,omitempty"`
+					Status      string     `json:"status" validate:"omitempty,oneof=draft published,excluded_with=PublishAt"`
+					PublishAt   *time.Time `json:"publishAt"`
 				}{
 					Title:       "Test Article",
 					Description: "Test Description",
@@ -363,7 +465,7 @@ func TestArticleHandler_CreateArticle(t *testing.T) {
 			},
 			setupMock: func() *MockArticleService {
 				mockService := &MockArticleService{
-					createArticleFunc: func(ctx context.Context, userID int64, title, description, body string, tagList []string) (*service.Article, error) {
+					createArticleFunc: func(ctx context.Context, userID int64, title, description, body, status string, publishAt *time.Time, tagList []string) (*service.Article, error) {
 						return nil, service.ErrUserNotFound
 					},
 				}
@@ -380,10 +482,12 @@ func TestArticleHandler_CreateArticle(t *testing.T) {
 			name: "Internal server error",
 			requestBody: CreateArticleRequest{
 				Article: struct {
-					Title       string   `json:"title" validate:"required"`
-					Description string   `json:"description" validate:"required"`
-					Body        string   `json:"body" validate:"required"`
-					TagList     []string `json:"tagList,omitempty"`
+					Title       string     `json:"title" validate:"required"`
+					Description string     `json:"description" validate:"required"`
+					Body        string     `json:"body" validate:"required,max=100000"`
+					TagList     []string   `json:"tagList,omitempty"`
+					Status      string     `json:"status" validate:"omitempty,oneof=draft published,excluded_with=PublishAt"`
+					PublishAt   *time.Time `json:"publishAt"`
 				}{
 					Title:       "Test Article",
 					Description: "Test Description",
@@ -401,7 +505,7 @@ func TestArticleHandler_CreateArticle(t *testing.T) {
 			},
 			setupMock: func() *MockArticleService {
 				mockService := &MockArticleService{
-					createArticleFunc: func(ctx context.Context, userID int64, title, description, body string, tagList []string) (*service.Article, error) {
+					createArticleFunc: func(ctx context.Context, userID int64, title, description, body, status string, publishAt *time.Time, tagList []string) (*service.Article, error) {
 						return nil, service.ErrInternalServer
 					},
 				}
@@ -425,7 +529,7 @@ func TestArticleHandler_CreateArticle(t *testing.T) {
 			mockService := tt.setupMock()
 
 			// Create Handler
-			handler := NewArticleHandler(mockService)
+			handler := NewArticleHandler(mockService, testCursors)
 
 			var bodyBytes []byte
 
@@ -602,7 +706,7 @@ func TestArticleHandler_GetArticle(t *testing.T) {
 			mockService := tt.setupMock()
 
 			// Create Handler
-			handler := NewArticleHandler(mockService)
+			handler := NewArticleHandler(mockService, testCursors)
 
 			// Create Request
 			req := httptest.NewRequest(
@@ -650,6 +754,102 @@ func TestArticleHandler_GetArticle(t *testing.T) {
 	}
 }
 
+// TestArticleHandler_GetArticle_OptionalAuth tests that GetArticle personalises the
+// response when served behind the OptionalAuth middleware
+func TestArticleHandler_GetArticle_OptionalAuth(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name              string
+		authHeader        string
+		expectedStatus    int
+		expectedFavorited bool
+		expectedFollowing bool
+	}{
+		{
+			name:              "Authenticated request is personalised",
+			authHeader:        "Token " + signTestToken(t, "1"),
+			expectedStatus:    http.StatusOK,
+			expectedFavorited: true,
+			expectedFollowing: true,
+		},
+		{
+			name:              "Anonymous request is not personalised",
+			authHeader:        "",
+			expectedStatus:    http.StatusOK,
+			expectedFavorited: false,
+			expectedFollowing: false,
+		},
+		{
+			name:           "Invalid token is rejected",
+			authHeader:     "Token invalid.token.here",
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup Mock that personalises the article for user 1
+			mockService := &MockArticleService{
+				getArticleFunc: func(ctx context.Context, slug string, currentUserID *int64) (*service.Article, error) {
+					personalised := currentUserID != nil && *currentUserID == 1
+					return &service.Article{
+						Slug:      slug,
+						Favorited: personalised,
+						Author: service.Profile{
+							Username:  "testuser",
+							Following: personalised,
+						},
+					}, nil
+				},
+			}
+
+			// Create Handler behind the optional auth middleware
+			handler := middleware.OptionalAuth(jwtkeys.NewHMACKeySet([]byte(testJWTSecret)), nil)(
+				NewArticleHandler(mockService, testCursors).GetArticle(),
+			)
+
+			// Create Request
+			req := httptest.NewRequest(http.MethodGet, "/api/articles/test-article", nil)
+			req.SetPathValue("slug", "test-article")
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+
+			// Serve Request
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			// Check Status Code
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Fatalf("Status code: got %v, want %v", status, tt.expectedStatus)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			// Check personalised flags
+			var resp ArticleResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+			if resp.Article.Favorited != tt.expectedFavorited {
+				t.Errorf("Favorited: got %v, want %v", resp.Article.Favorited, tt.expectedFavorited)
+			}
+			if resp.Article.Author.Following != tt.expectedFollowing {
+				t.Errorf(
+					"Following: got %v, want %v",
+					resp.Article.Author.Following,
+					tt.expectedFollowing,
+				)
+			}
+		})
+	}
+}
+
 // TestArticleHandler_UpdateArticle tests the UpdateArticle method of the ArticleHandler
 func TestArticleHandler_UpdateArticle(t *testing.T) {
 	t.Parallel()
@@ -672,9 +872,12 @@ func TestArticleHandler_UpdateArticle(t *testing.T) {
 			slug: "test-article",
 			requestBody: UpdateArticleRequest{
 				Article: struct {
-					Title       *string `json:"title" validate:"omitempty"`
-					Description *string `json:"description" validate:"omitempty"`
-					Body        *string `json:"body" validate:"omitempty"`
+					Title       *string  `json:"title" validate:"omitempty"`
+					Description *string  `json:"description" validate:"omitempty"`
+					Body        *string  `json:"body" validate:"omitempty,max=100000"`
+					TagList     []string `json:"tagList" validate:"excluded_with=AddTags RemoveTags"`
+					AddTags     []string `json:"addTags"`
+					RemoveTags  []string `json:"removeTags"`
 				}{
 					Title:       strPtr("Updated Title"),
 					Description: strPtr("Updated Description"),
@@ -690,7 +893,7 @@ func TestArticleHandler_UpdateArticle(t *testing.T) {
 			},
 			setupMock: func() *MockArticleService {
 				mockService := &MockArticleService{
-					updateArticleFunc: func(ctx context.Context, userID int64, slug string, title, description, body *string) (*service.Article, error) {
+					updateArticleFunc: func(ctx context.Context, userID int64, slug string, title, description, body *string, tags repository.TagChanges) (*service.Article, error) {
 						if userID != 1 {
 							t.Errorf("Expected userID 1, got %d", userID)
 						}
@@ -753,14 +956,87 @@ func TestArticleHandler_UpdateArticle(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:        "Tags added and removed",
+			slug:        "test-article",
+			requestBody: `{"article":{"addTags":["go"],"removeTags":["sql"]}}`,
+			setupAuth: func(r *http.Request) *http.Request {
+				ctx := context.WithValue(r.Context(), middleware.UserIDContextKey, int64(1))
+				return r.WithContext(ctx)
+			},
+			setupMock: func() *MockArticleService {
+				return &MockArticleService{
+					updateArticleFunc: func(ctx context.Context, userID int64, slug string, title, description, body *string, tags repository.TagChanges) (*service.Article, error) {
+						expected := repository.TagChanges{Add: []string{"go"}, Remove: []string{"sql"}}
+						if !reflect.DeepEqual(tags, expected) {
+							t.Errorf("Expected tags %+v, got %+v", expected, tags)
+						}
+						return &service.Article{Slug: slug, TagList: []string{"go"}}, nil
+					},
+				}
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: ArticleResponse{
+				Article: service.Article{Slug: "test-article", TagList: []string{"go"}},
+			},
+		},
+		{
+			name:        "Tag list combined with added tags",
+			slug:        "test-article",
+			requestBody: `{"article":{"tagList":["go"],"addTags":["sql"]}}`,
+			setupAuth: func(r *http.Request) *http.Request {
+				ctx := context.WithValue(r.Context(), middleware.UserIDContextKey, int64(1))
+				return r.WithContext(ctx)
+			},
+			setupMock: func() *MockArticleService {
+				return &MockArticleService{
+					updateArticleFunc: func(ctx context.Context, userID int64, slug string, title, description, body *string, tags repository.TagChanges) (*service.Article, error) {
+						t.Errorf("UpdateArticle should not be called when the tag list is combined with added tags")
+						return nil, nil
+					},
+				}
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"TagList cannot be combined with AddTags or RemoveTags"}},
+			},
+		},
+		{
+			name:        "Body too long",
+			slug:        "test-article",
+			requestBody: `{"article":{"body":"` + strings.Repeat("a", 100001) + `"}}`,
+			setupAuth: func(r *http.Request) *http.Request {
+				ctx := context.WithValue(r.Context(), middleware.UserIDContextKey, int64(1))
+				return r.WithContext(ctx)
+			},
+			setupMock: func() *MockArticleService {
+				return &MockArticleService{
+					updateArticleFunc: func(ctx context.Context, userID int64, slug string, title, description, body *string, tags repository.TagChanges) (*service.Article, error) {
+						t.Errorf("UpdateArticle should not be called for a body that is too long")
+						return nil, nil
+					},
+				}
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Body must be at most 100000 characters long"}},
+			},
+		},
 		{
 			name: "Unauthenticated request",
 			slug: "test-article",
 			requestBody: UpdateArticleRequest{
 				Article: struct {
-					Title       *string `json:"title" validate:"omitempty"`
-					Description *string `json:"description" validate:"omitempty"`
-					Body        *string `json:"body" validate:"omitempty"`
+					Title       *string  `json:"title" validate:"omitempty"`
+					Description *string  `json:"description" validate:"omitempty"`
+					Body        *string  `json:"body" validate:"omitempty,max=100000"`
+					TagList     []string `json:"tagList" validate:"excluded_with=AddTags RemoveTags"`
+					AddTags     []string `json:"addTags"`
+					RemoveTags  []string `json:"removeTags"`
 				}{
 					Title:       strPtr("Updated Title"),
 					Description: strPtr("Updated Description"),
@@ -772,7 +1048,7 @@ func TestArticleHandler_UpdateArticle(t *testing.T) {
 			},
 			setupMock: func() *MockArticleService {
 				mockService := &MockArticleService{
-					updateArticleFunc: func(ctx context.Context, userID int64, slug string, title, description, body *string) (*service.Article, error) {
+					updateArticleFunc: func(ctx context.Context, userID int64, slug string, title, description, body *string, tags repository.TagChanges) (*service.Article, error) {
 						return nil, nil
 					},
 				}
@@ -803,7 +1079,7 @@ func TestArticleHandler_UpdateArticle(t *testing.T) {
 			},
 			setupMock: func() *MockArticleService {
 				mockService := &MockArticleService{
-					updateArticleFunc: func(ctx context.Context, userID int64, slug string, title, description, body *string) (*service.Article, error) {
+					updateArticleFunc: func(ctx context.Context, userID int64, slug string, title, description, body *string, tags repository.TagChanges) (*service.Article, error) {
 						return nil, nil
 					},
 				}
@@ -821,9 +1097,12 @@ func TestArticleHandler_UpdateArticle(t *testing.T) {
 			slug: "test-article",
 			requestBody: UpdateArticleRequest{
 				Article: struct {
-					Title       *string `json:"title" validate:"omitempty"`
-					Description *string `json:"description" validate:"omitempty"`
-					Body        *string `json:"body" validate:"omitempty"`
+					Title       *string  `json:"title" validate:"omitempty"`
+					Description *string  `json:"description" validate:"omitempty"`
+					Body        *string  `json:"body" validate:"omitempty,max=100000"`
+					TagList     []string `json:"tagList" validate:"excluded_with=AddTags RemoveTags"`
+					AddTags     []string `json:"addTags"`
+					RemoveTags  []string `json:"removeTags"`
 				}{
 					Title:       strPtr("Updated Title"),
 					Description: strPtr("Updated Description"),
@@ -839,7 +1118,7 @@ func TestArticleHandler_UpdateArticle(t *testing.T) {
 			},
 			setupMock: func() *MockArticleService {
 				mockService := &MockArticleService{
-					updateArticleFunc: func(ctx context.Context, userID int64, slug string, title, description, body *string) (*service.Article, error) {
+					updateArticleFunc: func(ctx context.Context, userID int64, slug string, title, description, body *string, tags repository.TagChanges) (*service.Article, error) {
 						return nil, service.ErrArticleNotAuthorized
 					},
 				}
@@ -857,9 +1136,12 @@ func TestArticleHandler_UpdateArticle(t *testing.T) {
 			slug: "non-existent-article",
 			requestBody: UpdateArticleRequest{
 				Article: struct {
-					Title       *string `json:"title" validate:"omitempty"`
-					Description *string `json:"description" validate:"omitempty"`
-					Body        *string `json:"body" validate:"omitempty"`
+					Title       *string  `json:"title" validate:"omitempty"`
+					Description *string  `json:"description" validate:"omitempty"`
+					Body        *string  `json:"body" validate:"omitempty,max=100000"`
+					TagList     []string `json:"tagList" validate:"excluded_with=AddTags RemoveTags"`
+					AddTags     []string `json:"addTags"`
+					RemoveTags  []string `json:"removeTags"`
 				}{
 					Title:       strPtr("Updated Title"),
 					Description: strPtr("Updated Description"),
@@ -875,7 +1157,7 @@ func TestArticleHandler_UpdateArticle(t *testing.T) {
 			},
 			setupMock: func() *MockArticleService {
 				mockService := &MockArticleService{
-					updateArticleFunc: func(ctx context.Context, userID int64, slug string, title, description, body *string) (*service.Article, error) {
+					updateArticleFunc: func(ctx context.Context, userID int64, slug string, title, description, body *string, tags repository.TagChanges) (*service.Article, error) {
 						return nil, service.ErrArticleNotFound
 					},
 				}
@@ -893,9 +1175,12 @@ func TestArticleHandler_UpdateArticle(t *testing.T) {
 			slug: "test-article",
 			requestBody: UpdateArticleRequest{
 				Article: struct {
-					Title       *string `json:"title" validate:"omitempty"`
-					Description *string `json:"description" validate:"omitempty"`
-					Body        *string `json:"body" validate:"omitempty"`
+					Title       *string  `json:"title" validate:"omitempty"`
+					Description *string  `json:"description" validate:"omitempty"`
+					Body        *string  `json:"body" validate:"omitempty,max=100000"`
+					TagList     []string `json:"tagList" validate:"excluded_with=AddTags RemoveTags"`
+					AddTags     []string `json:"addTags"`
+					RemoveTags  []string `json:"removeTags"`
 				}{
 					Title:       strPtr("Updated Title"),
 					Description: strPtr("Updated Description"),
@@ -911,7 +1196,7 @@ func TestArticleHandler_UpdateArticle(t *testing.T) {
 			},
 			setupMock: func() *MockArticleService {
 				mockService := &MockArticleService{
-					updateArticleFunc: func(ctx context.Context, userID int64, slug string, title, description, body *string) (*service.Article, error) {
+					updateArticleFunc: func(ctx context.Context, userID int64, slug string, title, description, body *string, tags repository.TagChanges) (*service.Article, error) {
 						return nil, service.ErrInternalServer
 					},
 				}
@@ -935,7 +1220,7 @@ func TestArticleHandler_UpdateArticle(t *testing.T) {
 			mockService := tt.setupMock()
 
 			// Create Handler
-			handler := NewArticleHandler(mockService)
+			handler := NewArticleHandler(mockService, testCursors)
 
 			var bodyBytes []byte
 
@@ -1141,7 +1426,7 @@ func TestArticleHandler_DeleteArticle(t *testing.T) {
 			mockService := tt.setupMock()
 
 			// Create Handler
-			handler := NewArticleHandler(mockService)
+			handler := NewArticleHandler(mockService, testCursors)
 
 			// Create Request
 			req := httptest.NewRequest(
@@ -1344,7 +1629,7 @@ func TestArticleHandler_FavoriteArticle(t *testing.T) {
 			mockService := tt.setupMock()
 
 			// Create Handler
-			handler := NewArticleHandler(mockService)
+			handler := NewArticleHandler(mockService, testCursors)
 
 			// Create Request
 			req := httptest.NewRequest(
@@ -1556,7 +1841,7 @@ func TestArticleHandler_UnfavoriteArticle(t *testing.T) {
 			mockService := tt.setupMock()
 
 			// Create Handler
-			handler := NewArticleHandler(mockService)
+			handler := NewArticleHandler(mockService, testCursors)
 
 			// Create Request
 			req := httptest.NewRequest(
@@ -1613,6 +1898,8 @@ func TestArticleHandler_UnfavoriteArticle(t *testing.T) {
 func TestArticleHandler_ListArticles(t *testing.T) {
 	t.Parallel()
 
+	cursorTime := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
 	tests := []struct {
 		name             string
 		queryParams      string
@@ -1730,8 +2017,9 @@ func TestArticleHandler_ListArticles(t *testing.T) {
 			},
 		},
 		{
-			name:        "Successfully list articles with filters",
-			queryParams: "?tag=test&author=testuser&limit=10&offset=5",
+			name: "Successfully list articles with filters",
+			queryParams: "?tag=test&tag=go&tagMatch=all&excludeTag=draft&author=testuser&author=other&excludeAuthor=spammer" +
+				"&createdAfter=2024-01-01T00:00:00Z&createdBefore=2024-02-01T00:00:00%2B02:00&limit=10&offset=5",
 			setupAuth: func(r *http.Request) *http.Request {
 				// Optional authentication
 				return r
@@ -1739,11 +2027,23 @@ func TestArticleHandler_ListArticles(t *testing.T) {
 			setupMock: func() *MockArticleService {
 				mockService := &MockArticleService{
 					listArticlesFunc: func(ctx context.Context, filters repository.ArticleFilters, currentUserID *int64) (*repository.ArticleListResult, error) {
-						if *filters.Tag != "test" {
-							t.Errorf("Expected tag 'test', got %s", *filters.Tag)
+						if !reflect.DeepEqual(filters.Tags, []string{"test", "go"}) || filters.TagMatch != repository.ArticleTagMatchAll {
+							t.Errorf("Expected all of the tags [test go], got %s of %v", filters.TagMatch, filters.Tags)
+						}
+						if !reflect.DeepEqual(filters.ExcludeTags, []string{"draft"}) {
+							t.Errorf("Expected excluded tags [draft], got %v", filters.ExcludeTags)
+						}
+						if !reflect.DeepEqual(filters.Authors, []string{"testuser", "other"}) {
+							t.Errorf("Expected authors [testuser other], got %v", filters.Authors)
 						}
-						if *filters.Author != "testuser" {
-							t.Errorf("Expected author 'testuser', got %s", *filters.Author)
+						if !reflect.DeepEqual(filters.ExcludeAuthors, []string{"spammer"}) {
+							t.Errorf("Expected excluded authors [spammer], got %v", filters.ExcludeAuthors)
+						}
+						if !filters.CreatedAfter.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+							t.Errorf("Expected created after 2024-01-01, got %v", filters.CreatedAfter)
+						}
+						if !filters.CreatedBefore.Equal(time.Date(2024, 1, 31, 22, 0, 0, 0, time.UTC)) {
+							t.Errorf("Expected created before 2024-01-31T22:00:00Z, got %v", filters.CreatedBefore)
 						}
 						if filters.Limit != 10 {
 							t.Errorf("Expected limit 10, got %d", filters.Limit)
@@ -1767,72 +2067,388 @@ func TestArticleHandler_ListArticles(t *testing.T) {
 			},
 		},
 		{
-			name:        "Internal server error",
-			queryParams: "",
+			name:        "Successfully search articles",
+			queryParams: "?q=+go%20generics+",
 			setupAuth: func(r *http.Request) *http.Request {
 				return r
 			},
 			setupMock: func() *MockArticleService {
 				mockService := &MockArticleService{
 					listArticlesFunc: func(ctx context.Context, filters repository.ArticleFilters, currentUserID *int64) (*repository.ArticleListResult, error) {
-						return nil, service.ErrInternalServer
+						if filters.Query == nil || *filters.Query != "go generics" {
+							t.Errorf("Expected query 'go generics', got %v", filters.Query)
+						}
+
+						return &repository.ArticleListResult{
+							Articles: []*repository.Article{
+								{
+									ID:          1,
+									Slug:        "go-generics",
+									Title:       "Go Generics",
+									Description: "Type parameters",
+									Body:        "Generics arrived in Go 1.18",
+									Author:      &repository.User{ID: 1, Username: "testuser1"},
+									Highlight: &repository.ArticleHighlight{
+										Title:       "<mark>Go</mark> <mark>Generics</mark>",
+										Description: "Type parameters",
+										Body:        "<mark>Generics</mark> arrived in <mark>Go</mark> 1.18",
+									},
+								},
+							},
+							Count: 1,
+						}, nil
 					},
 				}
 				return mockService
 			},
-			expectedStatus: http.StatusInternalServerError,
+			expectedStatus: http.StatusOK,
+			expectedResponse: MultipleArticlesResponse{
+				Articles: []service.Article{
+					{
+						Slug:   "go-generics",
+						Title:  "Go Generics",
+						Author: service.Profile{Username: "testuser1"},
+						Highlight: &service.ArticleHighlight{
+							Title:       "<mark>Go</mark> <mark>Generics</mark>",
+							Description: "Type parameters",
+							Body:        "<mark>Generics</mark> arrived in <mark>Go</mark> 1.18",
+						},
+					},
+				},
+				ArticlesCount: 1,
+			},
+		},
+		{
+			name:        "Successfully list articles after a cursor",
+			queryParams: "?limit=1&after=" + testCursors.Encode(repository.Cursor{CreatedAt: cursorTime, ID: 3}),
+			setupAuth: func(r *http.Request) *http.Request {
+				return r
+			},
+			setupMock: func() *MockArticleService {
+				mockService := &MockArticleService{
+					listArticlesFunc: func(ctx context.Context, filters repository.ArticleFilters, currentUserID *int64) (*repository.ArticleListResult, error) {
+						if filters.After == nil || filters.After.ID != 3 || !filters.After.CreatedAt.Equal(cursorTime) {
+							t.Errorf("Expected to list after article 3, got %+v", filters.After)
+						}
+
+						return &repository.ArticleListResult{
+							Articles: []*repository.Article{
+								{
+									ID:        2,
+									Slug:      "test-article-2",
+									Title:     "Test Article 2",
+									CreatedAt: cursorTime.Add(-time.Hour),
+									Author:    &repository.User{ID: 1, Username: "testuser1"},
+								},
+							},
+							Count:   3,
+							HasMore: true,
+						}, nil
+					},
+				}
+				return mockService
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: MultipleArticlesResponse{
+				Articles: []service.Article{
+					{
+						Slug:   "test-article-2",
+						Title:  "Test Article 2",
+						Author: service.Profile{Username: "testuser1"},
+					},
+				},
+				ArticlesCount: 3,
+				PrevCursor:    testCursors.Encode(repository.Cursor{CreatedAt: cursorTime.Add(-time.Hour), ID: 2}),
+				NextCursor:    testCursors.Encode(repository.Cursor{CreatedAt: cursorTime.Add(-time.Hour), ID: 2}),
+			},
+		},
+		{
+			name:        "Cursor with a search query",
+			queryParams: "?q=go&after=" + testCursors.Encode(repository.Cursor{CreatedAt: cursorTime, ID: 3}),
+			setupAuth: func(r *http.Request) *http.Request {
+				return r
+			},
+			setupMock: func() *MockArticleService {
+				return &MockArticleService{}
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
 			expectedResponse: response.GenericErrorModel{
 				Errors: struct {
 					Body []string `json:"body"`
-				}{Body: []string{"Internal server error"}},
+				}{Body: []string{"Cursors cannot be used with a search query"}},
 			},
 		},
-	}
-
-	for _, tt := range tests {
-		tt := tt
-		t.Run(tt.name, func(t *testing.T) {
-			t.Parallel()
-
-			// Setup Mock
-			mockService := tt.setupMock()
-
-			// Create Handler
-			handler := NewArticleHandler(mockService)
-
-			// Create Request
-			req := httptest.NewRequest(
-				http.MethodGet,
-				"/api/articles"+tt.queryParams,
-				nil,
-			)
-
-			// Setup Auth if provided
-			if tt.setupAuth != nil {
-				req = tt.setupAuth(req)
-			}
-
-			// Create Response Recorder
-			rr := httptest.NewRecorder()
-
-			// Serve Request
-			handler.ListArticles()(rr, req)
-
-			// Check Status Code
-			if status := rr.Code; status != tt.expectedStatus {
-				t.Errorf("Status code: got %v, want %v", status, tt.expectedStatus)
-			}
-
-			// Check Response Body
-			if tt.expectedStatus == http.StatusOK {
-				var resp MultipleArticlesResponse
-				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
-					t.Errorf("Failed to unmarshal response: %v", err)
+		{
+			name:        "Cursor with an offset",
+			queryParams: "?offset=20&before=" + testCursors.Encode(repository.Cursor{CreatedAt: cursorTime, ID: 3}),
+			setupAuth: func(r *http.Request) *http.Request {
+				return r
+			},
+			setupMock: func() *MockArticleService {
+				return &MockArticleService{}
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Offset cannot be used with a cursor"}},
+			},
+		},
+		{
+			name:        "Sort by most favorited",
+			queryParams: "?sort=most-favorited&limit=1",
+			setupAuth: func(r *http.Request) *http.Request {
+				return r
+			},
+			setupMock: func() *MockArticleService {
+				return &MockArticleService{
+					listArticlesFunc: func(ctx context.Context, filters repository.ArticleFilters, currentUserID *int64) (*repository.ArticleListResult, error) {
+						if filters.Sort != repository.ArticleSortMostFavorited {
+							t.Errorf("Expected sort %q, got %q", repository.ArticleSortMostFavorited, filters.Sort)
+						}
+						return &repository.ArticleListResult{
+							Articles: []*repository.Article{
+								{
+									ID:             3,
+									Slug:           "popular-article",
+									Title:          "Popular Article",
+									TagList:        []string{},
+									CreatedAt:      cursorTime,
+									FavoritesCount: 10,
+									Author:         &repository.User{Username: "testuser1"},
+								},
+							},
+							Count:   2,
+							HasMore: true,
+						}, nil
+					},
 				}
-				// Reset timestamps for comparison
-				for i := range resp.Articles {
-					resp.Articles[i].CreatedAt = time.Time{}
-					resp.Articles[i].UpdatedAt = time.Time{}
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: MultipleArticlesResponse{
+				Articles: []service.Article{
+					{
+						Slug:           "popular-article",
+						Title:          "Popular Article",
+						TagList:        []string{},
+						FavoritesCount: 10,
+						Author:         service.Profile{Username: "testuser1"},
+					},
+				},
+				ArticlesCount: 2,
+			},
+		},
+		{
+			name:        "Empty tag",
+			queryParams: "?tag=go&tag=",
+			setupAuth: func(r *http.Request) *http.Request {
+				return r
+			},
+			setupMock: func() *MockArticleService {
+				return &MockArticleService{}
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Tag cannot be empty"}},
+			},
+		},
+		{
+			name:        "Invalid tag match",
+			queryParams: "?tag=go&tagMatch=some",
+			setupAuth: func(r *http.Request) *http.Request {
+				return r
+			},
+			setupMock: func() *MockArticleService {
+				return &MockArticleService{}
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Tag match must be one of any or all"}},
+			},
+		},
+		{
+			name:        "Invalid created after",
+			queryParams: "?createdAfter=2024-01-01",
+			setupAuth: func(r *http.Request) *http.Request {
+				return r
+			},
+			setupMock: func() *MockArticleService {
+				return &MockArticleService{}
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Created after must be an RFC3339 timestamp"}},
+			},
+		},
+		{
+			name:        "Created after not earlier than created before",
+			queryParams: "?createdAfter=2024-02-01T00:00:00Z&createdBefore=2024-01-01T00:00:00Z",
+			setupAuth: func(r *http.Request) *http.Request {
+				return r
+			},
+			setupMock: func() *MockArticleService {
+				return &MockArticleService{}
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Created after must be earlier than created before"}},
+			},
+		},
+		{
+			name:        "Invalid limit",
+			queryParams: "?limit=ten",
+			setupAuth: func(r *http.Request) *http.Request {
+				return r
+			},
+			setupMock: func() *MockArticleService {
+				return &MockArticleService{}
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Limit must be a positive integer"}},
+			},
+		},
+		{
+			name:        "Negative offset",
+			queryParams: "?offset=-1",
+			setupAuth: func(r *http.Request) *http.Request {
+				return r
+			},
+			setupMock: func() *MockArticleService {
+				return &MockArticleService{}
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Offset must be a non-negative integer"}},
+			},
+		},
+		{
+			name:        "Invalid sort",
+			queryParams: "?sort=popular",
+			setupAuth: func(r *http.Request) *http.Request {
+				return r
+			},
+			setupMock: func() *MockArticleService {
+				return &MockArticleService{}
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Sort must be one of newest, oldest, most-favorited, most-commented or recently-updated"}},
+			},
+		},
+		{
+			name:        "Cursor with a sort order not by creation time",
+			queryParams: "?sort=most-commented&after=" + testCursors.Encode(repository.Cursor{CreatedAt: cursorTime, ID: 3}),
+			setupAuth: func(r *http.Request) *http.Request {
+				return r
+			},
+			setupMock: func() *MockArticleService {
+				return &MockArticleService{}
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Cursors can only be used when sorting by newest or oldest"}},
+			},
+		},
+		{
+			name:        "Invalid cursor",
+			queryParams: "?after=not-a-cursor",
+			setupAuth: func(r *http.Request) *http.Request {
+				return r
+			},
+			setupMock: func() *MockArticleService {
+				return &MockArticleService{}
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"After must be a valid cursor"}},
+			},
+		},
+		{
+			name:        "Internal server error",
+			queryParams: "",
+			setupAuth: func(r *http.Request) *http.Request {
+				return r
+			},
+			setupMock: func() *MockArticleService {
+				mockService := &MockArticleService{
+					listArticlesFunc: func(ctx context.Context, filters repository.ArticleFilters, currentUserID *int64) (*repository.ArticleListResult, error) {
+						return nil, service.ErrInternalServer
+					},
+				}
+				return mockService
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Internal server error"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup Mock
+			mockService := tt.setupMock()
+
+			// Create Handler
+			handler := NewArticleHandler(mockService, testCursors)
+
+			// Create Request
+			req := httptest.NewRequest(
+				http.MethodGet,
+				"/api/articles"+tt.queryParams,
+				nil,
+			)
+
+			// Setup Auth if provided
+			if tt.setupAuth != nil {
+				req = tt.setupAuth(req)
+			}
+
+			// Create Response Recorder
+			rr := httptest.NewRecorder()
+
+			// Serve Request
+			handler.ListArticles()(rr, req)
+
+			// Check Status Code
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("Status code: got %v, want %v", status, tt.expectedStatus)
+			}
+
+			// Check Response Body
+			if tt.expectedStatus == http.StatusOK {
+				var resp MultipleArticlesResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				// Reset timestamps for comparison
+				for i := range resp.Articles {
+					resp.Articles[i].CreatedAt = time.Time{}
+					resp.Articles[i].UpdatedAt = time.Time{}
 				}
 
 				expected := tt.expectedResponse.(MultipleArticlesResponse)
@@ -1843,6 +2459,15 @@ func TestArticleHandler_ListArticles(t *testing.T) {
 						expected.ArticlesCount,
 					)
 				}
+				if resp.PrevCursor != expected.PrevCursor || resp.NextCursor != expected.NextCursor {
+					t.Errorf(
+						"Cursors: got %q and %q, want %q and %q",
+						resp.PrevCursor,
+						resp.NextCursor,
+						expected.PrevCursor,
+						expected.NextCursor,
+					)
+				}
 				if len(resp.Articles) != len(expected.Articles) {
 					t.Errorf(
 						"Articles length: got %d, want %d",
@@ -1903,6 +2528,14 @@ func TestArticleHandler_ListArticles(t *testing.T) {
 							expectedArticle.Author.Following,
 						)
 					}
+					if !reflect.DeepEqual(article.Highlight, expectedArticle.Highlight) {
+						t.Errorf(
+							"Article[%d].Highlight: got %+v, want %+v",
+							i,
+							article.Highlight,
+							expectedArticle.Highlight,
+						)
+					}
 				}
 			} else {
 				var resp response.GenericErrorModel
@@ -1942,15 +2575,15 @@ func TestArticleHandler_GetArticlesFeed(t *testing.T) {
 			},
 			setupMock: func() *MockArticleService {
 				mockService := &MockArticleService{
-					getArticlesFeedFunc: func(ctx context.Context, userID int64, limit, offset int) (*repository.ArticleListResult, error) {
+					getArticlesFeedFunc: func(ctx context.Context, userID int64, filters repository.FeedFilters) (*repository.ArticleListResult, error) {
 						if userID != 1 {
 							t.Errorf("Expected userID 1, got %d", userID)
 						}
-						if limit != 20 {
-							t.Errorf("Expected limit 20, got %d", limit)
+						if filters.Limit != 20 {
+							t.Errorf("Expected limit 20, got %d", filters.Limit)
 						}
-						if offset != 0 {
-							t.Errorf("Expected offset 0, got %d", offset)
+						if filters.Offset != 0 {
+							t.Errorf("Expected offset 0, got %d", filters.Offset)
 						}
 
 						now := time.Now()
@@ -2020,15 +2653,15 @@ func TestArticleHandler_GetArticlesFeed(t *testing.T) {
 			},
 			setupMock: func() *MockArticleService {
 				mockService := &MockArticleService{
-					getArticlesFeedFunc: func(ctx context.Context, userID int64, limit, offset int) (*repository.ArticleListResult, error) {
+					getArticlesFeedFunc: func(ctx context.Context, userID int64, filters repository.FeedFilters) (*repository.ArticleListResult, error) {
 						if userID != 1 {
 							t.Errorf("Expected userID 1, got %d", userID)
 						}
-						if limit != 5 {
-							t.Errorf("Expected limit 5, got %d", limit)
+						if filters.Limit != 5 {
+							t.Errorf("Expected limit 5, got %d", filters.Limit)
 						}
-						if offset != 10 {
-							t.Errorf("Expected offset 10, got %d", offset)
+						if filters.Offset != 10 {
+							t.Errorf("Expected offset 10, got %d", filters.Offset)
 						}
 
 						return &repository.ArticleListResult{
@@ -2054,7 +2687,7 @@ func TestArticleHandler_GetArticlesFeed(t *testing.T) {
 			},
 			setupMock: func() *MockArticleService {
 				mockService := &MockArticleService{
-					getArticlesFeedFunc: func(ctx context.Context, userID int64, limit, offset int) (*repository.ArticleListResult, error) {
+					getArticlesFeedFunc: func(ctx context.Context, userID int64, filters repository.FeedFilters) (*repository.ArticleListResult, error) {
 						t.Errorf("GetArticlesFeed should not be called for unauthenticated request")
 						return nil, nil
 					},
@@ -2080,7 +2713,7 @@ func TestArticleHandler_GetArticlesFeed(t *testing.T) {
 			},
 			setupMock: func() *MockArticleService {
 				mockService := &MockArticleService{
-					getArticlesFeedFunc: func(ctx context.Context, userID int64, limit, offset int) (*repository.ArticleListResult, error) {
+					getArticlesFeedFunc: func(ctx context.Context, userID int64, filters repository.FeedFilters) (*repository.ArticleListResult, error) {
 						return nil, service.ErrInternalServer
 					},
 				}
@@ -2104,7 +2737,7 @@ func TestArticleHandler_GetArticlesFeed(t *testing.T) {
 			mockService := tt.setupMock()
 
 			// Create Handler
-			handler := NewArticleHandler(mockService)
+			handler := NewArticleHandler(mockService, testCursors)
 
 			// Create Request
 			req := httptest.NewRequest(
@@ -2223,3 +2856,414 @@ func TestArticleHandler_GetArticlesFeed(t *testing.T) {
 		})
 	}
 }
+
+// TestArticleHandler_PublishArticle tests the PublishArticle method of the ArticleHandler
+func TestArticleHandler_PublishArticle(t *testing.T) {
+	t.Parallel()
+
+	authenticated := func(r *http.Request) *http.Request {
+		r.Header.Set("Authorization", "Token jwt.token.here")
+		ctx := context.WithValue(r.Context(), middleware.UserIDContextKey, int64(1))
+		return r.WithContext(ctx)
+	}
+
+	tests := []struct {
+		name             string
+		slug             string
+		setupAuth        func(r *http.Request) *http.Request
+		publishErr       error
+		expectedStatus   int
+		expectedResponse any
+	}{
+		{
+			name:           "Successfully publish an article",
+			slug:           "test-article",
+			setupAuth:      authenticated,
+			expectedStatus: http.StatusOK,
+			expectedResponse: ArticleResponse{
+				Article: service.Article{
+					Slug:    "test-article",
+					Title:   "Test Article",
+					TagList: []string{},
+					Status:  repository.ArticleStatusPublished,
+					Author:  service.Profile{Username: "testuser"},
+				},
+			},
+		},
+		{
+			name: "Unauthenticated request",
+			slug: "test-article",
+			setupAuth: func(r *http.Request) *http.Request {
+				return r
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Unauthorized"}},
+			},
+		},
+		{
+			name:           "Not the author",
+			slug:           "test-article",
+			setupAuth:      authenticated,
+			publishErr:     service.ErrArticleNotAuthorized,
+			expectedStatus: http.StatusForbidden,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"You are not the author of this article"}},
+			},
+		},
+		{
+			name:           "Article not found",
+			slug:           "non-existent-article",
+			setupAuth:      authenticated,
+			publishErr:     service.ErrArticleNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Article not found"}},
+			},
+		},
+		{
+			name:           "Internal server error",
+			slug:           "test-article",
+			setupAuth:      authenticated,
+			publishErr:     service.ErrInternalServer,
+			expectedStatus: http.StatusInternalServerError,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Internal server error"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup Mock
+			mockService := &MockArticleService{
+				publishArticleFunc: func(ctx context.Context, userID int64, slug string) (*service.Article, error) {
+					if tt.publishErr != nil {
+						return nil, tt.publishErr
+					}
+					return &service.Article{
+						Slug:    slug,
+						Title:   "Test Article",
+						TagList: []string{},
+						Status:  repository.ArticleStatusPublished,
+						Author:  service.Profile{Username: "testuser"},
+					}, nil
+				},
+			}
+
+			// Create Handler
+			handler := NewArticleHandler(mockService, testCursors)
+
+			// Create Request
+			req := httptest.NewRequest(
+				http.MethodPost,
+				"/api/articles/"+tt.slug+"/publish",
+				nil,
+			)
+			req.SetPathValue("slug", tt.slug)
+			req = tt.setupAuth(req)
+
+			// Create Response Recorder
+			rr := httptest.NewRecorder()
+
+			// Serve Request
+			handler.PublishArticle()(rr, req)
+
+			// Check Status Code
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("Status code: got %v, want %v", status, tt.expectedStatus)
+			}
+
+			// Check Response Body
+			var got any
+			if tt.expectedStatus == http.StatusOK {
+				var resp ArticleResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				got = resp
+			} else {
+				var resp response.GenericErrorModel
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				got = resp
+			}
+
+			// Deep compare expected and got
+			if !reflect.DeepEqual(got, tt.expectedResponse) {
+				t.Errorf("Response body: got %v, want %v", got, tt.expectedResponse)
+			}
+		})
+	}
+}
+
+// TestArticleHandler_ScheduleArticle tests the ScheduleArticle method of the ArticleHandler
+func TestArticleHandler_ScheduleArticle(t *testing.T) {
+	t.Parallel()
+
+	publishAt := time.Date(2030, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		requestBody      string
+		authenticated    bool
+		scheduleErr      error
+		expectedStatus   int
+		expectedResponse any
+	}{
+		{
+			name:           "Successfully schedule an article",
+			requestBody:    `{"article":{"publishAt":"2030-01-01T09:00:00Z"}}`,
+			authenticated:  true,
+			expectedStatus: http.StatusOK,
+			expectedResponse: ArticleResponse{
+				Article: service.Article{
+					Slug:      "test-article",
+					Title:     "Test Article",
+					TagList:   []string{},
+					Status:    repository.ArticleStatusScheduled,
+					PublishAt: &publishAt,
+					Author:    service.Profile{Username: "testuser"},
+				},
+			},
+		},
+		{
+			name:           "Unauthenticated request",
+			requestBody:    `{"article":{"publishAt":"2030-01-01T09:00:00Z"}}`,
+			authenticated:  false,
+			expectedStatus: http.StatusUnauthorized,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Unauthorized"}},
+			},
+		},
+		{
+			name:           "Missing publishAt",
+			requestBody:    `{"article":{}}`,
+			authenticated:  true,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"PublishAt is required"}},
+			},
+		},
+		{
+			name:           "Invalid publishAt",
+			requestBody:    `{"article":{"publishAt":"tomorrow"}}`,
+			authenticated:  true,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Invalid request body"}},
+			},
+		},
+		{
+			name:           "PublishAt in the past",
+			requestBody:    `{"article":{"publishAt":"2020-01-01T09:00:00Z"}}`,
+			authenticated:  true,
+			scheduleErr:    service.ErrInvalidPublishAt,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"PublishAt must be in the future"}},
+			},
+		},
+		{
+			name:           "Not the author",
+			requestBody:    `{"article":{"publishAt":"2030-01-01T09:00:00Z"}}`,
+			authenticated:  true,
+			scheduleErr:    service.ErrArticleNotAuthorized,
+			expectedStatus: http.StatusForbidden,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"You are not the author of this article"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup Mock
+			mockService := &MockArticleService{
+				scheduleArticleFunc: func(ctx context.Context, userID int64, slug string, at time.Time) (*service.Article, error) {
+					if tt.scheduleErr != nil {
+						return nil, tt.scheduleErr
+					}
+					if !at.Equal(publishAt) {
+						t.Errorf("Expected publishAt %v, got %v", publishAt, at)
+					}
+					return &service.Article{
+						Slug:      slug,
+						Title:     "Test Article",
+						TagList:   []string{},
+						Status:    repository.ArticleStatusScheduled,
+						PublishAt: &at,
+						Author:    service.Profile{Username: "testuser"},
+					}, nil
+				},
+			}
+
+			// Create Handler
+			handler := NewArticleHandler(mockService, testCursors)
+
+			// Create Request
+			req := httptest.NewRequest(
+				http.MethodPost,
+				"/api/articles/test-article/schedule",
+				bytes.NewBufferString(tt.requestBody),
+			)
+			req.SetPathValue("slug", "test-article")
+			if tt.authenticated {
+				ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, int64(1))
+				req = req.WithContext(ctx)
+			}
+
+			// Create Response Recorder
+			rr := httptest.NewRecorder()
+
+			// Serve Request
+			handler.ScheduleArticle()(rr, req)
+
+			// Check Status Code
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("Status code: got %v, want %v", status, tt.expectedStatus)
+			}
+
+			// Check Response Body
+			var got any
+			if tt.expectedStatus == http.StatusOK {
+				var resp ArticleResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				got = resp
+			} else {
+				var resp response.GenericErrorModel
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				got = resp
+			}
+
+			// Deep compare expected and got
+			if !reflect.DeepEqual(got, tt.expectedResponse) {
+				t.Errorf("Response body: got %v, want %v", got, tt.expectedResponse)
+			}
+		})
+	}
+}
+
+// TestArticleHandler_MovedArticle tests that requests for an article by a previous slug
+// are redirected to its current slug
+func TestArticleHandler_MovedArticle(t *testing.T) {
+	t.Parallel()
+
+	moved := &service.ArticleMovedError{Slug: "new-title"}
+
+	// Setup Mock
+	mockService := &MockArticleService{
+		getArticleFunc: func(ctx context.Context, slug string, currentUserID *int64) (*service.Article, error) {
+			return nil, moved
+		},
+		favoriteArticleFunc: func(ctx context.Context, userID int64, slug string) (*service.Article, error) {
+			return nil, moved
+		},
+		unfavoriteArticleFunc: func(ctx context.Context, userID int64, slug string) (*service.Article, error) {
+			return nil, moved
+		},
+	}
+
+	// Create Handler
+	handler := NewArticleHandler(mockService, testCursors)
+
+	tests := []struct {
+		name             string
+		method           string
+		target           string
+		handler          http.HandlerFunc
+		expectedStatus   int
+		expectedLocation string
+	}{
+		{
+			name:             "Get article",
+			method:           http.MethodGet,
+			target:           "/api/articles/old-title",
+			handler:          handler.GetArticle(),
+			expectedStatus:   http.StatusMovedPermanently,
+			expectedLocation: "/api/articles/new-title",
+		},
+		{
+			name:             "Get article keeps the query",
+			method:           http.MethodGet,
+			target:           "/api/articles/old-title?utm=feed",
+			handler:          handler.GetArticle(),
+			expectedStatus:   http.StatusMovedPermanently,
+			expectedLocation: "/api/articles/new-title?utm=feed",
+		},
+		{
+			name:             "Favorite article",
+			method:           http.MethodPost,
+			target:           "/api/articles/old-title/favorite",
+			handler:          handler.FavoriteArticle(),
+			expectedStatus:   http.StatusPermanentRedirect,
+			expectedLocation: "/api/articles/new-title/favorite",
+		},
+		{
+			name:             "Unfavorite article",
+			method:           http.MethodDelete,
+			target:           "/api/articles/old-title/favorite",
+			handler:          handler.UnfavoriteArticle(),
+			expectedStatus:   http.StatusPermanentRedirect,
+			expectedLocation: "/api/articles/new-title/favorite",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Create Request
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			req.SetPathValue("slug", "old-title")
+			ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, int64(1))
+			req = req.WithContext(ctx)
+
+			// Create Response Recorder
+			rr := httptest.NewRecorder()
+
+			// Serve Request
+			tt.handler(rr, req)
+
+			// Check Status Code
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("Status code: got %v, want %v", status, tt.expectedStatus)
+			}
+
+			// Check Location Header
+			if location := rr.Header().Get("Location"); location != tt.expectedLocation {
+				t.Errorf("Location: got %q, want %q", location, tt.expectedLocation)
+			}
+		})
+	}
+}
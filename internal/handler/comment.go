@@ -7,13 +7,26 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/Nilesh2000/conduit/internal/cursor"
 	"github.com/Nilesh2000/conduit/internal/middleware"
+	"github.com/Nilesh2000/conduit/internal/repository"
 	"github.com/Nilesh2000/conduit/internal/response"
 	"github.com/Nilesh2000/conduit/internal/service"
+	"github.com/Nilesh2000/conduit/internal/validation"
+
+	"github.com/go-playground/validator/v10"
 )
 
 // NewComment is the request for a new comment
 type NewComment struct {
+	Comment struct {
+		Body     string `json:"body" validate:"required"`
+		ParentID *int64 `json:"parentId"`
+	} `json:"comment" validate:"required"`
+}
+
+// UpdateCommentRequest is the request for editing a comment
+type UpdateCommentRequest struct {
 	Comment struct {
 		Body string `json:"body" validate:"required"`
 	} `json:"comment" validate:"required"`
@@ -24,25 +37,72 @@ type CommentResponse struct {
 	Comment service.Comment `json:"comment"`
 }
 
+// CommentVersionsResponse is the response for the earlier versions of a comment
+type CommentVersionsResponse struct {
+	Versions []service.CommentVersion `json:"versions"`
+}
+
+// Views of the comments of an article
+const (
+	// CommentViewFlat lists every comment with its depth, each followed by its replies
+	CommentViewFlat = "flat"
+	// CommentViewTree lists the top-level comments with their replies nested below them
+	CommentViewTree = "tree"
+)
+
+// CommentsResponse is the response for the comments of an article
 type CommentsResponse struct {
-	Comments []service.Comment `json:"comments"`
+	Comments      []service.Comment `json:"comments"`
+	CommentsCount int               `json:"commentsCount"`
+	NextCursor    string            `json:"nextCursor,omitempty"`
+	PrevCursor    string            `json:"prevCursor,omitempty"`
 }
 
 // CommentService is an interface for the comment service
 type CommentService interface {
-	GetComments(ctx context.Context, slug string, userID *int64) ([]service.Comment, error)
-	CreateComment(ctx context.Context, userID int64, slug, body string) (*service.Comment, error)
+	GetComments(
+		ctx context.Context,
+		slug string,
+		userID *int64,
+		filters repository.CommentFilters,
+	) (*service.CommentList, error)
+	CreateComment(
+		ctx context.Context,
+		userID int64,
+		slug, body string,
+		parentID *int64,
+	) (*service.Comment, error)
+	UpdateComment(
+		ctx context.Context,
+		userID int64,
+		slug string,
+		commentID int64,
+		body string,
+	) (*service.Comment, error)
+	GetCommentVersions(
+		ctx context.Context,
+		userID int64,
+		slug string,
+		commentID int64,
+	) ([]service.CommentVersion, error)
 	DeleteComment(ctx context.Context, userID int64, slug string, commentID int64) error
 }
 
 // commentHandler is a handler for comment-related requests
 type commentHandler struct {
 	commentService CommentService
+	cursors        *cursor.Signer
+	validate       *validator.Validate
 }
 
-// NewCommentHandler creates a new comment handler
-func NewCommentHandler(commentService CommentService) *commentHandler {
-	return &commentHandler{commentService: commentService}
+// NewCommentHandler creates a new comment handler that signs the cursors of comment
+// lists with cursors
+func NewCommentHandler(commentService CommentService, cursors *cursor.Signer) *commentHandler {
+	return &commentHandler{
+		commentService: commentService,
+		cursors:        cursors,
+		validate:       validator.New(),
+	}
 }
 
 // GetComments is a handler for getting comments for an article
@@ -59,8 +119,67 @@ func (h *commentHandler) GetComments() http.HandlerFunc {
 
 		slug := r.PathValue("slug")
 
-		comments, err := h.commentService.GetComments(r.Context(), slug, userID)
+		query := r.URL.Query()
+		filters := repository.CommentFilters{
+			Limit: 20, // Default limit
+		}
+
+		// Parse limit parameter
+		if limitStr := query.Get("limit"); limitStr != "" {
+			limit, err := strconv.Atoi(limitStr)
+			if err != nil || limit <= 0 {
+				response.RespondWithError(
+					w,
+					http.StatusUnprocessableEntity,
+					[]string{"Limit must be a positive integer"},
+				)
+				return
+			}
+			filters.Limit = limit
+		}
+
+		// Parse sort order
+		if sort := query.Get("sort"); sort != "" {
+			switch sort {
+			case repository.CommentSortOldest, repository.CommentSortNewest:
+				filters.Sort = sort
+			default:
+				response.RespondWithError(
+					w,
+					http.StatusUnprocessableEntity,
+					[]string{"Sort must be one of oldest or newest"},
+				)
+				return
+			}
+		}
+
+		// Parse cursor parameters
+		var ok bool
+		if filters.After, filters.Before, ok = parseCursors(w, r, h.cursors); !ok {
+			return
+		}
+
+		// Parse view parameter
+		view := CommentViewFlat
+		if v := query.Get("view"); v != "" {
+			switch v {
+			case CommentViewFlat, CommentViewTree:
+				view = v
+			default:
+				response.RespondWithError(
+					w,
+					http.StatusUnprocessableEntity,
+					[]string{"View must be one of flat or tree"},
+				)
+				return
+			}
+		}
+
+		result, err := h.commentService.GetComments(r.Context(), slug, userID, filters)
 		if err != nil {
+			if redirectMovedArticle(w, r, err) {
+				return
+			}
 			switch {
 			case errors.Is(err, service.ErrArticleNotFound):
 				response.RespondWithError(w, http.StatusNotFound, []string{"Article not found"})
@@ -74,8 +193,21 @@ func (h *commentHandler) GetComments() http.HandlerFunc {
 			return
 		}
 
+		// Pages are made of top-level comments, so the cursors are too
+		resp := CommentsResponse{Comments: result.Comments, CommentsCount: result.Count}
+		if view == CommentViewFlat {
+			resp.Comments = flattenComments(result.Comments)
+		}
+		page := listPage{after: filters.After, before: filters.Before, hasMore: result.HasMore}
+		if n := len(result.Comments); n > 0 {
+			first, last := result.Comments[0], result.Comments[n-1]
+			page.first = &repository.Cursor{CreatedAt: first.CreatedAt, ID: int64(first.ID)}
+			page.last = &repository.Cursor{CreatedAt: last.CreatedAt, ID: int64(last.ID)}
+		}
+		resp.PrevCursor, resp.NextCursor = page.cursors(h.cursors)
+
 		w.WriteHeader(http.StatusOK)
-		if err := json.NewEncoder(w).Encode(CommentsResponse{Comments: comments}); err != nil {
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
 			response.RespondWithError(
 				w,
 				http.StatusInternalServerError,
@@ -109,11 +241,38 @@ func (h *commentHandler) CreateComment() http.HandlerFunc {
 		}
 
 		// Call service to create comment
-		comment, err := h.commentService.CreateComment(r.Context(), userID, slug, req.Comment.Body)
+		comment, err := h.commentService.CreateComment(
+			r.Context(),
+			userID,
+			slug,
+			req.Comment.Body,
+			req.Comment.ParentID,
+		)
 		if err != nil {
+			if redirectMovedArticle(w, r, err) {
+				return
+			}
 			switch {
 			case errors.Is(err, service.ErrArticleNotFound):
 				response.RespondWithError(w, http.StatusNotFound, []string{"Article not found"})
+			case errors.Is(err, service.ErrParentCommentNotFound):
+				response.RespondWithError(
+					w,
+					http.StatusUnprocessableEntity,
+					[]string{"Parent comment not found"},
+				)
+			case errors.Is(err, service.ErrCommentTooDeep):
+				response.RespondWithError(
+					w,
+					http.StatusUnprocessableEntity,
+					[]string{"Replies cannot be nested this deeply"},
+				)
+			case errors.Is(err, service.ErrUserBlocked):
+				response.RespondWithError(
+					w,
+					http.StatusForbidden,
+					[]string{"You have been blocked from commenting here"},
+				)
 			default:
 				response.RespondWithError(
 					w,
@@ -137,7 +296,93 @@ func (h *commentHandler) CreateComment() http.HandlerFunc {
 	}
 }
 
-func (h *commentHandler) DeleteComment() http.HandlerFunc {
+// UpdateComment is a handler for editing a comment
+func (h *commentHandler) UpdateComment() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Set the content type to JSON
+		w.Header().Set("Content-Type", "application/json")
+
+		// Get user ID from context
+		userID, ok := middleware.GetUserIDFromContext(r.Context())
+		if !ok {
+			response.RespondWithError(w, http.StatusUnauthorized, []string{"Unauthorized"})
+			return
+		}
+
+		slug := r.PathValue("slug")
+		commentID, ok := parseCommentID(w, r)
+		if !ok {
+			return
+		}
+
+		// Parse request body
+		var req UpdateCommentRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.RespondWithError(w, http.StatusBadRequest, []string{"Invalid request body"})
+			return
+		}
+
+		// Validate request body
+		if err := h.validate.Struct(req); err != nil {
+			errors := validation.TranslateValidationErrors(err)
+			response.RespondWithError(w, http.StatusUnprocessableEntity, errors)
+			return
+		}
+
+		// Call service to update comment
+		comment, err := h.commentService.UpdateComment(
+			r.Context(),
+			userID,
+			slug,
+			commentID,
+			req.Comment.Body,
+		)
+		if err != nil {
+			if redirectMovedArticle(w, r, err) {
+				return
+			}
+			switch {
+			case errors.Is(err, service.ErrCommentNotAuthorized):
+				response.RespondWithError(
+					w,
+					http.StatusForbidden,
+					[]string{"You are not the author of this comment"},
+				)
+			case errors.Is(err, service.ErrCommentEditClosed):
+				response.RespondWithError(
+					w,
+					http.StatusForbidden,
+					[]string{"This comment can no longer be edited"},
+				)
+			case errors.Is(err, service.ErrArticleNotFound):
+				response.RespondWithError(w, http.StatusNotFound, []string{"Article not found"})
+			case errors.Is(err, service.ErrCommentNotFound):
+				response.RespondWithError(w, http.StatusNotFound, []string{"Comment not found"})
+			default:
+				response.RespondWithError(
+					w,
+					http.StatusInternalServerError,
+					[]string{"Internal server error"},
+				)
+			}
+			return
+		}
+
+		// Respond with updated comment
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(CommentResponse{Comment: *comment}); err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusInternalServerError,
+				[]string{"Internal server error"},
+			)
+			return
+		}
+	}
+}
+
+// GetCommentVersions is a handler for listing the earlier versions of a comment
+func (h *commentHandler) GetCommentVersions() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Set the content type to JSON
 		w.Header().Set("Content-Type", "application/json")
@@ -150,15 +395,73 @@ func (h *commentHandler) DeleteComment() http.HandlerFunc {
 		}
 
 		slug := r.PathValue("slug")
-		commentID := r.PathValue("id")
-		commentIDInt, err := strconv.ParseInt(commentID, 10, 64)
+		commentID, ok := parseCommentID(w, r)
+		if !ok {
+			return
+		}
+
+		versions, err := h.commentService.GetCommentVersions(r.Context(), userID, slug, commentID)
 		if err != nil {
-			response.RespondWithError(w, http.StatusBadRequest, []string{"Invalid comment ID"})
+			if redirectMovedArticle(w, r, err) {
+				return
+			}
+			switch {
+			case errors.Is(err, service.ErrNotModerator):
+				response.RespondWithError(
+					w,
+					http.StatusForbidden,
+					[]string{"Only moderators can see earlier versions of comments"},
+				)
+			case errors.Is(err, service.ErrUserNotFound):
+				response.RespondWithError(w, http.StatusNotFound, []string{"User not found"})
+			case errors.Is(err, service.ErrArticleNotFound):
+				response.RespondWithError(w, http.StatusNotFound, []string{"Article not found"})
+			case errors.Is(err, service.ErrCommentNotFound):
+				response.RespondWithError(w, http.StatusNotFound, []string{"Comment not found"})
+			default:
+				response.RespondWithError(
+					w,
+					http.StatusInternalServerError,
+					[]string{"Internal server error"},
+				)
+			}
 			return
 		}
 
-		err = h.commentService.DeleteComment(r.Context(), userID, slug, commentIDInt)
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(CommentVersionsResponse{Versions: versions}); err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusInternalServerError,
+				[]string{"Internal server error"},
+			)
+		}
+	}
+}
+
+func (h *commentHandler) DeleteComment() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Set the content type to JSON
+		w.Header().Set("Content-Type", "application/json")
+
+		// Get user ID from context
+		userID, ok := middleware.GetUserIDFromContext(r.Context())
+		if !ok {
+			response.RespondWithError(w, http.StatusUnauthorized, []string{"Unauthorized"})
+			return
+		}
+
+		slug := r.PathValue("slug")
+		commentID, ok := parseCommentID(w, r)
+		if !ok {
+			return
+		}
+
+		err := h.commentService.DeleteComment(r.Context(), userID, slug, commentID)
 		if err != nil {
+			if redirectMovedArticle(w, r, err) {
+				return
+			}
 			switch {
 			case errors.Is(err, service.ErrCommentNotAuthorized):
 				response.RespondWithError(
@@ -166,6 +469,8 @@ func (h *commentHandler) DeleteComment() http.HandlerFunc {
 					http.StatusForbidden,
 					[]string{"You are not the author of this comment"},
 				)
+			case errors.Is(err, service.ErrArticleNotFound):
+				response.RespondWithError(w, http.StatusNotFound, []string{"Article not found"})
 			case errors.Is(err, service.ErrCommentNotFound):
 				response.RespondWithError(w, http.StatusNotFound, []string{"Comment not found"})
 			default:
@@ -175,8 +480,32 @@ func (h *commentHandler) DeleteComment() http.HandlerFunc {
 					[]string{"Internal server error"},
 				)
 			}
+			return
 		}
 
 		w.WriteHeader(http.StatusOK)
 	}
 }
+
+// parseCommentID parses the comment ID in the request path, responding with an error
+// and returning false if it is invalid
+func parseCommentID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	commentID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, []string{"Invalid comment ID"})
+		return 0, false
+	}
+	return commentID, true
+}
+
+// flattenComments lists comments depth first, each followed by its replies
+func flattenComments(comments []service.Comment) []service.Comment {
+	flat := make([]service.Comment, 0, len(comments))
+	for _, comment := range comments {
+		replies := comment.Replies
+		comment.Replies = nil
+		flat = append(flat, comment)
+		flat = append(flat, flattenComments(replies)...)
+	}
+	return flat
+}
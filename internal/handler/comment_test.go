@@ -11,15 +11,18 @@ import (
 	"time"
 
 	"github.com/Nilesh2000/conduit/internal/middleware"
+	"github.com/Nilesh2000/conduit/internal/repository"
 	"github.com/Nilesh2000/conduit/internal/response"
 	"github.com/Nilesh2000/conduit/internal/service"
 )
 
 // MockCommentService is a mock implementation of the CommentService interface
 type MockCommentService struct {
-	getCommentsFunc   func(ctx context.Context, slug string, userID *int64) ([]service.Comment, error)
-	createCommentFunc func(ctx context.Context, userID int64, slug string, body string) (*service.Comment, error)
-	deleteCommentFunc func(ctx context.Context, userID int64, slug string, commentID int64) error
+	getCommentsFunc        func(ctx context.Context, slug string, userID *int64, filters repository.CommentFilters) (*service.CommentList, error)
+	createCommentFunc      func(ctx context.Context, userID int64, slug string, body string, parentID *int64) (*service.Comment, error)
+	updateCommentFunc      func(ctx context.Context, userID int64, slug string, commentID int64, body string) (*service.Comment, error)
+	getCommentVersionsFunc func(ctx context.Context, userID int64, slug string, commentID int64) ([]service.CommentVersion, error)
+	deleteCommentFunc      func(ctx context.Context, userID int64, slug string, commentID int64) error
 }
 
 // GetComments gets comments for an article in the mock service
@@ -27,8 +30,9 @@ func (m *MockCommentService) GetComments(
 	ctx context.Context,
 	slug string,
 	userID *int64,
-) ([]service.Comment, error) {
-	return m.getCommentsFunc(ctx, slug, userID)
+	filters repository.CommentFilters,
+) (*service.CommentList, error) {
+	return m.getCommentsFunc(ctx, slug, userID, filters)
 }
 
 // CreateComment creates a comment in the mock service
@@ -37,8 +41,30 @@ func (m *MockCommentService) CreateComment(
 	userID int64,
 	slug string,
 	body string,
+	parentID *int64,
 ) (*service.Comment, error) {
-	return m.createCommentFunc(ctx, userID, slug, body)
+	return m.createCommentFunc(ctx, userID, slug, body, parentID)
+}
+
+// UpdateComment updates a comment in the mock service
+func (m *MockCommentService) UpdateComment(
+	ctx context.Context,
+	userID int64,
+	slug string,
+	commentID int64,
+	body string,
+) (*service.Comment, error) {
+	return m.updateCommentFunc(ctx, userID, slug, commentID, body)
+}
+
+// GetCommentVersions gets the earlier versions of a comment in the mock service
+func (m *MockCommentService) GetCommentVersions(
+	ctx context.Context,
+	userID int64,
+	slug string,
+	commentID int64,
+) ([]service.CommentVersion, error) {
+	return m.getCommentVersionsFunc(ctx, userID, slug, commentID)
 }
 
 // DeleteComment deletes a comment in the mock service
@@ -75,13 +101,13 @@ func TestCommentHandler_GetComments(t *testing.T) {
 			},
 			setupMock: func() *MockCommentService {
 				mockService := &MockCommentService{
-					getCommentsFunc: func(ctx context.Context, slug string, userID *int64) ([]service.Comment, error) {
+					getCommentsFunc: func(ctx context.Context, slug string, userID *int64, filters repository.CommentFilters) (*service.CommentList, error) {
 						if slug != "test-slug" {
 							t.Errorf("Expected slug 'test-slug', got %q", slug)
 						}
 
 						now := time.Now()
-						return []service.Comment{
+						return &service.CommentList{Comments: []service.Comment{
 							{
 								ID:        1,
 								Body:      "This is a test comment",
@@ -106,7 +132,7 @@ func TestCommentHandler_GetComments(t *testing.T) {
 									Following: false,
 								},
 							},
-						}, nil
+						}}, nil
 					},
 				}
 				return mockService
@@ -149,7 +175,7 @@ func TestCommentHandler_GetComments(t *testing.T) {
 			},
 			setupMock: func() *MockCommentService {
 				mockService := &MockCommentService{
-					getCommentsFunc: func(ctx context.Context, slug string, userID *int64) ([]service.Comment, error) {
+					getCommentsFunc: func(ctx context.Context, slug string, userID *int64, filters repository.CommentFilters) (*service.CommentList, error) {
 						if slug != "test-slug" {
 							t.Errorf("Expected slug 'test-slug', got %q", slug)
 						}
@@ -159,7 +185,7 @@ func TestCommentHandler_GetComments(t *testing.T) {
 						}
 
 						now := time.Now()
-						return []service.Comment{
+						return &service.CommentList{Comments: []service.Comment{
 							{
 								ID:        1,
 								Body:      "This is a test comment",
@@ -184,7 +210,7 @@ func TestCommentHandler_GetComments(t *testing.T) {
 									Following: false,
 								},
 							},
-						}, nil
+						}}, nil
 					},
 				}
 				return mockService
@@ -231,7 +257,7 @@ func TestCommentHandler_GetComments(t *testing.T) {
 			},
 			setupMock: func() *MockCommentService {
 				mockService := &MockCommentService{
-					getCommentsFunc: func(ctx context.Context, slug string, userID *int64) ([]service.Comment, error) {
+					getCommentsFunc: func(ctx context.Context, slug string, userID *int64, filters repository.CommentFilters) (*service.CommentList, error) {
 						if slug != "non-existent-article" {
 							t.Errorf("Expected slug 'non-existent-article', got %q", slug)
 						}
@@ -260,7 +286,7 @@ func TestCommentHandler_GetComments(t *testing.T) {
 			},
 			setupMock: func() *MockCommentService {
 				mockService := &MockCommentService{
-					getCommentsFunc: func(ctx context.Context, slug string, userID *int64) ([]service.Comment, error) {
+					getCommentsFunc: func(ctx context.Context, slug string, userID *int64, filters repository.CommentFilters) (*service.CommentList, error) {
 						return nil, service.ErrInternalServer
 					},
 				}
@@ -284,7 +310,7 @@ func TestCommentHandler_GetComments(t *testing.T) {
 			mockService := tt.setupMock()
 
 			// Create Handler
-			handler := NewCommentHandler(mockService)
+			handler := NewCommentHandler(mockService, testCursors)
 
 			// Create Request
 			req := httptest.NewRequest(http.MethodGet, "/api/articles/"+tt.slug+"/comments", nil)
@@ -335,10 +361,227 @@ func TestCommentHandler_GetComments(t *testing.T) {
 	}
 }
 
+// TestCommentHandler_GetComments_View tests the views of the GetComments method of the
+// CommentHandler
+func TestCommentHandler_GetComments_View(t *testing.T) {
+	t.Parallel()
+
+	one, two := 1, 2
+	threads := []service.Comment{
+		{
+			ID:         1,
+			Body:       "Top-level comment",
+			ReplyCount: 1,
+			Replies: []service.Comment{
+				{
+					ID:         2,
+					ParentID:   &one,
+					Depth:      1,
+					ReplyCount: 1,
+					Deleted:    true,
+					Replies: []service.Comment{
+						{ID: 3, Body: "Reply to a deleted reply", ParentID: &two, Depth: 2},
+					},
+				},
+			},
+		},
+		{ID: 4, Body: "Another top-level comment"},
+	}
+
+	tests := []struct {
+		name             string
+		query            string
+		expectedStatus   int
+		expectedResponse any
+	}{
+		{
+			name:           "Flat by default",
+			query:          "",
+			expectedStatus: http.StatusOK,
+			expectedResponse: CommentsResponse{
+				Comments: []service.Comment{
+					{ID: 1, Body: "Top-level comment", ReplyCount: 1},
+					{ID: 2, ParentID: &one, Depth: 1, ReplyCount: 1, Deleted: true},
+					{ID: 3, Body: "Reply to a deleted reply", ParentID: &two, Depth: 2},
+					{ID: 4, Body: "Another top-level comment"},
+				},
+			},
+		},
+		{
+			name:             "Tree",
+			query:            "?view=tree",
+			expectedStatus:   http.StatusOK,
+			expectedResponse: CommentsResponse{Comments: threads},
+		},
+		{
+			name:           "Invalid view",
+			query:          "?view=nested",
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"View must be one of flat or tree"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup Mock Service
+			mockService := &MockCommentService{
+				getCommentsFunc: func(ctx context.Context, slug string, userID *int64, filters repository.CommentFilters) (*service.CommentList, error) {
+					return &service.CommentList{Comments: threads}, nil
+				},
+			}
+
+			// Create Handler
+			handler := NewCommentHandler(mockService, testCursors)
+
+			// Create Request
+			req := httptest.NewRequest(http.MethodGet, "/api/articles/test-slug/comments"+tt.query, nil)
+			req.SetPathValue("slug", "test-slug")
+
+			// Create Response Recorder
+			rr := httptest.NewRecorder()
+
+			// Serve Request
+			handler.GetComments()(rr, req)
+
+			// Check Status Code
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("Status code: got %v, want %v", status, tt.expectedStatus)
+			}
+
+			// Check Response Body
+			var got any
+			if tt.expectedStatus == http.StatusOK {
+				var resp CommentsResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				got = resp
+			} else {
+				var resp response.GenericErrorModel
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				got = resp
+			}
+
+			// Deep compare expected and got
+			if !reflect.DeepEqual(got, tt.expectedResponse) {
+				t.Errorf("Response body: got %+v, want %+v", got, tt.expectedResponse)
+			}
+		})
+	}
+}
+
+// TestCommentHandler_GetComments_Filters tests the query parameters of the GetComments
+// method of the CommentHandler
+func TestCommentHandler_GetComments_Filters(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		query           string
+		expectedStatus  int
+		expectedFilters repository.CommentFilters
+		expectedErrors  []string
+	}{
+		{
+			name:            "Default page",
+			query:           "",
+			expectedStatus:  http.StatusOK,
+			expectedFilters: repository.CommentFilters{Limit: 20},
+		},
+		{
+			name:            "Newest first",
+			query:           "?sort=newest&limit=5",
+			expectedStatus:  http.StatusOK,
+			expectedFilters: repository.CommentFilters{Limit: 5, Sort: repository.CommentSortNewest},
+		},
+		{
+			name:           "Invalid sort",
+			query:          "?sort=top",
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedErrors: []string{"Sort must be one of oldest or newest"},
+		},
+		{
+			name:           "Invalid limit",
+			query:          "?limit=0",
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedErrors: []string{"Limit must be a positive integer"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup Mock Service
+			mockService := &MockCommentService{
+				getCommentsFunc: func(ctx context.Context, slug string, userID *int64, filters repository.CommentFilters) (*service.CommentList, error) {
+					if !reflect.DeepEqual(filters, tt.expectedFilters) {
+						t.Errorf("Expected filters %+v, got %+v", tt.expectedFilters, filters)
+					}
+					return &service.CommentList{
+						Comments: []service.Comment{{ID: 1, Body: "Comment"}},
+						Count:    7,
+					}, nil
+				},
+			}
+
+			// Create Handler
+			handler := NewCommentHandler(mockService, testCursors)
+
+			// Create Request
+			req := httptest.NewRequest(http.MethodGet, "/api/articles/test-slug/comments"+tt.query, nil)
+			req.SetPathValue("slug", "test-slug")
+
+			// Create Response Recorder
+			rr := httptest.NewRecorder()
+
+			// Serve Request
+			handler.GetComments()(rr, req)
+
+			// Check Status Code
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("Status code: got %v, want %v", status, tt.expectedStatus)
+			}
+
+			// Check Response Body
+			if tt.expectedStatus == http.StatusOK {
+				var resp CommentsResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				if resp.CommentsCount != 7 {
+					t.Errorf("Expected comments count 7, got %d", resp.CommentsCount)
+				}
+			} else {
+				var resp response.GenericErrorModel
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				if !reflect.DeepEqual(resp.Errors.Body, tt.expectedErrors) {
+					t.Errorf("Expected errors %v, got %v", tt.expectedErrors, resp.Errors.Body)
+				}
+			}
+		})
+	}
+}
+
 // TestCommentHandler_CreateComment tests the CreateComment method of the CommentHandler
 func TestCommentHandler_CreateComment(t *testing.T) {
 	t.Parallel()
 
+	parentID := int64(1)
+	parentCommentID := 1
+
 	tests := []struct {
 		name             string
 		slug             string
@@ -353,7 +596,8 @@ func TestCommentHandler_CreateComment(t *testing.T) {
 			slug: "test-slug",
 			requestBody: NewComment{
 				Comment: struct {
-					Body string `json:"body" validate:"required"`
+					Body     string `json:"body" validate:"required"`
+					ParentID *int64 `json:"parentId"`
 				}{
 					Body: "This is a test comment",
 				},
@@ -367,7 +611,7 @@ func TestCommentHandler_CreateComment(t *testing.T) {
 			},
 			setupMock: func() *MockCommentService {
 				mockService := &MockCommentService{
-					createCommentFunc: func(ctx context.Context, userID int64, slug string, body string) (*service.Comment, error) {
+					createCommentFunc: func(ctx context.Context, userID int64, slug string, body string, parentID *int64) (*service.Comment, error) {
 						if userID != 1 {
 							t.Errorf("Expected userID 1, got %d", userID)
 						}
@@ -416,7 +660,8 @@ func TestCommentHandler_CreateComment(t *testing.T) {
 			slug: "test-slug",
 			requestBody: NewComment{
 				Comment: struct {
-					Body string `json:"body" validate:"required"`
+					Body     string `json:"body" validate:"required"`
+					ParentID *int64 `json:"parentId"`
 				}{
 					Body: "This is a test comment",
 				},
@@ -426,7 +671,7 @@ func TestCommentHandler_CreateComment(t *testing.T) {
 			},
 			setupMock: func() *MockCommentService {
 				mockService := &MockCommentService{
-					createCommentFunc: func(ctx context.Context, userID int64, slug string, body string) (*service.Comment, error) {
+					createCommentFunc: func(ctx context.Context, userID int64, slug string, body string, parentID *int64) (*service.Comment, error) {
 						return nil, nil
 					},
 				}
@@ -452,7 +697,7 @@ func TestCommentHandler_CreateComment(t *testing.T) {
 			},
 			setupMock: func() *MockCommentService {
 				mockService := &MockCommentService{
-					createCommentFunc: func(ctx context.Context, userID int64, slug string, body string) (*service.Comment, error) {
+					createCommentFunc: func(ctx context.Context, userID int64, slug string, body string, parentID *int64) (*service.Comment, error) {
 						return nil, nil
 					},
 				}
@@ -470,7 +715,8 @@ func TestCommentHandler_CreateComment(t *testing.T) {
 			slug: "non-existent-article",
 			requestBody: NewComment{
 				Comment: struct {
-					Body string `json:"body" validate:"required"`
+					Body     string `json:"body" validate:"required"`
+					ParentID *int64 `json:"parentId"`
 				}{
 					Body: "This is a test comment",
 				},
@@ -484,7 +730,7 @@ func TestCommentHandler_CreateComment(t *testing.T) {
 			},
 			setupMock: func() *MockCommentService {
 				mockService := &MockCommentService{
-					createCommentFunc: func(ctx context.Context, userID int64, slug string, body string) (*service.Comment, error) {
+					createCommentFunc: func(ctx context.Context, userID int64, slug string, body string, parentID *int64) (*service.Comment, error) {
 						return nil, service.ErrArticleNotFound
 					},
 				}
@@ -497,12 +743,118 @@ func TestCommentHandler_CreateComment(t *testing.T) {
 				}{Body: []string{"Article not found"}},
 			},
 		},
+		{
+			name: "Reply to a comment",
+			slug: "test-slug",
+			requestBody: NewComment{
+				Comment: struct {
+					Body     string `json:"body" validate:"required"`
+					ParentID *int64 `json:"parentId"`
+				}{
+					Body:     "This is a reply",
+					ParentID: &parentID,
+				},
+			},
+			setupAuth: func(r *http.Request) *http.Request {
+				ctx := context.WithValue(r.Context(), middleware.UserIDContextKey, int64(1))
+				return r.WithContext(ctx)
+			},
+			setupMock: func() *MockCommentService {
+				return &MockCommentService{
+					createCommentFunc: func(ctx context.Context, userID int64, slug string, body string, parentID *int64) (*service.Comment, error) {
+						if parentID == nil || *parentID != 1 {
+							t.Errorf("Expected a reply to comment 1, got %v", parentID)
+						}
+
+						parent := int(*parentID)
+						return &service.Comment{
+							ID:       2,
+							Body:     body,
+							Author:   service.Profile{Username: "testuser"},
+							ParentID: &parent,
+							Depth:    1,
+						}, nil
+					},
+				}
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: CommentResponse{
+				Comment: service.Comment{
+					ID:       2,
+					Body:     "This is a reply",
+					Author:   service.Profile{Username: "testuser"},
+					ParentID: &parentCommentID,
+					Depth:    1,
+				},
+			},
+		},
+		{
+			name: "Parent comment not found",
+			slug: "test-slug",
+			requestBody: NewComment{
+				Comment: struct {
+					Body     string `json:"body" validate:"required"`
+					ParentID *int64 `json:"parentId"`
+				}{
+					Body:     "This is a reply",
+					ParentID: &parentID,
+				},
+			},
+			setupAuth: func(r *http.Request) *http.Request {
+				ctx := context.WithValue(r.Context(), middleware.UserIDContextKey, int64(1))
+				return r.WithContext(ctx)
+			},
+			setupMock: func() *MockCommentService {
+				return &MockCommentService{
+					createCommentFunc: func(ctx context.Context, userID int64, slug string, body string, parentID *int64) (*service.Comment, error) {
+						return nil, service.ErrParentCommentNotFound
+					},
+				}
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Parent comment not found"}},
+			},
+		},
+		{
+			name: "Reply nested too deeply",
+			slug: "test-slug",
+			requestBody: NewComment{
+				Comment: struct {
+					Body     string `json:"body" validate:"required"`
+					ParentID *int64 `json:"parentId"`
+				}{
+					Body:     "This is a reply",
+					ParentID: &parentID,
+				},
+			},
+			setupAuth: func(r *http.Request) *http.Request {
+				ctx := context.WithValue(r.Context(), middleware.UserIDContextKey, int64(1))
+				return r.WithContext(ctx)
+			},
+			setupMock: func() *MockCommentService {
+				return &MockCommentService{
+					createCommentFunc: func(ctx context.Context, userID int64, slug string, body string, parentID *int64) (*service.Comment, error) {
+						return nil, service.ErrCommentTooDeep
+					},
+				}
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Replies cannot be nested this deeply"}},
+			},
+		},
 		{
 			name: "Internal server error",
 			slug: "test-slug",
 			requestBody: NewComment{
 				Comment: struct {
-					Body string `json:"body" validate:"required"`
+					Body     string `json:"body" validate:"required"`
+					ParentID *int64 `json:"parentId"`
 				}{
 					Body: "This is a test comment",
 				},
@@ -516,7 +868,7 @@ func TestCommentHandler_CreateComment(t *testing.T) {
 			},
 			setupMock: func() *MockCommentService {
 				mockService := &MockCommentService{
-					createCommentFunc: func(ctx context.Context, userID int64, slug string, body string) (*service.Comment, error) {
+					createCommentFunc: func(ctx context.Context, userID int64, slug string, body string, parentID *int64) (*service.Comment, error) {
 						return nil, service.ErrInternalServer
 					},
 				}
@@ -540,7 +892,7 @@ func TestCommentHandler_CreateComment(t *testing.T) {
 			mockService := tt.setupMock()
 
 			// Create Handler
-			handler := NewCommentHandler(mockService)
+			handler := NewCommentHandler(mockService, testCursors)
 
 			var bodyBytes []byte
 			switch v := tt.requestBody.(type) {
@@ -752,6 +1104,32 @@ func TestCommentHandler_DeleteComment(t *testing.T) {
 				}{Body: []string{"Comment not found"}},
 			},
 		},
+		{
+			name:      "Article moved",
+			slug:      "old-slug",
+			commentID: "1",
+			setupAuth: func(r *http.Request) *http.Request {
+				r.Header.Set("Authorization", "Token jwt.token.here")
+				ctx := r.Context()
+				ctx = context.WithValue(ctx, middleware.UserIDContextKey, int64(1))
+				r = r.WithContext(ctx)
+				return r
+			},
+			setupMock: func() *MockCommentService {
+				mockService := &MockCommentService{
+					deleteCommentFunc: func(ctx context.Context, userID int64, slug string, commentID int64) error {
+						return &service.ArticleMovedError{Slug: "test-slug"}
+					},
+				}
+				return mockService
+			},
+			expectedStatus: http.StatusPermanentRedirect,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Article has moved"}},
+			},
+		},
 		{
 			name:      "Internal server error",
 			slug:      "test-slug",
@@ -789,7 +1167,7 @@ func TestCommentHandler_DeleteComment(t *testing.T) {
 			mockService := tt.setupMock()
 
 			// Create Handler
-			handler := NewCommentHandler(mockService)
+			handler := NewCommentHandler(mockService, testCursors)
 
 			// Create Request
 			req := httptest.NewRequest(
@@ -830,3 +1208,382 @@ func TestCommentHandler_DeleteComment(t *testing.T) {
 		})
 	}
 }
+
+// TestCommentHandler_UpdateComment tests the UpdateComment method of the CommentHandler
+func TestCommentHandler_UpdateComment(t *testing.T) {
+	t.Parallel()
+
+	authenticated := func(r *http.Request) *http.Request {
+		r.Header.Set("Authorization", "Token jwt.token.here")
+		return r.WithContext(context.WithValue(r.Context(), middleware.UserIDContextKey, int64(1)))
+	}
+
+	tests := []struct {
+		name             string
+		commentID        string
+		requestBody      any
+		setupAuth        func(r *http.Request) *http.Request
+		setupMock        func() *MockCommentService
+		expectedStatus   int
+		expectedResponse any
+	}{
+		{
+			name:      "Successfully update comment",
+			commentID: "1",
+			requestBody: map[string]any{
+				"comment": map[string]any{
+					"body": "Edited comment",
+				},
+			},
+			setupAuth: authenticated,
+			setupMock: func() *MockCommentService {
+				return &MockCommentService{
+					updateCommentFunc: func(ctx context.Context, userID int64, slug string, commentID int64, body string) (*service.Comment, error) {
+						if userID != 1 || slug != "test-slug" || commentID != 1 {
+							t.Errorf("Expected comment 1 of 'test-slug' by user 1, got %d of %q by %d", commentID, slug, userID)
+						}
+						if body != "Edited comment" {
+							t.Errorf("Expected body 'Edited comment', got %q", body)
+						}
+						return &service.Comment{
+							ID:     1,
+							Body:   body,
+							Author: service.Profile{Username: "testuser"},
+							Edited: true,
+						}, nil
+					},
+				}
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: CommentResponse{
+				Comment: service.Comment{
+					ID:     1,
+					Body:   "Edited comment",
+					Author: service.Profile{Username: "testuser"},
+					Edited: true,
+				},
+			},
+		},
+		{
+			name:      "Unauthenticated request",
+			commentID: "1",
+			requestBody: map[string]any{
+				"comment": map[string]any{
+					"body": "Edited comment",
+				},
+			},
+			setupAuth: func(r *http.Request) *http.Request {
+				return r
+			},
+			setupMock: func() *MockCommentService {
+				return &MockCommentService{
+					updateCommentFunc: func(ctx context.Context, userID int64, slug string, commentID int64, body string) (*service.Comment, error) {
+						t.Errorf("UpdateComment should not be called for unauthenticated request")
+						return nil, nil
+					},
+				}
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Unauthorized"}},
+			},
+		},
+		{
+			name:      "Invalid comment ID",
+			commentID: "invalid-comment-id",
+			requestBody: map[string]any{
+				"comment": map[string]any{
+					"body": "Edited comment",
+				},
+			},
+			setupAuth: authenticated,
+			setupMock: func() *MockCommentService {
+				return &MockCommentService{
+					updateCommentFunc: func(ctx context.Context, userID int64, slug string, commentID int64, body string) (*service.Comment, error) {
+						t.Errorf("UpdateComment should not be called for invalid comment ID")
+						return nil, nil
+					},
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Invalid comment ID"}},
+			},
+		},
+		{
+			name:      "Empty body",
+			commentID: "1",
+			requestBody: map[string]any{
+				"comment": map[string]any{
+					"body": "",
+				},
+			},
+			setupAuth: authenticated,
+			setupMock: func() *MockCommentService {
+				return &MockCommentService{
+					updateCommentFunc: func(ctx context.Context, userID int64, slug string, commentID int64, body string) (*service.Comment, error) {
+						t.Errorf("UpdateComment should not be called for an empty body")
+						return nil, nil
+					},
+				}
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Body is required"}},
+			},
+		},
+		{
+			name:      "Not the author of the comment",
+			commentID: "1",
+			requestBody: map[string]any{
+				"comment": map[string]any{
+					"body": "Edited comment",
+				},
+			},
+			setupAuth: authenticated,
+			setupMock: func() *MockCommentService {
+				return &MockCommentService{
+					updateCommentFunc: func(ctx context.Context, userID int64, slug string, commentID int64, body string) (*service.Comment, error) {
+						return nil, service.ErrCommentNotAuthorized
+					},
+				}
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"You are not the author of this comment"}},
+			},
+		},
+		{
+			name:      "Edit window closed",
+			commentID: "1",
+			requestBody: map[string]any{
+				"comment": map[string]any{
+					"body": "Edited comment",
+				},
+			},
+			setupAuth: authenticated,
+			setupMock: func() *MockCommentService {
+				return &MockCommentService{
+					updateCommentFunc: func(ctx context.Context, userID int64, slug string, commentID int64, body string) (*service.Comment, error) {
+						return nil, service.ErrCommentEditClosed
+					},
+				}
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"This comment can no longer be edited"}},
+			},
+		},
+		{
+			name:      "Comment not found",
+			commentID: "1",
+			requestBody: map[string]any{
+				"comment": map[string]any{
+					"body": "Edited comment",
+				},
+			},
+			setupAuth: authenticated,
+			setupMock: func() *MockCommentService {
+				return &MockCommentService{
+					updateCommentFunc: func(ctx context.Context, userID int64, slug string, commentID int64, body string) (*service.Comment, error) {
+						return nil, service.ErrCommentNotFound
+					},
+				}
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Comment not found"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup Mock Service
+			mockService := tt.setupMock()
+
+			// Create Handler
+			handler := NewCommentHandler(mockService, testCursors)
+
+			// Create Request
+			body, err := json.Marshal(tt.requestBody)
+			if err != nil {
+				t.Fatalf("Failed to marshal request body: %v", err)
+			}
+			req := httptest.NewRequest(
+				http.MethodPut,
+				"/api/articles/test-slug/comments/"+tt.commentID,
+				bytes.NewBuffer(body),
+			)
+			req.SetPathValue("slug", "test-slug")
+			req.SetPathValue("id", tt.commentID)
+			req = tt.setupAuth(req)
+
+			// Create Response Recorder
+			rr := httptest.NewRecorder()
+
+			// Serve Request
+			handler.UpdateComment()(rr, req)
+
+			// Check Status Code
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+
+			// Check Response Body
+			var got any
+			if tt.expectedStatus == http.StatusOK {
+				var resp CommentResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				got = resp
+			} else {
+				var resp response.GenericErrorModel
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				got = resp
+			}
+
+			// Deep compare expected and got
+			if !reflect.DeepEqual(got, tt.expectedResponse) {
+				t.Errorf("Response body: got %v, want %v", got, tt.expectedResponse)
+			}
+		})
+	}
+}
+
+// TestCommentHandler_GetCommentVersions tests the GetCommentVersions method of the
+// CommentHandler
+func TestCommentHandler_GetCommentVersions(t *testing.T) {
+	t.Parallel()
+
+	writtenAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	replacedAt := writtenAt.Add(5 * time.Minute)
+
+	tests := []struct {
+		name             string
+		setupMock        func() *MockCommentService
+		expectedStatus   int
+		expectedResponse any
+	}{
+		{
+			name: "Moderator gets the versions",
+			setupMock: func() *MockCommentService {
+				return &MockCommentService{
+					getCommentVersionsFunc: func(ctx context.Context, userID int64, slug string, commentID int64) ([]service.CommentVersion, error) {
+						return []service.CommentVersion{
+							{Body: "Original comment", WrittenAt: writtenAt, ReplacedAt: replacedAt},
+						}, nil
+					},
+				}
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: CommentVersionsResponse{
+				Versions: []service.CommentVersion{
+					{Body: "Original comment", WrittenAt: writtenAt, ReplacedAt: replacedAt},
+				},
+			},
+		},
+		{
+			name: "Not a moderator",
+			setupMock: func() *MockCommentService {
+				return &MockCommentService{
+					getCommentVersionsFunc: func(ctx context.Context, userID int64, slug string, commentID int64) ([]service.CommentVersion, error) {
+						return nil, service.ErrNotModerator
+					},
+				}
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Only moderators can see earlier versions of comments"}},
+			},
+		},
+		{
+			name: "Comment not found",
+			setupMock: func() *MockCommentService {
+				return &MockCommentService{
+					getCommentVersionsFunc: func(ctx context.Context, userID int64, slug string, commentID int64) ([]service.CommentVersion, error) {
+						return nil, service.ErrCommentNotFound
+					},
+				}
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Comment not found"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Create Handler
+			handler := NewCommentHandler(tt.setupMock(), testCursors)
+
+			// Create Request
+			req := httptest.NewRequest(
+				http.MethodGet,
+				"/api/articles/test-slug/comments/1/versions",
+				nil,
+			)
+			req.SetPathValue("slug", "test-slug")
+			req.SetPathValue("id", "1")
+			req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDContextKey, int64(1)))
+
+			// Create Response Recorder
+			rr := httptest.NewRecorder()
+
+			// Serve Request
+			handler.GetCommentVersions()(rr, req)
+
+			// Check Status Code
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+
+			// Check Response Body
+			var got any
+			if tt.expectedStatus == http.StatusOK {
+				var resp CommentVersionsResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				got = resp
+			} else {
+				var resp response.GenericErrorModel
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				got = resp
+			}
+
+			// Deep compare expected and got
+			if !reflect.DeepEqual(got, tt.expectedResponse) {
+				t.Errorf("Response body: got %v, want %v", got, tt.expectedResponse)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Nilesh2000/conduit/internal/jwtkeys"
+	"github.com/Nilesh2000/conduit/internal/response"
+)
+
+// KeySetProvider defines the interface for the public keys that verify access tokens
+type KeySetProvider interface {
+	JWKS() jwtkeys.JWKSet
+}
+
+// jwksHandler handles JSON Web Key Set HTTP requests
+type jwksHandler struct {
+	keys KeySetProvider
+}
+
+// NewJWKSHandler creates a new JWKSHandler
+func NewJWKSHandler(keys KeySetProvider) *jwksHandler {
+	return &jwksHandler{keys: keys}
+}
+
+// GetJWKS returns a handler function that publishes the public keys as a JSON Web Key Set
+func (h *jwksHandler) GetJWKS() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Set the content type to JSON and let clients cache the keys for a while
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+
+		// Respond with the key set
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(h.keys.JWKS()); err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusInternalServerError,
+				[]string{"Internal server error"},
+			)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Nilesh2000/conduit/internal/jwtkeys"
+)
+
+// MockKeySetProvider is a mock implementation of the KeySetProvider interface
+type MockKeySetProvider struct {
+	jwksFunc func() jwtkeys.JWKSet
+}
+
+// JWKS returns the key set from the mock provider
+func (m *MockKeySetProvider) JWKS() jwtkeys.JWKSet {
+	return m.jwksFunc()
+}
+
+func TestJWKSHandler_GetJWKS(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		keySet       jwtkeys.JWKSet
+		expectedKids []string
+	}{
+		{
+			name: "Signing and rotated keys",
+			keySet: jwtkeys.JWKSet{Keys: []jwtkeys.JWK{
+				{Kty: "OKP", Use: "sig", Alg: "EdDSA", Kid: "current", Crv: "Ed25519", X: "x"},
+				{Kty: "RSA", Use: "sig", Alg: "RS256", Kid: "previous", N: "n", E: "AQAB"},
+			}},
+			expectedKids: []string{"current", "previous"},
+		},
+		{
+			name:         "HS256 only",
+			keySet:       jwtkeys.JWKSet{Keys: []jwtkeys.JWK{}},
+			expectedKids: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup handler with mock provider
+			handler := NewJWKSHandler(&MockKeySetProvider{
+				jwksFunc: func() jwtkeys.JWKSet { return tt.keySet },
+			})
+
+			// Create request and response recorder
+			req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+			rr := httptest.NewRecorder()
+
+			// Serve request
+			handler.GetJWKS()(rr, req)
+
+			// Check status code and headers
+			if got, want := rr.Code, http.StatusOK; got != want {
+				t.Errorf("Status code: got %v, want %v", got, want)
+			}
+			if got := rr.Header().Get("Content-Type"); got != "application/json" {
+				t.Errorf("Expected content type 'application/json', got '%s'", got)
+			}
+
+			// Check response body
+			var got jwtkeys.JWKSet
+			if err := json.NewDecoder(rr.Body).Decode(&got); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if got.Keys == nil {
+				t.Fatal("Expected keys to be an array")
+			}
+			if len(got.Keys) != len(tt.expectedKids) {
+				t.Fatalf("Expected %d keys, got %d", len(tt.expectedKids), len(got.Keys))
+			}
+			for i, kid := range tt.expectedKids {
+				if got.Keys[i].Kid != kid {
+					t.Errorf("Key %d: expected kid %q, got %q", i, kid, got.Keys[i].Kid)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Nilesh2000/conduit/internal/middleware"
+	"github.com/Nilesh2000/conduit/internal/response"
+	"github.com/Nilesh2000/conduit/internal/service"
+)
+
+// MutesResponse is the response body for the users and tags the current user muted
+type MutesResponse struct {
+	Mutes service.Mutes `json:"mutes"`
+}
+
+// MutedTagResponse is the response body for a muted or unmuted tag
+type MutedTagResponse struct {
+	Tag service.MutedTag `json:"tag"`
+}
+
+// MuteService is an interface for the mute service
+type MuteService interface {
+	MuteUser(ctx context.Context, muterID int64, mutedName string) (*service.Profile, error)
+	UnmuteUser(ctx context.Context, muterID int64, mutedName string) (*service.Profile, error)
+	MuteTag(ctx context.Context, userID int64, tag string) (*service.MutedTag, error)
+	UnmuteTag(ctx context.Context, userID int64, tag string) (*service.MutedTag, error)
+	GetMutes(ctx context.Context, userID int64) (*service.Mutes, error)
+}
+
+// muteHandler is a handler for mute requests
+type muteHandler struct {
+	muteService MuteService
+}
+
+// NewMuteHandler creates a new mute handler
+func NewMuteHandler(muteService MuteService) *muteHandler {
+	return &muteHandler{muteService: muteService}
+}
+
+// MuteUser is a handler for muting a user
+func (h *muteHandler) MuteUser() http.HandlerFunc {
+	return h.setUserMuted(h.muteService.MuteUser)
+}
+
+// UnmuteUser is a handler for unmuting a user
+func (h *muteHandler) UnmuteUser() http.HandlerFunc {
+	return h.setUserMuted(h.muteService.UnmuteUser)
+}
+
+// setUserMuted is a handler for muting or unmuting a user with set
+func (h *muteHandler) setUserMuted(
+	set func(ctx context.Context, muterID int64, mutedName string) (*service.Profile, error),
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Set the content type to JSON
+		w.Header().Set("Content-Type", "application/json")
+
+		// Get user ID from context
+		muterID, ok := middleware.GetUserIDFromContext(r.Context())
+		if !ok {
+			response.RespondWithError(w, http.StatusUnauthorized, []string{"Unauthorized"})
+			return
+		}
+
+		// Get username from URL path
+		username := r.PathValue("username")
+
+		profile, err := set(r.Context(), muterID, username)
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrUserNotFound):
+				response.RespondWithError(w, http.StatusNotFound, []string{"User not found"})
+			case errors.Is(err, service.ErrCannotMuteSelf):
+				response.RespondWithError(
+					w,
+					http.StatusBadRequest,
+					[]string{"Cannot mute yourself"},
+				)
+			default:
+				response.RespondWithError(
+					w,
+					http.StatusInternalServerError,
+					[]string{"Internal server error"},
+				)
+			}
+			return
+		}
+
+		// Respond with updated profile
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(ProfileResponse{Profile: *profile}); err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusInternalServerError,
+				[]string{"Internal server error"},
+			)
+		}
+	}
+}
+
+// MuteTag is a handler for muting a tag
+func (h *muteHandler) MuteTag() http.HandlerFunc {
+	return h.setTagMuted(h.muteService.MuteTag)
+}
+
+// UnmuteTag is a handler for unmuting a tag
+func (h *muteHandler) UnmuteTag() http.HandlerFunc {
+	return h.setTagMuted(h.muteService.UnmuteTag)
+}
+
+// setTagMuted is a handler for muting or unmuting a tag with set
+func (h *muteHandler) setTagMuted(
+	set func(ctx context.Context, userID int64, tag string) (*service.MutedTag, error),
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Set the content type to JSON
+		w.Header().Set("Content-Type", "application/json")
+
+		// Get user ID from context
+		userID, ok := middleware.GetUserIDFromContext(r.Context())
+		if !ok {
+			response.RespondWithError(w, http.StatusUnauthorized, []string{"Unauthorized"})
+			return
+		}
+
+		// Get tag from URL path
+		tag := r.PathValue("tag")
+
+		mutedTag, err := set(r.Context(), userID, tag)
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrInvalidTag):
+				response.RespondWithError(
+					w,
+					http.StatusUnprocessableEntity,
+					[]string{"Tag can't be blank"},
+				)
+			case errors.Is(err, service.ErrUserNotFound):
+				response.RespondWithError(w, http.StatusNotFound, []string{"User not found"})
+			default:
+				response.RespondWithError(
+					w,
+					http.StatusInternalServerError,
+					[]string{"Internal server error"},
+				)
+			}
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(MutedTagResponse{Tag: *mutedTag}); err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusInternalServerError,
+				[]string{"Internal server error"},
+			)
+		}
+	}
+}
+
+// GetMutes is a handler for listing the users and tags the current user muted
+func (h *muteHandler) GetMutes() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Set the content type to JSON
+		w.Header().Set("Content-Type", "application/json")
+
+		// Get user ID from context
+		userID, ok := middleware.GetUserIDFromContext(r.Context())
+		if !ok {
+			response.RespondWithError(w, http.StatusUnauthorized, []string{"Unauthorized"})
+			return
+		}
+
+		mutes, err := h.muteService.GetMutes(r.Context(), userID)
+		if err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusInternalServerError,
+				[]string{"Internal server error"},
+			)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(MutesResponse{Mutes: *mutes}); err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusInternalServerError,
+				[]string{"Internal server error"},
+			)
+		}
+	}
+}
@@ -0,0 +1,344 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/Nilesh2000/conduit/internal/middleware"
+	"github.com/Nilesh2000/conduit/internal/response"
+	"github.com/Nilesh2000/conduit/internal/service"
+)
+
+// MockMuteService is a mock implementation of the MuteService interface
+type MockMuteService struct {
+	muteUserFunc   func(ctx context.Context, muterID int64, mutedName string) (*service.Profile, error)
+	unmuteUserFunc func(ctx context.Context, muterID int64, mutedName string) (*service.Profile, error)
+	muteTagFunc    func(ctx context.Context, userID int64, tag string) (*service.MutedTag, error)
+	unmuteTagFunc  func(ctx context.Context, userID int64, tag string) (*service.MutedTag, error)
+	getMutesFunc   func(ctx context.Context, userID int64) (*service.Mutes, error)
+}
+
+// MuteUser mutes a user in the mock service
+func (m *MockMuteService) MuteUser(
+	ctx context.Context,
+	muterID int64,
+	mutedName string,
+) (*service.Profile, error) {
+	return m.muteUserFunc(ctx, muterID, mutedName)
+}
+
+// UnmuteUser unmutes a user in the mock service
+func (m *MockMuteService) UnmuteUser(
+	ctx context.Context,
+	muterID int64,
+	mutedName string,
+) (*service.Profile, error) {
+	return m.unmuteUserFunc(ctx, muterID, mutedName)
+}
+
+// MuteTag mutes a tag in the mock service
+func (m *MockMuteService) MuteTag(
+	ctx context.Context,
+	userID int64,
+	tag string,
+) (*service.MutedTag, error) {
+	return m.muteTagFunc(ctx, userID, tag)
+}
+
+// UnmuteTag unmutes a tag in the mock service
+func (m *MockMuteService) UnmuteTag(
+	ctx context.Context,
+	userID int64,
+	tag string,
+) (*service.MutedTag, error) {
+	return m.unmuteTagFunc(ctx, userID, tag)
+}
+
+// GetMutes gets the mutes of a user in the mock service
+func (m *MockMuteService) GetMutes(ctx context.Context, userID int64) (*service.Mutes, error) {
+	return m.getMutesFunc(ctx, userID)
+}
+
+func TestMuteHandler_MuteUser(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		username         string
+		serviceErr       error
+		expectedStatus   int
+		expectedResponse any
+	}{
+		{
+			name:           "User muted",
+			username:       "noisy",
+			expectedStatus: http.StatusOK,
+			expectedResponse: ProfileResponse{
+				Profile: service.Profile{Username: "noisy", Following: true, Muting: true},
+			},
+		},
+		{
+			name:           "Muting yourself",
+			username:       "testuser",
+			serviceErr:     service.ErrCannotMuteSelf,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Cannot mute yourself"}},
+			},
+		},
+		{
+			name:           "User not found",
+			username:       "nobody",
+			serviceErr:     service.ErrUserNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"User not found"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup Mock
+			mockService := &MockMuteService{
+				muteUserFunc: func(ctx context.Context, muterID int64, mutedName string) (*service.Profile, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &service.Profile{Username: mutedName, Following: true, Muting: true}, nil
+				},
+			}
+
+			// Create Handler
+			handler := NewMuteHandler(mockService)
+
+			// Create Request
+			req := httptest.NewRequest(http.MethodPost, "/api/profiles/"+tt.username+"/mute", nil)
+			req.SetPathValue("username", tt.username)
+			ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, int64(1))
+			req = req.WithContext(ctx)
+
+			// Create Response Recorder
+			rr := httptest.NewRecorder()
+
+			// Serve Request
+			handler.MuteUser()(rr, req)
+
+			// Check Status Code
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("Status code: got %v, want %v", status, tt.expectedStatus)
+			}
+
+			// Check Response Body
+			var got any
+			if tt.expectedStatus == http.StatusOK {
+				var resp ProfileResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				got = resp
+			} else {
+				var resp response.GenericErrorModel
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				got = resp
+			}
+
+			// Deep compare expected and got
+			if !reflect.DeepEqual(got, tt.expectedResponse) {
+				t.Errorf("Response body: got %v, want %v", got, tt.expectedResponse)
+			}
+		})
+	}
+}
+
+func TestMuteHandler_MuteTag(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		tag              string
+		serviceErr       error
+		expectedStatus   int
+		expectedResponse any
+	}{
+		{
+			name:           "Tag muted",
+			tag:            "crypto",
+			expectedStatus: http.StatusOK,
+			expectedResponse: MutedTagResponse{
+				Tag: service.MutedTag{Name: "crypto", Muting: true},
+			},
+		},
+		{
+			name:           "Blank tag",
+			tag:            " ",
+			serviceErr:     service.ErrInvalidTag,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Tag can't be blank"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup Mock
+			mockService := &MockMuteService{
+				muteTagFunc: func(ctx context.Context, userID int64, tag string) (*service.MutedTag, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &service.MutedTag{Name: tag, Muting: true}, nil
+				},
+			}
+
+			// Create Handler
+			handler := NewMuteHandler(mockService)
+
+			// Create Request
+			req := httptest.NewRequest(http.MethodPost, "/api/tags/tag/mute", nil)
+			req.SetPathValue("tag", tt.tag)
+			ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, int64(1))
+			req = req.WithContext(ctx)
+
+			// Create Response Recorder
+			rr := httptest.NewRecorder()
+
+			// Serve Request
+			handler.MuteTag()(rr, req)
+
+			// Check Status Code
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("Status code: got %v, want %v", status, tt.expectedStatus)
+			}
+
+			// Check Response Body
+			var got any
+			if tt.expectedStatus == http.StatusOK {
+				var resp MutedTagResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				got = resp
+			} else {
+				var resp response.GenericErrorModel
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				got = resp
+			}
+
+			// Deep compare expected and got
+			if !reflect.DeepEqual(got, tt.expectedResponse) {
+				t.Errorf("Response body: got %v, want %v", got, tt.expectedResponse)
+			}
+		})
+	}
+}
+
+func TestMuteHandler_GetMutes(t *testing.T) {
+	t.Parallel()
+
+	mutes := service.Mutes{
+		Profiles: []service.Profile{{Username: "noisy", Muting: true}},
+		Tags:     []string{"crypto"},
+	}
+
+	tests := []struct {
+		name             string
+		authenticated    bool
+		expectedStatus   int
+		expectedResponse any
+	}{
+		{
+			name:             "Mutes of the current user",
+			authenticated:    true,
+			expectedStatus:   http.StatusOK,
+			expectedResponse: MutesResponse{Mutes: mutes},
+		},
+		{
+			name:           "Unauthenticated request",
+			authenticated:  false,
+			expectedStatus: http.StatusUnauthorized,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Unauthorized"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup Mock
+			mockService := &MockMuteService{
+				getMutesFunc: func(ctx context.Context, userID int64) (*service.Mutes, error) {
+					return &mutes, nil
+				},
+			}
+
+			// Create Handler
+			handler := NewMuteHandler(mockService)
+
+			// Create Request
+			req := httptest.NewRequest(http.MethodGet, "/api/user/mutes", nil)
+			if tt.authenticated {
+				ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, int64(1))
+				req = req.WithContext(ctx)
+			}
+
+			// Create Response Recorder
+			rr := httptest.NewRecorder()
+
+			// Serve Request
+			handler.GetMutes()(rr, req)
+
+			// Check Status Code
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("Status code: got %v, want %v", status, tt.expectedStatus)
+			}
+
+			// Check Response Body
+			var got any
+			if tt.expectedStatus == http.StatusOK {
+				var resp MutesResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				got = resp
+			} else {
+				var resp response.GenericErrorModel
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				got = resp
+			}
+
+			// Deep compare expected and got
+			if !reflect.DeepEqual(got, tt.expectedResponse) {
+				t.Errorf("Response body: got %v, want %v", got, tt.expectedResponse)
+			}
+		})
+	}
+}
@@ -0,0 +1,330 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Nilesh2000/conduit/internal/cursor"
+	"github.com/Nilesh2000/conduit/internal/middleware"
+	"github.com/Nilesh2000/conduit/internal/repository"
+	"github.com/Nilesh2000/conduit/internal/response"
+	"github.com/Nilesh2000/conduit/internal/service"
+)
+
+// NotificationsResponse is the response body for a page of the notifications of the
+// current user
+type NotificationsResponse struct {
+	Notifications      []service.Notification `json:"notifications"`
+	NotificationsCount int                    `json:"notificationsCount"`
+	UnreadCount        int                    `json:"unreadCount"`
+	NextCursor         string                 `json:"nextCursor,omitempty"`
+	PrevCursor         string                 `json:"prevCursor,omitempty"`
+}
+
+// MarkAllReadResponse is the response body for marking every notification as read
+type MarkAllReadResponse struct {
+	Marked int `json:"marked"`
+}
+
+// NotificationPreferencesResponse is the response body for the notification preferences
+// of the current user
+type NotificationPreferencesResponse struct {
+	Preferences service.NotificationPreferences `json:"preferences"`
+}
+
+// UpdateNotificationPreferencesRequest is the request body for changing the notification
+// preferences of the current user. Types left out are not changed.
+type UpdateNotificationPreferencesRequest struct {
+	Preferences struct {
+		Follow   *bool `json:"follow"`
+		Favorite *bool `json:"favorite"`
+		Comment  *bool `json:"comment"`
+	} `json:"preferences"`
+}
+
+// NotificationService is an interface for the notification service
+type NotificationService interface {
+	ListNotifications(
+		ctx context.Context,
+		userID int64,
+		filters repository.NotificationFilters,
+	) (*service.NotificationList, error)
+	MarkRead(ctx context.Context, userID, notificationID int64) error
+	MarkAllRead(ctx context.Context, userID int64) (int, error)
+	GetPreferences(ctx context.Context, userID int64) (*service.NotificationPreferences, error)
+	UpdatePreferences(
+		ctx context.Context,
+		userID int64,
+		follow, favorite, comment *bool,
+	) (*service.NotificationPreferences, error)
+}
+
+// notificationHandler is a handler for notification requests
+type notificationHandler struct {
+	notificationService NotificationService
+	cursors             *cursor.Signer
+}
+
+// NewNotificationHandler creates a new notification handler that signs the cursors of
+// notification lists with cursors
+func NewNotificationHandler(
+	notificationService NotificationService,
+	cursors *cursor.Signer,
+) *notificationHandler {
+	return &notificationHandler{
+		notificationService: notificationService,
+		cursors:             cursors,
+	}
+}
+
+// ListNotifications is a handler for listing a page of the notifications of the current
+// user, newest first
+func (h *notificationHandler) ListNotifications() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Set the content type to JSON
+		w.Header().Set("Content-Type", "application/json")
+
+		// Get user ID from context
+		userID, ok := middleware.GetUserIDFromContext(r.Context())
+		if !ok {
+			response.RespondWithError(w, http.StatusUnauthorized, []string{"Unauthorized"})
+			return
+		}
+
+		filters := repository.NotificationFilters{
+			Limit: 20, // Default limit
+		}
+
+		// Parse limit parameter
+		if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+			limit, err := strconv.Atoi(limitStr)
+			if err != nil || limit <= 0 {
+				response.RespondWithError(
+					w,
+					http.StatusUnprocessableEntity,
+					[]string{"Limit must be a positive integer"},
+				)
+				return
+			}
+			filters.Limit = limit
+		}
+
+		// Parse unread parameter
+		if unreadStr := r.URL.Query().Get("unread"); unreadStr != "" {
+			unread, err := strconv.ParseBool(unreadStr)
+			if err != nil {
+				response.RespondWithError(
+					w,
+					http.StatusUnprocessableEntity,
+					[]string{"Unread must be true or false"},
+				)
+				return
+			}
+			filters.Unread = unread
+		}
+
+		// Parse cursor parameters
+		if filters.After, filters.Before, ok = parseCursors(w, r, h.cursors); !ok {
+			return
+		}
+
+		result, err := h.notificationService.ListNotifications(r.Context(), userID, filters)
+		if err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusInternalServerError,
+				[]string{"Internal server error"},
+			)
+			return
+		}
+
+		resp := NotificationsResponse{
+			Notifications:      result.Notifications,
+			NotificationsCount: result.Count,
+			UnreadCount:        result.UnreadCount,
+		}
+		page := listPage{
+			after:   filters.After,
+			before:  filters.Before,
+			first:   result.First,
+			last:    result.Last,
+			hasMore: result.HasMore,
+		}
+		resp.PrevCursor, resp.NextCursor = page.cursors(h.cursors)
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusInternalServerError,
+				[]string{"Internal server error"},
+			)
+		}
+	}
+}
+
+// MarkRead is a handler for marking a notification of the current user as read
+func (h *notificationHandler) MarkRead() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get user ID from context
+		userID, ok := middleware.GetUserIDFromContext(r.Context())
+		if !ok {
+			response.RespondWithError(w, http.StatusUnauthorized, []string{"Unauthorized"})
+			return
+		}
+
+		// Get notification ID from URL path
+		notificationID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusBadRequest,
+				[]string{"Invalid notification ID"},
+			)
+			return
+		}
+
+		if err := h.notificationService.MarkRead(r.Context(), userID, notificationID); err != nil {
+			switch {
+			case errors.Is(err, service.ErrNotificationNotFound):
+				response.RespondWithError(
+					w,
+					http.StatusNotFound,
+					[]string{"Notification not found"},
+				)
+			default:
+				response.RespondWithError(
+					w,
+					http.StatusInternalServerError,
+					[]string{"Internal server error"},
+				)
+			}
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// MarkAllRead is a handler for marking every notification of the current user as read
+func (h *notificationHandler) MarkAllRead() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Set the content type to JSON
+		w.Header().Set("Content-Type", "application/json")
+
+		// Get user ID from context
+		userID, ok := middleware.GetUserIDFromContext(r.Context())
+		if !ok {
+			response.RespondWithError(w, http.StatusUnauthorized, []string{"Unauthorized"})
+			return
+		}
+
+		marked, err := h.notificationService.MarkAllRead(r.Context(), userID)
+		if err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusInternalServerError,
+				[]string{"Internal server error"},
+			)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(MarkAllReadResponse{Marked: marked}); err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusInternalServerError,
+				[]string{"Internal server error"},
+			)
+		}
+	}
+}
+
+// GetPreferences is a handler for getting the notification preferences of the current
+// user
+func (h *notificationHandler) GetPreferences() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Set the content type to JSON
+		w.Header().Set("Content-Type", "application/json")
+
+		// Get user ID from context
+		userID, ok := middleware.GetUserIDFromContext(r.Context())
+		if !ok {
+			response.RespondWithError(w, http.StatusUnauthorized, []string{"Unauthorized"})
+			return
+		}
+
+		preferences, err := h.notificationService.GetPreferences(r.Context(), userID)
+		h.respondWithPreferences(w, preferences, err)
+	}
+}
+
+// UpdatePreferences is a handler for changing the notification preferences of the
+// current user
+func (h *notificationHandler) UpdatePreferences() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Set the content type to JSON
+		w.Header().Set("Content-Type", "application/json")
+
+		// Get user ID from context
+		userID, ok := middleware.GetUserIDFromContext(r.Context())
+		if !ok {
+			response.RespondWithError(w, http.StatusUnauthorized, []string{"Unauthorized"})
+			return
+		}
+
+		// Parse request body
+		var req UpdateNotificationPreferencesRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusUnprocessableEntity,
+				[]string{"Invalid request body"},
+			)
+			return
+		}
+
+		preferences, err := h.notificationService.UpdatePreferences(
+			r.Context(),
+			userID,
+			req.Preferences.Follow,
+			req.Preferences.Favorite,
+			req.Preferences.Comment,
+		)
+		h.respondWithPreferences(w, preferences, err)
+	}
+}
+
+// respondWithPreferences responds with notification preferences or the error reading
+// them
+func (h *notificationHandler) respondWithPreferences(
+	w http.ResponseWriter,
+	preferences *service.NotificationPreferences,
+	err error,
+) {
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUserNotFound):
+			response.RespondWithError(w, http.StatusNotFound, []string{"User not found"})
+		default:
+			response.RespondWithError(
+				w,
+				http.StatusInternalServerError,
+				[]string{"Internal server error"},
+			)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(NotificationPreferencesResponse{Preferences: *preferences}); err != nil {
+		response.RespondWithError(
+			w,
+			http.StatusInternalServerError,
+			[]string{"Internal server error"},
+		)
+	}
+}
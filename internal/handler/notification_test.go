@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Nilesh2000/conduit/internal/middleware"
+	"github.com/Nilesh2000/conduit/internal/repository"
+	"github.com/Nilesh2000/conduit/internal/response"
+	"github.com/Nilesh2000/conduit/internal/service"
+)
+
+// MockNotificationService is a mock implementation of the NotificationService interface
+type MockNotificationService struct {
+	listNotificationsFunc func(ctx context.Context, userID int64, filters repository.NotificationFilters) (*service.NotificationList, error)
+	markReadFunc          func(ctx context.Context, userID, notificationID int64) error
+	markAllReadFunc       func(ctx context.Context, userID int64) (int, error)
+	getPreferencesFunc    func(ctx context.Context, userID int64) (*service.NotificationPreferences, error)
+	updatePreferencesFunc func(ctx context.Context, userID int64, follow, favorite, comment *bool) (*service.NotificationPreferences, error)
+}
+
+// ListNotifications lists the notifications of a user in the mock service
+func (m *MockNotificationService) ListNotifications(
+	ctx context.Context,
+	userID int64,
+	filters repository.NotificationFilters,
+) (*service.NotificationList, error) {
+	return m.listNotificationsFunc(ctx, userID, filters)
+}
+
+// MarkRead marks a notification as read in the mock service
+func (m *MockNotificationService) MarkRead(ctx context.Context, userID, notificationID int64) error {
+	return m.markReadFunc(ctx, userID, notificationID)
+}
+
+// MarkAllRead marks every notification as read in the mock service
+func (m *MockNotificationService) MarkAllRead(ctx context.Context, userID int64) (int, error) {
+	return m.markAllReadFunc(ctx, userID)
+}
+
+// GetPreferences gets the notification preferences of a user in the mock service
+func (m *MockNotificationService) GetPreferences(
+	ctx context.Context,
+	userID int64,
+) (*service.NotificationPreferences, error) {
+	return m.getPreferencesFunc(ctx, userID)
+}
+
+// UpdatePreferences changes the notification preferences of a user in the mock service
+func (m *MockNotificationService) UpdatePreferences(
+	ctx context.Context,
+	userID int64,
+	follow, favorite, comment *bool,
+) (*service.NotificationPreferences, error) {
+	return m.updatePreferencesFunc(ctx, userID, follow, favorite, comment)
+}
+
+// Test_notificationHandler_ListNotifications tests the ListNotifications method of the
+// notificationHandler
+func Test_notificationHandler_ListNotifications(t *testing.T) {
+	t.Parallel()
+
+	createdAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	last := repository.Cursor{CreatedAt: createdAt, ID: 2}
+
+	tests := []struct {
+		name            string
+		query           string
+		expectedStatus  int
+		expectedFilters repository.NotificationFilters
+		expectedErrors  []string
+	}{
+		{
+			name:            "Default page",
+			query:           "",
+			expectedStatus:  http.StatusOK,
+			expectedFilters: repository.NotificationFilters{Limit: 20},
+		},
+		{
+			name:            "Unread page after a cursor",
+			query:           "?limit=1&unread=true&after=" + testCursors.Encode(last),
+			expectedStatus:  http.StatusOK,
+			expectedFilters: repository.NotificationFilters{Limit: 1, Unread: true, After: &last},
+		},
+		{
+			name:           "Invalid limit",
+			query:          "?limit=0",
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedErrors: []string{"Limit must be a positive integer"},
+		},
+		{
+			name:           "Invalid unread",
+			query:          "?unread=maybe",
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedErrors: []string{"Unread must be true or false"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup Mock Service
+			mockService := &MockNotificationService{
+				listNotificationsFunc: func(ctx context.Context, userID int64, filters repository.NotificationFilters) (*service.NotificationList, error) {
+					if !reflect.DeepEqual(filters, tt.expectedFilters) {
+						t.Errorf("Expected filters %+v, got %+v", tt.expectedFilters, filters)
+					}
+					return &service.NotificationList{
+						Notifications: []service.Notification{
+							{ID: 2, Type: repository.NotificationTypeFollow, Actor: service.Profile{Username: "follower"}, CreatedAt: createdAt},
+						},
+						HasMore:     true,
+						First:       &last,
+						Last:        &last,
+						Count:       4,
+						UnreadCount: 2,
+					}, nil
+				},
+			}
+
+			// Create Handler
+			handler := NewNotificationHandler(mockService, testCursors)
+
+			// Create Request
+			req := httptest.NewRequest(http.MethodGet, "/api/notifications"+tt.query, nil)
+			ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, int64(1))
+			req = req.WithContext(ctx)
+
+			// Create Response Recorder
+			rr := httptest.NewRecorder()
+
+			// Serve Request
+			handler.ListNotifications()(rr, req)
+
+			// Check Status Code
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("Status code: got %v, want %v", status, tt.expectedStatus)
+			}
+
+			// Check Response Body
+			if tt.expectedStatus == http.StatusOK {
+				var resp NotificationsResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				if resp.NotificationsCount != 4 || resp.UnreadCount != 2 || len(resp.Notifications) != 1 {
+					t.Errorf("Expected a notification out of 4 with 2 unread, got %+v", resp)
+				}
+				if resp.NextCursor != testCursors.Encode(last) {
+					t.Errorf("Expected next cursor %q, got %q", testCursors.Encode(last), resp.NextCursor)
+				}
+			} else {
+				var resp response.GenericErrorModel
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				if !reflect.DeepEqual(resp.Errors.Body, tt.expectedErrors) {
+					t.Errorf("Expected errors %v, got %v", tt.expectedErrors, resp.Errors.Body)
+				}
+			}
+		})
+	}
+}
+
+// Test_notificationHandler_MarkRead tests the MarkRead method of the notificationHandler
+func Test_notificationHandler_MarkRead(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		id             string
+		serviceErr     error
+		expectedStatus int
+		expectedErrors []string
+	}{
+		{
+			name:           "Notification marked as read",
+			id:             "2",
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "Invalid notification ID",
+			id:             "abc",
+			expectedStatus: http.StatusBadRequest,
+			expectedErrors: []string{"Invalid notification ID"},
+		},
+		{
+			name:           "Notification not found",
+			id:             "3",
+			serviceErr:     service.ErrNotificationNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedErrors: []string{"Notification not found"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup Mock Service
+			mockService := &MockNotificationService{
+				markReadFunc: func(ctx context.Context, userID, notificationID int64) error {
+					return tt.serviceErr
+				},
+			}
+
+			// Create Handler
+			handler := NewNotificationHandler(mockService, testCursors)
+
+			// Create Request
+			req := httptest.NewRequest(http.MethodPost, "/api/notifications/"+tt.id+"/read", nil)
+			req.SetPathValue("id", tt.id)
+			ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, int64(1))
+			req = req.WithContext(ctx)
+
+			// Create Response Recorder
+			rr := httptest.NewRecorder()
+
+			// Serve Request
+			handler.MarkRead()(rr, req)
+
+			// Check Status Code
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("Status code: got %v, want %v", status, tt.expectedStatus)
+			}
+
+			// Check Response Body
+			if tt.expectedErrors != nil {
+				var resp response.GenericErrorModel
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				if !reflect.DeepEqual(resp.Errors.Body, tt.expectedErrors) {
+					t.Errorf("Expected errors %v, got %v", tt.expectedErrors, resp.Errors.Body)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Nilesh2000/conduit/internal/cursor"
+	"github.com/Nilesh2000/conduit/internal/repository"
+	"github.com/Nilesh2000/conduit/internal/response"
+)
+
+// listPage is a page of a list paginated with cursors
+type listPage struct {
+	// after, before and offset are how the page was requested
+	after, before *repository.Cursor
+	offset        int
+	// first and last are the positions of the first and last items of the page, which
+	// are nil if it is empty
+	first, last *repository.Cursor
+	// hasMore reports whether more items follow the page in the direction it was read in
+	hasMore bool
+}
+
+// cursors returns the cursors of the pages right before and right after the page, or
+// empty strings if there are none
+func (p listPage) cursors(signer *cursor.Signer) (prev, next string) {
+	if p.first == nil {
+		return "", ""
+	}
+
+	// A page before a cursor is read backwards from it, so there is at least the
+	// item at the cursor after it
+	hasPrev := p.after != nil || p.offset > 0
+	hasNext := p.hasMore
+	if p.before != nil {
+		hasPrev, hasNext = p.hasMore, true
+	}
+
+	if hasPrev {
+		prev = signer.Encode(*p.first)
+	}
+	if hasNext {
+		next = signer.Encode(*p.last)
+	}
+	return prev, next
+}
+
+// parseCursors parses the after and before query parameters of a list paginated with
+// cursors. It responds with an error and returns false if they are not valid.
+func parseCursors(
+	w http.ResponseWriter,
+	r *http.Request,
+	signer *cursor.Signer,
+) (after, before *repository.Cursor, ok bool) {
+	query := r.URL.Query()
+	if query.Get("after") != "" && query.Get("before") != "" {
+		response.RespondWithError(
+			w,
+			http.StatusUnprocessableEntity,
+			[]string{"After and before cannot be used together"},
+		)
+		return nil, nil, false
+	}
+
+	if token := query.Get("after"); token != "" {
+		c, err := signer.Decode(token)
+		if err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusUnprocessableEntity,
+				[]string{"After must be a valid cursor"},
+			)
+			return nil, nil, false
+		}
+		after = &c
+	}
+
+	if token := query.Get("before"); token != "" {
+		c, err := signer.Decode(token)
+		if err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusUnprocessableEntity,
+				[]string{"Before must be a valid cursor"},
+			)
+			return nil, nil, false
+		}
+		before = &c
+	}
+
+	return after, before, true
+}
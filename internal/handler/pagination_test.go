@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Nilesh2000/conduit/internal/cursor"
+	"github.com/Nilesh2000/conduit/internal/repository"
+)
+
+// testCursors signs the cursors of lists in handler tests
+var testCursors = cursor.NewSigner([]byte("this-is-a-32-char-long-cursor-key-123"))
+
+// Test_listPage_cursors tests the cursors method of the listPage
+func Test_listPage_cursors(t *testing.T) {
+	t.Parallel()
+
+	first := &repository.Cursor{CreatedAt: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), ID: 2}
+	last := &repository.Cursor{CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), ID: 1}
+	at := &repository.Cursor{CreatedAt: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC), ID: 3}
+
+	tests := []struct {
+		name         string
+		page         listPage
+		expectedPrev *repository.Cursor
+		expectedNext *repository.Cursor
+	}{
+		{
+			name:         "First page with more articles",
+			page:         listPage{first: first, last: last, hasMore: true},
+			expectedPrev: nil,
+			expectedNext: last,
+		},
+		{
+			name:         "Only page",
+			page:         listPage{first: first, last: last},
+			expectedPrev: nil,
+			expectedNext: nil,
+		},
+		{
+			name:         "Page at an offset",
+			page:         listPage{offset: 20, first: first, last: last},
+			expectedPrev: first,
+			expectedNext: nil,
+		},
+		{
+			name:         "Page after a cursor",
+			page:         listPage{after: at, first: first, last: last, hasMore: true},
+			expectedPrev: first,
+			expectedNext: last,
+		},
+		{
+			name:         "Page before a cursor with no more before it",
+			page:         listPage{before: at, first: first, last: last},
+			expectedPrev: nil,
+			expectedNext: last,
+		},
+		{
+			name:         "Page before a cursor with more before it",
+			page:         listPage{before: at, first: first, last: last, hasMore: true},
+			expectedPrev: first,
+			expectedNext: last,
+		},
+		{
+			name:         "Empty page",
+			page:         listPage{after: at},
+			expectedPrev: nil,
+			expectedNext: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Call method
+			prev, next := tt.page.cursors(testCursors)
+
+			// Validate cursors
+			if expected := encodeTestCursor(tt.expectedPrev); prev != expected {
+				t.Errorf("Expected prev cursor %q, got %q", expected, prev)
+			}
+			if expected := encodeTestCursor(tt.expectedNext); next != expected {
+				t.Errorf("Expected next cursor %q, got %q", expected, next)
+			}
+		})
+	}
+}
+
+// Test_parseCursors tests the parseCursors function
+func Test_parseCursors(t *testing.T) {
+	t.Parallel()
+
+	c := repository.Cursor{CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), ID: 1}
+	token := testCursors.Encode(c)
+
+	tests := []struct {
+		name           string
+		queryParams    string
+		expectedOK     bool
+		expectedAfter  bool
+		expectedBefore bool
+		expectedStatus int
+	}{
+		{
+			name:           "No cursor",
+			queryParams:    "",
+			expectedOK:     true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "After cursor",
+			queryParams:    "?after=" + token,
+			expectedOK:     true,
+			expectedAfter:  true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Before cursor",
+			queryParams:    "?before=" + token,
+			expectedOK:     true,
+			expectedBefore: true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Both cursors",
+			queryParams:    "?after=" + token + "&before=" + token,
+			expectedOK:     false,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "Forged cursor",
+			queryParams:    "?after=" + cursor.NewSigner([]byte("another-32-char-long-cursor-key-4567")).Encode(c),
+			expectedOK:     false,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Create Request
+			req := httptest.NewRequest(http.MethodGet, "/api/articles"+tt.queryParams, nil)
+
+			// Create Response Recorder
+			rr := httptest.NewRecorder()
+
+			// Call function
+			after, before, ok := parseCursors(rr, req, testCursors)
+
+			// Validate result
+			if ok != tt.expectedOK {
+				t.Errorf("Expected ok %v, got %v", tt.expectedOK, ok)
+			}
+			if (after != nil) != tt.expectedAfter || (before != nil) != tt.expectedBefore {
+				t.Errorf("Expected after %v and before %v, got %v and %v", tt.expectedAfter, tt.expectedBefore, after, before)
+			}
+			if after != nil && *after != c {
+				t.Errorf("Expected after %+v, got %+v", c, *after)
+			}
+
+			// Check Status Code
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("Status code: got %v, want %v", status, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+// encodeTestCursor returns the token of a cursor, or an empty string if there is none
+func encodeTestCursor(c *repository.Cursor) string {
+	if c == nil {
+		return ""
+	}
+	return testCursors.Encode(*c)
+}
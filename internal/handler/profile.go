@@ -5,8 +5,11 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strconv"
 
+	"github.com/Nilesh2000/conduit/internal/cursor"
 	"github.com/Nilesh2000/conduit/internal/middleware"
+	"github.com/Nilesh2000/conduit/internal/repository"
 	"github.com/Nilesh2000/conduit/internal/response"
 	"github.com/Nilesh2000/conduit/internal/service"
 )
@@ -16,6 +19,20 @@ type ProfileResponse struct {
 	Profile service.Profile `json:"profile"`
 }
 
+// ProfilesResponse is the response body for a page of the followers or followed users
+// of a user
+type ProfilesResponse struct {
+	Profiles      []service.Profile `json:"profiles"`
+	ProfilesCount int               `json:"profilesCount"`
+	NextCursor    string            `json:"nextCursor,omitempty"`
+	PrevCursor    string            `json:"prevCursor,omitempty"`
+}
+
+// BlocksResponse is the response body for the users the current user blocked
+type BlocksResponse struct {
+	Profiles []service.Profile `json:"profiles"`
+}
+
 // ProfileService is an interface for the profile service
 type ProfileService interface {
 	GetProfile(ctx context.Context, username string, currentUserID *int64) (*service.Profile, error)
@@ -29,17 +46,43 @@ type ProfileService interface {
 		followerID int64,
 		followingName string,
 	) (*service.Profile, error)
+	BlockUser(
+		ctx context.Context,
+		blockerID int64,
+		blockedName string,
+	) (*service.Profile, error)
+	UnblockUser(
+		ctx context.Context,
+		blockerID int64,
+		blockedName string,
+	) (*service.Profile, error)
+	GetBlockedUsers(ctx context.Context, blockerID int64) ([]service.Profile, error)
+	GetFollowers(
+		ctx context.Context,
+		username string,
+		currentUserID *int64,
+		filters repository.FollowFilters,
+	) (*service.ProfileList, error)
+	GetFollowing(
+		ctx context.Context,
+		username string,
+		currentUserID *int64,
+		filters repository.FollowFilters,
+	) (*service.ProfileList, error)
 }
 
 // profileHandler is a handler for profile requests
 type profileHandler struct {
 	profileService ProfileService
+	cursors        *cursor.Signer
 }
 
-// NewProfileHandler creates a new profile handler
-func NewProfileHandler(profileService ProfileService) *profileHandler {
+// NewProfileHandler creates a new profile handler that signs the cursors of follower
+// and following lists with cursors
+func NewProfileHandler(profileService ProfileService, cursors *cursor.Signer) *profileHandler {
 	return &profileHandler{
 		profileService: profileService,
+		cursors:        cursors,
 	}
 }
 
@@ -114,6 +157,12 @@ func (h *profileHandler) Follow() http.HandlerFunc {
 					http.StatusBadRequest,
 					[]string{"Cannot follow yourself"},
 				)
+			case errors.Is(err, service.ErrUserBlocked):
+				response.RespondWithError(
+					w,
+					http.StatusForbidden,
+					[]string{"Cannot follow a user who blocked you or whom you blocked"},
+				)
 			default:
 				response.RespondWithError(
 					w,
@@ -186,3 +235,189 @@ func (h *profileHandler) Unfollow() http.HandlerFunc {
 		}
 	}
 }
+
+// Block blocks a user
+func (h *profileHandler) Block() http.HandlerFunc {
+	return h.setBlocked(h.profileService.BlockUser)
+}
+
+// Unblock unblocks a user
+func (h *profileHandler) Unblock() http.HandlerFunc {
+	return h.setBlocked(h.profileService.UnblockUser)
+}
+
+// setBlocked is a handler for blocking or unblocking a user with set
+func (h *profileHandler) setBlocked(
+	set func(ctx context.Context, blockerID int64, blockedName string) (*service.Profile, error),
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Set the content type to JSON
+		w.Header().Set("Content-Type", "application/json")
+
+		// Get user ID from context
+		blockerID, ok := middleware.GetUserIDFromContext(r.Context())
+		if !ok {
+			response.RespondWithError(w, http.StatusUnauthorized, []string{"Unauthorized"})
+			return
+		}
+
+		// Get username from URL path
+		username := r.PathValue("username")
+
+		profile, err := set(r.Context(), blockerID, username)
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrUserNotFound):
+				response.RespondWithError(w, http.StatusNotFound, []string{"User not found"})
+			case errors.Is(err, service.ErrCannotBlockSelf):
+				response.RespondWithError(
+					w,
+					http.StatusBadRequest,
+					[]string{"Cannot block yourself"},
+				)
+			default:
+				response.RespondWithError(
+					w,
+					http.StatusInternalServerError,
+					[]string{"Internal server error"},
+				)
+			}
+			return
+		}
+
+		// Respond with updated profile
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(ProfileResponse{Profile: *profile}); err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusInternalServerError,
+				[]string{"Internal server error"},
+			)
+		}
+	}
+}
+
+// GetBlocks lists the users the current user blocked
+func (h *profileHandler) GetBlocks() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Set the content type to JSON
+		w.Header().Set("Content-Type", "application/json")
+
+		// Get user ID from context
+		userID, ok := middleware.GetUserIDFromContext(r.Context())
+		if !ok {
+			response.RespondWithError(w, http.StatusUnauthorized, []string{"Unauthorized"})
+			return
+		}
+
+		profiles, err := h.profileService.GetBlockedUsers(r.Context(), userID)
+		if err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusInternalServerError,
+				[]string{"Internal server error"},
+			)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(BlocksResponse{Profiles: profiles}); err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusInternalServerError,
+				[]string{"Internal server error"},
+			)
+		}
+	}
+}
+
+// GetFollowers is a handler for listing the users following a user
+func (h *profileHandler) GetFollowers() http.HandlerFunc {
+	return h.listFollows(h.profileService.GetFollowers)
+}
+
+// GetFollowing is a handler for listing the users a user follows
+func (h *profileHandler) GetFollowing() http.HandlerFunc {
+	return h.listFollows(h.profileService.GetFollowing)
+}
+
+// listFollows is a handler for listing a page of a follow list of a user with list
+func (h *profileHandler) listFollows(
+	list func(
+		ctx context.Context,
+		username string,
+		currentUserID *int64,
+		filters repository.FollowFilters,
+	) (*service.ProfileList, error),
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Set the content type to JSON
+		w.Header().Set("Content-Type", "application/json")
+
+		// Get current user ID from context (optional)
+		var userID *int64
+		if id, ok := middleware.GetUserIDFromContext(r.Context()); ok {
+			userID = &id
+		}
+
+		username := r.PathValue("username")
+
+		filters := repository.FollowFilters{
+			Limit: 20, // Default limit
+		}
+
+		// Parse limit parameter
+		if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+			limit, err := strconv.Atoi(limitStr)
+			if err != nil || limit <= 0 {
+				response.RespondWithError(
+					w,
+					http.StatusUnprocessableEntity,
+					[]string{"Limit must be a positive integer"},
+				)
+				return
+			}
+			filters.Limit = limit
+		}
+
+		// Parse cursor parameters
+		var ok bool
+		if filters.After, filters.Before, ok = parseCursors(w, r, h.cursors); !ok {
+			return
+		}
+
+		result, err := list(r.Context(), username, userID, filters)
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrUserNotFound):
+				response.RespondWithError(w, http.StatusNotFound, []string{"User not found"})
+			default:
+				response.RespondWithError(
+					w,
+					http.StatusInternalServerError,
+					[]string{"Internal server error"},
+				)
+			}
+			return
+		}
+
+		resp := ProfilesResponse{Profiles: result.Profiles, ProfilesCount: result.Count}
+		page := listPage{
+			after:   filters.After,
+			before:  filters.Before,
+			first:   result.First,
+			last:    result.Last,
+			hasMore: result.HasMore,
+		}
+		resp.PrevCursor, resp.NextCursor = page.cursors(h.cursors)
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusInternalServerError,
+				[]string{"Internal server error"},
+			)
+		}
+	}
+}
@@ -8,17 +8,25 @@ import (
 	"net/http/httptest"
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/Nilesh2000/conduit/internal/jwtkeys"
 	"github.com/Nilesh2000/conduit/internal/middleware"
+	"github.com/Nilesh2000/conduit/internal/repository"
 	"github.com/Nilesh2000/conduit/internal/response"
 	"github.com/Nilesh2000/conduit/internal/service"
 )
 
 // MockProfileService is a mock implementation of the ProfileService interface
 type MockProfileService struct {
-	getProfileFunc   func(ctx context.Context, username string, currentUserID *int64) (*service.Profile, error)
-	followUserFunc   func(ctx context.Context, followerID int64, followingName string) (*service.Profile, error)
-	unfollowUserFunc func(ctx context.Context, followerID int64, followingName string) (*service.Profile, error)
+	getProfileFunc      func(ctx context.Context, username string, currentUserID *int64) (*service.Profile, error)
+	followUserFunc      func(ctx context.Context, followerID int64, followingName string) (*service.Profile, error)
+	unfollowUserFunc    func(ctx context.Context, followerID int64, followingName string) (*service.Profile, error)
+	blockUserFunc       func(ctx context.Context, blockerID int64, blockedName string) (*service.Profile, error)
+	unblockUserFunc     func(ctx context.Context, blockerID int64, blockedName string) (*service.Profile, error)
+	getBlockedUsersFunc func(ctx context.Context, blockerID int64) ([]service.Profile, error)
+	getFollowersFunc    func(ctx context.Context, username string, currentUserID *int64, filters repository.FollowFilters) (*service.ProfileList, error)
+	getFollowingFunc    func(ctx context.Context, username string, currentUserID *int64, filters repository.FollowFilters) (*service.ProfileList, error)
 }
 
 // GetProfile returns a mock profile
@@ -48,6 +56,52 @@ func (m *MockProfileService) UnfollowUser(
 	return m.unfollowUserFunc(ctx, followerID, followingName)
 }
 
+// BlockUser returns a mock profile
+func (m *MockProfileService) BlockUser(
+	ctx context.Context,
+	blockerID int64,
+	blockedName string,
+) (*service.Profile, error) {
+	return m.blockUserFunc(ctx, blockerID, blockedName)
+}
+
+// UnblockUser returns a mock profile
+func (m *MockProfileService) UnblockUser(
+	ctx context.Context,
+	blockerID int64,
+	blockedName string,
+) (*service.Profile, error) {
+	return m.unblockUserFunc(ctx, blockerID, blockedName)
+}
+
+// GetBlockedUsers returns a mock list of blocked users
+func (m *MockProfileService) GetBlockedUsers(
+	ctx context.Context,
+	blockerID int64,
+) ([]service.Profile, error) {
+	return m.getBlockedUsersFunc(ctx, blockerID)
+}
+
+// GetFollowers returns a mock list of followers
+func (m *MockProfileService) GetFollowers(
+	ctx context.Context,
+	username string,
+	currentUserID *int64,
+	filters repository.FollowFilters,
+) (*service.ProfileList, error) {
+	return m.getFollowersFunc(ctx, username, currentUserID, filters)
+}
+
+// GetFollowing returns a mock list of followed users
+func (m *MockProfileService) GetFollowing(
+	ctx context.Context,
+	username string,
+	currentUserID *int64,
+	filters repository.FollowFilters,
+) (*service.ProfileList, error) {
+	return m.getFollowingFunc(ctx, username, currentUserID, filters)
+}
+
 // Test_profileHandler_GetProfile tests the GetProfile method of the profileHandler
 func Test_profileHandler_GetProfile(t *testing.T) {
 	t.Parallel()
@@ -199,7 +253,7 @@ func Test_profileHandler_GetProfile(t *testing.T) {
 			mockService := tt.setupMock()
 
 			// Create handler
-			profileHandler := NewProfileHandler(mockService)
+			profileHandler := NewProfileHandler(mockService, testCursors)
 
 			// Create request
 			req := httptest.NewRequest(http.MethodGet, "/api/profiles/"+tt.username, nil)
@@ -245,6 +299,87 @@ func Test_profileHandler_GetProfile(t *testing.T) {
 	}
 }
 
+// Test_profileHandler_GetProfile_OptionalAuth tests that GetProfile resolves the following
+// flag when served behind the OptionalAuth middleware
+func Test_profileHandler_GetProfile_OptionalAuth(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name              string
+		authHeader        string
+		expectedStatus    int
+		expectedFollowing bool
+	}{
+		{
+			name:              "Authenticated follower sees following",
+			authHeader:        "Token " + signTestToken(t, "1"),
+			expectedStatus:    http.StatusOK,
+			expectedFollowing: true,
+		},
+		{
+			name:              "Anonymous request is not personalised",
+			authHeader:        "",
+			expectedStatus:    http.StatusOK,
+			expectedFollowing: false,
+		},
+		{
+			name:           "Malformed authorization header is rejected",
+			authHeader:     "Bearer something",
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock service that reports user 1 as a follower
+			mockService := &MockProfileService{
+				getProfileFunc: func(ctx context.Context, username string, currentUserID *int64) (*service.Profile, error) {
+					return &service.Profile{
+						Username:  username,
+						Following: currentUserID != nil && *currentUserID == 1,
+					}, nil
+				},
+			}
+
+			// Create handler behind the optional auth middleware
+			handler := middleware.OptionalAuth(jwtkeys.NewHMACKeySet([]byte(testJWTSecret)), nil)(
+				NewProfileHandler(mockService, testCursors).GetProfile(),
+			)
+
+			// Create request
+			req := httptest.NewRequest(http.MethodGet, "/api/profiles/testuser", nil)
+			req.SetPathValue("username", "testuser")
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+
+			// Serve request
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			// Check status code
+			if got, want := rr.Code, tt.expectedStatus; got != want {
+				t.Fatalf("Status code: got %v, want %v", got, want)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			// Check following flag
+			var resp ProfileResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+			if resp.Profile.Following != tt.expectedFollowing {
+				t.Errorf("Following: got %v, want %v", resp.Profile.Following, tt.expectedFollowing)
+			}
+		})
+	}
+}
+
 // Test_profileHandler_Follow tests the Follow method of the profileHandler
 func Test_profileHandler_Follow(t *testing.T) {
 	t.Parallel()
@@ -405,7 +540,7 @@ func Test_profileHandler_Follow(t *testing.T) {
 			mockService := tt.setupMock()
 
 			// Create handler
-			profileHandler := NewProfileHandler(mockService)
+			profileHandler := NewProfileHandler(mockService, testCursors)
 
 			// Create request
 			req := httptest.NewRequest(http.MethodPost, "/api/profiles/"+tt.username+"/follow", nil)
@@ -614,7 +749,7 @@ func Test_profileHandler_Unfollow(t *testing.T) {
 			mockService := tt.setupMock()
 
 			// Create handler
-			profileHandler := NewProfileHandler(mockService)
+			profileHandler := NewProfileHandler(mockService, testCursors)
 
 			// Create request
 			req := httptest.NewRequest(
@@ -663,3 +798,231 @@ func Test_profileHandler_Unfollow(t *testing.T) {
 		})
 	}
 }
+
+// Test_profileHandler_GetFollowers tests the GetFollowers method of the profileHandler
+func Test_profileHandler_GetFollowers(t *testing.T) {
+	t.Parallel()
+
+	followedAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	last := repository.Cursor{CreatedAt: followedAt, ID: 2}
+
+	tests := []struct {
+		name            string
+		query           string
+		serviceErr      error
+		expectedStatus  int
+		expectedFilters repository.FollowFilters
+		expectedErrors  []string
+	}{
+		{
+			name:            "Default page",
+			query:           "",
+			expectedStatus:  http.StatusOK,
+			expectedFilters: repository.FollowFilters{Limit: 20},
+		},
+		{
+			name:            "Page after a cursor",
+			query:           "?limit=1&after=" + testCursors.Encode(last),
+			expectedStatus:  http.StatusOK,
+			expectedFilters: repository.FollowFilters{Limit: 1, After: &last},
+		},
+		{
+			name:           "Invalid limit",
+			query:          "?limit=abc",
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedErrors: []string{"Limit must be a positive integer"},
+		},
+		{
+			name:           "User not found",
+			query:          "",
+			serviceErr:     service.ErrUserNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedErrors: []string{"User not found"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup Mock Service
+			mockService := &MockProfileService{
+				getFollowersFunc: func(ctx context.Context, username string, currentUserID *int64, filters repository.FollowFilters) (*service.ProfileList, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					if !reflect.DeepEqual(filters, tt.expectedFilters) {
+						t.Errorf("Expected filters %+v, got %+v", tt.expectedFilters, filters)
+					}
+					if currentUserID == nil || *currentUserID != 1 {
+						t.Errorf("Expected current user 1, got %v", currentUserID)
+					}
+					return &service.ProfileList{
+						Profiles: []service.Profile{{Username: "follower", Following: true}},
+						HasMore:  true,
+						First:    &last,
+						Last:     &last,
+						Count:    3,
+					}, nil
+				},
+			}
+
+			// Create Handler
+			handler := NewProfileHandler(mockService, testCursors)
+
+			// Create Request
+			req := httptest.NewRequest(http.MethodGet, "/api/profiles/testuser/followers"+tt.query, nil)
+			req.SetPathValue("username", "testuser")
+			ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, int64(1))
+			req = req.WithContext(ctx)
+
+			// Create Response Recorder
+			rr := httptest.NewRecorder()
+
+			// Serve Request
+			handler.GetFollowers()(rr, req)
+
+			// Check Status Code
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("Status code: got %v, want %v", status, tt.expectedStatus)
+			}
+
+			// Check Response Body
+			if tt.expectedStatus == http.StatusOK {
+				var resp ProfilesResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				if resp.ProfilesCount != 3 || len(resp.Profiles) != 1 || !resp.Profiles[0].Following {
+					t.Errorf("Expected a followed follower out of 3, got %+v", resp)
+				}
+				if resp.NextCursor != testCursors.Encode(last) {
+					t.Errorf("Expected next cursor %q, got %q", testCursors.Encode(last), resp.NextCursor)
+				}
+			} else {
+				var resp response.GenericErrorModel
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				if !reflect.DeepEqual(resp.Errors.Body, tt.expectedErrors) {
+					t.Errorf("Expected errors %v, got %v", tt.expectedErrors, resp.Errors.Body)
+				}
+			}
+		})
+	}
+}
+
+// Test_profileHandler_Block tests the Block method of the profileHandler
+func Test_profileHandler_Block(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		authenticated    bool
+		serviceErr       error
+		expectedStatus   int
+		expectedResponse any
+	}{
+		{
+			name:           "User blocked",
+			authenticated:  true,
+			expectedStatus: http.StatusOK,
+			expectedResponse: ProfileResponse{
+				Profile: service.Profile{Username: "harasser", Blocking: true},
+			},
+		},
+		{
+			name:           "Unauthenticated request",
+			authenticated:  false,
+			expectedStatus: http.StatusUnauthorized,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Unauthorized"}},
+			},
+		},
+		{
+			name:           "Blocking yourself",
+			authenticated:  true,
+			serviceErr:     service.ErrCannotBlockSelf,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Cannot block yourself"}},
+			},
+		},
+		{
+			name:           "User not found",
+			authenticated:  true,
+			serviceErr:     service.ErrUserNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"User not found"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup Mock Service
+			mockService := &MockProfileService{
+				blockUserFunc: func(ctx context.Context, blockerID int64, blockedName string) (*service.Profile, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &service.Profile{Username: blockedName, Blocking: true}, nil
+				},
+			}
+
+			// Create Handler
+			handler := NewProfileHandler(mockService, testCursors)
+
+			// Create Request
+			req := httptest.NewRequest(http.MethodPost, "/api/profiles/harasser/block", nil)
+			req.SetPathValue("username", "harasser")
+			if tt.authenticated {
+				ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, int64(1))
+				req = req.WithContext(ctx)
+			}
+
+			// Create Response Recorder
+			rr := httptest.NewRecorder()
+
+			// Serve Request
+			handler.Block()(rr, req)
+
+			// Check Status Code
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("Status code: got %v, want %v", status, tt.expectedStatus)
+			}
+
+			// Check Response Body
+			var got any
+			if tt.expectedStatus == http.StatusOK {
+				var resp ProfileResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				got = resp
+			} else {
+				var resp response.GenericErrorModel
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				got = resp
+			}
+
+			// Deep compare expected and got
+			if !reflect.DeepEqual(got, tt.expectedResponse) {
+				t.Errorf("Response body: got %v, want %v", got, tt.expectedResponse)
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Nilesh2000/conduit/internal/middleware"
+	"github.com/Nilesh2000/conduit/internal/response"
+	"github.com/Nilesh2000/conduit/internal/service"
+)
+
+// RevisionResponse is the response for an article revision
+type RevisionResponse struct {
+	Revision service.ArticleRevision `json:"revision"`
+}
+
+// RevisionsResponse is the response for the revisions of an article
+type RevisionsResponse struct {
+	Revisions []service.ArticleRevision `json:"revisions"`
+}
+
+// RevisionDiffResponse is the response for the diff between two revisions
+type RevisionDiffResponse struct {
+	Diff service.RevisionDiff `json:"diff"`
+}
+
+// RevisionService is an interface for the article revision service
+type RevisionService interface {
+	ListRevisions(
+		ctx context.Context,
+		slug string,
+		currentUserID *int64,
+	) ([]service.ArticleRevision, error)
+	GetRevision(
+		ctx context.Context,
+		slug string,
+		number int,
+		currentUserID *int64,
+	) (*service.ArticleRevision, error)
+	DiffRevisions(
+		ctx context.Context,
+		slug string,
+		from, to int,
+		currentUserID *int64,
+	) (*service.RevisionDiff, error)
+	RestoreRevision(
+		ctx context.Context,
+		userID int64,
+		slug string,
+		number int,
+	) (*service.Article, error)
+}
+
+// revisionHandler is a handler for article revision requests
+type revisionHandler struct {
+	revisionService RevisionService
+}
+
+// NewRevisionHandler creates a new revision handler
+func NewRevisionHandler(revisionService RevisionService) *revisionHandler {
+	return &revisionHandler{revisionService: revisionService}
+}
+
+// ListRevisions is a handler for listing the revisions of an article
+func (h *revisionHandler) ListRevisions() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Set the content type to JSON
+		w.Header().Set("Content-Type", "application/json")
+
+		// Get current user ID from context (optional)
+		var userID *int64
+		if id, ok := middleware.GetUserIDFromContext(r.Context()); ok {
+			userID = &id
+		}
+
+		slug := r.PathValue("slug")
+
+		revisions, err := h.revisionService.ListRevisions(r.Context(), slug, userID)
+		if err != nil {
+			respondWithRevisionError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(RevisionsResponse{Revisions: revisions}); err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusInternalServerError,
+				[]string{"Internal server error"},
+			)
+		}
+	}
+}
+
+// GetRevision is a handler for getting a revision of an article
+func (h *revisionHandler) GetRevision() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Set the content type to JSON
+		w.Header().Set("Content-Type", "application/json")
+
+		// Get current user ID from context (optional)
+		var userID *int64
+		if id, ok := middleware.GetUserIDFromContext(r.Context()); ok {
+			userID = &id
+		}
+
+		slug := r.PathValue("slug")
+		number, ok := parseRevisionNumber(r.PathValue("n"))
+		if !ok {
+			response.RespondWithError(w, http.StatusBadRequest, []string{"Invalid revision number"})
+			return
+		}
+
+		revision, err := h.revisionService.GetRevision(r.Context(), slug, number, userID)
+		if err != nil {
+			respondWithRevisionError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(RevisionResponse{Revision: *revision}); err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusInternalServerError,
+				[]string{"Internal server error"},
+			)
+		}
+	}
+}
+
+// DiffRevisions is a handler for comparing a revision of an article with an earlier one.
+// The revision is compared with the one before it unless the from query parameter is set.
+func (h *revisionHandler) DiffRevisions() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Set the content type to JSON
+		w.Header().Set("Content-Type", "application/json")
+
+		// Get current user ID from context (optional)
+		var userID *int64
+		if id, ok := middleware.GetUserIDFromContext(r.Context()); ok {
+			userID = &id
+		}
+
+		slug := r.PathValue("slug")
+		to, ok := parseRevisionNumber(r.PathValue("n"))
+		if !ok {
+			response.RespondWithError(w, http.StatusBadRequest, []string{"Invalid revision number"})
+			return
+		}
+
+		// Parse the revision to compare with, where 0 is the empty article
+		from := to - 1
+		if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+			var err error
+			from, err = strconv.Atoi(fromStr)
+			if err != nil || from < 0 {
+				response.RespondWithError(
+					w,
+					http.StatusBadRequest,
+					[]string{"Invalid revision number"},
+				)
+				return
+			}
+		}
+
+		revisionDiff, err := h.revisionService.DiffRevisions(r.Context(), slug, from, to, userID)
+		if err != nil {
+			respondWithRevisionError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(RevisionDiffResponse{Diff: *revisionDiff}); err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusInternalServerError,
+				[]string{"Internal server error"},
+			)
+		}
+	}
+}
+
+// RestoreRevision is a handler for restoring an article to one of its revisions
+func (h *revisionHandler) RestoreRevision() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Set the content type to JSON
+		w.Header().Set("Content-Type", "application/json")
+
+		// Get user ID from context
+		userID, ok := middleware.GetUserIDFromContext(r.Context())
+		if !ok {
+			response.RespondWithError(w, http.StatusUnauthorized, []string{"Unauthorized"})
+			return
+		}
+
+		slug := r.PathValue("slug")
+		number, ok := parseRevisionNumber(r.PathValue("n"))
+		if !ok {
+			response.RespondWithError(w, http.StatusBadRequest, []string{"Invalid revision number"})
+			return
+		}
+
+		article, err := h.revisionService.RestoreRevision(r.Context(), userID, slug, number)
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrArticleNotAuthorized):
+				response.RespondWithError(
+					w,
+					http.StatusForbidden,
+					[]string{"You are not the author of this article"},
+				)
+			case errors.Is(err, service.ErrArticleAlreadyExists):
+				response.RespondWithError(
+					w,
+					http.StatusUnprocessableEntity,
+					[]string{"Article with this title already exists"},
+				)
+			default:
+				respondWithRevisionError(w, r, err)
+			}
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(ArticleResponse{Article: *article}); err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusInternalServerError,
+				[]string{"Internal server error"},
+			)
+		}
+	}
+}
+
+// parseRevisionNumber parses a revision number from a request path
+func parseRevisionNumber(value string) (int, bool) {
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 1 {
+		return 0, false
+	}
+	return number, true
+}
+
+// respondWithRevisionError responds with the error shared by the revision handlers
+func respondWithRevisionError(w http.ResponseWriter, r *http.Request, err error) {
+	if redirectMovedArticle(w, r, err) {
+		return
+	}
+
+	switch {
+	case errors.Is(err, service.ErrArticleNotFound):
+		response.RespondWithError(w, http.StatusNotFound, []string{"Article not found"})
+	case errors.Is(err, service.ErrRevisionNotFound):
+		response.RespondWithError(w, http.StatusNotFound, []string{"Revision not found"})
+	default:
+		response.RespondWithError(
+			w,
+			http.StatusInternalServerError,
+			[]string{"Internal server error"},
+		)
+	}
+}
@@ -0,0 +1,423 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/Nilesh2000/conduit/internal/diff"
+	"github.com/Nilesh2000/conduit/internal/middleware"
+	"github.com/Nilesh2000/conduit/internal/response"
+	"github.com/Nilesh2000/conduit/internal/service"
+)
+
+// MockRevisionService is a mock implementation of the RevisionService interface
+type MockRevisionService struct {
+	listRevisionsFunc   func(ctx context.Context, slug string, currentUserID *int64) ([]service.ArticleRevision, error)
+	getRevisionFunc     func(ctx context.Context, slug string, number int, currentUserID *int64) (*service.ArticleRevision, error)
+	diffRevisionsFunc   func(ctx context.Context, slug string, from, to int, currentUserID *int64) (*service.RevisionDiff, error)
+	restoreRevisionFunc func(ctx context.Context, userID int64, slug string, number int) (*service.Article, error)
+}
+
+// ListRevisions lists the revisions of an article in the mock service
+func (m *MockRevisionService) ListRevisions(
+	ctx context.Context,
+	slug string,
+	currentUserID *int64,
+) ([]service.ArticleRevision, error) {
+	return m.listRevisionsFunc(ctx, slug, currentUserID)
+}
+
+// GetRevision gets a revision of an article in the mock service
+func (m *MockRevisionService) GetRevision(
+	ctx context.Context,
+	slug string,
+	number int,
+	currentUserID *int64,
+) (*service.ArticleRevision, error) {
+	return m.getRevisionFunc(ctx, slug, number, currentUserID)
+}
+
+// DiffRevisions compares two revisions of an article in the mock service
+func (m *MockRevisionService) DiffRevisions(
+	ctx context.Context,
+	slug string,
+	from, to int,
+	currentUserID *int64,
+) (*service.RevisionDiff, error) {
+	return m.diffRevisionsFunc(ctx, slug, from, to, currentUserID)
+}
+
+// RestoreRevision restores a revision of an article in the mock service
+func (m *MockRevisionService) RestoreRevision(
+	ctx context.Context,
+	userID int64,
+	slug string,
+	number int,
+) (*service.Article, error) {
+	return m.restoreRevisionFunc(ctx, userID, slug, number)
+}
+
+// TestRevisionHandler_GetRevision tests the GetRevision method of the RevisionHandler
+func TestRevisionHandler_GetRevision(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		number           string
+		serviceErr       error
+		expectedStatus   int
+		expectedResponse any
+	}{
+		{
+			name:           "Revision found",
+			number:         "2",
+			expectedStatus: http.StatusOK,
+			expectedResponse: RevisionResponse{
+				Revision: service.ArticleRevision{Number: 2, Title: "Title", Body: "Body"},
+			},
+		},
+		{
+			name:           "Invalid revision number",
+			number:         "first",
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Invalid revision number"}},
+			},
+		},
+		{
+			name:           "Revision not found",
+			number:         "9",
+			serviceErr:     service.ErrRevisionNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Revision not found"}},
+			},
+		},
+		{
+			name:           "Article not found",
+			number:         "1",
+			serviceErr:     service.ErrArticleNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Article not found"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup Mock
+			mockService := &MockRevisionService{
+				getRevisionFunc: func(ctx context.Context, slug string, number int, currentUserID *int64) (*service.ArticleRevision, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &service.ArticleRevision{Number: number, Title: "Title", Body: "Body"}, nil
+				},
+			}
+
+			// Create Handler
+			handler := NewRevisionHandler(mockService)
+
+			// Create Request
+			req := httptest.NewRequest(
+				http.MethodGet,
+				"/api/articles/test-article/revisions/"+tt.number,
+				nil,
+			)
+			req.SetPathValue("slug", "test-article")
+			req.SetPathValue("n", tt.number)
+
+			// Create Response Recorder
+			rr := httptest.NewRecorder()
+
+			// Serve Request
+			handler.GetRevision()(rr, req)
+
+			// Check Status Code
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("Status code: got %v, want %v", status, tt.expectedStatus)
+			}
+
+			// Check Response Body
+			var got any
+			if tt.expectedStatus == http.StatusOK {
+				var resp RevisionResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				got = resp
+			} else {
+				var resp response.GenericErrorModel
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				got = resp
+			}
+
+			// Deep compare expected and got
+			if !reflect.DeepEqual(got, tt.expectedResponse) {
+				t.Errorf("Response body: got %v, want %v", got, tt.expectedResponse)
+			}
+		})
+	}
+}
+
+// TestRevisionHandler_DiffRevisions tests the DiffRevisions method of the RevisionHandler
+func TestRevisionHandler_DiffRevisions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		number         string
+		query          string
+		expectedStatus int
+		expectedFrom   int
+		expectedTo     int
+	}{
+		{
+			name:           "Compared with the previous revision",
+			number:         "3",
+			expectedStatus: http.StatusOK,
+			expectedFrom:   2,
+			expectedTo:     3,
+		},
+		{
+			name:           "Compared with an explicit revision",
+			number:         "3",
+			query:          "?from=1",
+			expectedStatus: http.StatusOK,
+			expectedFrom:   1,
+			expectedTo:     3,
+		},
+		{
+			name:           "First revision is compared with the empty article",
+			number:         "1",
+			expectedStatus: http.StatusOK,
+			expectedFrom:   0,
+			expectedTo:     1,
+		},
+		{
+			name:           "Invalid from",
+			number:         "3",
+			query:          "?from=-1",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid revision number",
+			number:         "0",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup Mock
+			mockService := &MockRevisionService{
+				diffRevisionsFunc: func(ctx context.Context, slug string, from, to int, currentUserID *int64) (*service.RevisionDiff, error) {
+					if from != tt.expectedFrom || to != tt.expectedTo {
+						t.Errorf("Expected diff from %d to %d, got %d to %d", tt.expectedFrom, tt.expectedTo, from, to)
+					}
+					return &service.RevisionDiff{
+						From: from,
+						To:   to,
+						Body: []diff.Line{{Op: diff.OpInsert, Text: "line"}},
+					}, nil
+				},
+			}
+
+			// Create Handler
+			handler := NewRevisionHandler(mockService)
+
+			// Create Request
+			req := httptest.NewRequest(
+				http.MethodGet,
+				"/api/articles/test-article/revisions/"+tt.number+"/diff"+tt.query,
+				nil,
+			)
+			req.SetPathValue("slug", "test-article")
+			req.SetPathValue("n", tt.number)
+
+			// Create Response Recorder
+			rr := httptest.NewRecorder()
+
+			// Serve Request
+			handler.DiffRevisions()(rr, req)
+
+			// Check Status Code
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("Status code: got %v, want %v", status, tt.expectedStatus)
+			}
+
+			// Check Response Body
+			if tt.expectedStatus == http.StatusOK {
+				var resp RevisionDiffResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				if resp.Diff.From != tt.expectedFrom || resp.Diff.To != tt.expectedTo {
+					t.Errorf("Expected diff from %d to %d, got %+v", tt.expectedFrom, tt.expectedTo, resp.Diff)
+				}
+			}
+		})
+	}
+}
+
+// TestRevisionHandler_RestoreRevision tests the RestoreRevision method of the RevisionHandler
+func TestRevisionHandler_RestoreRevision(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		authenticated    bool
+		serviceErr       error
+		expectedStatus   int
+		expectedResponse any
+	}{
+		{
+			name:           "Author restores a revision",
+			authenticated:  true,
+			expectedStatus: http.StatusOK,
+			expectedResponse: ArticleResponse{
+				Article: service.Article{
+					Slug:    "test-article",
+					Title:   "Old Title",
+					TagList: []string{},
+					Author:  service.Profile{Username: "testuser"},
+				},
+			},
+		},
+		{
+			name:           "Unauthenticated request",
+			authenticated:  false,
+			expectedStatus: http.StatusUnauthorized,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Unauthorized"}},
+			},
+		},
+		{
+			name:           "Not the author",
+			authenticated:  true,
+			serviceErr:     service.ErrArticleNotAuthorized,
+			expectedStatus: http.StatusForbidden,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"You are not the author of this article"}},
+			},
+		},
+		{
+			name:           "Revision not found",
+			authenticated:  true,
+			serviceErr:     service.ErrRevisionNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Revision not found"}},
+			},
+		},
+		{
+			name:           "Internal server error",
+			authenticated:  true,
+			serviceErr:     service.ErrInternalServer,
+			expectedStatus: http.StatusInternalServerError,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Internal server error"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup Mock
+			mockService := &MockRevisionService{
+				restoreRevisionFunc: func(ctx context.Context, userID int64, slug string, number int) (*service.Article, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					if userID != 1 || number != 1 {
+						t.Errorf("Expected user 1 to restore revision 1, got user %d and revision %d", userID, number)
+					}
+					return &service.Article{
+						Slug:    slug,
+						Title:   "Old Title",
+						TagList: []string{},
+						Author:  service.Profile{Username: "testuser"},
+					}, nil
+				},
+			}
+
+			// Create Handler
+			handler := NewRevisionHandler(mockService)
+
+			// Create Request
+			req := httptest.NewRequest(
+				http.MethodPost,
+				"/api/articles/test-article/revisions/1/restore",
+				nil,
+			)
+			req.SetPathValue("slug", "test-article")
+			req.SetPathValue("n", "1")
+			if tt.authenticated {
+				ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, int64(1))
+				req = req.WithContext(ctx)
+			}
+
+			// Create Response Recorder
+			rr := httptest.NewRecorder()
+
+			// Serve Request
+			handler.RestoreRevision()(rr, req)
+
+			// Check Status Code
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("Status code: got %v, want %v", status, tt.expectedStatus)
+			}
+
+			// Check Response Body
+			var got any
+			if tt.expectedStatus == http.StatusOK {
+				var resp ArticleResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				got = resp
+			} else {
+				var resp response.GenericErrorModel
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				got = resp
+			}
+
+			// Deep compare expected and got
+			if !reflect.DeepEqual(got, tt.expectedResponse) {
+				t.Errorf("Response body: got %v, want %v", got, tt.expectedResponse)
+			}
+		})
+	}
+}
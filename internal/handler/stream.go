@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Nilesh2000/conduit/internal/middleware"
+	"github.com/Nilesh2000/conduit/internal/response"
+	"github.com/Nilesh2000/conduit/internal/service"
+	"github.com/Nilesh2000/conduit/internal/stream"
+)
+
+// StreamCommentResponse is the data of a streamed comment event
+type StreamCommentResponse struct {
+	Slug    string          `json:"slug"`
+	Comment service.Comment `json:"comment"`
+}
+
+// StreamNotificationResponse is the data of a streamed notification event
+type StreamNotificationResponse struct {
+	Notification service.Notification `json:"notification"`
+}
+
+// StreamService is an interface for the stream service
+type StreamService interface {
+	Stream(
+		ctx context.Context,
+		userID int64,
+		slugs []string,
+		lastEventID int64,
+	) (<-chan service.StreamMessage, error)
+}
+
+// streamHandler is a handler for event stream requests
+type streamHandler struct {
+	streamService     StreamService
+	heartbeatInterval time.Duration
+	writeTimeout      time.Duration
+}
+
+// NewStreamHandler creates a new stream handler that writes a heartbeat to idle streams
+// every heartbeatInterval and gives each write writeTimeout to finish, 0 allowing any
+// time
+func NewStreamHandler(
+	streamService StreamService,
+	heartbeatInterval time.Duration,
+	writeTimeout time.Duration,
+) *streamHandler {
+	return &streamHandler{
+		streamService:     streamService,
+		heartbeatInterval: heartbeatInterval,
+		writeTimeout:      writeTimeout,
+	}
+}
+
+// Stream is a handler for streaming server-sent events to the current user: the new
+// comments on the articles listed in the articles parameter, their new notifications
+// and the articles published into their feed. Clients that reconnect with the ID of the
+// last event they got in the Last-Event-ID header, or the lastEventId parameter, first
+// get the events they missed.
+func (h *streamHandler) Stream() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Set the content type to JSON until the stream starts
+		w.Header().Set("Content-Type", "application/json")
+
+		// Get user ID from context
+		userID, ok := middleware.GetUserIDFromContext(r.Context())
+		if !ok {
+			response.RespondWithError(w, http.StatusUnauthorized, []string{"Unauthorized"})
+			return
+		}
+
+		// Parse the ID of the last event the client got
+		var lastEventID int64
+		lastEventIDStr := r.Header.Get("Last-Event-ID")
+		if lastEventIDStr == "" {
+			lastEventIDStr = r.URL.Query().Get("lastEventId")
+		}
+		if lastEventIDStr != "" {
+			id, err := strconv.ParseInt(lastEventIDStr, 10, 64)
+			if err != nil || id <= 0 {
+				response.RespondWithError(
+					w,
+					http.StatusUnprocessableEntity,
+					[]string{"Last event ID must be a positive integer"},
+				)
+				return
+			}
+			lastEventID = id
+		}
+
+		// Parse the slugs of the articles to stream the comments of
+		var slugs []string
+		for _, slug := range strings.Split(r.URL.Query().Get("articles"), ",") {
+			if slug = strings.TrimSpace(slug); slug != "" {
+				slugs = append(slugs, slug)
+			}
+		}
+
+		messages, err := h.streamService.Stream(r.Context(), userID, slugs, lastEventID)
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrArticleNotFound):
+				response.RespondWithError(w, http.StatusNotFound, []string{"Article not found"})
+			default:
+				response.RespondWithError(
+					w,
+					http.StatusInternalServerError,
+					[]string{"Internal server error"},
+				)
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+
+		controller := http.NewResponseController(w)
+		if err := h.write(w, controller, ": connected\n\n"); err != nil {
+			return
+		}
+
+		heartbeat := time.NewTicker(h.heartbeatInterval)
+		defer heartbeat.Stop()
+
+		for {
+			var frame string
+			select {
+			case <-r.Context().Done():
+				return
+			case message, ok := <-messages:
+				if !ok {
+					return
+				}
+				frame, err = streamFrame(message)
+				if err != nil {
+					log.Printf("Failed to encode %s event %d: %v", message.Type, message.ID, err)
+					continue
+				}
+			case <-heartbeat.C:
+				frame = ": heartbeat\n\n"
+			}
+
+			if err := h.write(w, controller, frame); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// write writes a frame of the stream and flushes it to the client. The server's write
+// timeout covers the whole response, which would cut the stream off, so every write is
+// given the full timeout instead.
+func (h *streamHandler) write(
+	w http.ResponseWriter,
+	controller *http.ResponseController,
+	frame string,
+) error {
+	if h.writeTimeout > 0 {
+		err := controller.SetWriteDeadline(time.Now().Add(h.writeTimeout))
+		if err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+	}
+
+	if _, err := io.WriteString(w, frame); err != nil {
+		return err
+	}
+
+	return controller.Flush()
+}
+
+// streamFrame encodes a message as a server-sent event
+func streamFrame(message service.StreamMessage) (string, error) {
+	var data any
+	switch message.Type {
+	case stream.EventComment:
+		data = StreamCommentResponse{Slug: message.Slug, Comment: *message.Comment}
+	case stream.EventNotification:
+		data = StreamNotificationResponse{Notification: *message.Notification}
+	case stream.EventArticle:
+		data = ArticleResponse{Article: *message.Article}
+	default:
+		return "", fmt.Errorf("unknown event type %q", message.Type)
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("id: %d\nevent: %s\ndata: %s\n\n", message.ID, message.Type, encoded), nil
+}
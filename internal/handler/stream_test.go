@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Nilesh2000/conduit/internal/middleware"
+	"github.com/Nilesh2000/conduit/internal/response"
+	"github.com/Nilesh2000/conduit/internal/service"
+	"github.com/Nilesh2000/conduit/internal/stream"
+)
+
+// MockStreamService is a mock implementation of the StreamService interface
+type MockStreamService struct {
+	streamFunc func(ctx context.Context, userID int64, slugs []string, lastEventID int64) (<-chan service.StreamMessage, error)
+}
+
+// Stream streams the events of a user in the mock service
+func (m *MockStreamService) Stream(
+	ctx context.Context,
+	userID int64,
+	slugs []string,
+	lastEventID int64,
+) (<-chan service.StreamMessage, error) {
+	return m.streamFunc(ctx, userID, slugs, lastEventID)
+}
+
+// Test_streamHandler_Stream tests the Stream method of the streamHandler
+func Test_streamHandler_Stream(t *testing.T) {
+	t.Parallel()
+
+	messages := []service.StreamMessage{
+		{
+			ID:      5,
+			Type:    stream.EventComment,
+			Slug:    "test-article",
+			Comment: &service.Comment{ID: 7, Body: "Nice"},
+		},
+		{
+			ID:      6,
+			Type:    stream.EventArticle,
+			Article: &service.Article{Slug: "new-article"},
+		},
+	}
+	expectedBody := ": connected\n\n" +
+		"id: 5\nevent: comment\ndata: " +
+		`{"slug":"test-article","comment":{"id":7,"createdAt":"0001-01-01T00:00:00Z","updatedAt":"0001-01-01T00:00:00Z","body":"Nice","author":{"username":"","bio":"","image":"","following":false},"parentId":null,"depth":0,"replyCount":0,"deleted":false,"edited":false}}` +
+		"\n\n" +
+		"id: 6\nevent: article\ndata: "
+
+	tests := []struct {
+		name                string
+		query               string
+		lastEventIDHeader   string
+		streamErr           error
+		expectedSlugs       []string
+		expectedLastEventID int64
+		expectedStatus      int
+		expectedErrors      []string
+	}{
+		{
+			name:                "Resume from Last-Event-ID header",
+			query:               "?articles=test-article,%20other,",
+			lastEventIDHeader:   "4",
+			expectedSlugs:       []string{"test-article", "other"},
+			expectedLastEventID: 4,
+			expectedStatus:      http.StatusOK,
+		},
+		{
+			name:                "Resume from lastEventId parameter",
+			query:               "?lastEventId=4",
+			expectedLastEventID: 4,
+			expectedStatus:      http.StatusOK,
+		},
+		{
+			name:           "Invalid last event ID",
+			query:          "?lastEventId=abc",
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedErrors: []string{"Last event ID must be a positive integer"},
+		},
+		{
+			name:           "Unknown article",
+			query:          "?articles=missing",
+			streamErr:      service.ErrArticleNotFound,
+			expectedSlugs:  []string{"missing"},
+			expectedStatus: http.StatusNotFound,
+			expectedErrors: []string{"Article not found"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup Mock Service sending the messages and ending the stream
+			mockService := &MockStreamService{
+				streamFunc: func(ctx context.Context, userID int64, slugs []string, lastEventID int64) (<-chan service.StreamMessage, error) {
+					if userID != 1 {
+						t.Errorf("Expected user 1, got %d", userID)
+					}
+					if !reflect.DeepEqual(slugs, tt.expectedSlugs) {
+						t.Errorf("Expected slugs %v, got %v", tt.expectedSlugs, slugs)
+					}
+					if lastEventID != tt.expectedLastEventID {
+						t.Errorf("Expected last event ID %d, got %d", tt.expectedLastEventID, lastEventID)
+					}
+					if tt.streamErr != nil {
+						return nil, tt.streamErr
+					}
+
+					stream := make(chan service.StreamMessage, len(messages))
+					for _, message := range messages {
+						stream <- message
+					}
+					close(stream)
+					return stream, nil
+				},
+			}
+
+			// Create Handler
+			handler := NewStreamHandler(mockService, time.Minute, time.Minute)
+
+			// Create Request
+			req := httptest.NewRequest(http.MethodGet, "/api/stream"+tt.query, nil)
+			if tt.lastEventIDHeader != "" {
+				req.Header.Set("Last-Event-ID", tt.lastEventIDHeader)
+			}
+			ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, int64(1))
+			req = req.WithContext(ctx)
+
+			// Create Response Recorder
+			rr := httptest.NewRecorder()
+
+			// Serve Request
+			handler.Stream()(rr, req)
+
+			// Check Status Code
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("Status code: got %v, want %v", status, tt.expectedStatus)
+			}
+
+			// Check Response Body
+			if tt.expectedStatus == http.StatusOK {
+				if contentType := rr.Header().Get("Content-Type"); contentType != "text/event-stream" {
+					t.Errorf("Expected content type text/event-stream, got %q", contentType)
+				}
+				if body := rr.Body.String(); !strings.HasPrefix(body, expectedBody) {
+					t.Errorf("Expected body to start with %q, got %q", expectedBody, body)
+				}
+			} else {
+				var resp response.GenericErrorModel
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				if !reflect.DeepEqual(resp.Errors.Body, tt.expectedErrors) {
+					t.Errorf("Expected errors %v, got %v", tt.expectedErrors, resp.Errors.Body)
+				}
+			}
+		})
+	}
+}
+
+// Test_streamHandler_Stream_Heartbeat tests that idle streams get heartbeats and outlive
+// the server's write timeout
+func Test_streamHandler_Stream_Heartbeat(t *testing.T) {
+	t.Parallel()
+
+	// Setup Mock Service that never sends a message
+	mockService := &MockStreamService{
+		streamFunc: func(ctx context.Context, userID int64, slugs []string, lastEventID int64) (<-chan service.StreamMessage, error) {
+			return make(chan service.StreamMessage), nil
+		},
+	}
+
+	// Create a server with a write timeout shorter than the stream is read for
+	writeTimeout := 100 * time.Millisecond
+	handler := NewStreamHandler(mockService, 20*time.Millisecond, writeTimeout)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), middleware.UserIDContextKey, int64(1))
+		handler.Stream()(w, r.WithContext(ctx))
+	}))
+	server.Config.WriteTimeout = writeTimeout
+	server.Start()
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to open stream: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// Read heartbeats for several times the write timeout
+	deadline := time.Now().Add(3 * writeTimeout)
+	heartbeats := 0
+	scanner := bufio.NewScanner(resp.Body)
+	for time.Now().Before(deadline) && scanner.Scan() {
+		if scanner.Text() == ": heartbeat" {
+			heartbeats++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("Stream ended after %d heartbeats: %v", heartbeats, err)
+	}
+	if time.Now().Before(deadline) {
+		t.Fatalf("Stream ended after %d heartbeats", heartbeats)
+	}
+}
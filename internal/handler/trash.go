@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Nilesh2000/conduit/internal/middleware"
+	"github.com/Nilesh2000/conduit/internal/response"
+	"github.com/Nilesh2000/conduit/internal/service"
+)
+
+// TrashResponse is the response for the trash of a user
+type TrashResponse struct {
+	Trash service.Trash `json:"trash"`
+}
+
+// TrashService is an interface for the trash service
+type TrashService interface {
+	GetTrash(ctx context.Context, userID int64) (*service.Trash, error)
+	RestoreArticle(ctx context.Context, userID int64, slug string) (*service.Article, error)
+	RestoreComment(
+		ctx context.Context,
+		userID int64,
+		slug string,
+		commentID int64,
+	) (*service.Comment, error)
+}
+
+// trashHandler is a handler for trash requests
+type trashHandler struct {
+	trashService TrashService
+}
+
+// NewTrashHandler creates a new trash handler
+func NewTrashHandler(trashService TrashService) *trashHandler {
+	return &trashHandler{trashService: trashService}
+}
+
+// GetTrash is a handler for listing the deleted articles and comments of the current user
+func (h *trashHandler) GetTrash() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Set the content type to JSON
+		w.Header().Set("Content-Type", "application/json")
+
+		// Get user ID from context
+		userID, ok := middleware.GetUserIDFromContext(r.Context())
+		if !ok {
+			response.RespondWithError(w, http.StatusUnauthorized, []string{"Unauthorized"})
+			return
+		}
+
+		trash, err := h.trashService.GetTrash(r.Context(), userID)
+		if err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusInternalServerError,
+				[]string{"Internal server error"},
+			)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(TrashResponse{Trash: *trash}); err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusInternalServerError,
+				[]string{"Internal server error"},
+			)
+		}
+	}
+}
+
+// RestoreArticle is a handler for taking an article back out of the trash
+func (h *trashHandler) RestoreArticle() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Set the content type to JSON
+		w.Header().Set("Content-Type", "application/json")
+
+		// Get user ID from context
+		userID, ok := middleware.GetUserIDFromContext(r.Context())
+		if !ok {
+			response.RespondWithError(w, http.StatusUnauthorized, []string{"Unauthorized"})
+			return
+		}
+
+		article, err := h.trashService.RestoreArticle(r.Context(), userID, r.PathValue("slug"))
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrArticleNotFound):
+				response.RespondWithError(
+					w,
+					http.StatusNotFound,
+					[]string{"Article not found in trash"},
+				)
+			default:
+				response.RespondWithError(
+					w,
+					http.StatusInternalServerError,
+					[]string{"Internal server error"},
+				)
+			}
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(ArticleResponse{Article: *article}); err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusInternalServerError,
+				[]string{"Internal server error"},
+			)
+		}
+	}
+}
+
+// RestoreComment is a handler for taking a comment back out of the trash
+func (h *trashHandler) RestoreComment() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Set the content type to JSON
+		w.Header().Set("Content-Type", "application/json")
+
+		// Get user ID from context
+		userID, ok := middleware.GetUserIDFromContext(r.Context())
+		if !ok {
+			response.RespondWithError(w, http.StatusUnauthorized, []string{"Unauthorized"})
+			return
+		}
+
+		commentID, ok := parseCommentID(w, r)
+		if !ok {
+			return
+		}
+
+		comment, err := h.trashService.RestoreComment(
+			r.Context(),
+			userID,
+			r.PathValue("slug"),
+			commentID,
+		)
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrArticleNotFound):
+				response.RespondWithError(w, http.StatusNotFound, []string{"Article not found"})
+			case errors.Is(err, service.ErrCommentNotFound):
+				response.RespondWithError(
+					w,
+					http.StatusNotFound,
+					[]string{"Comment not found in trash"},
+				)
+			case errors.Is(err, service.ErrParentCommentDeleted):
+				response.RespondWithError(
+					w,
+					http.StatusConflict,
+					[]string{"Restore the comment this one replies to first"},
+				)
+			default:
+				response.RespondWithError(
+					w,
+					http.StatusInternalServerError,
+					[]string{"Internal server error"},
+				)
+			}
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(CommentResponse{Comment: *comment}); err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusInternalServerError,
+				[]string{"Internal server error"},
+			)
+		}
+	}
+}
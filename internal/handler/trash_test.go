@@ -0,0 +1,365 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Nilesh2000/conduit/internal/middleware"
+	"github.com/Nilesh2000/conduit/internal/response"
+	"github.com/Nilesh2000/conduit/internal/service"
+)
+
+// MockTrashService is a mock implementation of the TrashService interface
+type MockTrashService struct {
+	getTrashFunc       func(ctx context.Context, userID int64) (*service.Trash, error)
+	restoreArticleFunc func(ctx context.Context, userID int64, slug string) (*service.Article, error)
+	restoreCommentFunc func(ctx context.Context, userID int64, slug string, commentID int64) (*service.Comment, error)
+}
+
+// GetTrash gets the trash of a user in the mock service
+func (m *MockTrashService) GetTrash(ctx context.Context, userID int64) (*service.Trash, error) {
+	return m.getTrashFunc(ctx, userID)
+}
+
+// RestoreArticle restores an article in the mock service
+func (m *MockTrashService) RestoreArticle(
+	ctx context.Context,
+	userID int64,
+	slug string,
+) (*service.Article, error) {
+	return m.restoreArticleFunc(ctx, userID, slug)
+}
+
+// RestoreComment restores a comment in the mock service
+func (m *MockTrashService) RestoreComment(
+	ctx context.Context,
+	userID int64,
+	slug string,
+	commentID int64,
+) (*service.Comment, error) {
+	return m.restoreCommentFunc(ctx, userID, slug, commentID)
+}
+
+func TestTrashHandler_GetTrash(t *testing.T) {
+	t.Parallel()
+
+	deletedAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	trash := service.Trash{
+		Articles: []service.TrashedArticle{
+			{
+				Slug:      "test-article",
+				Title:     "Title",
+				Status:    "published",
+				DeletedAt: deletedAt,
+				ExpiresAt: deletedAt.Add(30 * 24 * time.Hour),
+			},
+		},
+		Comments: []service.TrashedComment{},
+	}
+
+	tests := []struct {
+		name             string
+		authenticated    bool
+		expectedStatus   int
+		expectedResponse any
+	}{
+		{
+			name:             "Trash of the current user",
+			authenticated:    true,
+			expectedStatus:   http.StatusOK,
+			expectedResponse: TrashResponse{Trash: trash},
+		},
+		{
+			name:           "Unauthenticated request",
+			authenticated:  false,
+			expectedStatus: http.StatusUnauthorized,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Unauthorized"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup Mock
+			mockService := &MockTrashService{
+				getTrashFunc: func(ctx context.Context, userID int64) (*service.Trash, error) {
+					return &trash, nil
+				},
+			}
+
+			// Create Handler
+			handler := NewTrashHandler(mockService)
+
+			// Create Request
+			req := httptest.NewRequest(http.MethodGet, "/api/user/trash", nil)
+			if tt.authenticated {
+				ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, int64(1))
+				req = req.WithContext(ctx)
+			}
+
+			// Create Response Recorder
+			rr := httptest.NewRecorder()
+
+			// Serve Request
+			handler.GetTrash()(rr, req)
+
+			// Check Status Code
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("Status code: got %v, want %v", status, tt.expectedStatus)
+			}
+
+			// Check Response Body
+			var got any
+			if tt.expectedStatus == http.StatusOK {
+				var resp TrashResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				got = resp
+			} else {
+				var resp response.GenericErrorModel
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				got = resp
+			}
+
+			// Deep compare expected and got
+			if !reflect.DeepEqual(got, tt.expectedResponse) {
+				t.Errorf("Response body: got %v, want %v", got, tt.expectedResponse)
+			}
+		})
+	}
+}
+
+func TestTrashHandler_RestoreArticle(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		serviceErr       error
+		expectedStatus   int
+		expectedResponse any
+	}{
+		{
+			name:           "Article restored",
+			expectedStatus: http.StatusOK,
+			expectedResponse: ArticleResponse{
+				Article: service.Article{
+					Slug:    "test-article",
+					Title:   "Title",
+					TagList: []string{},
+					Author:  service.Profile{Username: "testuser"},
+				},
+			},
+		},
+		{
+			name:           "Article not in the trash",
+			serviceErr:     service.ErrArticleNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Article not found in trash"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup Mock
+			mockService := &MockTrashService{
+				restoreArticleFunc: func(ctx context.Context, userID int64, slug string) (*service.Article, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &service.Article{
+						Slug:    slug,
+						Title:   "Title",
+						TagList: []string{},
+						Author:  service.Profile{Username: "testuser"},
+					}, nil
+				},
+			}
+
+			// Create Handler
+			handler := NewTrashHandler(mockService)
+
+			// Create Request
+			req := httptest.NewRequest(http.MethodPost, "/api/articles/test-article/restore", nil)
+			req.SetPathValue("slug", "test-article")
+			ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, int64(1))
+			req = req.WithContext(ctx)
+
+			// Create Response Recorder
+			rr := httptest.NewRecorder()
+
+			// Serve Request
+			handler.RestoreArticle()(rr, req)
+
+			// Check Status Code
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("Status code: got %v, want %v", status, tt.expectedStatus)
+			}
+
+			// Check Response Body
+			var got any
+			if tt.expectedStatus == http.StatusOK {
+				var resp ArticleResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				got = resp
+			} else {
+				var resp response.GenericErrorModel
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				got = resp
+			}
+
+			// Deep compare expected and got
+			if !reflect.DeepEqual(got, tt.expectedResponse) {
+				t.Errorf("Response body: got %v, want %v", got, tt.expectedResponse)
+			}
+		})
+	}
+}
+
+func TestTrashHandler_RestoreComment(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		commentID        string
+		serviceErr       error
+		expectedStatus   int
+		expectedResponse any
+	}{
+		{
+			name:           "Comment restored",
+			commentID:      "3",
+			expectedStatus: http.StatusOK,
+			expectedResponse: CommentResponse{
+				Comment: service.Comment{
+					ID:     3,
+					Body:   "Comment",
+					Author: service.Profile{Username: "testuser"},
+				},
+			},
+		},
+		{
+			name:           "Invalid comment ID",
+			commentID:      "abc",
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Invalid comment ID"}},
+			},
+		},
+		{
+			name:           "Comment not in the trash",
+			commentID:      "3",
+			serviceErr:     service.ErrCommentNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Comment not found in trash"}},
+			},
+		},
+		{
+			name:           "Parent comment still in the trash",
+			commentID:      "3",
+			serviceErr:     service.ErrParentCommentDeleted,
+			expectedStatus: http.StatusConflict,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Restore the comment this one replies to first"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup Mock
+			mockService := &MockTrashService{
+				restoreCommentFunc: func(ctx context.Context, userID int64, slug string, commentID int64) (*service.Comment, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &service.Comment{
+						ID:     int(commentID),
+						Body:   "Comment",
+						Author: service.Profile{Username: "testuser"},
+					}, nil
+				},
+			}
+
+			// Create Handler
+			handler := NewTrashHandler(mockService)
+
+			// Create Request
+			req := httptest.NewRequest(
+				http.MethodPost,
+				"/api/articles/test-article/comments/"+tt.commentID+"/restore",
+				nil,
+			)
+			req.SetPathValue("slug", "test-article")
+			req.SetPathValue("id", tt.commentID)
+			ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, int64(1))
+			req = req.WithContext(ctx)
+
+			// Create Response Recorder
+			rr := httptest.NewRecorder()
+
+			// Serve Request
+			handler.RestoreComment()(rr, req)
+
+			// Check Status Code
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("Status code: got %v, want %v", status, tt.expectedStatus)
+			}
+
+			// Check Response Body
+			var got any
+			if tt.expectedStatus == http.StatusOK {
+				var resp CommentResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				got = resp
+			} else {
+				var resp response.GenericErrorModel
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				got = resp
+			}
+
+			// Deep compare expected and got
+			if !reflect.DeepEqual(got, tt.expectedResponse) {
+				t.Errorf("Response body: got %v, want %v", got, tt.expectedResponse)
+			}
+		})
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/Nilesh2000/conduit/internal/middleware"
 	"github.com/Nilesh2000/conduit/internal/response"
@@ -43,6 +45,20 @@ type LoginRequest struct {
 	} `json:"user"`
 }
 
+// RefreshRequest represents the request body for refreshing tokens
+type RefreshRequest struct {
+	User struct {
+		RefreshToken string `json:"refreshToken" validate:"required"`
+	} `json:"user"`
+}
+
+// LogoutRequest represents the request body for logging out
+type LogoutRequest struct {
+	User struct {
+		RefreshToken string `json:"refreshToken"`
+	} `json:"user"`
+}
+
 // UserResponse represents the response body for user operations
 type UserResponse struct {
 	User service.User `json:"user"`
@@ -58,6 +74,14 @@ type UserService interface {
 		userID int64,
 		username, email, password, bio, image *string,
 	) (*service.User, error)
+	Refresh(ctx context.Context, refreshToken string) (*service.User, error)
+	Logout(
+		ctx context.Context,
+		userID int64,
+		accessTokenID string,
+		accessTokenExpiresAt time.Time,
+		refreshToken string,
+	) error
 }
 
 // userHandler handles user-related HTTP requests
@@ -341,3 +365,116 @@ func (h *userHandler) UpdateCurrentUser() http.HandlerFunc {
 		}
 	}
 }
+
+// Refresh returns a handler function for exchanging a refresh token for a new token pair
+func (h *userHandler) Refresh() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Set the content type to JSON
+		w.Header().Set("Content-Type", "application/json")
+
+		// Parse request body
+		var req RefreshRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusUnprocessableEntity,
+				[]string{"Invalid request body"},
+			)
+			return
+		}
+
+		// Validate request body
+		if err := h.validate.Struct(req); err != nil {
+			errors := validation.TranslateValidationErrors(err)
+			response.RespondWithError(w, http.StatusUnprocessableEntity, errors)
+			return
+		}
+
+		// Call service to refresh tokens
+		user, err := h.userService.Refresh(r.Context(), req.User.RefreshToken)
+		// Handle errors
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrInvalidRefreshToken):
+				response.RespondWithError(
+					w,
+					http.StatusUnauthorized,
+					[]string{"Invalid refresh token"},
+				)
+			default:
+				response.RespondWithError(
+					w,
+					http.StatusInternalServerError,
+					[]string{"Internal server error"},
+				)
+			}
+			return
+		}
+
+		// Respond with user data
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(UserResponse{
+			User: *user,
+		}); err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusInternalServerError,
+				[]string{"Internal server error"},
+			)
+		}
+	}
+}
+
+// Logout returns a handler function for revoking the current session
+func (h *userHandler) Logout() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Set the content type to JSON
+		w.Header().Set("Content-Type", "application/json")
+
+		// Get user ID from context
+		userID, ok := middleware.GetUserIDFromContext(r.Context())
+		if !ok {
+			response.RespondWithError(w, http.StatusUnauthorized, []string{"Unauthorized"})
+			return
+		}
+
+		// Get token ID from context
+		tokenID, ok := middleware.GetTokenIDFromContext(r.Context())
+		if !ok {
+			response.RespondWithError(w, http.StatusUnauthorized, []string{"Unauthorized"})
+			return
+		}
+
+		// Get token expiry from context
+		tokenExpiresAt, ok := middleware.GetTokenExpiresAtFromContext(r.Context())
+		if !ok {
+			response.RespondWithError(w, http.StatusUnauthorized, []string{"Unauthorized"})
+			return
+		}
+
+		// Parse optional request body
+		var req LogoutRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+			response.RespondWithError(
+				w,
+				http.StatusUnprocessableEntity,
+				[]string{"Invalid request body"},
+			)
+			return
+		}
+
+		// Call service to logout user
+		err := h.userService.Logout(r.Context(), userID, tokenID, tokenExpiresAt, req.User.RefreshToken)
+		if err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusInternalServerError,
+				[]string{"Internal server error"},
+			)
+			return
+		}
+
+		// Respond with no content
+		w.WriteHeader(http.StatusOK)
+	}
+}
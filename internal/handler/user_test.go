@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/Nilesh2000/conduit/internal/middleware"
 	"github.com/Nilesh2000/conduit/internal/response"
@@ -20,6 +21,8 @@ type MockUserService struct {
 	loginFunc          func(ctx context.Context, email, password string) (*service.User, error)
 	getCurrentUserFunc func(ctx context.Context, userID int64) (*service.User, error)
 	updateUserFunc     func(ctx context.Context, userID int64, username, email, password, bio, image *string) (*service.User, error)
+	refreshFunc        func(ctx context.Context, refreshToken string) (*service.User, error)
+	logoutFunc         func(ctx context.Context, userID int64, accessTokenID string, accessTokenExpiresAt time.Time, refreshToken string) error
 }
 
 // Ensure MockUserService implements the UserService interface
@@ -55,6 +58,22 @@ func (m *MockUserService) UpdateUser(
 	return m.updateUserFunc(ctx, userID, username, email, password, bio, image)
 }
 
+// Refresh refreshes the tokens of a user in the mock service
+func (m *MockUserService) Refresh(ctx context.Context, refreshToken string) (*service.User, error) {
+	return m.refreshFunc(ctx, refreshToken)
+}
+
+// Logout logs out a user in the mock service
+func (m *MockUserService) Logout(
+	ctx context.Context,
+	userID int64,
+	accessTokenID string,
+	accessTokenExpiresAt time.Time,
+	refreshToken string,
+) error {
+	return m.logoutFunc(ctx, userID, accessTokenID, accessTokenExpiresAt, refreshToken)
+}
+
 // TestUserHandler_Register tests the Register method of the UserHandler
 func TestUserHandler_Register(t *testing.T) {
 	t.Parallel()
@@ -1268,3 +1287,273 @@ func TestUserHandler_UpdateCurrentUser(t *testing.T) {
 		})
 	}
 }
+
+// TestUserHandler_Refresh tests the Refresh method of the UserHandler
+func TestUserHandler_Refresh(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		requestBody      string
+		setupMock        func() *MockUserService
+		expectedStatus   int
+		expectedResponse any
+	}{
+		{
+			name:        "Valid refresh",
+			requestBody: `{"user": {"refreshToken": "refresh-token"}}`,
+			setupMock: func() *MockUserService {
+				return &MockUserService{
+					refreshFunc: func(ctx context.Context, refreshToken string) (*service.User, error) {
+						if refreshToken != "refresh-token" {
+							t.Errorf("Expected refresh token %q, got %q", "refresh-token", refreshToken)
+						}
+						return &service.User{
+							Email:        "test@example.com",
+							Token:        "new.jwt.token",
+							RefreshToken: "new-refresh-token",
+							Username:     "testuser",
+						}, nil
+					},
+				}
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: UserResponse{
+				User: service.User{
+					Email:        "test@example.com",
+					Token:        "new.jwt.token",
+					RefreshToken: "new-refresh-token",
+					Username:     "testuser",
+				},
+			},
+		},
+		{
+			name:        "Missing refresh token",
+			requestBody: `{"user": {}}`,
+			setupMock: func() *MockUserService {
+				return &MockUserService{
+					refreshFunc: func(ctx context.Context, refreshToken string) (*service.User, error) {
+						t.Errorf("Refresh should not be called without a refresh token")
+						return nil, nil
+					},
+				}
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"RefreshToken is required"}},
+			},
+		},
+		{
+			name:        "Invalid refresh token",
+			requestBody: `{"user": {"refreshToken": "reused-token"}}`,
+			setupMock: func() *MockUserService {
+				return &MockUserService{
+					refreshFunc: func(ctx context.Context, refreshToken string) (*service.User, error) {
+						return nil, service.ErrInvalidRefreshToken
+					},
+				}
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Invalid refresh token"}},
+			},
+		},
+		{
+			name:        "Internal server error",
+			requestBody: `{"user": {"refreshToken": "refresh-token"}}`,
+			setupMock: func() *MockUserService {
+				return &MockUserService{
+					refreshFunc: func(ctx context.Context, refreshToken string) (*service.User, error) {
+						return nil, service.ErrInternalServer
+					},
+				}
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedResponse: response.GenericErrorModel{
+				Errors: struct {
+					Body []string `json:"body"`
+				}{Body: []string{"Internal server error"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Create handler
+			userHandler := NewUserHandler(tt.setupMock())
+
+			// Create request
+			req := httptest.NewRequest(
+				http.MethodPost,
+				"/api/users/refresh",
+				bytes.NewReader([]byte(tt.requestBody)),
+			)
+			req.Header.Set("Content-Type", "application/json")
+
+			// Serve request
+			rr := httptest.NewRecorder()
+			userHandler.Refresh().ServeHTTP(rr, req)
+
+			// Check status code
+			if got, want := rr.Code, tt.expectedStatus; got != want {
+				t.Errorf("Status code: got %v, want %v", got, want)
+			}
+
+			// Check response body
+			var got any
+			if tt.expectedStatus == http.StatusOK {
+				var resp UserResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				got = resp
+			} else {
+				var resp response.GenericErrorModel
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				got = resp
+			}
+
+			if !reflect.DeepEqual(got, tt.expectedResponse) {
+				t.Errorf("Response body: got %v, want %v", got, tt.expectedResponse)
+			}
+		})
+	}
+}
+
+// TestUserHandler_Logout tests the Logout method of the UserHandler
+func TestUserHandler_Logout(t *testing.T) {
+	t.Parallel()
+
+	tokenExpiresAt := time.Date(2024, 1, 1, 12, 15, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		requestBody    string
+		setupAuth      func(r *http.Request) *http.Request
+		setupMock      func() *MockUserService
+		expectedStatus int
+	}{
+		{
+			name:        "Logout with refresh token",
+			requestBody: `{"user": {"refreshToken": "refresh-token"}}`,
+			setupAuth: func(r *http.Request) *http.Request {
+				ctx := context.WithValue(r.Context(), middleware.UserIDContextKey, int64(1))
+				ctx = context.WithValue(ctx, middleware.TokenIDContextKey, "token-id")
+				ctx = context.WithValue(ctx, middleware.TokenExpiresAtContextKey, tokenExpiresAt)
+				return r.WithContext(ctx)
+			},
+			setupMock: func() *MockUserService {
+				return &MockUserService{
+					logoutFunc: func(ctx context.Context, userID int64, accessTokenID string, accessTokenExpiresAt time.Time, refreshToken string) error {
+						if userID != 1 || accessTokenID != "token-id" || refreshToken != "refresh-token" {
+							t.Errorf(
+								"Expected Logout(1, %q, %q), got Logout(%d, %q, %q)",
+								"token-id",
+								"refresh-token",
+								userID,
+								accessTokenID,
+								refreshToken,
+							)
+						}
+						if !accessTokenExpiresAt.Equal(tokenExpiresAt) {
+							t.Errorf("Expected the token to expire at %v, got %v", tokenExpiresAt, accessTokenExpiresAt)
+						}
+						return nil
+					},
+				}
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:        "Logout without body",
+			requestBody: "",
+			setupAuth: func(r *http.Request) *http.Request {
+				ctx := context.WithValue(r.Context(), middleware.UserIDContextKey, int64(1))
+				ctx = context.WithValue(ctx, middleware.TokenIDContextKey, "token-id")
+				ctx = context.WithValue(ctx, middleware.TokenExpiresAtContextKey, tokenExpiresAt)
+				return r.WithContext(ctx)
+			},
+			setupMock: func() *MockUserService {
+				return &MockUserService{
+					logoutFunc: func(ctx context.Context, userID int64, accessTokenID string, accessTokenExpiresAt time.Time, refreshToken string) error {
+						if refreshToken != "" {
+							t.Errorf("Expected empty refresh token, got %q", refreshToken)
+						}
+						return nil
+					},
+				}
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:        "Unauthenticated",
+			requestBody: "",
+			setupAuth: func(r *http.Request) *http.Request {
+				return r
+			},
+			setupMock: func() *MockUserService {
+				return &MockUserService{
+					logoutFunc: func(ctx context.Context, userID int64, accessTokenID string, accessTokenExpiresAt time.Time, refreshToken string) error {
+						t.Errorf("Logout should not be called when unauthenticated")
+						return nil
+					},
+				}
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:        "Internal server error",
+			requestBody: "",
+			setupAuth: func(r *http.Request) *http.Request {
+				ctx := context.WithValue(r.Context(), middleware.UserIDContextKey, int64(1))
+				ctx = context.WithValue(ctx, middleware.TokenIDContextKey, "token-id")
+				ctx = context.WithValue(ctx, middleware.TokenExpiresAtContextKey, tokenExpiresAt)
+				return r.WithContext(ctx)
+			},
+			setupMock: func() *MockUserService {
+				return &MockUserService{
+					logoutFunc: func(ctx context.Context, userID int64, accessTokenID string, accessTokenExpiresAt time.Time, refreshToken string) error {
+						return service.ErrInternalServer
+					},
+				}
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Create handler
+			userHandler := NewUserHandler(tt.setupMock())
+
+			// Create request
+			req := httptest.NewRequest(
+				http.MethodPost,
+				"/api/users/logout",
+				bytes.NewReader([]byte(tt.requestBody)),
+			)
+			req = tt.setupAuth(req)
+
+			// Serve request
+			rr := httptest.NewRecorder()
+			userHandler.Logout().ServeHTTP(rr, req)
+
+			// Check status code
+			if got, want := rr.Code, tt.expectedStatus; got != want {
+				t.Errorf("Status code: got %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,403 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Nilesh2000/conduit/internal/cursor"
+	"github.com/Nilesh2000/conduit/internal/middleware"
+	"github.com/Nilesh2000/conduit/internal/repository"
+	"github.com/Nilesh2000/conduit/internal/response"
+	"github.com/Nilesh2000/conduit/internal/service"
+)
+
+// WebhookResponse is the response body for a webhook
+type WebhookResponse struct {
+	Webhook service.Webhook `json:"webhook"`
+}
+
+// WebhooksResponse is the response body for the webhooks of the current user
+type WebhooksResponse struct {
+	Webhooks []service.Webhook `json:"webhooks"`
+}
+
+// WebhookDeliveryResponse is the response body for a webhook delivery
+type WebhookDeliveryResponse struct {
+	Delivery service.WebhookDelivery `json:"delivery"`
+}
+
+// WebhookDeliveriesResponse is the response body for a page of the deliveries of a
+// webhook
+type WebhookDeliveriesResponse struct {
+	Deliveries []service.WebhookDelivery `json:"deliveries"`
+	NextCursor string                    `json:"nextCursor,omitempty"`
+	PrevCursor string                    `json:"prevCursor,omitempty"`
+}
+
+// CreateWebhookRequest is the request body for creating a webhook
+type CreateWebhookRequest struct {
+	Webhook struct {
+		URL    string   `json:"url"`
+		Events []string `json:"events"`
+	} `json:"webhook"`
+}
+
+// UpdateWebhookRequest is the request body for updating a webhook. Fields left out are
+// not changed.
+type UpdateWebhookRequest struct {
+	Webhook struct {
+		URL    *string  `json:"url"`
+		Events []string `json:"events"`
+		Active *bool    `json:"active"`
+	} `json:"webhook"`
+}
+
+// WebhookService is an interface for the webhook service
+type WebhookService interface {
+	CreateWebhook(ctx context.Context, userID int64, url string, events []string) (*service.Webhook, error)
+	ListWebhooks(ctx context.Context, userID int64) ([]service.Webhook, error)
+	UpdateWebhook(
+		ctx context.Context,
+		userID, webhookID int64,
+		url *string,
+		events []string,
+		active *bool,
+	) (*service.Webhook, error)
+	DeleteWebhook(ctx context.Context, userID, webhookID int64) error
+	ListDeliveries(
+		ctx context.Context,
+		userID, webhookID int64,
+		filters repository.WebhookDeliveryFilters,
+	) (*service.WebhookDeliveryList, error)
+	ReplayDelivery(
+		ctx context.Context,
+		userID, webhookID, deliveryID int64,
+	) (*service.WebhookDelivery, error)
+}
+
+// webhookHandler is a handler for webhook requests
+type webhookHandler struct {
+	webhookService WebhookService
+	cursors        *cursor.Signer
+}
+
+// NewWebhookHandler creates a new webhook handler that signs the cursors of delivery
+// lists with cursors
+func NewWebhookHandler(webhookService WebhookService, cursors *cursor.Signer) *webhookHandler {
+	return &webhookHandler{
+		webhookService: webhookService,
+		cursors:        cursors,
+	}
+}
+
+// CreateWebhook is a handler for creating a webhook for the current user. The response
+// holds the secret the deliveries of the webhook are signed with, which is not shown
+// again.
+func (h *webhookHandler) CreateWebhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Set the content type to JSON
+		w.Header().Set("Content-Type", "application/json")
+
+		// Get user ID from context
+		userID, ok := middleware.GetUserIDFromContext(r.Context())
+		if !ok {
+			response.RespondWithError(w, http.StatusUnauthorized, []string{"Unauthorized"})
+			return
+		}
+
+		// Parse request body
+		var req CreateWebhookRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusUnprocessableEntity,
+				[]string{"Invalid request body"},
+			)
+			return
+		}
+
+		webhook, err := h.webhookService.CreateWebhook(
+			r.Context(),
+			userID,
+			req.Webhook.URL,
+			req.Webhook.Events,
+		)
+		if err != nil {
+			respondWithWebhookError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(WebhookResponse{Webhook: *webhook}); err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusInternalServerError,
+				[]string{"Internal server error"},
+			)
+		}
+	}
+}
+
+// ListWebhooks is a handler for listing the webhooks of the current user
+func (h *webhookHandler) ListWebhooks() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Set the content type to JSON
+		w.Header().Set("Content-Type", "application/json")
+
+		// Get user ID from context
+		userID, ok := middleware.GetUserIDFromContext(r.Context())
+		if !ok {
+			response.RespondWithError(w, http.StatusUnauthorized, []string{"Unauthorized"})
+			return
+		}
+
+		webhooks, err := h.webhookService.ListWebhooks(r.Context(), userID)
+		if err != nil {
+			respondWithWebhookError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(WebhooksResponse{Webhooks: webhooks}); err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusInternalServerError,
+				[]string{"Internal server error"},
+			)
+		}
+	}
+}
+
+// UpdateWebhook is a handler for updating a webhook of the current user
+func (h *webhookHandler) UpdateWebhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Set the content type to JSON
+		w.Header().Set("Content-Type", "application/json")
+
+		// Get user ID from context
+		userID, ok := middleware.GetUserIDFromContext(r.Context())
+		if !ok {
+			response.RespondWithError(w, http.StatusUnauthorized, []string{"Unauthorized"})
+			return
+		}
+
+		webhookID, ok := parseWebhookID(w, r)
+		if !ok {
+			return
+		}
+
+		// Parse request body
+		var req UpdateWebhookRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusUnprocessableEntity,
+				[]string{"Invalid request body"},
+			)
+			return
+		}
+
+		webhook, err := h.webhookService.UpdateWebhook(
+			r.Context(),
+			userID,
+			webhookID,
+			req.Webhook.URL,
+			req.Webhook.Events,
+			req.Webhook.Active,
+		)
+		if err != nil {
+			respondWithWebhookError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(WebhookResponse{Webhook: *webhook}); err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusInternalServerError,
+				[]string{"Internal server error"},
+			)
+		}
+	}
+}
+
+// DeleteWebhook is a handler for deleting a webhook of the current user
+func (h *webhookHandler) DeleteWebhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get user ID from context
+		userID, ok := middleware.GetUserIDFromContext(r.Context())
+		if !ok {
+			response.RespondWithError(w, http.StatusUnauthorized, []string{"Unauthorized"})
+			return
+		}
+
+		webhookID, ok := parseWebhookID(w, r)
+		if !ok {
+			return
+		}
+
+		if err := h.webhookService.DeleteWebhook(r.Context(), userID, webhookID); err != nil {
+			respondWithWebhookError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// ListDeliveries is a handler for listing a page of the deliveries of a webhook of the
+// current user, newest first
+func (h *webhookHandler) ListDeliveries() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Set the content type to JSON
+		w.Header().Set("Content-Type", "application/json")
+
+		// Get user ID from context
+		userID, ok := middleware.GetUserIDFromContext(r.Context())
+		if !ok {
+			response.RespondWithError(w, http.StatusUnauthorized, []string{"Unauthorized"})
+			return
+		}
+
+		webhookID, ok := parseWebhookID(w, r)
+		if !ok {
+			return
+		}
+
+		filters := repository.WebhookDeliveryFilters{
+			Limit: 20, // Default limit
+		}
+
+		// Parse limit parameter
+		if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+			limit, err := strconv.Atoi(limitStr)
+			if err != nil || limit <= 0 {
+				response.RespondWithError(
+					w,
+					http.StatusUnprocessableEntity,
+					[]string{"Limit must be a positive integer"},
+				)
+				return
+			}
+			filters.Limit = limit
+		}
+
+		// Parse cursor parameters
+		if filters.After, filters.Before, ok = parseCursors(w, r, h.cursors); !ok {
+			return
+		}
+
+		result, err := h.webhookService.ListDeliveries(r.Context(), userID, webhookID, filters)
+		if err != nil {
+			respondWithWebhookError(w, err)
+			return
+		}
+
+		resp := WebhookDeliveriesResponse{Deliveries: result.Deliveries}
+		page := listPage{
+			after:   filters.After,
+			before:  filters.Before,
+			first:   result.First,
+			last:    result.Last,
+			hasMore: result.HasMore,
+		}
+		resp.PrevCursor, resp.NextCursor = page.cursors(h.cursors)
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusInternalServerError,
+				[]string{"Internal server error"},
+			)
+		}
+	}
+}
+
+// ReplayDelivery is a handler for sending a delivery of a webhook of the current user
+// again. The delivery is queued as a new one, which is sent shortly after.
+func (h *webhookHandler) ReplayDelivery() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Set the content type to JSON
+		w.Header().Set("Content-Type", "application/json")
+
+		// Get user ID from context
+		userID, ok := middleware.GetUserIDFromContext(r.Context())
+		if !ok {
+			response.RespondWithError(w, http.StatusUnauthorized, []string{"Unauthorized"})
+			return
+		}
+
+		webhookID, ok := parseWebhookID(w, r)
+		if !ok {
+			return
+		}
+
+		// Get delivery ID from URL path
+		deliveryID, err := strconv.ParseInt(r.PathValue("deliveryId"), 10, 64)
+		if err != nil {
+			response.RespondWithError(w, http.StatusBadRequest, []string{"Invalid delivery ID"})
+			return
+		}
+
+		delivery, err := h.webhookService.ReplayDelivery(r.Context(), userID, webhookID, deliveryID)
+		if err != nil {
+			respondWithWebhookError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+		if err := json.NewEncoder(w).Encode(WebhookDeliveryResponse{Delivery: *delivery}); err != nil {
+			response.RespondWithError(
+				w,
+				http.StatusInternalServerError,
+				[]string{"Internal server error"},
+			)
+		}
+	}
+}
+
+// parseWebhookID parses the webhook ID in the URL path, responding with an error if it
+// is not a number
+func parseWebhookID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	webhookID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		response.RespondWithError(w, http.StatusBadRequest, []string{"Invalid webhook ID"})
+		return 0, false
+	}
+	return webhookID, true
+}
+
+// respondWithWebhookError responds with the error of a webhook request
+func respondWithWebhookError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidWebhookURL):
+		response.RespondWithError(
+			w,
+			http.StatusUnprocessableEntity,
+			[]string{"Webhook URL must be an absolute http or https URL"},
+		)
+	case errors.Is(err, service.ErrInvalidWebhookEvents):
+		response.RespondWithError(
+			w,
+			http.StatusUnprocessableEntity,
+			[]string{"Webhook events must be one or more of " + strings.Join(repository.WebhookEvents, ", ")},
+		)
+	case errors.Is(err, service.ErrWebhookNotFound):
+		response.RespondWithError(w, http.StatusNotFound, []string{"Webhook not found"})
+	case errors.Is(err, service.ErrWebhookDeliveryNotFound):
+		response.RespondWithError(w, http.StatusNotFound, []string{"Delivery not found"})
+	case errors.Is(err, service.ErrUserNotFound):
+		response.RespondWithError(w, http.StatusNotFound, []string{"User not found"})
+	default:
+		response.RespondWithError(
+			w,
+			http.StatusInternalServerError,
+			[]string{"Internal server error"},
+		)
+	}
+}
@@ -0,0 +1,388 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Nilesh2000/conduit/internal/middleware"
+	"github.com/Nilesh2000/conduit/internal/repository"
+	"github.com/Nilesh2000/conduit/internal/response"
+	"github.com/Nilesh2000/conduit/internal/service"
+)
+
+// MockWebhookService is a mock implementation of the WebhookService interface
+type MockWebhookService struct {
+	createWebhookFunc  func(ctx context.Context, userID int64, url string, events []string) (*service.Webhook, error)
+	listWebhooksFunc   func(ctx context.Context, userID int64) ([]service.Webhook, error)
+	updateWebhookFunc  func(ctx context.Context, userID, webhookID int64, url *string, events []string, active *bool) (*service.Webhook, error)
+	deleteWebhookFunc  func(ctx context.Context, userID, webhookID int64) error
+	listDeliveriesFunc func(ctx context.Context, userID, webhookID int64, filters repository.WebhookDeliveryFilters) (*service.WebhookDeliveryList, error)
+	replayDeliveryFunc func(ctx context.Context, userID, webhookID, deliveryID int64) (*service.WebhookDelivery, error)
+}
+
+// CreateWebhook creates a webhook in the mock service
+func (m *MockWebhookService) CreateWebhook(
+	ctx context.Context,
+	userID int64,
+	url string,
+	events []string,
+) (*service.Webhook, error) {
+	return m.createWebhookFunc(ctx, userID, url, events)
+}
+
+// ListWebhooks lists the webhooks of a user in the mock service
+func (m *MockWebhookService) ListWebhooks(ctx context.Context, userID int64) ([]service.Webhook, error) {
+	return m.listWebhooksFunc(ctx, userID)
+}
+
+// UpdateWebhook updates a webhook in the mock service
+func (m *MockWebhookService) UpdateWebhook(
+	ctx context.Context,
+	userID, webhookID int64,
+	url *string,
+	events []string,
+	active *bool,
+) (*service.Webhook, error) {
+	return m.updateWebhookFunc(ctx, userID, webhookID, url, events, active)
+}
+
+// DeleteWebhook deletes a webhook in the mock service
+func (m *MockWebhookService) DeleteWebhook(ctx context.Context, userID, webhookID int64) error {
+	return m.deleteWebhookFunc(ctx, userID, webhookID)
+}
+
+// ListDeliveries lists the deliveries of a webhook in the mock service
+func (m *MockWebhookService) ListDeliveries(
+	ctx context.Context,
+	userID, webhookID int64,
+	filters repository.WebhookDeliveryFilters,
+) (*service.WebhookDeliveryList, error) {
+	return m.listDeliveriesFunc(ctx, userID, webhookID, filters)
+}
+
+// ReplayDelivery replays a delivery in the mock service
+func (m *MockWebhookService) ReplayDelivery(
+	ctx context.Context,
+	userID, webhookID, deliveryID int64,
+) (*service.WebhookDelivery, error) {
+	return m.replayDeliveryFunc(ctx, userID, webhookID, deliveryID)
+}
+
+// Test_webhookHandler_CreateWebhook tests the CreateWebhook method of the webhookHandler
+func Test_webhookHandler_CreateWebhook(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		body           string
+		serviceErr     error
+		expectedStatus int
+		expectedErrors []string
+	}{
+		{
+			name:           "Webhook created",
+			body:           `{"webhook":{"url":"https://example.com/hook","events":["article.published"]}}`,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "Invalid request body",
+			body:           `{"webhook":`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedErrors: []string{"Invalid request body"},
+		},
+		{
+			name:           "Invalid URL",
+			body:           `{"webhook":{"url":"example.com","events":["article.published"]}}`,
+			serviceErr:     service.ErrInvalidWebhookURL,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedErrors: []string{"Webhook URL must be an absolute http or https URL"},
+		},
+		{
+			name:           "Invalid events",
+			body:           `{"webhook":{"url":"https://example.com/hook","events":["user.deleted"]}}`,
+			serviceErr:     service.ErrInvalidWebhookEvents,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedErrors: []string{
+				"Webhook events must be one or more of article.published, article.updated, article.deleted, comment.created",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup Mock Service
+			mockService := &MockWebhookService{
+				createWebhookFunc: func(ctx context.Context, userID int64, url string, events []string) (*service.Webhook, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &service.Webhook{ID: 3, URL: url, Events: events, Active: true, Secret: "whsec_test"}, nil
+				},
+			}
+
+			// Create Handler
+			handler := NewWebhookHandler(mockService, testCursors)
+
+			// Create Request
+			req := httptest.NewRequest(http.MethodPost, "/api/user/webhooks", strings.NewReader(tt.body))
+			ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, int64(1))
+			req = req.WithContext(ctx)
+
+			// Create Response Recorder
+			rr := httptest.NewRecorder()
+
+			// Serve Request
+			handler.CreateWebhook()(rr, req)
+
+			// Check Status Code
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("Status code: got %v, want %v", status, tt.expectedStatus)
+			}
+
+			// Check Response Body
+			if tt.expectedStatus == http.StatusCreated {
+				var resp WebhookResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				if resp.Webhook.ID != 3 || resp.Webhook.Secret != "whsec_test" {
+					t.Errorf("Expected webhook 3 with its secret, got %+v", resp.Webhook)
+				}
+			} else {
+				var resp response.GenericErrorModel
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				if !reflect.DeepEqual(resp.Errors.Body, tt.expectedErrors) {
+					t.Errorf("Expected errors %v, got %v", tt.expectedErrors, resp.Errors.Body)
+				}
+			}
+		})
+	}
+}
+
+// Test_webhookHandler_ListDeliveries tests the ListDeliveries method of the
+// webhookHandler
+func Test_webhookHandler_ListDeliveries(t *testing.T) {
+	t.Parallel()
+
+	createdAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	last := repository.Cursor{CreatedAt: createdAt, ID: 5}
+
+	tests := []struct {
+		name            string
+		id              string
+		query           string
+		serviceErr      error
+		expectedStatus  int
+		expectedFilters repository.WebhookDeliveryFilters
+		expectedErrors  []string
+	}{
+		{
+			name:            "Default page",
+			id:              "3",
+			expectedStatus:  http.StatusOK,
+			expectedFilters: repository.WebhookDeliveryFilters{Limit: 20},
+		},
+		{
+			name:            "Page after a cursor",
+			id:              "3",
+			query:           "?limit=1&after=" + testCursors.Encode(last),
+			expectedStatus:  http.StatusOK,
+			expectedFilters: repository.WebhookDeliveryFilters{Limit: 1, After: &last},
+		},
+		{
+			name:           "Invalid limit",
+			id:             "3",
+			query:          "?limit=-1",
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedErrors: []string{"Limit must be a positive integer"},
+		},
+		{
+			name:           "Invalid webhook ID",
+			id:             "abc",
+			expectedStatus: http.StatusBadRequest,
+			expectedErrors: []string{"Invalid webhook ID"},
+		},
+		{
+			name:            "Webhook not found",
+			id:              "4",
+			serviceErr:      service.ErrWebhookNotFound,
+			expectedStatus:  http.StatusNotFound,
+			expectedFilters: repository.WebhookDeliveryFilters{Limit: 20},
+			expectedErrors:  []string{"Webhook not found"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup Mock Service
+			mockService := &MockWebhookService{
+				listDeliveriesFunc: func(ctx context.Context, userID, webhookID int64, filters repository.WebhookDeliveryFilters) (*service.WebhookDeliveryList, error) {
+					if !reflect.DeepEqual(filters, tt.expectedFilters) {
+						t.Errorf("Expected filters %+v, got %+v", tt.expectedFilters, filters)
+					}
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &service.WebhookDeliveryList{
+						Deliveries: []service.WebhookDelivery{
+							{ID: 5, Event: repository.WebhookEventArticlePublished, Status: repository.WebhookDeliverySucceeded, CreatedAt: createdAt},
+						},
+						HasMore: true,
+						First:   &last,
+						Last:    &last,
+					}, nil
+				},
+			}
+
+			// Create Handler
+			handler := NewWebhookHandler(mockService, testCursors)
+
+			// Create Request
+			req := httptest.NewRequest(http.MethodGet, "/api/user/webhooks/"+tt.id+"/deliveries"+tt.query, nil)
+			req.SetPathValue("id", tt.id)
+			ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, int64(1))
+			req = req.WithContext(ctx)
+
+			// Create Response Recorder
+			rr := httptest.NewRecorder()
+
+			// Serve Request
+			handler.ListDeliveries()(rr, req)
+
+			// Check Status Code
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("Status code: got %v, want %v", status, tt.expectedStatus)
+			}
+
+			// Check Response Body
+			if tt.expectedStatus == http.StatusOK {
+				var resp WebhookDeliveriesResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				if len(resp.Deliveries) != 1 || resp.NextCursor != testCursors.Encode(last) {
+					t.Errorf("Expected a delivery and a next cursor, got %+v", resp)
+				}
+			} else {
+				var resp response.GenericErrorModel
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				if !reflect.DeepEqual(resp.Errors.Body, tt.expectedErrors) {
+					t.Errorf("Expected errors %v, got %v", tt.expectedErrors, resp.Errors.Body)
+				}
+			}
+		})
+	}
+}
+
+// Test_webhookHandler_ReplayDelivery tests the ReplayDelivery method of the
+// webhookHandler
+func Test_webhookHandler_ReplayDelivery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		deliveryID     string
+		serviceErr     error
+		expectedStatus int
+		expectedErrors []string
+	}{
+		{
+			name:           "Delivery replayed",
+			deliveryID:     "5",
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:           "Invalid delivery ID",
+			deliveryID:     "abc",
+			expectedStatus: http.StatusBadRequest,
+			expectedErrors: []string{"Invalid delivery ID"},
+		},
+		{
+			name:           "Delivery not found",
+			deliveryID:     "6",
+			serviceErr:     service.ErrWebhookDeliveryNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedErrors: []string{"Delivery not found"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup Mock Service
+			mockService := &MockWebhookService{
+				replayDeliveryFunc: func(ctx context.Context, userID, webhookID, deliveryID int64) (*service.WebhookDelivery, error) {
+					if userID != 1 || webhookID != 3 {
+						t.Errorf("Expected webhook 3 of user 1, got webhook %d of user %d", webhookID, userID)
+					}
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &service.WebhookDelivery{ID: 8, Status: repository.WebhookDeliveryPending, Payload: json.RawMessage(`{}`)}, nil
+				},
+			}
+
+			// Create Handler
+			handler := NewWebhookHandler(mockService, testCursors)
+
+			// Create Request
+			req := httptest.NewRequest(
+				http.MethodPost,
+				"/api/user/webhooks/3/deliveries/"+tt.deliveryID+"/replay",
+				nil,
+			)
+			req.SetPathValue("id", "3")
+			req.SetPathValue("deliveryId", tt.deliveryID)
+			ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, int64(1))
+			req = req.WithContext(ctx)
+
+			// Create Response Recorder
+			rr := httptest.NewRecorder()
+
+			// Serve Request
+			handler.ReplayDelivery()(rr, req)
+
+			// Check Status Code
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("Status code: got %v, want %v", status, tt.expectedStatus)
+			}
+
+			// Check Response Body
+			if tt.expectedStatus == http.StatusAccepted {
+				var resp WebhookDeliveryResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				if resp.Delivery.ID != 8 {
+					t.Errorf("Expected delivery 8, got %+v", resp.Delivery)
+				}
+			} else {
+				var resp response.GenericErrorModel
+				if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				if !reflect.DeepEqual(resp.Errors.Body, tt.expectedErrors) {
+					t.Errorf("Expected errors %v, got %v", tt.expectedErrors, resp.Errors.Body)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,391 @@
+// Package jwtkeys manages the keys used to sign and verify JWTs.
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/Nilesh2000/conduit/internal/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minRSAKeyBits is the smallest RSA modulus accepted for signing or verification
+const minRSAKeyBits = 2048
+
+var (
+	// ErrUnknownKey is returned when a token references a key that is not in the key set
+	ErrUnknownKey = errors.New("unknown signing key")
+	// ErrUnsupportedKey is returned when a PEM file holds a key of an unsupported type
+	ErrUnsupportedKey = errors.New("unsupported key type")
+)
+
+// Key represents a key used to sign or verify JWTs
+type Key struct {
+	// ID is the key ID (kid). For asymmetric keys it is the RFC 7638 thumbprint of the
+	// public key, so the same key always gets the same ID.
+	ID     string
+	Method jwt.SigningMethod
+
+	signingKey      any
+	verificationKey any
+}
+
+// KeySet represents the key used to sign new tokens and the keys accepted when
+// verifying them
+type KeySet struct {
+	signing *Key
+	keys    map[string]*Key
+	hmac    *Key
+}
+
+// JWK represents a public key in JSON Web Key format
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKSet represents a JSON Web Key Set
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewHMACKeySet creates a KeySet that signs and verifies tokens with an HS256 secret
+func NewHMACKeySet(secret []byte) *KeySet {
+	key := newHMACKey(secret)
+	return &KeySet{
+		signing: key,
+		keys:    map[string]*Key{},
+		hmac:    key,
+	}
+}
+
+// NewKeySet creates a KeySet that signs tokens with an asymmetric key. Tokens signed with
+// the signing key or any of the verification keys are accepted. If hmacSecret is not
+// empty, HS256 tokens signed with it are accepted as well.
+func NewKeySet(signing *Key, verification []*Key, hmacSecret []byte) (*KeySet, error) {
+	if signing == nil || signing.signingKey == nil {
+		return nil, errors.New("signing key must include a private key")
+	}
+
+	ks := &KeySet{
+		signing: signing,
+		keys:    map[string]*Key{signing.ID: signing},
+	}
+
+	for _, key := range verification {
+		if key.Method == jwt.SigningMethodHS256 {
+			return nil, errors.New("verification keys must be asymmetric")
+		}
+		ks.keys[key.ID] = key
+	}
+
+	if len(hmacSecret) > 0 {
+		ks.hmac = newHMACKey(hmacSecret)
+	}
+
+	return ks, nil
+}
+
+// FromConfig creates a KeySet from the JWT configuration
+func FromConfig(cfg config.JWT) (*KeySet, error) {
+	if cfg.Algorithm == config.AlgorithmHS256 {
+		return NewHMACKeySet([]byte(cfg.SecretKey)), nil
+	}
+
+	signing, err := LoadPrivateKey(cfg.PrivateKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	if signing.Method.Alg() != cfg.Algorithm {
+		return nil, fmt.Errorf(
+			"private key %s is a %s key, expected %s",
+			cfg.PrivateKeyFile,
+			signing.Method.Alg(),
+			cfg.Algorithm,
+		)
+	}
+
+	verification := make([]*Key, 0, len(cfg.PublicKeyFiles))
+	for _, path := range cfg.PublicKeyFiles {
+		key, err := LoadPublicKey(path)
+		if err != nil {
+			return nil, err
+		}
+		verification = append(verification, key)
+	}
+
+	var hmacSecret []byte
+	if cfg.HMACFallback {
+		hmacSecret = []byte(cfg.SecretKey)
+	}
+
+	return NewKeySet(signing, verification, hmacSecret)
+}
+
+// LoadPrivateKey loads an RSA or Ed25519 private key from a PEM file
+func LoadPrivateKey(path string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private key: %w", err)
+	}
+
+	key, err := ParsePrivateKeyPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key %s: %w", path, err)
+	}
+
+	return key, nil
+}
+
+// LoadPublicKey loads an RSA or Ed25519 public key or certificate from a PEM file
+func LoadPublicKey(path string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read public key: %w", err)
+	}
+
+	key, err := ParsePublicKeyPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key %s: %w", path, err)
+	}
+
+	return key, nil
+}
+
+// ParsePrivateKeyPEM parses a PKCS #8 or PKCS #1 encoded private key
+func ParsePrivateKeyPEM(data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var private any
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		private, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		private, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unexpected PEM block type %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := private.(crypto.Signer)
+	if !ok {
+		return nil, ErrUnsupportedKey
+	}
+
+	key, err := newPublicKey(signer.Public())
+	if err != nil {
+		return nil, err
+	}
+	key.signingKey = private
+
+	return key, nil
+}
+
+// ParsePublicKeyPEM parses a PKIX encoded public key or an X.509 certificate
+func ParsePublicKeyPEM(data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	switch block.Type {
+	case "PUBLIC KEY":
+		public, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return newPublicKey(public)
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return newPublicKey(cert.PublicKey)
+	default:
+		return nil, fmt.Errorf("unexpected PEM block type %q", block.Type)
+	}
+}
+
+// newPublicKey creates a verification key with its signing method and key ID
+func newPublicKey(public any) (*Key, error) {
+	var method jwt.SigningMethod
+	switch pub := public.(type) {
+	case *rsa.PublicKey:
+		if pub.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("RSA key must be at least %d bits", minRSAKeyBits)
+		}
+		method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		method = jwt.SigningMethodEdDSA
+	default:
+		return nil, ErrUnsupportedKey
+	}
+
+	key := &Key{
+		Method:          method,
+		verificationKey: public,
+	}
+
+	id, err := thumbprint(key.jwk())
+	if err != nil {
+		return nil, err
+	}
+	key.ID = id
+
+	return key, nil
+}
+
+// newHMACKey creates a symmetric HS256 key
+func newHMACKey(secret []byte) *Key {
+	return &Key{
+		Method:          jwt.SigningMethodHS256,
+		signingKey:      secret,
+		verificationKey: secret,
+	}
+}
+
+// Sign signs the claims with the signing key and sets the kid header
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.signing.Method, claims)
+	if ks.signing.ID != "" {
+		token.Header["kid"] = ks.signing.ID
+	}
+	return token.SignedString(ks.signing.signingKey)
+}
+
+// Keyfunc returns the key to verify a token with. Asymmetric tokens are looked up by
+// their kid header, and the key must belong to the algorithm the token claims to use.
+func (ks *KeySet) Keyfunc(token *jwt.Token) (any, error) {
+	if token.Method.Alg() == jwt.SigningMethodHS256.Alg() {
+		if ks.hmac == nil {
+			return nil, ErrUnknownKey
+		}
+		return ks.hmac.verificationKey, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := ks.keys[kid]
+	if !ok || key.Method.Alg() != token.Method.Alg() {
+		return nil, ErrUnknownKey
+	}
+
+	return key.verificationKey, nil
+}
+
+// Methods returns the signing algorithms accepted when verifying tokens
+func (ks *KeySet) Methods() []string {
+	seen := map[string]bool{}
+	methods := []string{}
+
+	if ks.hmac != nil {
+		seen[ks.hmac.Method.Alg()] = true
+		methods = append(methods, ks.hmac.Method.Alg())
+	}
+	for _, key := range ks.keys {
+		if !seen[key.Method.Alg()] {
+			seen[key.Method.Alg()] = true
+			methods = append(methods, key.Method.Alg())
+		}
+	}
+	sort.Strings(methods)
+
+	return methods
+}
+
+// JWKS returns the public keys of the key set. HMAC secrets are never included.
+func (ks *KeySet) JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+
+	// List the signing key first so clients that pick the first key get the current one
+	if ks.signing.Method != jwt.SigningMethodHS256 {
+		set.Keys = append(set.Keys, ks.signing.jwk())
+	}
+	ids := make([]string, 0, len(ks.keys))
+	for id := range ks.keys {
+		if id != ks.signing.ID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		set.Keys = append(set.Keys, ks.keys[id].jwk())
+	}
+
+	return set
+}
+
+// jwk returns the JSON Web Key representation of an asymmetric key
+func (k *Key) jwk() JWK {
+	jwk := JWK{
+		Use: "sig",
+		Alg: k.Method.Alg(),
+		Kid: k.ID,
+	}
+
+	switch pub := k.verificationKey.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	}
+
+	return jwk
+}
+
+// thumbprint computes the RFC 7638 thumbprint of a JSON Web Key
+func thumbprint(jwk JWK) (string, error) {
+	// The thumbprint is computed over the required members only, in lexicographic order
+	var members any
+	switch jwk.Kty {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N}
+	case "OKP":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Crv, jwk.Kty, jwk.X}
+	default:
+		return "", ErrUnsupportedKey
+	}
+
+	data, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,430 @@
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Nilesh2000/conduit/internal/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testSecret = "this-is-a-32-char-long-secret-key-123"
+
+// writePEM writes a PEM block to a file in the test's temporary directory
+func writePEM(t *testing.T, name, blockType string, der []byte) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+// generateRSAKey generates an RSA key and writes it as PKCS #1 and its public key as PKIX
+func generateRSAKey(t *testing.T) (privatePath, publicPath string) {
+	t.Helper()
+
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key: %v", err)
+	}
+	public, err := x509.MarshalPKIXPublicKey(&private.PublicKey)
+	if err != nil {
+		t.Fatalf("Failed to marshal RSA public key: %v", err)
+	}
+
+	return writePEM(t, "rsa.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(private)),
+		writePEM(t, "rsa.pub.pem", "PUBLIC KEY", public)
+}
+
+// generateEd25519Key generates an Ed25519 key and writes it as PKCS #8 and its public key as PKIX
+func generateEd25519Key(t *testing.T) (privatePath, publicPath string) {
+	t.Helper()
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate Ed25519 key: %v", err)
+	}
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatalf("Failed to marshal Ed25519 private key: %v", err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		t.Fatalf("Failed to marshal Ed25519 public key: %v", err)
+	}
+
+	return writePEM(t, "ed25519.pem", "PRIVATE KEY", privateDER),
+		writePEM(t, "ed25519.pub.pem", "PUBLIC KEY", publicDER)
+}
+
+// testClaims returns valid claims for a test token
+func testClaims() jwt.RegisteredClaims {
+	now := time.Now()
+	return jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		ID:        "token-id",
+		IssuedAt:  jwt.NewNumericDate(now),
+		Subject:   "42",
+	}
+}
+
+// verify parses a token the way the auth middleware does
+func verify(ks *KeySet, token string) error {
+	_, err := jwt.ParseWithClaims(
+		token,
+		&jwt.RegisteredClaims{},
+		ks.Keyfunc,
+		jwt.WithValidMethods(ks.Methods()),
+	)
+	return err
+}
+
+func TestThumbprint(t *testing.T) {
+	t.Parallel()
+
+	// Example key and thumbprint from RFC 7638, section 3.1
+	jwk := JWK{
+		Kty: "RSA",
+		N: "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECP" +
+			"ebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2Q" +
+			"vzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQF" +
+			"h6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+		E: "AQAB",
+	}
+
+	got, err := thumbprint(jwk)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"; got != want {
+		t.Errorf("Expected thumbprint %q, got %q", want, got)
+	}
+}
+
+func TestFromConfig(t *testing.T) {
+	t.Parallel()
+
+	rsaPrivate, rsaPublic := generateRSAKey(t)
+	edPrivate, edPublic := generateEd25519Key(t)
+
+	tests := []struct {
+		name            string
+		cfg             config.JWT
+		expectedErr     bool
+		expectedMethods []string
+		expectedKeys    int
+	}{
+		{
+			name:            "HS256",
+			cfg:             config.JWT{Algorithm: config.AlgorithmHS256, SecretKey: testSecret},
+			expectedMethods: []string{"HS256"},
+			expectedKeys:    0,
+		},
+		{
+			name: "RS256",
+			cfg: config.JWT{
+				Algorithm:      config.AlgorithmRS256,
+				PrivateKeyFile: rsaPrivate,
+			},
+			expectedMethods: []string{"RS256"},
+			expectedKeys:    1,
+		},
+		{
+			name: "EdDSA with a rotated RSA key and HS256 fallback",
+			cfg: config.JWT{
+				Algorithm:      config.AlgorithmEdDSA,
+				PrivateKeyFile: edPrivate,
+				PublicKeyFiles: []string{rsaPublic},
+				SecretKey:      testSecret,
+				HMACFallback:   true,
+			},
+			expectedMethods: []string{"EdDSA", "HS256", "RS256"},
+			expectedKeys:    2,
+		},
+		{
+			name: "Signing key listed as verification key",
+			cfg: config.JWT{
+				Algorithm:      config.AlgorithmEdDSA,
+				PrivateKeyFile: edPrivate,
+				PublicKeyFiles: []string{edPublic},
+			},
+			expectedMethods: []string{"EdDSA"},
+			expectedKeys:    1,
+		},
+		{
+			name: "Private key does not match algorithm",
+			cfg: config.JWT{
+				Algorithm:      config.AlgorithmRS256,
+				PrivateKeyFile: edPrivate,
+			},
+			expectedErr: true,
+		},
+		{
+			name: "Missing private key file",
+			cfg: config.JWT{
+				Algorithm:      config.AlgorithmRS256,
+				PrivateKeyFile: filepath.Join(t.TempDir(), "missing.pem"),
+			},
+			expectedErr: true,
+		},
+		{
+			name: "Public key file holds a private key",
+			cfg: config.JWT{
+				Algorithm:      config.AlgorithmEdDSA,
+				PrivateKeyFile: edPrivate,
+				PublicKeyFiles: []string{rsaPrivate},
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ks, err := FromConfig(tt.cfg)
+			if (err != nil) != tt.expectedErr {
+				t.Fatalf("FromConfig() error = %v, wantErr %v", err, tt.expectedErr)
+			}
+			if err != nil {
+				return
+			}
+
+			methods := ks.Methods()
+			if len(methods) != len(tt.expectedMethods) {
+				t.Fatalf("Expected methods %v, got %v", tt.expectedMethods, methods)
+			}
+			for i := range methods {
+				if methods[i] != tt.expectedMethods[i] {
+					t.Errorf("Expected methods %v, got %v", tt.expectedMethods, methods)
+				}
+			}
+
+			if got := len(ks.JWKS().Keys); got != tt.expectedKeys {
+				t.Errorf("Expected %d public keys, got %d", tt.expectedKeys, got)
+			}
+
+			// Tokens signed by the key set must verify against it
+			token, err := ks.Sign(testClaims())
+			if err != nil {
+				t.Fatalf("Failed to sign token: %v", err)
+			}
+			if err := verify(ks, token); err != nil {
+				t.Errorf("Failed to verify token: %v", err)
+			}
+		})
+	}
+}
+
+func TestKeySet_Rotation(t *testing.T) {
+	t.Parallel()
+
+	rsaPrivate, _ := generateRSAKey(t)
+	edPrivate, edPublic := generateEd25519Key(t)
+
+	// The old deployment signs with Ed25519, the new one with RSA
+	oldKeys, err := FromConfig(config.JWT{
+		Algorithm:      config.AlgorithmEdDSA,
+		PrivateKeyFile: edPrivate,
+	})
+	if err != nil {
+		t.Fatalf("Failed to load old keys: %v", err)
+	}
+	newKeys, err := FromConfig(config.JWT{
+		Algorithm:      config.AlgorithmRS256,
+		PrivateKeyFile: rsaPrivate,
+		PublicKeyFiles: []string{edPublic},
+	})
+	if err != nil {
+		t.Fatalf("Failed to load new keys: %v", err)
+	}
+
+	oldToken, err := oldKeys.Sign(testClaims())
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+	newToken, err := newKeys.Sign(testClaims())
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+
+	// The new key set accepts tokens from both deployments
+	if err := verify(newKeys, oldToken); err != nil {
+		t.Errorf("Expected token signed with rotated key to verify, got %v", err)
+	}
+	if err := verify(newKeys, newToken); err != nil {
+		t.Errorf("Expected token signed with current key to verify, got %v", err)
+	}
+
+	// The old key set has never heard of the new key
+	if err := verify(oldKeys, newToken); err == nil {
+		t.Error("Expected token signed with unknown key to be rejected")
+	}
+
+	// The signing key is published first, with its kid matching the token header
+	jwks := newKeys.JWKS()
+	if len(jwks.Keys) != 2 || jwks.Keys[0].Alg != "RS256" || jwks.Keys[1].Alg != "EdDSA" {
+		t.Fatalf("Unexpected key set: %+v", jwks.Keys)
+	}
+	parsed, _, err := jwt.NewParser().ParseUnverified(newToken, &jwt.RegisteredClaims{})
+	if err != nil {
+		t.Fatalf("Failed to parse token: %v", err)
+	}
+	if kid := parsed.Header["kid"]; kid != jwks.Keys[0].Kid {
+		t.Errorf("Expected kid %q, got %v", jwks.Keys[0].Kid, kid)
+	}
+}
+
+func TestKeySet_Keyfunc(t *testing.T) {
+	t.Parallel()
+
+	rsaPrivate, rsaPublic := generateRSAKey(t)
+	keys, err := FromConfig(config.JWT{
+		Algorithm:      config.AlgorithmRS256,
+		PrivateKeyFile: rsaPrivate,
+	})
+	if err != nil {
+		t.Fatalf("Failed to load keys: %v", err)
+	}
+	kid := keys.JWKS().Keys[0].Kid
+
+	publicPEM, err := os.ReadFile(rsaPublic)
+	if err != nil {
+		t.Fatalf("Failed to read public key: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token func() (string, error)
+	}{
+		{
+			name: "HS256 token signed with the public key",
+			token: func() (string, error) {
+				token := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims())
+				token.Header["kid"] = kid
+				return token.SignedString(publicPEM)
+			},
+		},
+		{
+			name: "HS256 token without fallback",
+			token: func() (string, error) {
+				return NewHMACKeySet([]byte(testSecret)).Sign(testClaims())
+			},
+		},
+		{
+			name: "Unsigned token",
+			token: func() (string, error) {
+				token := jwt.NewWithClaims(jwt.SigningMethodNone, testClaims())
+				token.Header["kid"] = kid
+				return token.SignedString(jwt.UnsafeAllowNoneSignatureType)
+			},
+		},
+		{
+			name: "Token without kid",
+			token: func() (string, error) {
+				private, err := LoadPrivateKey(rsaPrivate)
+				if err != nil {
+					return "", err
+				}
+				token := jwt.NewWithClaims(jwt.SigningMethodRS256, testClaims())
+				return token.SignedString(private.signingKey)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			token, err := tt.token()
+			if err != nil {
+				t.Fatalf("Failed to sign token: %v", err)
+			}
+			if err := verify(keys, token); err == nil {
+				t.Error("Expected token to be rejected")
+			}
+		})
+	}
+}
+
+func TestParsePublicKeyPEM(t *testing.T) {
+	t.Parallel()
+
+	small, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key: %v", err)
+	}
+	smallDER, err := x509.MarshalPKIXPublicKey(&small.PublicKey)
+	if err != nil {
+		t.Fatalf("Failed to marshal RSA public key: %v", err)
+	}
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate Ed25519 key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, public, private)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		data           []byte
+		expectedMethod string
+	}{
+		{
+			name:           "Certificate",
+			data:           pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}),
+			expectedMethod: "EdDSA",
+		},
+		{
+			name: "RSA key too small",
+			data: pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: smallDER}),
+		},
+		{
+			name: "Not PEM",
+			data: []byte("not a key"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			key, err := ParsePublicKeyPEM(tt.data)
+			if tt.expectedMethod == "" {
+				if err == nil {
+					t.Error("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if key.Method.Alg() != tt.expectedMethod {
+				t.Errorf("Expected method %s, got %s", tt.expectedMethod, key.Method.Alg())
+			}
+		})
+	}
+}
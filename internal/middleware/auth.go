@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
@@ -18,63 +19,77 @@ type contextKey string
 // UserIDContextKey is the context key for the user ID
 const UserIDContextKey = contextKey("userID")
 
+// TokenIDContextKey is the context key for the ID (jti) of the access token
+const TokenIDContextKey = contextKey("tokenID")
+
+// TokenExpiresAtContextKey is the context key for the expiry (exp) of the access token
+const TokenExpiresAtContextKey = contextKey("tokenExpiresAt")
+
+// errUnauthorized is returned when a request carries an invalid or revoked token
+var errUnauthorized = errors.New("unauthorized")
+
+// KeyProvider provides the keys used to verify access tokens
+type KeyProvider interface {
+	Keyfunc(token *jwt.Token) (any, error)
+	Methods() []string
+}
+
+// TokenRevocationChecker reports whether an access token has been revoked
+type TokenRevocationChecker interface {
+	IsAccessTokenRevoked(ctx context.Context, tokenID string) (bool, error)
+}
+
 // RequireAuth middleware validates the JWT token and adds the user ID to the request context
-func RequireAuth(jwtSecret []byte) func(http.HandlerFunc) http.HandlerFunc {
+func RequireAuth(
+	keys KeyProvider,
+	revocations TokenRevocationChecker,
+) func(http.HandlerFunc) http.HandlerFunc {
 	return func(next http.HandlerFunc) http.HandlerFunc {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// Get the request context
-			ctx := r.Context()
-
-			// Get the Authorization header
-			authHeader := r.Header.Get("Authorization")
-			if authHeader == "" || !strings.HasPrefix(authHeader, "Token ") {
-				response.RespondWithError(w, http.StatusUnauthorized, []string{"Unauthorized"})
+			// Authenticate the request
+			userID, tokenID, expiresAt, err := authenticate(r, keys, revocations)
+			if err != nil {
+				respondWithAuthError(w, err)
 				return
 			}
 
-			// Extract the token from the Authorization header
-			tokenString := strings.TrimPrefix(authHeader, "Token ")
-			if tokenString == "" {
-				response.RespondWithError(w, http.StatusUnauthorized, []string{"Unauthorized"})
-				return
-			}
+			// Add the user ID, token ID and token expiry to the request context
+			ctx := context.WithValue(r.Context(), UserIDContextKey, userID)
+			ctx = context.WithValue(ctx, TokenIDContextKey, tokenID)
+			ctx = context.WithValue(ctx, TokenExpiresAtContextKey, expiresAt)
 
-			// Parse the token
-			token, err := jwt.ParseWithClaims(
-				tokenString,
-				&jwt.RegisteredClaims{},
-				func(token *jwt.Token) (any, error) {
-					return jwtSecret, nil
-				},
-			)
-			if err != nil || !token.Valid {
-				response.RespondWithError(w, http.StatusUnauthorized, []string{"Unauthorized"})
-				return
-			}
-
-			// Extract the claims from the token
-			claims, ok := token.Claims.(*jwt.RegisteredClaims)
-			if !ok || claims.Subject == "" {
-				response.RespondWithError(w, http.StatusUnauthorized, []string{"Unauthorized"})
-				return
-			}
+			// Serve the next handler
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
 
-			// Check if the token has expired
-			expTime, err := claims.GetExpirationTime()
-			if err != nil || expTime.Before(time.Now()) {
-				response.RespondWithError(w, http.StatusUnauthorized, []string{"Unauthorized"})
+// OptionalAuth middleware validates the JWT token when one is present and adds the user ID
+// to the request context. Requests without an Authorization header are served anonymously,
+// while requests with a malformed, invalid or expired token are rejected.
+func OptionalAuth(
+	keys KeyProvider,
+	revocations TokenRevocationChecker,
+) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Serve anonymously if no token was provided
+			if r.Header.Get("Authorization") == "" {
+				next.ServeHTTP(w, r)
 				return
 			}
 
-			// Parse the user ID from the claims
-			userID, err := strconv.ParseInt(claims.Subject, 10, 64)
+			// Authenticate the request
+			userID, tokenID, expiresAt, err := authenticate(r, keys, revocations)
 			if err != nil {
-				response.RespondWithError(w, http.StatusUnauthorized, []string{"Unauthorized"})
+				respondWithAuthError(w, err)
 				return
 			}
 
-			// Add the user ID to the request context
-			ctx = context.WithValue(ctx, UserIDContextKey, userID)
+			// Add the user ID, token ID and token expiry to the request context
+			ctx := context.WithValue(r.Context(), UserIDContextKey, userID)
+			ctx = context.WithValue(ctx, TokenIDContextKey, tokenID)
+			ctx = context.WithValue(ctx, TokenExpiresAtContextKey, expiresAt)
 
 			// Serve the next handler
 			next.ServeHTTP(w, r.WithContext(ctx))
@@ -82,8 +97,92 @@ func RequireAuth(jwtSecret []byte) func(http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+// respondWithAuthError responds with the status matching an authentication error
+func respondWithAuthError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errUnauthorized) {
+		response.RespondWithError(w, http.StatusUnauthorized, []string{"Unauthorized"})
+		return
+	}
+	response.RespondWithError(w, http.StatusInternalServerError, []string{"Internal server error"})
+}
+
+// authenticate validates the token in the Authorization header and returns the user ID,
+// the token ID and when the token expires
+func authenticate(
+	r *http.Request,
+	keys KeyProvider,
+	revocations TokenRevocationChecker,
+) (int64, string, time.Time, error) {
+	// Get the Authorization header
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" || !strings.HasPrefix(authHeader, "Token ") {
+		return 0, "", time.Time{}, errUnauthorized
+	}
+
+	// Extract the token from the Authorization header
+	tokenString := strings.TrimPrefix(authHeader, "Token ")
+	if tokenString == "" {
+		return 0, "", time.Time{}, errUnauthorized
+	}
+
+	// Parse the token, only accepting the algorithms of the configured keys
+	token, err := jwt.ParseWithClaims(
+		tokenString,
+		&jwt.RegisteredClaims{},
+		keys.Keyfunc,
+		jwt.WithValidMethods(keys.Methods()),
+	)
+	if err != nil || !token.Valid {
+		return 0, "", time.Time{}, errUnauthorized
+	}
+
+	// Extract the claims from the token
+	claims, ok := token.Claims.(*jwt.RegisteredClaims)
+	if !ok || claims.Subject == "" || claims.ID == "" {
+		return 0, "", time.Time{}, errUnauthorized
+	}
+
+	// Check if the token has expired
+	expTime, err := claims.GetExpirationTime()
+	if err != nil || expTime == nil || expTime.Before(time.Now()) {
+		return 0, "", time.Time{}, errUnauthorized
+	}
+
+	// Parse the user ID from the claims
+	userID, err := strconv.ParseInt(claims.Subject, 10, 64)
+	if err != nil {
+		return 0, "", time.Time{}, errUnauthorized
+	}
+
+	// Check if the token has been revoked
+	if revocations != nil {
+		revoked, err := revocations.IsAccessTokenRevoked(r.Context(), claims.ID)
+		if err != nil {
+			return 0, "", time.Time{}, err
+		}
+		if revoked {
+			return 0, "", time.Time{}, errUnauthorized
+		}
+	}
+
+	return userID, claims.ID, expTime.Time, nil
+}
+
 // GetUserIDFromContext retrieves the user ID from the request context
 func GetUserIDFromContext(ctx context.Context) (int64, bool) {
 	userID, ok := ctx.Value(UserIDContextKey).(int64)
 	return userID, ok
 }
+
+// GetTokenIDFromContext retrieves the access token ID from the request context
+func GetTokenIDFromContext(ctx context.Context) (string, bool) {
+	tokenID, ok := ctx.Value(TokenIDContextKey).(string)
+	return tokenID, ok
+}
+
+// GetTokenExpiresAtFromContext retrieves when the access token expires from the request
+// context
+func GetTokenExpiresAtFromContext(ctx context.Context) (time.Time, bool) {
+	expiresAt, ok := ctx.Value(TokenExpiresAtContextKey).(time.Time)
+	return expiresAt, ok
+}
@@ -0,0 +1,330 @@
+package middleware
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Nilesh2000/conduit/internal/jwtkeys"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testSecret = "this-is-a-32-char-long-secret-key-123"
+
+// MockTokenRevocationChecker is a mock implementation of the TokenRevocationChecker interface
+type MockTokenRevocationChecker struct {
+	isAccessTokenRevokedFunc func(ctx context.Context, tokenID string) (bool, error)
+}
+
+// IsAccessTokenRevoked checks if a token has been revoked in the mock checker
+func (m *MockTokenRevocationChecker) IsAccessTokenRevoked(
+	ctx context.Context,
+	tokenID string,
+) (bool, error) {
+	return m.isAccessTokenRevokedFunc(ctx, tokenID)
+}
+
+// testRevocations reports the token with ID "revoked" as revoked and fails for "failing"
+var testRevocations = &MockTokenRevocationChecker{
+	isAccessTokenRevokedFunc: func(ctx context.Context, tokenID string) (bool, error) {
+		if tokenID == "failing" {
+			return false, errors.New("database error")
+		}
+		return tokenID == "revoked", nil
+	},
+}
+
+// signTestToken signs a token with the given subject, ID and expiry for testing
+func signTestToken(t *testing.T, secret, subject, id string, expiresAt time.Time) string {
+	t.Helper()
+
+	now := time.Now()
+	claims := jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+		ID:        id,
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now.Add(-time.Minute)),
+		Subject:   subject,
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+	return token
+}
+
+// authTestCase describes a request and the expected outcome of an auth middleware
+type authTestCase struct {
+	name           string
+	authHeader     string
+	expectedStatus int
+	expectedUserID *int64
+}
+
+// authTestCases returns the cases shared by the auth middleware tests
+func authTestCases(t *testing.T) []authTestCase {
+	userID := int64(42)
+	inAnHour := time.Now().Add(time.Hour)
+	valid := signTestToken(t, testSecret, "42", "token-id", inAnHour)
+	expired := signTestToken(t, testSecret, "42", "token-id", time.Now().Add(-time.Hour))
+	wrongSecret := signTestToken(t, "another-32-char-long-secret-key-456", "42", "token-id", inAnHour)
+	badSubject := signTestToken(t, testSecret, "not-a-number", "token-id", inAnHour)
+	missingID := signTestToken(t, testSecret, "42", "", inAnHour)
+	revoked := signTestToken(t, testSecret, "42", "revoked", inAnHour)
+	failing := signTestToken(t, testSecret, "42", "failing", inAnHour)
+
+	return []authTestCase{
+		{
+			name:           "Valid token",
+			authHeader:     "Token " + valid,
+			expectedStatus: http.StatusOK,
+			expectedUserID: &userID,
+		},
+		{
+			name:           "Wrong scheme",
+			authHeader:     "Bearer " + valid,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Empty token",
+			authHeader:     "Token ",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Malformed token",
+			authHeader:     "Token not.a.jwt",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Expired token",
+			authHeader:     "Token " + expired,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Token signed with another secret",
+			authHeader:     "Token " + wrongSecret,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Non-numeric subject",
+			authHeader:     "Token " + badSubject,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Missing token ID",
+			authHeader:     "Token " + missingID,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Revoked token",
+			authHeader:     "Token " + revoked,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Revocation check fails",
+			authHeader:     "Token " + failing,
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+}
+
+// runAuthTestCase serves a request through the middleware and checks the outcome
+func runAuthTestCase(
+	t *testing.T,
+	mw func(http.HandlerFunc) http.HandlerFunc,
+	tt authTestCase,
+) {
+	t.Helper()
+
+	called := false
+	var gotUserID int64
+	var gotOK bool
+	var gotTokenID string
+	var gotExpiresAt time.Time
+	next := func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		gotUserID, gotOK = GetUserIDFromContext(r.Context())
+		gotTokenID, _ = GetTokenIDFromContext(r.Context())
+		gotExpiresAt, _ = GetTokenExpiresAtFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/articles", nil)
+	if tt.authHeader != "" {
+		req.Header.Set("Authorization", tt.authHeader)
+	}
+	rr := httptest.NewRecorder()
+
+	mw(next).ServeHTTP(rr, req)
+
+	if got, want := rr.Code, tt.expectedStatus; got != want {
+		t.Errorf("Status code: got %v, want %v", got, want)
+	}
+
+	if tt.expectedStatus != http.StatusOK {
+		if called {
+			t.Error("Expected next handler not to be called")
+		}
+		return
+	}
+
+	if !called {
+		t.Fatal("Expected next handler to be called")
+	}
+
+	if tt.expectedUserID == nil {
+		if gotOK {
+			t.Errorf("Expected no user ID in context, got %d", gotUserID)
+		}
+		return
+	}
+
+	if !gotOK || gotUserID != *tt.expectedUserID {
+		t.Errorf("Expected user ID %d in context, got %d (ok=%v)", *tt.expectedUserID, gotUserID, gotOK)
+	}
+	if gotTokenID != "token-id" {
+		t.Errorf("Expected token ID %q in context, got %q", "token-id", gotTokenID)
+	}
+	if !gotExpiresAt.After(time.Now()) {
+		t.Errorf("Expected the token expiry in context, got %v", gotExpiresAt)
+	}
+}
+
+func TestRequireAuth(t *testing.T) {
+	t.Parallel()
+
+	tests := append(authTestCases(t), authTestCase{
+		name:           "Missing token",
+		authHeader:     "",
+		expectedStatus: http.StatusUnauthorized,
+	})
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			runAuthTestCase(t, RequireAuth(jwtkeys.NewHMACKeySet([]byte(testSecret)), testRevocations), tt)
+		})
+	}
+}
+
+func TestOptionalAuth(t *testing.T) {
+	t.Parallel()
+
+	tests := append(authTestCases(t), authTestCase{
+		name:           "Missing token is served anonymously",
+		authHeader:     "",
+		expectedStatus: http.StatusOK,
+		expectedUserID: nil,
+	})
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			runAuthTestCase(t, OptionalAuth(jwtkeys.NewHMACKeySet([]byte(testSecret)), testRevocations), tt)
+		})
+	}
+}
+
+func TestRequireAuth_AsymmetricKeys(t *testing.T) {
+	t.Parallel()
+
+	// Generate the current signing key and a key that has been rotated out
+	newKey := func() *jwtkeys.Key {
+		_, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatalf("Failed to generate key: %v", err)
+		}
+		der, err := x509.MarshalPKCS8PrivateKey(private)
+		if err != nil {
+			t.Fatalf("Failed to marshal key: %v", err)
+		}
+		key, err := jwtkeys.ParsePrivateKeyPEM(
+			pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}),
+		)
+		if err != nil {
+			t.Fatalf("Failed to parse key: %v", err)
+		}
+		return key
+	}
+	currentKey, previousKey, unknownKey := newKey(), newKey(), newKey()
+
+	mustKeySet := func(signing *jwtkeys.Key, verification []*jwtkeys.Key, secret []byte) *jwtkeys.KeySet {
+		ks, err := jwtkeys.NewKeySet(signing, verification, secret)
+		if err != nil {
+			t.Fatalf("Failed to create key set: %v", err)
+		}
+		return ks
+	}
+
+	sign := func(ks *jwtkeys.KeySet) string {
+		now := time.Now()
+		token, err := ks.Sign(jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+			ID:        "token-id",
+			IssuedAt:  jwt.NewNumericDate(now),
+			Subject:   "42",
+		})
+		if err != nil {
+			t.Fatalf("Failed to sign token: %v", err)
+		}
+		return token
+	}
+
+	userID := int64(42)
+	keys := mustKeySet(currentKey, []*jwtkeys.Key{previousKey}, nil)
+	keysWithFallback := mustKeySet(currentKey, nil, []byte(testSecret))
+	hmacToken := signTestToken(t, testSecret, "42", "token-id", time.Now().Add(time.Hour))
+
+	tests := []struct {
+		keys *jwtkeys.KeySet
+		authTestCase
+	}{
+		{keys, authTestCase{
+			name:           "Token signed with the current key",
+			authHeader:     "Token " + sign(keys),
+			expectedStatus: http.StatusOK,
+			expectedUserID: &userID,
+		}},
+		{keys, authTestCase{
+			name:           "Token signed with a rotated key",
+			authHeader:     "Token " + sign(mustKeySet(previousKey, nil, nil)),
+			expectedStatus: http.StatusOK,
+			expectedUserID: &userID,
+		}},
+		{keys, authTestCase{
+			name:           "Token signed with an unknown key",
+			authHeader:     "Token " + sign(mustKeySet(unknownKey, nil, nil)),
+			expectedStatus: http.StatusUnauthorized,
+		}},
+		{keys, authTestCase{
+			name:           "HS256 token without fallback",
+			authHeader:     "Token " + hmacToken,
+			expectedStatus: http.StatusUnauthorized,
+		}},
+		{keysWithFallback, authTestCase{
+			name:           "HS256 token with fallback",
+			authHeader:     "Token " + hmacToken,
+			expectedStatus: http.StatusOK,
+			expectedUserID: &userID,
+		}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			runAuthTestCase(t, RequireAuth(tt.keys, testRevocations), tt.authTestCase)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+)
+
+// Handler handles an event published on a Bus
+type Handler func(ctx context.Context, event Event) error
+
+// subscription is a handler and the types of events it handles, all of them if empty
+type subscription struct {
+	eventTypes []string
+	handler    Handler
+}
+
+// Bus publishes events to the handlers subscribed to them in the same process
+type Bus struct {
+	mu            sync.RWMutex
+	subscriptions []subscription
+}
+
+// NewBus creates a new Bus
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe has handler called with the events of eventTypes, or with every event if no
+// types are given
+func (b *Bus) Subscribe(handler Handler, eventTypes ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.subscriptions = append(b.subscriptions, subscription{eventTypes: eventTypes, handler: handler})
+}
+
+// Publish calls the handlers subscribed to an event in the order they subscribed and
+// returns the errors of those that failed. A failed event is published again to every
+// handler, so handlers should be idempotent.
+func (b *Bus) Publish(ctx context.Context, event Event) error {
+	b.mu.RLock()
+	subscriptions := b.subscriptions
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, s := range subscriptions {
+		if len(s.eventTypes) > 0 && !slices.Contains(s.eventTypes, event.Type) {
+			continue
+		}
+		if err := s.handler(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
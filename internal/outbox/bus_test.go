@@ -0,0 +1,41 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestBus_Publish tests that events reach the handlers subscribed to their type, and that
+// the errors of the handlers that failed are returned
+func TestBus_Publish(t *testing.T) {
+	t.Parallel()
+
+	bus := NewBus()
+	var handled []string
+	bus.Subscribe(func(ctx context.Context, event Event) error {
+		handled = append(handled, "all:"+event.Type)
+		return nil
+	})
+	bus.Subscribe(func(ctx context.Context, event Event) error {
+		handled = append(handled, "comments:"+event.Type)
+		return errors.New("comment handler failed")
+	}, "comment.created", "comment.deleted")
+	bus.Subscribe(func(ctx context.Context, event Event) error {
+		handled = append(handled, "follows:"+event.Type)
+		return nil
+	}, "user.followed")
+
+	if err := bus.Publish(context.Background(), Event{ID: 1, Type: "user.followed"}); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if err := bus.Publish(context.Background(), Event{ID: 2, Type: "comment.created"}); err == nil {
+		t.Error("Expected the error of the comment handler")
+	}
+
+	expected := []string{"all:user.followed", "follows:user.followed", "all:comment.created", "comments:comment.created"}
+	if !reflect.DeepEqual(handled, expected) {
+		t.Errorf("Expected handled events %v, got %v", expected, handled)
+	}
+}
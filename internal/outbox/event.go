@@ -0,0 +1,19 @@
+// Package outbox publishes the domain events relayed from the outbox to the sinks that
+// consume them.
+package outbox
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Event represents a domain event relayed from the outbox. Events are published at least
+// once, so a sink may get the same event again and should tell repeats apart by its ID.
+type Event struct {
+	ID            int64           `json:"id"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregateType"`
+	AggregateID   int64           `json:"aggregateId"`
+	Payload       json.RawMessage `json:"payload"`
+	CreatedAt     time.Time       `json:"createdAt"`
+}
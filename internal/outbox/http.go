@@ -0,0 +1,79 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Event headers
+const (
+	HeaderEvent   = "X-Conduit-Event"
+	HeaderEventID = "X-Conduit-Event-ID"
+)
+
+// userAgent is the User-Agent events are posted with
+const userAgent = "Conduit-Outbox/1.0"
+
+// maxResponseBody is how much of a response body is read before the connection is
+// reused
+const maxResponseBody = 64 << 10
+
+// HTTPSink posts events as JSON to a URL
+type HTTPSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPSink creates a new HTTPSink posting events to url, which has timeout to answer
+// each of them
+func NewHTTPSink(url string, timeout time.Duration) *HTTPSink {
+	return &HTTPSink{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Publish posts an event to the URL of the sink. It returns an error if the event could
+// not be posted or the status answered with is not a 2xx.
+func (s *HTTPSink) Publish(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(HeaderEvent, event.Type)
+	req.Header.Set(HeaderEventID, strconv.FormatInt(event.ID, 10))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Printf("error closing response body: %v", err)
+		}
+	}()
+
+	// Drain the body so that the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBody))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return nil
+}
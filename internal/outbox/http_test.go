@@ -0,0 +1,77 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestHTTPSink_Publish tests that events are posted as JSON, and that receivers that do
+// not accept them fail the publication
+func TestHTTPSink_Publish(t *testing.T) {
+	t.Parallel()
+
+	createdAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	event := Event{
+		ID:            7,
+		Type:          "article.favorited",
+		AggregateType: "article",
+		AggregateID:   1,
+		Payload:       json.RawMessage(`{"articleId":1,"userId":2}`),
+		CreatedAt:     createdAt,
+	}
+
+	tests := []struct {
+		name      string
+		status    int
+		expectErr bool
+	}{
+		{
+			name:   "Event accepted",
+			status: http.StatusAccepted,
+		},
+		{
+			name:      "Event rejected",
+			status:    http.StatusServiceUnavailable,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup a receiver that reads the event back
+			received := make(chan Event, 1)
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get(HeaderEvent) != "article.favorited" || r.Header.Get(HeaderEventID) != "7" {
+					t.Errorf("Unexpected event headers %v", r.Header)
+				}
+
+				var got Event
+				if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+					t.Errorf("Failed to decode event: %v", err)
+				}
+				received <- got
+				w.WriteHeader(tt.status)
+			}))
+			defer receiver.Close()
+
+			// Publish Event
+			err := NewHTTPSink(receiver.URL, time.Second).Publish(context.Background(), event)
+
+			// Validate result
+			if (err != nil) != tt.expectErr {
+				t.Errorf("Expected error: %v, got %v", tt.expectErr, err)
+			}
+			got := <-received
+			if got.ID != event.ID || got.AggregateID != 1 || string(got.Payload) != string(event.Payload) || !got.CreatedAt.Equal(createdAt) {
+				t.Errorf("Expected event %+v, got %+v", event, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package outbox
+
+import (
+	"context"
+	"log"
+)
+
+// LogSink writes events to the log
+type LogSink struct{}
+
+// NewLogSink creates a new LogSink
+func NewLogSink() *LogSink {
+	return &LogSink{}
+}
+
+// Publish writes an event to the log
+func (s *LogSink) Publish(_ context.Context, event Event) error {
+	log.Printf(
+		"Event %d %s on %s %d: %s",
+		event.ID,
+		event.Type,
+		event.AggregateType,
+		event.AggregateID,
+		event.Payload,
+	)
+	return nil
+}
@@ -2,6 +2,29 @@ package repository
 
 import "time"
 
+// Article statuses
+const (
+	ArticleStatusDraft     = "draft"
+	ArticleStatusScheduled = "scheduled"
+	ArticleStatusPublished = "published"
+	ArticleStatusArchived  = "archived"
+)
+
+// Ways of matching the tags of an article
+const (
+	ArticleTagMatchAny = "any"
+	ArticleTagMatchAll = "all"
+)
+
+// Article sort orders
+const (
+	ArticleSortNewest          = "newest"
+	ArticleSortOldest          = "oldest"
+	ArticleSortMostFavorited   = "most-favorited"
+	ArticleSortMostCommented   = "most-commented"
+	ArticleSortRecentlyUpdated = "recently-updated"
+)
+
 // Article represents an article in the repository
 type Article struct {
 	ID             int64
@@ -11,24 +34,76 @@ type Article struct {
 	Body           string
 	AuthorID       int64
 	Author         *User
+	Status         string
+	PublishAt      *time.Time
+	PublishedAt    *time.Time
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 	TagList        []string
 	Favorited      bool
 	FavoritesCount int
+	Highlight      *ArticleHighlight
+	// DeletedAt is when the article was moved to the trash, which is only read for
+	// articles listed in the trash
+	DeletedAt *time.Time
+}
+
+// TagChanges describes how an update changes the tags of an article. A non-nil Replace
+// replaces the tags, after which the tags in Add are linked and the ones in Remove are
+// unlinked, so a tag in both ends up removed.
+type TagChanges struct {
+	Replace []string
+	Add     []string
+	Remove  []string
 }
 
-// ArticleFilters represents filters for listing articles
+// ArticleHighlight holds the text of an article matching a search, with the matching
+// words wrapped in <mark> tags. The text itself is HTML escaped.
+type ArticleHighlight struct {
+	Title       string
+	Description string
+	Body        string
+}
+
+// ArticleFilters represents filters for listing articles. After and Before select the
+// page right after or right before a cursor, newest first.
 type ArticleFilters struct {
-	Tag       *string
-	Author    *string
-	Favorited *string
-	Limit     int
-	Offset    int
+	Query *string
+	// Tags are matched by TagMatch, any of them if it is empty
+	Tags        []string
+	TagMatch    string
+	ExcludeTags []string
+	// Authors and ExcludeAuthors are usernames
+	Authors        []string
+	ExcludeAuthors []string
+	Favorited      *string
+	Status         *string
+	// CreatedAfter and CreatedBefore are exclusive bounds on the creation time
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	// Sort is one of the article sort orders. Articles are listed newest first, or by
+	// relevance to Query, if it is empty.
+	Sort   string
+	Limit  int
+	Offset int
+	// After and Before are only used with the newest and oldest sort orders
+	After  *Cursor
+	Before *Cursor
+}
+
+// FeedFilters represents filters for the articles feed
+type FeedFilters struct {
+	Limit  int
+	Offset int
+	After  *Cursor
+	Before *Cursor
 }
 
-// ArticleListResult represents the result of listing articles
+// ArticleListResult represents the result of listing articles. HasMore reports whether
+// more articles follow the page in the direction it was read in, which is towards
+// newer articles for the page before a cursor.
 type ArticleListResult struct {
 	Articles []*Article
 	Count    int
+	HasMore  bool
 }
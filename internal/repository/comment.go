@@ -9,4 +9,53 @@ type Comment struct {
 	Article   Article   `json:"article"`
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
+	// ParentID is the comment replied to, which is nil for top-level comments
+	ParentID   *int `json:"parentId"`
+	Depth      int  `json:"depth"`
+	ReplyCount int  `json:"replyCount"`
+	// Tombstoned reports whether the comment was deleted while it had replies
+	Tombstoned bool `json:"tombstoned"`
+	// Edited reports whether the body of the comment was changed since it was posted
+	Edited bool `json:"edited"`
+	// DeletedAt is when the comment was moved to the trash, which is only read for
+	// comments listed in the trash
+	DeletedAt *time.Time `json:"deletedAt"`
+}
+
+// CommentVersion is an earlier body of an edited comment. WrittenAt is when the body was
+// posted or last edited, and ReplacedAt when the edit that replaced it was made.
+type CommentVersion struct {
+	ID         int       `json:"id"`
+	CommentID  int       `json:"commentId"`
+	Body       string    `json:"body"`
+	WrittenAt  time.Time `json:"writtenAt"`
+	ReplacedAt time.Time `json:"replacedAt"`
+}
+
+// Comment sort orders
+const (
+	CommentSortOldest = "oldest"
+	CommentSortNewest = "newest"
+)
+
+// CommentFilters represents filters for listing the comments of an article. Sort orders
+// the top-level comments, oldest first unless it is CommentSortNewest. After and Before
+// select the page right after or right before a cursor in that order.
+type CommentFilters struct {
+	Limit  int
+	Sort   string
+	After  *Cursor
+	Before *Cursor
+}
+
+// CommentListResult represents the result of listing comments. Comments are the top-level
+// comments of the page and Replies every reply below them, oldest first. HasMore reports
+// whether more top-level comments follow the page in the direction it was read in, which
+// is towards the start of the list for the page before a cursor. Count is the number of
+// comments on the article that were not deleted, across every page.
+type CommentListResult struct {
+	Comments []Comment
+	Replies  []Comment
+	HasMore  bool
+	Count    int
 }
@@ -14,7 +14,21 @@ var (
 
 	ErrArticleNotFound = errors.New("article not found")
 
+	ErrRevisionNotFound = errors.New("article revision not found")
+
 	ErrCannotFollowSelf = errors.New("cannot follow yourself")
+	ErrCannotBlockSelf  = errors.New("cannot block yourself")
+	ErrCannotMuteSelf   = errors.New("cannot mute yourself")
+	ErrUserBlocked      = errors.New("user is blocked")
+
+	ErrCommentNotFound      = errors.New("comment not found")
+	ErrParentCommentDeleted = errors.New("parent comment is in the trash")
+
+	ErrNotificationNotFound = errors.New("notification not found")
+
+	ErrWebhookNotFound         = errors.New("webhook not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
 
-	ErrCommentNotFound = errors.New("comment not found")
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	ErrRefreshTokenReused   = errors.New("refresh token already used")
 )
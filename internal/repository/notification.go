@@ -0,0 +1,56 @@
+package repository
+
+import "time"
+
+// Notification types
+const (
+	NotificationTypeFollow   = "follow"
+	NotificationTypeFavorite = "favorite"
+	NotificationTypeComment  = "comment"
+)
+
+// Notification represents something another user did that a user is told about. UserID
+// is the user notified and ActorID the user who did it. ArticleID is set for favorites
+// and comments, and CommentID for comments.
+type Notification struct {
+	ID        int64
+	UserID    int64
+	ActorID   int64
+	Type      string
+	ArticleID *int64
+	CommentID *int64
+	ReadAt    *time.Time
+	CreatedAt time.Time
+	// Actor, ArticleSlug and ArticleTitle are not set for notifications that were just
+	// created
+	Actor        *User
+	ArticleSlug  string
+	ArticleTitle string
+}
+
+// NotificationFilters represents the filters for listing the notifications of a user,
+// newest first. Unread leaves out the notifications that were read.
+type NotificationFilters struct {
+	Limit  int
+	Unread bool
+	After  *Cursor
+	Before *Cursor
+}
+
+// NotificationListResult represents the result of listing notifications. HasMore reports
+// whether more notifications follow the page in the direction it was read in, Count is
+// the number of notifications across every page and UnreadCount the number of them that
+// were not read.
+type NotificationListResult struct {
+	Notifications []Notification
+	HasMore       bool
+	Count         int
+	UnreadCount   int
+}
+
+// NotificationPreferences represents the types of notifications a user gets
+type NotificationPreferences struct {
+	Follow   bool
+	Favorite bool
+	Comment  bool
+}
@@ -0,0 +1,66 @@
+package repository
+
+import "time"
+
+// Outbox aggregate types, the kind of entity an event is about
+const (
+	OutboxAggregateArticle = "article"
+	OutboxAggregateComment = "comment"
+	OutboxAggregateUser    = "user"
+)
+
+// Outbox event types
+const (
+	OutboxEventArticleCreated       = "article.created"
+	OutboxEventArticleUpdated       = "article.updated"
+	OutboxEventArticleStatusChanged = "article.status_changed"
+	OutboxEventArticleDeleted       = "article.deleted"
+	OutboxEventArticleRestored      = "article.restored"
+	OutboxEventArticleFavorited     = "article.favorited"
+	OutboxEventArticleUnfavorited   = "article.unfavorited"
+	OutboxEventCommentCreated       = "comment.created"
+	OutboxEventCommentUpdated       = "comment.updated"
+	OutboxEventCommentDeleted       = "comment.deleted"
+	OutboxEventCommentRestored      = "comment.restored"
+	OutboxEventUserFollowed         = "user.followed"
+	OutboxEventUserUnfollowed       = "user.unfollowed"
+)
+
+// OutboxEvent represents a domain event that was committed along with the change it
+// describes and is waiting to be relayed
+type OutboxEvent struct {
+	ID            int64
+	AggregateType string
+	AggregateID   int64
+	EventType     string
+	Payload       []byte
+	Attempts      int
+	CreatedAt     time.Time
+}
+
+// ArticleEvent is the payload of the events about an article
+type ArticleEvent struct {
+	ArticleID int64  `json:"articleId"`
+	AuthorID  int64  `json:"authorId"`
+	Slug      string `json:"slug"`
+	Status    string `json:"status"`
+}
+
+// CommentEvent is the payload of the events about a comment
+type CommentEvent struct {
+	CommentID int64 `json:"commentId"`
+	ArticleID int64 `json:"articleId"`
+	AuthorID  int64 `json:"authorId"`
+}
+
+// FavoriteEvent is the payload of the events about a user favoriting an article
+type FavoriteEvent struct {
+	ArticleID int64 `json:"articleId"`
+	UserID    int64 `json:"userId"`
+}
+
+// FollowEvent is the payload of the events about a user following another
+type FollowEvent struct {
+	FollowerID  int64 `json:"followerId"`
+	FollowingID int64 `json:"followingId"`
+}
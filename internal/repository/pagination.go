@@ -0,0 +1,10 @@
+package repository
+
+import "time"
+
+// Cursor is the position of an item in a list ordered by creation time. The ID tells
+// apart items created at the same time.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        int64
+}
@@ -3,8 +3,10 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"slices"
 	"strings"
 	"time"
 
@@ -15,19 +17,215 @@ import (
 
 // articleRepository implements the repository.articleRepository using PostgreSQL
 type articleRepository struct {
-	db *sql.DB
+	db             *sql.DB
+	searchLanguage string
 }
 
-// NewArticleRepository creates a new ArticleRepository
-func NewArticleRepository(db *sql.DB) *articleRepository {
-	return &articleRepository{db: db}
+// NewArticleRepository creates a new ArticleRepository that indexes and searches
+// articles with the searchLanguage text search configuration
+func NewArticleRepository(db *sql.DB, searchLanguage string) *articleRepository {
+	return &articleRepository{db: db, searchLanguage: searchLanguage}
 }
 
-// Create creates a new article in the database
+// articleColumns are the article and author columns read by scanArticle
+const articleColumns = `
+	a.id, a.slug, a.title, a.description, a.body, a.author_id, a.status, a.publish_at,
+	a.published_at, a.created_at, a.updated_at, u.id, u.username, u.bio, u.image
+`
+
+// articleListColumns are the tags and favorites count of an article, and whether the
+// viewer %[1]s favorited it and follows its author, read by scanListedArticle. They are
+// selected along with articleColumns so that a page of articles takes a single query.
+const articleListColumns = `
+	COALESCE(
+		(SELECT array_agg(t.name ORDER BY t.name) FROM article_tags at JOIN tags t ON t.id = at.tag_id WHERE at.article_id = a.id),
+		'{}'
+	),
+	(SELECT COUNT(*) FROM favorites fav WHERE fav.article_id = a.id),
+	EXISTS (SELECT 1 FROM favorites fav WHERE fav.article_id = a.id AND fav.user_id = %[1]s),
+	EXISTS (SELECT 1 FROM follows fol WHERE fol.following_id = a.author_id AND fol.follower_id = %[1]s)
+`
+
+// feedArticles selects the published articles by the authors the user $1 follows, except
+// the ones the user muted
+var feedArticles = `
+	FROM articles a
+	JOIN users u ON a.author_id = u.id
+	JOIN follows f ON u.id = f.following_id
+	WHERE f.follower_id = $1 AND a.status = 'published' AND a.deleted_at IS NULL
+		AND NOT ` + fmt.Sprintf(mutedFor, "$1", "a")
+
+// articleKeyset pages through articles newest first
+var articleKeyset = keyset{createdAt: "a.created_at", id: "a.id", descending: true}
+
+// oldestArticleKeyset pages through articles oldest first
+var oldestArticleKeyset = keyset{createdAt: "a.created_at", id: "a.id"}
+
+// articleSortOrders are the ORDER BY clauses of the article sort orders that are not
+// paginated with cursors, which break ties newest first. Sort orders are looked up here
+// rather than written into queries, so that only our own SQL ever is.
+var articleSortOrders = map[string]string{
+	repository.ArticleSortMostFavorited:   "(SELECT COUNT(*) FROM favorites fav WHERE fav.article_id = a.id) DESC",
+	repository.ArticleSortMostCommented:   "(SELECT COUNT(*) FROM comments cm WHERE cm.article_id = a.id AND cm.deleted_at IS NULL) DESC",
+	repository.ArticleSortRecentlyUpdated: "a.updated_at DESC",
+}
+
+// searchHighlightColumns are the highlighted title, description and body of an article
+// matching the tsquery %[1]s, read by scanSearchArticle. Only the body is cut down to
+// the fragments around the matches. The text is HTML escaped before it is highlighted,
+// so that the <mark> tags are the only markup in a highlight.
+const searchHighlightColumns = `
+	ts_headline(a.search_language, replace(replace(replace(a.title, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), %[1]s, 'HighlightAll=true, StartSel=<mark>, StopSel=</mark>'),
+	ts_headline(a.search_language, replace(replace(replace(a.description, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), %[1]s, 'HighlightAll=true, StartSel=<mark>, StopSel=</mark>'),
+	ts_headline(a.search_language, replace(replace(replace(a.body, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), %[1]s, 'MaxFragments=3, MaxWords=30, MinWords=10, StartSel=<mark>, StopSel=</mark>')
+`
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// queryer is implemented by both *sql.DB and *sql.Tx
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// scanArticle scans an article and its author selected with articleColumns
+func scanArticle(row rowScanner) (*repository.Article, error) {
+	return scanArticleWith(row)
+}
+
+// scanArticleWith scans an article and its author selected with articleColumns, followed
+// by the extra columns scanned into dest
+func scanArticleWith(row rowScanner, dest ...any) (*repository.Article, error) {
+	var article repository.Article
+	article.Author = &repository.User{}
+	var publishAt, publishedAt sql.NullTime
+	var authorBio, authorImage sql.NullString
+
+	err := row.Scan(append([]any{
+		&article.ID,
+		&article.Slug,
+		&article.Title,
+		&article.Description,
+		&article.Body,
+		&article.AuthorID,
+		&article.Status,
+		&publishAt,
+		&publishedAt,
+		&article.CreatedAt,
+		&article.UpdatedAt,
+		&article.Author.ID,
+		&article.Author.Username,
+		&authorBio,
+		&authorImage,
+	}, dest...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Handle nullable values
+	if publishAt.Valid {
+		article.PublishAt = &publishAt.Time
+	}
+	if publishedAt.Valid {
+		article.PublishedAt = &publishedAt.Time
+	}
+	if authorBio.Valid {
+		article.Author.Bio = authorBio.String
+	}
+	if authorImage.Valid {
+		article.Author.Image = authorImage.String
+	}
+
+	return &article, nil
+}
+
+// scanListedArticle scans an article selected with articleColumns and articleListColumns
+func scanListedArticle(row rowScanner) (*repository.Article, error) {
+	return scanListedArticleWith(row)
+}
+
+// scanListedArticleWith scans an article selected with articleColumns and
+// articleListColumns, followed by the extra columns scanned into dest
+func scanListedArticleWith(row rowScanner, dest ...any) (*repository.Article, error) {
+	var tagList []string
+	var favoritesCount int
+	var favorited, following bool
+
+	article, err := scanArticleWith(
+		row,
+		append([]any{pq.Array(&tagList), &favoritesCount, &favorited, &following}, dest...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Articles without tags have a nil tag list, as when read by getTagList
+	if len(tagList) > 0 {
+		article.TagList = tagList
+	}
+	article.FavoritesCount = favoritesCount
+	article.Favorited = favorited
+	article.Author.Following = following
+
+	return article, nil
+}
+
+// scanSearchArticle scans an article selected with articleColumns, articleListColumns
+// and searchHighlightColumns
+func scanSearchArticle(row rowScanner) (*repository.Article, error) {
+	var highlight repository.ArticleHighlight
+	article, err := scanListedArticleWith(row, &highlight.Title, &highlight.Description, &highlight.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	article.Highlight = &highlight
+	return article, nil
+}
+
+// getTagList gets the tags of an article ordered by name
+func (r *articleRepository) getTagList(ctx context.Context, articleID int64) ([]string, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		"SELECT t.name FROM tags t JOIN article_tags at ON t.id = at.tag_id WHERE at.article_id = $1 ORDER BY t.name ASC",
+		articleID,
+	)
+	if err != nil {
+		return nil, repository.ErrInternal
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("error closing rows: %v", err)
+		}
+	}()
+
+	var tagList []string
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, repository.ErrInternal
+		}
+		tagList = append(tagList, tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, repository.ErrInternal
+	}
+
+	return tagList, nil
+}
+
+// Create creates a new article in the database with the first of slugs that is not
+// taken, trying them in order. Scheduled articles are published by PublishDue once
+// publishAt has passed.
 func (r *articleRepository) Create(
 	ctx context.Context,
 	userID int64,
-	slug, title, description, body string,
+	slugs []string,
+	title, description, body, status string,
+	publishAt *time.Time,
 	tagList []string,
 ) (*repository.Article, error) {
 	// Begin a transaction
@@ -41,97 +239,96 @@ func (r *articleRepository) Create(
 		}
 	}()
 
+	// A taken slug inserts nothing instead of failing, so the transaction can go on
+	// with the next one. An insert racing for the same slug waits for the other
+	// transaction and only skips the slug if it commits.
 	query := `
 		WITH inserted_article AS (
-			INSERT INTO articles (slug, title, description, body, author_id, created_at, updated_at)
-			VALUES ($1, $2, $3, $4, $5, $6, $7)
-			RETURNING id, slug, title, description, body, author_id, created_at, updated_at
+			INSERT INTO articles (
+				slug, title, description, body, author_id, status, publish_at, published_at,
+				created_at, updated_at, search_language
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			ON CONFLICT (slug) DO NOTHING
+			RETURNING *
 		)
-		SELECT
-			a.id, a.slug, a.title, a.description, a.body, a.author_id, a.created_at, a.updated_at,
-			u.id, u.username, u.bio, u.image
+		SELECT ` + articleColumns + `
 		FROM inserted_article a
 		JOIN users u ON u.id = a.author_id
 	`
 
+	// Published articles are published as soon as they are created
 	now := time.Now()
-	var article repository.Article
-	article.Author = &repository.User{}
-	var authorBio, authorImage sql.NullString
+	var publishedAt *time.Time
+	if status == repository.ArticleStatusPublished {
+		publishedAt = &now
+	}
 
-	err = tx.QueryRowContext(ctx, query, slug, title, description, body, userID, now, now).
-		Scan(
-			&article.ID,
-			&article.Slug,
-			&article.Title,
-			&article.Description,
-			&article.Body,
-			&article.AuthorID,
-			&article.CreatedAt,
-			&article.UpdatedAt,
-			&article.Author.ID,
-			&article.Author.Username,
-			&authorBio,
-			&authorImage,
+	var article *repository.Article
+	for _, slug := range slugs {
+		row := tx.QueryRowContext(
+			ctx,
+			query,
+			slug,
+			title,
+			description,
+			body,
+			userID,
+			status,
+			publishAt,
+			publishedAt,
+			now,
+			now,
+			r.searchLanguage,
 		)
+		article, err = scanArticle(row)
+		if err != sql.ErrNoRows {
+			break
+		}
+	}
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.ErrDuplicateSlug
+		}
 		// PostgreSQL specific error handling
 		if pqErr, ok := err.(*pq.Error); ok {
 			// Check for foreign key constraint violation
 			if pqErr.Code == "23503" && pqErr.Constraint == "articles_author_id_fkey" {
 				return nil, repository.ErrUserNotFound
 			}
-			// Check for duplicate slug
-			if pqErr.Code == "23505" {
-				if pqErr.Constraint == "articles_slug_key" {
-					return nil, repository.ErrDuplicateSlug
-				}
-			}
 		}
 		return nil, repository.ErrInternal
 	}
-
-	// Handle nullable values
-	if authorBio.Valid {
-		article.Author.Bio = authorBio.String
+	if article == nil {
+		return nil, repository.ErrDuplicateSlug
 	}
-	if authorImage.Valid {
-		article.Author.Image = authorImage.String
+
+	// Record the initial text as the first revision
+	if err := insertRevision(ctx, tx, article); err != nil {
+		return nil, err
 	}
 
 	// Add tags if any
 	if len(tagList) > 0 {
-		for _, tag := range tagList {
-			var tagID int64
-			// Insert or update tag and get its ID
-			err := tx.QueryRowContext(ctx, "INSERT INTO tags (name) VALUES ($1) ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name RETURNING id", tag).
-				Scan(&tagID)
-			if err != nil {
-				return nil, repository.ErrInternal
-			}
-
-			// Link tag to article
-			_, err = tx.ExecContext(
-				ctx,
-				"INSERT INTO article_tags (article_id, tag_id) VALUES ($1, $2) ON CONFLICT DO NOTHING",
-				article.ID,
-				tagID,
-			)
-			if err != nil {
-				return nil, repository.ErrInternal
-			}
+		if err := insertTags(ctx, tx, article.ID, tagList); err != nil {
+			return nil, err
 		}
 
 		// Set the TagList field
 		article.TagList = tagList
 	}
 
+	// Record the event along with the article
+	if err := insertArticleEvent(ctx, tx, repository.OutboxEventArticleCreated, article); err != nil {
+		return nil, err
+	}
+
 	// Commit the transaction
 	if err := tx.Commit(); err != nil {
 		return nil, repository.ErrInternal
 	}
 
-	return &article, nil
+	return article, nil
 }
 
 // GetBySlug gets an article by slug
@@ -139,33 +336,44 @@ func (r *articleRepository) GetBySlug(
 	ctx context.Context,
 	slug string,
 ) (*repository.Article, error) {
-	var article repository.Article
-	article.Author = &repository.User{}
-	var authorBio, authorImage sql.NullString
+	query := `
+		SELECT ` + articleColumns + `
+		FROM articles a
+		JOIN users u ON a.author_id = u.id
+		WHERE a.slug = $1 AND a.deleted_at IS NULL
+	`
 
+	article, err := scanArticle(r.db.QueryRowContext(ctx, query, slug))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.ErrArticleNotFound
+		}
+		return nil, repository.ErrInternal
+	}
+
+	// Get tags for the article
+	article.TagList, err = r.getTagList(ctx, article.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return article, nil
+}
+
+// GetByID gets an article by ID, even if it is in the trash, so that what happened to an
+// article can still be told about after it was deleted
+func (r *articleRepository) GetByID(
+	ctx context.Context,
+	articleID int64,
+) (*repository.Article, error) {
 	query := `
-		SELECT a.id, a.slug, a.title, a.description, a.body, a.author_id, a.created_at, a.updated_at,
-		u.id, u.username, u.bio, u.image
+		SELECT ` + articleColumns + `
 		FROM articles a
 		JOIN users u ON a.author_id = u.id
-		WHERE a.slug = $1
+		WHERE a.id = $1
 	`
 
-	err := r.db.QueryRowContext(ctx, query, slug).
-		Scan(
-			&article.ID,
-			&article.Slug,
-			&article.Title,
-			&article.Description,
-			&article.Body,
-			&article.AuthorID,
-			&article.CreatedAt,
-			&article.UpdatedAt,
-			&article.Author.ID,
-			&article.Author.Username,
-			&authorBio,
-			&authorImage,
-		)
+	article, err := scanArticle(r.db.QueryRowContext(ctx, query, articleID))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, repository.ErrArticleNotFound
@@ -173,92 +381,633 @@ func (r *articleRepository) GetBySlug(
 		return nil, repository.ErrInternal
 	}
 
-	// Handle nullable values
-	if authorBio.Valid {
-		article.Author.Bio = authorBio.String
+	// Get tags for the article
+	article.TagList, err = r.getTagList(ctx, article.ID)
+	if err != nil {
+		return nil, err
 	}
-	if authorImage.Valid {
-		article.Author.Image = authorImage.String
+
+	return article, nil
+}
+
+// GetByPreviousSlug gets the article that used to have a slug before its title changed
+func (r *articleRepository) GetByPreviousSlug(
+	ctx context.Context,
+	slug string,
+) (*repository.Article, error) {
+	query := `
+		SELECT ` + articleColumns + `
+		FROM article_slug_history h
+		JOIN articles a ON a.id = h.article_id
+		JOIN users u ON a.author_id = u.id
+		WHERE h.slug = $1 AND a.deleted_at IS NULL
+	`
+
+	article, err := scanArticle(r.db.QueryRowContext(ctx, query, slug))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.ErrArticleNotFound
+		}
+		return nil, repository.ErrInternal
 	}
 
 	// Get tags for the article
-	rows, err := r.db.QueryContext(
-		ctx,
-		"SELECT t.name FROM tags t JOIN article_tags at ON t.id = at.tag_id WHERE at.article_id = $1 ORDER BY t.name ASC",
-		article.ID,
+	article.TagList, err = r.getTagList(ctx, article.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return article, nil
+}
+
+// Update updates an article and records the new text as a revision in the same
+// transaction. When newSlugs is set the article moves to the first of them that is not
+// taken, and the old slug is kept in the slug history so links to it can be
+// redirected. The tags of the article are changed as described by tags.
+func (r *articleRepository) Update(
+	ctx context.Context,
+	userID int64,
+	slug string,
+	newSlugs []string,
+	title, description, body *string,
+	tags repository.TagChanges,
+) (*repository.Article, error) {
+	// Begin a transaction
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, repository.ErrInternal
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			log.Printf("transaction rollback error: %v", err)
+		}
+	}()
+
+	now := time.Now()
+	var article *repository.Article
+	if len(newSlugs) == 0 {
+		article, err = updateArticle(ctx, tx, slug, nil, title, description, body, now)
+	} else {
+		// A taken slug fails the update, so roll back to before it and try the next one
+		for _, newSlug := range newSlugs {
+			if _, err := tx.ExecContext(ctx, "SAVEPOINT update_slug"); err != nil {
+				return nil, repository.ErrInternal
+			}
+
+			article, err = updateArticle(ctx, tx, slug, &newSlug, title, description, body, now)
+			if !errors.Is(err, repository.ErrDuplicateSlug) {
+				break
+			}
+
+			if _, err := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT update_slug"); err != nil {
+				return nil, repository.ErrInternal
+			}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the old slug so that links to it can be redirected
+	if article.Slug != slug {
+		if err := moveSlug(ctx, tx, article.ID, slug, article.Slug, article.UpdatedAt); err != nil {
+			return nil, err
+		}
+	}
+
+	// Keep the previous text by recording the new one as the next revision if it changed
+	if err := insertRevision(ctx, tx, article); err != nil {
+		return nil, err
+	}
+
+	// Replace the tags if a new list was given
+	if tags.Replace != nil {
+		if err := setTags(ctx, tx, article.ID, tags.Replace); err != nil {
+			return nil, err
+		}
+	}
+
+	// Link the added tags and unlink the removed ones
+	if err := insertTags(ctx, tx, article.ID, tags.Add); err != nil {
+		return nil, err
+	}
+	if err := removeTags(ctx, tx, article.ID, tags.Remove); err != nil {
+		return nil, err
+	}
+
+	// Record the event along with the update
+	if err := insertArticleEvent(ctx, tx, repository.OutboxEventArticleUpdated, article); err != nil {
+		return nil, err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		return nil, repository.ErrInternal
+	}
+
+	// Get tags for the article
+	article.TagList, err = r.getTagList(ctx, article.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return article, nil
+}
+
+// updateArticle updates the fields of an article that are set and returns it
+func updateArticle(
+	ctx context.Context,
+	tx *sql.Tx,
+	slug string,
+	newSlug, title, description, body *string,
+	updatedAt time.Time,
+) (*repository.Article, error) {
+	query := `
+		WITH updated_article AS (
+			UPDATE articles
+			SET
+				slug = COALESCE($1, slug),
+				title = COALESCE($2, title),
+				description = COALESCE($3, description),
+				body = COALESCE($4, body),
+				updated_at = $5
+			WHERE slug = $6 AND deleted_at IS NULL
+			RETURNING *
+		)
+		SELECT ` + articleColumns + `
+		FROM updated_article a
+		JOIN users u ON u.id = a.author_id
+	`
+
+	article, err := scanArticle(
+		tx.QueryRowContext(ctx, query, newSlug, title, description, body, updatedAt, slug),
 	)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.ErrArticleNotFound
+		}
+		if pqErr, ok := err.(*pq.Error); ok {
+			// Check for duplicate slug
+			if pqErr.Code == "23505" && pqErr.Constraint == "articles_slug_key" {
+				return nil, repository.ErrDuplicateSlug
+			}
+		}
 		return nil, repository.ErrInternal
 	}
+
+	return article, nil
+}
+
+// insertTags links an article to the tags in tagList, creating the tags that do not
+// exist yet
+func insertTags(ctx context.Context, tx *sql.Tx, articleID int64, tagList []string) error {
+	for _, tag := range tagList {
+		var tagID int64
+		// Insert or update tag and get its ID
+		err := tx.QueryRowContext(ctx, "INSERT INTO tags (name) VALUES ($1) ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name RETURNING id", tag).
+			Scan(&tagID)
+		if err != nil {
+			return repository.ErrInternal
+		}
+
+		// Link tag to article
+		_, err = tx.ExecContext(
+			ctx,
+			"INSERT INTO article_tags (article_id, tag_id) VALUES ($1, $2) ON CONFLICT DO NOTHING",
+			articleID,
+			tagID,
+		)
+		if err != nil {
+			return repository.ErrInternal
+		}
+	}
+
+	return nil
+}
+
+// setTags replaces the tags of an article with tagList. Tags that are removed from
+// the article and no longer used by any other article are deleted.
+func setTags(ctx context.Context, tx *sql.Tx, articleID int64, tagList []string) error {
+	// Unlink the tags that are not in the new list
+	query := `
+		DELETE FROM article_tags at
+		USING tags t
+		WHERE at.tag_id = t.id
+			AND at.article_id = $1
+			AND NOT (t.name = ANY($2))
+		RETURNING at.tag_id
+	`
+
+	rows, err := tx.QueryContext(ctx, query, articleID, pq.Array(tagList))
+	if err != nil {
+		return repository.ErrInternal
+	}
+	removedTagIDs, err := scanIDs(rows)
+	if err != nil {
+		return err
+	}
+
+	// Link the tags that are new to the article
+	if err := insertTags(ctx, tx, articleID, tagList); err != nil {
+		return err
+	}
+
+	return deleteOrphanedTags(ctx, tx, removedTagIDs)
+}
+
+// removeTags unlinks the tags in tagList from an article. Tags that are no longer used by
+// any article are deleted.
+func removeTags(ctx context.Context, tx *sql.Tx, articleID int64, tagList []string) error {
+	if len(tagList) == 0 {
+		return nil
+	}
+
+	query := `
+		DELETE FROM article_tags at
+		USING tags t
+		WHERE at.tag_id = t.id
+			AND at.article_id = $1
+			AND t.name = ANY($2)
+		RETURNING at.tag_id
+	`
+
+	rows, err := tx.QueryContext(ctx, query, articleID, pq.Array(tagList))
+	if err != nil {
+		return repository.ErrInternal
+	}
+	removedTagIDs, err := scanIDs(rows)
+	if err != nil {
+		return err
+	}
+
+	return deleteOrphanedTags(ctx, tx, removedTagIDs)
+}
+
+// deleteOrphanedTags deletes the tags in tagIDs that are not used by any article. The
+// tags are locked before they are checked, so an article that links one of them
+// concurrently either commits first and keeps the tag, or waits and recreates it.
+func deleteOrphanedTags(ctx context.Context, tx *sql.Tx, tagIDs []int64) error {
+	if len(tagIDs) == 0 {
+		return nil
+	}
+
+	// Lock in name order, the order in which articles link their tags
+	rows, err := tx.QueryContext(
+		ctx,
+		"SELECT id FROM tags WHERE id = ANY($1) ORDER BY name FOR UPDATE",
+		pq.Array(tagIDs),
+	)
+	if err != nil {
+		return repository.ErrInternal
+	}
+	if _, err := scanIDs(rows); err != nil {
+		return err
+	}
+
+	query := `
+		DELETE FROM tags t
+		WHERE t.id = ANY($1)
+			AND NOT EXISTS (SELECT 1 FROM article_tags at WHERE at.tag_id = t.id)
+	`
+
+	if _, err := tx.ExecContext(ctx, query, pq.Array(tagIDs)); err != nil {
+		return repository.ErrInternal
+	}
+
+	return nil
+}
+
+// scanIDs reads a single column of IDs and closes rows
+func scanIDs(rows *sql.Rows) ([]int64, error) {
 	defer func() {
 		if err := rows.Close(); err != nil {
 			log.Printf("error closing rows: %v", err)
 		}
 	}()
 
-	var tagList []string
+	var ids []int64
 	for rows.Next() {
-		var tag string
-		if err := rows.Scan(&tag); err != nil {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
 			return nil, repository.ErrInternal
 		}
-		tagList = append(tagList, tag)
+		ids = append(ids, id)
 	}
-
 	if err := rows.Err(); err != nil {
 		return nil, repository.ErrInternal
 	}
 
-	article.TagList = tagList
+	return ids, nil
+}
 
-	return &article, nil
+// moveSlug records that an article moved from oldSlug to newSlug. A slug that is in
+// use by an article is never redirected, so newSlug is removed from the history,
+// and an old slug that was already redirected now points at this article.
+func moveSlug(
+	ctx context.Context,
+	tx *sql.Tx,
+	articleID int64,
+	oldSlug, newSlug string,
+	movedAt time.Time,
+) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM article_slug_history WHERE slug = $1`, newSlug)
+	if err != nil {
+		return repository.ErrInternal
+	}
+
+	query := `
+		INSERT INTO article_slug_history (slug, article_id, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (slug) DO UPDATE
+		SET article_id = EXCLUDED.article_id, created_at = EXCLUDED.created_at
+	`
+
+	_, err = tx.ExecContext(ctx, query, oldSlug, articleID, movedAt)
+	if err != nil {
+		return repository.ErrInternal
+	}
+
+	return nil
 }
 
-// Update updates an article
-func (r *articleRepository) Update(
+// insertRevision records the current text of an article as its next revision, unless
+// it is the text of the latest revision, so updates that leave the title, description
+// and body as they were do not fill the history with empty diffs. The article row must
+// be locked by tx, so concurrent updates get consecutive numbers.
+func insertRevision(ctx context.Context, tx *sql.Tx, article *repository.Article) error {
+	query := `
+		INSERT INTO article_revisions (article_id, revision_number, title, description, body, created_at)
+		SELECT $1, latest.revision_number + 1, $2, $3, $4, $5
+		FROM (
+			SELECT COALESCE(MAX(revision_number), 0) AS revision_number
+			FROM article_revisions
+			WHERE article_id = $1
+		) latest
+		WHERE NOT EXISTS (
+			SELECT 1
+			FROM article_revisions
+			WHERE article_id = $1 AND revision_number = latest.revision_number
+				AND title = $2 AND description = $3 AND body = $4
+		)
+	`
+
+	_, err := tx.ExecContext(
+		ctx,
+		query,
+		article.ID,
+		article.Title,
+		article.Description,
+		article.Body,
+		article.UpdatedAt,
+	)
+	if err != nil {
+		return repository.ErrInternal
+	}
+
+	return nil
+}
+
+// SetStatus changes the status of an article. Publishing sets the publication date,
+// unpublishing back to a draft or scheduling clears it and archiving keeps it. publishAt
+// is only kept for scheduled articles.
+func (r *articleRepository) SetStatus(
+	ctx context.Context,
+	articleID int64,
+	status string,
+	publishAt *time.Time,
+) (*repository.Article, error) {
+	// Begin a transaction
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, repository.ErrInternal
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			log.Printf("transaction rollback error: %v", err)
+		}
+	}()
+
+	query := `
+		WITH updated_article AS (
+			UPDATE articles
+			SET
+				status = $1,
+				publish_at = CASE WHEN $1 = 'scheduled' THEN $4::timestamp END,
+				published_at = CASE
+					WHEN $1 = 'published' THEN $2
+					WHEN $1 IN ('draft', 'scheduled') THEN NULL
+					ELSE published_at
+				END,
+				updated_at = $2
+			WHERE id = $3 AND deleted_at IS NULL
+			RETURNING *
+		)
+		SELECT ` + articleColumns + `
+		FROM updated_article a
+		JOIN users u ON u.id = a.author_id
+	`
+
+	article, err := scanArticle(
+		tx.QueryRowContext(ctx, query, status, time.Now(), articleID, publishAt),
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.ErrArticleNotFound
+		}
+		return nil, repository.ErrInternal
+	}
+
+	// Record the event along with the change of status
+	if err := insertArticleEvent(ctx, tx, repository.OutboxEventArticleStatusChanged, article); err != nil {
+		return nil, err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		return nil, repository.ErrInternal
+	}
+
+	// Get tags for the article
+	article.TagList, err = r.getTagList(ctx, article.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return article, nil
+}
+
+// PublishDue publishes up to limit scheduled articles whose publishAt is not after now
+// and returns them. Rows locked by another publisher are skipped rather than waited on,
+// so several replicas can run the publisher at the same time without publishing an
+// article twice.
+func (r *articleRepository) PublishDue(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+) ([]*repository.Article, error) {
+	// Begin a transaction
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, repository.ErrInternal
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			log.Printf("transaction rollback error: %v", err)
+		}
+	}()
+
+	query := `
+		WITH due AS (
+			SELECT id
+			FROM articles
+			WHERE status = 'scheduled' AND publish_at <= $1 AND deleted_at IS NULL
+			ORDER BY publish_at ASC
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		),
+		updated_article AS (
+			UPDATE articles
+			SET
+				status = 'published',
+				published_at = articles.publish_at,
+				publish_at = NULL,
+				updated_at = $1
+			FROM due
+			WHERE articles.id = due.id
+			RETURNING articles.*
+		)
+		SELECT ` + articleColumns + `, ` + fmt.Sprintf(articleListColumns, "NULL") + `
+		FROM updated_article a
+		JOIN users u ON u.id = a.author_id
+		ORDER BY a.published_at ASC
+	`
+
+	articles, err := queryArticles(ctx, tx, scanListedArticle, query, now, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Record an event for each article along with its publication
+	for _, article := range articles {
+		if err := insertArticleEvent(ctx, tx, repository.OutboxEventArticleStatusChanged, article); err != nil {
+			return nil, err
+		}
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		return nil, repository.ErrInternal
+	}
+
+	return articles, nil
+}
+
+// Delete moves an article to the trash, from which it can be restored until it is purged
+func (r *articleRepository) Delete(
+	ctx context.Context,
+	articleID int64,
+) error {
+	// Begin a transaction
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return repository.ErrInternal
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			log.Printf("transaction rollback error: %v", err)
+		}
+	}()
+
+	query := `
+		UPDATE articles
+		SET deleted_at = $2
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING id, author_id, slug, status
+	`
+
+	var article repository.Article
+	err = tx.QueryRowContext(ctx, query, articleID, time.Now()).
+		Scan(&article.ID, &article.AuthorID, &article.Slug, &article.Status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return repository.ErrArticleNotFound
+		}
+		return repository.ErrInternal
+	}
+
+	// Record the event along with the deletion
+	if err := insertArticleEvent(ctx, tx, repository.OutboxEventArticleDeleted, &article); err != nil {
+		return err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		return repository.ErrInternal
+	}
+
+	return nil
+}
+
+// ListDeleted lists the articles of a user that were moved to the trash after
+// deletedAfter, most recently deleted first
+func (r *articleRepository) ListDeleted(
+	ctx context.Context,
+	userID int64,
+	deletedAfter time.Time,
+) ([]*repository.Article, error) {
+	query := `
+		SELECT ` + articleColumns + `, a.deleted_at
+		FROM articles a
+		JOIN users u ON a.author_id = u.id
+		WHERE a.author_id = $1 AND a.deleted_at > $2
+		ORDER BY a.deleted_at DESC, a.id DESC
+	`
+
+	scan := func(row rowScanner) (*repository.Article, error) {
+		var deletedAt time.Time
+		article, err := scanArticleWith(row, &deletedAt)
+		if err != nil {
+			return nil, err
+		}
+		article.DeletedAt = &deletedAt
+		return article, nil
+	}
+
+	return r.queryArticles(ctx, scan, query, userID, deletedAfter)
+}
+
+// Restore takes an article of a user that was moved to the trash after deletedAfter back
+// out of it
+func (r *articleRepository) Restore(
 	ctx context.Context,
 	userID int64,
 	slug string,
-	title, description, body *string,
+	deletedAfter time.Time,
 ) (*repository.Article, error) {
+	// Begin a transaction
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, repository.ErrInternal
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			log.Printf("transaction rollback error: %v", err)
+		}
+	}()
+
 	query := `
-		WITH updated_article AS (
+		WITH restored_article AS (
 			UPDATE articles
-			SET
-				title = COALESCE($1, title),
-				description = COALESCE($2, description),
-				body = COALESCE($3, body),
-				updated_at = $4
-			WHERE slug = $5
-			RETURNING id, slug, title, description, body, author_id, created_at, updated_at
+			SET deleted_at = NULL
+			WHERE slug = $1 AND author_id = $2 AND deleted_at > $3
+			RETURNING *
 		)
-		SELECT
-			a.id, a.slug, a.title, a.description, a.body, a.author_id, a.created_at, a.updated_at,
-			u.id, u.username, u.bio, u.image
-		FROM updated_article a
+		SELECT ` + articleColumns + `
+		FROM restored_article a
 		JOIN users u ON u.id = a.author_id
 	`
 
-	now := time.Now()
-	var article repository.Article
-	article.Author = &repository.User{}
-	var authorBio, authorImage sql.NullString
-
-	err := r.db.QueryRowContext(ctx, query, title, description, body, now, slug).
-		Scan(
-			&article.ID,
-			&article.Slug,
-			&article.Title,
-			&article.Description,
-			&article.Body,
-			&article.AuthorID,
-			&article.CreatedAt,
-			&article.UpdatedAt,
-			&article.Author.ID,
-			&article.Author.Username,
-			&authorBio,
-			&authorImage,
-		)
+	article, err := scanArticle(tx.QueryRowContext(ctx, query, slug, userID, deletedAfter))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, repository.ErrArticleNotFound
@@ -266,63 +1015,94 @@ func (r *articleRepository) Update(
 		return nil, repository.ErrInternal
 	}
 
-	// Handle nullable values
-	if authorBio.Valid {
-		article.Author.Bio = authorBio.String
+	// Record the event along with the restoration
+	if err := insertArticleEvent(ctx, tx, repository.OutboxEventArticleRestored, article); err != nil {
+		return nil, err
 	}
-	if authorImage.Valid {
-		article.Author.Image = authorImage.String
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		return nil, repository.ErrInternal
 	}
 
 	// Get tags for the article
-	rows, err := r.db.QueryContext(
-		ctx,
-		"SELECT t.name FROM tags t JOIN article_tags at ON t.id = at.tag_id WHERE at.article_id = $1 ORDER BY t.name ASC",
-		article.ID,
-	)
+	article.TagList, err = r.getTagList(ctx, article.ID)
 	if err != nil {
-		return nil, repository.ErrInternal
+		return nil, err
+	}
+
+	return article, nil
+}
+
+// PurgeDeleted deletes up to limit articles that were moved to the trash before
+// deletedBefore for good, along with their comments and the tags no other article uses,
+// and returns how many were deleted. Rows locked by another worker are skipped, so
+// several replicas can purge at the same time.
+func (r *articleRepository) PurgeDeleted(
+	ctx context.Context,
+	deletedBefore time.Time,
+	limit int,
+) (int, error) {
+	// Begin a transaction
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, repository.ErrInternal
 	}
 	defer func() {
-		if err := rows.Close(); err != nil {
-			log.Printf("error closing rows: %v", err)
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			log.Printf("transaction rollback error: %v", err)
 		}
 	}()
 
-	var tagList []string
-	for rows.Next() {
-		var tag string
-		if err := rows.Scan(&tag); err != nil {
-			return nil, repository.ErrInternal
-		}
-		tagList = append(tagList, tag)
+	rows, err := tx.QueryContext(
+		ctx,
+		`SELECT id FROM articles
+		WHERE deleted_at <= $1
+		ORDER BY deleted_at ASC
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED`,
+		deletedBefore,
+		limit,
+	)
+	if err != nil {
+		return 0, repository.ErrInternal
 	}
-
-	if err := rows.Err(); err != nil {
-		return nil, repository.ErrInternal
+	articleIDs, err := scanIDs(rows)
+	if err != nil {
+		return 0, err
+	}
+	if len(articleIDs) == 0 {
+		return 0, nil
 	}
 
-	article.TagList = tagList
+	// Unlink the tags first, so the ones only these articles used can be deleted
+	rows, err = tx.QueryContext(
+		ctx,
+		"DELETE FROM article_tags WHERE article_id = ANY($1) RETURNING tag_id",
+		pq.Array(articleIDs),
+	)
+	if err != nil {
+		return 0, repository.ErrInternal
+	}
+	tagIDs, err := scanIDs(rows)
+	if err != nil {
+		return 0, err
+	}
 
-	return &article, nil
-}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM articles WHERE id = ANY($1)", pq.Array(articleIDs)); err != nil {
+		return 0, repository.ErrInternal
+	}
 
-// Delete deletes an article
-func (r *articleRepository) Delete(
-	ctx context.Context,
-	articleID int64,
-) error {
-	query := `
-		DELETE FROM articles
-		WHERE id = $1
-	`
+	if err := deleteOrphanedTags(ctx, tx, slices.Compact(slices.Sorted(slices.Values(tagIDs)))); err != nil {
+		return 0, err
+	}
 
-	_, err := r.db.ExecContext(ctx, query, articleID)
-	if err != nil {
-		return repository.ErrInternal
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		return 0, repository.ErrInternal
 	}
 
-	return nil
+	return len(articleIDs), nil
 }
 
 // Favorite adds an article to the user's favorites
@@ -348,7 +1128,7 @@ func (r *articleRepository) Favorite(
 		ON CONFLICT DO NOTHING
 	`
 
-	_, err = tx.ExecContext(ctx, query, userID, articleID)
+	result, err := tx.ExecContext(ctx, query, userID, articleID)
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok {
 			if pqErr.Code == "23503" {
@@ -363,6 +1143,19 @@ func (r *articleRepository) Favorite(
 		return repository.ErrInternal
 	}
 
+	// Record the event unless the article already was a favorite
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return repository.ErrInternal
+	}
+	if rowsAffected > 0 {
+		event := repository.FavoriteEvent{ArticleID: articleID, UserID: userID}
+		err := insertOutboxEvent(ctx, tx, repository.OutboxAggregateArticle, articleID, repository.OutboxEventArticleFavorited, event)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Commit the transaction
 	if err := tx.Commit(); err != nil {
 		return repository.ErrInternal
@@ -393,10 +1186,23 @@ func (r *articleRepository) Unfavorite(
 		WHERE user_id = $1 AND article_id = $2
 	`
 
-	_, err = tx.ExecContext(ctx, query, userID, articleID)
+	result, err := tx.ExecContext(ctx, query, userID, articleID)
+	if err != nil {
+		return repository.ErrInternal
+	}
+
+	// Record the event unless the article was not a favorite
+	rowsAffected, err := result.RowsAffected()
 	if err != nil {
 		return repository.ErrInternal
 	}
+	if rowsAffected > 0 {
+		event := repository.FavoriteEvent{ArticleID: articleID, UserID: userID}
+		err := insertOutboxEvent(ctx, tx, repository.OutboxAggregateArticle, articleID, repository.OutboxEventArticleUnfavorited, event)
+		if err != nil {
+			return err
+		}
+	}
 
 	// Commit the transaction
 	if err := tx.Commit(); err != nil {
@@ -442,41 +1248,136 @@ func (r *articleRepository) IsFavorited(
 	return exists, nil
 }
 
-// ListArticles lists articles with optional filters
+// IsInFeed checks if an article is in the feed of a user
+func (r *articleRepository) IsInFeed(
+	ctx context.Context,
+	userID int64,
+	articleID int64,
+) (bool, error) {
+	var exists bool
+	query := "SELECT EXISTS (SELECT 1" + feedArticles + " AND a.id = $2)"
+
+	err := r.db.QueryRowContext(ctx, query, userID, articleID).Scan(&exists)
+	if err != nil {
+		return false, repository.ErrInternal
+	}
+
+	return exists, nil
+}
+
+// ListArticles lists articles with optional filters. Only published articles are listed,
+// except for the articles of the current user. Articles matching a search query are
+// ranked by relevance and highlighted.
 func (r *articleRepository) ListArticles(
 	ctx context.Context,
 	filters repository.ArticleFilters,
 	currentUserID *int64,
 ) (*repository.ArticleListResult, error) {
-	// Build the base query
-	baseQuery := `
-		SELECT DISTINCT
-			a.id, a.slug, a.title, a.description, a.body, a.author_id, a.created_at, a.updated_at,
-			u.id, u.username, u.bio, u.image
-		FROM articles a
-		JOIN users u ON a.author_id = u.id
-	`
-
 	// Build WHERE clause based on filters
 	var conditions []string
 	var args []interface{}
 	argIndex := 1
 
-	if filters.Tag != nil {
+	// Articles in the trash are never listed
+	conditions = append(conditions, "a.deleted_at IS NULL")
+
+	// Anonymous viewers favorite and follow nothing, while signed-in viewers do not see
+	// the articles of users they blocked or who blocked them, nor the articles of users
+	// or with tags they muted
+	viewer := "NULL"
+	if currentUserID != nil {
+		viewer = fmt.Sprintf("$%d", argIndex)
 		conditions = append(
 			conditions,
-			fmt.Sprintf(
-				"EXISTS (SELECT 1 FROM article_tags at JOIN tags t ON at.tag_id = t.id WHERE at.article_id = a.id AND t.name = $%d)",
-				argIndex,
-			),
+			fmt.Sprintf("(a.status = 'published' OR a.author_id = %s)", viewer),
+			"NOT "+fmt.Sprintf(blockedBetween, viewer, "a.author_id"),
+			"NOT "+fmt.Sprintf(mutedFor, viewer, "a"),
 		)
-		args = append(args, *filters.Tag)
+		args = append(args, *currentUserID)
+		argIndex++
+	} else {
+		conditions = append(conditions, "a.status = 'published'")
+	}
+
+	columns := articleColumns + ", " + fmt.Sprintf(articleListColumns, viewer)
+	scan := scanListedArticle
+
+	// Order the articles by the sort order, paging through them with cursors when it
+	// is by creation time
+	pages := articleKeyset
+	if filters.Sort == repository.ArticleSortOldest {
+		pages = oldestArticleKeyset
+	}
+	sortOrder, sorted := articleSortOrders[filters.Sort]
+	after, before := filters.After, filters.Before
+	if sorted {
+		after, before = nil, nil
+	}
+	backwards := before != nil
+	orderBy := pages.orderBy(backwards)
+	if sorted {
+		orderBy = sortOrder + ", " + orderBy
+	}
+
+	// Match the search query and rank the articles by relevance
+	if filters.Query != nil {
+		tsQuery := fmt.Sprintf("websearch_to_tsquery($%d::regconfig, $%d)", argIndex, argIndex+1)
+		conditions = append(conditions, "a.search_vector @@ "+tsQuery)
+		args = append(args, r.searchLanguage, *filters.Query)
+		argIndex += 2
+
+		columns += ", " + fmt.Sprintf(searchHighlightColumns, tsQuery)
+		if filters.Sort == "" {
+			orderBy = "ts_rank(a.search_vector, " + tsQuery + ") DESC, " + orderBy
+		}
+		scan = scanSearchArticle
+	}
+
+	if filters.Status != nil {
+		conditions = append(conditions, fmt.Sprintf("a.status = $%d", argIndex))
+		args = append(args, *filters.Status)
+		argIndex++
+	}
+
+	// articleTagged matches the articles with a tag in the array $%d
+	const articleTagged = "EXISTS (SELECT 1 FROM article_tags at JOIN tags t ON at.tag_id = t.id WHERE at.article_id = a.id AND t.name = ANY($%d))"
+
+	if len(filters.Tags) > 0 {
+		if filters.TagMatch == repository.ArticleTagMatchAll {
+			// Every tag is matched if as many different tags match as there are
+			tags := slices.Compact(slices.Sorted(slices.Values(filters.Tags)))
+			conditions = append(
+				conditions,
+				fmt.Sprintf(
+					"(SELECT COUNT(DISTINCT t.name) FROM article_tags at JOIN tags t ON at.tag_id = t.id WHERE at.article_id = a.id AND t.name = ANY($%d)) = $%d",
+					argIndex,
+					argIndex+1,
+				),
+			)
+			args = append(args, pq.Array(tags), len(tags))
+			argIndex += 2
+		} else {
+			conditions = append(conditions, fmt.Sprintf(articleTagged, argIndex))
+			args = append(args, pq.Array(filters.Tags))
+			argIndex++
+		}
+	}
+
+	if len(filters.ExcludeTags) > 0 {
+		conditions = append(conditions, "NOT "+fmt.Sprintf(articleTagged, argIndex))
+		args = append(args, pq.Array(filters.ExcludeTags))
+		argIndex++
+	}
+
+	if len(filters.Authors) > 0 {
+		conditions = append(conditions, fmt.Sprintf("u.username = ANY($%d)", argIndex))
+		args = append(args, pq.Array(filters.Authors))
 		argIndex++
 	}
 
-	if filters.Author != nil {
-		conditions = append(conditions, fmt.Sprintf("u.username = $%d", argIndex))
-		args = append(args, *filters.Author)
+	if len(filters.ExcludeAuthors) > 0 {
+		conditions = append(conditions, fmt.Sprintf("u.username <> ALL($%d)", argIndex))
+		args = append(args, pq.Array(filters.ExcludeAuthors))
 		argIndex++
 	}
 
@@ -492,18 +1393,39 @@ func (r *articleRepository) ListArticles(
 		argIndex++
 	}
 
-	whereClause := ""
-	if len(conditions) > 0 {
-		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	if filters.CreatedAfter != nil {
+		conditions = append(conditions, fmt.Sprintf("a.created_at > $%d", argIndex))
+		args = append(args, *filters.CreatedAfter)
+		argIndex++
+	}
+
+	if filters.CreatedBefore != nil {
+		conditions = append(conditions, fmt.Sprintf("a.created_at < $%d", argIndex))
+		args = append(args, *filters.CreatedBefore)
+		argIndex++
+	}
+
+	whereClause := "WHERE " + strings.Join(conditions, " AND ")
+
+	// The count covers every page, so it leaves out the cursor
+	countQuery := "SELECT COUNT(DISTINCT a.id) FROM articles a JOIN users u ON a.author_id = u.id " + whereClause
+	countArgs := slices.Clone(args)
+
+	// Narrow the articles down to the page after or before the cursor
+	if condition, cursorArgs := pages.condition(after, before, argIndex); condition != "" {
+		whereClause += " AND " + condition
+		args = append(args, cursorArgs...)
+		argIndex += len(cursorArgs)
 	}
 
 	// Build the complete query with ORDER BY, LIMIT, and OFFSET
-	query := baseQuery + " " + whereClause + " ORDER BY a.created_at DESC"
+	query := "SELECT " + columns + " FROM articles a JOIN users u ON a.author_id = u.id " +
+		whereClause + " ORDER BY " + orderBy
 
-	// Add LIMIT and OFFSET
+	// Add LIMIT and OFFSET, reading one more article to tell whether another page follows
 	if filters.Limit > 0 {
 		query += fmt.Sprintf(" LIMIT $%d", argIndex)
-		args = append(args, filters.Limit)
+		args = append(args, filters.Limit+1)
 		argIndex++
 	}
 
@@ -513,97 +1435,65 @@ func (r *articleRepository) ListArticles(
 	}
 
 	// Execute the query
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	articles, err := r.queryArticles(ctx, scan, query, args...)
 	if err != nil {
-		return nil, repository.ErrInternal
+		return nil, err
 	}
-	defer func() {
-		if err := rows.Close(); err != nil {
-			log.Printf("error closing rows: %v", err)
-		}
-	}()
-
-	var articles []*repository.Article
-	for rows.Next() {
-		var article repository.Article
-		article.Author = &repository.User{}
-		var authorBio, authorImage sql.NullString
-
-		err := rows.Scan(
-			&article.ID,
-			&article.Slug,
-			&article.Title,
-			&article.Description,
-			&article.Body,
-			&article.AuthorID,
-			&article.CreatedAt,
-			&article.UpdatedAt,
-			&article.Author.ID,
-			&article.Author.Username,
-			&authorBio,
-			&authorImage,
-		)
-		if err != nil {
-			return nil, repository.ErrInternal
-		}
-
-		// Handle nullable values
-		if authorBio.Valid {
-			article.Author.Bio = authorBio.String
-		}
-		if authorImage.Valid {
-			article.Author.Image = authorImage.String
-		}
+	articles, hasMore := trimPage(articles, filters.Limit, backwards)
 
-		// Get tags for the article
-		tagRows, err := r.db.QueryContext(
-			ctx,
-			"SELECT t.name FROM tags t JOIN article_tags at ON t.id = at.tag_id WHERE at.article_id = $1 ORDER BY t.name ASC",
-			article.ID,
-		)
-		if err != nil {
-			return nil, repository.ErrInternal
-		}
+	// Get total count for pagination
+	var count int
+	err = r.db.QueryRowContext(ctx, countQuery, countArgs...).Scan(&count)
+	if err != nil {
+		return nil, repository.ErrInternal
+	}
 
-		var tagList []string
-		for tagRows.Next() {
-			var tag string
-			if err := tagRows.Scan(&tag); err != nil {
-				tagRows.Close()
-				return nil, repository.ErrInternal
-			}
-			tagList = append(tagList, tag)
-		}
-		tagRows.Close()
+	return &repository.ArticleListResult{
+		Articles: articles,
+		Count:    count,
+		HasMore:  hasMore,
+	}, nil
+}
 
-		if err := tagRows.Err(); err != nil {
-			return nil, repository.ErrInternal
-		}
+// GetArticlesFeed gets published articles from users that the current user follows,
+// leaving out the articles of users or with tags they muted
+func (r *articleRepository) GetArticlesFeed(
+	ctx context.Context,
+	userID int64,
+	filters repository.FeedFilters,
+) (*repository.ArticleListResult, error) {
+	query := "SELECT " + articleColumns + ", " + fmt.Sprintf(articleListColumns, "$1") + feedArticles
+	args := []any{userID}
+
+	// Narrow the articles down to the page after or before the cursor
+	backwards := filters.Before != nil
+	if condition, cursorArgs := articleKeyset.condition(filters.After, filters.Before, len(args)+1); condition != "" {
+		query += " AND " + condition
+		args = append(args, cursorArgs...)
+	}
+	query += " ORDER BY " + articleKeyset.orderBy(backwards)
 
-		article.TagList = tagList
-		articles = append(articles, &article)
+	// Add LIMIT and OFFSET, reading one more article to tell whether another page follows
+	if filters.Limit > 0 {
+		args = append(args, filters.Limit+1)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	if filters.Offset > 0 {
+		args = append(args, filters.Offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, repository.ErrInternal
+	articles, err := r.queryArticles(ctx, scanListedArticle, query, args...)
+	if err != nil {
+		return nil, err
 	}
+	articles, hasMore := trimPage(articles, filters.Limit, backwards)
 
 	// Get total count for pagination
-	countQuery := "SELECT COUNT(DISTINCT a.id) FROM articles a JOIN users u ON a.author_id = u.id"
-	if len(conditions) > 0 {
-		countQuery += " " + whereClause
-	}
+	countQuery := "SELECT COUNT(DISTINCT a.id)" + feedArticles
 
 	var count int
-	// Use args without LIMIT and OFFSET for count query
-	countArgs := args
-	if filters.Limit > 0 {
-		countArgs = countArgs[:len(countArgs)-1]
-	}
-	if filters.Offset > 0 {
-		countArgs = countArgs[:len(countArgs)-1]
-	}
-	err = r.db.QueryRowContext(ctx, countQuery, countArgs...).Scan(&count)
+	err = r.db.QueryRowContext(ctx, countQuery, userID).Scan(&count)
 	if err != nil {
 		return nil, repository.ErrInternal
 	}
@@ -611,28 +1501,30 @@ func (r *articleRepository) ListArticles(
 	return &repository.ArticleListResult{
 		Articles: articles,
 		Count:    count,
+		HasMore:  hasMore,
 	}, nil
 }
 
-// GetArticlesFeed gets articles from users that the current user follows
-func (r *articleRepository) GetArticlesFeed(
+// queryArticles runs a query selecting a list of articles and scans each row with scan
+func (r *articleRepository) queryArticles(
 	ctx context.Context,
-	userID int64,
-	limit, offset int,
-) (*repository.ArticleListResult, error) {
-	query := `
-		SELECT DISTINCT
-			a.id, a.slug, a.title, a.description, a.body, a.author_id, a.created_at, a.updated_at,
-			u.id, u.username, u.bio, u.image
-		FROM articles a
-		JOIN users u ON a.author_id = u.id
-		JOIN follows f ON u.id = f.following_id
-		WHERE f.follower_id = $1
-		ORDER BY a.created_at DESC
-		LIMIT $2 OFFSET $3
-	`
+	scan func(rowScanner) (*repository.Article, error),
+	query string,
+	args ...any,
+) ([]*repository.Article, error) {
+	return queryArticles(ctx, r.db, scan, query, args...)
+}
 
-	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+// queryArticles runs a query selecting a list of articles on q and scans each row with
+// scan
+func queryArticles(
+	ctx context.Context,
+	q queryer,
+	scan func(rowScanner) (*repository.Article, error),
+	query string,
+	args ...any,
+) ([]*repository.Article, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, repository.ErrInternal
 	}
@@ -644,86 +1536,16 @@ func (r *articleRepository) GetArticlesFeed(
 
 	var articles []*repository.Article
 	for rows.Next() {
-		var article repository.Article
-		article.Author = &repository.User{}
-		var authorBio, authorImage sql.NullString
-
-		err := rows.Scan(
-			&article.ID,
-			&article.Slug,
-			&article.Title,
-			&article.Description,
-			&article.Body,
-			&article.AuthorID,
-			&article.CreatedAt,
-			&article.UpdatedAt,
-			&article.Author.ID,
-			&article.Author.Username,
-			&authorBio,
-			&authorImage,
-		)
-		if err != nil {
-			return nil, repository.ErrInternal
-		}
-
-		// Handle nullable values
-		if authorBio.Valid {
-			article.Author.Bio = authorBio.String
-		}
-		if authorImage.Valid {
-			article.Author.Image = authorImage.String
-		}
-
-		// Get tags for the article
-		tagRows, err := r.db.QueryContext(
-			ctx,
-			"SELECT t.name FROM tags t JOIN article_tags at ON t.id = at.tag_id WHERE at.article_id = $1 ORDER BY t.name ASC",
-			article.ID,
-		)
+		article, err := scan(rows)
 		if err != nil {
 			return nil, repository.ErrInternal
 		}
-
-		var tagList []string
-		for tagRows.Next() {
-			var tag string
-			if err := tagRows.Scan(&tag); err != nil {
-				tagRows.Close()
-				return nil, repository.ErrInternal
-			}
-			tagList = append(tagList, tag)
-		}
-		tagRows.Close()
-
-		if err := tagRows.Err(); err != nil {
-			return nil, repository.ErrInternal
-		}
-
-		article.TagList = tagList
-		articles = append(articles, &article)
+		articles = append(articles, article)
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, repository.ErrInternal
 	}
 
-	// Get total count for pagination
-	countQuery := `
-		SELECT COUNT(DISTINCT a.id)
-		FROM articles a
-		JOIN users u ON a.author_id = u.id
-		JOIN follows f ON u.id = f.following_id
-		WHERE f.follower_id = $1
-	`
-
-	var count int
-	err = r.db.QueryRowContext(ctx, countQuery, userID).Scan(&count)
-	if err != nil {
-		return nil, repository.ErrInternal
-	}
-
-	return &repository.ArticleListResult{
-		Articles: articles,
-		Count:    count,
-	}, nil
+	return articles, nil
 }
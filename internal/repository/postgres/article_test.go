@@ -2,8 +2,12 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
+	"database/sql/driver"
 	"errors"
+	"fmt"
 	"log"
+	"reflect"
 	"testing"
 	"time"
 
@@ -20,7 +24,7 @@ func Test_articleRepository_Create(t *testing.T) {
 	tests := []struct {
 		name            string
 		userID          int64
-		slug            string
+		slugs           []string
 		title           string
 		description     string
 		body            string
@@ -32,7 +36,7 @@ func Test_articleRepository_Create(t *testing.T) {
 		{
 			name:        "Successful creation",
 			userID:      1,
-			slug:        "test-article",
+			slugs:       []string{"test-article"},
 			title:       "Test Article",
 			description: "Test Description",
 			body:        "Test Body",
@@ -41,12 +45,17 @@ func Test_articleRepository_Create(t *testing.T) {
 				// Mock beginning transaction
 				mock.ExpectBegin()
 
-				mock.ExpectQuery(`WITH inserted_article AS`).
-					WithArgs("test-article", "Test Article", "Test Description", "Test Body", int64(1), sqlmock.AnyArg(), sqlmock.AnyArg()).
-					WillReturnRows(sqlmock.NewRows([]string{"id", "slug", "title", "description", "body", "author_id", "created_at", "updated_at", "id", "username", "bio", "image"}).
-						AddRow(1, "test-article", "Test Article", "Test Description", "Test Body", int64(1), time.Now(), time.Now(), 1, "testuser", "Test Bio", "https://example.com/image.jpg"),
+				mock.ExpectQuery(`WITH inserted_article AS \( INSERT INTO articles .* ON CONFLICT \(slug\) DO NOTHING RETURNING \* \)`).
+					WithArgs("test-article", "Test Article", "Test Description", "Test Body", int64(1), "published", nil, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "english").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "slug", "title", "description", "body", "author_id", "status", "publish_at", "published_at", "created_at", "updated_at", "id", "username", "bio", "image"}).
+						AddRow(1, "test-article", "Test Article", "Test Description", "Test Body", int64(1), "published", nil, time.Now(), time.Now(), time.Now(), 1, "testuser", "Test Bio", "https://example.com/image.jpg"),
 					)
 
+				// Expect the first revision to be recorded
+				mock.ExpectExec(`INSERT INTO article_revisions`).
+					WithArgs(int64(1), "Test Article", "Test Description", "Test Body", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
 				// Expect insert tag queries
 				mock.ExpectQuery(`INSERT INTO tags \(name\) VALUES \(\$1\) ON CONFLICT \(name\) DO UPDATE SET name = EXCLUDED.name RETURNING id`).
 					WithArgs("tag1").
@@ -64,6 +73,11 @@ func Test_articleRepository_Create(t *testing.T) {
 					WithArgs(1, 2).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 
+				// Expect the event to be written in the same transaction
+				mock.ExpectExec(`INSERT INTO outbox`).
+					WithArgs("article", int64(1), "article.created", `{"articleId":1,"authorId":1,"slug":"test-article","status":"published"}`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
 				// Expect commit
 				mock.ExpectCommit()
 			},
@@ -117,7 +131,7 @@ func Test_articleRepository_Create(t *testing.T) {
 		{
 			name:        "Non-existent user",
 			userID:      999,
-			slug:        "test-article",
+			slugs:       []string{"test-article"},
 			title:       "Test Article",
 			description: "Test Description",
 			body:        "Test Body",
@@ -126,7 +140,7 @@ func Test_articleRepository_Create(t *testing.T) {
 				mock.ExpectBegin()
 
 				mock.ExpectQuery(`WITH inserted_article AS`).
-					WithArgs("test-article", "Test Article", "Test Description", "Test Body", int64(999), sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WithArgs("test-article", "Test Article", "Test Description", "Test Body", int64(999), "published", nil, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "english").
 					WillReturnError(&pq.Error{
 						Code:       "23503",
 						Message:    "insert or update on table \"articles\" violates foreign key constraint",
@@ -139,9 +153,9 @@ func Test_articleRepository_Create(t *testing.T) {
 			validateArticle: nil,
 		},
 		{
-			name:        "Duplicate slug",
+			name:        "Taken slug is skipped",
 			userID:      1,
-			slug:        "existing-article",
+			slugs:       []string{"test-article", "test-article-2"},
 			title:       "Test Article",
 			description: "Test Description",
 			body:        "Test Body",
@@ -149,13 +163,52 @@ func Test_articleRepository_Create(t *testing.T) {
 			mockSetup: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
 
+				// The first slug is taken, so nothing is inserted
 				mock.ExpectQuery(`WITH inserted_article AS`).
-					WithArgs("existing-article", "Test Article", "Test Description", "Test Body", int64(1), sqlmock.AnyArg(), sqlmock.AnyArg()).
-					WillReturnError(&pq.Error{
-						Code:       "23505",
-						Message:    "duplicate key value violates unique constraint",
-						Constraint: "articles_slug_key",
-					})
+					WithArgs("test-article", "Test Article", "Test Description", "Test Body", int64(1), "published", nil, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "english").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+				mock.ExpectQuery(`WITH inserted_article AS`).
+					WithArgs("test-article-2", "Test Article", "Test Description", "Test Body", int64(1), "published", nil, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "english").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "slug", "title", "description", "body", "author_id", "status", "publish_at", "published_at", "created_at", "updated_at", "id", "username", "bio", "image"}).
+						AddRow(2, "test-article-2", "Test Article", "Test Description", "Test Body", int64(1), "published", nil, time.Now(), time.Now(), time.Now(), 1, "testuser", nil, nil),
+					)
+
+				mock.ExpectExec(`INSERT INTO article_revisions`).
+					WithArgs(int64(2), "Test Article", "Test Description", "Test Body", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.ExpectExec(`INSERT INTO outbox`).
+					WithArgs("article", int64(2), "article.created", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+			validateArticle: func(t *testing.T, article *repository.Article) {
+				if article.Slug != "test-article-2" {
+					t.Errorf("Expected slug test-article-2, got %q", article.Slug)
+				}
+			},
+		},
+		{
+			name:        "All slugs taken",
+			userID:      1,
+			slugs:       []string{"existing-article", "existing-article-2"},
+			title:       "Test Article",
+			description: "Test Description",
+			body:        "Test Body",
+			tagList:     []string{},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(`WITH inserted_article AS`).
+					WithArgs("existing-article", "Test Article", "Test Description", "Test Body", int64(1), "published", nil, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "english").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+				mock.ExpectQuery(`WITH inserted_article AS`).
+					WithArgs("existing-article-2", "Test Article", "Test Description", "Test Body", int64(1), "published", nil, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "english").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
 
 				mock.ExpectRollback()
 			},
@@ -183,7 +236,7 @@ func Test_articleRepository_Create(t *testing.T) {
 			}
 
 			// Create repository with mock database
-			repo := NewArticleRepository(db)
+			repo := NewArticleRepository(db, "english")
 
 			// Create context
 			ctx := context.Background()
@@ -192,10 +245,12 @@ func Test_articleRepository_Create(t *testing.T) {
 			article, err := repo.Create(
 				ctx,
 				tt.userID,
-				tt.slug,
+				tt.slugs,
 				tt.title,
 				tt.description,
 				tt.body,
+				repository.ArticleStatusPublished,
+				nil,
 				tt.tagList,
 			)
 
@@ -233,15 +288,15 @@ func Test_articleRepository_GetBySlug(t *testing.T) {
 			slug: "test-article",
 			mockSetup: func(mock sqlmock.Sqlmock) {
 				rows := sqlmock.NewRows([]string{
-					"id", "slug", "title", "description", "body", "author_id", "created_at", "updated_at",
-					"author_id", "username", "bio", "image",
+					"id", "slug", "title", "description", "body", "author_id", "status", "publish_at",
+					"published_at", "created_at", "updated_at", "author_id", "username", "bio", "image",
 				}).AddRow(
-					1, "test-article", "Test Article", "Test Description", "Test Body", 1,
-					time.Now(),
+					1, "test-article", "Test Article", "Test Description", "Test Body", 1, "published",
+					nil, time.Now(), time.Now(),
 					time.Now(), 1, "testuser", "Test Bio", "https://example.com/image.jpg",
 				)
 
-				mock.ExpectQuery(`SELECT a.id, a.slug, a.title, a.description, a.body, a.author_id, a.created_at, a.updated_at, u.id, u.username, u.bio, u.image FROM articles a JOIN users u ON a.author_id = u.id WHERE a.slug = \$1`).
+				mock.ExpectQuery(`SELECT a.id, a.slug, a.title, a.description, a.body, a.author_id, a.status, a.publish_at, a.published_at, a.created_at, a.updated_at, u.id, u.username, u.bio, u.image FROM articles a JOIN users u ON a.author_id = u.id WHERE a.slug = \$1`).
 					WithArgs("test-article").
 					WillReturnRows(rows)
 
@@ -307,7 +362,7 @@ func Test_articleRepository_GetBySlug(t *testing.T) {
 			name: "Article not found",
 			slug: "non-existent-article",
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				mock.ExpectQuery(`SELECT a.id, a.slug, a.title, a.description, a.body, a.author_id, a.created_at, a.updated_at, u.id, u.username, u.bio, u.image FROM articles a JOIN users u ON a.author_id = u.id WHERE a.slug = \$1`).
+				mock.ExpectQuery(`SELECT a.id, a.slug, a.title, a.description, a.body, a.author_id, a.status, a.publish_at, a.published_at, a.created_at, a.updated_at, u.id, u.username, u.bio, u.image FROM articles a JOIN users u ON a.author_id = u.id WHERE a.slug = \$1`).
 					WithArgs("non-existent-article").
 					WillReturnRows(sqlmock.NewRows([]string{}))
 			},
@@ -318,7 +373,7 @@ func Test_articleRepository_GetBySlug(t *testing.T) {
 			name: "Database error",
 			slug: "test-article",
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				mock.ExpectQuery(`SELECT a.id, a.slug, a.title, a.description, a.body, a.author_id, a.created_at, a.updated_at, u.id, u.username, u.bio, u.image FROM articles a JOIN users u ON a.author_id = u.id WHERE a.slug = \$1`).
+				mock.ExpectQuery(`SELECT a.id, a.slug, a.title, a.description, a.body, a.author_id, a.status, a.publish_at, a.published_at, a.created_at, a.updated_at, u.id, u.username, u.bio, u.image FROM articles a JOIN users u ON a.author_id = u.id WHERE a.slug = \$1`).
 					WithArgs("test-article").
 					WillReturnError(errors.New("database error"))
 			},
@@ -346,7 +401,7 @@ func Test_articleRepository_GetBySlug(t *testing.T) {
 			}
 
 			// Create repository with mock database
-			repo := NewArticleRepository(db)
+			repo := NewArticleRepository(db, "english")
 
 			// Create context
 			ctx := context.Background()
@@ -371,3 +426,1514 @@ func Test_articleRepository_GetBySlug(t *testing.T) {
 		})
 	}
 }
+
+func Test_articleRepository_Update(t *testing.T) {
+	t.Parallel()
+
+	title := "Updated Title"
+	newSlug := "updated-title"
+	newSlugs := []string{"updated-title", "updated-title-2"}
+
+	// Define test cases
+	tests := []struct {
+		name        string
+		newSlugs    []string
+		tags        repository.TagChanges
+		mockSetup   func(mock sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name: "Update records a revision",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(`WITH updated_article AS \( UPDATE articles SET slug = COALESCE\(\$1, slug\), title = COALESCE\(\$2, title\)`).
+					WithArgs(nil, &title, nil, nil, sqlmock.AnyArg(), "test-article").
+					WillReturnRows(sqlmock.NewRows([]string{
+						"id", "slug", "title", "description", "body", "author_id", "status", "publish_at",
+						"published_at", "created_at", "updated_at", "author_id", "username", "bio", "image",
+					}).AddRow(
+						1, "test-article", "Updated Title", "Test Description", "Test Body", 1, "published",
+						nil, time.Now(), time.Now(), time.Now(), 1, "testuser", nil, nil,
+					))
+
+				mock.ExpectExec(`INSERT INTO article_revisions \(article_id, revision_number, title, description, body, created_at\) SELECT \$1, latest.revision_number \+ 1, \$2, \$3, \$4, \$5 FROM \( SELECT COALESCE\(MAX\(revision_number\), 0\) AS revision_number FROM article_revisions WHERE article_id = \$1 \) latest WHERE NOT EXISTS \( SELECT 1 FROM article_revisions WHERE article_id = \$1 AND revision_number = latest.revision_number AND title = \$2 AND description = \$3 AND body = \$4 \)`).
+					WithArgs(int64(1), "Updated Title", "Test Description", "Test Body", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(2, 1))
+
+				mock.ExpectExec(`INSERT INTO outbox`).
+					WithArgs("article", int64(1), "article.updated", `{"articleId":1,"authorId":1,"slug":"test-article","status":"published"}`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.ExpectCommit()
+
+				mock.ExpectQuery(`SELECT t.name FROM tags t`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"name"}))
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Tags are added and removed",
+			tags: repository.TagChanges{Add: []string{"go"}, Remove: []string{"sql"}},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(`WITH updated_article AS`).
+					WithArgs(nil, &title, nil, nil, sqlmock.AnyArg(), "test-article").
+					WillReturnRows(sqlmock.NewRows([]string{
+						"id", "slug", "title", "description", "body", "author_id", "status", "publish_at",
+						"published_at", "created_at", "updated_at", "author_id", "username", "bio", "image",
+					}).AddRow(
+						1, "test-article", "Updated Title", "Test Description", "Test Body", 1, "published",
+						nil, time.Now(), time.Now(), time.Now(), 1, "testuser", nil, nil,
+					))
+
+				mock.ExpectExec(`INSERT INTO article_revisions`).
+					WillReturnResult(sqlmock.NewResult(2, 1))
+
+				mock.ExpectQuery(`INSERT INTO tags`).
+					WithArgs("go").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectExec(`INSERT INTO article_tags`).
+					WithArgs(int64(1), int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectQuery(`DELETE FROM article_tags at USING tags t WHERE at.tag_id = t.id AND at.article_id = \$1 AND t.name = ANY\(\$2\) RETURNING at.tag_id`).
+					WithArgs(int64(1), pq.Array([]string{"sql"})).
+					WillReturnRows(sqlmock.NewRows([]string{"tag_id"}).AddRow(4))
+
+				mock.ExpectQuery(`SELECT id FROM tags WHERE id = ANY\(\$1\) ORDER BY name FOR UPDATE`).
+					WithArgs(pq.Array([]int64{4})).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+				mock.ExpectExec(`DELETE FROM tags t WHERE t.id = ANY\(\$1\) AND NOT EXISTS`).
+					WithArgs(pq.Array([]int64{4})).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				mock.ExpectExec(`INSERT INTO outbox`).
+					WithArgs("article", int64(1), "article.updated", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.ExpectCommit()
+
+				mock.ExpectQuery(`SELECT t.name FROM tags t`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("go"))
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Tag list replaces the tags",
+			tags: repository.TagChanges{Replace: []string{"go", "sql"}},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(`WITH updated_article AS`).
+					WithArgs(nil, &title, nil, nil, sqlmock.AnyArg(), "test-article").
+					WillReturnRows(sqlmock.NewRows([]string{
+						"id", "slug", "title", "description", "body", "author_id", "status", "publish_at",
+						"published_at", "created_at", "updated_at", "author_id", "username", "bio", "image",
+					}).AddRow(
+						1, "test-article", "Updated Title", "Test Description", "Test Body", 1, "published",
+						nil, time.Now(), time.Now(), time.Now(), 1, "testuser", nil, nil,
+					))
+
+				mock.ExpectExec(`INSERT INTO article_revisions`).
+					WillReturnResult(sqlmock.NewResult(2, 1))
+
+				mock.ExpectQuery(`DELETE FROM article_tags at USING tags t WHERE at.tag_id = t.id AND at.article_id = \$1 AND NOT \(t.name = ANY\(\$2\)\) RETURNING at.tag_id`).
+					WithArgs(int64(1), pq.Array([]string{"go", "sql"})).
+					WillReturnRows(sqlmock.NewRows([]string{"tag_id"}).AddRow(3))
+
+				mock.ExpectQuery(`INSERT INTO tags`).
+					WithArgs("go").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectExec(`INSERT INTO article_tags`).
+					WithArgs(int64(1), int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(`INSERT INTO tags`).
+					WithArgs("sql").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+				mock.ExpectExec(`INSERT INTO article_tags`).
+					WithArgs(int64(1), int64(4)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectQuery(`SELECT id FROM tags WHERE id = ANY\(\$1\) ORDER BY name FOR UPDATE`).
+					WithArgs(pq.Array([]int64{3})).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+				mock.ExpectExec(`DELETE FROM tags t WHERE t.id = ANY\(\$1\) AND NOT EXISTS`).
+					WithArgs(pq.Array([]int64{3})).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectExec(`INSERT INTO outbox`).
+					WithArgs("article", int64(1), "article.updated", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.ExpectCommit()
+
+				mock.ExpectQuery(`SELECT t.name FROM tags t`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("go").AddRow("sql"))
+			},
+			expectedErr: nil,
+		},
+		{
+			name:     "Slug change keeps the old slug",
+			newSlugs: newSlugs,
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+
+				mock.ExpectExec(`SAVEPOINT update_slug`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				mock.ExpectQuery(`WITH updated_article AS`).
+					WithArgs(&newSlug, &title, nil, nil, sqlmock.AnyArg(), "test-article").
+					WillReturnRows(sqlmock.NewRows([]string{
+						"id", "slug", "title", "description", "body", "author_id", "status", "publish_at",
+						"published_at", "created_at", "updated_at", "author_id", "username", "bio", "image",
+					}).AddRow(
+						1, "updated-title", "Updated Title", "Test Description", "Test Body", 1, "published",
+						nil, time.Now(), time.Now(), time.Now(), 1, "testuser", nil, nil,
+					))
+
+				mock.ExpectExec(`DELETE FROM article_slug_history WHERE slug = \$1`).
+					WithArgs("updated-title").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				mock.ExpectExec(`INSERT INTO article_slug_history \(slug, article_id, created_at\) VALUES \(\$1, \$2, \$3\) ON CONFLICT \(slug\) DO UPDATE`).
+					WithArgs("test-article", int64(1), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.ExpectExec(`INSERT INTO article_revisions`).
+					WithArgs(int64(1), "Updated Title", "Test Description", "Test Body", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(2, 1))
+
+				mock.ExpectExec(`INSERT INTO outbox`).
+					WithArgs("article", int64(1), "article.updated", `{"articleId":1,"authorId":1,"slug":"updated-title","status":"published"}`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.ExpectCommit()
+
+				mock.ExpectQuery(`SELECT t.name FROM tags t`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"name"}))
+			},
+			expectedErr: nil,
+		},
+		{
+			name:     "Taken slug is skipped",
+			newSlugs: newSlugs,
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+
+				mock.ExpectExec(`SAVEPOINT update_slug`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(`WITH updated_article AS`).
+					WithArgs(&newSlug, &title, nil, nil, sqlmock.AnyArg(), "test-article").
+					WillReturnError(&pq.Error{Code: "23505", Constraint: "articles_slug_key"})
+				mock.ExpectExec(`ROLLBACK TO SAVEPOINT update_slug`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				mock.ExpectExec(`SAVEPOINT update_slug`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(`WITH updated_article AS`).
+					WithArgs(&newSlugs[1], &title, nil, nil, sqlmock.AnyArg(), "test-article").
+					WillReturnRows(sqlmock.NewRows([]string{
+						"id", "slug", "title", "description", "body", "author_id", "status", "publish_at",
+						"published_at", "created_at", "updated_at", "author_id", "username", "bio", "image",
+					}).AddRow(
+						1, "updated-title-2", "Updated Title", "Test Description", "Test Body", 1, "published",
+						nil, time.Now(), time.Now(), time.Now(), 1, "testuser", nil, nil,
+					))
+
+				mock.ExpectExec(`DELETE FROM article_slug_history`).
+					WithArgs("updated-title-2").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`INSERT INTO article_slug_history`).
+					WithArgs("test-article", int64(1), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.ExpectExec(`INSERT INTO article_revisions`).
+					WillReturnResult(sqlmock.NewResult(2, 1))
+
+				mock.ExpectExec(`INSERT INTO outbox`).
+					WithArgs("article", int64(1), "article.updated", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.ExpectCommit()
+
+				mock.ExpectQuery(`SELECT t.name FROM tags t`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"name"}))
+			},
+			expectedErr: nil,
+		},
+		{
+			name:     "All slugs taken",
+			newSlugs: newSlugs[:1],
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+
+				mock.ExpectExec(`SAVEPOINT update_slug`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(`WITH updated_article AS`).
+					WithArgs(&newSlug, &title, nil, nil, sqlmock.AnyArg(), "test-article").
+					WillReturnError(&pq.Error{Code: "23505", Constraint: "articles_slug_key"})
+				mock.ExpectExec(`ROLLBACK TO SAVEPOINT update_slug`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				mock.ExpectRollback()
+			},
+			expectedErr: repository.ErrDuplicateSlug,
+		},
+		{
+			name: "Revision insert fails",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(`WITH updated_article AS`).
+					WithArgs(nil, &title, nil, nil, sqlmock.AnyArg(), "test-article").
+					WillReturnRows(sqlmock.NewRows([]string{
+						"id", "slug", "title", "description", "body", "author_id", "status", "publish_at",
+						"published_at", "created_at", "updated_at", "author_id", "username", "bio", "image",
+					}).AddRow(
+						1, "test-article", "Updated Title", "Test Description", "Test Body", 1, "published",
+						nil, time.Now(), time.Now(), time.Now(), 1, "testuser", nil, nil,
+					))
+
+				mock.ExpectExec(`INSERT INTO article_revisions`).
+					WillReturnError(errors.New("database error"))
+
+				mock.ExpectRollback()
+			},
+			expectedErr: repository.ErrInternal,
+		},
+		{
+			name: "Article not found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(`WITH updated_article AS`).
+					WithArgs(nil, &title, nil, nil, sqlmock.AnyArg(), "test-article").
+					WillReturnRows(sqlmock.NewRows([]string{}))
+
+				mock.ExpectRollback()
+			},
+			expectedErr: repository.ErrArticleNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock database for this test case
+			db, mock := setupTestDB(t)
+			defer func() {
+				if err := db.Close(); err != nil {
+					log.Printf("Error closing database connection: %v", err)
+				}
+			}()
+
+			// Setup mock expectations
+			tt.mockSetup(mock)
+
+			// Create repository with mock database
+			repo := NewArticleRepository(db, "english")
+
+			// Call Update method
+			_, err := repo.Update(context.Background(), 1, "test-article", tt.newSlugs, &title, nil, nil, tt.tags)
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Ensure all expectations were met
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_articleRepository_GetByPreviousSlug(t *testing.T) {
+	t.Parallel()
+
+	// Define test cases
+	tests := []struct {
+		name         string
+		mockSetup    func(mock sqlmock.Sqlmock)
+		expectedErr  error
+		expectedSlug string
+	}{
+		{
+			name: "Previous slug found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{
+					"id", "slug", "title", "description", "body", "author_id", "status", "publish_at",
+					"published_at", "created_at", "updated_at", "author_id", "username", "bio", "image",
+				}).AddRow(
+					1, "new-title", "New Title", "Test Description", "Test Body", 1, "published",
+					nil, time.Now(), time.Now(), time.Now(), 1, "testuser", nil, nil,
+				)
+
+				mock.ExpectQuery(`SELECT a.id, a.slug, .* FROM article_slug_history h JOIN articles a ON a.id = h.article_id JOIN users u ON a.author_id = u.id WHERE h.slug = \$1`).
+					WithArgs("old-title").
+					WillReturnRows(rows)
+
+				mock.ExpectQuery(`SELECT t.name FROM tags t`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"name"}))
+			},
+			expectedErr:  nil,
+			expectedSlug: "new-title",
+		},
+		{
+			name: "Previous slug not found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`FROM article_slug_history h`).
+					WithArgs("old-title").
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedErr: repository.ErrArticleNotFound,
+		},
+		{
+			name: "Database error",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`FROM article_slug_history h`).
+					WithArgs("old-title").
+					WillReturnError(errors.New("database error"))
+			},
+			expectedErr: repository.ErrInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock database for this test case
+			db, mock := setupTestDB(t)
+			defer func() {
+				if err := db.Close(); err != nil {
+					log.Printf("Error closing database connection: %v", err)
+				}
+			}()
+
+			// Setup mock expectations
+			tt.mockSetup(mock)
+
+			// Create repository with mock database
+			repo := NewArticleRepository(db, "english")
+
+			// Call GetByPreviousSlug method
+			article, err := repo.GetByPreviousSlug(context.Background(), "old-title")
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate slug if no error
+			if err == nil && article.Slug != tt.expectedSlug {
+				t.Errorf("Expected slug %q, got %q", tt.expectedSlug, article.Slug)
+			}
+
+			// Ensure all expectations were met
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_articleRepository_GetByID(t *testing.T) {
+	t.Parallel()
+
+	// Define test cases
+	tests := []struct {
+		name         string
+		mockSetup    func(mock sqlmock.Sqlmock)
+		expectedErr  error
+		expectedSlug string
+	}{
+		{
+			name: "Article found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{
+					"id", "slug", "title", "description", "body", "author_id", "status", "publish_at",
+					"published_at", "created_at", "updated_at", "author_id", "username", "bio", "image",
+				}).AddRow(
+					1, "test-article", "Test Article", "Test Description", "Test Body", 1, "published",
+					nil, time.Now(), time.Now(), time.Now(), 1, "testuser", nil, nil,
+				)
+
+				mock.ExpectQuery(`SELECT a.id, a.slug, .* FROM articles a JOIN users u ON a.author_id = u.id WHERE a.id = \$1$`).
+					WithArgs(int64(1)).
+					WillReturnRows(rows)
+
+				mock.ExpectQuery(`SELECT t.name FROM tags t`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"name"}))
+			},
+			expectedErr:  nil,
+			expectedSlug: "test-article",
+		},
+		{
+			name: "Article not found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`FROM articles a JOIN users u ON a.author_id = u.id WHERE a.id = \$1`).
+					WithArgs(int64(1)).
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedErr: repository.ErrArticleNotFound,
+		},
+		{
+			name: "Database error",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`FROM articles a JOIN users u ON a.author_id = u.id WHERE a.id = \$1`).
+					WithArgs(int64(1)).
+					WillReturnError(errors.New("database error"))
+			},
+			expectedErr: repository.ErrInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock database for this test case
+			db, mock := setupTestDB(t)
+			defer func() {
+				if err := db.Close(); err != nil {
+					log.Printf("Error closing database connection: %v", err)
+				}
+			}()
+
+			// Setup mock expectations
+			tt.mockSetup(mock)
+
+			// Create repository with mock database
+			repo := NewArticleRepository(db, "english")
+
+			// Call GetByID method
+			article, err := repo.GetByID(context.Background(), 1)
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate slug if no error
+			if err == nil && article.Slug != tt.expectedSlug {
+				t.Errorf("Expected slug %q, got %q", tt.expectedSlug, article.Slug)
+			}
+
+			// Ensure all expectations were met
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_articleRepository_Delete(t *testing.T) {
+	t.Parallel()
+
+	// Define test cases
+	tests := []struct {
+		name        string
+		mockSetup   func(mock sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name: "Delete moves the article to the trash",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`UPDATE articles SET deleted_at = \$2 WHERE id = \$1 AND deleted_at IS NULL RETURNING id, author_id, slug, status`).
+					WithArgs(int64(1), sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "author_id", "slug", "status"}).
+						AddRow(1, 2, "test-article", "published"))
+				mock.ExpectExec(`INSERT INTO outbox`).
+					WithArgs("article", int64(1), "article.deleted", `{"articleId":1,"authorId":2,"slug":"test-article","status":"published"}`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Article not found or already deleted",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`UPDATE articles SET deleted_at`).
+					WithArgs(int64(1), sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "author_id", "slug", "status"}))
+				mock.ExpectRollback()
+			},
+			expectedErr: repository.ErrArticleNotFound,
+		},
+		{
+			name: "Database error",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`UPDATE articles SET deleted_at`).
+					WithArgs(int64(1), sqlmock.AnyArg()).
+					WillReturnError(errors.New("database error"))
+				mock.ExpectRollback()
+			},
+			expectedErr: repository.ErrInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock database for this test case
+			db, mock := setupTestDB(t)
+			defer func() {
+				if err := db.Close(); err != nil {
+					log.Printf("Error closing database connection: %v", err)
+				}
+			}()
+
+			// Setup mock expectations
+			tt.mockSetup(mock)
+
+			// Create repository with mock database
+			repo := NewArticleRepository(db, "english")
+
+			// Call Delete method
+			err := repo.Delete(context.Background(), 1)
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Ensure all expectations were met
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_articleRepository_Restore(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	deletedAfter := now.Add(-720 * time.Hour)
+
+	// Define test cases
+	tests := []struct {
+		name        string
+		mockSetup   func(mock sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name: "Article restored",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{
+					"id", "slug", "title", "description", "body", "author_id", "status", "publish_at",
+					"published_at", "created_at", "updated_at", "author_id", "username", "bio", "image",
+				}).AddRow(1, "test-article", "Title", "Description", "Body", 1, "published", nil, now, now, now, 1, "author", nil, nil)
+
+				mock.ExpectBegin()
+				mock.ExpectQuery(`WITH restored_article AS \( UPDATE articles SET deleted_at = NULL WHERE slug = \$1 AND author_id = \$2 AND deleted_at > \$3 RETURNING \* \)`).
+					WithArgs("test-article", int64(1), deletedAfter).
+					WillReturnRows(rows)
+				mock.ExpectExec(`INSERT INTO outbox`).
+					WithArgs("article", int64(1), "article.restored", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+				mock.ExpectQuery(`SELECT t.name FROM tags t`).
+					WithArgs(int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("go"))
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Article not in the trash",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`WITH restored_article AS`).
+					WithArgs("test-article", int64(1), deletedAfter).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			expectedErr: repository.ErrArticleNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock database for this test case
+			db, mock := setupTestDB(t)
+			defer func() {
+				if err := db.Close(); err != nil {
+					log.Printf("Error closing database connection: %v", err)
+				}
+			}()
+
+			// Setup mock expectations
+			tt.mockSetup(mock)
+
+			// Create repository with mock database
+			repo := NewArticleRepository(db, "english")
+
+			// Call Restore method
+			article, err := repo.Restore(context.Background(), 1, "test-article", deletedAfter)
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate article if no error
+			if err == nil && (article.Slug != "test-article" || len(article.TagList) != 1) {
+				t.Errorf("Expected the restored article with its tags, got %+v", article)
+			}
+
+			// Ensure all expectations were met
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_articleRepository_PurgeDeleted(t *testing.T) {
+	t.Parallel()
+
+	deletedBefore := time.Now().Add(-720 * time.Hour)
+
+	// Define test cases
+	tests := []struct {
+		name          string
+		mockSetup     func(mock sqlmock.Sqlmock)
+		expectedErr   error
+		expectedCount int
+	}{
+		{
+			name: "Purge removes orphaned tags",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(`SELECT id FROM articles WHERE deleted_at <= \$1 ORDER BY deleted_at ASC LIMIT \$2 FOR UPDATE SKIP LOCKED`).
+					WithArgs(deletedBefore, 10).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+
+				mock.ExpectQuery(`DELETE FROM article_tags WHERE article_id = ANY\(\$1\) RETURNING tag_id`).
+					WithArgs(pq.Array([]int64{1, 2})).
+					WillReturnRows(sqlmock.NewRows([]string{"tag_id"}).AddRow(2).AddRow(1).AddRow(2))
+
+				mock.ExpectExec(`DELETE FROM articles WHERE id = ANY\(\$1\)`).
+					WithArgs(pq.Array([]int64{1, 2})).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+
+				mock.ExpectQuery(`SELECT id FROM tags WHERE id = ANY\(\$1\) ORDER BY name FOR UPDATE`).
+					WithArgs(pq.Array([]int64{1, 2})).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+				mock.ExpectExec(`DELETE FROM tags t WHERE t.id = ANY\(\$1\) AND NOT EXISTS`).
+					WithArgs(pq.Array([]int64{1, 2})).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+			expectedErr:   nil,
+			expectedCount: 2,
+		},
+		{
+			name: "Nothing to purge",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(`SELECT id FROM articles WHERE deleted_at <= \$1`).
+					WithArgs(deletedBefore, 10).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+				mock.ExpectRollback()
+			},
+			expectedErr:   nil,
+			expectedCount: 0,
+		},
+		{
+			name: "Database error",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(`SELECT id FROM articles WHERE deleted_at <= \$1`).
+					WithArgs(deletedBefore, 10).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+				mock.ExpectQuery(`DELETE FROM article_tags`).
+					WithArgs(pq.Array([]int64{1})).
+					WillReturnRows(sqlmock.NewRows([]string{"tag_id"}))
+
+				mock.ExpectExec(`DELETE FROM articles`).
+					WithArgs(pq.Array([]int64{1})).
+					WillReturnError(errors.New("database error"))
+
+				mock.ExpectRollback()
+			},
+			expectedErr: repository.ErrInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock database for this test case
+			db, mock := setupTestDB(t)
+			defer func() {
+				if err := db.Close(); err != nil {
+					log.Printf("Error closing database connection: %v", err)
+				}
+			}()
+
+			// Setup mock expectations
+			tt.mockSetup(mock)
+
+			// Create repository with mock database
+			repo := NewArticleRepository(db, "english")
+
+			// Call PurgeDeleted method
+			count, err := repo.PurgeDeleted(context.Background(), deletedBefore, 10)
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate count if no error
+			if err == nil && count != tt.expectedCount {
+				t.Errorf("Expected %d articles purged, got %d", tt.expectedCount, count)
+			}
+
+			// Ensure all expectations were met
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+// listedArticleColumns are the columns of an article selected with articleColumns and
+// articleListColumns
+var listedArticleColumns = []string{
+	"id", "slug", "title", "description", "body", "author_id", "status", "publish_at",
+	"published_at", "created_at", "updated_at", "author_id", "username", "bio", "image",
+	"tag_list", "favorites_count", "favorited", "following",
+}
+
+func Test_articleRepository_PublishDue(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// Define test cases
+	tests := []struct {
+		name          string
+		mockSetup     func(mock sqlmock.Sqlmock)
+		expectedErr   error
+		expectedSlugs []string
+	}{
+		{
+			name: "Due articles are published",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(listedArticleColumns).
+					AddRow(1, "first-article", "First", "Description", "Body", 1, "published", nil, now.Add(-time.Hour), now, now, 1, "testuser", nil, nil, "{}", 0, false, false).
+					AddRow(2, "second-article", "Second", "Description", "Body", 1, "published", nil, now.Add(-time.Minute), now, now, 1, "testuser", nil, nil, "{go}", 0, false, false)
+
+				mock.ExpectBegin()
+				mock.ExpectQuery(`WITH due AS \( SELECT id FROM articles WHERE status = 'scheduled' AND publish_at <= \$1 AND deleted_at IS NULL ORDER BY publish_at ASC LIMIT \$2 FOR UPDATE SKIP LOCKED \)`).
+					WithArgs(now, 10).
+					WillReturnRows(rows)
+
+				// Expect an event for each article published
+				mock.ExpectExec(`INSERT INTO outbox`).
+					WithArgs("article", int64(1), "article.status_changed", `{"articleId":1,"authorId":1,"slug":"first-article","status":"published"}`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(`INSERT INTO outbox`).
+					WithArgs("article", int64(2), "article.status_changed", `{"articleId":2,"authorId":1,"slug":"second-article","status":"published"}`).
+					WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectCommit()
+			},
+			expectedErr:   nil,
+			expectedSlugs: []string{"first-article", "second-article"},
+		},
+		{
+			name: "Nothing is due",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`WITH due AS`).
+					WithArgs(now, 10).
+					WillReturnRows(sqlmock.NewRows([]string{}))
+				mock.ExpectCommit()
+			},
+			expectedErr:   nil,
+			expectedSlugs: nil,
+		},
+		{
+			name: "Database error",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`WITH due AS`).
+					WithArgs(now, 10).
+					WillReturnError(errors.New("database error"))
+				mock.ExpectRollback()
+			},
+			expectedErr:   repository.ErrInternal,
+			expectedSlugs: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock database for this test case
+			db, mock := setupTestDB(t)
+			defer func() {
+				if err := db.Close(); err != nil {
+					log.Printf("Error closing database connection: %v", err)
+				}
+			}()
+
+			// Setup mock expectations
+			tt.mockSetup(mock)
+
+			// Create repository with mock database
+			repo := NewArticleRepository(db, "english")
+
+			// Call PublishDue method
+			articles, err := repo.PublishDue(context.Background(), now, 10)
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate published articles
+			var slugs []string
+			for _, article := range articles {
+				slugs = append(slugs, article.Slug)
+				if article.Status != repository.ArticleStatusPublished {
+					t.Errorf("Expected article %q to be published, got %q", article.Slug, article.Status)
+				}
+				if article.PublishAt != nil {
+					t.Errorf("Expected publishAt of %q to be cleared, got %v", article.Slug, article.PublishAt)
+				}
+			}
+			if len(slugs) != len(tt.expectedSlugs) {
+				t.Fatalf("Expected slugs %v, got %v", tt.expectedSlugs, slugs)
+			}
+			for i := range slugs {
+				if slugs[i] != tt.expectedSlugs[i] {
+					t.Errorf("Expected slugs %v, got %v", tt.expectedSlugs, slugs)
+				}
+			}
+
+			// Ensure all expectations were met
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_articleRepository_Favorite(t *testing.T) {
+	t.Parallel()
+
+	// Define test cases
+	tests := []struct {
+		name        string
+		mockSetup   func(mock sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name: "Favorite records an event",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`INSERT INTO favorites \(user_id, article_id\) VALUES \(\$1, \$2\) ON CONFLICT DO NOTHING`).
+					WithArgs(int64(2), int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`INSERT INTO outbox \(aggregate_type, aggregate_id, event_type, payload\) VALUES \(\$1, \$2, \$3, \$4\)`).
+					WithArgs("article", int64(1), "article.favorited", `{"articleId":1,"userId":2}`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Article already a favorite",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`INSERT INTO favorites`).
+					WithArgs(int64(2), int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Event not recorded",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`INSERT INTO favorites`).
+					WithArgs(int64(2), int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`INSERT INTO outbox`).
+					WillReturnError(errors.New("database error"))
+				mock.ExpectRollback()
+			},
+			expectedErr: repository.ErrInternal,
+		},
+		{
+			name: "Article not found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`INSERT INTO favorites`).
+					WithArgs(int64(2), int64(1)).
+					WillReturnError(&pq.Error{Code: "23503", Constraint: "favorites_article_id_fkey"})
+				mock.ExpectRollback()
+			},
+			expectedErr: repository.ErrArticleNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock database for this test case
+			db, mock := setupTestDB(t)
+			defer func() {
+				if err := db.Close(); err != nil {
+					log.Printf("Error closing database connection: %v", err)
+				}
+			}()
+
+			// Setup mock expectations
+			tt.mockSetup(mock)
+
+			// Create repository with mock database
+			repo := NewArticleRepository(db, "english")
+
+			// Call Favorite method
+			err := repo.Favorite(context.Background(), 2, 1)
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Ensure all expectations were met
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_articleRepository_ListArticles(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	query := "go generics"
+	tag := "go"
+	createdAfter := now.AddDate(0, -1, 0)
+	userID := int64(2)
+	cursor := &repository.Cursor{CreatedAt: now, ID: 5}
+
+	// Define test cases
+	tests := []struct {
+		name              string
+		filters           repository.ArticleFilters
+		currentUserID     *int64
+		mockSetup         func(mock sqlmock.Sqlmock)
+		expectedErr       error
+		expectedArticles  []*repository.Article
+		expectedHighlight *repository.ArticleHighlight
+		expectedCount     int
+		expectedHasMore   bool
+	}{
+		{
+			name:    "Newest articles first",
+			filters: repository.ArticleFilters{Limit: 20},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(listedArticleColumns).
+					AddRow(1, "test-article", "Test Article", "Description", "Body", 1, "published", nil, now, now, now, 1, "testuser", nil, nil, "{go,testing}", 3, false, false).
+					AddRow(2, "untagged-article", "Untagged Article", "Description", "Body", 1, "published", nil, now, now, now, 1, "testuser", nil, nil, "{}", 0, false, false)
+
+				mock.ExpectQuery(`SELECT .* fav.user_id = NULL\).* fol.follower_id = NULL\) FROM articles a JOIN users u ON a.author_id = u.id WHERE a.deleted_at IS NULL AND a.status = 'published' ORDER BY a.created_at DESC, a.id DESC LIMIT \$1`).
+					WithArgs(21).
+					WillReturnRows(rows)
+				mock.ExpectQuery(`SELECT COUNT\(DISTINCT a.id\) FROM articles a JOIN users u ON a.author_id = u.id WHERE a.deleted_at IS NULL AND a.status = 'published'`).
+					WithArgs().
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+			},
+			expectedErr: nil,
+			expectedArticles: []*repository.Article{
+				{Slug: "test-article", TagList: []string{"go", "testing"}, FavoritesCount: 3},
+				{Slug: "untagged-article"},
+			},
+			expectedCount: 2,
+		},
+		{
+			name:    "Page after a cursor",
+			filters: repository.ArticleFilters{Limit: 1, After: cursor},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(listedArticleColumns).
+					AddRow(4, "older-article", "Older Article", "Description", "Body", 1, "published", nil, now, now, now, 1, "testuser", nil, nil, "{}", 0, false, false).
+					AddRow(3, "oldest-article", "Oldest Article", "Description", "Body", 1, "published", nil, now, now, now, 1, "testuser", nil, nil, "{}", 0, false, false)
+
+				mock.ExpectQuery(`SELECT .* WHERE a.deleted_at IS NULL AND a.status = 'published' AND \(a.created_at, a.id\) < \(\$1, \$2\) ORDER BY a.created_at DESC, a.id DESC LIMIT \$3`).
+					WithArgs(now, int64(5), 2).
+					WillReturnRows(rows)
+				mock.ExpectQuery(`SELECT COUNT\(DISTINCT a.id\) FROM articles a JOIN users u ON a.author_id = u.id WHERE a.deleted_at IS NULL AND a.status = 'published'$`).
+					WithArgs().
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+			},
+			expectedErr: nil,
+			expectedArticles: []*repository.Article{
+				{Slug: "older-article"},
+			},
+			expectedCount:   5,
+			expectedHasMore: true,
+		},
+		{
+			name:    "Page before a cursor is read backwards",
+			filters: repository.ArticleFilters{Limit: 2, Before: cursor},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(listedArticleColumns).
+					AddRow(6, "newer-article", "Newer Article", "Description", "Body", 1, "published", nil, now, now, now, 1, "testuser", nil, nil, "{}", 0, false, false).
+					AddRow(7, "newest-article", "Newest Article", "Description", "Body", 1, "published", nil, now, now, now, 1, "testuser", nil, nil, "{}", 0, false, false)
+
+				mock.ExpectQuery(`SELECT .* WHERE a.deleted_at IS NULL AND a.status = 'published' AND \(a.created_at, a.id\) > \(\$1, \$2\) ORDER BY a.created_at ASC, a.id ASC LIMIT \$3`).
+					WithArgs(now, int64(5), 3).
+					WillReturnRows(rows)
+				mock.ExpectQuery(`SELECT COUNT\(DISTINCT a.id\)`).
+					WithArgs().
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+			},
+			expectedErr: nil,
+			expectedArticles: []*repository.Article{
+				{Slug: "newest-article"},
+				{Slug: "newer-article"},
+			},
+			expectedCount:   5,
+			expectedHasMore: false,
+		},
+		{
+			name:          "Signed-in viewer",
+			filters:       repository.ArticleFilters{Limit: 20},
+			currentUserID: &userID,
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(listedArticleColumns).
+					AddRow(1, "test-article", "Test Article", "Description", "Body", 1, "published", nil, now, now, now, 1, "testuser", nil, nil, "{go}", 1, true, true)
+
+				mock.ExpectQuery(`SELECT .* fav.user_id = \$1\).* fol.follower_id = \$1\) FROM articles a JOIN users u ON a.author_id = u.id WHERE a.deleted_at IS NULL AND \(a.status = 'published' OR a.author_id = \$1\) AND NOT EXISTS \(SELECT 1 FROM blocks b WHERE \(b.blocker_id = \$1 AND b.blocked_id = a.author_id\) OR \(b.blocker_id = a.author_id AND b.blocked_id = \$1\)\) AND NOT \(EXISTS \(SELECT 1 FROM user_mutes um WHERE um.muter_id = \$1 AND um.muted_id = a.author_id\) OR \(a.author_id <> \$1 AND EXISTS \(SELECT 1 FROM tag_mutes tm .* WHERE tm.user_id = \$1 AND mat.article_id = a.id\)\)\) ORDER BY a.created_at DESC, a.id DESC LIMIT \$2`).
+					WithArgs(userID, 21).
+					WillReturnRows(rows)
+				mock.ExpectQuery(`SELECT COUNT\(DISTINCT a.id\)`).
+					WithArgs(userID).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+			},
+			expectedErr: nil,
+			expectedArticles: []*repository.Article{
+				{Slug: "test-article", TagList: []string{"go"}, FavoritesCount: 1, Favorited: true, Author: &repository.User{Following: true}},
+			},
+			expectedCount: 1,
+		},
+		{
+			name:    "Search ranks and highlights matching articles",
+			filters: repository.ArticleFilters{Query: &query, Limit: 20},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(append(listedArticleColumns, "title_highlight", "description_highlight", "body_highlight")).
+					AddRow(
+						1, "go-generics", "Go Generics", "Type parameters", "Generics arrived in Go 1.18 <script>", 1, "published", nil, now, now, now, 1, "testuser", nil, nil, "{go}", 0, false, false,
+						"<mark>Go</mark> <mark>Generics</mark>", "Type parameters", "<mark>Generics</mark> arrived in <mark>Go</mark> 1.18 &lt;script&gt;",
+					)
+
+				mock.ExpectQuery(`SELECT .* ts_headline\(a.search_language, replace\(replace\(replace\(a.title, '&', '&amp;'\), '<', '&lt;'\), '>', '&gt;'\), websearch_to_tsquery\(\$1::regconfig, \$2\), .* FROM articles a JOIN users u ON a.author_id = u.id WHERE a.deleted_at IS NULL AND a.status = 'published' AND a.search_vector @@ websearch_to_tsquery\(\$1::regconfig, \$2\) ORDER BY ts_rank\(a.search_vector, websearch_to_tsquery\(\$1::regconfig, \$2\)\) DESC, a.created_at DESC, a.id DESC LIMIT \$3`).
+					WithArgs("english", query, 21).
+					WillReturnRows(rows)
+				mock.ExpectQuery(`SELECT COUNT\(DISTINCT a.id\) FROM articles a JOIN users u ON a.author_id = u.id WHERE a.deleted_at IS NULL AND a.status = 'published' AND a.search_vector @@ websearch_to_tsquery\(\$1::regconfig, \$2\)`).
+					WithArgs("english", query).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+			},
+			expectedErr: nil,
+			expectedArticles: []*repository.Article{
+				{Slug: "go-generics", TagList: []string{"go"}},
+			},
+			expectedHighlight: &repository.ArticleHighlight{
+				Title:       "<mark>Go</mark> <mark>Generics</mark>",
+				Description: "Type parameters",
+				Body:        "<mark>Generics</mark> arrived in <mark>Go</mark> 1.18 &lt;script&gt;",
+			},
+			expectedCount: 1,
+		},
+		{
+			name:    "Oldest first after a cursor",
+			filters: repository.ArticleFilters{Sort: repository.ArticleSortOldest, Limit: 20, After: cursor},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(listedArticleColumns).
+					AddRow(6, "newer-article", "Newer Article", "Description", "Body", 1, "published", nil, now, now, now, 1, "testuser", nil, nil, "{}", 0, false, false)
+
+				mock.ExpectQuery(`SELECT .* WHERE a.deleted_at IS NULL AND a.status = 'published' AND \(a.created_at, a.id\) > \(\$1, \$2\) ORDER BY a.created_at ASC, a.id ASC LIMIT \$3`).
+					WithArgs(now, int64(5), 21).
+					WillReturnRows(rows)
+				mock.ExpectQuery(`SELECT COUNT\(DISTINCT a.id\)`).
+					WithArgs().
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+			},
+			expectedErr: nil,
+			expectedArticles: []*repository.Article{
+				{Slug: "newer-article"},
+			},
+			expectedCount: 5,
+		},
+		{
+			name: "Most commented articles with a tag",
+			filters: repository.ArticleFilters{
+				Tags:   []string{tag},
+				Sort:   repository.ArticleSortMostCommented,
+				Limit:  20,
+				Offset: 20,
+			},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(listedArticleColumns).
+					AddRow(1, "test-article", "Test Article", "Description", "Body", 1, "published", nil, now, now, now, 1, "testuser", nil, nil, "{go}", 0, false, false)
+
+				mock.ExpectQuery(`SELECT .* WHERE a.deleted_at IS NULL AND a.status = 'published' AND EXISTS \(.* t.name = ANY\(\$1\)\) ORDER BY \(SELECT COUNT\(\*\) FROM comments cm WHERE cm.article_id = a.id AND cm.deleted_at IS NULL\) DESC, a.created_at DESC, a.id DESC LIMIT \$2 OFFSET \$3`).
+					WithArgs(pq.Array([]string{tag}), 21, 20).
+					WillReturnRows(rows)
+				mock.ExpectQuery(`SELECT COUNT\(DISTINCT a.id\) FROM articles a JOIN users u ON a.author_id = u.id WHERE a.deleted_at IS NULL AND a.status = 'published' AND EXISTS \(.* t.name = ANY\(\$1\)\)$`).
+					WithArgs(pq.Array([]string{tag})).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(21))
+			},
+			expectedErr: nil,
+			expectedArticles: []*repository.Article{
+				{Slug: "test-article", TagList: []string{"go"}},
+			},
+			expectedCount: 21,
+		},
+		{
+			name:    "Search sorted by recently updated",
+			filters: repository.ArticleFilters{Query: &query, Sort: repository.ArticleSortRecentlyUpdated, Limit: 20},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT .* WHERE a.deleted_at IS NULL AND a.status = 'published' AND a.search_vector @@ websearch_to_tsquery\(\$1::regconfig, \$2\) ORDER BY a.updated_at DESC, a.created_at DESC, a.id DESC LIMIT \$3`).
+					WithArgs("english", query, 21).
+					WillReturnRows(sqlmock.NewRows(append(listedArticleColumns, "title_highlight", "description_highlight", "body_highlight")))
+				mock.ExpectQuery(`SELECT COUNT\(DISTINCT a.id\)`).
+					WithArgs("english", query).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+			},
+			expectedErr:      nil,
+			expectedArticles: []*repository.Article{},
+			expectedCount:    0,
+		},
+		{
+			name: "Every tag, excluded tags, authors and a creation time range",
+			filters: repository.ArticleFilters{
+				Tags:           []string{"sql", "go", "go"},
+				TagMatch:       repository.ArticleTagMatchAll,
+				ExcludeTags:    []string{"draft"},
+				Authors:        []string{"testuser", "other"},
+				ExcludeAuthors: []string{"spammer"},
+				CreatedAfter:   &createdAfter,
+				CreatedBefore:  &now,
+				Limit:          20,
+			},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(listedArticleColumns).
+					AddRow(1, "test-article", "Test Article", "Description", "Body", 1, "published", nil, now, now, now, 1, "testuser", nil, nil, "{go,sql}", 0, false, false)
+				conditions := `WHERE a.deleted_at IS NULL AND a.status = 'published' ` +
+					`AND \(SELECT COUNT\(DISTINCT t.name\) .* t.name = ANY\(\$1\)\) = \$2 ` +
+					`AND NOT EXISTS \(.* t.name = ANY\(\$3\)\) ` +
+					`AND u.username = ANY\(\$4\) AND u.username <> ALL\(\$5\) ` +
+					`AND a.created_at > \$6 AND a.created_at < \$7`
+				filterArgs := []driver.Value{
+					pq.Array([]string{"go", "sql"}), 2,
+					pq.Array([]string{"draft"}),
+					pq.Array([]string{"testuser", "other"}),
+					pq.Array([]string{"spammer"}),
+					createdAfter, now,
+				}
+
+				mock.ExpectQuery(`SELECT .* ` + conditions + ` ORDER BY a.created_at DESC, a.id DESC LIMIT \$8`).
+					WithArgs(append(filterArgs, 21)...).
+					WillReturnRows(rows)
+				mock.ExpectQuery(`SELECT COUNT\(DISTINCT a.id\) FROM articles a JOIN users u ON a.author_id = u.id ` + conditions + `$`).
+					WithArgs(filterArgs...).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+			},
+			expectedErr: nil,
+			expectedArticles: []*repository.Article{
+				{Slug: "test-article", TagList: []string{"go", "sql"}},
+			},
+			expectedCount: 1,
+		},
+		{
+			name:    "Database error",
+			filters: repository.ArticleFilters{Query: &query, Limit: 20},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT .* FROM articles a`).
+					WithArgs("english", query, 21).
+					WillReturnError(errors.New("database error"))
+			},
+			expectedErr:      repository.ErrInternal,
+			expectedArticles: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock database for this test case
+			db, mock := setupTestDB(t)
+			defer func() {
+				if err := db.Close(); err != nil {
+					log.Printf("Error closing database connection: %v", err)
+				}
+			}()
+
+			// Setup mock expectations
+			tt.mockSetup(mock)
+
+			// Create repository with mock database
+			repo := NewArticleRepository(db, "english")
+
+			// Call ListArticles method
+			result, err := repo.ListArticles(context.Background(), tt.filters, tt.currentUserID)
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate listed articles if no error
+			if err == nil {
+				if len(result.Articles) != len(tt.expectedArticles) {
+					t.Fatalf("Expected %d articles, got %d", len(tt.expectedArticles), len(result.Articles))
+				}
+				for i, article := range result.Articles {
+					expected := tt.expectedArticles[i]
+					if article.Slug != expected.Slug {
+						t.Errorf("Expected slug %q at index %d, got %q", expected.Slug, i, article.Slug)
+					}
+					if !reflect.DeepEqual(article.TagList, expected.TagList) {
+						t.Errorf("Expected tags %v for %q, got %v", expected.TagList, article.Slug, article.TagList)
+					}
+					if article.FavoritesCount != expected.FavoritesCount || article.Favorited != expected.Favorited {
+						t.Errorf(
+							"Expected %d favorites (favorited: %v) for %q, got %d (favorited: %v)",
+							expected.FavoritesCount, expected.Favorited, article.Slug, article.FavoritesCount, article.Favorited,
+						)
+					}
+					if following := expected.Author != nil && expected.Author.Following; article.Author.Following != following {
+						t.Errorf("Expected following %v for %q, got %v", following, article.Slug, article.Author.Following)
+					}
+					if !reflect.DeepEqual(article.Highlight, tt.expectedHighlight) {
+						t.Errorf("Expected highlight %+v, got %+v", tt.expectedHighlight, article.Highlight)
+					}
+				}
+				if result.Count != tt.expectedCount {
+					t.Errorf("Expected count %d, got %d", tt.expectedCount, result.Count)
+				}
+				if result.HasMore != tt.expectedHasMore {
+					t.Errorf("Expected has more %v, got %v", tt.expectedHasMore, result.HasMore)
+				}
+			}
+
+			// Ensure all expectations were met
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_articleRepository_IsInFeed(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		inFeed        bool
+		err           error
+		expectedError error
+	}{
+		{
+			name:   "Article by a followed author",
+			inFeed: true,
+		},
+		{
+			name:   "Article outside the feed",
+			inFeed: false,
+		},
+		{
+			name:          "Database error",
+			err:           errors.New("connection refused"),
+			expectedError: repository.ErrInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock database
+			db, mock := setupTestDB(t)
+			defer func() {
+				if err := db.Close(); err != nil {
+					log.Printf("Error closing database connection: %v", err)
+				}
+			}()
+
+			// Setup mock expectations
+			query := mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM articles a JOIN users u ON a.author_id = u.id JOIN follows f ON u.id = f.following_id WHERE f.follower_id = \$1 AND a.status = 'published' AND a.deleted_at IS NULL AND NOT .* AND a.id = \$2\)`).
+				WithArgs(int64(1), int64(2))
+			if tt.err != nil {
+				query.WillReturnError(tt.err)
+			} else {
+				query.WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(tt.inFeed))
+			}
+
+			// Call IsInFeed method
+			inFeed, err := NewArticleRepository(db, "english").IsInFeed(context.Background(), 1, 2)
+
+			// Validate results
+			if !errors.Is(err, tt.expectedError) {
+				t.Fatalf("Expected error %v, got %v", tt.expectedError, err)
+			}
+			if inFeed != tt.inFeed {
+				t.Errorf("Expected in feed %v, got %v", tt.inFeed, inFeed)
+			}
+
+			// Ensure all expectations were met
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+// Benchmark_articleRepository_ListArticles lists pages of different sizes and reports the
+// number of queries per page, which must not grow with the page size
+func Benchmark_articleRepository_ListArticles(b *testing.B) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	userID := int64(2)
+
+	for _, pageSize := range []int{1, 20, 100} {
+		b.Run(fmt.Sprintf("%d articles", pageSize), func(b *testing.B) {
+			benchmarkArticleList(b, now, pageSize, func(mock sqlmock.Sqlmock, rows *sqlmock.Rows) {
+				mock.ExpectQuery(`SELECT .* FROM articles a`).
+					WithArgs(userID, pageSize+1).
+					WillReturnRows(rows)
+				mock.ExpectQuery(`SELECT COUNT\(DISTINCT a.id\)`).
+					WithArgs(userID).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(pageSize))
+			}, func(repo *articleRepository) error {
+				_, err := repo.ListArticles(context.Background(), repository.ArticleFilters{Limit: pageSize}, &userID)
+				return err
+			})
+		})
+	}
+}
+
+// Benchmark_articleRepository_GetArticlesFeed gets feed pages of different sizes and
+// reports the number of queries per page, which must not grow with the page size
+func Benchmark_articleRepository_GetArticlesFeed(b *testing.B) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	userID := int64(2)
+
+	for _, pageSize := range []int{1, 20, 100} {
+		b.Run(fmt.Sprintf("%d articles", pageSize), func(b *testing.B) {
+			benchmarkArticleList(b, now, pageSize, func(mock sqlmock.Sqlmock, rows *sqlmock.Rows) {
+				mock.ExpectQuery(`SELECT .* FROM articles a JOIN users u ON a.author_id = u.id JOIN follows f`).
+					WithArgs(userID, pageSize+1).
+					WillReturnRows(rows)
+				mock.ExpectQuery(`SELECT COUNT\(DISTINCT a.id\)`).
+					WithArgs(userID).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(pageSize))
+			}, func(repo *articleRepository) error {
+				_, err := repo.GetArticlesFeed(context.Background(), userID, repository.FeedFilters{Limit: pageSize})
+				return err
+			})
+		})
+	}
+}
+
+// benchmarkArticleList runs list b.N times against pages of pageSize articles, failing
+// if list runs any query that expect did not set up, and reports the queries per page
+func benchmarkArticleList(
+	b *testing.B,
+	now time.Time,
+	pageSize int,
+	expect func(mock sqlmock.Sqlmock, rows *sqlmock.Rows),
+	list func(repo *articleRepository) error,
+) {
+	// Count every query the repository runs
+	queries := 0
+	matcher := sqlmock.QueryMatcherFunc(func(expectedSQL, actualSQL string) error {
+		queries++
+		return sqlmock.QueryMatcherRegexp.Match(expectedSQL, actualSQL)
+	})
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(matcher))
+	if err != nil {
+		b.Fatalf("Error creating mock database: %v", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Printf("Error closing database connection: %v", err)
+		}
+	}()
+
+	repo := NewArticleRepository(db, "english")
+
+	b.ResetTimer()
+	for range b.N {
+		// Setup mock expectations
+		b.StopTimer()
+		rows := sqlmock.NewRows(listedArticleColumns)
+		for i := range pageSize {
+			rows.AddRow(i+1, fmt.Sprintf("article-%d", i+1), "Title", "Description", "Body", 1, "published", nil, now, now, now, 1, "testuser", nil, nil, "{go,testing}", 3, true, true)
+		}
+		expect(mock, rows)
+		b.StartTimer()
+
+		if err := list(repo); err != nil {
+			b.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	b.StopTimer()
+
+	// Ensure no query beyond the expected ones was run
+	if err := mock.ExpectationsWereMet(); err != nil {
+		b.Fatalf("Unfulfilled expectations: %v", err)
+	}
+
+	b.ReportMetric(float64(queries)/float64(b.N), "queries/op")
+	if queries != 2*b.N {
+		b.Fatalf("Expected 2 queries per page of %d articles, got %.1f", pageSize, float64(queries)/float64(b.N))
+	}
+}
@@ -0,0 +1,261 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+
+	"github.com/Nilesh2000/conduit/internal/repository"
+
+	"github.com/lib/pq"
+)
+
+// blockedBetween matches when either of the users %[1]s and %[2]s blocked the other
+const blockedBetween = "EXISTS (SELECT 1 FROM blocks b WHERE (b.blocker_id = %[1]s AND b.blocked_id = %[2]s) OR (b.blocker_id = %[2]s AND b.blocked_id = %[1]s))"
+
+// blockRepository implements the repository.blockRepository using PostgreSQL
+type blockRepository struct {
+	db *sql.DB
+}
+
+// NewBlockRepository creates a new block repository
+func NewBlockRepository(db *sql.DB) *blockRepository {
+	return &blockRepository{db: db}
+}
+
+// BlockUser blocks a user. The users stop following each other in both directions.
+func (r *blockRepository) BlockUser(
+	ctx context.Context,
+	blockerID int64,
+	blockedName string,
+) (*repository.Profile, error) {
+	// Begin a transaction
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, repository.ErrInternal
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			log.Printf("transaction rollback error: %v", err)
+		}
+	}()
+
+	// Blocking a user again keeps the block as it was, but still returns it
+	query := `
+		INSERT INTO blocks (blocker_id, blocked_id)
+		SELECT $2, id FROM users WHERE username = $1
+		ON CONFLICT (blocker_id, blocked_id) DO UPDATE SET created_at = blocks.created_at
+		RETURNING blocked_id
+	`
+
+	var blockedID int64
+	if err := tx.QueryRowContext(ctx, query, blockedName, blockerID).Scan(&blockedID); err != nil {
+		// blocked user does not exist
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrUserNotFound
+		}
+
+		// PostgreSQL specific error handling
+		if pqErr, ok := err.(*pq.Error); ok {
+			// Foreign key constraint violation
+			if pqErr.Code == "23503" && pqErr.Constraint == "blocks_blocker_id_fkey" {
+				return nil, repository.ErrUserNotFound
+			}
+			// Check for self-block constraint violation
+			if pqErr.Code == "23514" && pqErr.Constraint == "prevent_self_block" {
+				return nil, repository.ErrCannotBlockSelf
+			}
+		}
+
+		return nil, repository.ErrInternal
+	}
+
+	// Remove the follows between the users in both directions, recording an event for
+	// each of them
+	unfollowed, err := r.removeFollows(ctx, tx, blockerID, blockedID)
+	if err != nil {
+		return nil, err
+	}
+	for _, follow := range unfollowed {
+		if err := insertFollowEvent(ctx, tx, repository.OutboxEventUserUnfollowed, follow.FollowerID, follow.FollowingID); err != nil {
+			return nil, err
+		}
+	}
+
+	profile, err := r.blockedProfile(ctx, tx, blockedID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		return nil, repository.ErrInternal
+	}
+
+	return profile, nil
+}
+
+// removeFollows removes the follows between two users in both directions, returning
+// the ones that were removed
+func (r *blockRepository) removeFollows(
+	ctx context.Context,
+	tx *sql.Tx,
+	userID, otherID int64,
+) ([]repository.FollowEvent, error) {
+	query := `
+		DELETE FROM follows
+		WHERE (follower_id = $1 AND following_id = $2)
+			OR (follower_id = $2 AND following_id = $1)
+		RETURNING follower_id, following_id
+	`
+
+	rows, err := tx.QueryContext(ctx, query, userID, otherID)
+	if err != nil {
+		return nil, repository.ErrInternal
+	}
+	defer rows.Close()
+
+	var removed []repository.FollowEvent
+	for rows.Next() {
+		var follow repository.FollowEvent
+		if err := rows.Scan(&follow.FollowerID, &follow.FollowingID); err != nil {
+			return nil, repository.ErrInternal
+		}
+		removed = append(removed, follow)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, repository.ErrInternal
+	}
+
+	return removed, nil
+}
+
+// UnblockUser unblocks a user. Follows removed when the user was blocked are not
+// brought back.
+func (r *blockRepository) UnblockUser(
+	ctx context.Context,
+	blockerID int64,
+	blockedName string,
+) (*repository.Profile, error) {
+	query := `
+		WITH blocked_user AS (
+			SELECT id
+			FROM users
+			WHERE username = $1
+		),
+		unblock_attempt AS (
+			DELETE FROM blocks
+			WHERE blocker_id = $2 AND blocked_id = (SELECT id FROM blocked_user)
+		)
+		SELECT u.id, u.username, u.bio, u.image
+		FROM users u
+		JOIN blocked_user bu ON u.id = bu.id
+	`
+
+	profile, err := scanBlockedProfile(r.db.QueryRowContext(ctx, query, blockedName, blockerID), false)
+	if err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+// ListBlocked lists the users a user blocked, most recently blocked first
+func (r *blockRepository) ListBlocked(
+	ctx context.Context,
+	blockerID int64,
+) ([]repository.Profile, error) {
+	query := `
+		SELECT u.id, u.username, u.bio, u.image
+		FROM blocks b
+		JOIN users u ON u.id = b.blocked_id
+		WHERE b.blocker_id = $1
+		ORDER BY b.created_at DESC, u.id DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, blockerID)
+	if err != nil {
+		return nil, repository.ErrInternal
+	}
+	defer rows.Close()
+
+	profiles := []repository.Profile{}
+	for rows.Next() {
+		profile, err := scanBlockedProfile(rows, true)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, *profile)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, repository.ErrInternal
+	}
+
+	return profiles, nil
+}
+
+// IsBlocking checks if a user blocked another user
+func (r *blockRepository) IsBlocking(
+	ctx context.Context,
+	blockerID int64,
+	blockedID int64,
+) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM blocks
+			WHERE blocker_id = $1 AND blocked_id = $2
+		)
+	`
+
+	var blocking bool
+	err := r.db.QueryRowContext(ctx, query, blockerID, blockedID).Scan(&blocking)
+	if err != nil {
+		return false, repository.ErrInternal
+	}
+
+	return blocking, nil
+}
+
+// blockedProfile reads the profile of a user as seen by someone who blocked them or not
+func (r *blockRepository) blockedProfile(
+	ctx context.Context,
+	tx *sql.Tx,
+	userID int64,
+	blocking bool,
+) (*repository.Profile, error) {
+	query := `
+		SELECT id, username, bio, image
+		FROM users
+		WHERE id = $1
+	`
+
+	return scanBlockedProfile(tx.QueryRowContext(ctx, query, userID), blocking)
+}
+
+// scanBlockedProfile scans the ID, username, bio and image of a user. A blocked user is
+// never followed, so Following is always false.
+func scanBlockedProfile(row rowScanner, blocking bool) (*repository.Profile, error) {
+	var profile repository.Profile
+	var bio, image sql.NullString
+
+	if err := row.Scan(&profile.ID, &profile.Username, &bio, &image); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrUserNotFound
+		}
+		return nil, repository.ErrInternal
+	}
+
+	// Handle nullable values
+	if bio.Valid {
+		profile.Bio = bio.String
+	}
+	if image.Valid {
+		profile.Image = image.String
+	}
+	profile.Blocking = blocking
+
+	return &profile, nil
+}
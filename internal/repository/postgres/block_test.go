@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Nilesh2000/conduit/internal/repository"
+	"github.com/lib/pq"
+)
+
+func Test_blockRepository_BlockUser(t *testing.T) {
+	t.Parallel()
+
+	// Define test cases
+	tests := []struct {
+		name        string
+		mockSetup   func(mock sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name: "User blocked and unfollowed both ways",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`INSERT INTO blocks \(blocker_id, blocked_id\) SELECT \$2, id FROM users WHERE username = \$1 ON CONFLICT \(blocker_id, blocked_id\) DO UPDATE SET created_at = blocks.created_at RETURNING blocked_id`).
+					WithArgs("harasser", int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"blocked_id"}).AddRow(2))
+				mock.ExpectQuery(`DELETE FROM follows WHERE \(follower_id = \$1 AND following_id = \$2\) OR \(follower_id = \$2 AND following_id = \$1\) RETURNING follower_id, following_id`).
+					WithArgs(int64(1), int64(2)).
+					WillReturnRows(sqlmock.NewRows([]string{"follower_id", "following_id"}).AddRow(1, 2).AddRow(2, 1))
+				mock.ExpectExec(`INSERT INTO outbox`).
+					WithArgs("user", int64(2), "user.unfollowed", `{"followerId":1,"followingId":2}`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(`INSERT INTO outbox`).
+					WithArgs("user", int64(1), "user.unfollowed", `{"followerId":2,"followingId":1}`).
+					WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectQuery(`SELECT id, username, bio, image FROM users WHERE id = \$1`).
+					WithArgs(int64(2)).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "username", "bio", "image"}).AddRow(2, "harasser", nil, nil))
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "User blocked without follows between them",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`INSERT INTO blocks`).
+					WithArgs("harasser", int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"blocked_id"}).AddRow(2))
+				mock.ExpectQuery(`DELETE FROM follows`).
+					WithArgs(int64(1), int64(2)).
+					WillReturnRows(sqlmock.NewRows([]string{"follower_id", "following_id"}))
+				mock.ExpectQuery(`SELECT id, username, bio, image FROM users WHERE id = \$1`).
+					WithArgs(int64(2)).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "username", "bio", "image"}).AddRow(2, "harasser", nil, nil))
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Unfollow event not recorded",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`INSERT INTO blocks`).
+					WithArgs("harasser", int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"blocked_id"}).AddRow(2))
+				mock.ExpectQuery(`DELETE FROM follows`).
+					WithArgs(int64(1), int64(2)).
+					WillReturnRows(sqlmock.NewRows([]string{"follower_id", "following_id"}).AddRow(2, 1))
+				mock.ExpectExec(`INSERT INTO outbox`).
+					WithArgs("user", int64(1), "user.unfollowed", `{"followerId":2,"followingId":1}`).
+					WillReturnError(errors.New("database error"))
+				mock.ExpectRollback()
+			},
+			expectedErr: repository.ErrInternal,
+		},
+		{
+			name: "User not found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`INSERT INTO blocks`).
+					WithArgs("harasser", int64(1)).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			expectedErr: repository.ErrUserNotFound,
+		},
+		{
+			name: "Blocking yourself",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`INSERT INTO blocks`).
+					WithArgs("harasser", int64(1)).
+					WillReturnError(&pq.Error{Code: "23514", Constraint: "prevent_self_block"})
+				mock.ExpectRollback()
+			},
+			expectedErr: repository.ErrCannotBlockSelf,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock database for this test case
+			db, mock := setupTestDB(t)
+			defer func() {
+				if err := db.Close(); err != nil {
+					log.Printf("Error closing database connection: %v", err)
+				}
+			}()
+
+			// Setup mock expectations
+			tt.mockSetup(mock)
+
+			// Create repository with mock database
+			repo := NewBlockRepository(db)
+
+			// Call BlockUser method
+			profile, err := repo.BlockUser(context.Background(), 1, "harasser")
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate profile if no error
+			if err == nil && (profile.Username != "harasser" || !profile.Blocking || profile.Following) {
+				t.Errorf("Expected a blocked profile of harasser, got %+v", profile)
+			}
+
+			// Ensure all expectations were met
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
@@ -3,10 +3,13 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"log"
 	"time"
 
 	"github.com/Nilesh2000/conduit/internal/repository"
+
+	"github.com/lib/pq"
 )
 
 // commentRepository implements the CommentRepository interface
@@ -25,12 +28,14 @@ func (r *commentRepository) GetByID(
 	commentID int64,
 ) (*repository.Comment, error) {
 	query := `
-		SELECT id, body, article_id, user_id, created_at, updated_at
+		SELECT id, body, article_id, user_id, created_at, updated_at,
+			parent_id, depth, tombstoned_at IS NOT NULL
 		FROM comments
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 		`
 
 	var comment repository.Comment
+	var parentID sql.NullInt64
 
 	err := r.db.QueryRowContext(ctx, query, commentID).
 		Scan(
@@ -40,6 +45,9 @@ func (r *commentRepository) GetByID(
 			&comment.Author.ID,
 			&comment.CreatedAt,
 			&comment.UpdatedAt,
+			&parentID,
+			&comment.Depth,
+			&comment.Tombstoned,
 		)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -48,79 +56,196 @@ func (r *commentRepository) GetByID(
 		return nil, repository.ErrInternal
 	}
 
+	if parentID.Valid {
+		id := int(parentID.Int64)
+		comment.ParentID = &id
+	}
+
 	return &comment, nil
 }
 
-// GetByArticleID gets comments by article ID
+// commentKeyset pages through comments oldest first
+var commentKeyset = keyset{createdAt: "c.created_at", id: "c.id"}
+
+// newestCommentKeyset pages through comments newest first
+var newestCommentKeyset = keyset{createdAt: "c.created_at", id: "c.id", descending: true}
+
+// commentColumns are the columns of a comment, its author and its article read by
+// scanComment, for comments selected as c joined with their author u and article a. $2
+// is the current user, whom the author may be followed by.
+const commentColumns = `
+	c.id, c.body, c.created_at, c.updated_at,
+	c.parent_id, c.depth, c.tombstoned_at IS NOT NULL,
+	(SELECT COUNT(*) FROM comments r WHERE r.parent_id = c.id AND r.deleted_at IS NULL) AS reply_count,
+	EXISTS (SELECT 1 FROM comment_versions v WHERE v.comment_id = c.id) AS edited,
+	u.id AS author_id, u.username AS author_username, u.bio AS author_bio, u.image AS author_image,
+	a.id AS article_id, a.slug AS article_slug, a.title AS article_title,
+	a.description AS article_description, a.body AS article_body,
+	EXISTS (
+		SELECT 1 FROM follows f
+		WHERE f.follower_id = $2 AND f.following_id = u.id
+	) AS author_following
+`
+
+// scanComment scans a comment selected with commentColumns
+func scanComment(row rowScanner) (repository.Comment, error) {
+	return scanCommentWith(row)
+}
+
+// scanCommentWith scans a comment selected with commentColumns, followed by the extra
+// columns scanned into dest
+func scanCommentWith(row rowScanner, dest ...any) (repository.Comment, error) {
+	var comment repository.Comment
+	var parentID sql.NullInt64
+	var authorBio, authorImage sql.NullString
+
+	if err := row.Scan(append([]any{
+		&comment.ID, &comment.Body, &comment.CreatedAt, &comment.UpdatedAt,
+		&parentID, &comment.Depth, &comment.Tombstoned, &comment.ReplyCount, &comment.Edited,
+		&comment.Author.ID, &comment.Author.Username, &authorBio, &authorImage,
+		&comment.Article.ID, &comment.Article.Slug, &comment.Article.Title,
+		&comment.Article.Description, &comment.Article.Body,
+		&comment.Author.Following,
+	}, dest...)...); err != nil {
+		return repository.Comment{}, err
+	}
+
+	if parentID.Valid {
+		id := int(parentID.Int64)
+		comment.ParentID = &id
+	}
+	if authorBio.Valid {
+		comment.Author.Bio = authorBio.String
+	}
+	if authorImage.Valid {
+		comment.Author.Image = authorImage.String
+	}
+
+	return comment, nil
+}
+
+// queryComments queries comments selected with commentColumns
+func (r *commentRepository) queryComments(
+	ctx context.Context,
+	query string,
+	args ...any,
+) ([]repository.Comment, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, repository.ErrInternal
+	}
+	defer rows.Close()
+
+	var comments []repository.Comment
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, repository.ErrInternal
+		}
+		comments = append(comments, comment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, repository.ErrInternal
+	}
+
+	return comments, nil
+}
+
+// GetByArticleID gets a page of the top-level comments of an article in the order of
+// filters.Sort, along with every reply to them, oldest first
 func (r *commentRepository) GetByArticleID(
 	ctx context.Context,
 	articleID int64,
 	currentUserID *int64,
-) ([]repository.Comment, error) {
+	filters repository.CommentFilters,
+) (*repository.CommentListResult, error) {
 	query := `
-		SELECT
-			c.id, c.body, c.created_at, c.updated_at,
-			u.id AS author_id, u.username AS author_username, u.bio AS author_bio, u.image AS author_image,
-			a.id AS article_id, a.slug AS article_slug, a.title AS article_title,
-			a.description AS article_description, a.body AS article_body,
-			EXISTS (
-				SELECT 1 FROM follows f
-				WHERE f.follower_id = $2 AND f.following_id = u.id
-			) AS author_following
+		SELECT ` + commentColumns + `
 		FROM comments c
 		JOIN users u ON u.id = c.user_id
 		JOIN articles a ON a.id = c.article_id
-		WHERE c.article_id = $1
+		WHERE c.article_id = $1 AND c.parent_id IS NULL AND c.deleted_at IS NULL
 		`
+	args := []any{articleID, currentUserID}
 
-	var comments []repository.Comment
+	// Narrow the comments down to the page after or before the cursor
+	pages := commentKeyset
+	if filters.Sort == repository.CommentSortNewest {
+		pages = newestCommentKeyset
+	}
+	backwards := filters.Before != nil
+	if condition, cursorArgs := pages.condition(filters.After, filters.Before, len(args)+1); condition != "" {
+		query += " AND " + condition
+		args = append(args, cursorArgs...)
+	}
+	query += " ORDER BY " + pages.orderBy(backwards)
 
-	rows, err := r.db.QueryContext(ctx, query, articleID, currentUserID)
+	// Read one more comment to tell whether another page follows
+	if filters.Limit > 0 {
+		args = append(args, filters.Limit+1)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	comments, err := r.queryComments(ctx, query, args...)
 	if err != nil {
-		return nil, repository.ErrInternal
+		return nil, err
 	}
-	defer rows.Close()
+	comments, hasMore := trimPage(comments, filters.Limit, backwards)
 
-	for rows.Next() {
-		var comment repository.Comment
-		var authorBio, authorImage sql.NullString
-		comment.Author = repository.Profile{}
-		comment.Article = repository.Article{}
+	// The count covers every page and every reply, leaving out tombstones
+	countQuery := `
+		SELECT COUNT(*)
+		FROM comments
+		WHERE article_id = $1 AND tombstoned_at IS NULL AND deleted_at IS NULL
+		`
 
-		if err := rows.Scan(
-			&comment.ID, &comment.Body, &comment.CreatedAt, &comment.UpdatedAt,
-			&comment.Author.ID, &comment.Author.Username, &authorBio, &authorImage,
-			&comment.Article.ID, &comment.Article.Slug, &comment.Article.Title,
-			&comment.Article.Description, &comment.Article.Body,
-			&comment.Author.Following,
-		); err != nil {
-			if err == sql.ErrNoRows {
-				return nil, repository.ErrCommentNotFound
-			}
-			return nil, repository.ErrInternal
-		}
+	var count int
+	if err := r.db.QueryRowContext(ctx, countQuery, articleID).Scan(&count); err != nil {
+		return nil, repository.ErrInternal
+	}
 
-		if authorBio.Valid {
-			comment.Author.Bio = authorBio.String
-		}
-		if authorImage.Valid {
-			comment.Author.Image = authorImage.String
-		}
+	result := &repository.CommentListResult{
+		Comments: comments,
+		HasMore:  hasMore,
+		Count:    count,
+	}
+	if len(comments) == 0 {
+		return result, nil
+	}
 
-		comments = append(comments, comment)
+	// Read the threads below the comments of the page
+	rootIDs := make([]int64, len(comments))
+	for i, comment := range comments {
+		rootIDs[i] = int64(comment.ID)
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, repository.ErrInternal
+	repliesQuery := `
+		WITH RECURSIVE thread AS (
+			SELECT id FROM comments WHERE parent_id = ANY($1) AND deleted_at IS NULL
+			UNION ALL
+			SELECT r.id FROM comments r JOIN thread t ON r.parent_id = t.id WHERE r.deleted_at IS NULL
+		)
+		SELECT ` + commentColumns + `
+		FROM comments c
+		JOIN users u ON u.id = c.user_id
+		JOIN articles a ON a.id = c.article_id
+		WHERE c.id IN (SELECT id FROM thread)
+		ORDER BY ` + commentKeyset.orderBy(false)
+
+	result.Replies, err = r.queryComments(ctx, repliesQuery, pq.Array(rootIDs), currentUserID)
+	if err != nil {
+		return nil, err
 	}
 
-	return comments, nil
+	return result, nil
 }
 
-// Create creates a new comment
+// Create creates a new comment, replying to the comment parentID unless it is nil
 func (r *commentRepository) Create(
 	ctx context.Context,
 	userID, articleID int64,
+	parentID *int64,
 	body string,
 ) (*repository.Comment, error) {
 	// Begin a transaction
@@ -134,14 +259,15 @@ func (r *commentRepository) Create(
 		}
 	}()
 
+	// Replies are one level deeper than the comment they reply to
 	query := `
 		WITH inserted_comment AS (
-			INSERT INTO comments (body, article_id, user_id, created_at, updated_at)
-			VALUES ($1, $2, $3, $4, $5)
-			RETURNING id, body, article_id, user_id, created_at, updated_at
+			INSERT INTO comments (body, article_id, user_id, created_at, updated_at, parent_id, depth)
+			VALUES ($1, $2, $3, $4, $5, $6, COALESCE((SELECT depth + 1 FROM comments WHERE id = $6), 0))
+			RETURNING id, body, article_id, user_id, created_at, updated_at, parent_id, depth
 		)
 		SELECT
-			c.id, c.created_at, c.updated_at, c.body,
+			c.id, c.created_at, c.updated_at, c.body, c.parent_id, c.depth,
 			u.id AS author_id, u.username AS author_username, u.bio AS author_bio, u.image AS author_image,
 			a.id AS article_id, a.slug AS article_slug, a.title AS article_title,
 			a.description AS article_description, a.body AS article_body
@@ -152,17 +278,26 @@ func (r *commentRepository) Create(
 
 	now := time.Now()
 	var comment repository.Comment
+	var commentParentID sql.NullInt64
 	var authorBio, authorImage sql.NullString
 	comment.Author = repository.Profile{}
 	comment.Article = repository.Article{}
 
-	if err := tx.QueryRowContext(ctx, query, body, articleID, userID, now, now).
-		Scan(&comment.ID, &comment.CreatedAt, &comment.UpdatedAt, &comment.Body,
+	if err := tx.QueryRowContext(ctx, query, body, articleID, userID, now, now, parentID).
+		Scan(&comment.ID, &comment.CreatedAt, &comment.UpdatedAt, &comment.Body, &commentParentID, &comment.Depth,
 			&comment.Author.ID, &comment.Author.Username, &authorBio, &authorImage,
 			&comment.Article.ID, &comment.Article.Slug, &comment.Article.Title, &comment.Article.Description, &comment.Article.Body); err != nil {
-		return nil, err
+		// The comment replied to was deleted in the meantime
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" && pqErr.Constraint == "comments_parent_id_fkey" {
+			return nil, repository.ErrCommentNotFound
+		}
+		return nil, repository.ErrInternal
 	}
 
+	if commentParentID.Valid {
+		id := int(commentParentID.Int64)
+		comment.ParentID = &id
+	}
 	if authorBio.Valid {
 		comment.Author.Bio = authorBio.String
 	}
@@ -170,6 +305,11 @@ func (r *commentRepository) Create(
 		comment.Author.Image = authorImage.String
 	}
 
+	// Record the event along with the comment
+	if err := insertCommentEvent(ctx, tx, repository.OutboxEventCommentCreated, &comment); err != nil {
+		return nil, err
+	}
+
 	// Commit the transaction
 	if err := tx.Commit(); err != nil {
 		return nil, repository.ErrInternal
@@ -178,14 +318,148 @@ func (r *commentRepository) Create(
 	return &comment, nil
 }
 
-// Delete deletes a comment
-func (r *commentRepository) Delete(ctx context.Context, commentID int64) error {
-	query := `
-		DELETE FROM comments
+// Update replaces the body of a comment, keeping its previous body as a version. userID
+// is the current user, whom the author of the returned comment may be followed by.
+func (r *commentRepository) Update(
+	ctx context.Context,
+	userID, commentID int64,
+	body string,
+) (*repository.Comment, error) {
+	// Begin a transaction
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, repository.ErrInternal
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			log.Printf("transaction rollback error: %v", err)
+		}
+	}()
+
+	// Keep the previous body, which was written when the comment was last updated
+	versionQuery := `
+		INSERT INTO comment_versions (comment_id, body, written_at, replaced_at)
+		SELECT id, body, updated_at, $2
+		FROM comments
+		WHERE id = $1 AND tombstoned_at IS NULL AND deleted_at IS NULL
+		`
+
+	now := time.Now()
+	result, err := tx.ExecContext(ctx, versionQuery, commentID, now)
+	if err != nil {
+		return nil, repository.ErrInternal
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, repository.ErrInternal
+	}
+	if rowsAffected == 0 {
+		return nil, repository.ErrCommentNotFound
+	}
+
+	updateQuery := `
+		UPDATE comments
+		SET body = $2, updated_at = $3
 		WHERE id = $1
 		`
 
-	result, err := r.db.ExecContext(ctx, query, commentID)
+	if _, err := tx.ExecContext(ctx, updateQuery, commentID, body, now); err != nil {
+		return nil, repository.ErrInternal
+	}
+
+	// Read the updated comment back
+	query := `
+		SELECT ` + commentColumns + `
+		FROM comments c
+		JOIN users u ON u.id = c.user_id
+		JOIN articles a ON a.id = c.article_id
+		WHERE c.id = $1
+		`
+
+	comment, err := scanComment(tx.QueryRowContext(ctx, query, commentID, userID))
+	if err != nil {
+		return nil, repository.ErrInternal
+	}
+
+	// Record the event along with the update
+	if err := insertCommentEvent(ctx, tx, repository.OutboxEventCommentUpdated, &comment); err != nil {
+		return nil, err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		return nil, repository.ErrInternal
+	}
+
+	return &comment, nil
+}
+
+// ListVersions lists the earlier bodies of a comment, most recently replaced first
+func (r *commentRepository) ListVersions(
+	ctx context.Context,
+	commentID int64,
+) ([]repository.CommentVersion, error) {
+	query := `
+		SELECT id, comment_id, body, written_at, replaced_at
+		FROM comment_versions
+		WHERE comment_id = $1
+		ORDER BY replaced_at DESC, id DESC
+		`
+
+	rows, err := r.db.QueryContext(ctx, query, commentID)
+	if err != nil {
+		return nil, repository.ErrInternal
+	}
+	defer rows.Close()
+
+	versions := []repository.CommentVersion{}
+	for rows.Next() {
+		var version repository.CommentVersion
+		if err := rows.Scan(
+			&version.ID,
+			&version.CommentID,
+			&version.Body,
+			&version.WrittenAt,
+			&version.ReplacedAt,
+		); err != nil {
+			return nil, repository.ErrInternal
+		}
+		versions = append(versions, version)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, repository.ErrInternal
+	}
+
+	return versions, nil
+}
+
+// Delete moves a comment to the trash. A comment with replies is kept as a tombstone
+// with its body cleared instead, so that its replies stay in place, and tombstones are
+// moved to the trash along with the last of their replies.
+func (r *commentRepository) Delete(ctx context.Context, commentID int64) error {
+	// Begin a transaction
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return repository.ErrInternal
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			log.Printf("transaction rollback error: %v", err)
+		}
+	}()
+
+	// Turn the comment into a tombstone if it has replies
+	tombstoneQuery := `
+		UPDATE comments
+		SET body = '', tombstoned_at = $2
+		WHERE id = $1 AND tombstoned_at IS NULL AND deleted_at IS NULL
+			AND EXISTS (SELECT 1 FROM comments r WHERE r.parent_id = comments.id AND r.deleted_at IS NULL)
+		`
+
+	now := time.Now()
+	result, err := tx.ExecContext(ctx, tombstoneQuery, commentID, now)
 	if err != nil {
 		return repository.ErrInternal
 	}
@@ -195,9 +469,240 @@ func (r *commentRepository) Delete(ctx context.Context, commentID int64) error {
 		return repository.ErrInternal
 	}
 
-	if rowsAffected == 0 {
-		return repository.ErrCommentNotFound
+	if rowsAffected > 0 {
+		// Its earlier bodies are cleared along with its body
+		versionsQuery := `DELETE FROM comment_versions WHERE comment_id = $1`
+		if _, err := tx.ExecContext(ctx, versionsQuery, commentID); err != nil {
+			return repository.ErrInternal
+		}
+	} else {
+		// Otherwise move it to the trash, along with the tombstones above it that it was
+		// the last reply to
+		deleteQuery := `
+			UPDATE comments
+			SET deleted_at = $2
+			WHERE id = $1 AND deleted_at IS NULL
+			RETURNING parent_id
+			`
+		parentQuery := `
+			SELECT id
+			FROM comments c
+			WHERE id = $1 AND tombstoned_at IS NOT NULL AND deleted_at IS NULL
+				AND NOT EXISTS (SELECT 1 FROM comments r WHERE r.parent_id = c.id AND r.deleted_at IS NULL)
+			`
+
+		for id := commentID; ; {
+			var parentID sql.NullInt64
+			if err := tx.QueryRowContext(ctx, deleteQuery, id, now).Scan(&parentID); err != nil {
+				if err == sql.ErrNoRows {
+					return repository.ErrCommentNotFound
+				}
+				return repository.ErrInternal
+			}
+			if !parentID.Valid {
+				break
+			}
+
+			err := tx.QueryRowContext(ctx, parentQuery, parentID.Int64).Scan(&id)
+			if err == sql.ErrNoRows {
+				break
+			}
+			if err != nil {
+				return repository.ErrInternal
+			}
+		}
+	}
+
+	// Record the event along with the deletion. The tombstones moved to the trash with
+	// the comment are not events of their own.
+	comment := repository.Comment{ID: int(commentID)}
+	err = tx.QueryRowContext(ctx, `SELECT article_id, user_id FROM comments WHERE id = $1`, commentID).
+		Scan(&comment.Article.ID, &comment.Author.ID)
+	if err != nil {
+		return repository.ErrInternal
+	}
+	if err := insertCommentEvent(ctx, tx, repository.OutboxEventCommentDeleted, &comment); err != nil {
+		return err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		return repository.ErrInternal
 	}
 
 	return nil
 }
+
+// ListDeleted lists the comments of a user that were moved to the trash after
+// deletedAfter, most recently deleted first. Tombstones have nothing to restore, so
+// they are left out, as are the comments of articles in the trash.
+func (r *commentRepository) ListDeleted(
+	ctx context.Context,
+	userID int64,
+	deletedAfter time.Time,
+) ([]repository.Comment, error) {
+	query := `
+		SELECT ` + commentColumns + `, c.deleted_at
+		FROM comments c
+		JOIN users u ON u.id = c.user_id
+		JOIN articles a ON a.id = c.article_id
+		WHERE c.user_id = $1 AND c.deleted_at > $3 AND c.tombstoned_at IS NULL
+			AND a.deleted_at IS NULL
+		ORDER BY c.deleted_at DESC, c.id DESC
+		`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, userID, deletedAfter)
+	if err != nil {
+		return nil, repository.ErrInternal
+	}
+	defer rows.Close()
+
+	comments := []repository.Comment{}
+	for rows.Next() {
+		var deletedAt time.Time
+		comment, err := scanCommentWith(rows, &deletedAt)
+		if err != nil {
+			return nil, repository.ErrInternal
+		}
+		comment.DeletedAt = &deletedAt
+		comments = append(comments, comment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, repository.ErrInternal
+	}
+
+	return comments, nil
+}
+
+// Restore takes a comment of a user on an article that was moved to the trash after
+// deletedAfter back out of it, along with the tombstones above it that were moved to the
+// trash with it. A comment replying to a comment that is still in the trash cannot be
+// restored.
+func (r *commentRepository) Restore(
+	ctx context.Context,
+	userID, articleID, commentID int64,
+	deletedAfter time.Time,
+) (*repository.Comment, error) {
+	// Begin a transaction
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, repository.ErrInternal
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			log.Printf("transaction rollback error: %v", err)
+		}
+	}()
+
+	restoreQuery := `
+		UPDATE comments
+		SET deleted_at = NULL
+		WHERE id = $1 AND user_id = $2 AND article_id = $3 AND deleted_at > $4
+			AND tombstoned_at IS NULL
+		RETURNING parent_id
+		`
+	parentQuery := `
+		UPDATE comments
+		SET deleted_at = NULL
+		WHERE id = $1 AND tombstoned_at IS NOT NULL AND deleted_at IS NOT NULL
+		RETURNING parent_id
+		`
+	parentDeletedQuery := `
+		SELECT deleted_at IS NOT NULL
+		FROM comments
+		WHERE id = $1
+		`
+
+	var parentID sql.NullInt64
+	if err := tx.QueryRowContext(ctx, restoreQuery, commentID, userID, articleID, deletedAfter).
+		Scan(&parentID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.ErrCommentNotFound
+		}
+		return nil, repository.ErrInternal
+	}
+
+	// Bring back the tombstones above the comment until one that was not in the trash
+	for parentID.Valid {
+		id := parentID.Int64
+		err := tx.QueryRowContext(ctx, parentQuery, id).Scan(&parentID)
+		if err == nil {
+			continue
+		}
+		if err != sql.ErrNoRows {
+			return nil, repository.ErrInternal
+		}
+
+		var parentDeleted bool
+		if err := tx.QueryRowContext(ctx, parentDeletedQuery, id).Scan(&parentDeleted); err != nil {
+			return nil, repository.ErrInternal
+		}
+		if parentDeleted {
+			return nil, repository.ErrParentCommentDeleted
+		}
+		break
+	}
+
+	// Read the restored comment back
+	query := `
+		SELECT ` + commentColumns + `
+		FROM comments c
+		JOIN users u ON u.id = c.user_id
+		JOIN articles a ON a.id = c.article_id
+		WHERE c.id = $1
+		`
+
+	comment, err := scanComment(tx.QueryRowContext(ctx, query, commentID, userID))
+	if err != nil {
+		return nil, repository.ErrInternal
+	}
+
+	// Record the event along with the restoration
+	if err := insertCommentEvent(ctx, tx, repository.OutboxEventCommentRestored, &comment); err != nil {
+		return nil, err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		return nil, repository.ErrInternal
+	}
+
+	return &comment, nil
+}
+
+// PurgeDeleted deletes up to limit comments that were moved to the trash before
+// deletedBefore for good and returns how many were deleted. Replies are deleted before
+// the tombstones they were moved to the trash with, and rows locked by another worker
+// are skipped, so several replicas can purge at the same time.
+func (r *commentRepository) PurgeDeleted(
+	ctx context.Context,
+	deletedBefore time.Time,
+	limit int,
+) (int, error) {
+	query := `
+		WITH expired AS (
+			SELECT id
+			FROM comments
+			WHERE deleted_at <= $1
+			ORDER BY deleted_at ASC, depth DESC
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		DELETE FROM comments c
+		USING expired
+		WHERE c.id = expired.id
+		`
+
+	result, err := r.db.ExecContext(ctx, query, deletedBefore, limit)
+	if err != nil {
+		return 0, repository.ErrInternal
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, repository.ErrInternal
+	}
+
+	return int(rowsAffected), nil
+}
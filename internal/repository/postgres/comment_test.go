@@ -1 +1,584 @@
 package postgres
+
+import (
+	"context"
+	"errors"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Nilesh2000/conduit/internal/repository"
+	"github.com/lib/pq"
+)
+
+// commentColumnNames are the columns selected with commentColumns
+var commentColumnNames = []string{
+	"id", "body", "created_at", "updated_at",
+	"parent_id", "depth", "tombstoned", "reply_count", "edited",
+	"author_id", "author_username", "author_bio", "author_image",
+	"article_id", "article_slug", "article_title", "article_description", "article_body",
+	"author_following",
+}
+
+func Test_commentRepository_GetByArticleID(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// Define test cases
+	tests := []struct {
+		name              string
+		filters           repository.CommentFilters
+		mockSetup         func(mock sqlmock.Sqlmock)
+		expectedErr       error
+		expectedComments  []int
+		expectedReplies   []int
+		expectedHasMore   bool
+		expectedCount     int
+		expectedTombstone int
+	}{
+		{
+			name:    "Top-level comments with their threads",
+			filters: repository.CommentFilters{Limit: 2},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				comments := sqlmock.NewRows(commentColumnNames).
+					AddRow(1, "First", now, now, nil, 0, false, 1, false, 1, "author", nil, nil, 1, "test-article", "Title", "Description", "Body", false).
+					AddRow(4, "Second", now, now, nil, 0, false, 0, false, 1, "author", nil, nil, 1, "test-article", "Title", "Description", "Body", false).
+					AddRow(5, "Third", now, now, nil, 0, false, 0, false, 1, "author", nil, nil, 1, "test-article", "Title", "Description", "Body", false)
+				replies := sqlmock.NewRows(commentColumnNames).
+					AddRow(2, "", now, now, 1, 1, true, 1, false, 1, "author", nil, nil, 1, "test-article", "Title", "Description", "Body", false).
+					AddRow(3, "Reply", now, now, 2, 2, false, 0, false, 1, "author", nil, nil, 1, "test-article", "Title", "Description", "Body", false)
+
+				mock.ExpectQuery(`SELECT .* FROM comments c .* WHERE c.article_id = \$1 AND c.parent_id IS NULL AND c.deleted_at IS NULL ORDER BY c.created_at ASC, c.id ASC LIMIT \$3`).
+					WithArgs(int64(1), nil, 3).
+					WillReturnRows(comments)
+				mock.ExpectQuery(`SELECT COUNT\(\*\) FROM comments WHERE article_id = \$1 AND tombstoned_at IS NULL`).
+					WithArgs(int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+				mock.ExpectQuery(`WITH RECURSIVE thread AS .* parent_id = ANY\(\$1\) .* WHERE c.id IN \(SELECT id FROM thread\) ORDER BY c.created_at ASC, c.id ASC`).
+					WithArgs(pq.Array([]int64{1, 4}), nil).
+					WillReturnRows(replies)
+			},
+			expectedErr:       nil,
+			expectedComments:  []int{1, 4},
+			expectedReplies:   []int{2, 3},
+			expectedHasMore:   true,
+			expectedCount:     4,
+			expectedTombstone: 2,
+		},
+		{
+			name:    "Newest first after a cursor",
+			filters: repository.CommentFilters{Limit: 2, Sort: repository.CommentSortNewest, After: &repository.Cursor{CreatedAt: now, ID: 5}},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				comments := sqlmock.NewRows(commentColumnNames).
+					AddRow(4, "Second", now, now, nil, 0, false, 0, false, 2, "followed", nil, nil, 1, "test-article", "Title", "Description", "Body", true)
+
+				mock.ExpectQuery(`SELECT .* FROM comments c .* WHERE c.article_id = \$1 AND c.parent_id IS NULL AND c.deleted_at IS NULL AND \(c.created_at, c.id\) < \(\$3, \$4\) ORDER BY c.created_at DESC, c.id DESC LIMIT \$5`).
+					WithArgs(int64(1), nil, now, int64(5), 3).
+					WillReturnRows(comments)
+				mock.ExpectQuery(`SELECT COUNT\(\*\) FROM comments`).
+					WithArgs(int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+				mock.ExpectQuery(`WITH RECURSIVE thread AS`).
+					WithArgs(pq.Array([]int64{4}), nil).
+					WillReturnRows(sqlmock.NewRows(commentColumnNames))
+			},
+			expectedErr:      nil,
+			expectedComments: []int{4},
+			expectedReplies:  []int{},
+			expectedHasMore:  false,
+			expectedCount:    3,
+		},
+		{
+			name:    "No comments",
+			filters: repository.CommentFilters{},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT .* FROM comments c`).
+					WithArgs(int64(1), nil).
+					WillReturnRows(sqlmock.NewRows(commentColumnNames))
+				mock.ExpectQuery(`SELECT COUNT\(\*\) FROM comments`).
+					WithArgs(int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+			},
+			expectedErr:      nil,
+			expectedComments: []int{},
+			expectedReplies:  []int{},
+		},
+		{
+			name:    "Database error",
+			filters: repository.CommentFilters{},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT .* FROM comments c`).
+					WithArgs(int64(1), nil).
+					WillReturnError(errors.New("database error"))
+			},
+			expectedErr: repository.ErrInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock database for this test case
+			db, mock := setupTestDB(t)
+			defer func() {
+				if err := db.Close(); err != nil {
+					log.Printf("Error closing database connection: %v", err)
+				}
+			}()
+
+			// Setup mock expectations
+			tt.mockSetup(mock)
+
+			// Create repository with mock database
+			repo := NewCommentRepository(db)
+
+			// Call GetByArticleID method
+			result, err := repo.GetByArticleID(context.Background(), 1, nil, tt.filters)
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate comments and replies if no error
+			if err == nil {
+				for _, list := range []struct {
+					comments []repository.Comment
+					expected []int
+				}{
+					{result.Comments, tt.expectedComments},
+					{result.Replies, tt.expectedReplies},
+				} {
+					if len(list.comments) != len(list.expected) {
+						t.Fatalf("Expected comments %v, got %+v", list.expected, list.comments)
+					}
+					for i, comment := range list.comments {
+						if comment.ID != list.expected[i] {
+							t.Errorf("Expected comment %d at index %d, got %d", list.expected[i], i, comment.ID)
+						}
+						if comment.Tombstoned != (comment.ID == tt.expectedTombstone) {
+							t.Errorf("Unexpected tombstone state %v for comment %d", comment.Tombstoned, comment.ID)
+						}
+					}
+				}
+				if result.HasMore != tt.expectedHasMore {
+					t.Errorf("Expected has more %v, got %v", tt.expectedHasMore, result.HasMore)
+				}
+				if result.Count != tt.expectedCount {
+					t.Errorf("Expected count %d, got %d", tt.expectedCount, result.Count)
+				}
+			}
+
+			// Ensure all expectations were met
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_commentRepository_Delete(t *testing.T) {
+	t.Parallel()
+
+	// Define test cases
+	tests := []struct {
+		name        string
+		mockSetup   func(mock sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name: "Comment with replies becomes a tombstone",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE comments SET body = '', tombstoned_at = \$2 WHERE id = \$1 AND tombstoned_at IS NULL AND deleted_at IS NULL AND EXISTS`).
+					WithArgs(int64(3), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`DELETE FROM comment_versions WHERE comment_id = \$1`).
+					WithArgs(int64(3)).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectQuery(`SELECT article_id, user_id FROM comments WHERE id = \$1`).
+					WithArgs(int64(3)).
+					WillReturnRows(sqlmock.NewRows([]string{"article_id", "user_id"}).AddRow(7, 2))
+				mock.ExpectExec(`INSERT INTO outbox`).
+					WithArgs("comment", int64(3), "comment.deleted", `{"commentId":3,"articleId":7,"authorId":2}`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Last reply to a tombstone moves it to the trash too",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE comments SET body = ''`).
+					WithArgs(int64(3), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(`UPDATE comments SET deleted_at = \$2 WHERE id = \$1 AND deleted_at IS NULL RETURNING parent_id`).
+					WithArgs(int64(3), sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"parent_id"}).AddRow(2))
+				mock.ExpectQuery(`SELECT id FROM comments c WHERE id = \$1 AND tombstoned_at IS NOT NULL AND deleted_at IS NULL AND NOT EXISTS`).
+					WithArgs(int64(2)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				mock.ExpectQuery(`UPDATE comments SET deleted_at = \$2 WHERE id = \$1 AND deleted_at IS NULL RETURNING parent_id`).
+					WithArgs(int64(2), sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"parent_id"}).AddRow(1))
+				mock.ExpectQuery(`SELECT id FROM comments c WHERE id = \$1 AND tombstoned_at IS NOT NULL`).
+					WithArgs(int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+				// Only the comment deleted is an event, not the tombstone trashed with it
+				mock.ExpectQuery(`SELECT article_id, user_id FROM comments WHERE id = \$1`).
+					WithArgs(int64(3)).
+					WillReturnRows(sqlmock.NewRows([]string{"article_id", "user_id"}).AddRow(7, 2))
+				mock.ExpectExec(`INSERT INTO outbox`).
+					WithArgs("comment", int64(3), "comment.deleted", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Comment not found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE comments SET body = ''`).
+					WithArgs(int64(3), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(`UPDATE comments SET deleted_at = \$2 WHERE id = \$1 AND deleted_at IS NULL RETURNING parent_id`).
+					WithArgs(int64(3), sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"parent_id"}))
+				mock.ExpectRollback()
+			},
+			expectedErr: repository.ErrCommentNotFound,
+		},
+		{
+			name: "Database error",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE comments SET body = ''`).
+					WithArgs(int64(3), sqlmock.AnyArg()).
+					WillReturnError(errors.New("database error"))
+				mock.ExpectRollback()
+			},
+			expectedErr: repository.ErrInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock database for this test case
+			db, mock := setupTestDB(t)
+			defer func() {
+				if err := db.Close(); err != nil {
+					log.Printf("Error closing database connection: %v", err)
+				}
+			}()
+
+			// Setup mock expectations
+			tt.mockSetup(mock)
+
+			// Create repository with mock database
+			repo := NewCommentRepository(db)
+
+			// Call Delete method
+			err := repo.Delete(context.Background(), 3)
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Ensure all expectations were met
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_commentRepository_Update(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// Define test cases
+	tests := []struct {
+		name        string
+		mockSetup   func(mock sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name: "Comment updated",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`INSERT INTO comment_versions \(comment_id, body, written_at, replaced_at\) SELECT id, body, updated_at, \$2 FROM comments WHERE id = \$1 AND tombstoned_at IS NULL`).
+					WithArgs(int64(3), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(`UPDATE comments SET body = \$2, updated_at = \$3 WHERE id = \$1`).
+					WithArgs(int64(3), "Edited", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(`SELECT .* FROM comments c .* WHERE c.id = \$1`).
+					WithArgs(int64(3), int64(1)).
+					WillReturnRows(sqlmock.NewRows(commentColumnNames).
+						AddRow(3, "Edited", now, now, nil, 0, false, 0, true, 1, "author", nil, nil, 1, "test-article", "Title", "Description", "Body", false))
+				mock.ExpectExec(`INSERT INTO outbox`).
+					WithArgs("comment", int64(3), "comment.updated", `{"commentId":3,"articleId":1,"authorId":1}`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Comment not found or deleted",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`INSERT INTO comment_versions`).
+					WithArgs(int64(3), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			expectedErr: repository.ErrCommentNotFound,
+		},
+		{
+			name: "Database error",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`INSERT INTO comment_versions`).
+					WithArgs(int64(3), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(`UPDATE comments SET body = \$2`).
+					WithArgs(int64(3), "Edited", sqlmock.AnyArg()).
+					WillReturnError(errors.New("database error"))
+				mock.ExpectRollback()
+			},
+			expectedErr: repository.ErrInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock database for this test case
+			db, mock := setupTestDB(t)
+			defer func() {
+				if err := db.Close(); err != nil {
+					log.Printf("Error closing database connection: %v", err)
+				}
+			}()
+
+			// Setup mock expectations
+			tt.mockSetup(mock)
+
+			// Create repository with mock database
+			repo := NewCommentRepository(db)
+
+			// Call Update method
+			comment, err := repo.Update(context.Background(), 1, 3, "Edited")
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate comment if no error
+			if err == nil && (comment.Body != "Edited" || !comment.Edited) {
+				t.Errorf("Expected an edited comment with the new body, got %+v", comment)
+			}
+
+			// Ensure all expectations were met
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_commentRepository_ListVersions(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// Setup mock database
+	db, mock := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Printf("Error closing database connection: %v", err)
+		}
+	}()
+
+	// Setup mock expectations
+	rows := sqlmock.NewRows([]string{"id", "comment_id", "body", "written_at", "replaced_at"}).
+		AddRow(2, 3, "Second", now.Add(time.Minute), now.Add(2*time.Minute)).
+		AddRow(1, 3, "First", now, now.Add(time.Minute))
+	mock.ExpectQuery(`SELECT id, comment_id, body, written_at, replaced_at FROM comment_versions WHERE comment_id = \$1 ORDER BY replaced_at DESC, id DESC`).
+		WithArgs(int64(3)).
+		WillReturnRows(rows)
+
+	// Call ListVersions method
+	versions, err := NewCommentRepository(db).ListVersions(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Validate versions
+	if len(versions) != 2 || versions[0].Body != "Second" || versions[1].Body != "First" {
+		t.Errorf("Expected versions Second and First, got %+v", versions)
+	}
+
+	// Ensure all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func Test_commentRepository_Restore(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// Define test cases
+	tests := []struct {
+		name        string
+		mockSetup   func(mock sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name: "Reply restored along with its tombstone",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`UPDATE comments SET deleted_at = NULL WHERE id = \$1 AND user_id = \$2 AND article_id = \$3 AND deleted_at > \$4 AND tombstoned_at IS NULL RETURNING parent_id`).
+					WithArgs(int64(3), int64(1), int64(1), now).
+					WillReturnRows(sqlmock.NewRows([]string{"parent_id"}).AddRow(2))
+				mock.ExpectQuery(`UPDATE comments SET deleted_at = NULL WHERE id = \$1 AND tombstoned_at IS NOT NULL AND deleted_at IS NOT NULL RETURNING parent_id`).
+					WithArgs(int64(2)).
+					WillReturnRows(sqlmock.NewRows([]string{"parent_id"}).AddRow(1))
+				mock.ExpectQuery(`UPDATE comments SET deleted_at = NULL WHERE id = \$1 AND tombstoned_at IS NOT NULL`).
+					WithArgs(int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"parent_id"}))
+				mock.ExpectQuery(`SELECT deleted_at IS NOT NULL FROM comments WHERE id = \$1`).
+					WithArgs(int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"deleted"}).AddRow(false))
+				mock.ExpectQuery(`SELECT .* FROM comments c .* WHERE c.id = \$1`).
+					WithArgs(int64(3), int64(1)).
+					WillReturnRows(sqlmock.NewRows(commentColumnNames).
+						AddRow(3, "Reply", now, now, 2, 2, false, 0, false, 1, "author", nil, nil, 1, "test-article", "Title", "Description", "Body", false))
+				mock.ExpectExec(`INSERT INTO outbox`).
+					WithArgs("comment", int64(3), "comment.restored", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Comment not in the trash",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`UPDATE comments SET deleted_at = NULL`).
+					WithArgs(int64(3), int64(1), int64(1), now).
+					WillReturnRows(sqlmock.NewRows([]string{"parent_id"}))
+				mock.ExpectRollback()
+			},
+			expectedErr: repository.ErrCommentNotFound,
+		},
+		{
+			name: "Parent comment still in the trash",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`UPDATE comments SET deleted_at = NULL`).
+					WithArgs(int64(3), int64(1), int64(1), now).
+					WillReturnRows(sqlmock.NewRows([]string{"parent_id"}).AddRow(2))
+				mock.ExpectQuery(`UPDATE comments SET deleted_at = NULL WHERE id = \$1 AND tombstoned_at IS NOT NULL`).
+					WithArgs(int64(2)).
+					WillReturnRows(sqlmock.NewRows([]string{"parent_id"}))
+				mock.ExpectQuery(`SELECT deleted_at IS NOT NULL FROM comments WHERE id = \$1`).
+					WithArgs(int64(2)).
+					WillReturnRows(sqlmock.NewRows([]string{"deleted"}).AddRow(true))
+				mock.ExpectRollback()
+			},
+			expectedErr: repository.ErrParentCommentDeleted,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock database for this test case
+			db, mock := setupTestDB(t)
+			defer func() {
+				if err := db.Close(); err != nil {
+					log.Printf("Error closing database connection: %v", err)
+				}
+			}()
+
+			// Setup mock expectations
+			tt.mockSetup(mock)
+
+			// Create repository with mock database
+			repo := NewCommentRepository(db)
+
+			// Call Restore method
+			comment, err := repo.Restore(context.Background(), 1, 1, 3, now)
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate comment if no error
+			if err == nil && comment.ID != 3 {
+				t.Errorf("Expected comment 3, got %+v", comment)
+			}
+
+			// Ensure all expectations were met
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_commentRepository_PurgeDeleted(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// Setup mock database
+	db, mock := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Printf("Error closing database connection: %v", err)
+		}
+	}()
+
+	// Setup mock expectations
+	mock.ExpectExec(`WITH expired AS \( SELECT id FROM comments WHERE deleted_at <= \$1 ORDER BY deleted_at ASC, depth DESC LIMIT \$2 FOR UPDATE SKIP LOCKED \) DELETE FROM comments c USING expired WHERE c.id = expired.id`).
+		WithArgs(now, 10).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	// Call PurgeDeleted method
+	purged, err := NewCommentRepository(db).PurgeDeleted(context.Background(), now, 10)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Validate purged count
+	if purged != 3 {
+		t.Errorf("Expected 3 purged comments, got %d", purged)
+	}
+
+	// Ensure all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,225 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+
+	"github.com/Nilesh2000/conduit/internal/repository"
+
+	"github.com/lib/pq"
+)
+
+// mutedFor matches when the user %[1]s muted the author of the article aliased %[2]s, or
+// a tag of the article unless they wrote it themselves
+const mutedFor = "(EXISTS (SELECT 1 FROM user_mutes um WHERE um.muter_id = %[1]s AND um.muted_id = %[2]s.author_id) OR (%[2]s.author_id <> %[1]s AND EXISTS (SELECT 1 FROM tag_mutes tm JOIN tags mt ON mt.name = tm.tag JOIN article_tags mat ON mat.tag_id = mt.id WHERE tm.user_id = %[1]s AND mat.article_id = %[2]s.id)))"
+
+// muteRepository implements the repository.muteRepository using PostgreSQL
+type muteRepository struct {
+	db *sql.DB
+}
+
+// NewMuteRepository creates a new mute repository
+func NewMuteRepository(db *sql.DB) *muteRepository {
+	return &muteRepository{db: db}
+}
+
+// MuteUser mutes a user. Unlike blocking, the muted user is not told and follows are
+// kept.
+func (r *muteRepository) MuteUser(
+	ctx context.Context,
+	muterID int64,
+	mutedName string,
+) (*repository.Profile, error) {
+	query := `
+		WITH muted_user AS (
+			SELECT id
+			FROM users
+			WHERE username = $1
+		),
+		mute_attempt AS (
+			INSERT INTO user_mutes (muter_id, muted_id)
+			SELECT $2, id FROM muted_user
+			ON CONFLICT (muter_id, muted_id) DO NOTHING
+		)
+		SELECT u.id, u.username, u.bio, u.image,
+			EXISTS (SELECT 1 FROM follows WHERE follower_id = $2 AND following_id = u.id)
+		FROM users u
+		JOIN muted_user mu ON u.id = mu.id
+	`
+
+	return scanMutedProfile(r.db.QueryRowContext(ctx, query, mutedName, muterID), true)
+}
+
+// UnmuteUser unmutes a user
+func (r *muteRepository) UnmuteUser(
+	ctx context.Context,
+	muterID int64,
+	mutedName string,
+) (*repository.Profile, error) {
+	query := `
+		WITH muted_user AS (
+			SELECT id
+			FROM users
+			WHERE username = $1
+		),
+		unmute_attempt AS (
+			DELETE FROM user_mutes
+			WHERE muter_id = $2 AND muted_id = (SELECT id FROM muted_user)
+		)
+		SELECT u.id, u.username, u.bio, u.image,
+			EXISTS (SELECT 1 FROM follows WHERE follower_id = $2 AND following_id = u.id)
+		FROM users u
+		JOIN muted_user mu ON u.id = mu.id
+	`
+
+	return scanMutedProfile(r.db.QueryRowContext(ctx, query, mutedName, muterID), false)
+}
+
+// ListMutedUsers lists the users a user muted, most recently muted first
+func (r *muteRepository) ListMutedUsers(
+	ctx context.Context,
+	muterID int64,
+) ([]repository.Profile, error) {
+	query := `
+		SELECT u.id, u.username, u.bio, u.image,
+			EXISTS (SELECT 1 FROM follows WHERE follower_id = $1 AND following_id = u.id)
+		FROM user_mutes m
+		JOIN users u ON u.id = m.muted_id
+		WHERE m.muter_id = $1
+		ORDER BY m.created_at DESC, u.id DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, muterID)
+	if err != nil {
+		return nil, repository.ErrInternal
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("error closing rows: %v", err)
+		}
+	}()
+
+	profiles := []repository.Profile{}
+	for rows.Next() {
+		profile, err := scanMutedProfile(rows, true)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, *profile)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, repository.ErrInternal
+	}
+
+	return profiles, nil
+}
+
+// MuteTag mutes a tag. Muting a tag again keeps it muted.
+func (r *muteRepository) MuteTag(ctx context.Context, userID int64, tag string) error {
+	query := `
+		INSERT INTO tag_mutes (user_id, tag)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id, tag) DO NOTHING
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, userID, tag); err != nil {
+		// Foreign key constraint violation
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+			return repository.ErrUserNotFound
+		}
+		return repository.ErrInternal
+	}
+
+	return nil
+}
+
+// UnmuteTag unmutes a tag
+func (r *muteRepository) UnmuteTag(ctx context.Context, userID int64, tag string) error {
+	query := `
+		DELETE FROM tag_mutes
+		WHERE user_id = $1 AND tag = $2
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, userID, tag); err != nil {
+		return repository.ErrInternal
+	}
+
+	return nil
+}
+
+// ListMutedTags lists the tags a user muted, most recently muted first
+func (r *muteRepository) ListMutedTags(ctx context.Context, userID int64) ([]string, error) {
+	query := `
+		SELECT tag
+		FROM tag_mutes
+		WHERE user_id = $1
+		ORDER BY created_at DESC, tag
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, repository.ErrInternal
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("error closing rows: %v", err)
+		}
+	}()
+
+	tags := []string{}
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, repository.ErrInternal
+		}
+		tags = append(tags, tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, repository.ErrInternal
+	}
+
+	return tags, nil
+}
+
+// scanMutedProfile scans the ID, username, bio and image of a user and whether the
+// current user follows them
+func scanMutedProfile(row rowScanner, muting bool) (*repository.Profile, error) {
+	var profile repository.Profile
+	var bio, image sql.NullString
+
+	err := row.Scan(&profile.ID, &profile.Username, &bio, &image, &profile.Following)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrUserNotFound
+		}
+
+		// PostgreSQL specific error handling
+		if pqErr, ok := err.(*pq.Error); ok {
+			// Foreign key constraint violation
+			if pqErr.Code == "23503" && pqErr.Constraint == "user_mutes_muter_id_fkey" {
+				return nil, repository.ErrUserNotFound
+			}
+			// Check for self-mute constraint violation
+			if pqErr.Code == "23514" && pqErr.Constraint == "prevent_self_mute" {
+				return nil, repository.ErrCannotMuteSelf
+			}
+		}
+
+		return nil, repository.ErrInternal
+	}
+
+	// Handle nullable values
+	if bio.Valid {
+		profile.Bio = bio.String
+	}
+	if image.Valid {
+		profile.Image = image.String
+	}
+	profile.Muting = muting
+
+	return &profile, nil
+}
@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Nilesh2000/conduit/internal/repository"
+	"github.com/lib/pq"
+)
+
+func Test_muteRepository_MuteUser(t *testing.T) {
+	t.Parallel()
+
+	// Define test cases
+	tests := []struct {
+		name        string
+		mockSetup   func(mock sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name: "User muted",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`WITH muted_user AS .* INSERT INTO user_mutes \(muter_id, muted_id\) SELECT \$2, id FROM muted_user ON CONFLICT \(muter_id, muted_id\) DO NOTHING .* SELECT u.id, u.username, u.bio, u.image, EXISTS`).
+					WithArgs("noisy", int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "username", "bio", "image", "exists"}).AddRow(2, "noisy", nil, nil, true))
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "User not found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`WITH muted_user AS`).
+					WithArgs("noisy", int64(1)).
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedErr: repository.ErrUserNotFound,
+		},
+		{
+			name: "Muting yourself",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`WITH muted_user AS`).
+					WithArgs("noisy", int64(1)).
+					WillReturnError(&pq.Error{Code: "23514", Constraint: "prevent_self_mute"})
+			},
+			expectedErr: repository.ErrCannotMuteSelf,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock database for this test case
+			db, mock := setupTestDB(t)
+			defer func() {
+				if err := db.Close(); err != nil {
+					log.Printf("Error closing database connection: %v", err)
+				}
+			}()
+
+			// Setup mock expectations
+			tt.mockSetup(mock)
+
+			// Create repository with mock database
+			repo := NewMuteRepository(db)
+
+			// Call MuteUser method
+			profile, err := repo.MuteUser(context.Background(), 1, "noisy")
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate that the muted user is still followed
+			if err == nil && (profile.Username != "noisy" || !profile.Muting || !profile.Following) {
+				t.Errorf("Expected a muted and followed profile of noisy, got %+v", profile)
+			}
+
+			// Ensure all expectations were met
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_muteRepository_ListMutedTags(t *testing.T) {
+	t.Parallel()
+
+	// Setup mock database
+	db, mock := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Printf("Error closing database connection: %v", err)
+		}
+	}()
+
+	mock.ExpectQuery(`SELECT tag FROM tag_mutes WHERE user_id = \$1 ORDER BY created_at DESC, tag`).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"tag"}).AddRow("crypto").AddRow("drama"))
+
+	// Call ListMutedTags method
+	tags, err := NewMuteRepository(db).ListMutedTags(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Validate tags
+	if len(tags) != 2 || tags[0] != "crypto" || tags[1] != "drama" {
+		t.Errorf("Expected tags [crypto drama], got %v", tags)
+	}
+
+	// Ensure all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
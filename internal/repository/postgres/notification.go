@@ -0,0 +1,319 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Nilesh2000/conduit/internal/repository"
+)
+
+// notificationKeyset pages through the notifications of a user, newest first
+var notificationKeyset = keyset{createdAt: "n.created_at", id: "n.id", descending: true}
+
+// notificationColumns are the notification, actor and article columns read by
+// scanNotification
+const notificationColumns = `
+	SELECT n.id, n.user_id, n.actor_id, n.type, n.article_id, n.comment_id, n.read_at,
+		n.created_at, u.username, u.bio, u.image,
+		EXISTS (
+			SELECT 1 FROM follows f WHERE f.follower_id = n.user_id AND f.following_id = u.id
+		) AS following,
+		a.slug, a.title
+`
+
+// notificationsVisible leaves out the notifications about articles and comments in the
+// trash, which come back if they are restored
+const notificationsVisible = `
+	FROM notifications n
+	JOIN users u ON u.id = n.actor_id
+	LEFT JOIN articles a ON a.id = n.article_id
+	LEFT JOIN comments c ON c.id = n.comment_id
+	WHERE n.user_id = $1 AND a.deleted_at IS NULL AND c.deleted_at IS NULL
+`
+
+// notificationRepository implements the repository.notificationRepository using PostgreSQL
+type notificationRepository struct {
+	db *sql.DB
+}
+
+// NewNotificationRepository creates a new notification repository
+func NewNotificationRepository(db *sql.DB) *notificationRepository {
+	return &notificationRepository{db: db}
+}
+
+// Create records a notification. It returns nil without an error if the notification
+// was not recorded, because the user would be notified of their own action, turned off
+// notifications of its type, blocked or was blocked by the actor, or was already told
+// about the same follow, favorite or comment.
+func (r *notificationRepository) Create(
+	ctx context.Context,
+	notification repository.Notification,
+) (*repository.Notification, error) {
+	query := `
+		INSERT INTO notifications (user_id, actor_id, type, article_id, comment_id)
+		SELECT u.id, $2, $3, $4, $5
+		FROM users u
+		WHERE u.id = $1 AND u.id <> $2
+			AND CASE $3::text
+				WHEN 'follow' THEN u.notify_follow
+				WHEN 'favorite' THEN u.notify_favorite
+				ELSE u.notify_comment
+			END
+			AND NOT ` + fmt.Sprintf(blockedBetween, "u.id", "$2") + `
+		ON CONFLICT DO NOTHING
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		notification.UserID,
+		notification.ActorID,
+		notification.Type,
+		notification.ArticleID,
+		notification.CommentID,
+	).Scan(&notification.ID, &notification.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, repository.ErrInternal
+	}
+
+	return &notification, nil
+}
+
+// Get gets a notification of a user along with its actor and article
+func (r *notificationRepository) Get(
+	ctx context.Context,
+	userID, notificationID int64,
+) (*repository.Notification, error) {
+	query := notificationColumns + notificationsVisible + " AND n.id = $2"
+
+	return scanNotification(r.db.QueryRowContext(ctx, query, userID, notificationID))
+}
+
+// List lists a page of the notifications of a user, newest first
+func (r *notificationRepository) List(
+	ctx context.Context,
+	userID int64,
+	filters repository.NotificationFilters,
+) (*repository.NotificationListResult, error) {
+	query := notificationColumns + notificationsVisible
+	args := []any{userID}
+
+	if filters.Unread {
+		query += " AND n.read_at IS NULL"
+	}
+
+	// Narrow the notifications down to the page after or before the cursor
+	backwards := filters.Before != nil
+	if condition, cursorArgs := notificationKeyset.condition(filters.After, filters.Before, len(args)+1); condition != "" {
+		query += " AND " + condition
+		args = append(args, cursorArgs...)
+	}
+	query += " ORDER BY " + notificationKeyset.orderBy(backwards)
+
+	// Read one more notification to tell whether another page follows
+	if filters.Limit > 0 {
+		args = append(args, filters.Limit+1)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, repository.ErrInternal
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("error closing rows: %v", err)
+		}
+	}()
+
+	notifications := []repository.Notification{}
+	for rows.Next() {
+		notification, err := scanNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, *notification)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, repository.ErrInternal
+	}
+
+	notifications, hasMore := trimPage(notifications, filters.Limit, backwards)
+
+	// The counts cover every page
+	countQuery := `SELECT COUNT(*), COUNT(*) FILTER (WHERE n.read_at IS NULL)` + notificationsVisible
+	if filters.Unread {
+		countQuery += " AND n.read_at IS NULL"
+	}
+
+	var count, unreadCount int
+	if err := r.db.QueryRowContext(ctx, countQuery, userID).Scan(&count, &unreadCount); err != nil {
+		return nil, repository.ErrInternal
+	}
+
+	return &repository.NotificationListResult{
+		Notifications: notifications,
+		HasMore:       hasMore,
+		Count:         count,
+		UnreadCount:   unreadCount,
+	}, nil
+}
+
+// MarkRead marks a notification of a user as read. A notification that was already read
+// keeps the time it was first read.
+func (r *notificationRepository) MarkRead(ctx context.Context, userID, notificationID int64) error {
+	query := `
+		UPDATE notifications
+		SET read_at = COALESCE(read_at, $3)
+		WHERE id = $1 AND user_id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, notificationID, userID, time.Now())
+	if err != nil {
+		return repository.ErrInternal
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return repository.ErrInternal
+	}
+	if rowsAffected == 0 {
+		return repository.ErrNotificationNotFound
+	}
+
+	return nil
+}
+
+// MarkAllRead marks every unread notification of a user as read, returning how many
+// there were
+func (r *notificationRepository) MarkAllRead(ctx context.Context, userID int64) (int, error) {
+	query := `
+		UPDATE notifications
+		SET read_at = $2
+		WHERE user_id = $1 AND read_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, userID, time.Now())
+	if err != nil {
+		return 0, repository.ErrInternal
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, repository.ErrInternal
+	}
+
+	return int(rowsAffected), nil
+}
+
+// GetPreferences gets the types of notifications a user gets
+func (r *notificationRepository) GetPreferences(
+	ctx context.Context,
+	userID int64,
+) (*repository.NotificationPreferences, error) {
+	query := `
+		SELECT notify_follow, notify_favorite, notify_comment
+		FROM users
+		WHERE id = $1
+	`
+
+	return scanNotificationPreferences(r.db.QueryRowContext(ctx, query, userID))
+}
+
+// UpdatePreferences turns the types of notifications a user gets on or off, leaving the
+// types that are nil as they are
+func (r *notificationRepository) UpdatePreferences(
+	ctx context.Context,
+	userID int64,
+	follow, favorite, comment *bool,
+) (*repository.NotificationPreferences, error) {
+	query := `
+		UPDATE users
+		SET
+			notify_follow = COALESCE($2, notify_follow),
+			notify_favorite = COALESCE($3, notify_favorite),
+			notify_comment = COALESCE($4, notify_comment)
+		WHERE id = $1
+		RETURNING notify_follow, notify_favorite, notify_comment
+	`
+
+	return scanNotificationPreferences(
+		r.db.QueryRowContext(ctx, query, userID, follow, favorite, comment),
+	)
+}
+
+// scanNotification scans a notification along with its actor and article
+func scanNotification(row rowScanner) (*repository.Notification, error) {
+	var notification repository.Notification
+	var actor repository.User
+	var bio, image, articleSlug, articleTitle sql.NullString
+	var articleID, commentID sql.NullInt64
+	var readAt sql.NullTime
+
+	if err := row.Scan(
+		&notification.ID,
+		&notification.UserID,
+		&notification.ActorID,
+		&notification.Type,
+		&articleID,
+		&commentID,
+		&readAt,
+		&notification.CreatedAt,
+		&actor.Username,
+		&bio,
+		&image,
+		&actor.Following,
+		&articleSlug,
+		&articleTitle,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrNotificationNotFound
+		}
+		return nil, repository.ErrInternal
+	}
+
+	// Handle nullable values
+	actor.ID = notification.ActorID
+	if bio.Valid {
+		actor.Bio = bio.String
+	}
+	if image.Valid {
+		actor.Image = image.String
+	}
+	notification.Actor = &actor
+	if articleID.Valid {
+		notification.ArticleID = &articleID.Int64
+		notification.ArticleSlug = articleSlug.String
+		notification.ArticleTitle = articleTitle.String
+	}
+	if commentID.Valid {
+		notification.CommentID = &commentID.Int64
+	}
+	if readAt.Valid {
+		notification.ReadAt = &readAt.Time
+	}
+
+	return &notification, nil
+}
+
+// scanNotificationPreferences scans the notification preferences of a user
+func scanNotificationPreferences(row rowScanner) (*repository.NotificationPreferences, error) {
+	var preferences repository.NotificationPreferences
+	if err := row.Scan(&preferences.Follow, &preferences.Favorite, &preferences.Comment); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrUserNotFound
+		}
+		return nil, repository.ErrInternal
+	}
+
+	return &preferences, nil
+}
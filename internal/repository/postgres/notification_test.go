@@ -0,0 +1,232 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Nilesh2000/conduit/internal/repository"
+)
+
+func Test_notificationRepository_Create(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// Define test cases
+	tests := []struct {
+		name          string
+		mockSetup     func(mock sqlmock.Sqlmock)
+		expectedErr   error
+		expectCreated bool
+	}{
+		{
+			name: "Notification recorded",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`INSERT INTO notifications \(user_id, actor_id, type, article_id, comment_id\) SELECT u.id, \$2, \$3, \$4, \$5 FROM users u WHERE u.id = \$1 AND u.id <> \$2 AND CASE \$3::text .* NOT EXISTS \(SELECT 1 FROM blocks b .*\) ON CONFLICT DO NOTHING RETURNING id, created_at`).
+					WithArgs(int64(1), int64(2), "favorite", sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(5, now))
+			},
+			expectedErr:   nil,
+			expectCreated: true,
+		},
+		{
+			name: "Notification skipped",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`INSERT INTO notifications`).
+					WithArgs(int64(1), int64(2), "favorite", sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}))
+			},
+			expectedErr:   nil,
+			expectCreated: false,
+		},
+		{
+			name: "Database error",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`INSERT INTO notifications`).
+					WithArgs(int64(1), int64(2), "favorite", sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnError(errors.New("database error"))
+			},
+			expectedErr: repository.ErrInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock database for this test case
+			db, mock := setupTestDB(t)
+			defer func() {
+				if err := db.Close(); err != nil {
+					log.Printf("Error closing database connection: %v", err)
+				}
+			}()
+
+			// Setup mock expectations
+			tt.mockSetup(mock)
+
+			// Create repository with mock database
+			repo := NewNotificationRepository(db)
+
+			// Call Create method
+			articleID := int64(3)
+			notification, err := repo.Create(context.Background(), repository.Notification{
+				UserID:    1,
+				ActorID:   2,
+				Type:      repository.NotificationTypeFavorite,
+				ArticleID: &articleID,
+			})
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate whether the notification was recorded
+			if err == nil {
+				if created := notification != nil; created != tt.expectCreated {
+					t.Errorf("Expected notification to be recorded: %v, got %v", tt.expectCreated, created)
+				}
+				if notification != nil && (notification.ID != 5 || !notification.CreatedAt.Equal(now)) {
+					t.Errorf("Expected notification 5 created at %v, got %+v", now, notification)
+				}
+			}
+
+			// Ensure all expectations were met
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_notificationRepository_List(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	columns := []string{
+		"id", "user_id", "actor_id", "type", "article_id", "comment_id", "read_at", "created_at",
+		"username", "bio", "image", "following", "slug", "title",
+	}
+
+	// Setup mock database
+	db, mock := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Printf("Error closing database connection: %v", err)
+		}
+	}()
+
+	rows := sqlmock.NewRows(columns).
+		AddRow(3, 1, 2, "comment", 4, 7, nil, now, "commenter", nil, nil, false, "test-article", "Test Article").
+		AddRow(2, 1, 3, "follow", nil, nil, now, now.Add(-time.Hour), "follower", nil, nil, true, nil, nil).
+		AddRow(1, 1, 3, "favorite", 4, nil, nil, now.Add(-2*time.Hour), "follower", nil, nil, true, "test-article", "Test Article")
+	mock.ExpectQuery(`SELECT n.id, .* FROM notifications n JOIN users u ON u.id = n.actor_id LEFT JOIN articles a ON a.id = n.article_id LEFT JOIN comments c ON c.id = n.comment_id WHERE n.user_id = \$1 AND a.deleted_at IS NULL AND c.deleted_at IS NULL ORDER BY n.created_at DESC, n.id DESC LIMIT \$2`).
+		WithArgs(int64(1), 3).
+		WillReturnRows(rows)
+	mock.ExpectQuery(`SELECT COUNT\(\*\), COUNT\(\*\) FILTER \(WHERE n.read_at IS NULL\) FROM notifications n`).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"count", "unread"}).AddRow(3, 2))
+
+	// Call List method
+	result, err := NewNotificationRepository(db).List(
+		context.Background(),
+		1,
+		repository.NotificationFilters{Limit: 2},
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Validate the page
+	if len(result.Notifications) != 2 || !result.HasMore || result.Count != 3 || result.UnreadCount != 2 {
+		t.Fatalf("Expected 2 of 3 notifications with 2 unread and more to come, got %+v", result)
+	}
+	comment, follow := result.Notifications[0], result.Notifications[1]
+	if comment.ArticleSlug != "test-article" || comment.CommentID == nil || *comment.CommentID != 7 || comment.ReadAt != nil {
+		t.Errorf("Expected an unread comment on test-article, got %+v", comment)
+	}
+	if follow.ArticleID != nil || follow.ReadAt == nil || !follow.Actor.Following {
+		t.Errorf("Expected a read follow by a followed user, got %+v", follow)
+	}
+
+	// Ensure all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func Test_notificationRepository_Get(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	columns := []string{
+		"id", "user_id", "actor_id", "type", "article_id", "comment_id", "read_at", "created_at",
+		"username", "bio", "image", "following", "slug", "title",
+	}
+
+	tests := []struct {
+		name          string
+		setupMock     func(mock sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name: "Notification found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT n.id, .* FROM notifications n .* WHERE n.user_id = \$1 AND a.deleted_at IS NULL AND c.deleted_at IS NULL AND n.id = \$2`).
+					WithArgs(int64(1), int64(5)).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(5, 1, 2, "follow", nil, nil, nil, now, "follower", nil, nil, false, nil, nil))
+			},
+		},
+		{
+			name: "Notification of another user or about a deleted comment",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT n.id, .* AND n.id = \$2`).
+					WithArgs(int64(1), int64(5)).
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedError: repository.ErrNotificationNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock database
+			db, mock := setupTestDB(t)
+			defer func() {
+				if err := db.Close(); err != nil {
+					log.Printf("Error closing database connection: %v", err)
+				}
+			}()
+
+			// Setup mock expectations
+			tt.setupMock(mock)
+
+			// Call Get method
+			notification, err := NewNotificationRepository(db).Get(context.Background(), 1, 5)
+
+			// Validate results
+			if !errors.Is(err, tt.expectedError) {
+				t.Fatalf("Expected error %v, got %v", tt.expectedError, err)
+			}
+			if tt.expectedError == nil && (notification.ID != 5 || notification.Actor.Username != "follower") {
+				t.Errorf("Expected notification 5 by follower, got %+v", notification)
+			}
+
+			// Ensure all expectations were met
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/Nilesh2000/conduit/internal/repository"
+)
+
+// outboxRepository implements the outbox of domain events using PostgreSQL
+type outboxRepository struct {
+	db *sql.DB
+}
+
+// NewOutboxRepository creates a new outbox repository
+func NewOutboxRepository(db *sql.DB) *outboxRepository {
+	return &outboxRepository{db: db}
+}
+
+// insertOutboxEvent writes an event to the outbox as part of tx, so that the event is
+// relayed if and only if the change it describes is committed
+func insertOutboxEvent(
+	ctx context.Context,
+	tx *sql.Tx,
+	aggregateType string,
+	aggregateID int64,
+	eventType string,
+	payload any,
+) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return repository.ErrInternal
+	}
+
+	query := `
+		INSERT INTO outbox (aggregate_type, aggregate_id, event_type, payload)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	_, err = tx.ExecContext(ctx, query, aggregateType, aggregateID, eventType, string(data))
+	if err != nil {
+		return repository.ErrInternal
+	}
+
+	return nil
+}
+
+// insertArticleEvent writes an event about an article to the outbox as part of tx
+func insertArticleEvent(
+	ctx context.Context,
+	tx *sql.Tx,
+	eventType string,
+	article *repository.Article,
+) error {
+	return insertOutboxEvent(ctx, tx, repository.OutboxAggregateArticle, article.ID, eventType, repository.ArticleEvent{
+		ArticleID: article.ID,
+		AuthorID:  article.AuthorID,
+		Slug:      article.Slug,
+		Status:    article.Status,
+	})
+}
+
+// insertCommentEvent writes an event about a comment to the outbox as part of tx
+func insertCommentEvent(
+	ctx context.Context,
+	tx *sql.Tx,
+	eventType string,
+	comment *repository.Comment,
+) error {
+	return insertOutboxEvent(ctx, tx, repository.OutboxAggregateComment, int64(comment.ID), eventType, repository.CommentEvent{
+		CommentID: int64(comment.ID),
+		ArticleID: comment.Article.ID,
+		AuthorID:  comment.Author.ID,
+	})
+}
+
+// insertFollowEvent writes an event about a user following another to the outbox as
+// part of tx
+func insertFollowEvent(
+	ctx context.Context,
+	tx *sql.Tx,
+	eventType string,
+	followerID, followingID int64,
+) error {
+	return insertOutboxEvent(ctx, tx, repository.OutboxAggregateUser, followingID, eventType, repository.FollowEvent{
+		FollowerID:  followerID,
+		FollowingID: followingID,
+	})
+}
+
+// ClaimDue claims up to limit events that are due at now, oldest first, until
+// leaseUntil. Rows claimed by another relay are skipped, so several replicas can relay
+// at the same time, and an event whose outcome is not recorded by then, because its
+// relay stopped, is claimed again. Failed events are never claimed.
+func (r *outboxRepository) ClaimDue(
+	ctx context.Context,
+	now, leaseUntil time.Time,
+	limit int,
+) ([]repository.OutboxEvent, error) {
+	query := `
+		WITH due AS (
+			SELECT id
+			FROM outbox
+			WHERE next_attempt_at <= $1 AND failed_at IS NULL
+			ORDER BY id ASC
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		),
+		claimed AS (
+			UPDATE outbox
+			SET next_attempt_at = $2
+			FROM due
+			WHERE outbox.id = due.id
+			RETURNING outbox.*
+		)
+		SELECT id, aggregate_type, aggregate_id, event_type, payload, attempts, created_at
+		FROM claimed
+		ORDER BY id ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, now, leaseUntil, limit)
+	if err != nil {
+		return nil, repository.ErrInternal
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("error closing rows: %v", err)
+		}
+	}()
+
+	events := []repository.OutboxEvent{}
+	for rows.Next() {
+		var event repository.OutboxEvent
+		if err := rows.Scan(
+			&event.ID,
+			&event.AggregateType,
+			&event.AggregateID,
+			&event.EventType,
+			&event.Payload,
+			&event.Attempts,
+			&event.CreatedAt,
+		); err != nil {
+			return nil, repository.ErrInternal
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, repository.ErrInternal
+	}
+
+	return events, nil
+}
+
+// Delete removes an event that was published from the outbox. An event that is no
+// longer there was published by another relay after the claim on it ran out.
+func (r *outboxRepository) Delete(ctx context.Context, eventID int64) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM outbox WHERE id = $1`, eventID); err != nil {
+		return repository.ErrInternal
+	}
+
+	return nil
+}
+
+// RecordFailure records that publishing an event failed and when to try again
+func (r *outboxRepository) RecordFailure(
+	ctx context.Context,
+	eventID int64,
+	lastError string,
+	nextAttemptAt time.Time,
+) error {
+	query := `
+		UPDATE outbox
+		SET attempts = attempts + 1, last_error = $2, next_attempt_at = $3
+		WHERE id = $1
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, eventID, lastError, nextAttemptAt); err != nil {
+		return repository.ErrInternal
+	}
+
+	return nil
+}
+
+// RecordFailed records that publishing an event failed for the last time. The event is
+// kept with its last error, but no longer relayed.
+func (r *outboxRepository) RecordFailed(
+	ctx context.Context,
+	eventID int64,
+	lastError string,
+	failedAt time.Time,
+) error {
+	query := `
+		UPDATE outbox
+		SET attempts = attempts + 1, last_error = $2, failed_at = $3
+		WHERE id = $1
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, eventID, lastError, failedAt); err != nil {
+		return repository.ErrInternal
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/Nilesh2000/conduit/internal/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func Test_outboxRepository_ClaimDue(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	leaseUntil := now.Add(time.Minute)
+
+	// Define test cases
+	tests := []struct {
+		name        string
+		mockSetup   func(mock sqlmock.Sqlmock)
+		expectedErr error
+		expectedIDs []int64
+	}{
+		{
+			name: "Due events claimed in order",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "aggregate_type", "aggregate_id", "event_type", "payload", "attempts", "created_at"}).
+					AddRow(4, "article", 1, "article.created", []byte(`{"articleId":1}`), 0, now.Add(-time.Minute)).
+					AddRow(5, "user", 2, "user.followed", []byte(`{"followerId":1,"followingId":2}`), 3, now)
+				mock.ExpectQuery(`WITH due AS \( SELECT id FROM outbox WHERE next_attempt_at <= \$1 AND failed_at IS NULL ORDER BY id ASC LIMIT \$3 FOR UPDATE SKIP LOCKED \), claimed AS \( UPDATE outbox SET next_attempt_at = \$2 .*\) SELECT id, aggregate_type, aggregate_id, event_type, payload, attempts, created_at FROM claimed ORDER BY id ASC`).
+					WithArgs(now, leaseUntil, 10).
+					WillReturnRows(rows)
+			},
+			expectedErr: nil,
+			expectedIDs: []int64{4, 5},
+		},
+		{
+			name: "Database error",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`WITH due AS`).
+					WithArgs(now, leaseUntil, 10).
+					WillReturnError(errors.New("database error"))
+			},
+			expectedErr: repository.ErrInternal,
+			expectedIDs: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock database for this test case
+			db, mock := setupTestDB(t)
+			defer func() {
+				if err := db.Close(); err != nil {
+					log.Printf("Error closing database connection: %v", err)
+				}
+			}()
+
+			// Setup mock expectations
+			tt.mockSetup(mock)
+
+			// Call ClaimDue method
+			repo := NewOutboxRepository(db)
+			events, err := repo.ClaimDue(context.Background(), now, leaseUntil, 10)
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate claimed events
+			var ids []int64
+			for _, event := range events {
+				ids = append(ids, event.ID)
+			}
+			if len(ids) != len(tt.expectedIDs) {
+				t.Fatalf("Expected events %v, got %v", tt.expectedIDs, ids)
+			}
+			for i := range ids {
+				if ids[i] != tt.expectedIDs[i] {
+					t.Errorf("Expected events %v, got %v", tt.expectedIDs, ids)
+				}
+			}
+			if len(events) > 0 && (events[1].EventType != "user.followed" || events[1].Attempts != 3) {
+				t.Errorf("Unexpected event %+v", events[1])
+			}
+
+			// Ensure all expectations were met
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_outboxRepository_RecordFailure(t *testing.T) {
+	t.Parallel()
+
+	nextAttemptAt := time.Date(2024, 1, 1, 12, 5, 0, 0, time.UTC)
+
+	// Setup mock database
+	db, mock := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Printf("Error closing database connection: %v", err)
+		}
+	}()
+
+	// Setup mock expectations
+	mock.ExpectExec(`UPDATE outbox SET attempts = attempts \+ 1, last_error = \$2, next_attempt_at = \$3 WHERE id = \$1`).
+		WithArgs(int64(4), "unexpected status 503", nextAttemptAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Call RecordFailure method
+	repo := NewOutboxRepository(db)
+	if err := repo.RecordFailure(context.Background(), 4, "unexpected status 503", nextAttemptAt); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Ensure all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func Test_outboxRepository_RecordFailed(t *testing.T) {
+	t.Parallel()
+
+	failedAt := time.Date(2024, 1, 1, 12, 5, 0, 0, time.UTC)
+
+	// Setup mock database
+	db, mock := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Printf("Error closing database connection: %v", err)
+		}
+	}()
+
+	// Setup mock expectations
+	mock.ExpectExec(`UPDATE outbox SET attempts = attempts \+ 1, last_error = \$2, failed_at = \$3 WHERE id = \$1`).
+		WithArgs(int64(4), "decoding article.favorited event 4", failedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Call RecordFailed method
+	repo := NewOutboxRepository(db)
+	if err := repo.RecordFailed(context.Background(), 4, "decoding article.favorited event 4", failedAt); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Ensure all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/Nilesh2000/conduit/internal/repository"
+)
+
+// keyset pages through rows ordered by their creation time and ID. Pages start right
+// after or right before a cursor rather than at an offset, so they stay cheap however
+// deep they are and do not shift when rows are added.
+type keyset struct {
+	// createdAt and id are the columns rows are ordered by
+	createdAt, id string
+	// descending lists the newest rows first
+	descending bool
+}
+
+// condition returns the condition selecting the rows after the cursor after or before
+// the cursor before in list order, numbering its placeholders from argIndex, along
+// with its arguments. It returns an empty condition if there is no cursor.
+func (k keyset) condition(after, before *repository.Cursor, argIndex int) (string, []any) {
+	cursor := after
+	if cursor == nil {
+		cursor = before
+	}
+	if cursor == nil {
+		return "", nil
+	}
+
+	op := ">"
+	if k.descending == (after != nil) {
+		op = "<"
+	}
+
+	return fmt.Sprintf("(%s, %s) %s ($%d, $%d)", k.createdAt, k.id, op, argIndex, argIndex+1),
+		[]any{cursor.CreatedAt, cursor.ID}
+}
+
+// orderBy returns the order rows are read in. Pages before a cursor are read backwards,
+// starting at the cursor.
+func (k keyset) orderBy(backwards bool) string {
+	direction := "ASC"
+	if k.descending != backwards {
+		direction = "DESC"
+	}
+	return fmt.Sprintf("%s %s, %s %s", k.createdAt, direction, k.id, direction)
+}
+
+// trimPage drops the extra row read past a page of limit rows, reporting whether there
+// was one, and puts a page read backwards back in list order
+func trimPage[T any](rows []T, limit int, backwards bool) ([]T, bool) {
+	hasMore := limit > 0 && len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+	if backwards {
+		slices.Reverse(rows)
+	}
+	return rows, hasMore
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"log"
 
 	"github.com/Nilesh2000/conduit/internal/repository"
@@ -21,7 +22,8 @@ func NewProfileRepository(db *sql.DB) *profileRepository {
 	return &profileRepository{db: db}
 }
 
-// FollowUser follows a user
+// FollowUser follows a user. Users who blocked each other in either direction cannot
+// follow each other.
 func (r *profileRepository) FollowUser(
 	ctx context.Context,
 	followerID int64,
@@ -44,21 +46,30 @@ func (r *profileRepository) FollowUser(
 			FROM users
 			WHERE username = $1
 		),
+		block AS (
+			SELECT ` + fmt.Sprintf(blockedBetween, "$2", "fu.id") + ` AS blocked
+			FROM following_user fu
+		),
 		follow_attempt AS (
 			INSERT INTO follows (follower_id, following_id)
 			SELECT $2, id FROM following_user
+			WHERE NOT (SELECT blocked FROM block)
 			ON CONFLICT (follower_id, following_id) DO NOTHING
+			RETURNING following_id
 		)
-		SELECT u.id, u.username, u.bio, u.image, true AS following
+		SELECT
+			u.id, u.username, u.bio, u.image, true AS following, (SELECT blocked FROM block),
+			EXISTS (SELECT 1 FROM follow_attempt) AS followed
 		FROM users u
 		JOIN following_user fu ON u.id = fu.id
 	`
 
 	var profile repository.Profile
 	var bio, image sql.NullString
+	var blocked, followed bool
 
 	err = tx.QueryRowContext(ctx, query, followingName, followerID).
-		Scan(&profile.ID, &profile.Username, &bio, &image, &profile.Following)
+		Scan(&profile.ID, &profile.Username, &bio, &image, &profile.Following, &blocked, &followed)
 	if err != nil {
 		// following_user does not exist
 		if errors.Is(err, sql.ErrNoRows) {
@@ -84,6 +95,17 @@ func (r *profileRepository) FollowUser(
 		return nil, repository.ErrInternal
 	}
 
+	if blocked {
+		return nil, repository.ErrUserBlocked
+	}
+
+	// Record the event unless the user was already followed
+	if followed {
+		if err := insertFollowEvent(ctx, tx, repository.OutboxEventUserFollowed, followerID, profile.ID); err != nil {
+			return nil, err
+		}
+	}
+
 	// Handle nullable values
 	if bio.Valid {
 		profile.Bio = bio.String
@@ -126,17 +148,21 @@ func (r *profileRepository) UnfollowUser(
 		unfollow_attempt AS (
 			DELETE FROM follows
 			WHERE follower_id = $2 AND following_id = (SELECT id FROM following_user)
+			RETURNING following_id
 		)
-		SELECT u.id, u.username, u.bio, u.image, false AS following
+		SELECT
+			u.id, u.username, u.bio, u.image, false AS following,
+			EXISTS (SELECT 1 FROM unfollow_attempt) AS unfollowed
 		FROM users u
 		JOIN following_user fu ON u.id = fu.id
 	`
 
 	var profile repository.Profile
 	var bio, image sql.NullString
+	var unfollowed bool
 
 	err = tx.QueryRowContext(ctx, query, followingName, followerID).
-		Scan(&profile.ID, &profile.Username, &bio, &image, &profile.Following)
+		Scan(&profile.ID, &profile.Username, &bio, &image, &profile.Following, &unfollowed)
 	if err != nil {
 		// following_user does not exist
 		if errors.Is(err, sql.ErrNoRows) {
@@ -156,6 +182,13 @@ func (r *profileRepository) UnfollowUser(
 		return nil, repository.ErrInternal
 	}
 
+	// Record the event unless the user was not followed
+	if unfollowed {
+		if err := insertFollowEvent(ctx, tx, repository.OutboxEventUserUnfollowed, followerID, profile.ID); err != nil {
+			return nil, err
+		}
+	}
+
 	// Handle nullable values
 	if bio.Valid {
 		profile.Bio = bio.String
@@ -194,3 +227,128 @@ func (r *profileRepository) IsFollowing(
 
 	return following, nil
 }
+
+// followKeyset pages through the profiles of a follow list, newest follow first
+var followKeyset = keyset{createdAt: "f.created_at", id: "u.id", descending: true}
+
+// ListFollowers lists the users following a user. Following reports whether the
+// current user follows each of them, and is false if there is no current user.
+func (r *profileRepository) ListFollowers(
+	ctx context.Context,
+	userID int64,
+	currentUserID *int64,
+	filters repository.FollowFilters,
+) (*repository.FollowListResult, error) {
+	return r.listFollows(ctx, "f.follower_id", "f.following_id", userID, currentUserID, filters)
+}
+
+// ListFollowing lists the users a user follows. Following reports whether the current
+// user follows each of them, and is false if there is no current user.
+func (r *profileRepository) ListFollowing(
+	ctx context.Context,
+	userID int64,
+	currentUserID *int64,
+	filters repository.FollowFilters,
+) (*repository.FollowListResult, error) {
+	return r.listFollows(ctx, "f.following_id", "f.follower_id", userID, currentUserID, filters)
+}
+
+// listFollows lists the users in the listed column of the follows whose owner column is
+// userID
+func (r *profileRepository) listFollows(
+	ctx context.Context,
+	listedColumn, ownerColumn string,
+	userID int64,
+	currentUserID *int64,
+	filters repository.FollowFilters,
+) (*repository.FollowListResult, error) {
+	query := `
+		SELECT u.id, u.username, u.bio, u.image, f.created_at,
+			EXISTS (
+				SELECT 1 FROM follows v WHERE v.follower_id = $2 AND v.following_id = u.id
+			) AS following
+		FROM follows f
+		JOIN users u ON u.id = ` + listedColumn + `
+		WHERE ` + ownerColumn + ` = $1
+		`
+	args := []any{userID, currentUserID}
+
+	// Narrow the profiles down to the page after or before the cursor
+	backwards := filters.Before != nil
+	if condition, cursorArgs := followKeyset.condition(filters.After, filters.Before, len(args)+1); condition != "" {
+		query += " AND " + condition
+		args = append(args, cursorArgs...)
+	}
+	query += " ORDER BY " + followKeyset.orderBy(backwards)
+
+	// Read one more profile to tell whether another page follows
+	if filters.Limit > 0 {
+		args = append(args, filters.Limit+1)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, repository.ErrInternal
+	}
+	defer rows.Close()
+
+	profiles := []repository.Profile{}
+	for rows.Next() {
+		var profile repository.Profile
+		var bio, image sql.NullString
+		if err := rows.Scan(
+			&profile.ID, &profile.Username, &bio, &image, &profile.FollowedAt, &profile.Following,
+		); err != nil {
+			return nil, repository.ErrInternal
+		}
+
+		// Handle nullable values
+		if bio.Valid {
+			profile.Bio = bio.String
+		}
+		if image.Valid {
+			profile.Image = image.String
+		}
+
+		profiles = append(profiles, profile)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, repository.ErrInternal
+	}
+
+	profiles, hasMore := trimPage(profiles, filters.Limit, backwards)
+
+	// The count covers every page
+	countQuery := `SELECT COUNT(*) FROM follows f WHERE ` + ownerColumn + ` = $1`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, countQuery, userID).Scan(&count); err != nil {
+		return nil, repository.ErrInternal
+	}
+
+	return &repository.FollowListResult{
+		Profiles: profiles,
+		HasMore:  hasMore,
+		Count:    count,
+	}, nil
+}
+
+// CountFollows counts the followers of a user and the users they follow
+func (r *profileRepository) CountFollows(
+	ctx context.Context,
+	userID int64,
+) (followers, following int, err error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM follows WHERE following_id = $1) AS followers,
+			(SELECT COUNT(*) FROM follows WHERE follower_id = $1) AS following
+	`
+
+	if err := r.db.QueryRowContext(ctx, query, userID).Scan(&followers, &following); err != nil {
+		return 0, 0, repository.ErrInternal
+	}
+
+	return followers, following, nil
+}
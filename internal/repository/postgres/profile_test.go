@@ -0,0 +1,252 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Nilesh2000/conduit/internal/repository"
+)
+
+func Test_profileRepository_FollowUser(t *testing.T) {
+	t.Parallel()
+
+	profileColumnNames := []string{"id", "username", "bio", "image", "following", "blocked", "followed"}
+
+	// Define test cases
+	tests := []struct {
+		name        string
+		mockSetup   func(mock sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name: "Follow records an event",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`WITH following_user AS .* RETURNING following_id \) SELECT .* EXISTS \(SELECT 1 FROM follow_attempt\) AS followed`).
+					WithArgs("jane", int64(1)).
+					WillReturnRows(sqlmock.NewRows(profileColumnNames).AddRow(2, "jane", nil, nil, true, false, true))
+				mock.ExpectExec(`INSERT INTO outbox`).
+					WithArgs("user", int64(2), "user.followed", `{"followerId":1,"followingId":2}`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "User already followed",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`WITH following_user AS`).
+					WithArgs("jane", int64(1)).
+					WillReturnRows(sqlmock.NewRows(profileColumnNames).AddRow(2, "jane", nil, nil, true, false, false))
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "User blocked",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`WITH following_user AS`).
+					WithArgs("jane", int64(1)).
+					WillReturnRows(sqlmock.NewRows(profileColumnNames).AddRow(2, "jane", nil, nil, true, true, false))
+				mock.ExpectRollback()
+			},
+			expectedErr: repository.ErrUserBlocked,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock database for this test case
+			db, mock := setupTestDB(t)
+			defer func() {
+				if err := db.Close(); err != nil {
+					log.Printf("Error closing database connection: %v", err)
+				}
+			}()
+
+			// Setup mock expectations
+			tt.mockSetup(mock)
+
+			// Create repository with mock database
+			repo := NewProfileRepository(db)
+
+			// Call FollowUser method
+			profile, err := repo.FollowUser(context.Background(), 1, "jane")
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate profile if no error
+			if err == nil && (profile.Username != "jane" || !profile.Following) {
+				t.Errorf("Expected jane to be followed, got %+v", profile)
+			}
+
+			// Ensure all expectations were met
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_profileRepository_ListFollowers(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	currentUserID := int64(3)
+	profileColumnNames := []string{"id", "username", "bio", "image", "created_at", "following"}
+
+	// Define test cases
+	tests := []struct {
+		name              string
+		filters           repository.FollowFilters
+		mockSetup         func(mock sqlmock.Sqlmock)
+		expectedErr       error
+		expectedUsernames []string
+		expectedHasMore   bool
+	}{
+		{
+			name:    "First page",
+			filters: repository.FollowFilters{Limit: 2},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(profileColumnNames).
+					AddRow(4, "newest", nil, nil, now.Add(2*time.Hour), true).
+					AddRow(2, "older", "Bio", nil, now.Add(time.Hour), false).
+					AddRow(5, "oldest", nil, nil, now, false)
+				mock.ExpectQuery(`SELECT u.id, u.username, u.bio, u.image, f.created_at, EXISTS \( SELECT 1 FROM follows v WHERE v.follower_id = \$2 AND v.following_id = u.id \) AS following FROM follows f JOIN users u ON u.id = f.follower_id WHERE f.following_id = \$1 ORDER BY f.created_at DESC, u.id DESC LIMIT \$3`).
+					WithArgs(int64(1), currentUserID, 3).
+					WillReturnRows(rows)
+				mock.ExpectQuery(`SELECT COUNT\(\*\) FROM follows f WHERE f.following_id = \$1`).
+					WithArgs(int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+			},
+			expectedErr:       nil,
+			expectedUsernames: []string{"newest", "older"},
+			expectedHasMore:   true,
+		},
+		{
+			name: "Page before a cursor",
+			filters: repository.FollowFilters{
+				Limit:  2,
+				Before: &repository.Cursor{CreatedAt: now, ID: 5},
+			},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(profileColumnNames).
+					AddRow(2, "older", nil, nil, now.Add(time.Hour), false).
+					AddRow(4, "newest", nil, nil, now.Add(2*time.Hour), true)
+				mock.ExpectQuery(`WHERE f.following_id = \$1 AND \(f.created_at, u.id\) > \(\$3, \$4\) ORDER BY f.created_at ASC, u.id ASC LIMIT \$5`).
+					WithArgs(int64(1), currentUserID, now, int64(5), 3).
+					WillReturnRows(rows)
+				mock.ExpectQuery(`SELECT COUNT\(\*\) FROM follows f`).
+					WithArgs(int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+			},
+			expectedErr:       nil,
+			expectedUsernames: []string{"newest", "older"},
+			expectedHasMore:   false,
+		},
+		{
+			name:    "Database error",
+			filters: repository.FollowFilters{Limit: 2},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT u.id, u.username`).
+					WithArgs(int64(1), currentUserID, 3).
+					WillReturnError(errors.New("database error"))
+			},
+			expectedErr: repository.ErrInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock database for this test case
+			db, mock := setupTestDB(t)
+			defer func() {
+				if err := db.Close(); err != nil {
+					log.Printf("Error closing database connection: %v", err)
+				}
+			}()
+
+			// Setup mock expectations
+			tt.mockSetup(mock)
+
+			// Create repository with mock database
+			repo := NewProfileRepository(db)
+
+			// Call ListFollowers method
+			result, err := repo.ListFollowers(context.Background(), 1, &currentUserID, tt.filters)
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate result if no error
+			if err == nil {
+				if len(result.Profiles) != len(tt.expectedUsernames) {
+					t.Fatalf("Expected %d profiles, got %d", len(tt.expectedUsernames), len(result.Profiles))
+				}
+				for i, profile := range result.Profiles {
+					if profile.Username != tt.expectedUsernames[i] {
+						t.Errorf("Expected %q at index %d, got %q", tt.expectedUsernames[i], i, profile.Username)
+					}
+				}
+				if result.HasMore != tt.expectedHasMore || result.Count != 3 {
+					t.Errorf("Expected hasMore %v and count 3, got %v and %d", tt.expectedHasMore, result.HasMore, result.Count)
+				}
+			}
+
+			// Ensure all expectations were met
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_profileRepository_CountFollows(t *testing.T) {
+	t.Parallel()
+
+	// Setup mock database
+	db, mock := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Printf("Error closing database connection: %v", err)
+		}
+	}()
+
+	// Setup mock expectations
+	mock.ExpectQuery(`SELECT \(SELECT COUNT\(\*\) FROM follows WHERE following_id = \$1\) AS followers, \(SELECT COUNT\(\*\) FROM follows WHERE follower_id = \$1\) AS following`).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"followers", "following"}).AddRow(3, 5))
+
+	// Call CountFollows method
+	followers, following, err := NewProfileRepository(db).CountFollows(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Validate counts
+	if followers != 3 || following != 5 {
+		t.Errorf("Expected 3 followers and 5 followed users, got %d and %d", followers, following)
+	}
+
+	// Ensure all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
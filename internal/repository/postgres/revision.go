@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/Nilesh2000/conduit/internal/repository"
+)
+
+// revisionRepository implements the RevisionRepository interface
+type revisionRepository struct {
+	db *sql.DB
+}
+
+// NewRevisionRepository creates a new revision repository
+func NewRevisionRepository(db *sql.DB) *revisionRepository {
+	return &revisionRepository{db: db}
+}
+
+// ListByArticleID lists the revisions of an article, newest first
+func (r *revisionRepository) ListByArticleID(
+	ctx context.Context,
+	articleID int64,
+) ([]repository.ArticleRevision, error) {
+	query := `
+		SELECT id, article_id, revision_number, title, description, body, created_at
+		FROM article_revisions
+		WHERE article_id = $1
+		ORDER BY revision_number DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, articleID)
+	if err != nil {
+		return nil, repository.ErrInternal
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("error closing rows: %v", err)
+		}
+	}()
+
+	revisions := []repository.ArticleRevision{}
+	for rows.Next() {
+		var revision repository.ArticleRevision
+		if err := rows.Scan(
+			&revision.ID,
+			&revision.ArticleID,
+			&revision.Number,
+			&revision.Title,
+			&revision.Description,
+			&revision.Body,
+			&revision.CreatedAt,
+		); err != nil {
+			return nil, repository.ErrInternal
+		}
+		revisions = append(revisions, revision)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, repository.ErrInternal
+	}
+
+	return revisions, nil
+}
+
+// GetByNumber gets a revision of an article by its number
+func (r *revisionRepository) GetByNumber(
+	ctx context.Context,
+	articleID int64,
+	number int,
+) (*repository.ArticleRevision, error) {
+	query := `
+		SELECT id, article_id, revision_number, title, description, body, created_at
+		FROM article_revisions
+		WHERE article_id = $1 AND revision_number = $2
+	`
+
+	var revision repository.ArticleRevision
+	err := r.db.QueryRowContext(ctx, query, articleID, number).Scan(
+		&revision.ID,
+		&revision.ArticleID,
+		&revision.Number,
+		&revision.Title,
+		&revision.Description,
+		&revision.Body,
+		&revision.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.ErrRevisionNotFound
+		}
+		return nil, repository.ErrInternal
+	}
+
+	return &revision, nil
+}
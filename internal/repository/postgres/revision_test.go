@@ -0,0 +1,187 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Nilesh2000/conduit/internal/repository"
+)
+
+func Test_revisionRepository_ListByArticleID(t *testing.T) {
+	t.Parallel()
+
+	// Define test cases
+	tests := []struct {
+		name            string
+		mockSetup       func(mock sqlmock.Sqlmock)
+		expectedErr     error
+		expectedNumbers []int
+	}{
+		{
+			name: "Revisions found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "article_id", "revision_number", "title", "description", "body", "created_at"}).
+					AddRow(2, 1, 2, "Title", "Description", "New body", time.Now()).
+					AddRow(1, 1, 1, "Title", "Description", "Old body", time.Now())
+
+				mock.ExpectQuery(`SELECT id, article_id, revision_number, title, description, body, created_at FROM article_revisions WHERE article_id = \$1 ORDER BY revision_number DESC`).
+					WithArgs(int64(1)).
+					WillReturnRows(rows)
+			},
+			expectedErr:     nil,
+			expectedNumbers: []int{2, 1},
+		},
+		{
+			name: "No revisions",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT id, article_id, revision_number`).
+					WithArgs(int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "article_id", "revision_number", "title", "description", "body", "created_at"}))
+			},
+			expectedErr:     nil,
+			expectedNumbers: []int{},
+		},
+		{
+			name: "Database error",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT id, article_id, revision_number`).
+					WithArgs(int64(1)).
+					WillReturnError(errors.New("database error"))
+			},
+			expectedErr:     repository.ErrInternal,
+			expectedNumbers: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock database for this test case
+			db, mock := setupTestDB(t)
+			defer func() {
+				if err := db.Close(); err != nil {
+					log.Printf("Error closing database connection: %v", err)
+				}
+			}()
+
+			// Setup mock expectations
+			tt.mockSetup(mock)
+
+			// Create repository with mock database
+			repo := NewRevisionRepository(db)
+
+			// Call ListByArticleID method
+			revisions, err := repo.ListByArticleID(context.Background(), 1)
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate revisions if no error
+			if err == nil {
+				if len(revisions) != len(tt.expectedNumbers) {
+					t.Fatalf("Expected %d revisions, got %d", len(tt.expectedNumbers), len(revisions))
+				}
+				for i, revision := range revisions {
+					if revision.Number != tt.expectedNumbers[i] {
+						t.Errorf("Expected revision %d at index %d, got %d", tt.expectedNumbers[i], i, revision.Number)
+					}
+				}
+			}
+
+			// Ensure all expectations were met
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_revisionRepository_GetByNumber(t *testing.T) {
+	t.Parallel()
+
+	// Define test cases
+	tests := []struct {
+		name        string
+		mockSetup   func(mock sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name: "Revision found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "article_id", "revision_number", "title", "description", "body", "created_at"}).
+					AddRow(2, 1, 2, "Title", "Description", "Body", time.Now())
+
+				mock.ExpectQuery(`SELECT id, article_id, revision_number, title, description, body, created_at FROM article_revisions WHERE article_id = \$1 AND revision_number = \$2`).
+					WithArgs(int64(1), 2).
+					WillReturnRows(rows)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Revision not found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT id, article_id, revision_number`).
+					WithArgs(int64(1), 2).
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedErr: repository.ErrRevisionNotFound,
+		},
+		{
+			name: "Database error",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT id, article_id, revision_number`).
+					WithArgs(int64(1), 2).
+					WillReturnError(errors.New("database error"))
+			},
+			expectedErr: repository.ErrInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock database for this test case
+			db, mock := setupTestDB(t)
+			defer func() {
+				if err := db.Close(); err != nil {
+					log.Printf("Error closing database connection: %v", err)
+				}
+			}()
+
+			// Setup mock expectations
+			tt.mockSetup(mock)
+
+			// Create repository with mock database
+			repo := NewRevisionRepository(db)
+
+			// Call GetByNumber method
+			revision, err := repo.GetByNumber(context.Background(), 1, 2)
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate revision if no error
+			if err == nil && (revision.Number != 2 || revision.ArticleID != 1) {
+				t.Errorf("Expected revision 2 of article 1, got %+v", revision)
+			}
+
+			// Ensure all expectations were met
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
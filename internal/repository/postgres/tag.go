@@ -18,7 +18,12 @@ func NewTagRepository(db *sql.DB) *tagRepository {
 // GetTags gets all tags
 func (r *tagRepository) Get(ctx context.Context) ([]string, error) {
 	query := `
-		SELECT name FROM tags
+		SELECT name FROM tags t
+		WHERE EXISTS (
+			SELECT 1 FROM article_tags at
+			JOIN articles a ON a.id = at.article_id
+			WHERE at.tag_id = t.id AND a.deleted_at IS NULL
+		)
 	`
 
 	rows, err := r.db.QueryContext(ctx, query)
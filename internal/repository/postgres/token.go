@@ -0,0 +1,343 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/Nilesh2000/conduit/internal/repository"
+)
+
+// tokenRepository implements the repository.tokenRepository using PostgreSQL
+type tokenRepository struct {
+	db *sql.DB
+}
+
+// NewTokenRepository creates a new TokenRepository
+func NewTokenRepository(db *sql.DB) *tokenRepository {
+	return &tokenRepository{db: db}
+}
+
+// CreateRefreshToken stores a new refresh token
+func (r *tokenRepository) CreateRefreshToken(
+	ctx context.Context,
+	token repository.RefreshToken,
+) error {
+	query := `
+		INSERT INTO refresh_tokens (
+			user_id, token_hash, family_id, access_token_id, access_token_expires_at, expires_at, created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		token.UserID,
+		token.TokenHash,
+		token.FamilyID,
+		token.AccessTokenID,
+		token.AccessTokenExpiresAt,
+		token.ExpiresAt,
+		time.Now(),
+	)
+	if err != nil {
+		return repository.ErrInternal
+	}
+
+	return nil
+}
+
+// FindRefreshTokenByHash finds a refresh token by its hash
+func (r *tokenRepository) FindRefreshTokenByHash(
+	ctx context.Context,
+	tokenHash string,
+) (*repository.RefreshToken, error) {
+	query := `
+		SELECT
+			id, user_id, token_hash, family_id, access_token_id, access_token_expires_at,
+			expires_at, revoked_at, created_at
+		FROM refresh_tokens
+		WHERE token_hash = $1
+	`
+
+	return scanRefreshToken(r.db.QueryRowContext(ctx, query, tokenHash))
+}
+
+// FindRefreshTokenByAccessTokenID finds the refresh token issued alongside an access token
+func (r *tokenRepository) FindRefreshTokenByAccessTokenID(
+	ctx context.Context,
+	accessTokenID string,
+) (*repository.RefreshToken, error) {
+	query := `
+		SELECT
+			id, user_id, token_hash, family_id, access_token_id, access_token_expires_at,
+			expires_at, revoked_at, created_at
+		FROM refresh_tokens
+		WHERE access_token_id = $1
+	`
+
+	return scanRefreshToken(r.db.QueryRowContext(ctx, query, accessTokenID))
+}
+
+// scanRefreshToken scans a single refresh token row
+func scanRefreshToken(row *sql.Row) (*repository.RefreshToken, error) {
+	var token repository.RefreshToken
+	var revokedAt sql.NullTime
+
+	err := row.Scan(
+		&token.ID,
+		&token.UserID,
+		&token.TokenHash,
+		&token.FamilyID,
+		&token.AccessTokenID,
+		&token.AccessTokenExpiresAt,
+		&token.ExpiresAt,
+		&revokedAt,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.ErrRefreshTokenNotFound
+		}
+		return nil, repository.ErrInternal
+	}
+
+	// Handle nullable values
+	if revokedAt.Valid {
+		token.RevokedAt = &revokedAt.Time
+	}
+
+	return &token, nil
+}
+
+// RotateRefreshToken revokes the refresh token with the given ID and stores its replacement.
+// It returns ErrRefreshTokenReused if the token was already revoked by a concurrent request.
+func (r *tokenRepository) RotateRefreshToken(
+	ctx context.Context,
+	tokenID int64,
+	next repository.RefreshToken,
+) error {
+	// Begin a transaction
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return repository.ErrInternal
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			log.Printf("transaction rollback error: %v", err)
+		}
+	}()
+
+	now := time.Now()
+
+	// Revoke the current token, only if nobody else has done so already
+	result, err := tx.ExecContext(
+		ctx,
+		"UPDATE refresh_tokens SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL",
+		now,
+		tokenID,
+	)
+	if err != nil {
+		return repository.ErrInternal
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return repository.ErrInternal
+	}
+
+	if rowsAffected == 0 {
+		return repository.ErrRefreshTokenReused
+	}
+
+	// Store the replacement token
+	query := `
+		INSERT INTO refresh_tokens (
+			user_id, token_hash, family_id, access_token_id, access_token_expires_at, expires_at, created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err = tx.ExecContext(
+		ctx,
+		query,
+		next.UserID,
+		next.TokenHash,
+		next.FamilyID,
+		next.AccessTokenID,
+		next.AccessTokenExpiresAt,
+		next.ExpiresAt,
+		now,
+	)
+	if err != nil {
+		return repository.ErrInternal
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		return repository.ErrInternal
+	}
+
+	return nil
+}
+
+// RevokeRefreshTokenFamily revokes every refresh token in a family together with the
+// access tokens that were issued alongside them
+func (r *tokenRepository) RevokeRefreshTokenFamily(
+	ctx context.Context,
+	familyID string,
+) error {
+	// Begin a transaction
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return repository.ErrInternal
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			log.Printf("transaction rollback error: %v", err)
+		}
+	}()
+
+	now := time.Now()
+
+	// Revoke the refresh tokens
+	_, err = tx.ExecContext(
+		ctx,
+		"UPDATE refresh_tokens SET revoked_at = $1 WHERE family_id = $2 AND revoked_at IS NULL",
+		now,
+		familyID,
+	)
+	if err != nil {
+		return repository.ErrInternal
+	}
+
+	// Revoke the access tokens that have not expired yet
+	query := `
+		INSERT INTO revoked_tokens (jti, expires_at)
+		SELECT access_token_id, access_token_expires_at
+		FROM refresh_tokens
+		WHERE family_id = $1 AND access_token_expires_at > $2
+		ON CONFLICT (jti) DO NOTHING
+	`
+
+	_, err = tx.ExecContext(ctx, query, familyID, now)
+	if err != nil {
+		return repository.ErrInternal
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		return repository.ErrInternal
+	}
+
+	return nil
+}
+
+// RevokeAccessToken revokes an access token by its ID
+func (r *tokenRepository) RevokeAccessToken(
+	ctx context.Context,
+	tokenID string,
+	expiresAt time.Time,
+) error {
+	query := `
+		INSERT INTO revoked_tokens (jti, expires_at)
+		VALUES ($1, $2)
+		ON CONFLICT (jti) DO NOTHING
+	`
+
+	_, err := r.db.ExecContext(ctx, query, tokenID, expiresAt)
+	if err != nil {
+		return repository.ErrInternal
+	}
+
+	return nil
+}
+
+// IsAccessTokenRevoked checks if an access token has been revoked
+func (r *tokenRepository) IsAccessTokenRevoked(
+	ctx context.Context,
+	tokenID string,
+) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM revoked_tokens
+			WHERE jti = $1
+		)
+	`
+
+	var revoked bool
+	err := r.db.QueryRowContext(ctx, query, tokenID).Scan(&revoked)
+	if err != nil {
+		return false, repository.ErrInternal
+	}
+
+	return revoked, nil
+}
+
+// PurgeRevokedAccessTokens deletes up to limit revoked access tokens that expired before
+// expiredBefore, which no longer need to be rejected, and returns how many were deleted
+func (r *tokenRepository) PurgeRevokedAccessTokens(
+	ctx context.Context,
+	expiredBefore time.Time,
+	limit int,
+) (int, error) {
+	query := `
+		DELETE FROM revoked_tokens
+		WHERE jti IN (
+			SELECT jti
+			FROM revoked_tokens
+			WHERE expires_at < $1
+			ORDER BY expires_at ASC
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, expiredBefore, limit)
+	if err != nil {
+		return 0, repository.ErrInternal
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, repository.ErrInternal
+	}
+
+	return int(purged), nil
+}
+
+// PurgeRefreshTokens deletes up to limit refresh tokens that expired before
+// expiredBefore, which can no longer be used or reused, and returns how many were
+// deleted
+func (r *tokenRepository) PurgeRefreshTokens(
+	ctx context.Context,
+	expiredBefore time.Time,
+	limit int,
+) (int, error) {
+	query := `
+		DELETE FROM refresh_tokens
+		WHERE id IN (
+			SELECT id
+			FROM refresh_tokens
+			WHERE expires_at < $1
+			ORDER BY expires_at ASC
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, expiredBefore, limit)
+	if err != nil {
+		return 0, repository.ErrInternal
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, repository.ErrInternal
+	}
+
+	return int(purged), nil
+}
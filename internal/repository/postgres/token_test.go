@@ -0,0 +1,446 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/Nilesh2000/conduit/internal/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func Test_tokenRepository_FindRefreshTokenByHash(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		mockSetup     func(mock sqlmock.Sqlmock)
+		expectedErr   error
+		validateToken func(*testing.T, *repository.RefreshToken)
+	}{
+		{
+			name: "Active token found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT (.+) FROM refresh_tokens WHERE token_hash = \$1`).
+					WithArgs("hash").
+					WillReturnRows(sqlmock.NewRows([]string{
+						"id", "user_id", "token_hash", "family_id", "access_token_id",
+						"access_token_expires_at", "expires_at", "revoked_at", "created_at",
+					}).AddRow(1, 2, "hash", "family-id", "token-id", time.Now(), time.Now(), nil, time.Now()))
+			},
+			expectedErr: nil,
+			validateToken: func(t *testing.T, token *repository.RefreshToken) {
+				if token.ID != 1 || token.UserID != 2 {
+					t.Errorf("Expected token 1 of user 2, got token %d of user %d", token.ID, token.UserID)
+				}
+				if token.FamilyID != "family-id" {
+					t.Errorf("Expected family %q, got %q", "family-id", token.FamilyID)
+				}
+				if token.RevokedAt != nil {
+					t.Errorf("Expected token not to be revoked, got %v", token.RevokedAt)
+				}
+			},
+		},
+		{
+			name: "Revoked token found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT (.+) FROM refresh_tokens WHERE token_hash = \$1`).
+					WithArgs("hash").
+					WillReturnRows(sqlmock.NewRows([]string{
+						"id", "user_id", "token_hash", "family_id", "access_token_id",
+						"access_token_expires_at", "expires_at", "revoked_at", "created_at",
+					}).AddRow(1, 2, "hash", "family-id", "token-id", time.Now(), time.Now(), time.Now(), time.Now()))
+			},
+			expectedErr: nil,
+			validateToken: func(t *testing.T, token *repository.RefreshToken) {
+				if token.RevokedAt == nil {
+					t.Error("Expected token to be revoked")
+				}
+			},
+		},
+		{
+			name: "Token not found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT (.+) FROM refresh_tokens WHERE token_hash = \$1`).
+					WithArgs("hash").
+					WillReturnRows(sqlmock.NewRows([]string{}))
+			},
+			expectedErr: repository.ErrRefreshTokenNotFound,
+		},
+		{
+			name: "Database error",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT (.+) FROM refresh_tokens WHERE token_hash = \$1`).
+					WithArgs("hash").
+					WillReturnError(errors.New("database error"))
+			},
+			expectedErr: repository.ErrInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock database for this test case
+			db, mock := setupTestDB(t)
+			defer func() {
+				if err := db.Close(); err != nil {
+					log.Printf("Error closing database connection: %v", err)
+				}
+			}()
+
+			// Setup mock expectations
+			tt.mockSetup(mock)
+
+			// Call FindRefreshTokenByHash method
+			repo := NewTokenRepository(db)
+			token, err := repo.FindRefreshTokenByHash(context.Background(), "hash")
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate token if no error
+			if err == nil && tt.validateToken != nil {
+				tt.validateToken(t, token)
+			}
+
+			// Ensure all expectations were met
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_tokenRepository_RotateRefreshToken(t *testing.T) {
+	t.Parallel()
+
+	next := repository.RefreshToken{
+		UserID:               2,
+		TokenHash:            "new-hash",
+		FamilyID:             "family-id",
+		AccessTokenID:        "new-token-id",
+		AccessTokenExpiresAt: time.Now().Add(15 * time.Minute),
+		ExpiresAt:            time.Now().Add(720 * time.Hour),
+	}
+
+	tests := []struct {
+		name        string
+		mockSetup   func(mock sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name: "Successful rotation",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE refresh_tokens SET revoked_at = \$1 WHERE id = \$2 AND revoked_at IS NULL`).
+					WithArgs(sqlmock.AnyArg(), int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`INSERT INTO refresh_tokens`).
+					WithArgs(
+						int64(2), "new-hash", "family-id", "new-token-id",
+						sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+					).
+					WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Token already rotated",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE refresh_tokens SET revoked_at = \$1 WHERE id = \$2 AND revoked_at IS NULL`).
+					WithArgs(sqlmock.AnyArg(), int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			expectedErr: repository.ErrRefreshTokenReused,
+		},
+		{
+			name: "Insert fails",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE refresh_tokens SET revoked_at = \$1 WHERE id = \$2 AND revoked_at IS NULL`).
+					WithArgs(sqlmock.AnyArg(), int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`INSERT INTO refresh_tokens`).
+					WillReturnError(errors.New("database error"))
+				mock.ExpectRollback()
+			},
+			expectedErr: repository.ErrInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock database for this test case
+			db, mock := setupTestDB(t)
+			defer func() {
+				if err := db.Close(); err != nil {
+					log.Printf("Error closing database connection: %v", err)
+				}
+			}()
+
+			// Setup mock expectations
+			tt.mockSetup(mock)
+
+			// Call RotateRefreshToken method
+			repo := NewTokenRepository(db)
+			err := repo.RotateRefreshToken(context.Background(), 1, next)
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Ensure all expectations were met
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_tokenRepository_RevokeRefreshTokenFamily(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		mockSetup   func(mock sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name: "Family revoked with its access tokens",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE refresh_tokens SET revoked_at = \$1 WHERE family_id = \$2 AND revoked_at IS NULL`).
+					WithArgs(sqlmock.AnyArg(), "family-id").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(`INSERT INTO revoked_tokens \(jti, expires_at\) SELECT access_token_id, access_token_expires_at FROM refresh_tokens`).
+					WithArgs("family-id", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Database error",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE refresh_tokens SET revoked_at`).
+					WillReturnError(errors.New("database error"))
+				mock.ExpectRollback()
+			},
+			expectedErr: repository.ErrInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock database for this test case
+			db, mock := setupTestDB(t)
+			defer func() {
+				if err := db.Close(); err != nil {
+					log.Printf("Error closing database connection: %v", err)
+				}
+			}()
+
+			// Setup mock expectations
+			tt.mockSetup(mock)
+
+			// Call RevokeRefreshTokenFamily method
+			repo := NewTokenRepository(db)
+			err := repo.RevokeRefreshTokenFamily(context.Background(), "family-id")
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Ensure all expectations were met
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_tokenRepository_IsAccessTokenRevoked(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		mockSetup       func(mock sqlmock.Sqlmock)
+		expectedErr     error
+		expectedRevoked bool
+	}{
+		{
+			name: "Revoked",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT EXISTS \( SELECT 1 FROM revoked_tokens WHERE jti = \$1 \)`).
+					WithArgs("token-id").
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+			},
+			expectedErr:     nil,
+			expectedRevoked: true,
+		},
+		{
+			name: "Not revoked",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT EXISTS \( SELECT 1 FROM revoked_tokens WHERE jti = \$1 \)`).
+					WithArgs("token-id").
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+			},
+			expectedErr:     nil,
+			expectedRevoked: false,
+		},
+		{
+			name: "Database error",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT EXISTS`).
+					WithArgs("token-id").
+					WillReturnError(errors.New("database error"))
+			},
+			expectedErr: repository.ErrInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock database for this test case
+			db, mock := setupTestDB(t)
+			defer func() {
+				if err := db.Close(); err != nil {
+					log.Printf("Error closing database connection: %v", err)
+				}
+			}()
+
+			// Setup mock expectations
+			tt.mockSetup(mock)
+
+			// Call IsAccessTokenRevoked method
+			repo := NewTokenRepository(db)
+			revoked, err := repo.IsAccessTokenRevoked(context.Background(), "token-id")
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate result
+			if revoked != tt.expectedRevoked {
+				t.Errorf("Expected revoked %v, got %v", tt.expectedRevoked, revoked)
+			}
+
+			// Ensure all expectations were met
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_tokenRepository_PurgeExpired(t *testing.T) {
+	t.Parallel()
+
+	expiredBefore := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		purge          func(r *tokenRepository) (int, error)
+		mockSetup      func(mock sqlmock.Sqlmock)
+		expectedErr    error
+		expectedPurged int
+	}{
+		{
+			name: "Revoked access tokens purged",
+			purge: func(r *tokenRepository) (int, error) {
+				return r.PurgeRevokedAccessTokens(context.Background(), expiredBefore, 100)
+			},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`DELETE FROM revoked_tokens WHERE jti IN \( SELECT jti FROM revoked_tokens WHERE expires_at < \$1 ORDER BY expires_at ASC LIMIT \$2 FOR UPDATE SKIP LOCKED \)`).
+					WithArgs(expiredBefore, 100).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+			},
+			expectedErr:    nil,
+			expectedPurged: 3,
+		},
+		{
+			name: "Refresh tokens purged",
+			purge: func(r *tokenRepository) (int, error) {
+				return r.PurgeRefreshTokens(context.Background(), expiredBefore, 100)
+			},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`DELETE FROM refresh_tokens WHERE id IN \( SELECT id FROM refresh_tokens WHERE expires_at < \$1 ORDER BY expires_at ASC LIMIT \$2 FOR UPDATE SKIP LOCKED \)`).
+					WithArgs(expiredBefore, 100).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+			expectedErr:    nil,
+			expectedPurged: 2,
+		},
+		{
+			name: "Database error",
+			purge: func(r *tokenRepository) (int, error) {
+				return r.PurgeRefreshTokens(context.Background(), expiredBefore, 100)
+			},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`DELETE FROM refresh_tokens`).
+					WillReturnError(errors.New("database error"))
+			},
+			expectedErr: repository.ErrInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock database for this test case
+			db, mock := setupTestDB(t)
+			defer func() {
+				if err := db.Close(); err != nil {
+					log.Printf("Error closing database connection: %v", err)
+				}
+			}()
+
+			// Setup mock expectations
+			tt.mockSetup(mock)
+
+			// Call the purge method
+			purged, err := tt.purge(NewTokenRepository(db))
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate result
+			if purged != tt.expectedPurged {
+				t.Errorf("Expected %d tokens purged, got %d", tt.expectedPurged, purged)
+			}
+
+			// Ensure all expectations were met
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
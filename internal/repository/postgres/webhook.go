@@ -0,0 +1,448 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Nilesh2000/conduit/internal/repository"
+
+	"github.com/lib/pq"
+)
+
+// webhookDeliveryKeyset pages through the deliveries of a webhook, newest first
+var webhookDeliveryKeyset = keyset{createdAt: "d.created_at", id: "d.id", descending: true}
+
+// webhookColumns are the webhook columns read by scanWebhook
+const webhookColumns = "id, user_id, url, secret, events, active, created_at, updated_at"
+
+// webhookDeliveryColumns are the delivery columns read by scanWebhookDelivery, for
+// deliveries selected as d
+const webhookDeliveryColumns = `
+	d.id, d.webhook_id, d.event, d.payload, d.status, d.attempts, d.response_status,
+	d.last_error, d.next_attempt_at, d.last_attempt_at, d.created_at
+`
+
+// webhookRepository implements the repository.webhookRepository using PostgreSQL
+type webhookRepository struct {
+	db *sql.DB
+}
+
+// NewWebhookRepository creates a new webhook repository
+func NewWebhookRepository(db *sql.DB) *webhookRepository {
+	return &webhookRepository{db: db}
+}
+
+// Create creates a webhook
+func (r *webhookRepository) Create(
+	ctx context.Context,
+	webhook repository.Webhook,
+) (*repository.Webhook, error) {
+	query := `
+		INSERT INTO webhooks (user_id, url, secret, events, active)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + webhookColumns
+
+	created, err := scanWebhook(r.db.QueryRowContext(
+		ctx,
+		query,
+		webhook.UserID,
+		webhook.URL,
+		webhook.Secret,
+		pq.Array(webhook.Events),
+		webhook.Active,
+	))
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" && pqErr.Constraint == "webhooks_user_id_fkey" {
+			return nil, repository.ErrUserNotFound
+		}
+		return nil, repository.ErrInternal
+	}
+
+	return created, nil
+}
+
+// List lists the webhooks of a user, oldest first
+func (r *webhookRepository) List(ctx context.Context, userID int64) ([]repository.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE user_id = $1 ORDER BY created_at ASC, id ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, repository.ErrInternal
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("error closing rows: %v", err)
+		}
+	}()
+
+	webhooks := []repository.Webhook{}
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, repository.ErrInternal
+		}
+		webhooks = append(webhooks, *webhook)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, repository.ErrInternal
+	}
+
+	return webhooks, nil
+}
+
+// Update updates a webhook of a user, leaving the URL, events and active flag that are
+// nil as they are
+func (r *webhookRepository) Update(
+	ctx context.Context,
+	userID, webhookID int64,
+	url *string,
+	events []string,
+	active *bool,
+) (*repository.Webhook, error) {
+	query := `
+		UPDATE webhooks
+		SET
+			url = COALESCE($3, url),
+			events = COALESCE($4, events),
+			active = COALESCE($5, active),
+			updated_at = $6
+		WHERE id = $1 AND user_id = $2
+		RETURNING ` + webhookColumns
+
+	webhook, err := scanWebhook(r.db.QueryRowContext(
+		ctx,
+		query,
+		webhookID,
+		userID,
+		url,
+		pq.Array(events),
+		active,
+		time.Now(),
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrWebhookNotFound
+		}
+		return nil, repository.ErrInternal
+	}
+
+	return webhook, nil
+}
+
+// Delete deletes a webhook of a user along with its deliveries
+func (r *webhookRepository) Delete(ctx context.Context, userID, webhookID int64) error {
+	query := `DELETE FROM webhooks WHERE id = $1 AND user_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, webhookID, userID)
+	if err != nil {
+		return repository.ErrInternal
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return repository.ErrInternal
+	}
+	if rowsAffected == 0 {
+		return repository.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// Enqueue queues a delivery of an event to every active webhook of a user that
+// subscribes to it, returning how many were queued. eventID is the outbox event the
+// delivery is queued for, which is only queued once per webhook however many times it
+// is handled.
+func (r *webhookRepository) Enqueue(
+	ctx context.Context,
+	userID int64,
+	eventID int64,
+	event string,
+	payload []byte,
+) (int, error) {
+	query := `
+		INSERT INTO webhook_deliveries (webhook_id, event_id, event, payload)
+		SELECT id, $2, $3, $4
+		FROM webhooks
+		WHERE user_id = $1 AND active AND $3 = ANY(events)
+		ON CONFLICT (webhook_id, event_id) DO NOTHING
+	`
+
+	result, err := r.db.ExecContext(ctx, query, userID, eventID, event, payload)
+	if err != nil {
+		return 0, repository.ErrInternal
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, repository.ErrInternal
+	}
+
+	return int(rowsAffected), nil
+}
+
+// ClaimDue claims up to limit pending deliveries to active webhooks that are due at now,
+// oldest first. Claimed deliveries are not due again until leaseUntil, so that other
+// replicas leave them alone while they are sent, and are retried then if the attempt is
+// never recorded.
+func (r *webhookRepository) ClaimDue(
+	ctx context.Context,
+	now, leaseUntil time.Time,
+	limit int,
+) ([]repository.WebhookDelivery, error) {
+	query := `
+		WITH due AS (
+			SELECT d.id
+			FROM webhook_deliveries d
+			JOIN webhooks w ON w.id = d.webhook_id
+			WHERE d.status = 'pending' AND d.next_attempt_at <= $1 AND w.active
+			ORDER BY d.next_attempt_at ASC, d.id ASC
+			LIMIT $3
+			FOR UPDATE OF d SKIP LOCKED
+		),
+		claimed AS (
+			UPDATE webhook_deliveries
+			SET next_attempt_at = $2
+			FROM due
+			WHERE webhook_deliveries.id = due.id
+			RETURNING webhook_deliveries.*
+		)
+		SELECT ` + webhookDeliveryColumns + `, w.url, w.secret
+		FROM claimed d
+		JOIN webhooks w ON w.id = d.webhook_id
+		ORDER BY d.created_at ASC, d.id ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, now, leaseUntil, limit)
+	if err != nil {
+		return nil, repository.ErrInternal
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("error closing rows: %v", err)
+		}
+	}()
+
+	deliveries := []repository.WebhookDelivery{}
+	for rows.Next() {
+		var url, secret string
+		delivery, err := scanWebhookDelivery(rows, &url, &secret)
+		if err != nil {
+			return nil, repository.ErrInternal
+		}
+		delivery.URL = url
+		delivery.Secret = secret
+		deliveries = append(deliveries, *delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, repository.ErrInternal
+	}
+
+	return deliveries, nil
+}
+
+// RecordAttempt records the outcome of sending a delivery and counts the attempt
+func (r *webhookRepository) RecordAttempt(
+	ctx context.Context,
+	deliveryID int64,
+	attempt repository.WebhookAttempt,
+) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET
+			attempts = attempts + 1,
+			status = $2,
+			response_status = $3,
+			last_error = $4,
+			last_attempt_at = $5,
+			next_attempt_at = $6
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(
+		ctx,
+		query,
+		deliveryID,
+		attempt.Status,
+		attempt.ResponseStatus,
+		attempt.Error,
+		attempt.AttemptedAt,
+		attempt.NextAttemptAt,
+	)
+	if err != nil {
+		return repository.ErrInternal
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return repository.ErrInternal
+	}
+	if rowsAffected == 0 {
+		return repository.ErrWebhookDeliveryNotFound
+	}
+
+	return nil
+}
+
+// ListDeliveries lists a page of the deliveries of a webhook of a user, newest first
+func (r *webhookRepository) ListDeliveries(
+	ctx context.Context,
+	userID, webhookID int64,
+	filters repository.WebhookDeliveryFilters,
+) (*repository.WebhookDeliveryListResult, error) {
+	if err := r.checkOwner(ctx, userID, webhookID); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries d WHERE d.webhook_id = $1`
+	args := []any{webhookID}
+
+	// Narrow the deliveries down to the page after or before the cursor
+	backwards := filters.Before != nil
+	if condition, cursorArgs := webhookDeliveryKeyset.condition(filters.After, filters.Before, len(args)+1); condition != "" {
+		query += " AND " + condition
+		args = append(args, cursorArgs...)
+	}
+	query += " ORDER BY " + webhookDeliveryKeyset.orderBy(backwards)
+
+	// Read one more delivery to tell whether another page follows
+	if filters.Limit > 0 {
+		args = append(args, filters.Limit+1)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, repository.ErrInternal
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("error closing rows: %v", err)
+		}
+	}()
+
+	deliveries := []repository.WebhookDelivery{}
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, repository.ErrInternal
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, repository.ErrInternal
+	}
+
+	deliveries, hasMore := trimPage(deliveries, filters.Limit, backwards)
+
+	return &repository.WebhookDeliveryListResult{
+		Deliveries: deliveries,
+		HasMore:    hasMore,
+	}, nil
+}
+
+// Replay queues a delivery of a webhook of a user again. The new delivery sends the same
+// event and payload, and the original is kept in the log as it is.
+func (r *webhookRepository) Replay(
+	ctx context.Context,
+	userID, webhookID, deliveryID int64,
+) (*repository.WebhookDelivery, error) {
+	if err := r.checkOwner(ctx, userID, webhookID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		WITH d AS (
+			INSERT INTO webhook_deliveries (webhook_id, event, payload)
+			SELECT webhook_id, event, payload
+			FROM webhook_deliveries
+			WHERE id = $1 AND webhook_id = $2
+			RETURNING *
+		)
+		SELECT ` + webhookDeliveryColumns + ` FROM d`
+
+	delivery, err := scanWebhookDelivery(r.db.QueryRowContext(ctx, query, deliveryID, webhookID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrWebhookDeliveryNotFound
+		}
+		return nil, repository.ErrInternal
+	}
+
+	return delivery, nil
+}
+
+// checkOwner checks that a webhook exists and belongs to a user
+func (r *webhookRepository) checkOwner(ctx context.Context, userID, webhookID int64) error {
+	query := `SELECT EXISTS (SELECT 1 FROM webhooks WHERE id = $1 AND user_id = $2)`
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, query, webhookID, userID).Scan(&exists); err != nil {
+		return repository.ErrInternal
+	}
+	if !exists {
+		return repository.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// scanWebhook scans a webhook
+func scanWebhook(row rowScanner) (*repository.Webhook, error) {
+	var webhook repository.Webhook
+	if err := row.Scan(
+		&webhook.ID,
+		&webhook.UserID,
+		&webhook.URL,
+		&webhook.Secret,
+		pq.Array(&webhook.Events),
+		&webhook.Active,
+		&webhook.CreatedAt,
+		&webhook.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+// scanWebhookDelivery scans a webhook delivery, followed by the extra columns in dest
+func scanWebhookDelivery(row rowScanner, dest ...any) (*repository.WebhookDelivery, error) {
+	var delivery repository.WebhookDelivery
+	var responseStatus sql.NullInt64
+	var lastAttemptAt sql.NullTime
+
+	if err := row.Scan(append([]any{
+		&delivery.ID,
+		&delivery.WebhookID,
+		&delivery.Event,
+		&delivery.Payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&responseStatus,
+		&delivery.LastError,
+		&delivery.NextAttemptAt,
+		&lastAttemptAt,
+		&delivery.CreatedAt,
+	}, dest...)...); err != nil {
+		return nil, err
+	}
+
+	// Handle nullable values
+	if responseStatus.Valid {
+		status := int(responseStatus.Int64)
+		delivery.ResponseStatus = &status
+	}
+	if lastAttemptAt.Valid {
+		delivery.LastAttemptAt = &lastAttemptAt.Time
+	}
+
+	return &delivery, nil
+}
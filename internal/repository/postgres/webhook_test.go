@@ -0,0 +1,399 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"log"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Nilesh2000/conduit/internal/repository"
+
+	"github.com/lib/pq"
+)
+
+// webhookDeliveryRowColumns are the columns of a scanned webhook delivery
+var webhookDeliveryRowColumns = []string{
+	"id", "webhook_id", "event", "payload", "status", "attempts", "response_status",
+	"last_error", "next_attempt_at", "last_attempt_at", "created_at",
+}
+
+func Test_webhookRepository_Create(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	columns := []string{"id", "user_id", "url", "secret", "events", "active", "created_at", "updated_at"}
+
+	// Define test cases
+	tests := []struct {
+		name        string
+		mockSetup   func(mock sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name: "Webhook created",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`INSERT INTO webhooks \(user_id, url, secret, events, active\) VALUES \(\$1, \$2, \$3, \$4, \$5\) RETURNING id, user_id, url, secret, events, active, created_at, updated_at`).
+					WithArgs(int64(1), "https://example.com/hook", "whsec_test", sqlmock.AnyArg(), true).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(3, 1, "https://example.com/hook", "whsec_test", "{article.published,comment.created}", true, now, now))
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "User not found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`INSERT INTO webhooks`).
+					WithArgs(int64(1), "https://example.com/hook", "whsec_test", sqlmock.AnyArg(), true).
+					WillReturnError(&pq.Error{Code: "23503", Constraint: "webhooks_user_id_fkey"})
+			},
+			expectedErr: repository.ErrUserNotFound,
+		},
+		{
+			name: "Database error",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`INSERT INTO webhooks`).
+					WithArgs(int64(1), "https://example.com/hook", "whsec_test", sqlmock.AnyArg(), true).
+					WillReturnError(errors.New("database error"))
+			},
+			expectedErr: repository.ErrInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock database for this test case
+			db, mock := setupTestDB(t)
+			defer func() {
+				if err := db.Close(); err != nil {
+					log.Printf("Error closing database connection: %v", err)
+				}
+			}()
+
+			// Setup mock expectations
+			tt.mockSetup(mock)
+
+			// Create repository with mock database
+			repo := NewWebhookRepository(db)
+
+			// Call Create method
+			webhook, err := repo.Create(context.Background(), repository.Webhook{
+				UserID: 1,
+				URL:    "https://example.com/hook",
+				Secret: "whsec_test",
+				Events: []string{repository.WebhookEventArticlePublished, repository.WebhookEventCommentCreated},
+				Active: true,
+			})
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate webhook
+			if err == nil {
+				expectedEvents := []string{"article.published", "comment.created"}
+				if webhook.ID != 3 || !reflect.DeepEqual(webhook.Events, expectedEvents) {
+					t.Errorf("Expected webhook 3 with events %v, got %+v", expectedEvents, webhook)
+				}
+			}
+
+			// Ensure all expectations were met
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_webhookRepository_Update(t *testing.T) {
+	t.Parallel()
+
+	// Setup mock database
+	db, mock := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Printf("Error closing database connection: %v", err)
+		}
+	}()
+
+	// A webhook that does not exist, or belongs to someone else, is not found
+	active := false
+	mock.ExpectQuery(`UPDATE webhooks SET url = COALESCE\(\$3, url\), events = COALESCE\(\$4, events\), active = COALESCE\(\$5, active\), updated_at = \$6 WHERE id = \$1 AND user_id = \$2 RETURNING`).
+		WithArgs(int64(3), int64(1), nil, nil, &active, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	// Call Update method
+	repo := NewWebhookRepository(db)
+	_, err := repo.Update(context.Background(), 1, 3, nil, nil, &active)
+
+	// Validate error
+	if !errors.Is(err, repository.ErrWebhookNotFound) {
+		t.Errorf("Expected error %v, got %v", repository.ErrWebhookNotFound, err)
+	}
+
+	// Ensure all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func Test_webhookRepository_Enqueue(t *testing.T) {
+	t.Parallel()
+
+	// Setup mock database
+	db, mock := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Printf("Error closing database connection: %v", err)
+		}
+	}()
+
+	payload := []byte(`{"event":"article.published"}`)
+	mock.ExpectExec(`INSERT INTO webhook_deliveries \(webhook_id, event_id, event, payload\) SELECT id, \$2, \$3, \$4 FROM webhooks WHERE user_id = \$1 AND active AND \$3 = ANY\(events\) ON CONFLICT \(webhook_id, event_id\) DO NOTHING`).
+		WithArgs(int64(1), int64(5), "article.published", payload).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	// Call Enqueue method
+	repo := NewWebhookRepository(db)
+	queued, err := repo.Enqueue(context.Background(), 1, 5, repository.WebhookEventArticlePublished, payload)
+
+	// Validate result
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if queued != 2 {
+		t.Errorf("Expected 2 deliveries queued, got %d", queued)
+	}
+
+	// Ensure all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func Test_webhookRepository_ClaimDue(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	leaseUntil := now.Add(time.Minute)
+
+	// Setup mock database
+	db, mock := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Printf("Error closing database connection: %v", err)
+		}
+	}()
+
+	rows := sqlmock.NewRows(append(webhookDeliveryRowColumns, "url", "secret")).
+		AddRow(5, 3, "comment.created", []byte(`{}`), "pending", 2, 500, "unexpected status 500", leaseUntil, now.Add(-time.Minute), now.Add(-time.Hour), "https://example.com/hook", "whsec_test")
+	mock.ExpectQuery(`WITH due AS \( SELECT d.id FROM webhook_deliveries d JOIN webhooks w ON w.id = d.webhook_id WHERE d.status = 'pending' AND d.next_attempt_at <= \$1 AND w.active ORDER BY d.next_attempt_at ASC, d.id ASC LIMIT \$3 FOR UPDATE OF d SKIP LOCKED \), claimed AS \( UPDATE webhook_deliveries SET next_attempt_at = \$2 .*\) SELECT d.id, .*, w.url, w.secret FROM claimed d JOIN webhooks w ON w.id = d.webhook_id`).
+		WithArgs(now, leaseUntil, 10).
+		WillReturnRows(rows)
+
+	// Call ClaimDue method
+	repo := NewWebhookRepository(db)
+	deliveries, err := repo.ClaimDue(context.Background(), now, leaseUntil, 10)
+
+	// Validate result
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(deliveries) != 1 {
+		t.Fatalf("Expected 1 delivery, got %d", len(deliveries))
+	}
+	delivery := deliveries[0]
+	if delivery.ID != 5 || delivery.Attempts != 2 || delivery.URL != "https://example.com/hook" || delivery.Secret != "whsec_test" {
+		t.Errorf("Unexpected delivery %+v", delivery)
+	}
+	if delivery.ResponseStatus == nil || *delivery.ResponseStatus != 500 {
+		t.Errorf("Expected response status 500, got %v", delivery.ResponseStatus)
+	}
+
+	// Ensure all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func Test_webhookRepository_ListDeliveries(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// Define test cases
+	tests := []struct {
+		name            string
+		mockSetup       func(mock sqlmock.Sqlmock)
+		expectedErr     error
+		expectedIDs     []int64
+		expectedHasMore bool
+	}{
+		{
+			name: "Deliveries listed",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM webhooks WHERE id = \$1 AND user_id = \$2\)`).
+					WithArgs(int64(3), int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectQuery(`SELECT d.id, .* FROM webhook_deliveries d WHERE d.webhook_id = \$1 ORDER BY d.created_at DESC, d.id DESC LIMIT \$2`).
+					WithArgs(int64(3), 3).
+					WillReturnRows(sqlmock.NewRows(webhookDeliveryRowColumns).
+						AddRow(7, 3, "article.updated", []byte(`{}`), "pending", 0, nil, "", now, nil, now).
+						AddRow(6, 3, "article.published", []byte(`{}`), "succeeded", 1, 204, "", now, now, now.Add(-time.Hour)).
+						AddRow(5, 3, "article.published", []byte(`{}`), "failed", 8, 500, "unexpected status 500", now, now, now.Add(-2*time.Hour)))
+			},
+			expectedIDs:     []int64{7, 6},
+			expectedHasMore: true,
+		},
+		{
+			name: "Webhook not found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM webhooks`).
+					WithArgs(int64(3), int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+			},
+			expectedErr: repository.ErrWebhookNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock database for this test case
+			db, mock := setupTestDB(t)
+			defer func() {
+				if err := db.Close(); err != nil {
+					log.Printf("Error closing database connection: %v", err)
+				}
+			}()
+
+			// Setup mock expectations
+			tt.mockSetup(mock)
+
+			// Create repository with mock database
+			repo := NewWebhookRepository(db)
+
+			// Call ListDeliveries method
+			result, err := repo.ListDeliveries(context.Background(), 1, 3, repository.WebhookDeliveryFilters{Limit: 2})
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate page
+			if err == nil {
+				var ids []int64
+				for _, delivery := range result.Deliveries {
+					ids = append(ids, delivery.ID)
+				}
+				if !reflect.DeepEqual(ids, tt.expectedIDs) {
+					t.Errorf("Expected deliveries %v, got %v", tt.expectedIDs, ids)
+				}
+				if result.HasMore != tt.expectedHasMore {
+					t.Errorf("Expected has more %v, got %v", tt.expectedHasMore, result.HasMore)
+				}
+			}
+
+			// Ensure all expectations were met
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_webhookRepository_Replay(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// Define test cases
+	tests := []struct {
+		name        string
+		mockSetup   func(mock sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name: "Delivery replayed",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM webhooks`).
+					WithArgs(int64(3), int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectQuery(`WITH d AS \( INSERT INTO webhook_deliveries \(webhook_id, event, payload\) SELECT webhook_id, event, payload FROM webhook_deliveries WHERE id = \$1 AND webhook_id = \$2 RETURNING \* \) SELECT d.id, .* FROM d`).
+					WithArgs(int64(5), int64(3)).
+					WillReturnRows(sqlmock.NewRows(webhookDeliveryRowColumns).
+						AddRow(8, 3, "article.published", []byte(`{}`), "pending", 0, nil, "", now, nil, now))
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Delivery not found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM webhooks`).
+					WithArgs(int64(3), int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectQuery(`WITH d AS \( INSERT INTO webhook_deliveries`).
+					WithArgs(int64(5), int64(3)).
+					WillReturnRows(sqlmock.NewRows(webhookDeliveryRowColumns))
+			},
+			expectedErr: repository.ErrWebhookDeliveryNotFound,
+		},
+		{
+			name: "Webhook not found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM webhooks`).
+					WithArgs(int64(3), int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+			},
+			expectedErr: repository.ErrWebhookNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock database for this test case
+			db, mock := setupTestDB(t)
+			defer func() {
+				if err := db.Close(); err != nil {
+					log.Printf("Error closing database connection: %v", err)
+				}
+			}()
+
+			// Setup mock expectations
+			tt.mockSetup(mock)
+
+			// Create repository with mock database
+			repo := NewWebhookRepository(db)
+
+			// Call Replay method
+			delivery, err := repo.Replay(context.Background(), 1, 3, 5)
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate the new delivery
+			if err == nil && (delivery.ID != 8 || delivery.Status != repository.WebhookDeliveryPending) {
+				t.Errorf("Expected pending delivery 8, got %+v", delivery)
+			}
+
+			// Ensure all expectations were met
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
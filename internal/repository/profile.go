@@ -1,5 +1,7 @@
 package repository
 
+import "time"
+
 // Profile represents a user profile in the repository
 type Profile struct {
 	ID        int64
@@ -7,4 +9,30 @@ type Profile struct {
 	Bio       string
 	Image     string
 	Following bool
+	// Blocking reports whether the current user blocked the user, which is only set for
+	// profiles returned by blocking and unblocking
+	Blocking bool
+	// Muting reports whether the current user muted the user, which is only set for
+	// profiles returned by muting and unmuting
+	Muting bool
+	// FollowedAt is when the follow was made, which is only set for profiles listed as
+	// followers or followed users
+	FollowedAt time.Time
+}
+
+// FollowFilters represents the filters for listing the followers or followed users of a
+// user, newest follow first
+type FollowFilters struct {
+	Limit  int
+	After  *Cursor
+	Before *Cursor
+}
+
+// FollowListResult represents the result of listing followers or followed users. HasMore
+// reports whether more profiles follow the page in the direction it was read in, and
+// Count is the number of profiles across every page.
+type FollowListResult struct {
+	Profiles []Profile
+	HasMore  bool
+	Count    int
 }
@@ -0,0 +1,14 @@
+package repository
+
+import "time"
+
+// ArticleRevision represents a saved version of an article in the repository
+type ArticleRevision struct {
+	ID          int64
+	ArticleID   int64
+	Number      int
+	Title       string
+	Description string
+	Body        string
+	CreatedAt   time.Time
+}
@@ -0,0 +1,16 @@
+package repository
+
+import "time"
+
+// RefreshToken represents a refresh token in the repository
+type RefreshToken struct {
+	ID                   int64
+	UserID               int64
+	TokenHash            string
+	FamilyID             string
+	AccessTokenID        string
+	AccessTokenExpiresAt time.Time
+	ExpiresAt            time.Time
+	RevokedAt            *time.Time
+	CreatedAt            time.Time
+}
@@ -0,0 +1,85 @@
+package repository
+
+import "time"
+
+// Webhook events
+const (
+	WebhookEventArticlePublished = "article.published"
+	WebhookEventArticleUpdated   = "article.updated"
+	WebhookEventArticleDeleted   = "article.deleted"
+	WebhookEventCommentCreated   = "comment.created"
+)
+
+// WebhookEvents are the events webhooks can subscribe to
+var WebhookEvents = []string{
+	WebhookEventArticlePublished,
+	WebhookEventArticleUpdated,
+	WebhookEventArticleDeleted,
+	WebhookEventCommentCreated,
+}
+
+// Webhook delivery statuses
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// Webhook represents a URL a user has events about their articles sent to. Payloads are
+// signed with Secret so that the receiver can tell they came from us.
+type Webhook struct {
+	ID        int64
+	UserID    int64
+	URL       string
+	Secret    string
+	Events    []string
+	Active    bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// WebhookDelivery represents an event queued to be sent to a webhook. Pending deliveries
+// are sent once NextAttemptAt is due, and are retried until they succeed or fail for
+// good.
+type WebhookDelivery struct {
+	ID             int64
+	WebhookID      int64
+	Event          string
+	Payload        []byte
+	Status         string
+	Attempts       int
+	ResponseStatus *int
+	LastError      string
+	NextAttemptAt  time.Time
+	LastAttemptAt  *time.Time
+	CreatedAt      time.Time
+	// URL and Secret are those of the webhook, and are only set for deliveries that were
+	// claimed to be sent
+	URL    string
+	Secret string
+}
+
+// WebhookAttempt represents the outcome of sending a delivery. Status is the status the
+// delivery is left in, and NextAttemptAt when it is retried if it is still pending.
+type WebhookAttempt struct {
+	AttemptedAt    time.Time
+	ResponseStatus *int
+	Error          string
+	Status         string
+	NextAttemptAt  time.Time
+}
+
+// WebhookDeliveryFilters represents the filters for listing the deliveries of a webhook,
+// newest first
+type WebhookDeliveryFilters struct {
+	Limit  int
+	After  *Cursor
+	Before *Cursor
+}
+
+// WebhookDeliveryListResult represents the result of listing webhook deliveries. HasMore
+// reports whether more deliveries follow the page in the direction it was read in.
+type WebhookDeliveryListResult struct {
+	Deliveries []WebhookDelivery
+	HasMore    bool
+}
@@ -3,25 +3,37 @@ package service
 import (
 	"context"
 	"errors"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/Nilesh2000/conduit/internal/repository"
-
-	"github.com/gosimple/slug"
 )
 
 // Article represents a article
 type Article struct {
-	Slug           string    `json:"slug"`
-	Title          string    `json:"title"`
-	Description    string    `json:"description"`
-	Body           string    `json:"body"`
-	TagList        []string  `json:"tagList"`
-	CreatedAt      time.Time `json:"createdAt"`
-	UpdatedAt      time.Time `json:"updatedAt"`
-	Favorited      bool      `json:"favorited"`
-	FavoritesCount int       `json:"favoritesCount"`
-	Author         Profile   `json:"author"`
+	Slug           string            `json:"slug"`
+	Title          string            `json:"title"`
+	Description    string            `json:"description"`
+	Body           string            `json:"body"`
+	TagList        []string          `json:"tagList"`
+	CreatedAt      time.Time         `json:"createdAt"`
+	UpdatedAt      time.Time         `json:"updatedAt"`
+	Favorited      bool              `json:"favorited"`
+	FavoritesCount int               `json:"favoritesCount"`
+	Author         Profile           `json:"author"`
+	Status         string            `json:"status"`
+	PublishAt      *time.Time        `json:"publishAt"`
+	PublishedAt    *time.Time        `json:"publishedAt"`
+	Highlight      *ArticleHighlight `json:"highlight,omitempty"`
+}
+
+// ArticleHighlight represents the HTML escaped text of an article matching a search,
+// with the matching words wrapped in <mark> tags
+type ArticleHighlight struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Body        string `json:"body"`
 }
 
 // ArticleRepository is an interface for the article repository
@@ -29,19 +41,42 @@ type ArticleRepository interface {
 	Create(
 		ctx context.Context,
 		userID int64,
-		slug, title, description, body string,
+		slugs []string,
+		title, description, body, status string,
+		publishAt *time.Time,
 		tagList []string,
 	) (*repository.Article, error)
 	GetBySlug(
 		ctx context.Context,
 		slug string,
 	) (*repository.Article, error)
+	GetByID(
+		ctx context.Context,
+		articleID int64,
+	) (*repository.Article, error)
+	GetByPreviousSlug(
+		ctx context.Context,
+		slug string,
+	) (*repository.Article, error)
 	Update(
 		ctx context.Context,
 		userID int64,
 		slug string,
+		newSlugs []string,
 		title, description, body *string,
+		tags repository.TagChanges,
+	) (*repository.Article, error)
+	SetStatus(
+		ctx context.Context,
+		articleID int64,
+		status string,
+		publishAt *time.Time,
 	) (*repository.Article, error)
+	PublishDue(
+		ctx context.Context,
+		now time.Time,
+		limit int,
+	) ([]*repository.Article, error)
 	Delete(
 		ctx context.Context,
 		articleID int64,
@@ -73,44 +108,63 @@ type ArticleRepository interface {
 	GetArticlesFeed(
 		ctx context.Context,
 		userID int64,
-		limit, offset int,
+		filters repository.FeedFilters,
 	) (*repository.ArticleListResult, error)
+	IsInFeed(ctx context.Context, userID int64, articleID int64) (bool, error)
 }
 
 // articleService implements the articleService interface
 type articleService struct {
 	articleRepository ArticleRepository
 	profileRepository ProfileRepository
+	slugGenerator     *SlugGenerator
 }
 
 // NewArticleService creates a new ArticleService
 func NewArticleService(
 	articleRepository ArticleRepository,
 	profileRepository ProfileRepository,
+	slugGenerator *SlugGenerator,
 ) *articleService {
 	return &articleService{
 		articleRepository: articleRepository,
 		profileRepository: profileRepository,
+		slugGenerator:     slugGenerator,
 	}
 }
 
-// CreateArticle creates a new article
+// CreateArticle creates a new article. Articles are published right away unless they
+// are created as drafts or scheduled to be published at publishAt.
 func (s *articleService) CreateArticle(
 	ctx context.Context,
 	userID int64,
-	title, description, body string,
+	title, description, body, status string,
+	publishAt *time.Time,
 	tagList []string,
 ) (*Article, error) {
-	// Generate slug from title
-	slug := generateSlug(title)
+	// Generate the slugs to try from the title
+	slugs := s.slugGenerator.Candidates(title)
+	tagList = normalizeTags(tagList)
+
+	switch {
+	case publishAt != nil:
+		if !publishAt.After(time.Now()) {
+			return nil, ErrInvalidPublishAt
+		}
+		status = repository.ArticleStatusScheduled
+	case status == "":
+		status = repository.ArticleStatusPublished
+	}
 
 	article, err := s.articleRepository.Create(
 		ctx,
 		userID,
-		slug,
+		slugs,
 		title,
 		description,
 		body,
+		status,
+		publishAt,
 		tagList,
 	)
 	if err != nil {
@@ -140,6 +194,9 @@ func (s *articleService) CreateArticle(
 			Image:     article.Author.Image,
 			Following: false,
 		},
+		Status:      article.Status,
+		PublishAt:   article.PublishAt,
+		PublishedAt: article.PublishedAt,
 	}, nil
 }
 
@@ -149,17 +206,9 @@ func (s *articleService) GetArticle(
 	slug string,
 	currentUserID *int64,
 ) (*Article, error) {
-	article, err := s.articleRepository.GetBySlug(
-		ctx,
-		slug,
-	)
+	article, err := getViewableArticle(ctx, s.articleRepository, slug, currentUserID)
 	if err != nil {
-		switch {
-		case errors.Is(err, repository.ErrArticleNotFound):
-			return nil, ErrArticleNotFound
-		default:
-			return nil, ErrInternalServer
-		}
+		return nil, err
 	}
 
 	// Get favorites count
@@ -202,15 +251,21 @@ func (s *articleService) GetArticle(
 			Image:     article.Author.Image,
 			Following: following,
 		},
+		Status:      article.Status,
+		PublishAt:   article.PublishAt,
+		PublishedAt: article.PublishedAt,
 	}, nil
 }
 
-// UpdateArticle updates an article
+// UpdateArticle updates an article. A non-nil Replace list of tags replaces the tags of
+// the article, so an empty list removes all of them, and the Add and Remove lists link
+// and unlink single tags. Every list is normalized like the tags of a new article.
 func (s *articleService) UpdateArticle(
 	ctx context.Context,
 	userID int64,
 	slug string,
 	title, description, body *string,
+	tags repository.TagChanges,
 ) (*Article, error) {
 	article, err := s.articleRepository.GetBySlug(ctx, slug)
 	if err != nil {
@@ -232,14 +287,22 @@ func (s *articleService) UpdateArticle(
 		ctx,
 		userID,
 		slug,
+		s.slugGenerator.newSlugs(article, title),
 		title,
 		description,
 		body,
+		repository.TagChanges{
+			Replace: normalizeTags(tags.Replace),
+			Add:     normalizeTags(tags.Add),
+			Remove:  normalizeTags(tags.Remove),
+		},
 	)
 	if err != nil {
 		switch {
 		case errors.Is(err, repository.ErrArticleNotFound):
 			return nil, ErrArticleNotFound
+		case errors.Is(err, repository.ErrDuplicateSlug):
+			return nil, ErrArticleAlreadyExists
 		default:
 			return nil, ErrInternalServer
 		}
@@ -267,6 +330,9 @@ func (s *articleService) UpdateArticle(
 			Image:     article.Author.Image,
 			Following: false,
 		},
+		Status:      article.Status,
+		PublishAt:   article.PublishAt,
+		PublishedAt: article.PublishedAt,
 	}, nil
 }
 
@@ -310,14 +376,9 @@ func (s *articleService) FavoriteArticle(
 	userID int64,
 	slug string,
 ) (*Article, error) {
-	article, err := s.articleRepository.GetBySlug(ctx, slug)
+	article, err := getViewableArticle(ctx, s.articleRepository, slug, &userID)
 	if err != nil {
-		switch {
-		case errors.Is(err, repository.ErrArticleNotFound):
-			return nil, ErrArticleNotFound
-		default:
-			return nil, ErrInternalServer
-		}
+		return nil, err
 	}
 
 	// Favorite the article
@@ -361,6 +422,9 @@ func (s *articleService) FavoriteArticle(
 			Image:     article.Author.Image,
 			Following: following,
 		},
+		Status:      article.Status,
+		PublishAt:   article.PublishAt,
+		PublishedAt: article.PublishedAt,
 	}, nil
 }
 
@@ -370,14 +434,9 @@ func (s *articleService) UnfavoriteArticle(
 	userID int64,
 	slug string,
 ) (*Article, error) {
-	article, err := s.articleRepository.GetBySlug(ctx, slug)
+	article, err := getViewableArticle(ctx, s.articleRepository, slug, &userID)
 	if err != nil {
-		switch {
-		case errors.Is(err, repository.ErrArticleNotFound):
-			return nil, ErrArticleNotFound
-		default:
-			return nil, ErrInternalServer
-		}
+		return nil, err
 	}
 
 	// Unfavorite the article
@@ -414,10 +473,14 @@ func (s *articleService) UnfavoriteArticle(
 			Image:     article.Author.Image,
 			Following: following,
 		},
+		Status:      article.Status,
+		PublishAt:   article.PublishAt,
+		PublishedAt: article.PublishedAt,
 	}, nil
 }
 
-// ListArticles lists articles with optional filters
+// ListArticles lists articles with optional filters. The repository returns the tags,
+// favorites and following information of the articles along with them.
 func (s *articleService) ListArticles(
 	ctx context.Context,
 	filters repository.ArticleFilters,
@@ -428,38 +491,6 @@ func (s *articleService) ListArticles(
 		return nil, ErrInternalServer
 	}
 
-	// Process each article to add favorites and following information
-	for _, article := range result.Articles {
-		// Get favorites count
-		favoritesCount, err := s.articleRepository.GetFavoritesCount(ctx, article.ID)
-		if err != nil {
-			return nil, ErrInternalServer
-		}
-
-		// Check if user has favorited the article
-		favorited := false
-		if currentUserID != nil {
-			favorited, err = s.articleRepository.IsFavorited(ctx, *currentUserID, article.ID)
-			if err != nil {
-				return nil, ErrInternalServer
-			}
-		}
-
-		// Check if user is following the author
-		following := false
-		if currentUserID != nil && article.Author != nil {
-			following, err = s.profileRepository.IsFollowing(ctx, *currentUserID, article.Author.ID)
-			if err != nil {
-				return nil, ErrInternalServer
-			}
-		}
-
-		// Update the article with the calculated fields
-		article.Favorited = favorited
-		article.FavoritesCount = favoritesCount
-		article.Author.Following = following
-	}
-
 	return result, nil
 }
 
@@ -467,46 +498,212 @@ func (s *articleService) ListArticles(
 func (s *articleService) GetArticlesFeed(
 	ctx context.Context,
 	userID int64,
-	limit, offset int,
+	filters repository.FeedFilters,
 ) (*repository.ArticleListResult, error) {
-	result, err := s.articleRepository.GetArticlesFeed(ctx, userID, limit, offset)
+	result, err := s.articleRepository.GetArticlesFeed(ctx, userID, filters)
 	if err != nil {
 		return nil, ErrInternalServer
 	}
 
-	// Process each article to add favorites and following information
-	for _, article := range result.Articles {
-		// Get favorites count
-		favoritesCount, err := s.articleRepository.GetFavoritesCount(ctx, article.ID)
-		if err != nil {
-			return nil, ErrInternalServer
-		}
+	return result, nil
+}
 
-		// Check if user has favorited the article
-		favorited, err := s.articleRepository.IsFavorited(ctx, userID, article.ID)
-		if err != nil {
+// PublishArticle publishes an article
+func (s *articleService) PublishArticle(
+	ctx context.Context,
+	userID int64,
+	slug string,
+) (*Article, error) {
+	return s.setArticleStatus(ctx, userID, slug, repository.ArticleStatusPublished, nil)
+}
+
+// UnpublishArticle turns a published or archived article back into a draft
+func (s *articleService) UnpublishArticle(
+	ctx context.Context,
+	userID int64,
+	slug string,
+) (*Article, error) {
+	return s.setArticleStatus(ctx, userID, slug, repository.ArticleStatusDraft, nil)
+}
+
+// ArchiveArticle archives an article, hiding it from everyone but its author
+func (s *articleService) ArchiveArticle(
+	ctx context.Context,
+	userID int64,
+	slug string,
+) (*Article, error) {
+	return s.setArticleStatus(ctx, userID, slug, repository.ArticleStatusArchived, nil)
+}
+
+// ScheduleArticle schedules an article to be published automatically at publishAt.
+// Scheduling an article that is already scheduled moves its publication time.
+func (s *articleService) ScheduleArticle(
+	ctx context.Context,
+	userID int64,
+	slug string,
+	publishAt time.Time,
+) (*Article, error) {
+	if !publishAt.After(time.Now()) {
+		return nil, ErrInvalidPublishAt
+	}
+
+	return s.setArticleStatus(ctx, userID, slug, repository.ArticleStatusScheduled, &publishAt)
+}
+
+// PublishScheduledArticles publishes up to limit scheduled articles that are due at now
+// and returns their slugs
+func (s *articleService) PublishScheduledArticles(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+) ([]string, error) {
+	articles, err := s.articleRepository.PublishDue(ctx, now, limit)
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+
+	slugs := make([]string, 0, len(articles))
+	for _, article := range articles {
+		slugs = append(slugs, article.Slug)
+	}
+
+	return slugs, nil
+}
+
+// setArticleStatus changes the status of an article on behalf of its author
+func (s *articleService) setArticleStatus(
+	ctx context.Context,
+	userID int64,
+	slug string,
+	status string,
+	publishAt *time.Time,
+) (*Article, error) {
+	article, err := s.articleRepository.GetBySlug(ctx, slug)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrArticleNotFound):
+			return nil, ErrArticleNotFound
+		default:
 			return nil, ErrInternalServer
 		}
+	}
 
-		// Check if user is following the author
-		following := false
-		if article.Author != nil {
-			following, err = s.profileRepository.IsFollowing(ctx, userID, article.Author.ID)
-			if err != nil {
+	// Hide unpublished articles from everyone but their author
+	if !canView(article, &userID) {
+		return nil, ErrArticleNotFound
+	}
+
+	// Check if user is the author
+	if article.AuthorID != userID {
+		return nil, ErrArticleNotAuthorized
+	}
+
+	// Only change the status if needed, so republishing keeps the publication date
+	if article.Status != status || publishAt != nil {
+		article, err = s.articleRepository.SetStatus(ctx, article.ID, status, publishAt)
+		if err != nil {
+			switch {
+			case errors.Is(err, repository.ErrArticleNotFound):
+				return nil, ErrArticleNotFound
+			default:
 				return nil, ErrInternalServer
 			}
 		}
+	}
 
-		// Update the article with the calculated fields
-		article.Favorited = favorited
-		article.FavoritesCount = favoritesCount
-		article.Author.Following = following
+	// Get favorites count
+	favoritesCount, err := s.articleRepository.GetFavoritesCount(ctx, article.ID)
+	if err != nil {
+		return nil, ErrInternalServer
 	}
 
-	return result, nil
+	// Check if user has favorited the article
+	favorited, err := s.articleRepository.IsFavorited(ctx, userID, article.ID)
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+
+	return &Article{
+		Slug:           article.Slug,
+		Title:          article.Title,
+		Description:    article.Description,
+		Body:           article.Body,
+		TagList:        article.TagList,
+		CreatedAt:      article.CreatedAt,
+		UpdatedAt:      article.UpdatedAt,
+		Favorited:      favorited,
+		FavoritesCount: favoritesCount,
+		Author: Profile{
+			Username:  article.Author.Username,
+			Bio:       article.Author.Bio,
+			Image:     article.Author.Image,
+			Following: false,
+		},
+		Status:      article.Status,
+		PublishAt:   article.PublishAt,
+		PublishedAt: article.PublishedAt,
+	}, nil
+}
+
+// canView reports whether a user can see an article. Published articles are visible to
+// everyone, while drafts, scheduled and archived articles are only visible to their author.
+func canView(article *repository.Article, userID *int64) bool {
+	if article.Status == repository.ArticleStatusPublished {
+		return true
+	}
+	return userID != nil && *userID == article.AuthorID
+}
+
+// getViewableArticle gets an article by slug if the user can see it. An article that is
+// requested by one of its previous slugs fails with an ArticleMovedError.
+func getViewableArticle(
+	ctx context.Context,
+	articleRepository ArticleRepository,
+	slug string,
+	currentUserID *int64,
+) (*repository.Article, error) {
+	article, err := articleRepository.GetBySlug(ctx, slug)
+	if errors.Is(err, repository.ErrArticleNotFound) {
+		article, err = articleRepository.GetByPreviousSlug(ctx, slug)
+		if err == nil && canView(article, currentUserID) {
+			return nil, &ArticleMovedError{Slug: article.Slug}
+		}
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrArticleNotFound):
+			return nil, ErrArticleNotFound
+		default:
+			return nil, ErrInternalServer
+		}
+	}
+
+	// Hide unpublished articles from everyone but their author
+	if !canView(article, currentUserID) {
+		return nil, ErrArticleNotFound
+	}
+
+	return article, nil
 }
 
-// generateSlug generates a slug from a title
-func generateSlug(title string) string {
-	return slug.Make(title)
+// normalizeTags trims and lower-cases tags, dropping empty and duplicate ones. The tags
+// are sorted, which is the order they are read back in. A nil list stays nil.
+func normalizeTags(tagList []string) []string {
+	if tagList == nil {
+		return nil
+	}
+
+	normalized := make([]string, 0, len(tagList))
+	seen := make(map[string]bool, len(tagList))
+	for _, tag := range tagList {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	sort.Strings(normalized)
+
+	return normalized
 }
@@ -3,36 +3,48 @@ package service
 import (
 	"context"
 	"errors"
+	"reflect"
 	"testing"
 	"time"
 
+	"github.com/Nilesh2000/conduit/internal/config"
 	"github.com/Nilesh2000/conduit/internal/repository"
 
 	"github.com/gosimple/slug"
 )
 
+// testSlugGenerator is the slug generator used by the services under test
+var testSlugGenerator = NewSlugGenerator(config.SlugStrategySuffix, 3)
+
 // MockArticleRepository is a mock implementation of the ArticleRepository interface
 type MockArticleRepository struct {
-	createFunc            func(ctx context.Context, userID int64, articleSlug, title, description, body string, tagList []string) (*repository.Article, error)
+	createFunc            func(ctx context.Context, userID int64, slugs []string, title, description, body, status string, publishAt *time.Time, tagList []string) (*repository.Article, error)
 	getBySlugFunc         func(ctx context.Context, slug string) (*repository.Article, error)
-	updateFunc            func(ctx context.Context, userID int64, slug string, title, description, body *string) (*repository.Article, error)
+	getByIDFunc           func(ctx context.Context, articleID int64) (*repository.Article, error)
+	getByPreviousSlugFunc func(ctx context.Context, slug string) (*repository.Article, error)
+	updateFunc            func(ctx context.Context, userID int64, slug string, newSlugs []string, title, description, body *string, tags repository.TagChanges) (*repository.Article, error)
+	setStatusFunc         func(ctx context.Context, articleID int64, status string, publishAt *time.Time) (*repository.Article, error)
+	publishDueFunc        func(ctx context.Context, now time.Time, limit int) ([]*repository.Article, error)
 	deleteFunc            func(ctx context.Context, articleID int64) error
 	favoriteFunc          func(ctx context.Context, userID int64, articleID int64) error
 	unfavoriteFunc        func(ctx context.Context, userID int64, articleID int64) error
 	getFavoritesCountFunc func(ctx context.Context, articleID int64) (int, error)
 	isFavoritedFunc       func(ctx context.Context, userID int64, articleID int64) (bool, error)
 	listArticlesFunc      func(ctx context.Context, filters repository.ArticleFilters, currentUserID *int64) (*repository.ArticleListResult, error)
-	getArticlesFeedFunc   func(ctx context.Context, userID int64, limit, offset int) (*repository.ArticleListResult, error)
+	getArticlesFeedFunc   func(ctx context.Context, userID int64, filters repository.FeedFilters) (*repository.ArticleListResult, error)
+	isInFeedFunc          func(ctx context.Context, userID int64, articleID int64) (bool, error)
 }
 
 // Create is a mock implementation of the Create method
 func (m *MockArticleRepository) Create(
 	ctx context.Context,
 	userID int64,
-	articleSlug, title, description, body string,
+	slugs []string,
+	title, description, body, status string,
+	publishAt *time.Time,
 	tagList []string,
 ) (*repository.Article, error) {
-	return m.createFunc(ctx, userID, articleSlug, title, description, body, tagList)
+	return m.createFunc(ctx, userID, slugs, title, description, body, status, publishAt, tagList)
 }
 
 // GetBySlug is a mock implementation of the GetBySlug method
@@ -43,14 +55,55 @@ func (m *MockArticleRepository) GetBySlug(
 	return m.getBySlugFunc(ctx, slug)
 }
 
+// GetByID is a mock implementation of the GetByID method
+func (m *MockArticleRepository) GetByID(
+	ctx context.Context,
+	articleID int64,
+) (*repository.Article, error) {
+	return m.getByIDFunc(ctx, articleID)
+}
+
+// GetByPreviousSlug is a mock implementation of the GetByPreviousSlug method. Slugs
+// are not redirected unless getByPreviousSlugFunc is set.
+func (m *MockArticleRepository) GetByPreviousSlug(
+	ctx context.Context,
+	slug string,
+) (*repository.Article, error) {
+	if m.getByPreviousSlugFunc == nil {
+		return nil, repository.ErrArticleNotFound
+	}
+	return m.getByPreviousSlugFunc(ctx, slug)
+}
+
 // Update is a mock implementation of the Update method
 func (m *MockArticleRepository) Update(
 	ctx context.Context,
 	userID int64,
 	slug string,
+	newSlugs []string,
 	title, description, body *string,
+	tags repository.TagChanges,
 ) (*repository.Article, error) {
-	return m.updateFunc(ctx, userID, slug, title, description, body)
+	return m.updateFunc(ctx, userID, slug, newSlugs, title, description, body, tags)
+}
+
+// SetStatus is a mock implementation of the SetStatus method
+func (m *MockArticleRepository) SetStatus(
+	ctx context.Context,
+	articleID int64,
+	status string,
+	publishAt *time.Time,
+) (*repository.Article, error) {
+	return m.setStatusFunc(ctx, articleID, status, publishAt)
+}
+
+// PublishDue is a mock implementation of the PublishDue method
+func (m *MockArticleRepository) PublishDue(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+) ([]*repository.Article, error) {
+	return m.publishDueFunc(ctx, now, limit)
 }
 
 // Delete is a mock implementation of the Delete method
@@ -109,21 +162,35 @@ func (m *MockArticleRepository) ListArticles(
 func (m *MockArticleRepository) GetArticlesFeed(
 	ctx context.Context,
 	userID int64,
-	limit, offset int,
+	filters repository.FeedFilters,
 ) (*repository.ArticleListResult, error) {
-	return m.getArticlesFeedFunc(ctx, userID, limit, offset)
+	return m.getArticlesFeedFunc(ctx, userID, filters)
+}
+
+// IsInFeed is a mock implementation of the IsInFeed method
+func (m *MockArticleRepository) IsInFeed(
+	ctx context.Context,
+	userID int64,
+	articleID int64,
+) (bool, error) {
+	return m.isInFeedFunc(ctx, userID, articleID)
 }
 
 // Test_articleService_CreateArticle tests the CreateArticle method of the articleService
 func Test_articleService_CreateArticle(t *testing.T) {
 	t.Parallel()
 
+	publishAt := time.Now().Add(time.Hour)
+	pastPublishAt := time.Now().Add(-time.Hour)
+
 	tests := []struct {
 		name        string
 		userID      int64
 		title       string
 		description string
 		body        string
+		status      string
+		publishAt   *time.Time
 		tagList     []string
 		setupMock   func() (*MockArticleRepository, *MockProfileRepository)
 		expectedErr error
@@ -138,10 +205,10 @@ func Test_articleService_CreateArticle(t *testing.T) {
 			tagList:     []string{"tag1", "tag2"},
 			setupMock: func() (*MockArticleRepository, *MockProfileRepository) {
 				mockArticleRepo := &MockArticleRepository{
-					createFunc: func(ctx context.Context, userID int64, articleSlug, title, description, body string, tagList []string) (*repository.Article, error) {
+					createFunc: func(ctx context.Context, userID int64, slugs []string, title, description, body, status string, publishAt *time.Time, tagList []string) (*repository.Article, error) {
 						expectedSlug := slug.Make("Test Article")
-						if articleSlug != expectedSlug {
-							t.Errorf("Expected slug %q, got %q", expectedSlug, articleSlug)
+						if len(slugs) == 0 || slugs[0] != expectedSlug {
+							t.Errorf("Expected first slug %q, got %v", expectedSlug, slugs)
 						}
 						if title != "Test Article" {
 							t.Errorf("Expected title %q, got %q", "Test Article", title)
@@ -159,6 +226,9 @@ func Test_articleService_CreateArticle(t *testing.T) {
 						if len(tagList) != 2 || tagList[0] != "tag1" || tagList[1] != "tag2" {
 							t.Errorf("Expected tags %v, got %v", []string{"tag1", "tag2"}, tagList)
 						}
+						if status != repository.ArticleStatusPublished {
+							t.Errorf("Expected status %q, got %q", repository.ArticleStatusPublished, status)
+						}
 
 						now := time.Now()
 						return &repository.Article{
@@ -174,9 +244,11 @@ func Test_articleService_CreateArticle(t *testing.T) {
 								Bio:      "Test Bio",
 								Image:    "https://example.com/image.jpg",
 							},
-							CreatedAt: now,
-							UpdatedAt: now,
-							TagList:   []string{"tag1", "tag2"},
+							Status:      status,
+							PublishedAt: &now,
+							CreatedAt:   now,
+							UpdatedAt:   now,
+							TagList:     []string{"tag1", "tag2"},
 						}, nil
 					},
 				}
@@ -231,8 +303,100 @@ func Test_articleService_CreateArticle(t *testing.T) {
 				if article.Author.Following {
 					t.Errorf("Expected author following to be false, got true")
 				}
+				if article.Status != repository.ArticleStatusPublished || article.PublishedAt == nil {
+					t.Errorf(
+						"Expected a published article, got status %q published at %v",
+						article.Status,
+						article.PublishedAt,
+					)
+				}
+			},
+		},
+		{
+			name:        "Draft creation",
+			userID:      1,
+			title:       "Test Article",
+			description: "Test Description",
+			body:        "Test Body",
+			status:      repository.ArticleStatusDraft,
+			setupMock: func() (*MockArticleRepository, *MockProfileRepository) {
+				return &MockArticleRepository{
+					createFunc: func(ctx context.Context, userID int64, slugs []string, title, description, body, status string, publishAt *time.Time, tagList []string) (*repository.Article, error) {
+						if status != repository.ArticleStatusDraft {
+							t.Errorf("Expected status %q, got %q", repository.ArticleStatusDraft, status)
+						}
+						return &repository.Article{
+							ID:     1,
+							Slug:   slugs[0],
+							Title:  title,
+							Author: &repository.User{ID: 1, Username: "testuser"},
+							Status: status,
+						}, nil
+					},
+				}, nil
+			},
+			expectedErr: nil,
+			validate: func(t *testing.T, article *Article) {
+				if article.Status != repository.ArticleStatusDraft {
+					t.Errorf("Expected status %q, got %q", repository.ArticleStatusDraft, article.Status)
+				}
+				if article.PublishedAt != nil {
+					t.Errorf("Expected draft not to be published, got %v", article.PublishedAt)
+				}
 			},
 		},
+		{
+			name:        "Scheduled creation",
+			userID:      1,
+			title:       "Test Article",
+			description: "Test Description",
+			body:        "Test Body",
+			publishAt:   &publishAt,
+			setupMock: func() (*MockArticleRepository, *MockProfileRepository) {
+				return &MockArticleRepository{
+					createFunc: func(ctx context.Context, userID int64, slugs []string, title, description, body, status string, publishAt *time.Time, tagList []string) (*repository.Article, error) {
+						if status != repository.ArticleStatusScheduled {
+							t.Errorf("Expected status %q, got %q", repository.ArticleStatusScheduled, status)
+						}
+						return &repository.Article{
+							ID:        1,
+							Slug:      slugs[0],
+							Title:     title,
+							Author:    &repository.User{ID: 1, Username: "testuser"},
+							Status:    status,
+							PublishAt: publishAt,
+						}, nil
+					},
+				}, nil
+			},
+			expectedErr: nil,
+			validate: func(t *testing.T, article *Article) {
+				if article.Status != repository.ArticleStatusScheduled {
+					t.Errorf("Expected status %q, got %q", repository.ArticleStatusScheduled, article.Status)
+				}
+				if article.PublishAt == nil || !article.PublishAt.Equal(publishAt) {
+					t.Errorf("Expected publishAt %v, got %v", publishAt, article.PublishAt)
+				}
+			},
+		},
+		{
+			name:        "PublishAt in the past",
+			userID:      1,
+			title:       "Test Article",
+			description: "Test Description",
+			body:        "Test Body",
+			publishAt:   &pastPublishAt,
+			setupMock: func() (*MockArticleRepository, *MockProfileRepository) {
+				return &MockArticleRepository{
+					createFunc: func(ctx context.Context, userID int64, slugs []string, title, description, body, status string, publishAt *time.Time, tagList []string) (*repository.Article, error) {
+						t.Errorf("Create should not be called when publishAt is in the past")
+						return nil, nil
+					},
+				}, nil
+			},
+			expectedErr: ErrInvalidPublishAt,
+			validate:    nil,
+		},
 		{
 			name:        "User not found",
 			userID:      999,
@@ -242,7 +406,7 @@ func Test_articleService_CreateArticle(t *testing.T) {
 			tagList:     []string{"tag1", "tag2"},
 			setupMock: func() (*MockArticleRepository, *MockProfileRepository) {
 				return &MockArticleRepository{
-					createFunc: func(ctx context.Context, userID int64, articleSlug, title, description, body string, tagList []string) (*repository.Article, error) {
+					createFunc: func(ctx context.Context, userID int64, slugs []string, title, description, body, status string, publishAt *time.Time, tagList []string) (*repository.Article, error) {
 						return nil, repository.ErrUserNotFound
 					},
 				}, nil
@@ -259,7 +423,7 @@ func Test_articleService_CreateArticle(t *testing.T) {
 			tagList:     []string{"tag1", "tag2"},
 			setupMock: func() (*MockArticleRepository, *MockProfileRepository) {
 				return &MockArticleRepository{
-					createFunc: func(ctx context.Context, userID int64, articleSlug, title, description, body string, tagList []string) (*repository.Article, error) {
+					createFunc: func(ctx context.Context, userID int64, slugs []string, title, description, body, status string, publishAt *time.Time, tagList []string) (*repository.Article, error) {
 						return nil, repository.ErrDuplicateSlug
 					},
 				}, nil
@@ -276,7 +440,7 @@ func Test_articleService_CreateArticle(t *testing.T) {
 			tagList:     []string{"tag1", "tag2"},
 			setupMock: func() (*MockArticleRepository, *MockProfileRepository) {
 				return &MockArticleRepository{
-					createFunc: func(ctx context.Context, userID int64, articleSlug, title, description, body string, tagList []string) (*repository.Article, error) {
+					createFunc: func(ctx context.Context, userID int64, slugs []string, title, description, body, status string, publishAt *time.Time, tagList []string) (*repository.Article, error) {
 						return nil, repository.ErrInternal
 					},
 				}, nil
@@ -295,7 +459,11 @@ func Test_articleService_CreateArticle(t *testing.T) {
 			mockArticleRepository, mockProfileRepository := tt.setupMock()
 
 			// Create service with mock repository
-			articleService := NewArticleService(mockArticleRepository, mockProfileRepository)
+			articleService := NewArticleService(
+				mockArticleRepository,
+				mockProfileRepository,
+				testSlugGenerator,
+			)
 
 			// Create context
 			ctx := context.Background()
@@ -307,6 +475,8 @@ func Test_articleService_CreateArticle(t *testing.T) {
 				tt.title,
 				tt.description,
 				tt.body,
+				tt.status,
+				tt.publishAt,
 				tt.tagList,
 			)
 
@@ -351,6 +521,7 @@ func Test_articleService_GetArticle(t *testing.T) {
 							Description: "Test Description",
 							Body:        "Test Body",
 							AuthorID:    1,
+							Status:      repository.ArticleStatusPublished,
 							Author: &repository.User{
 								ID:       1,
 								Username: "testuser",
@@ -485,7 +656,11 @@ func Test_articleService_GetArticle(t *testing.T) {
 			mockArticleRepository, mockProfileRepository := tt.setupMock()
 
 			// Create service with mock repository
-			articleService := NewArticleService(mockArticleRepository, mockProfileRepository)
+			articleService := NewArticleService(
+				mockArticleRepository,
+				mockProfileRepository,
+				testSlugGenerator,
+			)
 
 			// Create context
 			ctx := context.Background()
@@ -533,6 +708,7 @@ func Test_articleService_FavoriteArticle(t *testing.T) {
 							Description: "Test Description",
 							Body:        "Test Body",
 							AuthorID:    2,
+							Status:      repository.ArticleStatusPublished,
 							Author: &repository.User{
 								ID:       2,
 								Username: "authoruser",
@@ -613,6 +789,7 @@ func Test_articleService_FavoriteArticle(t *testing.T) {
 							Description: "Test Description",
 							Body:        "Test Body",
 							AuthorID:    2,
+							Status:      repository.ArticleStatusPublished,
 							Author: &repository.User{
 								ID:       2,
 								Username: "authoruser",
@@ -649,7 +826,11 @@ func Test_articleService_FavoriteArticle(t *testing.T) {
 			mockArticleRepository, mockProfileRepository := tt.setupMock()
 
 			// Create service with mock repository
-			articleService := NewArticleService(mockArticleRepository, mockProfileRepository)
+			articleService := NewArticleService(
+				mockArticleRepository,
+				mockProfileRepository,
+				testSlugGenerator,
+			)
 
 			// Create context
 			ctx := context.Background()
@@ -697,6 +878,7 @@ func Test_articleService_UnfavoriteArticle(t *testing.T) {
 							Description: "Test Description",
 							Body:        "Test Body",
 							AuthorID:    2,
+							Status:      repository.ArticleStatusPublished,
 							Author: &repository.User{
 								ID:       2,
 								Username: "authoruser",
@@ -778,6 +960,7 @@ func Test_articleService_UnfavoriteArticle(t *testing.T) {
 							Description: "Test Description",
 							Body:        "Test Body",
 							AuthorID:    2,
+							Status:      repository.ArticleStatusPublished,
 							Author: &repository.User{
 								ID:       2,
 								Username: "authoruser",
@@ -814,7 +997,11 @@ func Test_articleService_UnfavoriteArticle(t *testing.T) {
 			mockArticleRepository, mockProfileRepository := tt.setupMock()
 
 			// Create service with mock repository
-			articleService := NewArticleService(mockArticleRepository, mockProfileRepository)
+			articleService := NewArticleService(
+				mockArticleRepository,
+				mockProfileRepository,
+				testSlugGenerator,
+			)
 
 			// Create context
 			ctx := context.Background()
@@ -834,3 +1021,732 @@ func Test_articleService_UnfavoriteArticle(t *testing.T) {
 		})
 	}
 }
+
+// Test_articleService_GetArticle_Visibility tests that unpublished articles are only
+// visible to their author
+func Test_articleService_GetArticle_Visibility(t *testing.T) {
+	t.Parallel()
+
+	authorID := int64(1)
+	otherID := int64(2)
+
+	tests := []struct {
+		name          string
+		status        string
+		currentUserID *int64
+		expectedErr   error
+	}{
+		{
+			name:          "Published article for anonymous user",
+			status:        repository.ArticleStatusPublished,
+			currentUserID: nil,
+			expectedErr:   nil,
+		},
+		{
+			name:          "Draft for its author",
+			status:        repository.ArticleStatusDraft,
+			currentUserID: &authorID,
+			expectedErr:   nil,
+		},
+		{
+			name:          "Draft for another user",
+			status:        repository.ArticleStatusDraft,
+			currentUserID: &otherID,
+			expectedErr:   ErrArticleNotFound,
+		},
+		{
+			name:          "Draft for anonymous user",
+			status:        repository.ArticleStatusDraft,
+			currentUserID: nil,
+			expectedErr:   ErrArticleNotFound,
+		},
+		{
+			name:          "Archived article for another user",
+			status:        repository.ArticleStatusArchived,
+			currentUserID: &otherID,
+			expectedErr:   ErrArticleNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock repositories
+			mockArticleRepository := &MockArticleRepository{
+				getBySlugFunc: func(ctx context.Context, slug string) (*repository.Article, error) {
+					return &repository.Article{
+						ID:       1,
+						Slug:     slug,
+						AuthorID: authorID,
+						Author:   &repository.User{ID: authorID, Username: "author"},
+						Status:   tt.status,
+					}, nil
+				},
+				getFavoritesCountFunc: func(ctx context.Context, articleID int64) (int, error) {
+					return 0, nil
+				},
+				isFavoritedFunc: func(ctx context.Context, userID int64, articleID int64) (bool, error) {
+					return false, nil
+				},
+			}
+			mockProfileRepository := &MockProfileRepository{
+				isFollowingFunc: func(ctx context.Context, followerID int64, followingID int64) (bool, error) {
+					return false, nil
+				},
+			}
+
+			// Create service with mock repositories
+			articleService := NewArticleService(
+				mockArticleRepository,
+				mockProfileRepository,
+				testSlugGenerator,
+			)
+
+			// Call method
+			article, err := articleService.GetArticle(
+				context.Background(),
+				"test-article",
+				tt.currentUserID,
+			)
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate status if no error
+			if err == nil && article.Status != tt.status {
+				t.Errorf("Expected status %q, got %q", tt.status, article.Status)
+			}
+		})
+	}
+}
+
+// Test_articleService_GetArticle_MovedSlug tests that the GetArticle method of the
+// articleService reports articles requested by a previous slug as moved
+func Test_articleService_GetArticle_MovedSlug(t *testing.T) {
+	t.Parallel()
+
+	otherID := int64(2)
+
+	tests := []struct {
+		name          string
+		slug          string
+		status        string
+		currentUserID *int64
+		expectedErr   error
+		expectedSlug  string
+	}{
+		{
+			name:          "Previous slug of a published article",
+			slug:          "old-title",
+			status:        repository.ArticleStatusPublished,
+			currentUserID: nil,
+			expectedErr:   nil,
+			expectedSlug:  "new-title",
+		},
+		{
+			name:          "Previous slug of a draft of another user",
+			slug:          "old-title",
+			status:        repository.ArticleStatusDraft,
+			currentUserID: &otherID,
+			expectedErr:   ErrArticleNotFound,
+		},
+		{
+			name:          "Unknown slug",
+			slug:          "unknown-title",
+			status:        repository.ArticleStatusPublished,
+			currentUserID: nil,
+			expectedErr:   ErrArticleNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock repositories
+			mockArticleRepository := &MockArticleRepository{
+				getBySlugFunc: func(ctx context.Context, slug string) (*repository.Article, error) {
+					return nil, repository.ErrArticleNotFound
+				},
+				getByPreviousSlugFunc: func(ctx context.Context, slug string) (*repository.Article, error) {
+					if slug != "old-title" {
+						return nil, repository.ErrArticleNotFound
+					}
+					return &repository.Article{
+						ID:       1,
+						Slug:     "new-title",
+						AuthorID: 1,
+						Author:   &repository.User{ID: 1, Username: "author"},
+						Status:   tt.status,
+					}, nil
+				},
+			}
+
+			// Create service with mock repositories
+			articleService := NewArticleService(
+				mockArticleRepository,
+				&MockProfileRepository{},
+				testSlugGenerator,
+			)
+
+			// Call method
+			_, err := articleService.GetArticle(context.Background(), tt.slug, tt.currentUserID)
+
+			// Validate error
+			if tt.expectedSlug != "" {
+				var moved *ArticleMovedError
+				if !errors.As(err, &moved) {
+					t.Fatalf("Expected ArticleMovedError, got %v", err)
+				}
+				if moved.Slug != tt.expectedSlug {
+					t.Errorf("Expected article to move to %q, got %q", tt.expectedSlug, moved.Slug)
+				}
+				return
+			}
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+// Test_articleService_UpdateArticle_Slug tests that the UpdateArticle method of the
+// articleService moves the article to a new slug when its title changes
+func Test_articleService_UpdateArticle_Slug(t *testing.T) {
+	t.Parallel()
+
+	strPtr := func(s string) *string {
+		return &s
+	}
+
+	tests := []struct {
+		name             string
+		slug             string
+		title            *string
+		updateErr        error
+		expectedNewSlugs []string
+		expectedErr      error
+	}{
+		{
+			name:             "Title changed",
+			slug:             "test-article",
+			title:            strPtr("New Title"),
+			expectedNewSlugs: []string{"new-title", "new-title-2", "new-title-3"},
+			expectedErr:      nil,
+		},
+		{
+			name:             "Title changed without changing the slug",
+			slug:             "test-article",
+			title:            strPtr("Test Article!"),
+			expectedNewSlugs: nil,
+			expectedErr:      nil,
+		},
+		{
+			name:             "Title of an article with a suffixed slug set again",
+			slug:             "test-article-2",
+			title:            strPtr("Test Article"),
+			expectedNewSlugs: nil,
+			expectedErr:      nil,
+		},
+		{
+			name:             "Title cut to a word of the slug",
+			slug:             "test-article",
+			title:            strPtr("Test"),
+			expectedNewSlugs: []string{"test", "test-2", "test-3"},
+			expectedErr:      nil,
+		},
+		{
+			name:             "Title not changed",
+			slug:             "test-article",
+			title:            nil,
+			expectedNewSlugs: nil,
+			expectedErr:      nil,
+		},
+		{
+			name:             "All slugs taken",
+			slug:             "test-article",
+			title:            strPtr("Other Article"),
+			updateErr:        repository.ErrDuplicateSlug,
+			expectedNewSlugs: []string{"other-article", "other-article-2", "other-article-3"},
+			expectedErr:      ErrArticleAlreadyExists,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock repositories
+			var gotNewSlugs []string
+			mockArticleRepository := &MockArticleRepository{
+				getBySlugFunc: func(ctx context.Context, slug string) (*repository.Article, error) {
+					return &repository.Article{
+						ID:       1,
+						Slug:     slug,
+						Title:    "Test Article",
+						AuthorID: 1,
+						Author:   &repository.User{ID: 1, Username: "author"},
+						Status:   repository.ArticleStatusPublished,
+					}, nil
+				},
+				updateFunc: func(ctx context.Context, userID int64, slug string, newSlugs []string, title, description, body *string, tags repository.TagChanges) (*repository.Article, error) {
+					gotNewSlugs = newSlugs
+					if tt.updateErr != nil {
+						return nil, tt.updateErr
+					}
+					if len(newSlugs) > 0 {
+						slug = newSlugs[0]
+					}
+					return &repository.Article{
+						ID:       1,
+						Slug:     slug,
+						AuthorID: 1,
+						Author:   &repository.User{ID: 1, Username: "author"},
+						Status:   repository.ArticleStatusPublished,
+					}, nil
+				},
+				getFavoritesCountFunc: func(ctx context.Context, articleID int64) (int, error) {
+					return 0, nil
+				},
+			}
+
+			// Create service with mock repositories
+			articleService := NewArticleService(
+				mockArticleRepository,
+				&MockProfileRepository{},
+				testSlugGenerator,
+			)
+
+			// Call method
+			article, err := articleService.UpdateArticle(
+				context.Background(),
+				1,
+				tt.slug,
+				tt.title,
+				nil,
+				nil,
+				repository.TagChanges{},
+			)
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate the slugs passed to the repository, which end with random fallbacks
+			if tt.expectedNewSlugs == nil {
+				if gotNewSlugs != nil {
+					t.Errorf("Expected no new slugs, got %v", gotNewSlugs)
+				}
+			} else if len(gotNewSlugs) != len(tt.expectedNewSlugs)+randomFallbacks ||
+				!reflect.DeepEqual(gotNewSlugs[:len(tt.expectedNewSlugs)], tt.expectedNewSlugs) {
+				t.Errorf("Expected new slugs %v followed by %d random ones, got %v", tt.expectedNewSlugs, randomFallbacks, gotNewSlugs)
+			}
+
+			// Validate slug if no error
+			if err == nil && len(tt.expectedNewSlugs) > 0 && article.Slug != tt.expectedNewSlugs[0] {
+				t.Errorf("Expected slug %q, got %q", tt.expectedNewSlugs[0], article.Slug)
+			}
+		})
+	}
+}
+
+// Test_articleService_UpdateArticle_Tags tests that the tags an update replaces, adds
+// and removes are normalized before they reach the repository
+func Test_articleService_UpdateArticle_Tags(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		tags         repository.TagChanges
+		expectedTags repository.TagChanges
+	}{
+		{
+			name:         "Tags left alone",
+			tags:         repository.TagChanges{},
+			expectedTags: repository.TagChanges{},
+		},
+		{
+			name:         "Tags replaced",
+			tags:         repository.TagChanges{Replace: []string{" Go ", "sql", "go"}},
+			expectedTags: repository.TagChanges{Replace: []string{"go", "sql"}},
+		},
+		{
+			name:         "Tags cleared",
+			tags:         repository.TagChanges{Replace: []string{}},
+			expectedTags: repository.TagChanges{Replace: []string{}},
+		},
+		{
+			name: "Tags added and removed",
+			tags: repository.TagChanges{Add: []string{"Go", " "}, Remove: []string{"SQL", "sql"}},
+			expectedTags: repository.TagChanges{
+				Add:    []string{"go"},
+				Remove: []string{"sql"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock repositories
+			var gotTags repository.TagChanges
+			mockArticleRepository := &MockArticleRepository{
+				getBySlugFunc: func(ctx context.Context, slug string) (*repository.Article, error) {
+					return &repository.Article{ID: 1, Slug: slug, AuthorID: 1, Status: repository.ArticleStatusDraft}, nil
+				},
+				updateFunc: func(ctx context.Context, userID int64, slug string, newSlugs []string, title, description, body *string, tags repository.TagChanges) (*repository.Article, error) {
+					gotTags = tags
+					return &repository.Article{
+						ID:       1,
+						Slug:     slug,
+						AuthorID: 1,
+						Author:   &repository.User{ID: 1, Username: "author"},
+						Status:   repository.ArticleStatusDraft,
+					}, nil
+				},
+				getFavoritesCountFunc: func(ctx context.Context, articleID int64) (int, error) {
+					return 0, nil
+				},
+			}
+
+			// Create service with mock repositories
+			articleService := NewArticleService(
+				mockArticleRepository,
+				&MockProfileRepository{},
+				testSlugGenerator,
+			)
+
+			// Call method
+			_, err := articleService.UpdateArticle(context.Background(), 1, "test-article", nil, nil, nil, tt.tags)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			// Validate the tags passed to the repository
+			if !reflect.DeepEqual(gotTags, tt.expectedTags) {
+				t.Errorf("Expected tags %+v, got %+v", tt.expectedTags, gotTags)
+			}
+		})
+	}
+}
+
+// Test_articleService_SetArticleStatus tests the PublishArticle, UnpublishArticle and
+// ArchiveArticle methods of the articleService
+func Test_articleService_SetArticleStatus(t *testing.T) {
+	t.Parallel()
+
+	type changeFunc func(s *articleService, ctx context.Context, userID int64, slug string) (*Article, error)
+
+	publish := (*articleService).PublishArticle
+	unpublish := (*articleService).UnpublishArticle
+	archive := (*articleService).ArchiveArticle
+	publishAt := time.Now().Add(time.Hour)
+	schedule := func(at time.Time) changeFunc {
+		return func(s *articleService, ctx context.Context, userID int64, slug string) (*Article, error) {
+			return s.ScheduleArticle(ctx, userID, slug, at)
+		}
+	}
+
+	tests := []struct {
+		name              string
+		change            changeFunc
+		userID            int64
+		currentStatus     string
+		setStatusErr      error
+		expectedSetStatus string
+		expectedErr       error
+		expectedStatus    string
+	}{
+		{
+			name:              "Publish a draft",
+			change:            publish,
+			userID:            1,
+			currentStatus:     repository.ArticleStatusDraft,
+			expectedSetStatus: repository.ArticleStatusPublished,
+			expectedStatus:    repository.ArticleStatusPublished,
+		},
+		{
+			name:           "Publish a published article",
+			change:         publish,
+			userID:         1,
+			currentStatus:  repository.ArticleStatusPublished,
+			expectedStatus: repository.ArticleStatusPublished,
+		},
+		{
+			name:              "Unpublish an article",
+			change:            unpublish,
+			userID:            1,
+			currentStatus:     repository.ArticleStatusPublished,
+			expectedSetStatus: repository.ArticleStatusDraft,
+			expectedStatus:    repository.ArticleStatusDraft,
+		},
+		{
+			name:              "Archive an article",
+			change:            archive,
+			userID:            1,
+			currentStatus:     repository.ArticleStatusPublished,
+			expectedSetStatus: repository.ArticleStatusArchived,
+			expectedStatus:    repository.ArticleStatusArchived,
+		},
+		{
+			name:              "Publish a scheduled article right away",
+			change:            publish,
+			userID:            1,
+			currentStatus:     repository.ArticleStatusScheduled,
+			expectedSetStatus: repository.ArticleStatusPublished,
+			expectedStatus:    repository.ArticleStatusPublished,
+		},
+		{
+			name:              "Schedule a draft",
+			change:            schedule(publishAt),
+			userID:            1,
+			currentStatus:     repository.ArticleStatusDraft,
+			expectedSetStatus: repository.ArticleStatusScheduled,
+			expectedStatus:    repository.ArticleStatusScheduled,
+		},
+		{
+			name:              "Reschedule a scheduled article",
+			change:            schedule(publishAt),
+			userID:            1,
+			currentStatus:     repository.ArticleStatusScheduled,
+			expectedSetStatus: repository.ArticleStatusScheduled,
+			expectedStatus:    repository.ArticleStatusScheduled,
+		},
+		{
+			name:          "Schedule in the past",
+			change:        schedule(time.Now().Add(-time.Hour)),
+			userID:        1,
+			currentStatus: repository.ArticleStatusDraft,
+			expectedErr:   ErrInvalidPublishAt,
+		},
+		{
+			name:          "Schedule someone else's article",
+			change:        schedule(publishAt),
+			userID:        2,
+			currentStatus: repository.ArticleStatusPublished,
+			expectedErr:   ErrArticleNotAuthorized,
+		},
+		{
+			name:          "Unpublish someone else's article",
+			change:        unpublish,
+			userID:        2,
+			currentStatus: repository.ArticleStatusPublished,
+			expectedErr:   ErrArticleNotAuthorized,
+		},
+		{
+			name:          "Publish someone else's draft",
+			change:        publish,
+			userID:        2,
+			currentStatus: repository.ArticleStatusDraft,
+			expectedErr:   ErrArticleNotFound,
+		},
+		{
+			name:              "Repository error",
+			change:            publish,
+			userID:            1,
+			currentStatus:     repository.ArticleStatusDraft,
+			setStatusErr:      repository.ErrInternal,
+			expectedSetStatus: repository.ArticleStatusPublished,
+			expectedErr:       ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			article := &repository.Article{
+				ID:       1,
+				Slug:     "test-article",
+				AuthorID: 1,
+				Author:   &repository.User{ID: 1, Username: "author"},
+				Status:   tt.currentStatus,
+			}
+
+			// Setup mock repositories
+			setStatus := ""
+			mockArticleRepository := &MockArticleRepository{
+				getBySlugFunc: func(ctx context.Context, slug string) (*repository.Article, error) {
+					return article, nil
+				},
+				setStatusFunc: func(ctx context.Context, articleID int64, status string, publishAt *time.Time) (*repository.Article, error) {
+					setStatus = status
+					if tt.setStatusErr != nil {
+						return nil, tt.setStatusErr
+					}
+					if (status == repository.ArticleStatusScheduled) != (publishAt != nil) {
+						t.Errorf("Expected publishAt only for scheduled articles, got %v", publishAt)
+					}
+					updated := *article
+					updated.Status = status
+					updated.PublishAt = publishAt
+					return &updated, nil
+				},
+				getFavoritesCountFunc: func(ctx context.Context, articleID int64) (int, error) {
+					return 0, nil
+				},
+				isFavoritedFunc: func(ctx context.Context, userID int64, articleID int64) (bool, error) {
+					return false, nil
+				},
+			}
+
+			// Create service with mock repositories
+			articleService := NewArticleService(
+				mockArticleRepository,
+				&MockProfileRepository{},
+				testSlugGenerator,
+			)
+
+			// Call method
+			result, err := tt.change(articleService, context.Background(), tt.userID, "test-article")
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate the status was only changed when needed
+			if setStatus != tt.expectedSetStatus {
+				t.Errorf("Expected status to be set to %q, got %q", tt.expectedSetStatus, setStatus)
+			}
+
+			// Validate article if no error
+			if err == nil && result.Status != tt.expectedStatus {
+				t.Errorf("Expected status %q, got %q", tt.expectedStatus, result.Status)
+			}
+		})
+	}
+}
+
+// Test_articleService_PublishScheduledArticles tests the PublishScheduledArticles method of the articleService
+func Test_articleService_PublishScheduledArticles(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		articles      []*repository.Article
+		repositoryErr error
+		expectedSlugs []string
+		expectedErr   error
+	}{
+		{
+			name: "Due articles are published",
+			articles: []*repository.Article{
+				{ID: 1, Slug: "first-article", Status: repository.ArticleStatusPublished},
+				{ID: 2, Slug: "second-article", Status: repository.ArticleStatusPublished},
+			},
+			expectedSlugs: []string{"first-article", "second-article"},
+			expectedErr:   nil,
+		},
+		{
+			name:          "Nothing is due",
+			articles:      nil,
+			expectedSlugs: []string{},
+			expectedErr:   nil,
+		},
+		{
+			name:          "Repository error",
+			repositoryErr: repository.ErrInternal,
+			expectedErr:   ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock repositories
+			mockArticleRepository := &MockArticleRepository{
+				publishDueFunc: func(ctx context.Context, gotNow time.Time, limit int) ([]*repository.Article, error) {
+					if !gotNow.Equal(now) {
+						t.Errorf("Expected now %v, got %v", now, gotNow)
+					}
+					if limit != 10 {
+						t.Errorf("Expected limit 10, got %d", limit)
+					}
+					return tt.articles, tt.repositoryErr
+				},
+			}
+
+			// Create service with mock repositories
+			articleService := NewArticleService(
+				mockArticleRepository,
+				&MockProfileRepository{},
+				testSlugGenerator,
+			)
+
+			// Call method
+			slugs, err := articleService.PublishScheduledArticles(context.Background(), now, 10)
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate slugs if no error
+			if err == nil && !reflect.DeepEqual(slugs, tt.expectedSlugs) {
+				t.Errorf("Expected slugs %v, got %v", tt.expectedSlugs, slugs)
+			}
+		})
+	}
+}
+
+// Test_normalizeTags tests the normalizeTags function
+func Test_normalizeTags(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		tagList  []string
+		expected []string
+	}{
+		{
+			name:     "Trims, lower-cases and sorts tags",
+			tagList:  []string{" SQL", "go "},
+			expected: []string{"go", "sql"},
+		},
+		{
+			name:     "Drops duplicate and empty tags",
+			tagList:  []string{"go", "Go", " ", "", "GO "},
+			expected: []string{"go"},
+		},
+		{
+			name:     "Empty list stays empty",
+			tagList:  []string{},
+			expected: []string{},
+		},
+		{
+			name:     "Nil list stays nil",
+			tagList:  nil,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Call function
+			got := normalizeTags(tt.tagList)
+
+			// Validate tags
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected tags %#v, got %#v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -8,13 +8,37 @@ import (
 	"github.com/Nilesh2000/conduit/internal/repository"
 )
 
-// Comment represents a comment on an article
+// Comment represents a comment on an article. A deleted comment that has replies is
+// kept in its thread with Deleted set and no body or author. Edited reports whether the
+// body was changed since the comment was posted.
 type Comment struct {
-	ID        int       `json:"id"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
-	Body      string    `json:"body"`
-	Author    Profile   `json:"author"`
+	ID         int       `json:"id"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+	Body       string    `json:"body"`
+	Author     Profile   `json:"author"`
+	ParentID   *int      `json:"parentId"`
+	Depth      int       `json:"depth"`
+	ReplyCount int       `json:"replyCount"`
+	Deleted    bool      `json:"deleted"`
+	Edited     bool      `json:"edited"`
+	Replies    []Comment `json:"replies,omitempty"`
+}
+
+// CommentVersion represents an earlier body of an edited comment
+type CommentVersion struct {
+	Body       string    `json:"body"`
+	WrittenAt  time.Time `json:"writtenAt"`
+	ReplacedAt time.Time `json:"replacedAt"`
+}
+
+// CommentList represents a page of the top-level comments of an article, with their
+// replies nested below them. HasMore reports whether more comments follow the page in
+// the direction it was read in, and Count is the number of comments on the article.
+type CommentList struct {
+	Comments []Comment
+	HasMore  bool
+	Count    int
 }
 
 // CommentRepository is an interface for the comment repository
@@ -24,8 +48,20 @@ type CommentRepository interface {
 		ctx context.Context,
 		articleID int64,
 		currentUserID *int64,
-	) ([]repository.Comment, error)
-	Create(ctx context.Context, userID, articleID int64, body string) (*repository.Comment, error)
+		filters repository.CommentFilters,
+	) (*repository.CommentListResult, error)
+	Create(
+		ctx context.Context,
+		userID, articleID int64,
+		parentID *int64,
+		body string,
+	) (*repository.Comment, error)
+	Update(
+		ctx context.Context,
+		userID, commentID int64,
+		body string,
+	) (*repository.Comment, error)
+	ListVersions(ctx context.Context, commentID int64) ([]repository.CommentVersion, error)
 	Delete(ctx context.Context, commentID int64) error
 }
 
@@ -33,98 +69,245 @@ type CommentRepository interface {
 type commentService struct {
 	commentRepository CommentRepository
 	articleRepository ArticleRepository
+	userRepository    UserRepository
+	blockRepository   BlockRepository
+	maxDepth          int
+	editWindow        time.Duration
+	moderators        map[string]bool
 }
 
-// NewCommentService creates a new comment service
+// NewCommentService creates a new comment service that lets replies be nested up to
+// maxDepth levels deep and comments be edited for editWindow after they are posted, or
+// at any time if it is 0. The users named in moderators can see the earlier bodies of
+// edited comments.
 func NewCommentService(
 	commentRepository CommentRepository,
 	articleRepository ArticleRepository,
+	userRepository UserRepository,
+	blockRepository BlockRepository,
+	maxDepth int,
+	editWindow time.Duration,
+	moderators []string,
 ) *commentService {
+	moderatorSet := make(map[string]bool, len(moderators))
+	for _, username := range moderators {
+		moderatorSet[username] = true
+	}
+
 	return &commentService{
 		commentRepository: commentRepository,
 		articleRepository: articleRepository,
+		userRepository:    userRepository,
+		blockRepository:   blockRepository,
+		maxDepth:          maxDepth,
+		editWindow:        editWindow,
+		moderators:        moderatorSet,
 	}
 }
 
-// GetComments gets comments for an article
+// GetComments gets a page of the top-level comments of an article with their replies
 func (s *commentService) GetComments(
 	ctx context.Context,
 	slug string,
 	currentUserID *int64,
-) ([]Comment, error) {
-	// Get article by slug
-	article, err := s.articleRepository.GetBySlug(ctx, slug)
+	filters repository.CommentFilters,
+) (*CommentList, error) {
+	// Get article by slug, hiding comments of unpublished articles from everyone but
+	// their author
+	article, err := getViewableArticle(ctx, s.articleRepository, slug, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.commentRepository.GetByArticleID(ctx, article.ID, currentUserID, filters)
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+
+	return &CommentList{
+		Comments: commentThreads(result.Comments, result.Replies),
+		HasMore:  result.HasMore,
+		Count:    result.Count,
+	}, nil
+}
+
+// CreateComment creates a new comment, replying to the comment parentID unless it is nil.
+// Users blocked by the author of the article or of the comment replied to cannot comment.
+func (s *commentService) CreateComment(
+	ctx context.Context,
+	userID int64,
+	slug, body string,
+	parentID *int64,
+) (*Comment, error) {
+	// Only the author can comment on unpublished articles
+	article, err := getViewableArticle(ctx, s.articleRepository, slug, &userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkNotBlocked(ctx, article.AuthorID, userID); err != nil {
+		return nil, err
+	}
+
+	// Replies answer a comment of the same article that was not deleted, and are nested
+	// no deeper than maxDepth
+	if parentID != nil {
+		parent, err := s.commentRepository.GetByID(ctx, *parentID)
+		if err != nil {
+			switch {
+			case errors.Is(err, repository.ErrCommentNotFound):
+				return nil, ErrParentCommentNotFound
+			default:
+				return nil, ErrInternalServer
+			}
+		}
+		if parent.Article.ID != article.ID || parent.Tombstoned {
+			return nil, ErrParentCommentNotFound
+		}
+		if parent.Depth >= s.maxDepth {
+			return nil, ErrCommentTooDeep
+		}
+		if err := s.checkNotBlocked(ctx, parent.Author.ID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	comment, err := s.commentRepository.Create(ctx, userID, article.ID, parentID, body)
 	if err != nil {
 		switch {
-		case errors.Is(err, ErrArticleNotFound):
+		case errors.Is(err, repository.ErrArticleNotFound):
 			return nil, ErrArticleNotFound
+		case errors.Is(err, repository.ErrCommentNotFound):
+			return nil, ErrParentCommentNotFound
 		default:
 			return nil, ErrInternalServer
 		}
 	}
 
-	commentsRepo, err := s.commentRepository.GetByArticleID(ctx, article.ID, currentUserID)
+	created := commentFromRepository(*comment)
+	return &created, nil
+}
+
+// UpdateComment replaces the body of a comment, which only its author can do while the
+// edit window is open
+func (s *commentService) UpdateComment(
+	ctx context.Context,
+	userID int64,
+	slug string,
+	commentID int64,
+	body string,
+) (*Comment, error) {
+	comment, err := s.getArticleComment(ctx, slug, commentID, &userID)
 	if err != nil {
-		return nil, ErrInternalServer
+		return nil, err
 	}
 
-	comments := make([]Comment, len(commentsRepo))
-	for i, comment := range commentsRepo {
-		comments[i] = Comment{
-			ID:        comment.ID,
-			CreatedAt: comment.CreatedAt,
-			UpdatedAt: comment.UpdatedAt,
-			Body:      comment.Body,
-			Author: Profile{
-				Username:  comment.Author.Username,
-				Bio:       comment.Author.Bio,
-				Image:     comment.Author.Image,
-				Following: false,
-			},
+	// Check if the comment is owned by the user
+	if comment.Author.ID != userID {
+		return nil, ErrCommentNotAuthorized
+	}
+
+	// Check if the comment is still open for editing
+	if s.editWindow > 0 && time.Since(comment.CreatedAt) > s.editWindow {
+		return nil, ErrCommentEditClosed
+	}
+
+	updated, err := s.commentRepository.Update(ctx, userID, commentID, body)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrCommentNotFound):
+			return nil, ErrCommentNotFound
+		default:
+			return nil, ErrInternalServer
 		}
 	}
 
-	return comments, nil
+	converted := commentFromRepository(*updated)
+	return &converted, nil
 }
 
-// CreateComment creates a new comment
-func (s *commentService) CreateComment(
+// GetCommentVersions lists the earlier bodies of a comment, most recently replaced
+// first, which only moderators can see
+func (s *commentService) GetCommentVersions(
 	ctx context.Context,
 	userID int64,
-	slug, body string,
-) (*Comment, error) {
-	article, err := s.articleRepository.GetBySlug(ctx, slug)
+	slug string,
+	commentID int64,
+) ([]CommentVersion, error) {
+	// Check if the user is a moderator
+	user, err := s.userRepository.FindByID(ctx, userID)
 	if err != nil {
 		switch {
-		case errors.Is(err, ErrArticleNotFound):
-			return nil, ErrArticleNotFound
+		case errors.Is(err, repository.ErrUserNotFound):
+			return nil, ErrUserNotFound
 		default:
 			return nil, ErrInternalServer
 		}
 	}
+	if !s.moderators[user.Username] {
+		return nil, ErrNotModerator
+	}
+
+	if _, err := s.getArticleComment(ctx, slug, commentID, &userID); err != nil {
+		return nil, err
+	}
+
+	versions, err := s.commentRepository.ListVersions(ctx, commentID)
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+
+	converted := make([]CommentVersion, len(versions))
+	for i, version := range versions {
+		converted[i] = CommentVersion{
+			Body:       version.Body,
+			WrittenAt:  version.WrittenAt,
+			ReplacedAt: version.ReplacedAt,
+		}
+	}
+	return converted, nil
+}
+
+// checkNotBlocked returns ErrUserBlocked if the user blockerID blocked the user userID
+func (s *commentService) checkNotBlocked(ctx context.Context, blockerID, userID int64) error {
+	blocked, err := s.blockRepository.IsBlocking(ctx, blockerID, userID)
+	if err != nil {
+		return ErrInternalServer
+	}
+	if blocked {
+		return ErrUserBlocked
+	}
+	return nil
+}
 
-	comment, err := s.commentRepository.Create(ctx, userID, article.ID, body)
+// getArticleComment gets a comment of the article with the given slug, which must be
+// viewable by the current user and must not have been deleted
+func (s *commentService) getArticleComment(
+	ctx context.Context,
+	slug string,
+	commentID int64,
+	currentUserID *int64,
+) (*repository.Comment, error) {
+	article, err := getViewableArticle(ctx, s.articleRepository, slug, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err := s.commentRepository.GetByID(ctx, commentID)
 	if err != nil {
 		switch {
-		case errors.Is(err, repository.ErrArticleNotFound):
-			return nil, ErrArticleNotFound
+		case errors.Is(err, repository.ErrCommentNotFound):
+			return nil, ErrCommentNotFound
 		default:
 			return nil, ErrInternalServer
 		}
 	}
 
-	return &Comment{
-		ID:        comment.ID,
-		CreatedAt: comment.CreatedAt,
-		UpdatedAt: comment.UpdatedAt,
-		Body:      comment.Body,
-		Author: Profile{
-			Username:  comment.Author.Username,
-			Bio:       comment.Author.Bio,
-			Image:     comment.Author.Image,
-			Following: false,
-		},
-	}, nil
+	if comment.Article.ID != article.ID || comment.Tombstoned {
+		return nil, ErrCommentNotFound
+	}
+
+	return comment, nil
 }
 
 // DeleteComment deletes a comment
@@ -134,15 +317,10 @@ func (s *commentService) DeleteComment(
 	slug string,
 	commentID int64,
 ) error {
-	// Get the comment by ID
-	comment, err := s.commentRepository.GetByID(ctx, commentID)
+	// Get the comment, which must belong to the article and not be deleted already
+	comment, err := s.getArticleComment(ctx, slug, commentID, &userID)
 	if err != nil {
-		switch {
-		case errors.Is(err, repository.ErrCommentNotFound):
-			return ErrCommentNotFound
-		default:
-			return ErrInternalServer
-		}
+		return err
 	}
 
 	// Check if the comment is owned by the user
@@ -158,3 +336,53 @@ func (s *commentService) DeleteComment(
 
 	return nil
 }
+
+// commentFromRepository converts a repository comment to a comment, leaving out the body
+// and author of tombstones
+func commentFromRepository(comment repository.Comment) Comment {
+	converted := Comment{
+		ID:         comment.ID,
+		CreatedAt:  comment.CreatedAt,
+		UpdatedAt:  comment.UpdatedAt,
+		ParentID:   comment.ParentID,
+		Depth:      comment.Depth,
+		ReplyCount: comment.ReplyCount,
+		Deleted:    comment.Tombstoned,
+		Edited:     comment.Edited,
+	}
+	if !comment.Tombstoned {
+		converted.Body = comment.Body
+		converted.Author = Profile{
+			Username:  comment.Author.Username,
+			Bio:       comment.Author.Bio,
+			Image:     comment.Author.Image,
+			Following: comment.Author.Following,
+		}
+	}
+	return converted
+}
+
+// commentThreads converts top-level comments and nests their replies below them
+func commentThreads(comments, replies []repository.Comment) []Comment {
+	repliesTo := make(map[int][]repository.Comment)
+	for _, reply := range replies {
+		if reply.ParentID != nil {
+			repliesTo[*reply.ParentID] = append(repliesTo[*reply.ParentID], reply)
+		}
+	}
+
+	var thread func(comment repository.Comment) Comment
+	thread = func(comment repository.Comment) Comment {
+		converted := commentFromRepository(comment)
+		for _, reply := range repliesTo[comment.ID] {
+			converted.Replies = append(converted.Replies, thread(reply))
+		}
+		return converted
+	}
+
+	threads := make([]Comment, len(comments))
+	for i, comment := range comments {
+		threads[i] = thread(comment)
+	}
+	return threads
+}
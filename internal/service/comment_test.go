@@ -1 +1,467 @@
 package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Nilesh2000/conduit/internal/repository"
+)
+
+// MockCommentRepository is a mock implementation of the CommentRepository interface
+type MockCommentRepository struct {
+	getByIDFunc        func(ctx context.Context, commentID int64) (*repository.Comment, error)
+	getByArticleIDFunc func(ctx context.Context, articleID int64, currentUserID *int64, filters repository.CommentFilters) (*repository.CommentListResult, error)
+	createFunc         func(ctx context.Context, userID, articleID int64, parentID *int64, body string) (*repository.Comment, error)
+	updateFunc         func(ctx context.Context, userID, commentID int64, body string) (*repository.Comment, error)
+	listVersionsFunc   func(ctx context.Context, commentID int64) ([]repository.CommentVersion, error)
+	deleteFunc         func(ctx context.Context, commentID int64) error
+}
+
+// GetByID is a mock implementation of the GetByID method
+func (m *MockCommentRepository) GetByID(
+	ctx context.Context,
+	commentID int64,
+) (*repository.Comment, error) {
+	return m.getByIDFunc(ctx, commentID)
+}
+
+// GetByArticleID is a mock implementation of the GetByArticleID method
+func (m *MockCommentRepository) GetByArticleID(
+	ctx context.Context,
+	articleID int64,
+	currentUserID *int64,
+	filters repository.CommentFilters,
+) (*repository.CommentListResult, error) {
+	return m.getByArticleIDFunc(ctx, articleID, currentUserID, filters)
+}
+
+// Create is a mock implementation of the Create method
+func (m *MockCommentRepository) Create(
+	ctx context.Context,
+	userID, articleID int64,
+	parentID *int64,
+	body string,
+) (*repository.Comment, error) {
+	return m.createFunc(ctx, userID, articleID, parentID, body)
+}
+
+// Update is a mock implementation of the Update method
+func (m *MockCommentRepository) Update(
+	ctx context.Context,
+	userID, commentID int64,
+	body string,
+) (*repository.Comment, error) {
+	return m.updateFunc(ctx, userID, commentID, body)
+}
+
+// ListVersions is a mock implementation of the ListVersions method
+func (m *MockCommentRepository) ListVersions(
+	ctx context.Context,
+	commentID int64,
+) ([]repository.CommentVersion, error) {
+	return m.listVersionsFunc(ctx, commentID)
+}
+
+// Delete is a mock implementation of the Delete method
+func (m *MockCommentRepository) Delete(ctx context.Context, commentID int64) error {
+	return m.deleteFunc(ctx, commentID)
+}
+
+// Test_commentService_CreateComment tests the CreateComment method of the commentService
+func Test_commentService_CreateComment(t *testing.T) {
+	t.Parallel()
+
+	const maxDepth = 2
+
+	tests := []struct {
+		name          string
+		parentID      *int64
+		parent        *repository.Comment
+		blockedBy     int64
+		createErr     error
+		expectedErr   error
+		expectedDepth int
+	}{
+		{
+			name:          "Top-level comment",
+			expectedErr:   nil,
+			expectedDepth: 0,
+		},
+		{
+			name:          "Reply",
+			parentID:      new(int64),
+			parent:        &repository.Comment{ID: 7, Article: repository.Article{ID: 1}, Depth: 1},
+			expectedErr:   nil,
+			expectedDepth: 2,
+		},
+		{
+			name:        "Reply nested too deeply",
+			parentID:    new(int64),
+			parent:      &repository.Comment{ID: 7, Article: repository.Article{ID: 1}, Depth: maxDepth},
+			expectedErr: ErrCommentTooDeep,
+		},
+		{
+			name:        "Parent on another article",
+			parentID:    new(int64),
+			parent:      &repository.Comment{ID: 7, Article: repository.Article{ID: 2}},
+			expectedErr: ErrParentCommentNotFound,
+		},
+		{
+			name:        "Deleted parent",
+			parentID:    new(int64),
+			parent:      &repository.Comment{ID: 7, Article: repository.Article{ID: 1}, Tombstoned: true},
+			expectedErr: ErrParentCommentNotFound,
+		},
+		{
+			name:        "Parent not found",
+			parentID:    new(int64),
+			expectedErr: ErrParentCommentNotFound,
+		},
+		{
+			name:        "Blocked by the author of the article",
+			blockedBy:   1,
+			expectedErr: ErrUserBlocked,
+		},
+		{
+			name:        "Blocked by the author of the parent comment",
+			parentID:    new(int64),
+			parent:      &repository.Comment{ID: 7, Article: repository.Article{ID: 1}, Author: repository.Profile{ID: 3}},
+			blockedBy:   3,
+			expectedErr: ErrUserBlocked,
+		},
+		{
+			name:        "Parent deleted while replying",
+			parentID:    new(int64),
+			parent:      &repository.Comment{ID: 7, Article: repository.Article{ID: 1}},
+			createErr:   repository.ErrCommentNotFound,
+			expectedErr: ErrParentCommentNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock repository
+			mockCommentRepository := &MockCommentRepository{
+				getByIDFunc: func(ctx context.Context, commentID int64) (*repository.Comment, error) {
+					if tt.parent == nil {
+						return nil, repository.ErrCommentNotFound
+					}
+					return tt.parent, nil
+				},
+				createFunc: func(ctx context.Context, userID, articleID int64, parentID *int64, body string) (*repository.Comment, error) {
+					if tt.createErr != nil {
+						return nil, tt.createErr
+					}
+					comment := &repository.Comment{ID: 8, Body: body, Author: repository.Profile{ID: userID, Username: "author"}}
+					if tt.parent != nil {
+						comment.ParentID = &tt.parent.ID
+						comment.Depth = tt.parent.Depth + 1
+					}
+					return comment, nil
+				},
+			}
+
+			mockBlockRepository := &MockBlockRepository{
+				isBlockingFunc: func(ctx context.Context, blockerID, blockedID int64) (bool, error) {
+					return tt.blockedBy != 0 && blockerID == tt.blockedBy && blockedID == 2, nil
+				},
+			}
+
+			// Create service with mock repositories
+			commentService := NewCommentService(
+				mockCommentRepository,
+				revisionArticleRepository(repository.ArticleStatusPublished),
+				&MockUserRepository{},
+				mockBlockRepository,
+				maxDepth,
+				0,
+				nil,
+			)
+
+			// Call method
+			comment, err := commentService.CreateComment(context.Background(), 2, "test-article", "Body", tt.parentID)
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate comment if no error
+			if err == nil && comment.Depth != tt.expectedDepth {
+				t.Errorf("Expected depth %d, got %d", tt.expectedDepth, comment.Depth)
+			}
+		})
+	}
+}
+
+// Test_commentService_GetComments tests that GetComments nests replies below the comments
+// they reply to and hides the body and author of deleted comments
+func Test_commentService_GetComments(t *testing.T) {
+	t.Parallel()
+
+	one, two := 1, 2
+	author := repository.Profile{ID: 1, Username: "author"}
+	followed := repository.Profile{ID: 2, Username: "followed", Following: true}
+
+	// Setup mock repository
+	mockCommentRepository := &MockCommentRepository{
+		getByArticleIDFunc: func(ctx context.Context, articleID int64, currentUserID *int64, filters repository.CommentFilters) (*repository.CommentListResult, error) {
+			return &repository.CommentListResult{
+				Comments: []repository.Comment{
+					{ID: 1, Body: "First", Author: author, ReplyCount: 1},
+					{ID: 4, Body: "Second", Author: followed},
+				},
+				Replies: []repository.Comment{
+					{ID: 2, Body: "", Author: author, ParentID: &one, Depth: 1, ReplyCount: 1, Tombstoned: true},
+					{ID: 3, Body: "Reply", Author: author, ParentID: &two, Depth: 2},
+				},
+				HasMore: true,
+				Count:   3,
+			}, nil
+		},
+	}
+
+	// Create service with mock repositories
+	commentService := NewCommentService(
+		mockCommentRepository,
+		revisionArticleRepository(repository.ArticleStatusPublished),
+		&MockUserRepository{},
+		&MockBlockRepository{},
+		5,
+		0,
+		nil,
+	)
+
+	// Call method
+	list, err := commentService.GetComments(context.Background(), "test-article", nil, repository.CommentFilters{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Validate threads
+	expected := []Comment{
+		{
+			ID:         1,
+			Body:       "First",
+			Author:     Profile{Username: "author"},
+			ReplyCount: 1,
+			Replies: []Comment{
+				{
+					ID:         2,
+					ParentID:   &one,
+					Depth:      1,
+					ReplyCount: 1,
+					Deleted:    true,
+					Replies: []Comment{
+						{ID: 3, Body: "Reply", Author: Profile{Username: "author"}, ParentID: &two, Depth: 2},
+					},
+				},
+			},
+		},
+		{ID: 4, Body: "Second", Author: Profile{Username: "followed", Following: true}},
+	}
+	if !reflect.DeepEqual(list.Comments, expected) {
+		t.Errorf("Expected comments %+v, got %+v", expected, list.Comments)
+	}
+	if !list.HasMore {
+		t.Errorf("Expected more comments to follow")
+	}
+	if list.Count != 3 {
+		t.Errorf("Expected count 3, got %d", list.Count)
+	}
+}
+
+// Test_commentService_UpdateComment tests the UpdateComment method of the commentService
+func Test_commentService_UpdateComment(t *testing.T) {
+	t.Parallel()
+
+	const editWindow = 15 * time.Minute
+
+	tests := []struct {
+		name        string
+		userID      int64
+		editWindow  time.Duration
+		comment     *repository.Comment
+		expectedErr error
+	}{
+		{
+			name:        "Author edits within the edit window",
+			userID:      1,
+			editWindow:  editWindow,
+			comment:     &repository.Comment{ID: 7, Article: repository.Article{ID: 1}, Author: repository.Profile{ID: 1}, CreatedAt: time.Now()},
+			expectedErr: nil,
+		},
+		{
+			name:        "Author edits without an edit window",
+			userID:      1,
+			editWindow:  0,
+			comment:     &repository.Comment{ID: 7, Article: repository.Article{ID: 1}, Author: repository.Profile{ID: 1}, CreatedAt: time.Now().Add(-24 * time.Hour)},
+			expectedErr: nil,
+		},
+		{
+			name:        "Edit window closed",
+			userID:      1,
+			editWindow:  editWindow,
+			comment:     &repository.Comment{ID: 7, Article: repository.Article{ID: 1}, Author: repository.Profile{ID: 1}, CreatedAt: time.Now().Add(-time.Hour)},
+			expectedErr: ErrCommentEditClosed,
+		},
+		{
+			name:        "Not the author",
+			userID:      2,
+			editWindow:  editWindow,
+			comment:     &repository.Comment{ID: 7, Article: repository.Article{ID: 1}, Author: repository.Profile{ID: 1}, CreatedAt: time.Now()},
+			expectedErr: ErrCommentNotAuthorized,
+		},
+		{
+			name:        "Comment on another article",
+			userID:      1,
+			editWindow:  editWindow,
+			comment:     &repository.Comment{ID: 7, Article: repository.Article{ID: 2}, Author: repository.Profile{ID: 1}, CreatedAt: time.Now()},
+			expectedErr: ErrCommentNotFound,
+		},
+		{
+			name:        "Deleted comment",
+			userID:      1,
+			editWindow:  editWindow,
+			comment:     &repository.Comment{ID: 7, Article: repository.Article{ID: 1}, Author: repository.Profile{ID: 1}, CreatedAt: time.Now(), Tombstoned: true},
+			expectedErr: ErrCommentNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock repository
+			updated := false
+			mockCommentRepository := &MockCommentRepository{
+				getByIDFunc: func(ctx context.Context, commentID int64) (*repository.Comment, error) {
+					return tt.comment, nil
+				},
+				updateFunc: func(ctx context.Context, userID, commentID int64, body string) (*repository.Comment, error) {
+					updated = true
+					comment := *tt.comment
+					comment.Body = body
+					comment.Edited = true
+					return &comment, nil
+				},
+			}
+
+			// Create service with mock repositories
+			commentService := NewCommentService(
+				mockCommentRepository,
+				revisionArticleRepository(repository.ArticleStatusPublished),
+				&MockUserRepository{},
+				&MockBlockRepository{},
+				5,
+				tt.editWindow,
+				nil,
+			)
+
+			// Call method
+			comment, err := commentService.UpdateComment(context.Background(), tt.userID, "test-article", 7, "Edited")
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate that only permitted edits update the comment
+			if updated != (tt.expectedErr == nil) {
+				t.Errorf("Expected update to be called: %v, got %v", tt.expectedErr == nil, updated)
+			}
+
+			// Validate comment if no error
+			if err == nil && (comment.Body != "Edited" || !comment.Edited) {
+				t.Errorf("Expected an edited comment with the new body, got %+v", comment)
+			}
+		})
+	}
+}
+
+// Test_commentService_GetCommentVersions tests the GetCommentVersions method of the
+// commentService
+func Test_commentService_GetCommentVersions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		username       string
+		expectedErr    error
+		expectedBodies []string
+	}{
+		{
+			name:           "Moderator",
+			username:       "moderator",
+			expectedErr:    nil,
+			expectedBodies: []string{"Second", "First"},
+		},
+		{
+			name:        "Not a moderator",
+			username:    "author",
+			expectedErr: ErrNotModerator,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock repositories
+			mockCommentRepository := &MockCommentRepository{
+				getByIDFunc: func(ctx context.Context, commentID int64) (*repository.Comment, error) {
+					return &repository.Comment{ID: 7, Article: repository.Article{ID: 1}, Author: repository.Profile{ID: 1}}, nil
+				},
+				listVersionsFunc: func(ctx context.Context, commentID int64) ([]repository.CommentVersion, error) {
+					return []repository.CommentVersion{
+						{ID: 2, CommentID: 7, Body: "Second"},
+						{ID: 1, CommentID: 7, Body: "First"},
+					}, nil
+				},
+			}
+			mockUserRepository := &MockUserRepository{
+				findByIDFunc: func(ctx context.Context, id int64) (*repository.User, error) {
+					return &repository.User{ID: id, Username: tt.username}, nil
+				},
+			}
+
+			// Create service with mock repositories
+			commentService := NewCommentService(
+				mockCommentRepository,
+				revisionArticleRepository(repository.ArticleStatusPublished),
+				mockUserRepository,
+				&MockBlockRepository{},
+				5,
+				0,
+				[]string{"moderator"},
+			)
+
+			// Call method
+			versions, err := commentService.GetCommentVersions(context.Background(), 2, "test-article", 7)
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate versions if no error
+			if err == nil {
+				var bodies []string
+				for _, version := range versions {
+					bodies = append(bodies, version.Body)
+				}
+				if !reflect.DeepEqual(bodies, tt.expectedBodies) {
+					t.Errorf("Expected versions %v, got %v", tt.expectedBodies, bodies)
+				}
+			}
+		})
+	}
+}
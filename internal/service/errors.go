@@ -11,14 +11,49 @@ var (
 	ErrUserNotFound       = errors.New("user not found")
 	ErrInvalidCredentials = errors.New("invalid credentials")
 
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+
 	ErrArticleAlreadyExists = errors.New("article with this title already exists")
 
 	ErrArticleNotFound = errors.New("article not found")
 
+	ErrRevisionNotFound = errors.New("article revision not found")
+
+	ErrInvalidPublishAt = errors.New("publish time must be in the future")
+
+	ErrInvalidTag = errors.New("tag is blank")
+
 	ErrCannotFollowSelf = errors.New("cannot follow yourself")
+	ErrCannotBlockSelf  = errors.New("cannot block yourself")
+	ErrCannotMuteSelf   = errors.New("cannot mute yourself")
+	ErrUserBlocked      = errors.New("user is blocked")
 
 	ErrArticleNotAuthorized = errors.New("article not authorized")
 
-	ErrCommentNotFound      = errors.New("comment not found")
-	ErrCommentNotAuthorized = errors.New("comment not authorized")
+	ErrCommentNotFound       = errors.New("comment not found")
+	ErrCommentNotAuthorized  = errors.New("comment not authorized")
+	ErrParentCommentNotFound = errors.New("parent comment not found")
+	ErrCommentTooDeep        = errors.New("comment nested too deeply")
+	ErrCommentEditClosed     = errors.New("comment can no longer be edited")
+	ErrParentCommentDeleted  = errors.New("parent comment is in the trash")
+
+	ErrNotModerator = errors.New("not a moderator")
+
+	ErrNotificationNotFound = errors.New("notification not found")
+
+	ErrWebhookNotFound         = errors.New("webhook not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrInvalidWebhookURL       = errors.New("webhook URL must be an absolute http or https URL")
+	ErrInvalidWebhookEvents    = errors.New("webhook events are empty or unknown")
 )
+
+// ArticleMovedError is returned when an article is requested by a slug it had before its
+// title changed. Slug is the current slug of the article.
+type ArticleMovedError struct {
+	Slug string
+}
+
+// Error returns the error message
+func (e *ArticleMovedError) Error() string {
+	return "article moved to " + e.Slug
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Nilesh2000/conduit/internal/repository"
+)
+
+// Mutes represents the users and tags a user muted, most recently muted first
+type Mutes struct {
+	Profiles []Profile `json:"profiles"`
+	Tags     []string  `json:"tags"`
+}
+
+// MutedTag represents a tag and whether the current user muted it
+type MutedTag struct {
+	Name   string `json:"name"`
+	Muting bool   `json:"muting"`
+}
+
+// MuteRepository is an interface for the mute repository
+type MuteRepository interface {
+	MuteUser(
+		ctx context.Context,
+		muterID int64,
+		mutedName string,
+	) (*repository.Profile, error)
+	UnmuteUser(
+		ctx context.Context,
+		muterID int64,
+		mutedName string,
+	) (*repository.Profile, error)
+	ListMutedUsers(ctx context.Context, muterID int64) ([]repository.Profile, error)
+	MuteTag(ctx context.Context, userID int64, tag string) error
+	UnmuteTag(ctx context.Context, userID int64, tag string) error
+	ListMutedTags(ctx context.Context, userID int64) ([]string, error)
+}
+
+// muteService implements the MuteService interface
+type muteService struct {
+	muteRepository MuteRepository
+}
+
+// NewMuteService creates a new mute service
+func NewMuteService(muteRepository MuteRepository) *muteService {
+	return &muteService{muteRepository: muteRepository}
+}
+
+// MuteUser mutes a user, hiding their articles from the lists and feed of the muter
+// without the muted user knowing
+func (s *muteService) MuteUser(
+	ctx context.Context,
+	muterID int64,
+	mutedName string,
+) (*Profile, error) {
+	return s.setUserMuted(ctx, muterID, mutedName, s.muteRepository.MuteUser)
+}
+
+// UnmuteUser unmutes a user
+func (s *muteService) UnmuteUser(
+	ctx context.Context,
+	muterID int64,
+	mutedName string,
+) (*Profile, error) {
+	return s.setUserMuted(ctx, muterID, mutedName, s.muteRepository.UnmuteUser)
+}
+
+// setUserMuted mutes or unmutes a user with set
+func (s *muteService) setUserMuted(
+	ctx context.Context,
+	muterID int64,
+	mutedName string,
+	set func(ctx context.Context, muterID int64, mutedName string) (*repository.Profile, error),
+) (*Profile, error) {
+	profile, err := set(ctx, muterID, mutedName)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrUserNotFound):
+			return nil, ErrUserNotFound
+		case errors.Is(err, repository.ErrCannotMuteSelf):
+			return nil, ErrCannotMuteSelf
+		default:
+			return nil, ErrInternalServer
+		}
+	}
+
+	return &Profile{
+		Username:  profile.Username,
+		Bio:       profile.Bio,
+		Image:     profile.Image,
+		Following: profile.Following,
+		Muting:    profile.Muting,
+	}, nil
+}
+
+// MuteTag mutes a tag, hiding the articles with it from the lists and feed of the user
+func (s *muteService) MuteTag(ctx context.Context, userID int64, tag string) (*MutedTag, error) {
+	return s.setTagMuted(ctx, userID, tag, true, s.muteRepository.MuteTag)
+}
+
+// UnmuteTag unmutes a tag
+func (s *muteService) UnmuteTag(ctx context.Context, userID int64, tag string) (*MutedTag, error) {
+	return s.setTagMuted(ctx, userID, tag, false, s.muteRepository.UnmuteTag)
+}
+
+// setTagMuted mutes or unmutes a tag with set. The tag is normalized the way the tags of
+// articles are, so that it matches them.
+func (s *muteService) setTagMuted(
+	ctx context.Context,
+	userID int64,
+	tag string,
+	muting bool,
+	set func(ctx context.Context, userID int64, tag string) error,
+) (*MutedTag, error) {
+	tags := normalizeTags([]string{tag})
+	if len(tags) == 0 {
+		return nil, ErrInvalidTag
+	}
+
+	if err := set(ctx, userID, tags[0]); err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, ErrInternalServer
+	}
+
+	return &MutedTag{Name: tags[0], Muting: muting}, nil
+}
+
+// GetMutes gets the users and tags a user muted
+func (s *muteService) GetMutes(ctx context.Context, userID int64) (*Mutes, error) {
+	muted, err := s.muteRepository.ListMutedUsers(ctx, userID)
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+
+	tags, err := s.muteRepository.ListMutedTags(ctx, userID)
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+
+	profiles := make([]Profile, 0, len(muted))
+	for _, profile := range muted {
+		profiles = append(profiles, Profile{
+			Username:  profile.Username,
+			Bio:       profile.Bio,
+			Image:     profile.Image,
+			Following: profile.Following,
+			Muting:    true,
+		})
+	}
+
+	return &Mutes{Profiles: profiles, Tags: tags}, nil
+}
@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Nilesh2000/conduit/internal/repository"
+)
+
+// MockMuteRepository is a mock implementation of the MuteRepository interface
+type MockMuteRepository struct {
+	muteUserFunc       func(ctx context.Context, muterID int64, mutedName string) (*repository.Profile, error)
+	unmuteUserFunc     func(ctx context.Context, muterID int64, mutedName string) (*repository.Profile, error)
+	listMutedUsersFunc func(ctx context.Context, muterID int64) ([]repository.Profile, error)
+	muteTagFunc        func(ctx context.Context, userID int64, tag string) error
+	unmuteTagFunc      func(ctx context.Context, userID int64, tag string) error
+	listMutedTagsFunc  func(ctx context.Context, userID int64) ([]string, error)
+}
+
+// MuteUser is a mock implementation of the MuteUser method
+func (m *MockMuteRepository) MuteUser(
+	ctx context.Context,
+	muterID int64,
+	mutedName string,
+) (*repository.Profile, error) {
+	return m.muteUserFunc(ctx, muterID, mutedName)
+}
+
+// UnmuteUser is a mock implementation of the UnmuteUser method
+func (m *MockMuteRepository) UnmuteUser(
+	ctx context.Context,
+	muterID int64,
+	mutedName string,
+) (*repository.Profile, error) {
+	return m.unmuteUserFunc(ctx, muterID, mutedName)
+}
+
+// ListMutedUsers is a mock implementation of the ListMutedUsers method
+func (m *MockMuteRepository) ListMutedUsers(
+	ctx context.Context,
+	muterID int64,
+) ([]repository.Profile, error) {
+	return m.listMutedUsersFunc(ctx, muterID)
+}
+
+// MuteTag is a mock implementation of the MuteTag method
+func (m *MockMuteRepository) MuteTag(ctx context.Context, userID int64, tag string) error {
+	return m.muteTagFunc(ctx, userID, tag)
+}
+
+// UnmuteTag is a mock implementation of the UnmuteTag method
+func (m *MockMuteRepository) UnmuteTag(ctx context.Context, userID int64, tag string) error {
+	return m.unmuteTagFunc(ctx, userID, tag)
+}
+
+// ListMutedTags is a mock implementation of the ListMutedTags method
+func (m *MockMuteRepository) ListMutedTags(ctx context.Context, userID int64) ([]string, error) {
+	return m.listMutedTagsFunc(ctx, userID)
+}
+
+// Test_muteService_MuteUser tests the MuteUser method of the muteService
+func Test_muteService_MuteUser(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		muteErr     error
+		expectedErr error
+	}{
+		{
+			name:        "User muted",
+			expectedErr: nil,
+		},
+		{
+			name:        "User not found",
+			muteErr:     repository.ErrUserNotFound,
+			expectedErr: ErrUserNotFound,
+		},
+		{
+			name:        "Muting yourself",
+			muteErr:     repository.ErrCannotMuteSelf,
+			expectedErr: ErrCannotMuteSelf,
+		},
+		{
+			name:        "Repository error",
+			muteErr:     repository.ErrInternal,
+			expectedErr: ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock repository
+			muteRepo := &MockMuteRepository{
+				muteUserFunc: func(ctx context.Context, muterID int64, mutedName string) (*repository.Profile, error) {
+					if tt.muteErr != nil {
+						return nil, tt.muteErr
+					}
+					return &repository.Profile{ID: 2, Username: mutedName, Following: true, Muting: true}, nil
+				},
+			}
+
+			// Create service
+			service := NewMuteService(muteRepo)
+
+			// Call MuteUser
+			profile, err := service.MuteUser(context.Background(), 1, "noisy")
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate that muting keeps the follow
+			if err == nil && (!profile.Muting || !profile.Following || profile.Username != "noisy") {
+				t.Errorf("Expected a muted and followed profile of noisy, got %+v", profile)
+			}
+		})
+	}
+}
+
+// Test_muteService_MuteTag tests the MuteTag method of the muteService
+func Test_muteService_MuteTag(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		tag         string
+		muteErr     error
+		expectedErr error
+		expectedTag string
+	}{
+		{
+			name:        "Tag muted",
+			tag:         "crypto",
+			expectedErr: nil,
+			expectedTag: "crypto",
+		},
+		{
+			name:        "Tag normalized like article tags",
+			tag:         "  Crypto ",
+			expectedErr: nil,
+			expectedTag: "crypto",
+		},
+		{
+			name:        "Blank tag",
+			tag:         "   ",
+			expectedErr: ErrInvalidTag,
+		},
+		{
+			name:        "Repository error",
+			tag:         "crypto",
+			muteErr:     repository.ErrInternal,
+			expectedErr: ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock repository
+			var muted string
+			muteRepo := &MockMuteRepository{
+				muteTagFunc: func(ctx context.Context, userID int64, tag string) error {
+					muted = tag
+					return tt.muteErr
+				},
+			}
+
+			// Create service
+			service := NewMuteService(muteRepo)
+
+			// Call MuteTag
+			tag, err := service.MuteTag(context.Background(), 1, tt.tag)
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate that the normalized tag was muted
+			if err == nil {
+				if muted != tt.expectedTag {
+					t.Errorf("Expected tag %q to be muted, got %q", tt.expectedTag, muted)
+				}
+				if tag.Name != tt.expectedTag || !tag.Muting {
+					t.Errorf("Expected muted tag %q, got %+v", tt.expectedTag, tag)
+				}
+			}
+		})
+	}
+}
+
+// Test_muteService_GetMutes tests the GetMutes method of the muteService
+func Test_muteService_GetMutes(t *testing.T) {
+	t.Parallel()
+
+	// Setup mock repository
+	muteRepo := &MockMuteRepository{
+		listMutedUsersFunc: func(ctx context.Context, muterID int64) ([]repository.Profile, error) {
+			return []repository.Profile{{ID: 2, Username: "noisy", Following: true, Muting: true}}, nil
+		},
+		listMutedTagsFunc: func(ctx context.Context, userID int64) ([]string, error) {
+			return []string{"crypto"}, nil
+		},
+	}
+
+	// Create service
+	service := NewMuteService(muteRepo)
+
+	// Call GetMutes
+	mutes, err := service.GetMutes(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Validate mutes
+	expected := &Mutes{
+		Profiles: []Profile{{Username: "noisy", Following: true, Muting: true}},
+		Tags:     []string{"crypto"},
+	}
+	if !reflect.DeepEqual(mutes, expected) {
+		t.Errorf("Expected mutes %+v, got %+v", expected, mutes)
+	}
+}
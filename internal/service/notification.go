@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Nilesh2000/conduit/internal/repository"
+)
+
+// Notification represents something another user did that the current user is told
+// about. Article is set for favorites and comments, and CommentID for comments.
+type Notification struct {
+	ID        int64                `json:"id"`
+	Type      string               `json:"type"`
+	Actor     Profile              `json:"actor"`
+	Article   *NotificationArticle `json:"article,omitempty"`
+	CommentID *int64               `json:"commentId,omitempty"`
+	Read      bool                 `json:"read"`
+	CreatedAt time.Time            `json:"createdAt"`
+}
+
+// NotificationArticle represents the article a notification is about
+type NotificationArticle struct {
+	Slug  string `json:"slug"`
+	Title string `json:"title"`
+}
+
+// NotificationList represents a page of the notifications of a user, newest first.
+// HasMore reports whether more notifications follow the page in the direction it was
+// read in, First and Last are the positions of the first and last notifications of the
+// page, which are nil if it is empty, Count is the number of notifications across every
+// page and UnreadCount the number of them that were not read.
+type NotificationList struct {
+	Notifications []Notification
+	HasMore       bool
+	First         *repository.Cursor
+	Last          *repository.Cursor
+	Count         int
+	UnreadCount   int
+}
+
+// NotificationPreferences represents the types of notifications a user gets
+type NotificationPreferences struct {
+	Follow   bool `json:"follow"`
+	Favorite bool `json:"favorite"`
+	Comment  bool `json:"comment"`
+}
+
+// NotificationRepository is an interface for the notification repository
+type NotificationRepository interface {
+	Create(
+		ctx context.Context,
+		notification repository.Notification,
+	) (*repository.Notification, error)
+	Get(ctx context.Context, userID, notificationID int64) (*repository.Notification, error)
+	List(
+		ctx context.Context,
+		userID int64,
+		filters repository.NotificationFilters,
+	) (*repository.NotificationListResult, error)
+	MarkRead(ctx context.Context, userID, notificationID int64) error
+	MarkAllRead(ctx context.Context, userID int64) (int, error)
+	GetPreferences(ctx context.Context, userID int64) (*repository.NotificationPreferences, error)
+	UpdatePreferences(
+		ctx context.Context,
+		userID int64,
+		follow, favorite, comment *bool,
+	) (*repository.NotificationPreferences, error)
+}
+
+// notificationService implements the NotificationService interface
+type notificationService struct {
+	notificationRepository NotificationRepository
+}
+
+// NewNotificationService creates a new notification service
+func NewNotificationService(notificationRepository NotificationRepository) *notificationService {
+	return &notificationService{notificationRepository: notificationRepository}
+}
+
+// ListNotifications gets a page of the notifications of a user, newest first
+func (s *notificationService) ListNotifications(
+	ctx context.Context,
+	userID int64,
+	filters repository.NotificationFilters,
+) (*NotificationList, error) {
+	result, err := s.notificationRepository.List(ctx, userID, filters)
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+
+	notifications := make([]Notification, 0, len(result.Notifications))
+	for _, notification := range result.Notifications {
+		notifications = append(notifications, notificationFromRepository(notification))
+	}
+
+	list := &NotificationList{
+		Notifications: notifications,
+		HasMore:       result.HasMore,
+		Count:         result.Count,
+		UnreadCount:   result.UnreadCount,
+	}
+	if n := len(result.Notifications); n > 0 {
+		first, last := result.Notifications[0], result.Notifications[n-1]
+		list.First = &repository.Cursor{CreatedAt: first.CreatedAt, ID: first.ID}
+		list.Last = &repository.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	return list, nil
+}
+
+// MarkRead marks a notification of a user as read
+func (s *notificationService) MarkRead(ctx context.Context, userID, notificationID int64) error {
+	if err := s.notificationRepository.MarkRead(ctx, userID, notificationID); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotificationNotFound):
+			return ErrNotificationNotFound
+		default:
+			return ErrInternalServer
+		}
+	}
+
+	return nil
+}
+
+// MarkAllRead marks every notification of a user as read, returning how many were unread
+func (s *notificationService) MarkAllRead(ctx context.Context, userID int64) (int, error) {
+	marked, err := s.notificationRepository.MarkAllRead(ctx, userID)
+	if err != nil {
+		return 0, ErrInternalServer
+	}
+
+	return marked, nil
+}
+
+// GetPreferences gets the types of notifications a user gets
+func (s *notificationService) GetPreferences(
+	ctx context.Context,
+	userID int64,
+) (*NotificationPreferences, error) {
+	return s.preferences(s.notificationRepository.GetPreferences(ctx, userID))
+}
+
+// UpdatePreferences turns the types of notifications a user gets on or off, leaving the
+// types that are nil as they are
+func (s *notificationService) UpdatePreferences(
+	ctx context.Context,
+	userID int64,
+	follow, favorite, comment *bool,
+) (*NotificationPreferences, error) {
+	return s.preferences(
+		s.notificationRepository.UpdatePreferences(ctx, userID, follow, favorite, comment),
+	)
+}
+
+// preferences converts notification preferences read from the repository
+func (s *notificationService) preferences(
+	preferences *repository.NotificationPreferences,
+	err error,
+) (*NotificationPreferences, error) {
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrUserNotFound):
+			return nil, ErrUserNotFound
+		default:
+			return nil, ErrInternalServer
+		}
+	}
+
+	return &NotificationPreferences{
+		Follow:   preferences.Follow,
+		Favorite: preferences.Favorite,
+		Comment:  preferences.Comment,
+	}, nil
+}
+
+// notificationFromRepository converts a listed notification
+func notificationFromRepository(notification repository.Notification) Notification {
+	converted := Notification{
+		ID:        notification.ID,
+		Type:      notification.Type,
+		CommentID: notification.CommentID,
+		Read:      notification.ReadAt != nil,
+		CreatedAt: notification.CreatedAt,
+	}
+	if notification.Actor != nil {
+		converted.Actor = Profile{
+			Username:  notification.Actor.Username,
+			Bio:       notification.Actor.Bio,
+			Image:     notification.Actor.Image,
+			Following: notification.Actor.Following,
+		}
+	}
+	if notification.ArticleID != nil {
+		converted.Article = &NotificationArticle{
+			Slug:  notification.ArticleSlug,
+			Title: notification.ArticleTitle,
+		}
+	}
+	return converted
+}
@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Nilesh2000/conduit/internal/repository"
+)
+
+// MockNotificationRepository is a mock implementation of the NotificationRepository
+// interface. Notifications are not recorded unless createFunc is set.
+type MockNotificationRepository struct {
+	createFunc            func(ctx context.Context, notification repository.Notification) (*repository.Notification, error)
+	getFunc               func(ctx context.Context, userID, notificationID int64) (*repository.Notification, error)
+	listFunc              func(ctx context.Context, userID int64, filters repository.NotificationFilters) (*repository.NotificationListResult, error)
+	markReadFunc          func(ctx context.Context, userID, notificationID int64) error
+	markAllReadFunc       func(ctx context.Context, userID int64) (int, error)
+	getPreferencesFunc    func(ctx context.Context, userID int64) (*repository.NotificationPreferences, error)
+	updatePreferencesFunc func(ctx context.Context, userID int64, follow, favorite, comment *bool) (*repository.NotificationPreferences, error)
+}
+
+// Create is a mock implementation of the Create method
+func (m *MockNotificationRepository) Create(
+	ctx context.Context,
+	notification repository.Notification,
+) (*repository.Notification, error) {
+	if m.createFunc == nil {
+		return nil, nil
+	}
+	return m.createFunc(ctx, notification)
+}
+
+// Get is a mock implementation of the Get method
+func (m *MockNotificationRepository) Get(
+	ctx context.Context,
+	userID, notificationID int64,
+) (*repository.Notification, error) {
+	return m.getFunc(ctx, userID, notificationID)
+}
+
+// List is a mock implementation of the List method
+func (m *MockNotificationRepository) List(
+	ctx context.Context,
+	userID int64,
+	filters repository.NotificationFilters,
+) (*repository.NotificationListResult, error) {
+	return m.listFunc(ctx, userID, filters)
+}
+
+// MarkRead is a mock implementation of the MarkRead method
+func (m *MockNotificationRepository) MarkRead(ctx context.Context, userID, notificationID int64) error {
+	return m.markReadFunc(ctx, userID, notificationID)
+}
+
+// MarkAllRead is a mock implementation of the MarkAllRead method
+func (m *MockNotificationRepository) MarkAllRead(ctx context.Context, userID int64) (int, error) {
+	return m.markAllReadFunc(ctx, userID)
+}
+
+// GetPreferences is a mock implementation of the GetPreferences method
+func (m *MockNotificationRepository) GetPreferences(
+	ctx context.Context,
+	userID int64,
+) (*repository.NotificationPreferences, error) {
+	return m.getPreferencesFunc(ctx, userID)
+}
+
+// UpdatePreferences is a mock implementation of the UpdatePreferences method
+func (m *MockNotificationRepository) UpdatePreferences(
+	ctx context.Context,
+	userID int64,
+	follow, favorite, comment *bool,
+) (*repository.NotificationPreferences, error) {
+	return m.updatePreferencesFunc(ctx, userID, follow, favorite, comment)
+}
+
+// recordingNotificationRepository returns a mock notification repository that appends
+// the notifications it is asked to record to recorded, failing with err
+func recordingNotificationRepository(
+	recorded *[]repository.Notification,
+	err error,
+) *MockNotificationRepository {
+	return &MockNotificationRepository{
+		createFunc: func(ctx context.Context, notification repository.Notification) (*repository.Notification, error) {
+			*recorded = append(*recorded, notification)
+			if err != nil {
+				return nil, err
+			}
+			return &notification, nil
+		},
+	}
+}
+
+// Test_notificationService_ListNotifications tests the ListNotifications method of the
+// notificationService
+func Test_notificationService_ListNotifications(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	articleID, commentID := int64(1), int64(7)
+
+	tests := []struct {
+		name          string
+		result        *repository.NotificationListResult
+		listErr       error
+		expectedErr   error
+		expectedList  []Notification
+		expectedFirst *repository.Cursor
+	}{
+		{
+			name: "Notifications found",
+			result: &repository.NotificationListResult{
+				Notifications: []repository.Notification{
+					{
+						ID:           2,
+						Type:         repository.NotificationTypeComment,
+						ArticleID:    &articleID,
+						CommentID:    &commentID,
+						CreatedAt:    now,
+						Actor:        &repository.User{ID: 2, Username: "commenter"},
+						ArticleSlug:  "test-article",
+						ArticleTitle: "Test Article",
+					},
+					{
+						ID:        1,
+						Type:      repository.NotificationTypeFollow,
+						ReadAt:    &now,
+						CreatedAt: now.Add(-time.Hour),
+						Actor:     &repository.User{ID: 3, Username: "follower", Following: true},
+					},
+				},
+				HasMore:     true,
+				Count:       5,
+				UnreadCount: 3,
+			},
+			expectedErr: nil,
+			expectedList: []Notification{
+				{
+					ID:        2,
+					Type:      repository.NotificationTypeComment,
+					Actor:     Profile{Username: "commenter"},
+					Article:   &NotificationArticle{Slug: "test-article", Title: "Test Article"},
+					CommentID: &commentID,
+					CreatedAt: now,
+				},
+				{
+					ID:        1,
+					Type:      repository.NotificationTypeFollow,
+					Actor:     Profile{Username: "follower", Following: true},
+					Read:      true,
+					CreatedAt: now.Add(-time.Hour),
+				},
+			},
+			expectedFirst: &repository.Cursor{CreatedAt: now, ID: 2},
+		},
+		{
+			name:        "Repository error",
+			listErr:     repository.ErrInternal,
+			expectedErr: ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock repository
+			notificationRepo := &MockNotificationRepository{
+				listFunc: func(ctx context.Context, userID int64, filters repository.NotificationFilters) (*repository.NotificationListResult, error) {
+					return tt.result, tt.listErr
+				},
+			}
+
+			// Create service
+			service := NewNotificationService(notificationRepo)
+
+			// Call ListNotifications
+			list, err := service.ListNotifications(
+				context.Background(),
+				1,
+				repository.NotificationFilters{Limit: 2},
+			)
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate list if no error
+			if err == nil {
+				if !reflect.DeepEqual(list.Notifications, tt.expectedList) {
+					t.Errorf("Expected notifications %+v, got %+v", tt.expectedList, list.Notifications)
+				}
+				if !reflect.DeepEqual(list.First, tt.expectedFirst) {
+					t.Errorf("Expected first cursor %+v, got %+v", tt.expectedFirst, list.First)
+				}
+				if !list.HasMore || list.Count != 5 || list.UnreadCount != 3 {
+					t.Errorf("Expected more pages of 5 notifications with 3 unread, got %+v", list)
+				}
+			}
+		})
+	}
+}
+
+// Test_notificationService_MarkRead tests the MarkRead method of the notificationService
+func Test_notificationService_MarkRead(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		markErr     error
+		expectedErr error
+	}{
+		{
+			name:        "Notification marked as read",
+			expectedErr: nil,
+		},
+		{
+			name:        "Notification not found",
+			markErr:     repository.ErrNotificationNotFound,
+			expectedErr: ErrNotificationNotFound,
+		},
+		{
+			name:        "Repository error",
+			markErr:     repository.ErrInternal,
+			expectedErr: ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock repository
+			notificationRepo := &MockNotificationRepository{
+				markReadFunc: func(ctx context.Context, userID, notificationID int64) error {
+					return tt.markErr
+				},
+			}
+
+			// Create service
+			service := NewNotificationService(notificationRepo)
+
+			// Call MarkRead
+			err := service.MarkRead(context.Background(), 1, 2)
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+// Test_notificationService_UpdatePreferences tests the UpdatePreferences method of the
+// notificationService
+func Test_notificationService_UpdatePreferences(t *testing.T) {
+	t.Parallel()
+
+	// Setup mock repository that keeps the preferences left out
+	notificationRepo := &MockNotificationRepository{
+		updatePreferencesFunc: func(ctx context.Context, userID int64, follow, favorite, comment *bool) (*repository.NotificationPreferences, error) {
+			preferences := repository.NotificationPreferences{Follow: true, Favorite: true, Comment: true}
+			if follow != nil {
+				preferences.Follow = *follow
+			}
+			if favorite != nil {
+				preferences.Favorite = *favorite
+			}
+			if comment != nil {
+				preferences.Comment = *comment
+			}
+			return &preferences, nil
+		},
+	}
+
+	// Create service
+	service := NewNotificationService(notificationRepo)
+
+	// Call UpdatePreferences
+	off := false
+	preferences, err := service.UpdatePreferences(context.Background(), 1, nil, &off, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Validate preferences
+	expected := &NotificationPreferences{Follow: true, Favorite: false, Comment: true}
+	if !reflect.DeepEqual(preferences, expected) {
+		t.Errorf("Expected preferences %+v, got %+v", expected, preferences)
+	}
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Nilesh2000/conduit/internal/outbox"
+	"github.com/Nilesh2000/conduit/internal/repository"
+)
+
+// OutboxRepository is an interface for the outbox of domain events
+type OutboxRepository interface {
+	ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]repository.OutboxEvent, error)
+	Delete(ctx context.Context, eventID int64) error
+	RecordFailure(ctx context.Context, eventID int64, lastError string, nextAttemptAt time.Time) error
+	RecordFailed(ctx context.Context, eventID int64, lastError string, failedAt time.Time) error
+}
+
+// OutboxSink is an interface for the sinks domain events are published to
+type OutboxSink interface {
+	Publish(ctx context.Context, event outbox.Event) error
+}
+
+// outboxService relays the events of the outbox to the sinks that consume them
+type outboxService struct {
+	outboxRepository OutboxRepository
+	sinks            []OutboxSink
+	timeout          time.Duration
+	maxAttempts      int
+	retryBackoff     time.Duration
+	maxRetryBackoff  time.Duration
+}
+
+// NewOutboxService creates a new outbox service relaying events to sinks. Every sink is
+// given timeout to take an event, and events are published up to maxAttempts times,
+// waiting retryBackoff after the first failure and twice as long after each one that
+// follows, up to maxRetryBackoff.
+func NewOutboxService(
+	outboxRepository OutboxRepository,
+	sinks []OutboxSink,
+	timeout time.Duration,
+	maxAttempts int,
+	retryBackoff time.Duration,
+	maxRetryBackoff time.Duration,
+) *outboxService {
+	return &outboxService{
+		outboxRepository: outboxRepository,
+		sinks:            sinks,
+		timeout:          timeout,
+		maxAttempts:      maxAttempts,
+		retryBackoff:     retryBackoff,
+		maxRetryBackoff:  maxRetryBackoff,
+	}
+}
+
+// RelayEvents publishes up to limit events that are due at now to every sink, oldest
+// first, and returns how many were attempted. Events are only removed from the outbox
+// once every sink has taken them, and are otherwise retried with exponential backoff
+// until they run out of attempts, after which they are kept in the outbox with their
+// last error. Events are claimed for as long as the whole batch may take to publish,
+// and an event whose outcome is not recorded, because the process stopped, is published
+// again once its claim runs out. Sinks may therefore get an event more than once,
+// including after a retry caused by another sink.
+func (s *outboxService) RelayEvents(ctx context.Context, now time.Time, limit int) (int, error) {
+	leaseUntil := now.Add(time.Duration(limit+1) * time.Duration(len(s.sinks)) * s.timeout)
+	events, err := s.outboxRepository.ClaimDue(ctx, now, leaseUntil, limit)
+	if err != nil {
+		return 0, ErrInternalServer
+	}
+
+	for _, event := range events {
+		if err := s.publish(ctx, event); err != nil {
+			s.recordFailure(ctx, event, err)
+			continue
+		}
+
+		if err := s.outboxRepository.Delete(ctx, event.ID); err != nil {
+			log.Printf("error deleting outbox event %d: %v", event.ID, err)
+		}
+	}
+
+	return len(events), nil
+}
+
+// recordFailure records that publishing an event failed, and gives up on the event once
+// it has run out of attempts
+func (s *outboxService) recordFailure(ctx context.Context, event repository.OutboxEvent, publishErr error) {
+	attempts := event.Attempts + 1
+	if attempts >= s.maxAttempts {
+		log.Printf("giving up on outbox event %d after %d attempts: %v", event.ID, attempts, publishErr)
+		if err := s.outboxRepository.RecordFailed(ctx, event.ID, publishErr.Error(), time.Now()); err != nil {
+			log.Printf("error recording failure of outbox event %d: %v", event.ID, err)
+		}
+		return
+	}
+
+	nextAttemptAt := time.Now().Add(backoff(s.retryBackoff, s.maxRetryBackoff, attempts))
+	if err := s.outboxRepository.RecordFailure(ctx, event.ID, publishErr.Error(), nextAttemptAt); err != nil {
+		log.Printf("error recording failure of outbox event %d: %v", event.ID, err)
+	}
+}
+
+// publish publishes an event to every sink, stopping at the first that fails
+func (s *outboxService) publish(ctx context.Context, event repository.OutboxEvent) error {
+	published := outbox.Event{
+		ID:            event.ID,
+		Type:          event.EventType,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		Payload:       event.Payload,
+		CreatedAt:     event.CreatedAt,
+	}
+
+	for _, sink := range s.sinks {
+		publishCtx, cancel := context.WithTimeout(ctx, s.timeout)
+		err := sink.Publish(publishCtx, published)
+		cancel()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
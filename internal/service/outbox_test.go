@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Nilesh2000/conduit/internal/outbox"
+	"github.com/Nilesh2000/conduit/internal/repository"
+)
+
+// MockOutboxRepository is a mock implementation of the OutboxRepository interface
+type MockOutboxRepository struct {
+	claimDueFunc      func(ctx context.Context, now, leaseUntil time.Time, limit int) ([]repository.OutboxEvent, error)
+	deleteFunc        func(ctx context.Context, eventID int64) error
+	recordFailureFunc func(ctx context.Context, eventID int64, lastError string, nextAttemptAt time.Time) error
+	recordFailedFunc  func(ctx context.Context, eventID int64, lastError string, failedAt time.Time) error
+}
+
+// ClaimDue is a mock implementation of the ClaimDue method
+func (m *MockOutboxRepository) ClaimDue(
+	ctx context.Context,
+	now, leaseUntil time.Time,
+	limit int,
+) ([]repository.OutboxEvent, error) {
+	return m.claimDueFunc(ctx, now, leaseUntil, limit)
+}
+
+// Delete is a mock implementation of the Delete method
+func (m *MockOutboxRepository) Delete(ctx context.Context, eventID int64) error {
+	return m.deleteFunc(ctx, eventID)
+}
+
+// RecordFailure is a mock implementation of the RecordFailure method
+func (m *MockOutboxRepository) RecordFailure(
+	ctx context.Context,
+	eventID int64,
+	lastError string,
+	nextAttemptAt time.Time,
+) error {
+	return m.recordFailureFunc(ctx, eventID, lastError, nextAttemptAt)
+}
+
+// RecordFailed is a mock implementation of the RecordFailed method
+func (m *MockOutboxRepository) RecordFailed(
+	ctx context.Context,
+	eventID int64,
+	lastError string,
+	failedAt time.Time,
+) error {
+	return m.recordFailedFunc(ctx, eventID, lastError, failedAt)
+}
+
+// MockOutboxSink is a mock implementation of the OutboxSink interface
+type MockOutboxSink struct {
+	publishFunc func(ctx context.Context, event outbox.Event) error
+}
+
+// Publish is a mock implementation of the Publish method
+func (m *MockOutboxSink) Publish(ctx context.Context, event outbox.Event) error {
+	return m.publishFunc(ctx, event)
+}
+
+// Test_outboxService_RelayEvents tests that events taken by every sink are removed from
+// the outbox, and that the others are retried with backoff until they run out of attempts
+func Test_outboxService_RelayEvents(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	unavailable := errors.New("unexpected status 503")
+
+	tests := []struct {
+		name            string
+		attempts        int
+		sinkErr         error
+		claimErr        error
+		expectedErr     error
+		expectDeleted   bool
+		expectFailed    bool
+		expectedBackoff time.Duration
+	}{
+		{
+			name:          "Event published to every sink",
+			expectDeleted: true,
+		},
+		{
+			name:            "Sink failed",
+			sinkErr:         unavailable,
+			expectedBackoff: time.Second,
+		},
+		{
+			name:            "Backoff doubles",
+			attempts:        2,
+			sinkErr:         unavailable,
+			expectedBackoff: 4 * time.Second,
+		},
+		{
+			name:         "Event ran out of attempts",
+			attempts:     4,
+			sinkErr:      unavailable,
+			expectFailed: true,
+		},
+		{
+			name:        "Claim failed",
+			claimErr:    errors.New("database error"),
+			expectedErr: ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup Mock Repository with one due event
+			var deleted, failed bool
+			var retryAt time.Time
+			var lastError string
+			mockRepo := &MockOutboxRepository{
+				claimDueFunc: func(ctx context.Context, claimedAt, leaseUntil time.Time, limit int) ([]repository.OutboxEvent, error) {
+					if !claimedAt.Equal(now) || !leaseUntil.After(now) || limit != 10 {
+						t.Errorf("Unexpected claim at %v until %v of %d", claimedAt, leaseUntil, limit)
+					}
+					if tt.claimErr != nil {
+						return nil, tt.claimErr
+					}
+					return []repository.OutboxEvent{{
+						ID:            4,
+						AggregateType: repository.OutboxAggregateArticle,
+						AggregateID:   1,
+						EventType:     repository.OutboxEventArticleFavorited,
+						Payload:       []byte(`{"articleId":1,"userId":2}`),
+						Attempts:      tt.attempts,
+					}}, nil
+				},
+				deleteFunc: func(ctx context.Context, eventID int64) error {
+					deleted = eventID == 4
+					return nil
+				},
+				recordFailureFunc: func(ctx context.Context, eventID int64, err string, nextAttemptAt time.Time) error {
+					retryAt = nextAttemptAt
+					lastError = err
+					return nil
+				},
+				recordFailedFunc: func(ctx context.Context, eventID int64, err string, at time.Time) error {
+					failed = eventID == 4
+					lastError = err
+					return nil
+				},
+			}
+
+			// Setup an in-process bus followed by a sink that may fail
+			bus := outbox.NewBus()
+			var handled []int64
+			bus.Subscribe(func(ctx context.Context, event outbox.Event) error {
+				handled = append(handled, event.ID)
+				return nil
+			}, repository.OutboxEventArticleFavorited)
+			mockSink := &MockOutboxSink{
+				publishFunc: func(ctx context.Context, event outbox.Event) error {
+					if event.Type != repository.OutboxEventArticleFavorited || string(event.Payload) != `{"articleId":1,"userId":2}` {
+						t.Errorf("Unexpected event %+v", event)
+					}
+					return tt.sinkErr
+				},
+			}
+
+			// Create Service
+			outboxService := NewOutboxService(mockRepo, []OutboxSink{bus, mockSink}, time.Second, 5, time.Second, time.Minute)
+
+			// Call Method
+			before := time.Now()
+			attempted, err := outboxService.RelayEvents(context.Background(), now, 10)
+
+			// Validate Result
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+			if tt.expectedErr != nil {
+				return
+			}
+			if attempted != 1 || len(handled) != 1 {
+				t.Errorf("Expected the event to be attempted and handled once, got %d and %v", attempted, handled)
+			}
+			if deleted != tt.expectDeleted {
+				t.Errorf("Expected deleted %v, got %v", tt.expectDeleted, deleted)
+			}
+			if failed != tt.expectFailed {
+				t.Errorf("Expected failed %v, got %v", tt.expectFailed, failed)
+			}
+			if tt.sinkErr != nil {
+				if lastError != tt.sinkErr.Error() {
+					t.Errorf("Expected error %q, got %q", tt.sinkErr.Error(), lastError)
+				}
+			}
+			if tt.sinkErr != nil && !tt.expectFailed {
+				if retryAt.Before(before.Add(tt.expectedBackoff)) || retryAt.After(time.Now().Add(tt.expectedBackoff)) {
+					t.Errorf("Expected a retry in %v, got one at %v", tt.expectedBackoff, retryAt)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/Nilesh2000/conduit/internal/outbox"
+	"github.com/Nilesh2000/conduit/internal/repository"
+	"github.com/Nilesh2000/conduit/internal/stream"
+)
+
+// producerService produces the notifications, stream events and webhook deliveries that
+// follow from the domain events relayed from the outbox. Since events are relayed after
+// the change they describe was committed, and at least once, nothing is produced for
+// changes that were rolled back, and what is produced is not lost if the process stops.
+type producerService struct {
+	articleRepository      ArticleRepository
+	commentRepository      CommentRepository
+	userRepository         UserRepository
+	notificationRepository NotificationRepository
+	// events publishes published articles, comments and notifications to streaming
+	// clients
+	events   EventPublisher
+	webhooks WebhookQueue
+}
+
+// NewProducerService creates a new producer service
+func NewProducerService(
+	articleRepository ArticleRepository,
+	commentRepository CommentRepository,
+	userRepository UserRepository,
+	notificationRepository NotificationRepository,
+	events EventPublisher,
+	webhooks WebhookQueue,
+) *producerService {
+	return &producerService{
+		articleRepository:      articleRepository,
+		commentRepository:      commentRepository,
+		userRepository:         userRepository,
+		notificationRepository: notificationRepository,
+		events:                 events,
+		webhooks:               webhooks,
+	}
+}
+
+// Subscribe subscribes the producers to the events of bus they produce from
+func (s *producerService) Subscribe(bus *outbox.Bus) {
+	bus.Subscribe(
+		s.Notify,
+		repository.OutboxEventUserFollowed,
+		repository.OutboxEventArticleFavorited,
+		repository.OutboxEventCommentCreated,
+	)
+	bus.Subscribe(
+		s.Stream,
+		repository.OutboxEventArticleCreated,
+		repository.OutboxEventArticleStatusChanged,
+		repository.OutboxEventCommentCreated,
+	)
+	bus.Subscribe(
+		s.QueueWebhooks,
+		repository.OutboxEventArticleCreated,
+		repository.OutboxEventArticleStatusChanged,
+		repository.OutboxEventArticleUpdated,
+		repository.OutboxEventArticleDeleted,
+		repository.OutboxEventCommentCreated,
+	)
+}
+
+// Notify records the notification of the user a follow, favorite or comment is about
+// and publishes it to their streaming clients. A notification is recorded once however
+// many times its event is handled.
+func (s *producerService) Notify(ctx context.Context, event outbox.Event) error {
+	var notification repository.Notification
+	switch event.Type {
+	case repository.OutboxEventUserFollowed:
+		var payload repository.FollowEvent
+		if err := decodeEvent(event, &payload); err != nil {
+			return err
+		}
+		notification = repository.Notification{
+			UserID:  payload.FollowingID,
+			ActorID: payload.FollowerID,
+			Type:    repository.NotificationTypeFollow,
+		}
+	case repository.OutboxEventArticleFavorited:
+		var payload repository.FavoriteEvent
+		if err := decodeEvent(event, &payload); err != nil {
+			return err
+		}
+		article, err := s.eventArticle(ctx, payload.ArticleID)
+		if err != nil || article == nil {
+			return err
+		}
+		notification = repository.Notification{
+			UserID:    article.AuthorID,
+			ActorID:   payload.UserID,
+			Type:      repository.NotificationTypeFavorite,
+			ArticleID: &article.ID,
+		}
+	case repository.OutboxEventCommentCreated:
+		var payload repository.CommentEvent
+		if err := decodeEvent(event, &payload); err != nil {
+			return err
+		}
+		article, err := s.eventArticle(ctx, payload.ArticleID)
+		if err != nil || article == nil {
+			return err
+		}
+		notification = repository.Notification{
+			UserID:    article.AuthorID,
+			ActorID:   payload.AuthorID,
+			Type:      repository.NotificationTypeComment,
+			ArticleID: &article.ID,
+			CommentID: &payload.CommentID,
+		}
+	default:
+		return nil
+	}
+
+	created, err := s.notificationRepository.Create(ctx, notification)
+	if err != nil {
+		return fmt.Errorf("recording %s notification for user %d: %w", notification.Type, notification.UserID, err)
+	}
+
+	// Notifications the user does not get, or already got, are not recorded
+	if created != nil {
+		publish(ctx, s.events, stream.Event{
+			Type:           stream.EventNotification,
+			UserID:         created.UserID,
+			NotificationID: created.ID,
+		})
+	}
+
+	return nil
+}
+
+// Stream publishes published articles to the streaming clients of the users whose feed
+// they are in, and new comments to the readers of their article
+func (s *producerService) Stream(ctx context.Context, event outbox.Event) error {
+	switch event.Type {
+	case repository.OutboxEventArticleCreated, repository.OutboxEventArticleStatusChanged:
+		var payload repository.ArticleEvent
+		if err := decodeEvent(event, &payload); err != nil {
+			return err
+		}
+		if payload.Status != repository.ArticleStatusPublished {
+			return nil
+		}
+		return s.events.Publish(ctx, stream.Event{
+			Type:      stream.EventArticle,
+			ArticleID: payload.ArticleID,
+			Slug:      payload.Slug,
+		})
+	case repository.OutboxEventCommentCreated:
+		var payload repository.CommentEvent
+		if err := decodeEvent(event, &payload); err != nil {
+			return err
+		}
+		return s.events.Publish(ctx, stream.Event{
+			Type:      stream.EventComment,
+			ArticleID: payload.ArticleID,
+			CommentID: payload.CommentID,
+		})
+	}
+
+	return nil
+}
+
+// QueueWebhooks queues a delivery of the events webhooks subscribe to for the webhooks
+// of the author of their article. Webhooks are only told about articles while they are
+// published, and about each event once however many times it is handled.
+func (s *producerService) QueueWebhooks(ctx context.Context, event outbox.Event) error {
+	webhookPayload := WebhookPayload{CreatedAt: event.CreatedAt.UTC()}
+	var articleID int64
+	switch event.Type {
+	case repository.OutboxEventArticleCreated,
+		repository.OutboxEventArticleStatusChanged,
+		repository.OutboxEventArticleUpdated,
+		repository.OutboxEventArticleDeleted:
+		var payload repository.ArticleEvent
+		if err := decodeEvent(event, &payload); err != nil {
+			return err
+		}
+		if payload.Status != repository.ArticleStatusPublished {
+			return nil
+		}
+		articleID = payload.ArticleID
+		switch event.Type {
+		case repository.OutboxEventArticleUpdated:
+			webhookPayload.Event = repository.WebhookEventArticleUpdated
+		case repository.OutboxEventArticleDeleted:
+			webhookPayload.Event = repository.WebhookEventArticleDeleted
+		default:
+			webhookPayload.Event = repository.WebhookEventArticlePublished
+		}
+	case repository.OutboxEventCommentCreated:
+		var payload repository.CommentEvent
+		if err := decodeEvent(event, &payload); err != nil {
+			return err
+		}
+		comment, err := s.eventComment(ctx, payload.CommentID)
+		if err != nil || comment == nil {
+			return err
+		}
+		articleID = payload.ArticleID
+		webhookPayload.Event = repository.WebhookEventCommentCreated
+		webhookPayload.Comment = comment
+	default:
+		return nil
+	}
+
+	article, err := s.eventArticle(ctx, articleID)
+	if err != nil || article == nil {
+		return err
+	}
+	article.FavoritesCount, err = s.articleRepository.GetFavoritesCount(ctx, article.ID)
+	if err != nil {
+		return fmt.Errorf("counting favorites of article %d: %w", article.ID, err)
+	}
+	webhookPayload.Article = webhookArticle(article)
+
+	body, err := json.Marshal(webhookPayload)
+	if err != nil {
+		return fmt.Errorf("encoding %s webhook payload: %w", webhookPayload.Event, err)
+	}
+	if _, err := s.webhooks.Enqueue(ctx, article.AuthorID, event.ID, webhookPayload.Event, body); err != nil {
+		return fmt.Errorf("queueing %s webhooks for user %d: %w", webhookPayload.Event, article.AuthorID, err)
+	}
+
+	return nil
+}
+
+// eventArticle gets the article an event is about. It returns nil without an error if
+// the article was purged since, in which case there is nothing left to tell about it.
+func (s *producerService) eventArticle(ctx context.Context, articleID int64) (*repository.Article, error) {
+	article, err := s.articleRepository.GetByID(ctx, articleID)
+	if err != nil {
+		if errors.Is(err, repository.ErrArticleNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("getting article %d: %w", articleID, err)
+	}
+	return article, nil
+}
+
+// eventComment gets a new comment with its author, as anyone would see it. It returns
+// nil without an error if the comment was deleted since.
+func (s *producerService) eventComment(ctx context.Context, commentID int64) (*Comment, error) {
+	comment, err := s.commentRepository.GetByID(ctx, commentID)
+	if err != nil {
+		if errors.Is(err, repository.ErrCommentNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("getting comment %d: %w", commentID, err)
+	}
+	if comment.Tombstoned {
+		return nil, nil
+	}
+
+	author, err := s.userRepository.FindByID(ctx, comment.Author.ID)
+	if err != nil {
+		return nil, fmt.Errorf("getting author of comment %d: %w", commentID, err)
+	}
+	comment.Author = repository.Profile{
+		ID:       author.ID,
+		Username: author.Username,
+		Bio:      author.Bio,
+		Image:    author.Image,
+	}
+
+	converted := commentFromRepository(*comment)
+	return &converted, nil
+}
+
+// decodeEvent decodes the payload of an event into payload
+func decodeEvent(event outbox.Event, payload any) error {
+	if err := json.Unmarshal(event.Payload, payload); err != nil {
+		return fmt.Errorf("decoding %s event %d: %w", event.Type, event.ID, err)
+	}
+	return nil
+}
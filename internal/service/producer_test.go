@@ -0,0 +1,434 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Nilesh2000/conduit/internal/outbox"
+	"github.com/Nilesh2000/conduit/internal/repository"
+	"github.com/Nilesh2000/conduit/internal/stream"
+)
+
+// producerFixture returns a producer service for article 1, test-article, which user 1
+// wrote and has two favorites. Article 2 was purged. User 2 wrote comment 7 on article
+// 1, and comment 8 was deleted.
+func producerFixture(
+	notificationRepo NotificationRepository,
+	events EventPublisher,
+	webhooks WebhookQueue,
+) *producerService {
+	articleRepo := &MockArticleRepository{
+		getByIDFunc: func(ctx context.Context, articleID int64) (*repository.Article, error) {
+			if articleID != 1 {
+				return nil, repository.ErrArticleNotFound
+			}
+			return &repository.Article{
+				ID:       1,
+				Slug:     "test-article",
+				Title:    "Title",
+				AuthorID: 1,
+				Author:   &repository.User{ID: 1, Username: "author"},
+				Status:   repository.ArticleStatusPublished,
+			}, nil
+		},
+		getFavoritesCountFunc: func(ctx context.Context, articleID int64) (int, error) {
+			return 2, nil
+		},
+	}
+
+	commentRepo := &MockCommentRepository{
+		getByIDFunc: func(ctx context.Context, commentID int64) (*repository.Comment, error) {
+			if commentID != 7 {
+				return nil, repository.ErrCommentNotFound
+			}
+			return &repository.Comment{
+				ID:      7,
+				Body:    "Nice",
+				Article: repository.Article{ID: 1},
+				Author:  repository.Profile{ID: 2},
+			}, nil
+		},
+	}
+
+	userRepo := &MockUserRepository{
+		findByIDFunc: func(ctx context.Context, id int64) (*repository.User, error) {
+			return &repository.User{ID: id, Username: "commenter"}, nil
+		},
+	}
+
+	return NewProducerService(articleRepo, commentRepo, userRepo, notificationRepo, events, webhooks)
+}
+
+// producerEvent returns an outbox event numbered 5 of eventType with payload
+func producerEvent(t *testing.T, eventType string, payload any) outbox.Event {
+	t.Helper()
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("Failed to encode payload: %v", err)
+	}
+	return outbox.Event{
+		ID:        5,
+		Type:      eventType,
+		Payload:   data,
+		CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+// Test_producerService_Notify tests that follows, favorites and comments notify the user
+// they are about, and that the notifications are published to their streaming clients
+func Test_producerService_Notify(t *testing.T) {
+	t.Parallel()
+
+	articleID, commentID := int64(1), int64(7)
+
+	tests := []struct {
+		name                 string
+		eventType            string
+		payload              any
+		notifyErr            error
+		expectedErr          bool
+		expectedNotification *repository.Notification
+		expectedEvents       []stream.Event
+	}{
+		{
+			name:                 "Follow",
+			eventType:            repository.OutboxEventUserFollowed,
+			payload:              repository.FollowEvent{FollowerID: 2, FollowingID: 1},
+			expectedNotification: &repository.Notification{UserID: 1, ActorID: 2, Type: repository.NotificationTypeFollow},
+			expectedEvents:       []stream.Event{{Type: stream.EventNotification, UserID: 1}},
+		},
+		{
+			name:      "Favorite",
+			eventType: repository.OutboxEventArticleFavorited,
+			payload:   repository.FavoriteEvent{ArticleID: 1, UserID: 2},
+			expectedNotification: &repository.Notification{
+				UserID:    1,
+				ActorID:   2,
+				Type:      repository.NotificationTypeFavorite,
+				ArticleID: &articleID,
+			},
+			expectedEvents: []stream.Event{{Type: stream.EventNotification, UserID: 1}},
+		},
+		{
+			name:      "Comment",
+			eventType: repository.OutboxEventCommentCreated,
+			payload:   repository.CommentEvent{CommentID: 7, ArticleID: 1, AuthorID: 2},
+			expectedNotification: &repository.Notification{
+				UserID:    1,
+				ActorID:   2,
+				Type:      repository.NotificationTypeComment,
+				ArticleID: &articleID,
+				CommentID: &commentID,
+			},
+			expectedEvents: []stream.Event{{Type: stream.EventNotification, UserID: 1}},
+		},
+		{
+			name:           "Favorite of a purged article",
+			eventType:      repository.OutboxEventArticleFavorited,
+			payload:        repository.FavoriteEvent{ArticleID: 2, UserID: 2},
+			expectedEvents: []stream.Event{},
+		},
+		{
+			name:                 "Notification that cannot be recorded",
+			eventType:            repository.OutboxEventUserFollowed,
+			payload:              repository.FollowEvent{FollowerID: 2, FollowingID: 1},
+			notifyErr:            repository.ErrInternal,
+			expectedErr:          true,
+			expectedNotification: &repository.Notification{UserID: 1, ActorID: 2, Type: repository.NotificationTypeFollow},
+			expectedEvents:       []stream.Event{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mocks recording the notifications and events
+			var recorded []repository.Notification
+			notificationRepo := recordingNotificationRepository(&recorded, tt.notifyErr)
+			events := &MockEventPublisher{}
+			service := producerFixture(notificationRepo, events, &MockWebhookQueue{})
+
+			// Call method
+			err := service.Notify(context.Background(), producerEvent(t, tt.eventType, tt.payload))
+
+			// Validate error
+			if (err != nil) != tt.expectedErr {
+				t.Errorf("Expected error: %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate the notification
+			switch {
+			case tt.expectedNotification == nil && len(recorded) != 0:
+				t.Errorf("Expected no notification, got %+v", recorded)
+			case tt.expectedNotification != nil &&
+				(len(recorded) != 1 || !reflect.DeepEqual(recorded[0], *tt.expectedNotification)):
+				t.Errorf("Expected notification %+v, got %+v", *tt.expectedNotification, recorded)
+			}
+
+			// Validate the events
+			if got := events.Events(); !reflect.DeepEqual(got, tt.expectedEvents) {
+				t.Errorf("Expected events %+v, got %+v", tt.expectedEvents, got)
+			}
+		})
+	}
+}
+
+// Test_producerService_Notify_NotRecorded tests that notifications the user does not
+// get, or already got, are not published
+func Test_producerService_Notify_NotRecorded(t *testing.T) {
+	t.Parallel()
+
+	notificationRepo := &MockNotificationRepository{
+		createFunc: func(ctx context.Context, notification repository.Notification) (*repository.Notification, error) {
+			return nil, nil
+		},
+	}
+	events := &MockEventPublisher{}
+	service := producerFixture(notificationRepo, events, &MockWebhookQueue{})
+
+	event := producerEvent(t, repository.OutboxEventUserFollowed, repository.FollowEvent{FollowerID: 2, FollowingID: 1})
+	if err := service.Notify(context.Background(), event); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := events.Events(); len(got) != 0 {
+		t.Errorf("Expected no events, got %+v", got)
+	}
+}
+
+// Test_producerService_Stream tests that published articles and new comments are
+// published to streaming clients
+func Test_producerService_Stream(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		eventType string
+		payload   any
+		expected  []stream.Event
+	}{
+		{
+			name:      "Article created published",
+			eventType: repository.OutboxEventArticleCreated,
+			payload:   repository.ArticleEvent{ArticleID: 1, AuthorID: 1, Slug: "test-article", Status: repository.ArticleStatusPublished},
+			expected:  []stream.Event{{Type: stream.EventArticle, ArticleID: 1, Slug: "test-article"}},
+		},
+		{
+			name:      "Draft created",
+			eventType: repository.OutboxEventArticleCreated,
+			payload:   repository.ArticleEvent{ArticleID: 1, AuthorID: 1, Slug: "test-article", Status: repository.ArticleStatusDraft},
+			expected:  []stream.Event{},
+		},
+		{
+			name:      "Article published",
+			eventType: repository.OutboxEventArticleStatusChanged,
+			payload:   repository.ArticleEvent{ArticleID: 1, AuthorID: 1, Slug: "test-article", Status: repository.ArticleStatusPublished},
+			expected:  []stream.Event{{Type: stream.EventArticle, ArticleID: 1, Slug: "test-article"}},
+		},
+		{
+			name:      "Article archived",
+			eventType: repository.OutboxEventArticleStatusChanged,
+			payload:   repository.ArticleEvent{ArticleID: 1, AuthorID: 1, Slug: "test-article", Status: repository.ArticleStatusArchived},
+			expected:  []stream.Event{},
+		},
+		{
+			name:      "Comment",
+			eventType: repository.OutboxEventCommentCreated,
+			payload:   repository.CommentEvent{CommentID: 7, ArticleID: 1, AuthorID: 2},
+			expected:  []stream.Event{{Type: stream.EventComment, ArticleID: 1, CommentID: 7}},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup publisher recording the events
+			events := &MockEventPublisher{}
+			service := producerFixture(&MockNotificationRepository{}, events, &MockWebhookQueue{})
+
+			// Call method
+			if err := service.Stream(context.Background(), producerEvent(t, tt.eventType, tt.payload)); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			// Validate the events
+			if got := events.Events(); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected events %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+// Test_producerService_QueueWebhooks tests that the events webhooks are sent are queued
+// for the author of their article, while it is published
+func Test_producerService_QueueWebhooks(t *testing.T) {
+	t.Parallel()
+
+	published := repository.ArticleEvent{ArticleID: 1, AuthorID: 1, Slug: "test-article", Status: repository.ArticleStatusPublished}
+	draft := repository.ArticleEvent{ArticleID: 1, AuthorID: 1, Slug: "test-article", Status: repository.ArticleStatusDraft}
+
+	tests := []struct {
+		name            string
+		eventType       string
+		payload         any
+		expectedEvents  []string
+		expectedComment bool
+	}{
+		{
+			name:           "Article created published",
+			eventType:      repository.OutboxEventArticleCreated,
+			payload:        published,
+			expectedEvents: []string{repository.WebhookEventArticlePublished},
+		},
+		{
+			name:           "Draft created",
+			eventType:      repository.OutboxEventArticleCreated,
+			payload:        draft,
+			expectedEvents: []string{},
+		},
+		{
+			name:           "Article published",
+			eventType:      repository.OutboxEventArticleStatusChanged,
+			payload:        published,
+			expectedEvents: []string{repository.WebhookEventArticlePublished},
+		},
+		{
+			name:           "Published article updated",
+			eventType:      repository.OutboxEventArticleUpdated,
+			payload:        published,
+			expectedEvents: []string{repository.WebhookEventArticleUpdated},
+		},
+		{
+			name:           "Draft updated",
+			eventType:      repository.OutboxEventArticleUpdated,
+			payload:        draft,
+			expectedEvents: []string{},
+		},
+		{
+			name:           "Published article deleted",
+			eventType:      repository.OutboxEventArticleDeleted,
+			payload:        published,
+			expectedEvents: []string{repository.WebhookEventArticleDeleted},
+		},
+		{
+			name:           "Purged article",
+			eventType:      repository.OutboxEventArticleUpdated,
+			payload:        repository.ArticleEvent{ArticleID: 2, AuthorID: 1, Slug: "purged", Status: repository.ArticleStatusPublished},
+			expectedEvents: []string{},
+		},
+		{
+			name:            "Comment",
+			eventType:       repository.OutboxEventCommentCreated,
+			payload:         repository.CommentEvent{CommentID: 7, ArticleID: 1, AuthorID: 2},
+			expectedEvents:  []string{repository.WebhookEventCommentCreated},
+			expectedComment: true,
+		},
+		{
+			name:           "Deleted comment",
+			eventType:      repository.OutboxEventCommentCreated,
+			payload:        repository.CommentEvent{CommentID: 8, ArticleID: 1, AuthorID: 2},
+			expectedEvents: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup queue recording the deliveries
+			webhooks := &MockWebhookQueue{}
+			service := producerFixture(&MockNotificationRepository{}, &MockEventPublisher{}, webhooks)
+
+			// Call method
+			event := producerEvent(t, tt.eventType, tt.payload)
+			if err := service.QueueWebhooks(context.Background(), event); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			// Validate the deliveries, which all go to the author of test-article
+			events := []string{}
+			for _, queued := range webhooks.Queued() {
+				events = append(events, queued.Payload.Event)
+				if queued.UserID != 1 || queued.EventID != 5 {
+					t.Errorf("Expected the delivery of event 5 to go to user 1, got %+v", queued)
+				}
+				if !queued.Payload.CreatedAt.Equal(event.CreatedAt) {
+					t.Errorf("Expected the payload to be created at %v, got %v", event.CreatedAt, queued.Payload.CreatedAt)
+				}
+				if queued.Payload.Article == nil || queued.Payload.Article.Slug != "test-article" ||
+					queued.Payload.Article.FavoritesCount != 2 {
+					t.Errorf("Expected the payload to be about test-article, got %+v", queued.Payload.Article)
+				}
+				if hasComment := queued.Payload.Comment != nil; hasComment != tt.expectedComment {
+					t.Errorf("Expected the payload to have a comment: %v, got %+v", tt.expectedComment, queued.Payload.Comment)
+				}
+				if tt.expectedComment && queued.Payload.Comment.Author.Username != "commenter" {
+					t.Errorf("Expected the comment to be by commenter, got %+v", queued.Payload.Comment.Author)
+				}
+			}
+			if !reflect.DeepEqual(events, tt.expectedEvents) {
+				t.Errorf("Expected events %v, got %v", tt.expectedEvents, events)
+			}
+		})
+	}
+}
+
+// Test_producerService_Subscribe tests that the events handed to the bus reach the
+// producers of what follows from them
+func Test_producerService_Subscribe(t *testing.T) {
+	t.Parallel()
+
+	var recorded []repository.Notification
+	events := &MockEventPublisher{}
+	webhooks := &MockWebhookQueue{}
+	bus := outbox.NewBus()
+	producerFixture(recordingNotificationRepository(&recorded, nil), events, webhooks).Subscribe(bus)
+
+	// Publish a comment, which is told about by every producer
+	event := producerEvent(t, repository.OutboxEventCommentCreated, repository.CommentEvent{CommentID: 7, ArticleID: 1, AuthorID: 2})
+	if err := bus.Publish(context.Background(), event); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(recorded) != 1 || len(events.Events()) != 2 || len(webhooks.Queued()) != 1 {
+		t.Errorf(
+			"Expected a notification, two stream events and a webhook delivery, got %+v, %+v and %+v",
+			recorded,
+			events.Events(),
+			webhooks.Queued(),
+		)
+	}
+
+	// Events no producer handles are left alone
+	event = producerEvent(t, repository.OutboxEventArticleRestored, repository.ArticleEvent{ArticleID: 1})
+	if err := bus.Publish(context.Background(), event); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if len(recorded) != 1 || len(events.Events()) != 2 || len(webhooks.Queued()) != 1 {
+		t.Errorf("Expected nothing more to be produced")
+	}
+}
+
+// Test_producerService_QueueWebhooks_Error tests that failures are returned, so that the
+// event is retried
+func Test_producerService_QueueWebhooks_Error(t *testing.T) {
+	t.Parallel()
+
+	service := producerFixture(&MockNotificationRepository{}, &MockEventPublisher{}, &MockWebhookQueue{})
+	service.articleRepository.(*MockArticleRepository).getByIDFunc = func(ctx context.Context, articleID int64) (*repository.Article, error) {
+		return nil, repository.ErrInternal
+	}
+
+	event := producerEvent(t, repository.OutboxEventArticleUpdated, repository.ArticleEvent{ArticleID: 1, Status: repository.ArticleStatusPublished})
+	if err := service.QueueWebhooks(context.Background(), event); !errors.Is(err, repository.ErrInternal) {
+		t.Errorf("Expected error %v, got %v", repository.ErrInternal, err)
+	}
+}
@@ -7,12 +7,31 @@ import (
 	"github.com/Nilesh2000/conduit/internal/repository"
 )
 
-// Profile represents a user profile
+// Profile represents a user profile. The follower and following counts are only set when
+// the profile is requested on its own, and left out where it is embedded as an author.
+// Blocking and Muting report whether the current user blocked or muted the user.
 type Profile struct {
-	Username  string `json:"username"`
-	Bio       string `json:"bio"`
-	Image     string `json:"image"`
-	Following bool   `json:"following"`
+	Username       string `json:"username"`
+	Bio            string `json:"bio"`
+	Image          string `json:"image"`
+	Following      bool   `json:"following"`
+	Blocking       bool   `json:"blocking,omitempty"`
+	Muting         bool   `json:"muting,omitempty"`
+	FollowersCount *int   `json:"followersCount,omitempty"`
+	FollowingCount *int   `json:"followingCount,omitempty"`
+}
+
+// ProfileList represents a page of the followers or followed users of a user, newest
+// follow first. HasMore reports whether more profiles follow the page in the direction
+// it was read in, First and Last are the positions of the first and last profiles of the
+// page, which are nil if it is empty, and Count is the number of profiles across every
+// page.
+type ProfileList struct {
+	Profiles []Profile
+	HasMore  bool
+	First    *repository.Cursor
+	Last     *repository.Cursor
+	Count    int
 }
 
 // ProfileRepository is an interface for the profile repository
@@ -32,26 +51,63 @@ type ProfileRepository interface {
 		followerID int64,
 		followingID int64,
 	) (bool, error)
+	ListFollowers(
+		ctx context.Context,
+		userID int64,
+		currentUserID *int64,
+		filters repository.FollowFilters,
+	) (*repository.FollowListResult, error)
+	ListFollowing(
+		ctx context.Context,
+		userID int64,
+		currentUserID *int64,
+		filters repository.FollowFilters,
+	) (*repository.FollowListResult, error)
+	CountFollows(ctx context.Context, userID int64) (followers, following int, err error)
+}
+
+// BlockRepository is an interface for the block repository
+type BlockRepository interface {
+	BlockUser(
+		ctx context.Context,
+		blockerID int64,
+		blockedName string,
+	) (*repository.Profile, error)
+	UnblockUser(
+		ctx context.Context,
+		blockerID int64,
+		blockedName string,
+	) (*repository.Profile, error)
+	ListBlocked(ctx context.Context, blockerID int64) ([]repository.Profile, error)
+	IsBlocking(
+		ctx context.Context,
+		blockerID int64,
+		blockedID int64,
+	) (bool, error)
 }
 
 // profileService implements the profileService interface
 type profileService struct {
 	userRepository    UserRepository
 	profileRepository ProfileRepository
+	blockRepository   BlockRepository
 }
 
 // NewProfileService creates a new profile service
 func NewProfileService(
 	userRepository UserRepository,
 	profileRepository ProfileRepository,
+	blockRepository BlockRepository,
 ) *profileService {
 	return &profileService{
 		userRepository:    userRepository,
 		profileRepository: profileRepository,
+		blockRepository:   blockRepository,
 	}
 }
 
-// GetProfile gets a profile by username
+// GetProfile gets a profile by username. Users who blocked the current user are not
+// found.
 func (s *profileService) GetProfile(
 	ctx context.Context,
 	username string,
@@ -67,23 +123,37 @@ func (s *profileService) GetProfile(
 		}
 	}
 
-	var following bool
+	var following, blocking bool
 	if currentUserID != nil {
+		blocked, err := s.blockRepository.IsBlocking(ctx, user.ID, *currentUserID)
+		if err != nil {
+			return nil, ErrInternalServer
+		}
+		if blocked {
+			return nil, ErrUserNotFound
+		}
+
+		blocking, err = s.blockRepository.IsBlocking(ctx, *currentUserID, user.ID)
+		if err != nil {
+			return nil, ErrInternalServer
+		}
+
 		following, err = s.profileRepository.IsFollowing(ctx, *currentUserID, user.ID)
 		if err != nil {
 			return nil, ErrInternalServer
 		}
 	}
 
-	return &Profile{
+	return s.withFollowCounts(ctx, user.ID, &Profile{
 		Username:  user.Username,
 		Bio:       user.Bio,
 		Image:     user.Image,
 		Following: following,
-	}, nil
+		Blocking:  blocking,
+	})
 }
 
-// FollowUser follows a user
+// FollowUser follows a user, unless either of the users blocked the other
 func (s *profileService) FollowUser(
 	ctx context.Context,
 	followerID int64,
@@ -96,17 +166,19 @@ func (s *profileService) FollowUser(
 			return nil, ErrUserNotFound
 		case errors.Is(err, repository.ErrCannotFollowSelf):
 			return nil, ErrCannotFollowSelf
+		case errors.Is(err, repository.ErrUserBlocked):
+			return nil, ErrUserBlocked
 		default:
 			return nil, ErrInternalServer
 		}
 	}
 
-	return &Profile{
+	return s.withFollowCounts(ctx, profile.ID, &Profile{
 		Username:  profile.Username,
 		Bio:       profile.Bio,
 		Image:     profile.Image,
 		Following: profile.Following,
-	}, nil
+	})
 }
 
 // UnfollowUser unfollows a user
@@ -127,10 +199,164 @@ func (s *profileService) UnfollowUser(
 		}
 	}
 
+	return s.withFollowCounts(ctx, profile.ID, &Profile{
+		Username:  profile.Username,
+		Bio:       profile.Bio,
+		Image:     profile.Image,
+		Following: profile.Following,
+	})
+}
+
+// BlockUser blocks a user, which also stops the users following each other
+func (s *profileService) BlockUser(
+	ctx context.Context,
+	blockerID int64,
+	blockedName string,
+) (*Profile, error) {
+	return s.setBlocked(ctx, blockerID, blockedName, s.blockRepository.BlockUser)
+}
+
+// UnblockUser unblocks a user
+func (s *profileService) UnblockUser(
+	ctx context.Context,
+	blockerID int64,
+	blockedName string,
+) (*Profile, error) {
+	return s.setBlocked(ctx, blockerID, blockedName, s.blockRepository.UnblockUser)
+}
+
+// setBlocked blocks or unblocks a user with set
+func (s *profileService) setBlocked(
+	ctx context.Context,
+	blockerID int64,
+	blockedName string,
+	set func(ctx context.Context, blockerID int64, blockedName string) (*repository.Profile, error),
+) (*Profile, error) {
+	profile, err := set(ctx, blockerID, blockedName)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrUserNotFound):
+			return nil, ErrUserNotFound
+		case errors.Is(err, repository.ErrCannotBlockSelf):
+			return nil, ErrCannotBlockSelf
+		default:
+			return nil, ErrInternalServer
+		}
+	}
+
 	return &Profile{
 		Username:  profile.Username,
 		Bio:       profile.Bio,
 		Image:     profile.Image,
 		Following: profile.Following,
+		Blocking:  profile.Blocking,
 	}, nil
 }
+
+// GetBlockedUsers gets the users a user blocked, most recently blocked first
+func (s *profileService) GetBlockedUsers(ctx context.Context, blockerID int64) ([]Profile, error) {
+	blocked, err := s.blockRepository.ListBlocked(ctx, blockerID)
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+
+	profiles := make([]Profile, 0, len(blocked))
+	for _, profile := range blocked {
+		profiles = append(profiles, Profile{
+			Username: profile.Username,
+			Bio:      profile.Bio,
+			Image:    profile.Image,
+			Blocking: true,
+		})
+	}
+
+	return profiles, nil
+}
+
+// GetFollowers gets a page of the users following the user with the given username
+func (s *profileService) GetFollowers(
+	ctx context.Context,
+	username string,
+	currentUserID *int64,
+	filters repository.FollowFilters,
+) (*ProfileList, error) {
+	return s.listFollows(ctx, username, currentUserID, filters, s.profileRepository.ListFollowers)
+}
+
+// GetFollowing gets a page of the users followed by the user with the given username
+func (s *profileService) GetFollowing(
+	ctx context.Context,
+	username string,
+	currentUserID *int64,
+	filters repository.FollowFilters,
+) (*ProfileList, error) {
+	return s.listFollows(ctx, username, currentUserID, filters, s.profileRepository.ListFollowing)
+}
+
+// listFollows gets a page of a follow list of the user with the given username with list
+func (s *profileService) listFollows(
+	ctx context.Context,
+	username string,
+	currentUserID *int64,
+	filters repository.FollowFilters,
+	list func(
+		ctx context.Context,
+		userID int64,
+		currentUserID *int64,
+		filters repository.FollowFilters,
+	) (*repository.FollowListResult, error),
+) (*ProfileList, error) {
+	user, err := s.userRepository.FindByUsername(ctx, username)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrUserNotFound):
+			return nil, ErrUserNotFound
+		default:
+			return nil, ErrInternalServer
+		}
+	}
+
+	result, err := list(ctx, user.ID, currentUserID, filters)
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+
+	profiles := make([]Profile, 0, len(result.Profiles))
+	for _, profile := range result.Profiles {
+		profiles = append(profiles, Profile{
+			Username:  profile.Username,
+			Bio:       profile.Bio,
+			Image:     profile.Image,
+			Following: profile.Following,
+		})
+	}
+
+	page := &ProfileList{
+		Profiles: profiles,
+		HasMore:  result.HasMore,
+		Count:    result.Count,
+	}
+	if n := len(result.Profiles); n > 0 {
+		first, last := result.Profiles[0], result.Profiles[n-1]
+		page.First = &repository.Cursor{CreatedAt: first.FollowedAt, ID: first.ID}
+		page.Last = &repository.Cursor{CreatedAt: last.FollowedAt, ID: last.ID}
+	}
+
+	return page, nil
+}
+
+// withFollowCounts sets the follower and following counts of the profile of a user
+func (s *profileService) withFollowCounts(
+	ctx context.Context,
+	userID int64,
+	profile *Profile,
+) (*Profile, error) {
+	followers, following, err := s.profileRepository.CountFollows(ctx, userID)
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+
+	profile.FollowersCount = &followers
+	profile.FollowingCount = &following
+	return profile, nil
+}
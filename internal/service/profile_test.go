@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"reflect"
 	"testing"
 	"time"
 
@@ -11,9 +12,12 @@ import (
 
 // MockProfileRepository is a mock implementation of the ProfileRepository interface
 type MockProfileRepository struct {
-	followUserFunc   func(ctx context.Context, followerID int64, followingName string) (*repository.Profile, error)
-	unfollowUserFunc func(ctx context.Context, followerID int64, followingName string) (*repository.Profile, error)
-	isFollowingFunc  func(ctx context.Context, followerID int64, followingID int64) (bool, error)
+	followUserFunc    func(ctx context.Context, followerID int64, followingName string) (*repository.Profile, error)
+	unfollowUserFunc  func(ctx context.Context, followerID int64, followingName string) (*repository.Profile, error)
+	isFollowingFunc   func(ctx context.Context, followerID int64, followingID int64) (bool, error)
+	listFollowersFunc func(ctx context.Context, userID int64, currentUserID *int64, filters repository.FollowFilters) (*repository.FollowListResult, error)
+	listFollowingFunc func(ctx context.Context, userID int64, currentUserID *int64, filters repository.FollowFilters) (*repository.FollowListResult, error)
+	countFollowsFunc  func(ctx context.Context, userID int64) (int, int, error)
 }
 
 var _ ProfileRepository = (*MockProfileRepository)(nil)
@@ -45,6 +49,84 @@ func (m *MockProfileRepository) IsFollowing(
 	return m.isFollowingFunc(ctx, followerID, followingID)
 }
 
+// ListFollowers lists the followers of a user
+func (m *MockProfileRepository) ListFollowers(
+	ctx context.Context,
+	userID int64,
+	currentUserID *int64,
+	filters repository.FollowFilters,
+) (*repository.FollowListResult, error) {
+	return m.listFollowersFunc(ctx, userID, currentUserID, filters)
+}
+
+// ListFollowing lists the users a user follows
+func (m *MockProfileRepository) ListFollowing(
+	ctx context.Context,
+	userID int64,
+	currentUserID *int64,
+	filters repository.FollowFilters,
+) (*repository.FollowListResult, error) {
+	return m.listFollowingFunc(ctx, userID, currentUserID, filters)
+}
+
+// CountFollows counts the followers of a user and the users they follow, which are
+// both 0 unless countFollowsFunc is set
+func (m *MockProfileRepository) CountFollows(ctx context.Context, userID int64) (int, int, error) {
+	if m.countFollowsFunc == nil {
+		return 0, 0, nil
+	}
+	return m.countFollowsFunc(ctx, userID)
+}
+
+// MockBlockRepository is a mock implementation of the BlockRepository interface
+type MockBlockRepository struct {
+	blockUserFunc   func(ctx context.Context, blockerID int64, blockedName string) (*repository.Profile, error)
+	unblockUserFunc func(ctx context.Context, blockerID int64, blockedName string) (*repository.Profile, error)
+	listBlockedFunc func(ctx context.Context, blockerID int64) ([]repository.Profile, error)
+	isBlockingFunc  func(ctx context.Context, blockerID int64, blockedID int64) (bool, error)
+}
+
+var _ BlockRepository = (*MockBlockRepository)(nil)
+
+// BlockUser blocks a user
+func (m *MockBlockRepository) BlockUser(
+	ctx context.Context,
+	blockerID int64,
+	blockedName string,
+) (*repository.Profile, error) {
+	return m.blockUserFunc(ctx, blockerID, blockedName)
+}
+
+// UnblockUser unblocks a user
+func (m *MockBlockRepository) UnblockUser(
+	ctx context.Context,
+	blockerID int64,
+	blockedName string,
+) (*repository.Profile, error) {
+	return m.unblockUserFunc(ctx, blockerID, blockedName)
+}
+
+// ListBlocked lists the users a user blocked
+func (m *MockBlockRepository) ListBlocked(
+	ctx context.Context,
+	blockerID int64,
+) ([]repository.Profile, error) {
+	return m.listBlockedFunc(ctx, blockerID)
+}
+
+// IsBlocking checks if a user blocked another user, which is false unless isBlockingFunc
+// is set
+func (m *MockBlockRepository) IsBlocking(
+	ctx context.Context,
+	blockerID int64,
+	blockedID int64,
+) (bool, error) {
+	if m.isBlockingFunc == nil {
+		return false, nil
+	}
+	return m.isBlockingFunc(ctx, blockerID, blockedID)
+}
+
 // Test_profileService_GetByUsername tests the GetByUsername method of the profileService
 func Test_profileService_GetProfile(t *testing.T) {
 	tests := []struct {
@@ -82,6 +164,9 @@ func Test_profileService_GetProfile(t *testing.T) {
 					isFollowingFunc: func(ctx context.Context, followerID int64, followingID int64) (bool, error) {
 						return false, nil
 					},
+					countFollowsFunc: func(ctx context.Context, userID int64) (int, int, error) {
+						return 3, 5, nil
+					},
 				}
 				return userRepo, profileRepo
 			},
@@ -90,6 +175,12 @@ func Test_profileService_GetProfile(t *testing.T) {
 				if profile.Username != "testuser" {
 					t.Errorf("Expected username to be testuser, got %q", profile.Username)
 				}
+				if profile.FollowersCount == nil || *profile.FollowersCount != 3 {
+					t.Errorf("Expected 3 followers, got %v", profile.FollowersCount)
+				}
+				if profile.FollowingCount == nil || *profile.FollowingCount != 5 {
+					t.Errorf("Expected 5 followed users, got %v", profile.FollowingCount)
+				}
 				if profile.Bio != "Test bio" {
 					t.Errorf("Expected bio to be Test bio, got %q", profile.Bio)
 				}
@@ -206,7 +297,7 @@ func Test_profileService_GetProfile(t *testing.T) {
 			userRepo, profileRepo := tt.setupMock()
 
 			// Create service
-			service := NewProfileService(userRepo, profileRepo)
+			service := NewProfileService(userRepo, profileRepo, &MockBlockRepository{})
 
 			// Call GetProfile
 			profile, err := service.GetProfile(
@@ -341,7 +432,7 @@ func Test_profileService_FollowUser(t *testing.T) {
 			userRepo, profileRepo := tt.setupMock()
 
 			// Create service
-			service := NewProfileService(userRepo, profileRepo)
+			service := NewProfileService(userRepo, profileRepo, &MockBlockRepository{})
 
 			// Call FollowUser
 			profile, err := service.FollowUser(
@@ -476,7 +567,7 @@ func Test_profileService_UnfollowUser(t *testing.T) {
 			userRepo, profileRepo := tt.setupMock()
 
 			// Create service
-			service := NewProfileService(userRepo, profileRepo)
+			service := NewProfileService(userRepo, profileRepo, &MockBlockRepository{})
 
 			// Call UnfollowUser
 			profile, err := service.UnfollowUser(
@@ -497,3 +588,230 @@ func Test_profileService_UnfollowUser(t *testing.T) {
 		})
 	}
 }
+
+// Test_profileService_GetFollowers tests the GetFollowers method of the profileService
+func Test_profileService_GetFollowers(t *testing.T) {
+	t.Parallel()
+
+	followedAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	currentUserID := int64(3)
+
+	tests := []struct {
+		name              string
+		username          string
+		expectedErr       error
+		expectedUsernames []string
+	}{
+		{
+			name:              "Followers found",
+			username:          "testuser",
+			expectedErr:       nil,
+			expectedUsernames: []string{"follower", "other"},
+		},
+		{
+			name:        "User not found",
+			username:    "unknown",
+			expectedErr: ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock repositories
+			userRepo := &MockUserRepository{
+				findByUsernameFunc: func(ctx context.Context, username string) (*repository.User, error) {
+					if username != "testuser" {
+						return nil, repository.ErrUserNotFound
+					}
+					return &repository.User{ID: 1, Username: username}, nil
+				},
+			}
+			profileRepo := &MockProfileRepository{
+				listFollowersFunc: func(ctx context.Context, userID int64, viewerID *int64, filters repository.FollowFilters) (*repository.FollowListResult, error) {
+					if userID != 1 || viewerID == nil || *viewerID != currentUserID || filters.Limit != 2 {
+						t.Errorf("Expected 2 followers of user 1 seen by user 3, got %d of user %d seen by %v", filters.Limit, userID, viewerID)
+					}
+					return &repository.FollowListResult{
+						Profiles: []repository.Profile{
+							{ID: 4, Username: "follower", Following: true, FollowedAt: followedAt.Add(time.Hour)},
+							{ID: 2, Username: "other", FollowedAt: followedAt},
+						},
+						HasMore: true,
+						Count:   5,
+					}, nil
+				},
+			}
+
+			// Create service
+			service := NewProfileService(userRepo, profileRepo, &MockBlockRepository{})
+
+			// Call GetFollowers
+			list, err := service.GetFollowers(
+				context.Background(),
+				tt.username,
+				&currentUserID,
+				repository.FollowFilters{Limit: 2},
+			)
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate list if no error
+			if err == nil {
+				var usernames []string
+				for _, profile := range list.Profiles {
+					usernames = append(usernames, profile.Username)
+				}
+				if !reflect.DeepEqual(usernames, tt.expectedUsernames) {
+					t.Errorf("Expected followers %v, got %v", tt.expectedUsernames, usernames)
+				}
+				if !list.Profiles[0].Following || list.Profiles[1].Following {
+					t.Errorf("Expected only the first follower to be followed, got %+v", list.Profiles)
+				}
+				if !list.HasMore || list.Count != 5 {
+					t.Errorf("Expected more of 5 followers, got hasMore %v and count %d", list.HasMore, list.Count)
+				}
+				expectedLast := repository.Cursor{CreatedAt: followedAt, ID: 2}
+				if list.Last == nil || *list.Last != expectedLast {
+					t.Errorf("Expected last cursor %+v, got %+v", expectedLast, list.Last)
+				}
+			}
+		})
+	}
+}
+
+// Test_profileService_GetProfile_Blocks tests that GetProfile hides users who blocked the
+// current user and flags users the current user blocked
+func Test_profileService_GetProfile_Blocks(t *testing.T) {
+	t.Parallel()
+
+	currentUserID := int64(2)
+
+	tests := []struct {
+		name             string
+		blockerID        int64
+		expectedErr      error
+		expectedBlocking bool
+	}{
+		{
+			name:        "Blocked by the user",
+			blockerID:   1,
+			expectedErr: ErrUserNotFound,
+		},
+		{
+			name:             "Blocking the user",
+			blockerID:        currentUserID,
+			expectedErr:      nil,
+			expectedBlocking: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock repositories
+			userRepo := &MockUserRepository{
+				findByUsernameFunc: func(ctx context.Context, username string) (*repository.User, error) {
+					return &repository.User{ID: 1, Username: username}, nil
+				},
+			}
+			profileRepo := &MockProfileRepository{
+				isFollowingFunc: func(ctx context.Context, followerID int64, followingID int64) (bool, error) {
+					return false, nil
+				},
+			}
+			blockRepo := &MockBlockRepository{
+				isBlockingFunc: func(ctx context.Context, blockerID int64, blockedID int64) (bool, error) {
+					return blockerID == tt.blockerID, nil
+				},
+			}
+
+			// Create service
+			service := NewProfileService(userRepo, profileRepo, blockRepo)
+
+			// Call GetProfile
+			profile, err := service.GetProfile(context.Background(), "testuser", &currentUserID)
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate profile if no error
+			if err == nil && profile.Blocking != tt.expectedBlocking {
+				t.Errorf("Expected blocking %v, got %v", tt.expectedBlocking, profile.Blocking)
+			}
+		})
+	}
+}
+
+// Test_profileService_BlockUser tests the BlockUser method of the profileService
+func Test_profileService_BlockUser(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		blockErr    error
+		expectedErr error
+	}{
+		{
+			name:        "User blocked",
+			expectedErr: nil,
+		},
+		{
+			name:        "User not found",
+			blockErr:    repository.ErrUserNotFound,
+			expectedErr: ErrUserNotFound,
+		},
+		{
+			name:        "Blocking yourself",
+			blockErr:    repository.ErrCannotBlockSelf,
+			expectedErr: ErrCannotBlockSelf,
+		},
+		{
+			name:        "Repository error",
+			blockErr:    repository.ErrInternal,
+			expectedErr: ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock repository
+			blockRepo := &MockBlockRepository{
+				blockUserFunc: func(ctx context.Context, blockerID int64, blockedName string) (*repository.Profile, error) {
+					if tt.blockErr != nil {
+						return nil, tt.blockErr
+					}
+					return &repository.Profile{ID: 2, Username: blockedName, Blocking: true}, nil
+				},
+			}
+
+			// Create service
+			service := NewProfileService(&MockUserRepository{}, &MockProfileRepository{}, blockRepo)
+
+			// Call BlockUser
+			profile, err := service.BlockUser(context.Background(), 1, "harasser")
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate profile if no error
+			if err == nil && (!profile.Blocking || profile.Following || profile.Username != "harasser") {
+				t.Errorf("Expected a blocked profile of harasser, got %+v", profile)
+			}
+		})
+	}
+}
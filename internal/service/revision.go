@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Nilesh2000/conduit/internal/diff"
+	"github.com/Nilesh2000/conduit/internal/repository"
+)
+
+// ArticleRevision represents a saved version of an article
+type ArticleRevision struct {
+	Number      int       `json:"number"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Body        string    `json:"body"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// RevisionDiff represents the line-based changes between two revisions of an article.
+// A From of 0 compares against an empty article.
+type RevisionDiff struct {
+	From        int         `json:"from"`
+	To          int         `json:"to"`
+	Title       []diff.Line `json:"title"`
+	Description []diff.Line `json:"description"`
+	Body        []diff.Line `json:"body"`
+}
+
+// RevisionRepository is an interface for the article revision repository
+type RevisionRepository interface {
+	ListByArticleID(ctx context.Context, articleID int64) ([]repository.ArticleRevision, error)
+	GetByNumber(
+		ctx context.Context,
+		articleID int64,
+		number int,
+	) (*repository.ArticleRevision, error)
+}
+
+// revisionService implements the RevisionService interface
+type revisionService struct {
+	revisionRepository RevisionRepository
+	articleRepository  ArticleRepository
+	slugGenerator      *SlugGenerator
+}
+
+// NewRevisionService creates a new revision service
+func NewRevisionService(
+	revisionRepository RevisionRepository,
+	articleRepository ArticleRepository,
+	slugGenerator *SlugGenerator,
+) *revisionService {
+	return &revisionService{
+		revisionRepository: revisionRepository,
+		articleRepository:  articleRepository,
+		slugGenerator:      slugGenerator,
+	}
+}
+
+// ListRevisions lists the revisions of an article, newest first
+func (s *revisionService) ListRevisions(
+	ctx context.Context,
+	slug string,
+	currentUserID *int64,
+) ([]ArticleRevision, error) {
+	article, err := getViewableArticle(ctx, s.articleRepository, slug, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	revisionsRepo, err := s.revisionRepository.ListByArticleID(ctx, article.ID)
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+
+	revisions := make([]ArticleRevision, len(revisionsRepo))
+	for i, revision := range revisionsRepo {
+		revisions[i] = toArticleRevision(&revision)
+	}
+
+	return revisions, nil
+}
+
+// GetRevision gets a revision of an article by its number
+func (s *revisionService) GetRevision(
+	ctx context.Context,
+	slug string,
+	number int,
+	currentUserID *int64,
+) (*ArticleRevision, error) {
+	article, err := getViewableArticle(ctx, s.articleRepository, slug, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := s.getRevision(ctx, article.ID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	result := toArticleRevision(revision)
+	return &result, nil
+}
+
+// DiffRevisions compares two revisions of an article line by line
+func (s *revisionService) DiffRevisions(
+	ctx context.Context,
+	slug string,
+	from, to int,
+	currentUserID *int64,
+) (*RevisionDiff, error) {
+	article, err := getViewableArticle(ctx, s.articleRepository, slug, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Revision 0 stands for the empty article before the first revision
+	fromRevision := &repository.ArticleRevision{}
+	if from != 0 {
+		fromRevision, err = s.getRevision(ctx, article.ID, from)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	toRevision, err := s.getRevision(ctx, article.ID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RevisionDiff{
+		From:        from,
+		To:          to,
+		Title:       diff.Lines(fromRevision.Title, toRevision.Title),
+		Description: diff.Lines(fromRevision.Description, toRevision.Description),
+		Body:        diff.Lines(fromRevision.Body, toRevision.Body),
+	}, nil
+}
+
+// RestoreRevision restores the text of an article to one of its revisions. The
+// restored text is recorded as a new revision, so the restore can be undone.
+func (s *revisionService) RestoreRevision(
+	ctx context.Context,
+	userID int64,
+	slug string,
+	number int,
+) (*Article, error) {
+	article, err := getViewableArticle(ctx, s.articleRepository, slug, &userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if user is the author
+	if article.AuthorID != userID {
+		return nil, ErrArticleNotAuthorized
+	}
+
+	revision, err := s.getRevision(ctx, article.ID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update the article with the text of the revision
+	article, err = s.articleRepository.Update(
+		ctx,
+		userID,
+		slug,
+		s.slugGenerator.newSlugs(article, &revision.Title),
+		&revision.Title,
+		&revision.Description,
+		&revision.Body,
+		repository.TagChanges{},
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrArticleNotFound):
+			return nil, ErrArticleNotFound
+		case errors.Is(err, repository.ErrDuplicateSlug):
+			return nil, ErrArticleAlreadyExists
+		default:
+			return nil, ErrInternalServer
+		}
+	}
+
+	// Get favorites count
+	favoritesCount, err := s.articleRepository.GetFavoritesCount(ctx, article.ID)
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+
+	// Check if user has favorited the article
+	favorited, err := s.articleRepository.IsFavorited(ctx, userID, article.ID)
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+
+	return &Article{
+		Slug:           article.Slug,
+		Title:          article.Title,
+		Description:    article.Description,
+		Body:           article.Body,
+		TagList:        article.TagList,
+		CreatedAt:      article.CreatedAt,
+		UpdatedAt:      article.UpdatedAt,
+		Favorited:      favorited,
+		FavoritesCount: favoritesCount,
+		Author: Profile{
+			Username:  article.Author.Username,
+			Bio:       article.Author.Bio,
+			Image:     article.Author.Image,
+			Following: false,
+		},
+		Status:      article.Status,
+		PublishAt:   article.PublishAt,
+		PublishedAt: article.PublishedAt,
+	}, nil
+}
+
+// getRevision gets a revision of an article, mapping repository errors
+func (s *revisionService) getRevision(
+	ctx context.Context,
+	articleID int64,
+	number int,
+) (*repository.ArticleRevision, error) {
+	revision, err := s.revisionRepository.GetByNumber(ctx, articleID, number)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRevisionNotFound):
+			return nil, ErrRevisionNotFound
+		default:
+			return nil, ErrInternalServer
+		}
+	}
+
+	return revision, nil
+}
+
+// toArticleRevision converts a repository revision to a service revision
+func toArticleRevision(revision *repository.ArticleRevision) ArticleRevision {
+	return ArticleRevision{
+		Number:      revision.Number,
+		Title:       revision.Title,
+		Description: revision.Description,
+		Body:        revision.Body,
+		CreatedAt:   revision.CreatedAt,
+	}
+}
@@ -0,0 +1,364 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Nilesh2000/conduit/internal/diff"
+	"github.com/Nilesh2000/conduit/internal/repository"
+)
+
+// MockRevisionRepository is a mock implementation of the RevisionRepository interface
+type MockRevisionRepository struct {
+	listByArticleIDFunc func(ctx context.Context, articleID int64) ([]repository.ArticleRevision, error)
+	getByNumberFunc     func(ctx context.Context, articleID int64, number int) (*repository.ArticleRevision, error)
+}
+
+// ListByArticleID is a mock implementation of the ListByArticleID method
+func (m *MockRevisionRepository) ListByArticleID(
+	ctx context.Context,
+	articleID int64,
+) ([]repository.ArticleRevision, error) {
+	return m.listByArticleIDFunc(ctx, articleID)
+}
+
+// GetByNumber is a mock implementation of the GetByNumber method
+func (m *MockRevisionRepository) GetByNumber(
+	ctx context.Context,
+	articleID int64,
+	number int,
+) (*repository.ArticleRevision, error) {
+	return m.getByNumberFunc(ctx, articleID, number)
+}
+
+// revisionFixtures are the revisions of the article returned by revisionArticleRepository
+var revisionFixtures = map[int]*repository.ArticleRevision{
+	1: {ID: 1, ArticleID: 1, Number: 1, Title: "Title", Description: "Description", Body: "one\ntwo"},
+	2: {ID: 2, ArticleID: 1, Number: 2, Title: "Title", Description: "Description", Body: "one\n2"},
+}
+
+// revisionArticleRepository returns a mock article repository holding a single article
+// with the given status written by user 1
+func revisionArticleRepository(status string) *MockArticleRepository {
+	return &MockArticleRepository{
+		getBySlugFunc: func(ctx context.Context, slug string) (*repository.Article, error) {
+			if slug != "test-article" {
+				return nil, repository.ErrArticleNotFound
+			}
+			return &repository.Article{
+				ID:       1,
+				Slug:     slug,
+				Title:    "Title",
+				AuthorID: 1,
+				Author:   &repository.User{ID: 1, Username: "author"},
+				Status:   status,
+			}, nil
+		},
+	}
+}
+
+// revisionRepository returns a mock revision repository holding revisionFixtures
+func revisionRepository() *MockRevisionRepository {
+	return &MockRevisionRepository{
+		listByArticleIDFunc: func(ctx context.Context, articleID int64) ([]repository.ArticleRevision, error) {
+			return []repository.ArticleRevision{*revisionFixtures[2], *revisionFixtures[1]}, nil
+		},
+		getByNumberFunc: func(ctx context.Context, articleID int64, number int) (*repository.ArticleRevision, error) {
+			revision, ok := revisionFixtures[number]
+			if !ok {
+				return nil, repository.ErrRevisionNotFound
+			}
+			return revision, nil
+		},
+	}
+}
+
+// Test_revisionService_ListRevisions tests the ListRevisions method of the revisionService
+func Test_revisionService_ListRevisions(t *testing.T) {
+	t.Parallel()
+
+	otherID := int64(2)
+
+	tests := []struct {
+		name            string
+		slug            string
+		status          string
+		currentUserID   *int64
+		expectedErr     error
+		expectedNumbers []int
+	}{
+		{
+			name:            "Published article",
+			slug:            "test-article",
+			status:          repository.ArticleStatusPublished,
+			expectedErr:     nil,
+			expectedNumbers: []int{2, 1},
+		},
+		{
+			name:          "Draft of another user",
+			slug:          "test-article",
+			status:        repository.ArticleStatusDraft,
+			currentUserID: &otherID,
+			expectedErr:   ErrArticleNotFound,
+		},
+		{
+			name:        "Article not found",
+			slug:        "non-existent-article",
+			status:      repository.ArticleStatusPublished,
+			expectedErr: ErrArticleNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Create service with mock repositories
+			revisionService := NewRevisionService(
+				revisionRepository(),
+				revisionArticleRepository(tt.status),
+				testSlugGenerator,
+			)
+
+			// Call method
+			revisions, err := revisionService.ListRevisions(context.Background(), tt.slug, tt.currentUserID)
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate revisions if no error
+			if err == nil {
+				var numbers []int
+				for _, revision := range revisions {
+					numbers = append(numbers, revision.Number)
+				}
+				if !reflect.DeepEqual(numbers, tt.expectedNumbers) {
+					t.Errorf("Expected revisions %v, got %v", tt.expectedNumbers, numbers)
+				}
+			}
+		})
+	}
+}
+
+// Test_revisionService_GetRevision tests the GetRevision method of the revisionService
+func Test_revisionService_GetRevision(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		number      int
+		expectedErr error
+	}{
+		{
+			name:        "Revision found",
+			number:      1,
+			expectedErr: nil,
+		},
+		{
+			name:        "Revision not found",
+			number:      3,
+			expectedErr: ErrRevisionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Create service with mock repositories
+			revisionService := NewRevisionService(
+				revisionRepository(),
+				revisionArticleRepository(repository.ArticleStatusPublished),
+				testSlugGenerator,
+			)
+
+			// Call method
+			revision, err := revisionService.GetRevision(context.Background(), "test-article", tt.number, nil)
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate revision if no error
+			if err == nil && (revision.Number != tt.number || revision.Body != revisionFixtures[tt.number].Body) {
+				t.Errorf("Expected revision %d, got %+v", tt.number, revision)
+			}
+		})
+	}
+}
+
+// Test_revisionService_DiffRevisions tests the DiffRevisions method of the revisionService
+func Test_revisionService_DiffRevisions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		from         int
+		to           int
+		expectedErr  error
+		expectedBody []diff.Line
+	}{
+		{
+			name:        "Consecutive revisions",
+			from:        1,
+			to:          2,
+			expectedErr: nil,
+			expectedBody: []diff.Line{
+				{Op: diff.OpEqual, Text: "one"},
+				{Op: diff.OpDelete, Text: "two"},
+				{Op: diff.OpInsert, Text: "2"},
+			},
+		},
+		{
+			name:        "First revision against the empty article",
+			from:        0,
+			to:          1,
+			expectedErr: nil,
+			expectedBody: []diff.Line{
+				{Op: diff.OpInsert, Text: "one"},
+				{Op: diff.OpInsert, Text: "two"},
+			},
+		},
+		{
+			name:        "Unknown revision",
+			from:        1,
+			to:          3,
+			expectedErr: ErrRevisionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Create service with mock repositories
+			revisionService := NewRevisionService(
+				revisionRepository(),
+				revisionArticleRepository(repository.ArticleStatusPublished),
+				testSlugGenerator,
+			)
+
+			// Call method
+			revisionDiff, err := revisionService.DiffRevisions(context.Background(), "test-article", tt.from, tt.to, nil)
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate diff if no error
+			if err == nil {
+				if revisionDiff.From != tt.from || revisionDiff.To != tt.to {
+					t.Errorf("Expected diff from %d to %d, got %d to %d", tt.from, tt.to, revisionDiff.From, revisionDiff.To)
+				}
+				if !reflect.DeepEqual(revisionDiff.Body, tt.expectedBody) {
+					t.Errorf("Expected body diff %v, got %v", tt.expectedBody, revisionDiff.Body)
+				}
+			}
+		})
+	}
+}
+
+// Test_revisionService_RestoreRevision tests the RestoreRevision method of the revisionService
+func Test_revisionService_RestoreRevision(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		userID      int64
+		number      int
+		updateErr   error
+		expectedErr error
+	}{
+		{
+			name:        "Author restores a revision",
+			userID:      1,
+			number:      1,
+			expectedErr: nil,
+		},
+		{
+			name:        "Not the author",
+			userID:      2,
+			number:      1,
+			expectedErr: ErrArticleNotAuthorized,
+		},
+		{
+			name:        "Revision not found",
+			userID:      1,
+			number:      3,
+			expectedErr: ErrRevisionNotFound,
+		},
+		{
+			name:        "Repository error",
+			userID:      1,
+			number:      1,
+			updateErr:   repository.ErrInternal,
+			expectedErr: ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock repositories
+			updated := false
+			articleRepository := revisionArticleRepository(repository.ArticleStatusPublished)
+			articleRepository.updateFunc = func(ctx context.Context, userID int64, slug string, newSlugs []string, title, description, body *string, tags repository.TagChanges) (*repository.Article, error) {
+				updated = true
+				if tt.updateErr != nil {
+					return nil, tt.updateErr
+				}
+				return &repository.Article{
+					ID:          1,
+					Slug:        slug,
+					Title:       *title,
+					Description: *description,
+					Body:        *body,
+					AuthorID:    1,
+					Author:      &repository.User{ID: 1, Username: "author"},
+					Status:      repository.ArticleStatusPublished,
+					UpdatedAt:   time.Now(),
+				}, nil
+			}
+			articleRepository.getFavoritesCountFunc = func(ctx context.Context, articleID int64) (int, error) {
+				return 0, nil
+			}
+			articleRepository.isFavoritedFunc = func(ctx context.Context, userID int64, articleID int64) (bool, error) {
+				return false, nil
+			}
+
+			// Create service with mock repositories
+			revisionService := NewRevisionService(revisionRepository(), articleRepository, testSlugGenerator)
+
+			// Call method
+			article, err := revisionService.RestoreRevision(context.Background(), tt.userID, "test-article", tt.number)
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate that only permitted restores update the article
+			expectUpdate := tt.expectedErr == nil || tt.updateErr != nil
+			if updated != expectUpdate {
+				t.Errorf("Expected update to be called: %v, got %v", expectUpdate, updated)
+			}
+
+			// Validate article if no error
+			if err == nil && article.Body != revisionFixtures[tt.number].Body {
+				t.Errorf("Expected body %q, got %q", revisionFixtures[tt.number].Body, article.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"crypto/rand"
+	"strconv"
+
+	"github.com/Nilesh2000/conduit/internal/config"
+	"github.com/Nilesh2000/conduit/internal/repository"
+
+	"github.com/gosimple/slug"
+)
+
+// randomIDAlphabet is the alphabet of the random IDs appended to slugs
+const randomIDAlphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// randomIDLength is the length of the random IDs appended to slugs
+const randomIDLength = 6
+
+// randomFallbacks is the number of slugs with a random ID tried once the slugs of the
+// strategy are all taken, so that an article can be created whatever its title
+const randomFallbacks = 3
+
+// SlugGenerator generates the slugs an article can have. The first candidate for a
+// title is its plain slug, and the others tell it apart from articles that already
+// use it, either by a counter or by a short random ID.
+type SlugGenerator struct {
+	strategy    string
+	maxAttempts int
+}
+
+// NewSlugGenerator creates a new SlugGenerator that tries up to maxAttempts slugs
+func NewSlugGenerator(strategy string, maxAttempts int) *SlugGenerator {
+	return &SlugGenerator{
+		strategy:    strategy,
+		maxAttempts: max(maxAttempts, 1),
+	}
+}
+
+// Candidates returns the slugs to try for a title, in order. The maxAttempts slugs of
+// the strategy are followed by a few with a random ID, which are only tried once the
+// others are taken, such as when a title was used more than maxAttempts times.
+func (g *SlugGenerator) Candidates(title string) []string {
+	base := generateSlug(title)
+
+	candidates := make([]string, 0, g.maxAttempts+randomFallbacks)
+	candidates = append(candidates, base)
+	for attempt := 2; len(candidates) < g.maxAttempts; attempt++ {
+		if g.strategy == config.SlugStrategyRandom {
+			candidates = append(candidates, base+"-"+randomID())
+		} else {
+			candidates = append(candidates, base+"-"+strconv.Itoa(attempt))
+		}
+	}
+	for range randomFallbacks {
+		candidates = append(candidates, base+"-"+randomID())
+	}
+
+	return candidates
+}
+
+// newSlugs returns the slugs an article moves to when its title changes, or nil if it
+// keeps its slug because the new title has the same slug as its current one
+func (g *SlugGenerator) newSlugs(article *repository.Article, title *string) []string {
+	if title == nil || generateSlug(*title) == generateSlug(article.Title) {
+		return nil
+	}
+	return g.Candidates(*title)
+}
+
+// randomID returns a short random ID
+func randomID() string {
+	b := make([]byte, randomIDLength)
+	// crypto/rand.Read never returns an error
+	_, _ = rand.Read(b)
+
+	for i := range b {
+		b[i] = randomIDAlphabet[int(b[i])%len(randomIDAlphabet)]
+	}
+
+	return string(b)
+}
+
+// generateSlug generates a slug from a title
+func generateSlug(title string) string {
+	return slug.Make(title)
+}
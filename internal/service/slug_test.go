@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Nilesh2000/conduit/internal/config"
+	"github.com/Nilesh2000/conduit/internal/repository"
+)
+
+// Test_SlugGenerator_Candidates tests the Candidates method of the SlugGenerator
+func Test_SlugGenerator_Candidates(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		strategy    string
+		maxAttempts int
+		expected    *regexp.Regexp
+	}{
+		{
+			name:        "Suffix strategy",
+			strategy:    config.SlugStrategySuffix,
+			maxAttempts: 4,
+			expected:    regexp.MustCompile(`^introduction-to-go introduction-to-go-2 introduction-to-go-3 introduction-to-go-4( introduction-to-go-[a-z0-9]{6}){3}$`),
+		},
+		{
+			name:        "Random strategy",
+			strategy:    config.SlugStrategyRandom,
+			maxAttempts: 3,
+			expected:    regexp.MustCompile(`^introduction-to-go( introduction-to-go-[a-z0-9]{6}){5}$`),
+		},
+		{
+			name:        "Single attempt",
+			strategy:    config.SlugStrategyRandom,
+			maxAttempts: 1,
+			expected:    regexp.MustCompile(`^introduction-to-go( introduction-to-go-[a-z0-9]{6}){3}$`),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Call method
+			candidates := NewSlugGenerator(tt.strategy, tt.maxAttempts).Candidates("Introduction to Go")
+
+			// Validate candidates
+			if !tt.expected.MatchString(strings.Join(candidates, " ")) {
+				t.Errorf("Expected candidates matching %s, got %v", tt.expected, candidates)
+			}
+		})
+	}
+}
+
+// Test_SlugGenerator_newSlugs tests that an article only moves to new slugs when its
+// title no longer has the slug of its current title
+func Test_SlugGenerator_newSlugs(t *testing.T) {
+	t.Parallel()
+
+	strPtr := func(s string) *string {
+		return &s
+	}
+
+	tests := []struct {
+		name         string
+		currentTitle string
+		currentSlug  string
+		title        *string
+		expectedSlug string
+	}{
+		{
+			name:         "Title not changed",
+			currentTitle: "Introduction to Go",
+			currentSlug:  "introduction-to-go",
+			title:        nil,
+			expectedSlug: "",
+		},
+		{
+			name:         "Title with the same slug",
+			currentTitle: "Introduction to Go",
+			currentSlug:  "introduction-to-go",
+			title:        strPtr("Introduction to Go!"),
+			expectedSlug: "",
+		},
+		{
+			name:         "Same title with a suffixed slug",
+			currentTitle: "Introduction to Go",
+			currentSlug:  "introduction-to-go-2",
+			title:        strPtr("Introduction to Go"),
+			expectedSlug: "",
+		},
+		{
+			name:         "Title cut to a word of the slug",
+			currentTitle: "Go Update",
+			currentSlug:  "go-update",
+			title:        strPtr("Go"),
+			expectedSlug: "go",
+		},
+		{
+			name:         "Title without its number",
+			currentTitle: "Top 10",
+			currentSlug:  "top-10",
+			title:        strPtr("Top"),
+			expectedSlug: "top",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Call method
+			article := &repository.Article{Title: tt.currentTitle, Slug: tt.currentSlug}
+			got := NewSlugGenerator(config.SlugStrategySuffix, 10).newSlugs(article, tt.title)
+
+			// Validate result
+			if tt.expectedSlug == "" {
+				if got != nil {
+					t.Errorf("Expected the article to keep its slug, got %v", got)
+				}
+				return
+			}
+			if len(got) == 0 || got[0] != tt.expectedSlug {
+				t.Errorf("Expected slugs starting with %s, got %v", tt.expectedSlug, got)
+			}
+		})
+	}
+}
+
+// Test_articleService_CreateArticle_ConcurrentSlugs tests that articles created
+// concurrently with the same title all get a slug of their own
+func Test_articleService_CreateArticle_ConcurrentSlugs(t *testing.T) {
+	t.Parallel()
+
+	const writers = 8
+
+	tests := []struct {
+		name        string
+		strategy    string
+		maxAttempts int
+	}{
+		{
+			name:        "Suffix strategy",
+			strategy:    config.SlugStrategySuffix,
+			maxAttempts: writers,
+		},
+		{
+			name:        "Random strategy",
+			strategy:    config.SlugStrategyRandom,
+			maxAttempts: writers,
+		},
+		{
+			name:        "Suffix strategy with more writers than attempts",
+			strategy:    config.SlugStrategySuffix,
+			maxAttempts: 3,
+		},
+		{
+			name:        "Random strategy with more writers than attempts",
+			strategy:    config.SlugStrategyRandom,
+			maxAttempts: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock repository that takes the first free slug like the database does
+			var mu sync.Mutex
+			taken := make(map[string]bool)
+			mockArticleRepository := &MockArticleRepository{
+				createFunc: func(ctx context.Context, userID int64, slugs []string, title, description, body, status string, publishAt *time.Time, tagList []string) (*repository.Article, error) {
+					mu.Lock()
+					defer mu.Unlock()
+
+					for _, slug := range slugs {
+						if taken[slug] {
+							continue
+						}
+						taken[slug] = true
+						return &repository.Article{
+							ID:       int64(len(taken)),
+							Slug:     slug,
+							Title:    title,
+							AuthorID: userID,
+							Author:   &repository.User{ID: userID, Username: "author"},
+							Status:   status,
+						}, nil
+					}
+					return nil, repository.ErrDuplicateSlug
+				},
+			}
+
+			// Create service with mock repositories
+			articleService := NewArticleService(
+				mockArticleRepository,
+				&MockProfileRepository{},
+				NewSlugGenerator(tt.strategy, tt.maxAttempts),
+			)
+
+			// Create the articles concurrently
+			var wg sync.WaitGroup
+			slugs := make(chan string, writers)
+			for i := range writers {
+				wg.Add(1)
+				go func(userID int64) {
+					defer wg.Done()
+
+					article, err := articleService.CreateArticle(
+						context.Background(),
+						userID,
+						"Introduction to Go",
+						"Description",
+						"Body",
+						"",
+						nil,
+						nil,
+					)
+					if err != nil {
+						t.Errorf("Expected no error, got %v", err)
+						return
+					}
+					slugs <- article.Slug
+				}(int64(i + 1))
+			}
+			wg.Wait()
+			close(slugs)
+
+			// Validate that every article got a different slug
+			seen := make(map[string]bool)
+			for slug := range slugs {
+				if seen[slug] {
+					t.Errorf("Slug %q was given to more than one article", slug)
+				}
+				seen[slug] = true
+			}
+			if len(seen) != writers {
+				t.Errorf("Expected %d articles, got %d", writers, len(seen))
+			}
+			if !seen["introduction-to-go"] {
+				t.Errorf("Expected one article to get the plain slug, got %v", seen)
+			}
+		})
+	}
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/Nilesh2000/conduit/internal/repository"
+	"github.com/Nilesh2000/conduit/internal/stream"
+)
+
+// StreamMessage represents an event streamed to a user. Type tells which of Comment,
+// Notification and Article is set, and comments come with the slug of their article.
+type StreamMessage struct {
+	ID           int64
+	Type         string
+	Slug         string
+	Comment      *Comment
+	Notification *Notification
+	Article      *Article
+}
+
+// EventPublisher is an interface for publishing events to streaming clients
+type EventPublisher interface {
+	Publish(ctx context.Context, event stream.Event) error
+}
+
+// EventSubscriber is an interface for subscribing to the events published to streaming
+// clients
+type EventSubscriber interface {
+	Subscribe(lastEventID int64) (*stream.Subscription, []stream.Event)
+}
+
+// ArticleGetter is an interface for getting an article the way a user sees it
+type ArticleGetter interface {
+	GetArticle(ctx context.Context, slug string, currentUserID *int64) (*Article, error)
+}
+
+// streamService implements the StreamService interface
+type streamService struct {
+	events                 EventSubscriber
+	articles               ArticleGetter
+	articleRepository      ArticleRepository
+	commentRepository      CommentRepository
+	userRepository         UserRepository
+	profileRepository      ProfileRepository
+	blockRepository        BlockRepository
+	notificationRepository NotificationRepository
+}
+
+// NewStreamService creates a new stream service that streams the events it subscribes
+// to with events, reading published articles with articles
+func NewStreamService(
+	events EventSubscriber,
+	articles ArticleGetter,
+	articleRepository ArticleRepository,
+	commentRepository CommentRepository,
+	userRepository UserRepository,
+	profileRepository ProfileRepository,
+	blockRepository BlockRepository,
+	notificationRepository NotificationRepository,
+) *streamService {
+	return &streamService{
+		events:                 events,
+		articles:               articles,
+		articleRepository:      articleRepository,
+		commentRepository:      commentRepository,
+		userRepository:         userRepository,
+		profileRepository:      profileRepository,
+		blockRepository:        blockRepository,
+		notificationRepository: notificationRepository,
+	}
+}
+
+// Stream streams the new comments on the articles with slugs, the new notifications of
+// a user and the articles published into their feed, starting after the event numbered
+// lastEventID. The channel is closed once ctx is done or the subscription ends, after
+// which the user has to stream again from the last event they got.
+func (s *streamService) Stream(
+	ctx context.Context,
+	userID int64,
+	slugs []string,
+	lastEventID int64,
+) (<-chan StreamMessage, error) {
+	// Only the comments on articles the user can see are streamed
+	articles := make(map[int64]string, len(slugs))
+	for _, slug := range slugs {
+		article, err := getViewableArticle(ctx, s.articleRepository, slug, &userID)
+		var moved *ArticleMovedError
+		if errors.As(err, &moved) {
+			article, err = getViewableArticle(ctx, s.articleRepository, moved.Slug, &userID)
+		}
+		if err != nil {
+			return nil, err
+		}
+		articles[article.ID] = article.Slug
+	}
+
+	subscription, missed := s.events.Subscribe(lastEventID)
+	messages := make(chan StreamMessage)
+
+	go func() {
+		defer close(messages)
+		defer subscription.Close()
+
+		// send sends the message for an event, reporting false once ctx is done
+		send := func(event stream.Event) bool {
+			message, err := s.message(ctx, userID, articles, event)
+			if err != nil {
+				log.Printf("error reading %s event %d for user %d: %v", event.Type, event.ID, userID, err)
+				return true
+			}
+			if message == nil {
+				return true
+			}
+
+			select {
+			case messages <- *message:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		// Catch up on the events the user missed before streaming new ones
+		for _, event := range missed {
+			if !send(event) {
+				return
+			}
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-subscription.Events():
+				if !ok || !send(event) {
+					return
+				}
+			}
+		}
+	}()
+
+	return messages, nil
+}
+
+// message reads what an event is about the way a user sees it. It returns nil without
+// an error for events that are not meant for the user or about something they cannot
+// see, such as another user's notifications or a comment that was already deleted.
+func (s *streamService) message(
+	ctx context.Context,
+	userID int64,
+	articles map[int64]string,
+	event stream.Event,
+) (*StreamMessage, error) {
+	message := &StreamMessage{ID: event.ID, Type: event.Type}
+
+	switch event.Type {
+	case stream.EventComment:
+		slug, ok := articles[event.ArticleID]
+		if !ok {
+			return nil, nil
+		}
+		comment, err := s.comment(ctx, userID, event.CommentID)
+		if err != nil || comment == nil {
+			return nil, err
+		}
+		message.Slug = slug
+		message.Comment = comment
+	case stream.EventNotification:
+		if event.UserID != userID {
+			return nil, nil
+		}
+		notification, err := s.notificationRepository.Get(ctx, userID, event.NotificationID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotificationNotFound) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		converted := notificationFromRepository(*notification)
+		message.Notification = &converted
+	case stream.EventArticle:
+		inFeed, err := s.articleRepository.IsInFeed(ctx, userID, event.ArticleID)
+		if err != nil || !inFeed {
+			return nil, err
+		}
+		article, err := s.articles.GetArticle(ctx, event.Slug, &userID)
+		if err != nil {
+			// The article may have been renamed or deleted since it was published
+			var moved *ArticleMovedError
+			if errors.Is(err, ErrArticleNotFound) || errors.As(err, &moved) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		message.Article = article
+	default:
+		return nil, nil
+	}
+
+	return message, nil
+}
+
+// comment reads a new comment the way a user sees it. It returns nil without an error
+// if the comment was deleted, or if the user and its author blocked one another.
+func (s *streamService) comment(
+	ctx context.Context,
+	userID int64,
+	commentID int64,
+) (*Comment, error) {
+	comment, err := s.commentRepository.GetByID(ctx, commentID)
+	if err != nil {
+		if errors.Is(err, repository.ErrCommentNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if comment.Tombstoned {
+		return nil, nil
+	}
+
+	authorID := comment.Author.ID
+	following := false
+	if authorID != userID {
+		for _, pair := range [][2]int64{{authorID, userID}, {userID, authorID}} {
+			blocked, err := s.blockRepository.IsBlocking(ctx, pair[0], pair[1])
+			if err != nil || blocked {
+				return nil, err
+			}
+		}
+
+		following, err = s.profileRepository.IsFollowing(ctx, userID, authorID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	author, err := s.userRepository.FindByID(ctx, authorID)
+	if err != nil {
+		return nil, err
+	}
+	comment.Author = repository.Profile{
+		ID:        author.ID,
+		Username:  author.Username,
+		Bio:       author.Bio,
+		Image:     author.Image,
+		Following: following,
+	}
+
+	converted := commentFromRepository(*comment)
+	return &converted, nil
+}
+
+// publish publishes an event about an action that already happened. The action stands
+// even if the event cannot be published, so the error is only logged.
+func publish(ctx context.Context, events EventPublisher, event stream.Event) {
+	if err := events.Publish(ctx, event); err != nil {
+		log.Printf("error publishing %s event: %v", event.Type, err)
+	}
+}
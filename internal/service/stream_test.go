@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Nilesh2000/conduit/internal/repository"
+	"github.com/Nilesh2000/conduit/internal/stream"
+)
+
+// MockEventPublisher is a mock implementation of the EventPublisher interface that
+// records the events it publishes
+type MockEventPublisher struct {
+	mu     sync.Mutex
+	events []stream.Event
+}
+
+// Publish is a mock implementation of the Publish method
+func (m *MockEventPublisher) Publish(ctx context.Context, event stream.Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, event)
+	return nil
+}
+
+// Events returns the events published so far
+func (m *MockEventPublisher) Events() []stream.Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]stream.Event{}, m.events...)
+}
+
+// MockArticleGetter is a mock implementation of the ArticleGetter interface
+type MockArticleGetter struct {
+	getArticleFunc func(ctx context.Context, slug string, currentUserID *int64) (*Article, error)
+}
+
+// GetArticle is a mock implementation of the GetArticle method
+func (m *MockArticleGetter) GetArticle(
+	ctx context.Context,
+	slug string,
+	currentUserID *int64,
+) (*Article, error) {
+	return m.getArticleFunc(ctx, slug, currentUserID)
+}
+
+// streamFixture returns a stream service streaming from hub to user 2, who reads the
+// article test-article. User 3 commented on it with comment 7, user 4, who blocked user
+// 2, with comment 8, and comment 9 was deleted. Notification 5 is user 2's, and only
+// article 1 is in their feed.
+func streamFixture(hub *stream.Hub) *streamService {
+	articleRepo := revisionArticleRepository(repository.ArticleStatusPublished)
+	articleRepo.isInFeedFunc = func(ctx context.Context, userID int64, articleID int64) (bool, error) {
+		return articleID == 1, nil
+	}
+
+	commentRepo := &MockCommentRepository{
+		getByIDFunc: func(ctx context.Context, commentID int64) (*repository.Comment, error) {
+			authors := map[int64]int64{7: 3, 8: 4}
+			authorID, ok := authors[commentID]
+			if !ok {
+				return nil, repository.ErrCommentNotFound
+			}
+			return &repository.Comment{
+				ID:      int(commentID),
+				Body:    "Nice",
+				Article: repository.Article{ID: 1},
+				Author:  repository.Profile{ID: authorID},
+			}, nil
+		},
+	}
+
+	userRepo := &MockUserRepository{
+		findByIDFunc: func(ctx context.Context, id int64) (*repository.User, error) {
+			return &repository.User{ID: id, Username: fmt.Sprintf("user%d", id)}, nil
+		},
+	}
+
+	profileRepo := &MockProfileRepository{
+		isFollowingFunc: func(ctx context.Context, followerID int64, followingID int64) (bool, error) {
+			return followingID == 3, nil
+		},
+	}
+
+	blockRepo := &MockBlockRepository{
+		isBlockingFunc: func(ctx context.Context, blockerID int64, blockedID int64) (bool, error) {
+			return blockerID == 4 && blockedID == 2, nil
+		},
+	}
+
+	notificationRepo := &MockNotificationRepository{
+		getFunc: func(ctx context.Context, userID, notificationID int64) (*repository.Notification, error) {
+			if userID != 2 || notificationID != 5 {
+				return nil, repository.ErrNotificationNotFound
+			}
+			return &repository.Notification{
+				ID:      5,
+				UserID:  2,
+				ActorID: 3,
+				Type:    repository.NotificationTypeFollow,
+				Actor:   &repository.User{ID: 3, Username: "user3"},
+			}, nil
+		},
+	}
+
+	articles := &MockArticleGetter{
+		getArticleFunc: func(ctx context.Context, slug string, currentUserID *int64) (*Article, error) {
+			return &Article{Slug: slug, Title: "Title"}, nil
+		},
+	}
+
+	return NewStreamService(
+		hub,
+		articles,
+		articleRepo,
+		commentRepo,
+		userRepo,
+		profileRepo,
+		blockRepo,
+		notificationRepo,
+	)
+}
+
+// Test_streamService_Stream tests the Stream method of the streamService
+func Test_streamService_Stream(t *testing.T) {
+	t.Parallel()
+
+	// Every stream ends with notification 5, which user 2 always gets
+	sentinel := stream.Event{ID: 100, Type: stream.EventNotification, UserID: 2, NotificationID: 5}
+	sentinelMessage := StreamMessage{
+		ID:   100,
+		Type: stream.EventNotification,
+		Notification: &Notification{
+			ID:    5,
+			Type:  repository.NotificationTypeFollow,
+			Actor: Profile{Username: "user3"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		events   []stream.Event
+		expected []StreamMessage
+	}{
+		{
+			name:   "Comment on a read article",
+			events: []stream.Event{{ID: 2, Type: stream.EventComment, ArticleID: 1, CommentID: 7}},
+			expected: []StreamMessage{
+				{
+					ID:   2,
+					Type: stream.EventComment,
+					Slug: "test-article",
+					Comment: &Comment{
+						ID:     7,
+						Body:   "Nice",
+						Author: Profile{Username: "user3", Following: true},
+					},
+				},
+			},
+		},
+		{
+			name:   "Comment on another article",
+			events: []stream.Event{{ID: 2, Type: stream.EventComment, ArticleID: 5, CommentID: 7}},
+		},
+		{
+			name:   "Comment by a user who blocked the reader",
+			events: []stream.Event{{ID: 2, Type: stream.EventComment, ArticleID: 1, CommentID: 8}},
+		},
+		{
+			name:   "Comment deleted since",
+			events: []stream.Event{{ID: 2, Type: stream.EventComment, ArticleID: 1, CommentID: 9}},
+		},
+		{
+			name:   "Notification of another user",
+			events: []stream.Event{{ID: 2, Type: stream.EventNotification, UserID: 3, NotificationID: 6}},
+		},
+		{
+			name:   "Article published into the feed",
+			events: []stream.Event{{ID: 2, Type: stream.EventArticle, ArticleID: 1, Slug: "test-article"}},
+			expected: []StreamMessage{
+				{ID: 2, Type: stream.EventArticle, Article: &Article{Slug: "test-article", Title: "Title"}},
+			},
+		},
+		{
+			name:   "Article outside the feed",
+			events: []stream.Event{{ID: 2, Type: stream.EventArticle, ArticleID: 2, Slug: "other"}},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			// Deliver the events before the stream starts after event 1, so that they are
+			// caught up on, and the sentinel once it has started
+			hub := stream.NewHub(10)
+			hub.Deliver(stream.Event{ID: 1, Type: stream.EventArticle, ArticleID: 1})
+			for _, event := range tt.events {
+				hub.Deliver(event)
+			}
+
+			// Create Service
+			streamService := streamFixture(hub)
+
+			// Call Stream
+			messages, err := streamService.Stream(ctx, 2, []string{"test-article"}, 1)
+			if err != nil {
+				t.Fatalf("Stream() error = %v", err)
+			}
+			hub.Deliver(sentinel)
+
+			// Read the messages up to the sentinel
+			got := []StreamMessage{}
+			for message := range messages {
+				if message.ID == sentinel.ID {
+					if !reflect.DeepEqual(message, sentinelMessage) {
+						t.Errorf("Expected message %+v, got %+v", sentinelMessage, message)
+					}
+					break
+				}
+				got = append(got, message)
+			}
+
+			// Validate results
+			expected := tt.expected
+			if expected == nil {
+				expected = []StreamMessage{}
+			}
+			if !reflect.DeepEqual(got, expected) {
+				t.Errorf("Expected messages %+v, got %+v", expected, got)
+			}
+		})
+	}
+}
+
+// Test_streamService_Stream_UnknownArticle tests that only the comments on articles
+// that exist can be streamed
+func Test_streamService_Stream_UnknownArticle(t *testing.T) {
+	t.Parallel()
+
+	streamService := streamFixture(stream.NewHub(10))
+
+	_, err := streamService.Stream(context.Background(), 2, []string{"missing"}, 0)
+	if !errors.Is(err, ErrArticleNotFound) {
+		t.Errorf("Expected error %v, got %v", ErrArticleNotFound, err)
+	}
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Nilesh2000/conduit/internal/repository"
+)
+
+// Trash represents the articles and comments of a user that were deleted and can still
+// be restored
+type Trash struct {
+	Articles []TrashedArticle `json:"articles"`
+	Comments []TrashedComment `json:"comments"`
+}
+
+// TrashedArticle represents an article in the trash, which is deleted for good at
+// ExpiresAt
+type TrashedArticle struct {
+	Slug        string    `json:"slug"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Status      string    `json:"status"`
+	DeletedAt   time.Time `json:"deletedAt"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
+
+// TrashedComment represents a comment in the trash, which is deleted for good at
+// ExpiresAt
+type TrashedComment struct {
+	ID          int       `json:"id"`
+	Body        string    `json:"body"`
+	ArticleSlug string    `json:"articleSlug"`
+	ParentID    *int      `json:"parentId"`
+	CreatedAt   time.Time `json:"createdAt"`
+	DeletedAt   time.Time `json:"deletedAt"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
+
+// ArticleTrashRepository is an interface for the deleted articles of the article repository
+type ArticleTrashRepository interface {
+	ListDeleted(
+		ctx context.Context,
+		userID int64,
+		deletedAfter time.Time,
+	) ([]*repository.Article, error)
+	Restore(
+		ctx context.Context,
+		userID int64,
+		slug string,
+		deletedAfter time.Time,
+	) (*repository.Article, error)
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time, limit int) (int, error)
+}
+
+// CommentTrashRepository is an interface for the deleted comments of the comment repository
+type CommentTrashRepository interface {
+	ListDeleted(
+		ctx context.Context,
+		userID int64,
+		deletedAfter time.Time,
+	) ([]repository.Comment, error)
+	Restore(
+		ctx context.Context,
+		userID, articleID, commentID int64,
+		deletedAfter time.Time,
+	) (*repository.Comment, error)
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time, limit int) (int, error)
+}
+
+// trashService implements the TrashService interface
+type trashService struct {
+	articleTrashRepository ArticleTrashRepository
+	commentTrashRepository CommentTrashRepository
+	articleRepository      ArticleRepository
+	retention              time.Duration
+}
+
+// NewTrashService creates a new trash service that keeps deleted articles and comments
+// restorable for retention
+func NewTrashService(
+	articleTrashRepository ArticleTrashRepository,
+	commentTrashRepository CommentTrashRepository,
+	articleRepository ArticleRepository,
+	retention time.Duration,
+) *trashService {
+	return &trashService{
+		articleTrashRepository: articleTrashRepository,
+		commentTrashRepository: commentTrashRepository,
+		articleRepository:      articleRepository,
+		retention:              retention,
+	}
+}
+
+// GetTrash gets the articles and comments of a user that can still be restored, most
+// recently deleted first
+func (s *trashService) GetTrash(ctx context.Context, userID int64) (*Trash, error) {
+	deletedAfter := time.Now().Add(-s.retention)
+
+	articles, err := s.articleTrashRepository.ListDeleted(ctx, userID, deletedAfter)
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+
+	comments, err := s.commentTrashRepository.ListDeleted(ctx, userID, deletedAfter)
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+
+	trash := &Trash{
+		Articles: make([]TrashedArticle, 0, len(articles)),
+		Comments: make([]TrashedComment, 0, len(comments)),
+	}
+	for _, article := range articles {
+		trash.Articles = append(trash.Articles, TrashedArticle{
+			Slug:        article.Slug,
+			Title:       article.Title,
+			Description: article.Description,
+			Status:      article.Status,
+			DeletedAt:   *article.DeletedAt,
+			ExpiresAt:   article.DeletedAt.Add(s.retention),
+		})
+	}
+	for _, comment := range comments {
+		trash.Comments = append(trash.Comments, TrashedComment{
+			ID:          comment.ID,
+			Body:        comment.Body,
+			ArticleSlug: comment.Article.Slug,
+			ParentID:    comment.ParentID,
+			CreatedAt:   comment.CreatedAt,
+			DeletedAt:   *comment.DeletedAt,
+			ExpiresAt:   comment.DeletedAt.Add(s.retention),
+		})
+	}
+
+	return trash, nil
+}
+
+// RestoreArticle takes an article of a user back out of the trash
+func (s *trashService) RestoreArticle(
+	ctx context.Context,
+	userID int64,
+	slug string,
+) (*Article, error) {
+	article, err := s.articleTrashRepository.Restore(ctx, userID, slug, time.Now().Add(-s.retention))
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrArticleNotFound):
+			return nil, ErrArticleNotFound
+		default:
+			return nil, ErrInternalServer
+		}
+	}
+
+	// Get favorites count
+	favoritesCount, err := s.articleRepository.GetFavoritesCount(ctx, article.ID)
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+
+	// Check if user has favorited the article
+	favorited, err := s.articleRepository.IsFavorited(ctx, userID, article.ID)
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+
+	return &Article{
+		Slug:           article.Slug,
+		Title:          article.Title,
+		Description:    article.Description,
+		Body:           article.Body,
+		TagList:        article.TagList,
+		CreatedAt:      article.CreatedAt,
+		UpdatedAt:      article.UpdatedAt,
+		Favorited:      favorited,
+		FavoritesCount: favoritesCount,
+		Author: Profile{
+			Username:  article.Author.Username,
+			Bio:       article.Author.Bio,
+			Image:     article.Author.Image,
+			Following: false,
+		},
+		Status:      article.Status,
+		PublishAt:   article.PublishAt,
+		PublishedAt: article.PublishedAt,
+	}, nil
+}
+
+// RestoreComment takes a comment of a user on the article with the given slug back out
+// of the trash
+func (s *trashService) RestoreComment(
+	ctx context.Context,
+	userID int64,
+	slug string,
+	commentID int64,
+) (*Comment, error) {
+	article, err := getViewableArticle(ctx, s.articleRepository, slug, &userID)
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err := s.commentTrashRepository.Restore(
+		ctx,
+		userID,
+		article.ID,
+		commentID,
+		time.Now().Add(-s.retention),
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrCommentNotFound):
+			return nil, ErrCommentNotFound
+		case errors.Is(err, repository.ErrParentCommentDeleted):
+			return nil, ErrParentCommentDeleted
+		default:
+			return nil, ErrInternalServer
+		}
+	}
+
+	restored := commentFromRepository(*comment)
+	return &restored, nil
+}
+
+// PurgeTrash deletes up to limit articles and comments that were deleted longer than the
+// retention before now for good and returns how many were deleted
+func (s *trashService) PurgeTrash(ctx context.Context, now time.Time, limit int) (int, error) {
+	deletedBefore := now.Add(-s.retention)
+
+	comments, err := s.commentTrashRepository.PurgeDeleted(ctx, deletedBefore, limit)
+	if err != nil {
+		return 0, ErrInternalServer
+	}
+	if comments >= limit {
+		return comments, nil
+	}
+
+	articles, err := s.articleTrashRepository.PurgeDeleted(ctx, deletedBefore, limit-comments)
+	if err != nil {
+		return comments, ErrInternalServer
+	}
+
+	return comments + articles, nil
+}
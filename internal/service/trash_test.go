@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Nilesh2000/conduit/internal/repository"
+)
+
+// MockArticleTrashRepository is a mock implementation of the ArticleTrashRepository interface
+type MockArticleTrashRepository struct {
+	listDeletedFunc  func(ctx context.Context, userID int64, deletedAfter time.Time) ([]*repository.Article, error)
+	restoreFunc      func(ctx context.Context, userID int64, slug string, deletedAfter time.Time) (*repository.Article, error)
+	purgeDeletedFunc func(ctx context.Context, deletedBefore time.Time, limit int) (int, error)
+}
+
+// ListDeleted is a mock implementation of the ListDeleted method
+func (m *MockArticleTrashRepository) ListDeleted(
+	ctx context.Context,
+	userID int64,
+	deletedAfter time.Time,
+) ([]*repository.Article, error) {
+	return m.listDeletedFunc(ctx, userID, deletedAfter)
+}
+
+// Restore is a mock implementation of the Restore method
+func (m *MockArticleTrashRepository) Restore(
+	ctx context.Context,
+	userID int64,
+	slug string,
+	deletedAfter time.Time,
+) (*repository.Article, error) {
+	return m.restoreFunc(ctx, userID, slug, deletedAfter)
+}
+
+// PurgeDeleted is a mock implementation of the PurgeDeleted method
+func (m *MockArticleTrashRepository) PurgeDeleted(
+	ctx context.Context,
+	deletedBefore time.Time,
+	limit int,
+) (int, error) {
+	return m.purgeDeletedFunc(ctx, deletedBefore, limit)
+}
+
+// MockCommentTrashRepository is a mock implementation of the CommentTrashRepository interface
+type MockCommentTrashRepository struct {
+	listDeletedFunc  func(ctx context.Context, userID int64, deletedAfter time.Time) ([]repository.Comment, error)
+	restoreFunc      func(ctx context.Context, userID, articleID, commentID int64, deletedAfter time.Time) (*repository.Comment, error)
+	purgeDeletedFunc func(ctx context.Context, deletedBefore time.Time, limit int) (int, error)
+}
+
+// ListDeleted is a mock implementation of the ListDeleted method
+func (m *MockCommentTrashRepository) ListDeleted(
+	ctx context.Context,
+	userID int64,
+	deletedAfter time.Time,
+) ([]repository.Comment, error) {
+	return m.listDeletedFunc(ctx, userID, deletedAfter)
+}
+
+// Restore is a mock implementation of the Restore method
+func (m *MockCommentTrashRepository) Restore(
+	ctx context.Context,
+	userID, articleID, commentID int64,
+	deletedAfter time.Time,
+) (*repository.Comment, error) {
+	return m.restoreFunc(ctx, userID, articleID, commentID, deletedAfter)
+}
+
+// PurgeDeleted is a mock implementation of the PurgeDeleted method
+func (m *MockCommentTrashRepository) PurgeDeleted(
+	ctx context.Context,
+	deletedBefore time.Time,
+	limit int,
+) (int, error) {
+	return m.purgeDeletedFunc(ctx, deletedBefore, limit)
+}
+
+// Test_trashService_GetTrash tests the GetTrash method of the trashService
+func Test_trashService_GetTrash(t *testing.T) {
+	t.Parallel()
+
+	retention := 24 * time.Hour
+	deletedAt := time.Now().Add(-time.Hour)
+
+	// Setup mock repositories
+	var articlesDeletedAfter, commentsDeletedAfter time.Time
+	articleTrash := &MockArticleTrashRepository{
+		listDeletedFunc: func(ctx context.Context, userID int64, deletedAfter time.Time) ([]*repository.Article, error) {
+			articlesDeletedAfter = deletedAfter
+			return []*repository.Article{
+				{ID: 1, Slug: "test-article", Title: "Title", Status: repository.ArticleStatusDraft, DeletedAt: &deletedAt},
+			}, nil
+		},
+	}
+	commentTrash := &MockCommentTrashRepository{
+		listDeletedFunc: func(ctx context.Context, userID int64, deletedAfter time.Time) ([]repository.Comment, error) {
+			commentsDeletedAfter = deletedAfter
+			comment := repository.Comment{ID: 3, Body: "Comment", DeletedAt: &deletedAt}
+			comment.Article.Slug = "other-article"
+			return []repository.Comment{comment}, nil
+		},
+	}
+
+	// Create service with mock repositories
+	trashService := NewTrashService(articleTrash, commentTrash, &MockArticleRepository{}, retention)
+
+	// Call method
+	before := time.Now()
+	trash, err := trashService.GetTrash(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Validate that only items deleted within the retention are listed
+	if articlesDeletedAfter.Before(before.Add(-retention)) || !articlesDeletedAfter.Equal(commentsDeletedAfter) {
+		t.Errorf("Expected items deleted in the last %v, got articles after %v and comments after %v", retention, articlesDeletedAfter, commentsDeletedAfter)
+	}
+
+	// Validate trash
+	if len(trash.Articles) != 1 || trash.Articles[0].Slug != "test-article" || !trash.Articles[0].ExpiresAt.Equal(deletedAt.Add(retention)) {
+		t.Errorf("Expected the deleted article expiring at %v, got %+v", deletedAt.Add(retention), trash.Articles)
+	}
+	if len(trash.Comments) != 1 || trash.Comments[0].ID != 3 || trash.Comments[0].ArticleSlug != "other-article" {
+		t.Errorf("Expected the deleted comment, got %+v", trash.Comments)
+	}
+}
+
+// Test_trashService_RestoreComment tests the RestoreComment method of the trashService
+func Test_trashService_RestoreComment(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		slug        string
+		restoreErr  error
+		expectedErr error
+	}{
+		{
+			name:        "Comment restored",
+			slug:        "test-article",
+			expectedErr: nil,
+		},
+		{
+			name:        "Article not found",
+			slug:        "non-existent-article",
+			expectedErr: ErrArticleNotFound,
+		},
+		{
+			name:        "Comment not in the trash",
+			slug:        "test-article",
+			restoreErr:  repository.ErrCommentNotFound,
+			expectedErr: ErrCommentNotFound,
+		},
+		{
+			name:        "Parent comment still in the trash",
+			slug:        "test-article",
+			restoreErr:  repository.ErrParentCommentDeleted,
+			expectedErr: ErrParentCommentDeleted,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock repositories
+			commentTrash := &MockCommentTrashRepository{
+				restoreFunc: func(ctx context.Context, userID, articleID, commentID int64, deletedAfter time.Time) (*repository.Comment, error) {
+					if articleID != 1 || commentID != 3 {
+						t.Errorf("Expected comment 3 of article 1, got comment %d of article %d", commentID, articleID)
+					}
+					if tt.restoreErr != nil {
+						return nil, tt.restoreErr
+					}
+					comment := &repository.Comment{ID: int(commentID), Body: "Comment"}
+					comment.Author.Username = "author"
+					return comment, nil
+				},
+			}
+
+			// Create service with mock repositories
+			trashService := NewTrashService(
+				&MockArticleTrashRepository{},
+				commentTrash,
+				revisionArticleRepository(repository.ArticleStatusPublished),
+				24*time.Hour,
+			)
+
+			// Call method
+			comment, err := trashService.RestoreComment(context.Background(), 1, tt.slug, 3)
+
+			// Validate error
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			// Validate comment if no error
+			if err == nil && (comment.ID != 3 || comment.Body != "Comment") {
+				t.Errorf("Expected comment 3, got %+v", comment)
+			}
+		})
+	}
+}
+
+// Test_trashService_PurgeTrash tests the PurgeTrash method of the trashService
+func Test_trashService_PurgeTrash(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	retention := 24 * time.Hour
+
+	tests := []struct {
+		name           string
+		commentsPurged int
+		articlesPurged int
+		expectedLimit  int
+		expectedPurged int
+		expectArticles bool
+	}{
+		{
+			name:           "Comments and articles purged",
+			commentsPurged: 3,
+			articlesPurged: 2,
+			expectedLimit:  7,
+			expectedPurged: 5,
+			expectArticles: true,
+		},
+		{
+			name:           "Full batch of comments",
+			commentsPurged: 10,
+			expectedPurged: 10,
+			expectArticles: false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock repositories
+			articlesPurged := false
+			articleTrash := &MockArticleTrashRepository{
+				purgeDeletedFunc: func(ctx context.Context, deletedBefore time.Time, limit int) (int, error) {
+					articlesPurged = true
+					if limit != tt.expectedLimit {
+						t.Errorf("Expected limit %d, got %d", tt.expectedLimit, limit)
+					}
+					return tt.articlesPurged, nil
+				},
+			}
+			commentTrash := &MockCommentTrashRepository{
+				purgeDeletedFunc: func(ctx context.Context, deletedBefore time.Time, limit int) (int, error) {
+					if !deletedBefore.Equal(now.Add(-retention)) {
+						t.Errorf("Expected items deleted before %v, got %v", now.Add(-retention), deletedBefore)
+					}
+					return tt.commentsPurged, nil
+				},
+			}
+
+			// Create service with mock repositories
+			trashService := NewTrashService(articleTrash, commentTrash, &MockArticleRepository{}, retention)
+
+			// Call method
+			purged, err := trashService.PurgeTrash(context.Background(), now, 10)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			// Validate result
+			if purged != tt.expectedPurged {
+				t.Errorf("Expected %d purged, got %d", tt.expectedPurged, purged)
+			}
+			if articlesPurged != tt.expectArticles {
+				t.Errorf("Expected articles to be purged: %v, got %v", tt.expectArticles, articlesPurged)
+			}
+		})
+	}
+}
@@ -2,6 +2,10 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"time"
@@ -15,11 +19,12 @@ import (
 
 // User represents a user in the system
 type User struct {
-	Email    string `json:"email"`
-	Token    string `json:"token"`
-	Username string `json:"username"`
-	Bio      string `json:"bio"`
-	Image    string `json:"image"`
+	Email        string `json:"email"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken,omitempty"`
+	Username     string `json:"username"`
+	Bio          string `json:"bio"`
+	Image        string `json:"image"`
 }
 
 // UserRepository defines the interface for user repository operations
@@ -35,23 +40,56 @@ type UserRepository interface {
 	) (*repository.User, error)
 }
 
+// TokenRepository defines the interface for token repository operations
+type TokenRepository interface {
+	CreateRefreshToken(ctx context.Context, token repository.RefreshToken) error
+	FindRefreshTokenByHash(ctx context.Context, tokenHash string) (*repository.RefreshToken, error)
+	FindRefreshTokenByAccessTokenID(
+		ctx context.Context,
+		accessTokenID string,
+	) (*repository.RefreshToken, error)
+	RotateRefreshToken(ctx context.Context, tokenID int64, next repository.RefreshToken) error
+	RevokeRefreshTokenFamily(ctx context.Context, familyID string) error
+	RevokeAccessToken(ctx context.Context, tokenID string, expiresAt time.Time) error
+	PurgeRevokedAccessTokens(ctx context.Context, expiredBefore time.Time, limit int) (int, error)
+	PurgeRefreshTokens(ctx context.Context, expiredBefore time.Time, limit int) (int, error)
+}
+
+// TokenSigner defines the interface for signing access tokens
+type TokenSigner interface {
+	Sign(claims jwt.Claims) (string, error)
+}
+
 // userService implements the UserService interface
 type userService struct {
-	userRepository UserRepository
-	jwtSecret      []byte
-	jwtExpiration  time.Duration
+	userRepository    UserRepository
+	tokenRepository   TokenRepository
+	tokenSigner       TokenSigner
+	jwtExpiration     time.Duration
+	refreshExpiration time.Duration
+}
+
+// tokenPair represents an access token and the refresh token issued alongside it
+type tokenPair struct {
+	accessToken  string
+	refreshToken string
+	record       repository.RefreshToken
 }
 
 // NewUserService creates a new user service
 func NewUserService(
 	userRepository UserRepository,
-	jwtSecret string,
+	tokenRepository TokenRepository,
+	tokenSigner TokenSigner,
 	jwtExpiration time.Duration,
+	refreshExpiration time.Duration,
 ) *userService {
 	return &userService{
-		userRepository: userRepository,
-		jwtSecret:      []byte(jwtSecret),
-		jwtExpiration:  jwtExpiration,
+		userRepository:    userRepository,
+		tokenRepository:   tokenRepository,
+		tokenSigner:       tokenSigner,
+		jwtExpiration:     jwtExpiration,
+		refreshExpiration: refreshExpiration,
 	}
 }
 
@@ -79,19 +117,20 @@ func (s *userService) Register(
 		}
 	}
 
-	// Generate a JWT token for the user
-	token, err := s.generateToken(user.ID)
+	// Issue a new token pair for the user
+	tokens, err := s.issueTokens(ctx, user.ID)
 	if err != nil {
 		return nil, ErrInternalServer
 	}
 
 	// Return user data
 	return &User{
-		Email:    user.Email,
-		Token:    token,
-		Username: user.Username,
-		Bio:      user.Bio,
-		Image:    user.Image,
+		Email:        user.Email,
+		Token:        tokens.accessToken,
+		RefreshToken: tokens.refreshToken,
+		Username:     user.Username,
+		Bio:          user.Bio,
+		Image:        user.Image,
 	}, nil
 }
 
@@ -113,19 +152,20 @@ func (s *userService) Login(ctx context.Context, email, password string) (*User,
 		return nil, ErrInvalidCredentials
 	}
 
-	// Generate JWT token
-	token, err := s.generateToken(user.ID)
+	// Issue a new token pair for the user
+	tokens, err := s.issueTokens(ctx, user.ID)
 	if err != nil {
 		return nil, ErrInternalServer
 	}
 
 	// Return user data
 	return &User{
-		Email:    user.Email,
-		Token:    token,
-		Username: user.Username,
-		Bio:      user.Bio,
-		Image:    user.Image,
+		Email:        user.Email,
+		Token:        tokens.accessToken,
+		RefreshToken: tokens.refreshToken,
+		Username:     user.Username,
+		Bio:          user.Bio,
+		Image:        user.Image,
 	}, nil
 }
 
@@ -187,20 +227,211 @@ func (s *userService) UpdateUser(
 	}, nil
 }
 
-// generateToken generates a JWT token for a user
-func (s *userService) generateToken(userID int64) (string, error) {
+// Refresh exchanges a refresh token for a new token pair.
+// Presenting a refresh token that was already rotated revokes its whole token family.
+func (s *userService) Refresh(ctx context.Context, refreshToken string) (*User, error) {
+	// Find the refresh token
+	current, err := s.tokenRepository.FindRefreshTokenByHash(ctx, hashToken(refreshToken))
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRefreshTokenNotFound):
+			return nil, ErrInvalidRefreshToken
+		default:
+			return nil, ErrInternalServer
+		}
+	}
+
+	// A revoked token being presented again means it was stolen or replayed
+	if current.RevokedAt != nil {
+		if err := s.tokenRepository.RevokeRefreshTokenFamily(ctx, current.FamilyID); err != nil {
+			return nil, ErrInternalServer
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+
+	// Check if the refresh token has expired
+	if !current.ExpiresAt.After(time.Now()) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	// Find the user the token belongs to
+	user, err := s.userRepository.FindByID(ctx, current.UserID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrUserNotFound):
+			return nil, ErrInvalidRefreshToken
+		default:
+			return nil, ErrInternalServer
+		}
+	}
+
+	// Generate the replacement token pair in the same family
+	tokens, err := s.generateTokens(user.ID, current.FamilyID)
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+
+	// Rotate the refresh token
+	if err := s.tokenRepository.RotateRefreshToken(ctx, current.ID, tokens.record); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRefreshTokenReused):
+			// Lost a race against another request using the same token
+			if err := s.tokenRepository.RevokeRefreshTokenFamily(ctx, current.FamilyID); err != nil {
+				return nil, ErrInternalServer
+			}
+			return nil, ErrInvalidRefreshToken
+		default:
+			return nil, ErrInternalServer
+		}
+	}
+
+	return &User{
+		Email:        user.Email,
+		Token:        tokens.accessToken,
+		RefreshToken: tokens.refreshToken,
+		Username:     user.Username,
+		Bio:          user.Bio,
+		Image:        user.Image,
+	}, nil
+}
+
+// Logout revokes the access token with the given ID, which expires at
+// accessTokenExpiresAt, and the refresh token family of the session. The session is
+// identified by the refresh token if one is given, otherwise by the access token.
+func (s *userService) Logout(
+	ctx context.Context,
+	userID int64,
+	accessTokenID string,
+	accessTokenExpiresAt time.Time,
+	refreshToken string,
+) error {
+	// Revoke the access token until it expires, after which it is rejected anyway
+	err := s.tokenRepository.RevokeAccessToken(ctx, accessTokenID, accessTokenExpiresAt)
+	if err != nil {
+		return ErrInternalServer
+	}
+
+	// Find the refresh token of the session
+	var session *repository.RefreshToken
+	if refreshToken != "" {
+		session, err = s.tokenRepository.FindRefreshTokenByHash(ctx, hashToken(refreshToken))
+	} else {
+		session, err = s.tokenRepository.FindRefreshTokenByAccessTokenID(ctx, accessTokenID)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRefreshTokenNotFound):
+			return nil
+		default:
+			return ErrInternalServer
+		}
+	}
+
+	// Never revoke sessions belonging to someone else
+	if session.UserID != userID {
+		return nil
+	}
+
+	// Revoke the refresh token family
+	if err := s.tokenRepository.RevokeRefreshTokenFamily(ctx, session.FamilyID); err != nil {
+		return ErrInternalServer
+	}
+
+	return nil
+}
+
+// PurgeExpiredTokens deletes up to limit revoked access tokens and refresh tokens that
+// expired before now and returns how many were deleted
+func (s *userService) PurgeExpiredTokens(ctx context.Context, now time.Time, limit int) (int, error) {
+	accessTokens, err := s.tokenRepository.PurgeRevokedAccessTokens(ctx, now, limit)
+	if err != nil {
+		return 0, ErrInternalServer
+	}
+	if accessTokens >= limit {
+		return accessTokens, nil
+	}
+
+	refreshTokens, err := s.tokenRepository.PurgeRefreshTokens(ctx, now, limit-accessTokens)
+	if err != nil {
+		return accessTokens, ErrInternalServer
+	}
+
+	return accessTokens + refreshTokens, nil
+}
+
+// issueTokens generates a token pair in a new family and stores the refresh token
+func (s *userService) issueTokens(ctx context.Context, userID int64) (*tokenPair, error) {
+	tokens, err := s.generateTokens(userID, uuid.New().String())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.tokenRepository.CreateRefreshToken(ctx, tokens.record); err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// generateTokens generates an access token and a refresh token in the given family
+func (s *userService) generateTokens(userID int64, familyID string) (*tokenPair, error) {
 	now := time.Now()
-	expirationTime := now.Add(s.jwtExpiration)
+	accessTokenID := uuid.New().String()
+	accessTokenExpiresAt := now.Add(s.jwtExpiration)
+
+	accessToken, err := s.generateToken(userID, accessTokenID, now, accessTokenExpiresAt)
+	if err != nil {
+		return nil, err
+	}
 
+	refreshToken, err := generateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return &tokenPair{
+		accessToken:  accessToken,
+		refreshToken: refreshToken,
+		record: repository.RefreshToken{
+			UserID:               userID,
+			TokenHash:            hashToken(refreshToken),
+			FamilyID:             familyID,
+			AccessTokenID:        accessTokenID,
+			AccessTokenExpiresAt: accessTokenExpiresAt,
+			ExpiresAt:            now.Add(s.refreshExpiration),
+		},
+	}, nil
+}
+
+// generateToken generates a JWT token for a user
+func (s *userService) generateToken(
+	userID int64,
+	tokenID string,
+	issuedAt, expiresAt time.Time,
+) (string, error) {
 	claims := jwt.RegisteredClaims{
-		ExpiresAt: jwt.NewNumericDate(expirationTime),
-		ID:        uuid.New().String(),
-		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+		ID:        tokenID,
+		IssuedAt:  jwt.NewNumericDate(issuedAt),
 		Issuer:    "conduit-api",
-		NotBefore: jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(issuedAt),
 		Subject:   fmt.Sprintf("%d", userID),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(s.jwtSecret)
+	return s.tokenSigner.Sign(claims)
+}
+
+// generateRefreshToken generates a random opaque refresh token
+func generateRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken hashes a refresh token so that only its digest is stored
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Nilesh2000/conduit/internal/jwtkeys"
 	"github.com/Nilesh2000/conduit/internal/repository"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -62,13 +63,102 @@ func (m *MockUserRepository) Update(
 	return m.updateFunc(ctx, userID, username, email, password, bio, image)
 }
 
+// MockTokenRepository is a mock implementation of the TokenRepository interface
+type MockTokenRepository struct {
+	createRefreshTokenFunc              func(ctx context.Context, token repository.RefreshToken) error
+	findRefreshTokenByHashFunc          func(ctx context.Context, tokenHash string) (*repository.RefreshToken, error)
+	findRefreshTokenByAccessTokenIDFunc func(ctx context.Context, accessTokenID string) (*repository.RefreshToken, error)
+	rotateRefreshTokenFunc              func(ctx context.Context, tokenID int64, next repository.RefreshToken) error
+	revokeRefreshTokenFamilyFunc        func(ctx context.Context, familyID string) error
+	revokeAccessTokenFunc               func(ctx context.Context, tokenID string, expiresAt time.Time) error
+	purgeRevokedAccessTokensFunc        func(ctx context.Context, expiredBefore time.Time, limit int) (int, error)
+	purgeRefreshTokensFunc              func(ctx context.Context, expiredBefore time.Time, limit int) (int, error)
+}
+
+var _ TokenRepository = (*MockTokenRepository)(nil)
+
+// newMockTokenRepository returns a mock token repository that stores refresh tokens successfully
+func newMockTokenRepository() *MockTokenRepository {
+	return &MockTokenRepository{
+		createRefreshTokenFunc: func(ctx context.Context, token repository.RefreshToken) error {
+			return nil
+		},
+	}
+}
+
+// CreateRefreshToken stores a refresh token in the repository
+func (m *MockTokenRepository) CreateRefreshToken(
+	ctx context.Context,
+	token repository.RefreshToken,
+) error {
+	return m.createRefreshTokenFunc(ctx, token)
+}
+
+// FindRefreshTokenByHash finds a refresh token by hash in the repository
+func (m *MockTokenRepository) FindRefreshTokenByHash(
+	ctx context.Context,
+	tokenHash string,
+) (*repository.RefreshToken, error) {
+	return m.findRefreshTokenByHashFunc(ctx, tokenHash)
+}
+
+// FindRefreshTokenByAccessTokenID finds a refresh token by access token ID in the repository
+func (m *MockTokenRepository) FindRefreshTokenByAccessTokenID(
+	ctx context.Context,
+	accessTokenID string,
+) (*repository.RefreshToken, error) {
+	return m.findRefreshTokenByAccessTokenIDFunc(ctx, accessTokenID)
+}
+
+// RotateRefreshToken rotates a refresh token in the repository
+func (m *MockTokenRepository) RotateRefreshToken(
+	ctx context.Context,
+	tokenID int64,
+	next repository.RefreshToken,
+) error {
+	return m.rotateRefreshTokenFunc(ctx, tokenID, next)
+}
+
+// RevokeRefreshTokenFamily revokes a refresh token family in the repository
+func (m *MockTokenRepository) RevokeRefreshTokenFamily(ctx context.Context, familyID string) error {
+	return m.revokeRefreshTokenFamilyFunc(ctx, familyID)
+}
+
+// RevokeAccessToken revokes an access token in the repository
+func (m *MockTokenRepository) RevokeAccessToken(
+	ctx context.Context,
+	tokenID string,
+	expiresAt time.Time,
+) error {
+	return m.revokeAccessTokenFunc(ctx, tokenID, expiresAt)
+}
+
+// PurgeRevokedAccessTokens purges expired revoked access tokens in the repository
+func (m *MockTokenRepository) PurgeRevokedAccessTokens(
+	ctx context.Context,
+	expiredBefore time.Time,
+	limit int,
+) (int, error) {
+	return m.purgeRevokedAccessTokensFunc(ctx, expiredBefore, limit)
+}
+
+// PurgeRefreshTokens purges expired refresh tokens in the repository
+func (m *MockTokenRepository) PurgeRefreshTokens(
+	ctx context.Context,
+	expiredBefore time.Time,
+	limit int,
+) (int, error) {
+	return m.purgeRefreshTokensFunc(ctx, expiredBefore, limit)
+}
+
 // Test_userService_Register tests the Register method of the userService
 func Test_userService_Register(t *testing.T) {
 	t.Parallel()
 
 	const (
-		jwtSecret     = "test-secret"
-		jwtExpiration = time.Hour * 24
+		jwtSecret         = "test-secret"
+		jwtExpiration     = time.Hour * 24
+		refreshExpiration = time.Hour * 720
 	)
 
 	tests := []struct {
@@ -241,7 +331,13 @@ func Test_userService_Register(t *testing.T) {
 			mockUserRepository := tt.setupMock()
 
 			// Create service with mock repository
-			userService := NewUserService(mockUserRepository, jwtSecret, jwtExpiration)
+			userService := NewUserService(
+				mockUserRepository,
+				newMockTokenRepository(),
+				jwtkeys.NewHMACKeySet([]byte(jwtSecret)),
+				jwtExpiration,
+				refreshExpiration,
+			)
 
 			// Create context
 			ctx := context.Background()
@@ -267,8 +363,9 @@ func Test_userService_Login(t *testing.T) {
 	t.Parallel()
 
 	const (
-		jwtSecret     = "test-secret"
-		jwtExpiration = time.Hour * 24
+		jwtSecret         = "test-secret"
+		jwtExpiration     = time.Hour * 24
+		refreshExpiration = time.Hour * 720
 	)
 
 	tests := []struct {
@@ -433,7 +530,13 @@ func Test_userService_Login(t *testing.T) {
 			mockUserRepository := tt.setupMock()
 
 			// Create service with mock repository
-			userService := NewUserService(mockUserRepository, jwtSecret, jwtExpiration)
+			userService := NewUserService(
+				mockUserRepository,
+				newMockTokenRepository(),
+				jwtkeys.NewHMACKeySet([]byte(jwtSecret)),
+				jwtExpiration,
+				refreshExpiration,
+			)
 
 			// Create context
 			ctx := context.Background()
@@ -459,8 +562,9 @@ func Test_userService_GetCurrentUser(t *testing.T) {
 	t.Parallel()
 
 	const (
-		jwtSecret     = "test-secret"
-		jwtExpiration = time.Hour * 24
+		jwtSecret         = "test-secret"
+		jwtExpiration     = time.Hour * 24
+		refreshExpiration = time.Hour * 720
 	)
 
 	tests := []struct {
@@ -547,7 +651,13 @@ func Test_userService_GetCurrentUser(t *testing.T) {
 			mockUserRepository := tt.setupMock()
 
 			// Create service with mock repository
-			userService := NewUserService(mockUserRepository, jwtSecret, jwtExpiration)
+			userService := NewUserService(
+				mockUserRepository,
+				newMockTokenRepository(),
+				jwtkeys.NewHMACKeySet([]byte(jwtSecret)),
+				jwtExpiration,
+				refreshExpiration,
+			)
 
 			// Create context
 			ctx := context.Background()
@@ -573,8 +683,9 @@ func Test_userService_UpdateUser(t *testing.T) {
 	t.Parallel()
 
 	const (
-		jwtSecret     = "test-secret"
-		jwtExpiration = time.Hour * 24
+		jwtSecret         = "test-secret"
+		jwtExpiration     = time.Hour * 24
+		refreshExpiration = time.Hour * 720
 	)
 
 	// Helper functions to create pointers to strings
@@ -830,7 +941,13 @@ func Test_userService_UpdateUser(t *testing.T) {
 			mockUserRepository := tt.setupMock()
 
 			// Create service with mock repository
-			userService := NewUserService(mockUserRepository, jwtSecret, jwtExpiration)
+			userService := NewUserService(
+				mockUserRepository,
+				newMockTokenRepository(),
+				jwtkeys.NewHMACKeySet([]byte(jwtSecret)),
+				jwtExpiration,
+				refreshExpiration,
+			)
 
 			// Create context
 			ctx := context.Background()
@@ -858,3 +975,422 @@ func Test_userService_UpdateUser(t *testing.T) {
 		})
 	}
 }
+
+// Test_userService_Refresh tests the Refresh method of the userService
+func Test_userService_Refresh(t *testing.T) {
+	t.Parallel()
+
+	const (
+		jwtSecret         = "test-secret"
+		jwtExpiration     = time.Minute * 15
+		refreshExpiration = time.Hour * 720
+	)
+
+	activeToken := func() *repository.RefreshToken {
+		return &repository.RefreshToken{
+			ID:        1,
+			UserID:    1,
+			TokenHash: hashToken("refresh-token"),
+			FamilyID:  "family-id",
+			ExpiresAt: time.Now().Add(time.Hour),
+		}
+	}
+
+	findUser := func(ctx context.Context, id int64) (*repository.User, error) {
+		return &repository.User{ID: id, Username: "testuser", Email: "test@example.com"}, nil
+	}
+
+	tests := []struct {
+		name           string
+		setupMock      func(revoked *[]string) (*MockUserRepository, *MockTokenRepository)
+		expectedError  error
+		expectedRevoke []string
+		validateFunc   func(*testing.T, *User)
+	}{
+		{
+			name: "Successful rotation",
+			setupMock: func(revoked *[]string) (*MockUserRepository, *MockTokenRepository) {
+				return &MockUserRepository{findByIDFunc: findUser}, &MockTokenRepository{
+					findRefreshTokenByHashFunc: func(ctx context.Context, tokenHash string) (*repository.RefreshToken, error) {
+						if tokenHash != hashToken("refresh-token") {
+							t.Errorf("Expected hash of the presented token, got %q", tokenHash)
+						}
+						return activeToken(), nil
+					},
+					rotateRefreshTokenFunc: func(ctx context.Context, tokenID int64, next repository.RefreshToken) error {
+						if tokenID != 1 {
+							t.Errorf("Expected token ID 1, got %d", tokenID)
+						}
+						if next.FamilyID != "family-id" {
+							t.Errorf("Expected family %q, got %q", "family-id", next.FamilyID)
+						}
+						if next.UserID != 1 {
+							t.Errorf("Expected user ID 1, got %d", next.UserID)
+						}
+						if next.TokenHash == hashToken("refresh-token") {
+							t.Error("Expected a new refresh token to be issued")
+						}
+						return nil
+					},
+				}
+			},
+			expectedError: nil,
+			validateFunc: func(t *testing.T, user *User) {
+				if user.Token == "" {
+					t.Error("Expected access token to be set")
+				}
+				if user.RefreshToken == "" || user.RefreshToken == "refresh-token" {
+					t.Errorf("Expected a new refresh token, got %q", user.RefreshToken)
+				}
+				if user.Username != "testuser" {
+					t.Errorf("Expected username %q, got %q", "testuser", user.Username)
+				}
+			},
+		},
+		{
+			name: "Unknown refresh token",
+			setupMock: func(revoked *[]string) (*MockUserRepository, *MockTokenRepository) {
+				return &MockUserRepository{}, &MockTokenRepository{
+					findRefreshTokenByHashFunc: func(ctx context.Context, tokenHash string) (*repository.RefreshToken, error) {
+						return nil, repository.ErrRefreshTokenNotFound
+					},
+				}
+			},
+			expectedError: ErrInvalidRefreshToken,
+		},
+		{
+			name: "Reused refresh token revokes the family",
+			setupMock: func(revoked *[]string) (*MockUserRepository, *MockTokenRepository) {
+				return &MockUserRepository{}, &MockTokenRepository{
+					findRefreshTokenByHashFunc: func(ctx context.Context, tokenHash string) (*repository.RefreshToken, error) {
+						token := activeToken()
+						revokedAt := time.Now().Add(-time.Minute)
+						token.RevokedAt = &revokedAt
+						return token, nil
+					},
+					revokeRefreshTokenFamilyFunc: func(ctx context.Context, familyID string) error {
+						*revoked = append(*revoked, familyID)
+						return nil
+					},
+				}
+			},
+			expectedError:  ErrInvalidRefreshToken,
+			expectedRevoke: []string{"family-id"},
+		},
+		{
+			name: "Expired refresh token",
+			setupMock: func(revoked *[]string) (*MockUserRepository, *MockTokenRepository) {
+				return &MockUserRepository{}, &MockTokenRepository{
+					findRefreshTokenByHashFunc: func(ctx context.Context, tokenHash string) (*repository.RefreshToken, error) {
+						token := activeToken()
+						token.ExpiresAt = time.Now().Add(-time.Minute)
+						return token, nil
+					},
+				}
+			},
+			expectedError: ErrInvalidRefreshToken,
+		},
+		{
+			name: "Concurrent rotation revokes the family",
+			setupMock: func(revoked *[]string) (*MockUserRepository, *MockTokenRepository) {
+				return &MockUserRepository{findByIDFunc: findUser}, &MockTokenRepository{
+					findRefreshTokenByHashFunc: func(ctx context.Context, tokenHash string) (*repository.RefreshToken, error) {
+						return activeToken(), nil
+					},
+					rotateRefreshTokenFunc: func(ctx context.Context, tokenID int64, next repository.RefreshToken) error {
+						return repository.ErrRefreshTokenReused
+					},
+					revokeRefreshTokenFamilyFunc: func(ctx context.Context, familyID string) error {
+						*revoked = append(*revoked, familyID)
+						return nil
+					},
+				}
+			},
+			expectedError:  ErrInvalidRefreshToken,
+			expectedRevoke: []string{"family-id"},
+		},
+		{
+			name: "Repository error",
+			setupMock: func(revoked *[]string) (*MockUserRepository, *MockTokenRepository) {
+				return &MockUserRepository{}, &MockTokenRepository{
+					findRefreshTokenByHashFunc: func(ctx context.Context, tokenHash string) (*repository.RefreshToken, error) {
+						return nil, repository.ErrInternal
+					},
+				}
+			},
+			expectedError: ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock repositories
+			var revoked []string
+			mockUserRepository, mockTokenRepository := tt.setupMock(&revoked)
+
+			// Create service with mock repositories
+			userService := NewUserService(
+				mockUserRepository,
+				mockTokenRepository,
+				jwtkeys.NewHMACKeySet([]byte(jwtSecret)),
+				jwtExpiration,
+				refreshExpiration,
+			)
+
+			// Call Refresh
+			user, err := userService.Refresh(context.Background(), "refresh-token")
+
+			// Validate error
+			if !errors.Is(err, tt.expectedError) {
+				t.Errorf("Expected error %v, got %v", tt.expectedError, err)
+			}
+
+			// Validate revoked families
+			if len(revoked) != len(tt.expectedRevoke) {
+				t.Errorf("Expected revoked families %v, got %v", tt.expectedRevoke, revoked)
+			}
+
+			// Validate user if expected
+			if err == nil && tt.validateFunc != nil {
+				tt.validateFunc(t, user)
+			}
+		})
+	}
+}
+
+// Test_userService_Logout tests the Logout method of the userService
+func Test_userService_Logout(t *testing.T) {
+	t.Parallel()
+
+	const (
+		jwtSecret         = "test-secret"
+		jwtExpiration     = time.Minute * 15
+		refreshExpiration = time.Hour * 720
+	)
+
+	session := func(userID int64) *repository.RefreshToken {
+		return &repository.RefreshToken{ID: 1, UserID: userID, FamilyID: "family-id"}
+	}
+	tokenExpiresAt := time.Date(2024, 1, 1, 12, 15, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		refreshToken   string
+		setupMock      func(revoked *[]string) *MockTokenRepository
+		expectedError  error
+		expectedRevoke []string
+	}{
+		{
+			name:         "Logout with refresh token",
+			refreshToken: "refresh-token",
+			setupMock: func(revoked *[]string) *MockTokenRepository {
+				return &MockTokenRepository{
+					revokeAccessTokenFunc: func(ctx context.Context, tokenID string, expiresAt time.Time) error {
+						if tokenID != "token-id" {
+							t.Errorf("Expected token ID %q, got %q", "token-id", tokenID)
+						}
+						if !expiresAt.Equal(tokenExpiresAt) {
+							t.Errorf("Expected the revocation to expire with the token at %v, got %v", tokenExpiresAt, expiresAt)
+						}
+						return nil
+					},
+					findRefreshTokenByHashFunc: func(ctx context.Context, tokenHash string) (*repository.RefreshToken, error) {
+						return session(1), nil
+					},
+					revokeRefreshTokenFamilyFunc: func(ctx context.Context, familyID string) error {
+						*revoked = append(*revoked, familyID)
+						return nil
+					},
+				}
+			},
+			expectedError:  nil,
+			expectedRevoke: []string{"family-id"},
+		},
+		{
+			name:         "Logout finds the session by access token",
+			refreshToken: "",
+			setupMock: func(revoked *[]string) *MockTokenRepository {
+				return &MockTokenRepository{
+					revokeAccessTokenFunc: func(ctx context.Context, tokenID string, expiresAt time.Time) error {
+						return nil
+					},
+					findRefreshTokenByAccessTokenIDFunc: func(ctx context.Context, accessTokenID string) (*repository.RefreshToken, error) {
+						if accessTokenID != "token-id" {
+							t.Errorf("Expected access token ID %q, got %q", "token-id", accessTokenID)
+						}
+						return session(1), nil
+					},
+					revokeRefreshTokenFamilyFunc: func(ctx context.Context, familyID string) error {
+						*revoked = append(*revoked, familyID)
+						return nil
+					},
+				}
+			},
+			expectedError:  nil,
+			expectedRevoke: []string{"family-id"},
+		},
+		{
+			name:         "Refresh token of another user is left alone",
+			refreshToken: "someone-elses-token",
+			setupMock: func(revoked *[]string) *MockTokenRepository {
+				return &MockTokenRepository{
+					revokeAccessTokenFunc: func(ctx context.Context, tokenID string, expiresAt time.Time) error {
+						return nil
+					},
+					findRefreshTokenByHashFunc: func(ctx context.Context, tokenHash string) (*repository.RefreshToken, error) {
+						return session(2), nil
+					},
+				}
+			},
+			expectedError: nil,
+		},
+		{
+			name:         "Unknown refresh token",
+			refreshToken: "unknown-token",
+			setupMock: func(revoked *[]string) *MockTokenRepository {
+				return &MockTokenRepository{
+					revokeAccessTokenFunc: func(ctx context.Context, tokenID string, expiresAt time.Time) error {
+						return nil
+					},
+					findRefreshTokenByHashFunc: func(ctx context.Context, tokenHash string) (*repository.RefreshToken, error) {
+						return nil, repository.ErrRefreshTokenNotFound
+					},
+				}
+			},
+			expectedError: nil,
+		},
+		{
+			name:         "Repository error",
+			refreshToken: "",
+			setupMock: func(revoked *[]string) *MockTokenRepository {
+				return &MockTokenRepository{
+					revokeAccessTokenFunc: func(ctx context.Context, tokenID string, expiresAt time.Time) error {
+						return repository.ErrInternal
+					},
+				}
+			},
+			expectedError: ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock repository
+			var revoked []string
+			mockTokenRepository := tt.setupMock(&revoked)
+
+			// Create service with mock repositories
+			userService := NewUserService(
+				&MockUserRepository{},
+				mockTokenRepository,
+				jwtkeys.NewHMACKeySet([]byte(jwtSecret)),
+				jwtExpiration,
+				refreshExpiration,
+			)
+
+			// Call Logout
+			err := userService.Logout(context.Background(), 1, "token-id", tokenExpiresAt, tt.refreshToken)
+
+			// Validate error
+			if !errors.Is(err, tt.expectedError) {
+				t.Errorf("Expected error %v, got %v", tt.expectedError, err)
+			}
+
+			// Validate revoked families
+			if len(revoked) != len(tt.expectedRevoke) {
+				t.Errorf("Expected revoked families %v, got %v", tt.expectedRevoke, revoked)
+			}
+		})
+	}
+}
+
+// Test_userService_PurgeExpiredTokens tests the PurgeExpiredTokens method of the
+// userService
+func Test_userService_PurgeExpiredTokens(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		accessTokens   int
+		refreshErr     error
+		expectedPurged int
+		expectedLimit  int
+		expectedErr    error
+	}{
+		{
+			name:           "Both tables purged",
+			accessTokens:   3,
+			expectedPurged: 5,
+			expectedLimit:  7,
+		},
+		{
+			name:           "Batch filled by access tokens",
+			accessTokens:   10,
+			expectedPurged: 10,
+		},
+		{
+			name:           "Repository error",
+			accessTokens:   3,
+			refreshErr:     repository.ErrInternal,
+			expectedPurged: 3,
+			expectedLimit:  7,
+			expectedErr:    ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock repository
+			refreshLimit := 0
+			mockTokenRepository := &MockTokenRepository{
+				purgeRevokedAccessTokensFunc: func(ctx context.Context, expiredBefore time.Time, limit int) (int, error) {
+					if !expiredBefore.Equal(now) || limit != 10 {
+						t.Errorf("Unexpected purge of %d access tokens expired before %v", limit, expiredBefore)
+					}
+					return tt.accessTokens, nil
+				},
+				purgeRefreshTokensFunc: func(ctx context.Context, expiredBefore time.Time, limit int) (int, error) {
+					refreshLimit = limit
+					if tt.refreshErr != nil {
+						return 0, tt.refreshErr
+					}
+					return 2, nil
+				},
+			}
+
+			// Create service with mock repositories
+			userService := NewUserService(
+				&MockUserRepository{},
+				mockTokenRepository,
+				jwtkeys.NewHMACKeySet([]byte("test-secret")),
+				15*time.Minute,
+				720*time.Hour,
+			)
+
+			// Call PurgeExpiredTokens
+			purged, err := userService.PurgeExpiredTokens(context.Background(), now, 10)
+
+			// Validate result
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+			if purged != tt.expectedPurged {
+				t.Errorf("Expected %d tokens purged, got %d", tt.expectedPurged, purged)
+			}
+			if refreshLimit != tt.expectedLimit {
+				t.Errorf("Expected refresh tokens purged up to %d, got %d", tt.expectedLimit, refreshLimit)
+			}
+		})
+	}
+}
@@ -0,0 +1,463 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Nilesh2000/conduit/internal/repository"
+	"github.com/Nilesh2000/conduit/internal/webhook"
+)
+
+// webhookSecretPrefix marks webhook signing secrets, so that they are easy to recognize
+const webhookSecretPrefix = "whsec_"
+
+// Webhook represents a URL the current user has events about their articles sent to.
+// Secret is only returned when the webhook is created.
+type Webhook struct {
+	ID        int64     `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// WebhookDelivery represents an event sent, or queued to be sent, to a webhook.
+// NextAttemptAt is only set while the delivery is pending.
+type WebhookDelivery struct {
+	ID             int64           `json:"id"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	ResponseStatus *int            `json:"responseStatus"`
+	LastError      string          `json:"lastError,omitempty"`
+	NextAttemptAt  *time.Time      `json:"nextAttemptAt"`
+	LastAttemptAt  *time.Time      `json:"lastAttemptAt"`
+	CreatedAt      time.Time       `json:"createdAt"`
+}
+
+// WebhookDeliveryList represents a page of the deliveries of a webhook, newest first.
+// HasMore reports whether more deliveries follow the page in the direction it was read
+// in, and First and Last are the positions of the first and last deliveries of the
+// page, which are nil if it is empty.
+type WebhookDeliveryList struct {
+	Deliveries []WebhookDelivery
+	HasMore    bool
+	First      *repository.Cursor
+	Last       *repository.Cursor
+}
+
+// WebhookPayload represents the body of a delivery. Article is the article the event is
+// about, as anyone would see it, and Comment is set for comments.
+type WebhookPayload struct {
+	Event     string    `json:"event"`
+	CreatedAt time.Time `json:"createdAt"`
+	Article   *Article  `json:"article"`
+	Comment   *Comment  `json:"comment,omitempty"`
+}
+
+// WebhookQueue is an interface for queueing deliveries to webhooks
+type WebhookQueue interface {
+	Enqueue(ctx context.Context, userID, eventID int64, event string, payload []byte) (int, error)
+}
+
+// WebhookRepository is an interface for the webhook repository
+type WebhookRepository interface {
+	WebhookQueue
+	Create(ctx context.Context, webhook repository.Webhook) (*repository.Webhook, error)
+	List(ctx context.Context, userID int64) ([]repository.Webhook, error)
+	Update(
+		ctx context.Context,
+		userID, webhookID int64,
+		url *string,
+		events []string,
+		active *bool,
+	) (*repository.Webhook, error)
+	Delete(ctx context.Context, userID, webhookID int64) error
+	ClaimDue(
+		ctx context.Context,
+		now, leaseUntil time.Time,
+		limit int,
+	) ([]repository.WebhookDelivery, error)
+	RecordAttempt(ctx context.Context, deliveryID int64, attempt repository.WebhookAttempt) error
+	ListDeliveries(
+		ctx context.Context,
+		userID, webhookID int64,
+		filters repository.WebhookDeliveryFilters,
+	) (*repository.WebhookDeliveryListResult, error)
+	Replay(ctx context.Context, userID, webhookID, deliveryID int64) (*repository.WebhookDelivery, error)
+}
+
+// WebhookSender is an interface for sending deliveries to webhooks
+type WebhookSender interface {
+	Send(ctx context.Context, delivery webhook.Delivery) (int, error)
+}
+
+// webhookService implements the WebhookService interface
+type webhookService struct {
+	webhookRepository WebhookRepository
+	sender            WebhookSender
+	timeout           time.Duration
+	maxAttempts       int
+	retryBackoff      time.Duration
+	maxRetryBackoff   time.Duration
+}
+
+// NewWebhookService creates a new webhook service. Deliveries are given timeout to be
+// sent and are attempted up to maxAttempts times, waiting retryBackoff after the first
+// failed attempt and twice as long after each one that follows, up to maxRetryBackoff.
+func NewWebhookService(
+	webhookRepository WebhookRepository,
+	sender WebhookSender,
+	timeout time.Duration,
+	maxAttempts int,
+	retryBackoff time.Duration,
+	maxRetryBackoff time.Duration,
+) *webhookService {
+	return &webhookService{
+		webhookRepository: webhookRepository,
+		sender:            sender,
+		timeout:           timeout,
+		maxAttempts:       maxAttempts,
+		retryBackoff:      retryBackoff,
+		maxRetryBackoff:   maxRetryBackoff,
+	}
+}
+
+// CreateWebhook creates a webhook that is sent the events a user subscribes it to, and
+// returns it along with the secret its deliveries are signed with
+func (s *webhookService) CreateWebhook(
+	ctx context.Context,
+	userID int64,
+	rawURL string,
+	events []string,
+) (*Webhook, error) {
+	if err := validateWebhookURL(rawURL); err != nil {
+		return nil, err
+	}
+	events, err := normalizeWebhookEvents(events)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.webhookRepository.Create(ctx, repository.Webhook{
+		UserID: userID,
+		URL:    rawURL,
+		Secret: generateWebhookSecret(),
+		Events: events,
+		Active: true,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrUserNotFound):
+			return nil, ErrUserNotFound
+		default:
+			return nil, ErrInternalServer
+		}
+	}
+
+	converted := webhookFromRepository(*created)
+	converted.Secret = created.Secret
+	return &converted, nil
+}
+
+// ListWebhooks lists the webhooks of a user, oldest first
+func (s *webhookService) ListWebhooks(ctx context.Context, userID int64) ([]Webhook, error) {
+	webhooks, err := s.webhookRepository.List(ctx, userID)
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+
+	converted := make([]Webhook, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		converted = append(converted, webhookFromRepository(webhook))
+	}
+
+	return converted, nil
+}
+
+// UpdateWebhook updates a webhook of a user, leaving the URL, events and active flag that
+// are nil as they are. Deliveries to inactive webhooks are queued but not sent until the
+// webhook is active again.
+func (s *webhookService) UpdateWebhook(
+	ctx context.Context,
+	userID, webhookID int64,
+	rawURL *string,
+	events []string,
+	active *bool,
+) (*Webhook, error) {
+	if rawURL != nil {
+		if err := validateWebhookURL(*rawURL); err != nil {
+			return nil, err
+		}
+	}
+	if events != nil {
+		var err error
+		if events, err = normalizeWebhookEvents(events); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := s.webhookRepository.Update(ctx, userID, webhookID, rawURL, events, active)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrWebhookNotFound):
+			return nil, ErrWebhookNotFound
+		default:
+			return nil, ErrInternalServer
+		}
+	}
+
+	converted := webhookFromRepository(*updated)
+	return &converted, nil
+}
+
+// DeleteWebhook deletes a webhook of a user along with its deliveries
+func (s *webhookService) DeleteWebhook(ctx context.Context, userID, webhookID int64) error {
+	if err := s.webhookRepository.Delete(ctx, userID, webhookID); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrWebhookNotFound):
+			return ErrWebhookNotFound
+		default:
+			return ErrInternalServer
+		}
+	}
+
+	return nil
+}
+
+// ListDeliveries gets a page of the deliveries of a webhook of a user, newest first
+func (s *webhookService) ListDeliveries(
+	ctx context.Context,
+	userID, webhookID int64,
+	filters repository.WebhookDeliveryFilters,
+) (*WebhookDeliveryList, error) {
+	result, err := s.webhookRepository.ListDeliveries(ctx, userID, webhookID, filters)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrWebhookNotFound):
+			return nil, ErrWebhookNotFound
+		default:
+			return nil, ErrInternalServer
+		}
+	}
+
+	deliveries := make([]WebhookDelivery, 0, len(result.Deliveries))
+	for _, delivery := range result.Deliveries {
+		deliveries = append(deliveries, webhookDeliveryFromRepository(delivery))
+	}
+
+	list := &WebhookDeliveryList{
+		Deliveries: deliveries,
+		HasMore:    result.HasMore,
+	}
+	if n := len(result.Deliveries); n > 0 {
+		first, last := result.Deliveries[0], result.Deliveries[n-1]
+		list.First = &repository.Cursor{CreatedAt: first.CreatedAt, ID: first.ID}
+		list.Last = &repository.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	return list, nil
+}
+
+// ReplayDelivery queues a delivery of a webhook of a user to be sent again, and returns
+// the new delivery
+func (s *webhookService) ReplayDelivery(
+	ctx context.Context,
+	userID, webhookID, deliveryID int64,
+) (*WebhookDelivery, error) {
+	delivery, err := s.webhookRepository.Replay(ctx, userID, webhookID, deliveryID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrWebhookNotFound):
+			return nil, ErrWebhookNotFound
+		case errors.Is(err, repository.ErrWebhookDeliveryNotFound):
+			return nil, ErrWebhookDeliveryNotFound
+		default:
+			return nil, ErrInternalServer
+		}
+	}
+
+	converted := webhookDeliveryFromRepository(*delivery)
+	return &converted, nil
+}
+
+// DeliverDueWebhooks sends up to limit deliveries that are due at now and returns how
+// many were attempted. Deliveries that fail are retried with exponential backoff until
+// they run out of attempts. Deliveries are claimed for as long as the whole batch may
+// take to send, and a delivery whose attempt is not recorded, because the process
+// stopped, is sent again once its claim runs out, so receivers may get a delivery more
+// than once and should tell repeats apart by its ID.
+func (s *webhookService) DeliverDueWebhooks(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+) (int, error) {
+	leaseUntil := now.Add(time.Duration(limit+1) * s.timeout)
+	deliveries, err := s.webhookRepository.ClaimDue(ctx, now, leaseUntil, limit)
+	if err != nil {
+		return 0, ErrInternalServer
+	}
+
+	for _, delivery := range deliveries {
+		attempt := s.send(ctx, delivery)
+		if err := s.webhookRepository.RecordAttempt(ctx, delivery.ID, attempt); err != nil {
+			log.Printf("error recording attempt of webhook delivery %d: %v", delivery.ID, err)
+		}
+	}
+
+	return len(deliveries), nil
+}
+
+// send sends a delivery and works out the status it is left in
+func (s *webhookService) send(
+	ctx context.Context,
+	delivery repository.WebhookDelivery,
+) repository.WebhookAttempt {
+	sendCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	status, err := s.sender.Send(sendCtx, webhook.Delivery{
+		ID:      delivery.ID,
+		Event:   delivery.Event,
+		URL:     delivery.URL,
+		Secret:  delivery.Secret,
+		Payload: delivery.Payload,
+	})
+
+	attempt := repository.WebhookAttempt{
+		AttemptedAt:   time.Now(),
+		Status:        repository.WebhookDeliverySucceeded,
+		NextAttemptAt: delivery.NextAttemptAt,
+	}
+	if status != 0 {
+		attempt.ResponseStatus = &status
+	}
+	if err == nil {
+		return attempt
+	}
+
+	attempt.Error = err.Error()
+	attempts := delivery.Attempts + 1
+	if attempts >= s.maxAttempts {
+		attempt.Status = repository.WebhookDeliveryFailed
+		return attempt
+	}
+
+	attempt.Status = repository.WebhookDeliveryPending
+	attempt.NextAttemptAt = attempt.AttemptedAt.Add(backoff(s.retryBackoff, s.maxRetryBackoff, attempts))
+	return attempt
+}
+
+// backoff returns how long to wait before retrying something that failed attempts
+// times, doubling from retryBackoff up to maxRetryBackoff
+func backoff(retryBackoff, maxRetryBackoff time.Duration, attempts int) time.Duration {
+	backoff := retryBackoff
+	for i := 1; i < attempts && backoff < maxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxRetryBackoff)
+}
+
+// webhookArticle converts an article for a webhook payload, as anyone would see it
+func webhookArticle(article *repository.Article) *Article {
+	converted := &Article{
+		Slug:           article.Slug,
+		Title:          article.Title,
+		Description:    article.Description,
+		Body:           article.Body,
+		TagList:        article.TagList,
+		CreatedAt:      article.CreatedAt,
+		UpdatedAt:      article.UpdatedAt,
+		FavoritesCount: article.FavoritesCount,
+		Status:         article.Status,
+		PublishAt:      article.PublishAt,
+		PublishedAt:    article.PublishedAt,
+	}
+	if article.Author != nil {
+		converted.Author = Profile{
+			Username: article.Author.Username,
+			Bio:      article.Author.Bio,
+			Image:    article.Author.Image,
+		}
+	}
+	return converted
+}
+
+// validateWebhookURL checks that a webhook URL is an absolute http or https URL
+func validateWebhookURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return ErrInvalidWebhookURL
+	}
+	return nil
+}
+
+// normalizeWebhookEvents trims, sorts and dedupes the events a webhook subscribes to,
+// which must be known and not empty
+func normalizeWebhookEvents(events []string) ([]string, error) {
+	normalized := make([]string, 0, len(events))
+	for _, event := range events {
+		event = strings.TrimSpace(event)
+		if !slices.Contains(repository.WebhookEvents, event) {
+			return nil, ErrInvalidWebhookEvents
+		}
+		normalized = append(normalized, event)
+	}
+	if len(normalized) == 0 {
+		return nil, ErrInvalidWebhookEvents
+	}
+
+	slices.Sort(normalized)
+	return slices.Compact(normalized), nil
+}
+
+// generateWebhookSecret generates a random secret to sign the deliveries of a webhook
+func generateWebhookSecret() string {
+	b := make([]byte, 32)
+	// crypto/rand.Read never returns an error
+	_, _ = rand.Read(b)
+	return webhookSecretPrefix + hex.EncodeToString(b)
+}
+
+// webhookFromRepository converts a webhook read from the repository, leaving out its
+// secret
+func webhookFromRepository(webhook repository.Webhook) Webhook {
+	return Webhook{
+		ID:        webhook.ID,
+		URL:       webhook.URL,
+		Events:    webhook.Events,
+		Active:    webhook.Active,
+		CreatedAt: webhook.CreatedAt,
+		UpdatedAt: webhook.UpdatedAt,
+	}
+}
+
+// webhookDeliveryFromRepository converts a webhook delivery read from the repository
+func webhookDeliveryFromRepository(delivery repository.WebhookDelivery) WebhookDelivery {
+	converted := WebhookDelivery{
+		ID:             delivery.ID,
+		Event:          delivery.Event,
+		Payload:        json.RawMessage(delivery.Payload),
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		ResponseStatus: delivery.ResponseStatus,
+		LastError:      delivery.LastError,
+		LastAttemptAt:  delivery.LastAttemptAt,
+		CreatedAt:      delivery.CreatedAt,
+	}
+	if delivery.Status == repository.WebhookDeliveryPending {
+		converted.NextAttemptAt = &delivery.NextAttemptAt
+	}
+	return converted
+}
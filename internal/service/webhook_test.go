@@ -0,0 +1,405 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Nilesh2000/conduit/internal/repository"
+	"github.com/Nilesh2000/conduit/internal/webhook"
+)
+
+// QueuedWebhook is a delivery queued with a MockWebhookQueue
+type QueuedWebhook struct {
+	UserID  int64
+	EventID int64
+	Payload WebhookPayload
+}
+
+// MockWebhookQueue is a mock implementation of the WebhookQueue interface that records
+// the deliveries it queues
+type MockWebhookQueue struct {
+	mu     sync.Mutex
+	queued []QueuedWebhook
+}
+
+// Enqueue is a mock implementation of the Enqueue method
+func (m *MockWebhookQueue) Enqueue(ctx context.Context, userID, eventID int64, event string, payload []byte) (int, error) {
+	var decoded WebhookPayload
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		return 0, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.queued = append(m.queued, QueuedWebhook{UserID: userID, EventID: eventID, Payload: decoded})
+	return 1, nil
+}
+
+// Queued returns the deliveries queued so far
+func (m *MockWebhookQueue) Queued() []QueuedWebhook {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]QueuedWebhook{}, m.queued...)
+}
+
+// MockWebhookRepository is a mock implementation of the WebhookRepository interface
+type MockWebhookRepository struct {
+	MockWebhookQueue
+	createFunc         func(ctx context.Context, webhook repository.Webhook) (*repository.Webhook, error)
+	listFunc           func(ctx context.Context, userID int64) ([]repository.Webhook, error)
+	updateFunc         func(ctx context.Context, userID, webhookID int64, url *string, events []string, active *bool) (*repository.Webhook, error)
+	deleteFunc         func(ctx context.Context, userID, webhookID int64) error
+	claimDueFunc       func(ctx context.Context, now, leaseUntil time.Time, limit int) ([]repository.WebhookDelivery, error)
+	recordAttemptFunc  func(ctx context.Context, deliveryID int64, attempt repository.WebhookAttempt) error
+	listDeliveriesFunc func(ctx context.Context, userID, webhookID int64, filters repository.WebhookDeliveryFilters) (*repository.WebhookDeliveryListResult, error)
+	replayFunc         func(ctx context.Context, userID, webhookID, deliveryID int64) (*repository.WebhookDelivery, error)
+}
+
+// Create is a mock implementation of the Create method
+func (m *MockWebhookRepository) Create(ctx context.Context, webhook repository.Webhook) (*repository.Webhook, error) {
+	return m.createFunc(ctx, webhook)
+}
+
+// List is a mock implementation of the List method
+func (m *MockWebhookRepository) List(ctx context.Context, userID int64) ([]repository.Webhook, error) {
+	return m.listFunc(ctx, userID)
+}
+
+// Update is a mock implementation of the Update method
+func (m *MockWebhookRepository) Update(
+	ctx context.Context,
+	userID, webhookID int64,
+	url *string,
+	events []string,
+	active *bool,
+) (*repository.Webhook, error) {
+	return m.updateFunc(ctx, userID, webhookID, url, events, active)
+}
+
+// Delete is a mock implementation of the Delete method
+func (m *MockWebhookRepository) Delete(ctx context.Context, userID, webhookID int64) error {
+	return m.deleteFunc(ctx, userID, webhookID)
+}
+
+// ClaimDue is a mock implementation of the ClaimDue method
+func (m *MockWebhookRepository) ClaimDue(
+	ctx context.Context,
+	now, leaseUntil time.Time,
+	limit int,
+) ([]repository.WebhookDelivery, error) {
+	return m.claimDueFunc(ctx, now, leaseUntil, limit)
+}
+
+// RecordAttempt is a mock implementation of the RecordAttempt method
+func (m *MockWebhookRepository) RecordAttempt(
+	ctx context.Context,
+	deliveryID int64,
+	attempt repository.WebhookAttempt,
+) error {
+	return m.recordAttemptFunc(ctx, deliveryID, attempt)
+}
+
+// ListDeliveries is a mock implementation of the ListDeliveries method
+func (m *MockWebhookRepository) ListDeliveries(
+	ctx context.Context,
+	userID, webhookID int64,
+	filters repository.WebhookDeliveryFilters,
+) (*repository.WebhookDeliveryListResult, error) {
+	return m.listDeliveriesFunc(ctx, userID, webhookID, filters)
+}
+
+// Replay is a mock implementation of the Replay method
+func (m *MockWebhookRepository) Replay(
+	ctx context.Context,
+	userID, webhookID, deliveryID int64,
+) (*repository.WebhookDelivery, error) {
+	return m.replayFunc(ctx, userID, webhookID, deliveryID)
+}
+
+// MockWebhookSender is a mock implementation of the WebhookSender interface
+type MockWebhookSender struct {
+	sendFunc func(ctx context.Context, delivery webhook.Delivery) (int, error)
+}
+
+// Send is a mock implementation of the Send method
+func (m *MockWebhookSender) Send(ctx context.Context, delivery webhook.Delivery) (int, error) {
+	return m.sendFunc(ctx, delivery)
+}
+
+// Test_webhookService_CreateWebhook tests the CreateWebhook method of the webhookService
+func Test_webhookService_CreateWebhook(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		url            string
+		events         []string
+		createErr      error
+		expectedEvents []string
+		expectedErr    error
+	}{
+		{
+			name:           "Webhook created",
+			url:            "https://example.com/hook",
+			events:         []string{" comment.created", "article.published", "comment.created"},
+			expectedEvents: []string{"article.published", "comment.created"},
+		},
+		{
+			name:        "Relative URL",
+			url:         "/hook",
+			events:      []string{"article.published"},
+			expectedErr: ErrInvalidWebhookURL,
+		},
+		{
+			name:        "Unsupported scheme",
+			url:         "ftp://example.com/hook",
+			events:      []string{"article.published"},
+			expectedErr: ErrInvalidWebhookURL,
+		},
+		{
+			name:        "No events",
+			url:         "https://example.com/hook",
+			expectedErr: ErrInvalidWebhookEvents,
+		},
+		{
+			name:        "Unknown event",
+			url:         "https://example.com/hook",
+			events:      []string{"article.published", "user.deleted"},
+			expectedErr: ErrInvalidWebhookEvents,
+		},
+		{
+			name:        "Repository error",
+			url:         "https://example.com/hook",
+			events:      []string{"article.published"},
+			createErr:   repository.ErrInternal,
+			expectedErr: ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup Mock Repository
+			mockRepo := &MockWebhookRepository{
+				createFunc: func(ctx context.Context, webhook repository.Webhook) (*repository.Webhook, error) {
+					if tt.createErr != nil {
+						return nil, tt.createErr
+					}
+					webhook.ID = 3
+					return &webhook, nil
+				},
+			}
+
+			// Create Service
+			webhookService := NewWebhookService(mockRepo, &MockWebhookSender{}, time.Second, 3, time.Minute, time.Hour)
+
+			// Call Method
+			webhook, err := webhookService.CreateWebhook(context.Background(), 1, tt.url, tt.events)
+
+			// Validate Result
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(webhook.Events, tt.expectedEvents) {
+				t.Errorf("Expected events %v, got %v", tt.expectedEvents, webhook.Events)
+			}
+			if !webhook.Active || !strings.HasPrefix(webhook.Secret, webhookSecretPrefix) {
+				t.Errorf("Expected an active webhook with a secret, got %+v", webhook)
+			}
+		})
+	}
+}
+
+// Test_webhookService_DeliverDueWebhooks tests that deliveries are sent and left
+// succeeded, retried with backoff or failed
+func Test_webhookService_DeliverDueWebhooks(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	unavailable := errors.New("unexpected status 503")
+
+	tests := []struct {
+		name            string
+		attempts        int
+		sendStatus      int
+		sendErr         error
+		expectedStatus  string
+		expectedBackoff time.Duration
+	}{
+		{
+			name:           "Delivery accepted",
+			sendStatus:     204,
+			expectedStatus: repository.WebhookDeliverySucceeded,
+		},
+		{
+			name:            "First attempt failed",
+			sendStatus:      503,
+			sendErr:         unavailable,
+			expectedStatus:  repository.WebhookDeliveryPending,
+			expectedBackoff: time.Minute,
+		},
+		{
+			name:            "Backoff doubles",
+			attempts:        1,
+			sendErr:         unavailable,
+			expectedStatus:  repository.WebhookDeliveryPending,
+			expectedBackoff: 2 * time.Minute,
+		},
+		{
+			name:           "Out of attempts",
+			attempts:       2,
+			sendErr:        unavailable,
+			expectedStatus: repository.WebhookDeliveryFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup Mock Repository with one due delivery
+			var recorded *repository.WebhookAttempt
+			mockRepo := &MockWebhookRepository{
+				claimDueFunc: func(ctx context.Context, claimedAt, leaseUntil time.Time, limit int) ([]repository.WebhookDelivery, error) {
+					if !claimedAt.Equal(now) || !leaseUntil.After(now) || limit != 10 {
+						t.Errorf("Unexpected claim at %v until %v of %d", claimedAt, leaseUntil, limit)
+					}
+					return []repository.WebhookDelivery{{
+						ID:            5,
+						Event:         repository.WebhookEventArticlePublished,
+						Payload:       []byte(`{}`),
+						Attempts:      tt.attempts,
+						NextAttemptAt: leaseUntil,
+						URL:           "https://example.com/hook",
+						Secret:        "whsec_test",
+					}}, nil
+				},
+				recordAttemptFunc: func(ctx context.Context, deliveryID int64, attempt repository.WebhookAttempt) error {
+					if deliveryID != 5 {
+						t.Errorf("Expected delivery 5, got %d", deliveryID)
+					}
+					recorded = &attempt
+					return nil
+				},
+			}
+
+			// Setup Mock Sender
+			mockSender := &MockWebhookSender{
+				sendFunc: func(ctx context.Context, delivery webhook.Delivery) (int, error) {
+					if delivery.ID != 5 || delivery.URL != "https://example.com/hook" || delivery.Secret != "whsec_test" {
+						t.Errorf("Unexpected delivery %+v", delivery)
+					}
+					return tt.sendStatus, tt.sendErr
+				},
+			}
+
+			// Create Service
+			webhookService := NewWebhookService(mockRepo, mockSender, time.Second, 3, time.Minute, time.Hour)
+
+			// Call Method
+			attempted, err := webhookService.DeliverDueWebhooks(context.Background(), now, 10)
+
+			// Validate Result
+			if err != nil || attempted != 1 {
+				t.Fatalf("Expected 1 delivery attempted, got %d, %v", attempted, err)
+			}
+			if recorded == nil {
+				t.Fatal("Expected the attempt to be recorded")
+			}
+			if recorded.Status != tt.expectedStatus {
+				t.Errorf("Expected status %q, got %q", tt.expectedStatus, recorded.Status)
+			}
+			if tt.sendStatus != 0 && (recorded.ResponseStatus == nil || *recorded.ResponseStatus != tt.sendStatus) {
+				t.Errorf("Expected response status %d, got %v", tt.sendStatus, recorded.ResponseStatus)
+			}
+			if tt.sendErr != nil && recorded.Error != tt.sendErr.Error() {
+				t.Errorf("Expected error %q, got %q", tt.sendErr.Error(), recorded.Error)
+			}
+			if tt.expectedBackoff != 0 {
+				if backoff := recorded.NextAttemptAt.Sub(recorded.AttemptedAt); backoff != tt.expectedBackoff {
+					t.Errorf("Expected backoff %v, got %v", tt.expectedBackoff, backoff)
+				}
+			}
+		})
+	}
+}
+
+// Test_backoff tests that the backoff doubles up to its maximum
+func Test_backoff(t *testing.T) {
+	t.Parallel()
+
+	expected := map[int]time.Duration{
+		1:  30 * time.Second,
+		2:  time.Minute,
+		5:  8 * time.Minute,
+		8:  time.Hour,
+		99: time.Hour,
+	}
+	for attempts, want := range expected {
+		if got := backoff(30*time.Second, time.Hour, attempts); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", attempts, got, want)
+		}
+	}
+}
+
+// Test_webhookService_ReplayDelivery tests the ReplayDelivery method of the webhookService
+func Test_webhookService_ReplayDelivery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		replayErr   error
+		expectedErr error
+	}{
+		{name: "Delivery replayed"},
+		{name: "Webhook not found", replayErr: repository.ErrWebhookNotFound, expectedErr: ErrWebhookNotFound},
+		{name: "Delivery not found", replayErr: repository.ErrWebhookDeliveryNotFound, expectedErr: ErrWebhookDeliveryNotFound},
+		{name: "Repository error", replayErr: repository.ErrInternal, expectedErr: ErrInternalServer},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup Mock Repository
+			mockRepo := &MockWebhookRepository{
+				replayFunc: func(ctx context.Context, userID, webhookID, deliveryID int64) (*repository.WebhookDelivery, error) {
+					if userID != 1 || webhookID != 3 || deliveryID != 5 {
+						t.Errorf("Unexpected replay of delivery %d of webhook %d by user %d", deliveryID, webhookID, userID)
+					}
+					if tt.replayErr != nil {
+						return nil, tt.replayErr
+					}
+					return &repository.WebhookDelivery{ID: 8, Status: repository.WebhookDeliveryPending, Payload: []byte(`{}`)}, nil
+				},
+			}
+
+			// Create Service
+			webhookService := NewWebhookService(mockRepo, &MockWebhookSender{}, time.Second, 3, time.Minute, time.Hour)
+
+			// Call Method
+			delivery, err := webhookService.ReplayDelivery(context.Background(), 1, 3, 5)
+
+			// Validate Result
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+			if err == nil && (delivery.ID != 8 || delivery.NextAttemptAt == nil) {
+				t.Errorf("Expected pending delivery 8, got %+v", delivery)
+			}
+		})
+	}
+}
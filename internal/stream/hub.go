@@ -0,0 +1,175 @@
+package stream
+
+import (
+	"context"
+	"sync"
+)
+
+// Event types
+const (
+	EventComment      = "comment"
+	EventNotification = "notification"
+	EventArticle      = "article"
+)
+
+// Event represents something that happened which streaming clients may be told about.
+// Events only point at what happened, and are read again for every client that gets
+// them, so that each client sees it the way it is allowed to. ArticleID is set for
+// comments and articles, Slug for articles, CommentID for comments, and UserID and
+// NotificationID for notifications, UserID being the user notified.
+type Event struct {
+	ID             int64  `json:"-"`
+	Type           string `json:"type"`
+	UserID         int64  `json:"userId,omitempty"`
+	ArticleID      int64  `json:"articleId,omitempty"`
+	Slug           string `json:"slug,omitempty"`
+	CommentID      int64  `json:"commentId,omitempty"`
+	NotificationID int64  `json:"notificationId,omitempty"`
+}
+
+// subscriptionBuffer is the number of events a subscription holds before its subscriber
+// is considered too slow to keep up
+const subscriptionBuffer = 64
+
+// Hub fans events out to the subscribers in this process and keeps the most recent ones,
+// so that subscribers that reconnect can catch up on the events they missed
+type Hub struct {
+	mu          sync.Mutex
+	lastID      int64
+	history     []Event
+	historySize int
+	subscribers map[*Subscription]struct{}
+	closed      bool
+}
+
+// NewHub creates a new Hub that keeps the last historySize events
+func NewHub(historySize int) *Hub {
+	return &Hub{
+		historySize: historySize,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish numbers an event after the last one and delivers it, for when every subscriber
+// is in this process
+func (h *Hub) Publish(_ context.Context, event Event) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	event.ID = h.lastID + 1
+	h.deliver(event)
+	return nil
+}
+
+// Deliver delivers an event that was already numbered, such as one received from
+// another replica
+func (h *Hub) Deliver(event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.deliver(event)
+}
+
+// deliver records an event and sends it to every subscriber. Subscribers whose buffer
+// is full are dropped rather than blocking everyone else, and can catch up from the
+// history when they subscribe again. The caller must hold h.mu.
+func (h *Hub) deliver(event Event) {
+	if h.closed {
+		return
+	}
+
+	h.lastID = max(h.lastID, event.ID)
+	if h.historySize > 0 {
+		if len(h.history) == h.historySize {
+			h.history = append(h.history[:0], h.history[1:]...)
+		}
+		h.history = append(h.history, event)
+	}
+
+	for subscription := range h.subscribers {
+		select {
+		case subscription.events <- event:
+		default:
+			h.unsubscribe(subscription)
+		}
+	}
+}
+
+// Subscribe subscribes to the events delivered from now on, and returns the events kept
+// in the history that were delivered after the one numbered lastEventID, or none if it
+// is 0. Events are usually delivered in the order they were numbered, but events from
+// different replicas can arrive out of order, so the history is read from the position
+// of that event when it is still kept.
+func (h *Hub) Subscribe(lastEventID int64) (*Subscription, []Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	subscription := &Subscription{
+		hub:    h,
+		events: make(chan Event, subscriptionBuffer),
+	}
+	if h.closed {
+		close(subscription.events)
+		return subscription, nil
+	}
+	h.subscribers[subscription] = struct{}{}
+
+	if lastEventID == 0 {
+		return subscription, nil
+	}
+
+	var missed []Event
+	for i := len(h.history) - 1; i >= 0; i-- {
+		if h.history[i].ID == lastEventID {
+			return subscription, append(missed, h.history[i+1:]...)
+		}
+	}
+	for _, event := range h.history {
+		if event.ID > lastEventID {
+			missed = append(missed, event)
+		}
+	}
+	return subscription, missed
+}
+
+// Close ends every subscription and stops delivering events, so that streams end when
+// the server shuts down
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for subscription := range h.subscribers {
+		h.unsubscribe(subscription)
+	}
+}
+
+// unsubscribe removes a subscription and closes its channel. The caller must hold h.mu.
+func (h *Hub) unsubscribe(subscription *Subscription) {
+	if _, ok := h.subscribers[subscription]; !ok {
+		return
+	}
+	delete(h.subscribers, subscription)
+	close(subscription.events)
+}
+
+// Subscription is a subscription to the events delivered by a Hub
+type Subscription struct {
+	hub    *Hub
+	events chan Event
+}
+
+// Events returns the events delivered to the subscription. The channel is closed when
+// the subscription ends, because it was closed, its subscriber fell too far behind or
+// the hub was closed.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Close ends the subscription
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	s.hub.unsubscribe(s)
+}
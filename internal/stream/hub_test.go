@@ -0,0 +1,150 @@
+package stream
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// eventIDs returns the IDs of events
+func eventIDs(events []Event) []int64 {
+	ids := []int64{}
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
+
+// TestHub_Publish tests that published events are numbered and reach every subscriber
+func TestHub_Publish(t *testing.T) {
+	t.Parallel()
+
+	hub := NewHub(10)
+	first, _ := hub.Subscribe(0)
+	second, _ := hub.Subscribe(0)
+
+	for range 2 {
+		if err := hub.Publish(context.Background(), Event{Type: EventComment, ArticleID: 1}); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+
+	for _, subscription := range []*Subscription{first, second} {
+		for _, want := range []int64{1, 2} {
+			event := <-subscription.Events()
+			if event.ID != want || event.Type != EventComment {
+				t.Errorf("Expected comment event %d, got %+v", want, event)
+			}
+		}
+	}
+}
+
+// TestHub_Subscribe tests the events missed since the last event a subscriber got
+func TestHub_Subscribe(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		delivered   []int64
+		lastEventID int64
+		expected    []int64
+	}{
+		{
+			name:        "New subscriber",
+			delivered:   []int64{1, 2, 3},
+			lastEventID: 0,
+			expected:    []int64{},
+		},
+		{
+			name:        "Resume after kept event",
+			delivered:   []int64{1, 2, 3},
+			lastEventID: 1,
+			expected:    []int64{2, 3},
+		},
+		{
+			name:        "Resume after event delivered out of order",
+			delivered:   []int64{1, 3, 2, 4},
+			lastEventID: 3,
+			expected:    []int64{2, 4},
+		},
+		{
+			name:        "Resume after event no longer kept",
+			delivered:   []int64{1, 2, 3, 4, 5},
+			lastEventID: 1,
+			expected:    []int64{3, 4, 5},
+		},
+		{
+			name:        "Up to date",
+			delivered:   []int64{1, 2},
+			lastEventID: 2,
+			expected:    []int64{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			hub := NewHub(3)
+			for _, id := range tt.delivered {
+				hub.Deliver(Event{ID: id, Type: EventArticle})
+			}
+
+			_, missed := hub.Subscribe(tt.lastEventID)
+			if got := eventIDs(missed); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected missed events %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+// TestHub_SlowSubscriber tests that a subscriber that falls behind is dropped without
+// holding up the others
+func TestHub_SlowSubscriber(t *testing.T) {
+	t.Parallel()
+
+	hub := NewHub(0)
+	slow, _ := hub.Subscribe(0)
+	fast, _ := hub.Subscribe(0)
+
+	for id := int64(1); id <= subscriptionBuffer+1; id++ {
+		hub.Deliver(Event{ID: id, Type: EventArticle})
+		if event := <-fast.Events(); event.ID != id {
+			t.Fatalf("Expected event %d, got %d", id, event.ID)
+		}
+	}
+
+	// The slow subscriber gets what fit in its buffer before its subscription ended
+	received := 0
+	for range slow.Events() {
+		received++
+	}
+	if received != subscriptionBuffer {
+		t.Errorf("Expected %d buffered events, got %d", subscriptionBuffer, received)
+	}
+}
+
+// TestHub_Close tests that closing the hub ends current and later subscriptions
+func TestHub_Close(t *testing.T) {
+	t.Parallel()
+
+	hub := NewHub(10)
+	subscription, _ := hub.Subscribe(0)
+	closed, _ := hub.Subscribe(0)
+	closed.Close()
+
+	hub.Close()
+	hub.Deliver(Event{ID: 1, Type: EventArticle})
+
+	if _, ok := <-subscription.Events(); ok {
+		t.Error("Expected subscription to end when the hub is closed")
+	}
+	late, _ := hub.Subscribe(0)
+	if _, ok := <-late.Events(); ok {
+		t.Error("Expected subscription to a closed hub to end at once")
+	}
+
+	// Closing a subscription that already ended does nothing
+	subscription.Close()
+}
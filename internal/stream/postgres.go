@@ -0,0 +1,124 @@
+package stream
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// channel is the Postgres notification channel events are sent on
+const channel = "conduit_events"
+
+// PostgresBus publishes events to every replica through Postgres LISTEN/NOTIFY. The
+// events of all replicas are numbered from one sequence, so a client can resume from the
+// last event it got on any replica.
+type PostgresBus struct {
+	db  *sql.DB
+	dsn string
+	hub *Hub
+}
+
+// NewPostgresBus creates a new PostgresBus that sends events through db and delivers
+// the events it listens for on a separate connection to dsn to hub
+func NewPostgresBus(db *sql.DB, dsn string, hub *Hub) *PostgresBus {
+	return &PostgresBus{db: db, dsn: dsn, hub: hub}
+}
+
+// Publish numbers an event and sends it to every replica, including this one
+func (b *PostgresBus) Publish(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	query := `SELECT pg_notify($1, nextval('stream_event_id_seq') || ' ' || $2)`
+	if _, err := b.db.ExecContext(ctx, query, channel, string(payload)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Run delivers the events sent by every replica to the hub until ctx is cancelled. The
+// listener reconnects on its own when its connection is lost, and the events sent in
+// the meantime are missed.
+func (b *PostgresBus) Run(ctx context.Context) {
+	listener := pq.NewListener(
+		b.dsn,
+		time.Second,
+		time.Minute,
+		func(event pq.ListenerEventType, err error) {
+			if err != nil {
+				log.Printf("Event stream listener error: %v", err)
+			}
+		},
+	)
+	defer func() {
+		if err := listener.Close(); err != nil {
+			log.Printf("Error closing event stream listener: %v", err)
+		}
+	}()
+
+	if err := listener.Listen(channel); err != nil {
+		log.Printf("Failed to listen for stream events: %v", err)
+		return
+	}
+
+	// Check the connection now and then, as a lost one goes unnoticed while idle
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case notification := <-listener.Notify:
+			// A nil notification means the connection was re-established
+			if notification == nil {
+				log.Printf("Event stream listener reconnected, events may have been missed")
+				continue
+			}
+			event, err := parseNotification(notification.Extra)
+			if err != nil {
+				log.Printf("Failed to read stream event: %v", err)
+				continue
+			}
+			b.hub.Deliver(event)
+		case <-ticker.C:
+			go func() {
+				if err := listener.Ping(); err != nil {
+					log.Printf("Event stream listener ping failed: %v", err)
+				}
+			}()
+		}
+	}
+}
+
+// parseNotification reads an event from the payload of a notification, which is its ID
+// followed by a space and the event as JSON
+func parseNotification(payload string) (Event, error) {
+	idStr, data, ok := strings.Cut(payload, " ")
+	if !ok {
+		return Event{}, fmt.Errorf("malformed payload %q", payload)
+	}
+
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return Event{}, fmt.Errorf("malformed event ID %q: %w", idStr, err)
+	}
+
+	var event Event
+	if err := json.Unmarshal([]byte(data), &event); err != nil {
+		return Event{}, fmt.Errorf("malformed event: %w", err)
+	}
+	event.ID = id
+
+	return event, nil
+}
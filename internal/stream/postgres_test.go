@@ -0,0 +1,90 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// TestPostgresBus_Publish tests that events are sent on the channel as JSON
+func TestPostgresBus_Publish(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock database: %v", err)
+	}
+	defer db.Close()
+
+	event := Event{Type: EventNotification, UserID: 2, NotificationID: 7}
+	payload, _ := json.Marshal(event)
+
+	mock.ExpectExec(`SELECT pg_notify\(\$1, nextval\('stream_event_id_seq'\) \|\| ' ' \|\| \$2\)`).
+		WithArgs(channel, string(payload)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`SELECT pg_notify`).
+		WillReturnError(errors.New("connection refused"))
+
+	bus := NewPostgresBus(db, "", NewHub(0))
+	if err := bus.Publish(context.Background(), event); err != nil {
+		t.Errorf("Publish() error = %v", err)
+	}
+	if err := bus.Publish(context.Background(), event); err == nil {
+		t.Error("Expected Publish() to fail when the notification cannot be sent")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+// Test_parseNotification tests reading events from notification payloads
+func Test_parseNotification(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		payload  string
+		expected Event
+		wantErr  bool
+	}{
+		{
+			name:     "Comment event",
+			payload:  `42 {"type":"comment","articleId":3,"commentId":9}`,
+			expected: Event{ID: 42, Type: EventComment, ArticleID: 3, CommentID: 9},
+		},
+		{
+			name:    "Missing event",
+			payload: "42",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid ID",
+			payload: `x {"type":"comment"}`,
+			wantErr: true,
+		},
+		{
+			name:    "Invalid JSON",
+			payload: "42 {",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			event, err := parseNotification(tt.payload)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseNotification() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && event != tt.expected {
+				t.Errorf("Expected event %+v, got %+v", tt.expected, event)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package validation
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 )
@@ -28,6 +29,20 @@ func TranslateValidationErrors(err error) []string {
 					validationErrors,
 					fmt.Sprintf("%s must be at least %s characters long", e.Field(), e.Param()),
 				)
+			case "max":
+				validationErrors = append(
+					validationErrors,
+					fmt.Sprintf("%s must be at most %s characters long", e.Field(), e.Param()),
+				)
+			case "excluded_with":
+				validationErrors = append(
+					validationErrors,
+					fmt.Sprintf(
+						"%s cannot be combined with %s",
+						e.Field(),
+						strings.ReplaceAll(e.Param(), " ", " or "),
+					),
+				)
 			default:
 				validationErrors = append(
 					validationErrors,
@@ -0,0 +1,143 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// Delivery headers
+const (
+	HeaderSignature = "X-Conduit-Signature"
+	HeaderTimestamp = "X-Conduit-Timestamp"
+	HeaderEvent     = "X-Conduit-Event"
+	HeaderDelivery  = "X-Conduit-Delivery"
+)
+
+// userAgent is the User-Agent deliveries are sent with
+const userAgent = "Conduit-Webhooks/1.0"
+
+// maxResponseBody is how much of a response body is read before the connection is
+// reused
+const maxResponseBody = 64 << 10
+
+// ErrAddressNotAllowed is returned when a webhook resolves to an address deliveries may
+// not be sent to
+var ErrAddressNotAllowed = errors.New("address not allowed")
+
+// sharedAddressSpace is the carrier-grade NAT range, which is not public either
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// Delivery represents an event sent to a webhook
+type Delivery struct {
+	ID      int64
+	Event   string
+	URL     string
+	Secret  string
+	Payload []byte
+}
+
+// Sender sends signed deliveries to webhooks over HTTP
+type Sender struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewSender creates a new Sender that gives each delivery timeout to be answered.
+// Redirects are not followed, since the receiver is expected to answer at the URL it was
+// registered with. Deliveries are only sent to public addresses, or to those in
+// allowedNetworks. The address is checked when connecting, after the host of the URL
+// was resolved, so that a host that resolves to an internal address cannot be used to
+// reach it, whatever it resolved to when the webhook was registered.
+func NewSender(timeout time.Duration, allowedNetworks []netip.Prefix) *Sender {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !allowedAddress(addrPort.Addr(), allowedNetworks) {
+				return fmt.Errorf("%w: %s", ErrAddressNotAllowed, addrPort.Addr())
+			}
+			return nil
+		},
+	}
+
+	// Deliveries are not sent through a proxy, which would connect on their behalf
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &Sender{
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		now: time.Now,
+	}
+}
+
+// allowedAddress reports whether deliveries may be sent to addr, which they may if it is
+// public or in one of allowedNetworks
+func allowedAddress(addr netip.Addr, allowedNetworks []netip.Prefix) bool {
+	addr = addr.Unmap()
+	for _, network := range allowedNetworks {
+		if network.Contains(addr) {
+			return true
+		}
+	}
+
+	return addr.IsGlobalUnicast() &&
+		!addr.IsPrivate() &&
+		!sharedAddressSpace.Contains(addr)
+}
+
+// Send posts a delivery to its webhook, returning the status the receiver answered with.
+// It returns an error if the delivery could not be sent or the status is not a 2xx, in
+// which case the status is 0 if there was no answer.
+func (s *Sender) Send(ctx context.Context, delivery Delivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(delivery.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Printf("error closing response body: %v", err)
+		}
+	}()
+
+	// Drain the body so that the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBody))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,242 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+// loopback lets deliveries reach the receivers the tests run locally
+var loopback = []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8"), netip.MustParsePrefix("::1/128")}
+
+// TestSender_Send tests that deliveries reach the receiver signed, and that the status it
+// answers with is reported
+func TestSender_Send(t *testing.T) {
+	t.Parallel()
+
+	sentAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	payload := []byte(`{"event":"article.published"}`)
+
+	tests := []struct {
+		name           string
+		status         int
+		redirect       bool
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "Delivery accepted",
+			status:         http.StatusNoContent,
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "Delivery rejected",
+			status:         http.StatusInternalServerError,
+			expectedStatus: http.StatusInternalServerError,
+			expectErr:      true,
+		},
+		{
+			name:           "Redirect not followed",
+			redirect:       true,
+			expectedStatus: http.StatusFound,
+			expectErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup a receiver that checks the signature the way a subscriber would
+			received := make(chan *http.Request, 1)
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/elsewhere" {
+					t.Error("Expected the redirect not to be followed")
+					return
+				}
+				if tt.redirect {
+					http.Redirect(w, r, "/elsewhere", http.StatusFound)
+					return
+				}
+
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					t.Errorf("Failed to read body: %v", err)
+				}
+				if !Verify("whsec_test", r.Header.Get(HeaderTimestamp), body, r.Header.Get(HeaderSignature)) {
+					t.Errorf("Signature %q does not match the body", r.Header.Get(HeaderSignature))
+				}
+				received <- r
+				w.WriteHeader(tt.status)
+			}))
+			defer receiver.Close()
+
+			// Create Sender
+			sender := NewSender(time.Second, loopback)
+			sender.now = func() time.Time { return sentAt }
+
+			// Send Delivery
+			status, err := sender.Send(context.Background(), Delivery{
+				ID:      7,
+				Event:   "article.published",
+				URL:     receiver.URL + "/hook",
+				Secret:  "whsec_test",
+				Payload: payload,
+			})
+
+			// Validate result
+			if (err != nil) != tt.expectErr {
+				t.Errorf("Expected error: %v, got %v", tt.expectErr, err)
+			}
+			if status != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, status)
+			}
+
+			// Validate headers
+			if tt.redirect {
+				return
+			}
+			r := <-received
+			expectedHeaders := map[string]string{
+				"Content-Type":  "application/json",
+				HeaderEvent:     "article.published",
+				HeaderDelivery:  "7",
+				HeaderTimestamp: "1704110400",
+			}
+			for name, want := range expectedHeaders {
+				if got := r.Header.Get(name); got != want {
+					t.Errorf("Expected header %s %q, got %q", name, want, got)
+				}
+			}
+		})
+	}
+}
+
+// TestSender_Send_Timeout tests that receivers that do not answer in time fail the
+// delivery
+func TestSender_Send_Timeout(t *testing.T) {
+	t.Parallel()
+
+	done := make(chan struct{})
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer receiver.Close()
+	defer close(done)
+
+	sender := NewSender(50*time.Millisecond, loopback)
+	status, err := sender.Send(context.Background(), Delivery{ID: 1, URL: receiver.URL, Payload: []byte(`{}`)})
+	if err == nil || status != 0 {
+		t.Errorf("Expected the delivery to time out without a status, got %d, %v", status, err)
+	}
+}
+
+// TestSender_Send_AddressNotAllowed tests that deliveries are not sent to addresses that
+// are not public unless they are allowed
+func TestSender_Send_AddressNotAllowed(t *testing.T) {
+	t.Parallel()
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected the delivery not to reach the receiver")
+	}))
+	defer receiver.Close()
+
+	sender := NewSender(time.Second, nil)
+	status, err := sender.Send(context.Background(), Delivery{ID: 1, URL: receiver.URL, Payload: []byte(`{}`)})
+	if !errors.Is(err, ErrAddressNotAllowed) || status != 0 {
+		t.Errorf("Expected the delivery to be refused without a status, got %d, %v", status, err)
+	}
+}
+
+// Test_allowedAddress tests which addresses deliveries may be sent to
+func Test_allowedAddress(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		addr            string
+		allowedNetworks []netip.Prefix
+		expected        bool
+	}{
+		{
+			name:     "Public IPv4",
+			addr:     "93.184.216.34",
+			expected: true,
+		},
+		{
+			name:     "Public IPv6",
+			addr:     "2606:2800:220:1:248:1893:25c8:1946",
+			expected: true,
+		},
+		{
+			name:     "Loopback",
+			addr:     "127.0.0.1",
+			expected: false,
+		},
+		{
+			name:     "IPv6 loopback",
+			addr:     "::1",
+			expected: false,
+		},
+		{
+			name:     "Private",
+			addr:     "10.0.0.5",
+			expected: false,
+		},
+		{
+			name:     "IPv6 unique local",
+			addr:     "fd00::1",
+			expected: false,
+		},
+		{
+			name:     "Link-local metadata",
+			addr:     "169.254.169.254",
+			expected: false,
+		},
+		{
+			name:     "Shared address space",
+			addr:     "100.100.100.200",
+			expected: false,
+		},
+		{
+			name:     "Unspecified",
+			addr:     "0.0.0.0",
+			expected: false,
+		},
+		{
+			name:     "IPv4-mapped loopback",
+			addr:     "::ffff:127.0.0.1",
+			expected: false,
+		},
+		{
+			name:            "Allowed loopback",
+			addr:            "127.0.0.1",
+			allowedNetworks: loopback,
+			expected:        true,
+		},
+		{
+			name:            "Private outside the allowed networks",
+			addr:            "10.0.0.5",
+			allowedNetworks: loopback,
+			expected:        false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := allowedAddress(netip.MustParseAddr(tt.addr), tt.allowedNetworks)
+			if got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
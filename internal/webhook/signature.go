@@ -0,0 +1,27 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// signaturePrefix names the algorithm a signature was made with
+const signaturePrefix = "sha256="
+
+// Sign signs the body of a delivery sent at timestamp, in Unix seconds, with the secret
+// of its webhook. The timestamp is signed along with the body so that a receiver that
+// checks it can turn away old deliveries that are sent again by someone else.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is the signature of the body of a delivery sent at
+// timestamp, made with secret
+func Verify(secret, timestamp string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body)))
+}
@@ -0,0 +1,36 @@
+package webhook
+
+import "testing"
+
+// TestVerify tests that signatures only match the secret, timestamp and body they were
+// made with
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{"event":"comment.created"}`)
+	signature := Sign("whsec_test", "1704110400", body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		body      []byte
+		expected  bool
+	}{
+		{name: "Matching", secret: "whsec_test", timestamp: "1704110400", body: body, expected: true},
+		{name: "Other secret", secret: "whsec_other", timestamp: "1704110400", body: body},
+		{name: "Other timestamp", secret: "whsec_test", timestamp: "1704110401", body: body},
+		{name: "Other body", secret: "whsec_test", timestamp: "1704110400", body: []byte(`{}`)},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := Verify(tt.secret, tt.timestamp, tt.body, signature); got != tt.expected {
+				t.Errorf("Verify() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// BatchFunc processes up to limit items that are due at now and returns how many it
+// processed
+type BatchFunc func(ctx context.Context, now time.Time, limit int) (int, error)
+
+// Batch periodically processes the items that are due in batches, such as expired rows
+// to delete or queued work to send. Several batches of the same kind can run at the same
+// time, one per replica, as long as process claims every item it processes for itself.
+type Batch struct {
+	name      string
+	process   BatchFunc
+	interval  time.Duration
+	batchSize int
+	now       func() time.Time
+}
+
+// NewBatch creates a new Batch processing the items described by name, such as
+// "expired tokens", with process
+func NewBatch(name string, process BatchFunc, interval time.Duration, batchSize int) *Batch {
+	return &Batch{
+		name:      name,
+		process:   process,
+		interval:  interval,
+		batchSize: batchSize,
+		now:       time.Now,
+	}
+}
+
+// Run processes due items every interval until ctx is cancelled. A batch that is being
+// processed when ctx is cancelled is finished before Run returns, so callers can wait
+// for Run to return during graceful shutdown.
+func (b *Batch) Run(ctx context.Context) {
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+
+	for {
+		b.processDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// processDue processes due items in batches until none are left
+func (b *Batch) processDue(ctx context.Context) {
+	// Let the current batch complete even if shutdown starts while it is running
+	batchCtx := context.WithoutCancel(ctx)
+
+	for ctx.Err() == nil {
+		processed, err := b.process(batchCtx, b.now(), b.batchSize)
+		if err != nil {
+			log.Printf("Failed to process %s: %v", b.name, err)
+			return
+		}
+
+		if processed > 0 {
+			log.Printf("Processed %d %s", processed, b.name)
+		}
+
+		// A partial batch means there is nothing left to process
+		if processed < b.batchSize {
+			return
+		}
+	}
+}
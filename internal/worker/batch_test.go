@@ -0,0 +1,142 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// MockBatchFunc is a mock implementation of a BatchFunc counting its calls
+type MockBatchFunc struct {
+	mu          sync.Mutex
+	calls       int
+	processFunc func(call int, now time.Time, limit int) (int, error)
+}
+
+// Process is a mock implementation of the BatchFunc
+func (m *MockBatchFunc) Process(ctx context.Context, now time.Time, limit int) (int, error) {
+	m.mu.Lock()
+	m.calls++
+	call := m.calls
+	m.mu.Unlock()
+	return m.processFunc(call, now, limit)
+}
+
+// Calls returns the number of times Process was called
+func (m *MockBatchFunc) Calls() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.calls
+}
+
+// TestBatch_processDue tests the processDue method of the Batch
+func TestBatch_processDue(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		batches       []int
+		err           error
+		expectedCalls int
+	}{
+		{
+			name:          "Nothing to process",
+			batches:       []int{0},
+			expectedCalls: 1,
+		},
+		{
+			name:          "Partial batch",
+			batches:       []int{1},
+			expectedCalls: 1,
+		},
+		{
+			name:          "Full batches are drained",
+			batches:       []int{2, 2, 1},
+			expectedCalls: 3,
+		},
+		{
+			name:          "Processing error",
+			batches:       []int{2},
+			err:           errors.New("database is down"),
+			expectedCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+			// Setup mock expectations
+			mock := &MockBatchFunc{
+				processFunc: func(call int, gotNow time.Time, limit int) (int, error) {
+					if !gotNow.Equal(now) {
+						t.Errorf("Expected now %v, got %v", now, gotNow)
+					}
+					if limit != 2 {
+						t.Errorf("Expected limit 2, got %d", limit)
+					}
+					if tt.err != nil {
+						return 0, tt.err
+					}
+					if call > len(tt.batches) {
+						t.Fatalf("Unexpected call %d", call)
+					}
+					return tt.batches[call-1], nil
+				},
+			}
+
+			batch := NewBatch("items", mock.Process, time.Hour, 2)
+			batch.now = func() time.Time { return now }
+
+			batch.processDue(context.Background())
+
+			if calls := mock.Calls(); calls != tt.expectedCalls {
+				t.Errorf("Expected %d calls, got %d", tt.expectedCalls, calls)
+			}
+		})
+	}
+}
+
+// TestBatch_Run tests that Run processes on every tick and returns once its context is cancelled
+func TestBatch_Run(t *testing.T) {
+	t.Parallel()
+
+	processed := make(chan struct{}, 10)
+	mock := &MockBatchFunc{
+		processFunc: func(call int, now time.Time, limit int) (int, error) {
+			processed <- struct{}{}
+			return 0, nil
+		},
+	}
+
+	batch := NewBatch("items", mock.Process, 10*time.Millisecond, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		batch.Run(ctx)
+		close(done)
+	}()
+
+	// Wait for the initial run and at least one tick
+	for range 2 {
+		select {
+		case <-processed:
+		case <-time.After(time.Second):
+			t.Fatal("Timed out waiting for the batch to run")
+		}
+	}
+
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after its context was cancelled")
+	}
+}
@@ -0,0 +1,26 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// ScheduledArticlePublisher publishes scheduled articles that are due
+type ScheduledArticlePublisher interface {
+	PublishScheduledArticles(ctx context.Context, now time.Time, limit int) ([]string, error)
+}
+
+// NewPublisher creates a Batch that periodically publishes scheduled articles once their
+// publication time has passed
+func NewPublisher(articles ScheduledArticlePublisher, interval time.Duration, batchSize int) *Batch {
+	publish := func(ctx context.Context, now time.Time, limit int) (int, error) {
+		slugs, err := articles.PublishScheduledArticles(ctx, now, limit)
+		for _, slug := range slugs {
+			log.Printf("Published scheduled article %q", slug)
+		}
+		return len(slugs), err
+	}
+
+	return NewBatch("scheduled articles", publish, interval, batchSize)
+}
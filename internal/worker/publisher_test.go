@@ -0,0 +1,94 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// MockScheduledArticlePublisher is a mock implementation of the ScheduledArticlePublisher interface
+type MockScheduledArticlePublisher struct {
+	mu                           sync.Mutex
+	calls                        int
+	publishScheduledArticlesFunc func(call int, now time.Time, limit int) ([]string, error)
+}
+
+// PublishScheduledArticles is a mock implementation of the PublishScheduledArticles method
+func (m *MockScheduledArticlePublisher) PublishScheduledArticles(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+) ([]string, error) {
+	m.mu.Lock()
+	m.calls++
+	call := m.calls
+	m.mu.Unlock()
+	return m.publishScheduledArticlesFunc(call, now, limit)
+}
+
+// Calls returns the number of times PublishScheduledArticles was called
+func (m *MockScheduledArticlePublisher) Calls() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.calls
+}
+
+// TestNewPublisher tests that the publisher counts the articles it published, so that
+// full batches are drained
+func TestNewPublisher(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		batches       [][]string
+		err           error
+		expectedCalls int
+	}{
+		{
+			name:          "Nothing to publish",
+			batches:       [][]string{{}},
+			expectedCalls: 1,
+		},
+		{
+			name:          "Full batches are drained",
+			batches:       [][]string{{"a", "b"}, {"c", "d"}, {"e"}},
+			expectedCalls: 3,
+		},
+		{
+			name:          "Repository error",
+			batches:       [][]string{{"a", "b"}},
+			err:           errors.New("database is down"),
+			expectedCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup mock expectations
+			mock := &MockScheduledArticlePublisher{
+				publishScheduledArticlesFunc: func(call int, now time.Time, limit int) ([]string, error) {
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					if call > len(tt.batches) {
+						t.Fatalf("Unexpected call %d", call)
+					}
+					return tt.batches[call-1], nil
+				},
+			}
+
+			publisher := NewPublisher(mock, time.Hour, 2)
+
+			publisher.processDue(context.Background())
+
+			if calls := mock.Calls(); calls != tt.expectedCalls {
+				t.Errorf("Expected %d calls, got %d", tt.expectedCalls, calls)
+			}
+		})
+	}
+}
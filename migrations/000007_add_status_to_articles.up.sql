@@ -0,0 +1,9 @@
+ALTER TABLE articles
+    ADD COLUMN status TEXT NOT NULL DEFAULT 'published'
+        CHECK (status IN ('draft', 'published', 'archived')),
+    ADD COLUMN published_at TIMESTAMP;
+
+-- Existing articles were visible as soon as they were created
+UPDATE articles SET published_at = created_at;
+
+CREATE INDEX idx_articles_status_created_at ON articles (status, created_at DESC);
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_articles_scheduled_publish_at;
+
+UPDATE articles SET status = 'draft' WHERE status = 'scheduled';
+
+ALTER TABLE articles
+    DROP CONSTRAINT IF EXISTS articles_publish_at_check,
+    DROP COLUMN IF EXISTS publish_at,
+    DROP CONSTRAINT articles_status_check;
+
+ALTER TABLE articles
+    ADD CONSTRAINT articles_status_check
+        CHECK (status IN ('draft', 'published', 'archived'));
@@ -0,0 +1,11 @@
+ALTER TABLE articles DROP CONSTRAINT articles_status_check;
+
+ALTER TABLE articles
+    ADD CONSTRAINT articles_status_check
+        CHECK (status IN ('draft', 'scheduled', 'published', 'archived')),
+    ADD COLUMN publish_at TIMESTAMP,
+    ADD CONSTRAINT articles_publish_at_check
+        CHECK ((status = 'scheduled') = (publish_at IS NOT NULL));
+
+-- The publisher worker only ever looks at scheduled articles that are due
+CREATE INDEX idx_articles_scheduled_publish_at ON articles (publish_at) WHERE status = 'scheduled';
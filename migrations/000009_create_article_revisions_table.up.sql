@@ -0,0 +1,16 @@
+CREATE TABLE article_revisions (
+    id SERIAL PRIMARY KEY,
+    article_id INTEGER NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    revision_number INTEGER NOT NULL,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE (article_id, revision_number)
+);
+
+-- The current text of existing articles becomes their first revision
+INSERT INTO article_revisions (article_id, revision_number, title, description, body, created_at)
+SELECT id, 1, title, description, body, updated_at
+FROM articles;
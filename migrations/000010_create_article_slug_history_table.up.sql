@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS article_slug_history (
+    slug TEXT PRIMARY KEY,
+    article_id INTEGER NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_slug_history_article_id ON article_slug_history(article_id);
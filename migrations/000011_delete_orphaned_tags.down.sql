@@ -0,0 +1 @@
+-- Orphaned tags cannot be restored
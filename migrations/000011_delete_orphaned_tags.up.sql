@@ -0,0 +1,4 @@
+-- Tags are deleted along with their last article from now on, so remove the ones
+-- that were left behind before
+DELETE FROM tags t
+WHERE NOT EXISTS (SELECT 1 FROM article_tags at WHERE at.tag_id = t.id);
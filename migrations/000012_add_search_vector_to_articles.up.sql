@@ -0,0 +1,11 @@
+-- Articles are indexed in the language they were written with, so changing the
+-- configured language does not change the meaning of existing search vectors
+ALTER TABLE articles
+    ADD COLUMN search_language REGCONFIG NOT NULL DEFAULT 'english',
+    ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector(search_language, title), 'A') ||
+        setweight(to_tsvector(search_language, description), 'B') ||
+        setweight(to_tsvector(search_language, body), 'C')
+    ) STORED;
+
+CREATE INDEX idx_articles_search_vector ON articles USING GIN (search_vector);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_comments_article_id_created_at_id;
+DROP INDEX IF EXISTS idx_articles_created_at_id;
@@ -0,0 +1,4 @@
+-- Pages are read by (created_at, id), which breaks ties between articles or comments
+-- created at the same time
+CREATE INDEX idx_articles_created_at_id ON articles (created_at DESC, id DESC);
+CREATE INDEX idx_comments_article_id_created_at_id ON comments (article_id, created_at, id);
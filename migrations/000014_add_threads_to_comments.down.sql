@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_comments_parent_id;
+
+ALTER TABLE comments
+    DROP COLUMN IF EXISTS depth,
+    DROP COLUMN IF EXISTS parent_id;
+
+-- Tombstones have nothing left to show once replies are flat again
+DELETE FROM comments WHERE tombstoned_at IS NOT NULL;
+
+ALTER TABLE comments DROP COLUMN IF EXISTS tombstoned_at;
@@ -0,0 +1,8 @@
+-- Replies point at the comment they answer. A deleted comment that has replies is kept
+-- as a tombstone, with its body cleared, so that its replies stay in place.
+ALTER TABLE comments
+    ADD COLUMN parent_id INTEGER REFERENCES comments(id),
+    ADD COLUMN depth INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN tombstoned_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_comments_parent_id ON comments (parent_id);
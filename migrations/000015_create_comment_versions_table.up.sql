@@ -0,0 +1,10 @@
+-- The earlier bodies of edited comments, each replaced by the next edit
+CREATE TABLE comment_versions (
+    id SERIAL PRIMARY KEY,
+    comment_id INTEGER NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    written_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    replaced_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_comment_versions_comment_id ON comment_versions (comment_id);
@@ -0,0 +1,15 @@
+DROP INDEX IF EXISTS idx_comments_deleted_at;
+DROP INDEX IF EXISTS idx_articles_deleted_at;
+
+ALTER TABLE comments
+    DROP CONSTRAINT comments_article_id_fkey,
+    ADD CONSTRAINT comments_article_id_fkey
+        FOREIGN KEY (article_id) REFERENCES articles(id);
+
+-- What is still in the trash is deleted for good
+DELETE FROM comments WHERE deleted_at IS NOT NULL;
+DELETE FROM comments WHERE article_id IN (SELECT id FROM articles WHERE deleted_at IS NOT NULL);
+DELETE FROM articles WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE comments DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE articles DROP COLUMN IF EXISTS deleted_at;
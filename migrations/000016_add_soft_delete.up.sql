@@ -0,0 +1,14 @@
+-- Deleted articles and comments stay in the trash of their author until they are restored
+-- or purged once the retention has passed
+ALTER TABLE articles ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE comments ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+
+-- Purging an article takes its comments with it
+ALTER TABLE comments
+    DROP CONSTRAINT comments_article_id_fkey,
+    ADD CONSTRAINT comments_article_id_fkey
+        FOREIGN KEY (article_id) REFERENCES articles(id) ON DELETE CASCADE;
+
+-- Trash listings and the purge worker only ever look at deleted rows
+CREATE INDEX idx_articles_deleted_at ON articles (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_comments_deleted_at ON comments (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_follows_following_id_created_at;
+
+ALTER TABLE follows DROP COLUMN IF EXISTS created_at;
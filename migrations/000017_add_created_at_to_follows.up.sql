@@ -0,0 +1,6 @@
+-- Follower and following lists are ordered by when the follow happened. Follows made
+-- before this migration all get the time it ran.
+ALTER TABLE follows ADD COLUMN created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+
+-- The primary key already covers the following list of a user, but not their followers
+CREATE INDEX idx_follows_following_id_created_at ON follows (following_id, created_at);
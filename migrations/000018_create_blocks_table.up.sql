@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS blocks (
+    blocker_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    blocked_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (blocker_id, blocked_id),
+
+    -- Check constraint to prevent self-block
+    CONSTRAINT prevent_self_block CHECK (blocker_id <> blocked_id)
+);
+
+-- Blocks are looked up from both sides
+CREATE INDEX idx_blocks_blocked_id ON blocks (blocked_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS tag_mutes;
+DROP TABLE IF EXISTS user_mutes;
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS user_mutes (
+    muter_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    muted_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (muter_id, muted_id),
+
+    -- Check constraint to prevent self-mute
+    CONSTRAINT prevent_self_mute CHECK (muter_id <> muted_id)
+);
+
+-- Tags are muted by name, so a tag can be muted before any article uses it
+CREATE TABLE IF NOT EXISTS tag_mutes (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    tag TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (user_id, tag)
+);
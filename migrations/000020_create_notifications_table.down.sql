@@ -0,0 +1,6 @@
+ALTER TABLE users
+    DROP COLUMN IF EXISTS notify_comment,
+    DROP COLUMN IF EXISTS notify_favorite,
+    DROP COLUMN IF EXISTS notify_follow;
+
+DROP TABLE IF EXISTS notifications;
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS notifications (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    actor_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type TEXT NOT NULL,
+    article_id INTEGER REFERENCES articles(id) ON DELETE CASCADE,
+    comment_id INTEGER REFERENCES comments(id) ON DELETE CASCADE,
+    read_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT notifications_type_check CHECK (type IN ('follow', 'favorite', 'comment'))
+);
+
+-- Notifications are listed newest first and their unread ones counted per user
+CREATE INDEX idx_notifications_user_id_created_at ON notifications (user_id, created_at DESC, id DESC);
+CREATE INDEX idx_notifications_unread ON notifications (user_id) WHERE read_at IS NULL;
+
+-- Following or favoriting again after undoing it does not notify twice
+CREATE UNIQUE INDEX idx_notifications_once
+    ON notifications (user_id, actor_id, type, COALESCE(article_id, 0))
+    WHERE type IN ('follow', 'favorite');
+
+-- Users choose the types of notifications they get
+ALTER TABLE users
+    ADD COLUMN notify_follow BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN notify_favorite BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN notify_comment BOOLEAN NOT NULL DEFAULT TRUE;
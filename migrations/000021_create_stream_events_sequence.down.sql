@@ -0,0 +1 @@
+DROP SEQUENCE IF EXISTS stream_event_id_seq;
@@ -0,0 +1,3 @@
+-- Numbers the events pushed to streaming clients so every replica gives an event the
+-- same ID, which clients send back in Last-Event-ID when they reconnect
+CREATE SEQUENCE IF NOT EXISTS stream_event_id_seq;
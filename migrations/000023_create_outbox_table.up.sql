@@ -0,0 +1,28 @@
+-- Domain events are written to the outbox in the same transaction as the change they
+-- describe, and deleted once the relay has published them. Events that run out of
+-- attempts are kept with their last error as failed.
+CREATE TABLE IF NOT EXISTS outbox (
+    id BIGSERIAL PRIMARY KEY,
+    aggregate_type TEXT NOT NULL,
+    aggregate_id INTEGER NOT NULL,
+    event_type TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_error TEXT NOT NULL DEFAULT '',
+    failed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- The relay reads the events that are due in the order they were written
+CREATE INDEX idx_outbox_due ON outbox (next_attempt_at, id) WHERE failed_at IS NULL;
+
+-- Events relayed from the outbox may be handled more than once, which must not notify
+-- about the same comment twice
+CREATE UNIQUE INDEX idx_notifications_comment_once ON notifications (user_id, comment_id)
+    WHERE type = 'comment';
+
+-- nor queue a delivery of the same event twice for a webhook. Replayed deliveries have
+-- no event.
+ALTER TABLE webhook_deliveries ADD COLUMN event_id BIGINT;
+CREATE UNIQUE INDEX idx_webhook_deliveries_event ON webhook_deliveries (webhook_id, event_id);